@@ -0,0 +1,34 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+// build is the build step of the chaincode-as-a-service external builder.
+// It renders the connection.json of the package with the configuration
+// found in the CHAINCODE_AS_A_SERVICE_BUILDER_CONFIG environment variable.
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/osdi23p228/fabric/core/container/externalbuilder/ccaas"
+)
+
+func main() {
+	if len(os.Args) != 4 {
+		fmt.Fprintln(os.Stderr, "usage: build CHAINCODE_SOURCE_DIR CHAINCODE_METADATA_DIR BUILD_OUTPUT_DIR")
+		os.Exit(2)
+	}
+
+	config, err := ccaas.ParseConfig(os.Getenv(ccaas.ConfigEnvVar))
+	if err == nil {
+		err = ccaas.Build(os.Args[1], os.Args[3], config)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
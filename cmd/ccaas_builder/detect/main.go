@@ -0,0 +1,29 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+// detect is the detect step of the chaincode-as-a-service external builder.
+// It succeeds only for chaincode packages of type "ccaas".
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/osdi23p228/fabric/core/container/externalbuilder/ccaas"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: detect CHAINCODE_SOURCE_DIR CHAINCODE_METADATA_DIR")
+		os.Exit(2)
+	}
+
+	if err := ccaas.Detect(os.Args[2]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,29 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+// release is the release step of the chaincode-as-a-service external builder.
+// It publishes the rendered connection.json where the peer expects it.
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/osdi23p228/fabric/core/container/externalbuilder/ccaas"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: release BUILD_OUTPUT_DIR RELEASE_OUTPUT_DIR")
+		os.Exit(2)
+	}
+
+	if err := ccaas.Release(os.Args[1], os.Args[2]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/osdi23p228/fabric/bccsp/factory"
+	"github.com/osdi23p228/fabric/internal/ledgerutil"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// command line flags
+var (
+	app = kingpin.New("ledgerutil", "Utility for inspecting the ledgers of stopped peers and orderers")
+
+	verify               = app.Command("verify", "Verify the hash chain, the block signatures and the block index of a channel ledger")
+	verifyBlockStorePath = verify.Flag("blockStorePath", "The block store directory, i.e. <peer.fileSystemPath>/ledgersData/chains for a peer, or <FileLedger.Location> for an orderer.").Required().ExistingDir()
+	verifyChannelID      = verify.Flag("channelID", "The channel whose ledger is verified.").Required().String()
+	verifyLedgerType     = verify.Flag("ledgerType", "The kind of node owning the block store, which determines the block index layout.").Default(string(ledgerutil.PeerLedger)).Enum(string(ledgerutil.PeerLedger), string(ledgerutil.OrdererLedger))
+	verifyNoSignatures   = verify.Flag("noSignatures", "Skip the verification of block signatures.").Bool()
+)
+
+func main() {
+	kingpin.Version("0.0.1")
+	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	case verify.FullCommand():
+		report, err := ledgerutil.VerifyLedger(
+			*verifyBlockStorePath,
+			*verifyChannelID,
+			ledgerutil.LedgerType(*verifyLedgerType),
+			*verifyNoSignatures,
+			factory.GetDefault(),
+		)
+		if err != nil {
+			app.Fatalf("Error verifying ledger: %s", err)
+		}
+		fmt.Print(report)
+		if !report.OK() {
+			os.Exit(1)
+		}
+	}
+}
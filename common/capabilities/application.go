@@ -28,6 +28,9 @@ const (
 	// ApplicationV2_0 is the capabilities string for standard new non-backwards compatible fabric v2.0 application capabilities.
 	ApplicationV2_0 = "V2_0"
 
+	// ApplicationV2_5 is the capabilities string for standard new non-backwards compatible fabric v2.5 application capabilities.
+	ApplicationV2_5 = "V2_5"
+
 	// ApplicationPvtDataExperimental is the capabilities string for private data using the experimental feature of collections/sideDB.
 	ApplicationPvtDataExperimental = "V1_1_PVTDATA_EXPERIMENTAL"
 
@@ -43,6 +46,7 @@ type ApplicationProvider struct {
 	v13                    bool
 	v142                   bool
 	v20                    bool
+	v25                    bool
 	v11PvtDataExperimental bool
 }
 
@@ -55,6 +59,7 @@ func NewApplicationProvider(capabilities map[string]*cb.Capability) *Application
 	_, ap.v13 = capabilities[ApplicationV1_3]
 	_, ap.v142 = capabilities[ApplicationV1_4_2]
 	_, ap.v20 = capabilities[ApplicationV2_0]
+	_, ap.v25 = capabilities[ApplicationV2_5]
 	_, ap.v11PvtDataExperimental = capabilities[ApplicationPvtDataExperimental]
 	return ap
 }
@@ -66,44 +71,44 @@ func (ap *ApplicationProvider) Type() string {
 
 // ACLs returns whether ACLs may be specified in the channel application config
 func (ap *ApplicationProvider) ACLs() bool {
-	return ap.v12 || ap.v13 || ap.v142 || ap.v20
+	return ap.v12 || ap.v13 || ap.v142 || ap.v20 || ap.v25
 }
 
 // ForbidDuplicateTXIdInBlock specifies whether two transactions with the same TXId are permitted
 // in the same block or whether we mark the second one as TxValidationCode_DUPLICATE_TXID
 func (ap *ApplicationProvider) ForbidDuplicateTXIdInBlock() bool {
-	return ap.v11 || ap.v12 || ap.v13 || ap.v142 || ap.v20
+	return ap.v11 || ap.v12 || ap.v13 || ap.v142 || ap.v20 || ap.v25
 }
 
 // PrivateChannelData returns true if support for private channel data (a.k.a. collections) is enabled.
 // In v1.1, the private channel data is experimental and has to be enabled explicitly.
 // In v1.2, the private channel data is enabled by default.
 func (ap *ApplicationProvider) PrivateChannelData() bool {
-	return ap.v11PvtDataExperimental || ap.v12 || ap.v13 || ap.v142 || ap.v20
+	return ap.v11PvtDataExperimental || ap.v12 || ap.v13 || ap.v142 || ap.v20 || ap.v25
 }
 
 // CollectionUpgrade returns true if this channel is configured to allow updates to
 // existing collection or add new collections through chaincode upgrade (as introduced in v1.2)
 func (ap ApplicationProvider) CollectionUpgrade() bool {
-	return ap.v12 || ap.v13 || ap.v142 || ap.v20
+	return ap.v12 || ap.v13 || ap.v142 || ap.v20 || ap.v25
 }
 
 // V1_1Validation returns true is this channel is configured to perform stricter validation
 // of transactions (as introduced in v1.1).
 func (ap *ApplicationProvider) V1_1Validation() bool {
-	return ap.v11 || ap.v12 || ap.v13 || ap.v142 || ap.v20
+	return ap.v11 || ap.v12 || ap.v13 || ap.v142 || ap.v20 || ap.v25
 }
 
 // V1_2Validation returns true if this channel is configured to perform stricter validation
 // of transactions (as introduced in v1.2).
 func (ap *ApplicationProvider) V1_2Validation() bool {
-	return ap.v12 || ap.v13 || ap.v142 || ap.v20
+	return ap.v12 || ap.v13 || ap.v142 || ap.v20 || ap.v25
 }
 
 // V1_3Validation returns true if this channel is configured to perform stricter validation
 // of transactions (as introduced in v1.3).
 func (ap *ApplicationProvider) V1_3Validation() bool {
-	return ap.v13 || ap.v142 || ap.v20
+	return ap.v13 || ap.v142 || ap.v20 || ap.v25
 }
 
 // V2_0Validation returns true if this channel supports transaction validation
@@ -111,7 +116,7 @@ func (ap *ApplicationProvider) V1_3Validation() bool {
 //  - new chaincode lifecycle
 //  - implicit per-org collections
 func (ap *ApplicationProvider) V2_0Validation() bool {
-	return ap.v20
+	return ap.v20 || ap.v25
 }
 
 // LifecycleV20 indicates whether the peer should use the deprecated and problematic
@@ -119,7 +124,7 @@ func (ap *ApplicationProvider) V2_0Validation() bool {
 // process introduced in v2.0.  Note, this should only be used on the endorsing side
 // of peer processing, so that we may safely remove all checks against it in v2.1.
 func (ap *ApplicationProvider) LifecycleV20() bool {
-	return ap.v20
+	return ap.v20 || ap.v25
 }
 
 // MetadataLifecycle always returns false
@@ -130,13 +135,25 @@ func (ap *ApplicationProvider) MetadataLifecycle() bool {
 // KeyLevelEndorsement returns true if this channel supports endorsement
 // policies expressible at a ledger key granularity, as described in FAB-8812
 func (ap *ApplicationProvider) KeyLevelEndorsement() bool {
-	return ap.v13 || ap.v142 || ap.v20
+	return ap.v13 || ap.v142 || ap.v20 || ap.v25
+}
+
+// BatchStateAccess returns true if chaincode may read and write multiple keys
+// with a single GET_STATE_MULTIPLE or PUT_STATE_BATCH request.
+func (ap *ApplicationProvider) BatchStateAccess() bool {
+	return ap.v25
+}
+
+// PurgePvtData returns true if chaincode may purge the private data of a key
+// from the peers with a PURGE_PRIVATE_DATA message.
+func (ap *ApplicationProvider) PurgePvtData() bool {
+	return ap.v25
 }
 
 // StorePvtDataOfInvalidTx returns true if the peer needs to store
 // the pvtData of invalid transactions.
 func (ap *ApplicationProvider) StorePvtDataOfInvalidTx() bool {
-	return ap.v142 || ap.v20
+	return ap.v142 || ap.v20 || ap.v25
 }
 
 // HasCapability returns true if the capability is supported by this binary.
@@ -153,6 +170,8 @@ func (ap *ApplicationProvider) HasCapability(capability string) bool {
 		return true
 	case ApplicationV2_0:
 		return true
+	case ApplicationV2_5:
+		return true
 	case ApplicationPvtDataExperimental:
 		return true
 	case ApplicationResourcesTreeExperimental:
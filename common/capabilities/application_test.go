@@ -87,6 +87,28 @@ func TestApplicationV20(t *testing.T) {
 	assert.True(t, ap.PrivateChannelData())
 	assert.True(t, ap.LifecycleV20())
 	assert.True(t, ap.StorePvtDataOfInvalidTx())
+	assert.False(t, ap.BatchStateAccess())
+	assert.False(t, ap.PurgePvtData())
+}
+
+func TestApplicationV25(t *testing.T) {
+	ap := NewApplicationProvider(map[string]*cb.Capability{
+		ApplicationV2_5: {},
+	})
+	assert.NoError(t, ap.Supported())
+	assert.True(t, ap.ForbidDuplicateTXIdInBlock())
+	assert.True(t, ap.V1_1Validation())
+	assert.True(t, ap.V1_2Validation())
+	assert.True(t, ap.V1_3Validation())
+	assert.True(t, ap.V2_0Validation())
+	assert.True(t, ap.KeyLevelEndorsement())
+	assert.True(t, ap.ACLs())
+	assert.True(t, ap.CollectionUpgrade())
+	assert.True(t, ap.PrivateChannelData())
+	assert.True(t, ap.LifecycleV20())
+	assert.True(t, ap.StorePvtDataOfInvalidTx())
+	assert.True(t, ap.BatchStateAccess())
+	assert.True(t, ap.PurgePvtData())
 }
 
 func TestApplicationPvtDataExperimental(t *testing.T) {
@@ -102,6 +124,7 @@ func TestHasCapability(t *testing.T) {
 	assert.True(t, ap.HasCapability(ApplicationV1_2))
 	assert.True(t, ap.HasCapability(ApplicationV1_3))
 	assert.True(t, ap.HasCapability(ApplicationV2_0))
+	assert.True(t, ap.HasCapability(ApplicationV2_5))
 	assert.True(t, ap.HasCapability(ApplicationPvtDataExperimental))
 	assert.True(t, ap.HasCapability(ApplicationResourcesTreeExperimental))
 	assert.False(t, ap.HasCapability("default"))
@@ -194,6 +194,14 @@ type ApplicationCapabilities interface {
 	// KeyLevelEndorsement returns true if this channel supports endorsement
 	// policies expressible at a ledger key granularity, as described in FAB-8812
 	KeyLevelEndorsement() bool
+
+	// BatchStateAccess returns true if chaincode may read and write multiple keys
+	// with a single GET_STATE_MULTIPLE or PUT_STATE_BATCH request.
+	BatchStateAccess() bool
+
+	// PurgePvtData returns true if chaincode may purge the private data of a key
+	// from the peers with a PURGE_PRIVATE_DATA message.
+	PurgePvtData() bool
 }
 
 // OrdererCapabilities defines the capabilities for the orderer portion of a channel
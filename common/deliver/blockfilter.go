@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver
+
+import (
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/internal/pkg/txflags"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// HeaderOnlyBlock returns a copy of the block which carries the header and
+// the metadata of the block but no transactions. The header hashes and the
+// orderer signatures of such blocks can still be verified, which is all
+// clients following the height and the hash chain of a channel need.
+func HeaderOnlyBlock(block *cb.Block) *cb.Block {
+	return &cb.Block{
+		Header:   proto.Clone(block.Header).(*cb.BlockHeader),
+		Metadata: proto.Clone(block.Metadata).(*cb.BlockMetadata),
+	}
+}
+
+// FilterBlock returns the filtered form of the block, which carries the
+// identifier, the type and the validation code of each transaction. Blocks
+// which have not been validated yet, such as the blocks of the ordering
+// service, report their transactions as NOT_VALIDATED.
+func FilterBlock(block *cb.Block) (*pb.FilteredBlock, error) {
+	filteredBlock := &pb.FilteredBlock{
+		Number: block.Header.Number,
+	}
+
+	var txsFltr txflags.ValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txsFltr = txflags.ValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+	validated := len(txsFltr) == len(block.Data.GetData())
+
+	for txIndex, ebytes := range block.Data.GetData() {
+		env, err := protoutil.GetEnvelopeFromBlock(ebytes)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "error getting transaction %d from block %d", txIndex, block.Header.Number)
+		}
+		chdr, err := protoutil.ChannelHeader(env)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "error getting channel header of transaction %d from block %d", txIndex, block.Header.Number)
+		}
+
+		filteredBlock.ChannelId = chdr.ChannelId
+
+		txValidationCode := pb.TxValidationCode_NOT_VALIDATED
+		if validated {
+			txValidationCode = txsFltr.Flag(txIndex)
+		}
+
+		filteredBlock.FilteredTransactions = append(filteredBlock.FilteredTransactions, &pb.FilteredTransaction{
+			Txid:             chdr.TxId,
+			Type:             cb.HeaderType(chdr.Type),
+			TxValidationCode: txValidationCode,
+		})
+	}
+
+	return filteredBlock, nil
+}
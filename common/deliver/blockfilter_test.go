@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver_test
+
+import (
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/osdi23p228/fabric/common/deliver"
+	"github.com/osdi23p228/fabric/internal/pkg/txflags"
+	"github.com/osdi23p228/fabric/protoutil"
+)
+
+var _ = Describe("Block filtering", func() {
+	var block *cb.Block
+
+	newEnvelope := func(txID string, headerType cb.HeaderType) []byte {
+		return protoutil.MarshalOrPanic(&cb.Envelope{
+			Payload: protoutil.MarshalOrPanic(&cb.Payload{
+				Header: &cb.Header{
+					ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+						ChannelId: "mychannel",
+						TxId:      txID,
+						Type:      int32(headerType),
+					}),
+				},
+			}),
+		})
+	}
+
+	BeforeEach(func() {
+		block = protoutil.NewBlock(7, []byte("previous-hash"))
+		block.Data.Data = [][]byte{
+			newEnvelope("tx1", cb.HeaderType_ENDORSER_TRANSACTION),
+			newEnvelope("tx2", cb.HeaderType_CONFIG),
+		}
+		block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+		block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = []byte("signatures")
+	})
+
+	Describe("HeaderOnlyBlock", func() {
+		It("keeps the header and the metadata", func() {
+			headerOnly := deliver.HeaderOnlyBlock(block)
+			Expect(headerOnly.Data).To(BeNil())
+			Expect(proto.Equal(headerOnly.Header, block.Header)).To(BeTrue())
+			Expect(proto.Equal(headerOnly.Metadata, block.Metadata)).To(BeTrue())
+			Expect(block.Data.Data).To(HaveLen(2))
+		})
+	})
+
+	Describe("FilterBlock", func() {
+		It("reports unvalidated transactions as not validated", func() {
+			filteredBlock, err := deliver.FilterBlock(block)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proto.Equal(filteredBlock, &pb.FilteredBlock{
+				ChannelId: "mychannel",
+				Number:    7,
+				FilteredTransactions: []*pb.FilteredTransaction{
+					{Txid: "tx1", Type: cb.HeaderType_ENDORSER_TRANSACTION, TxValidationCode: pb.TxValidationCode_NOT_VALIDATED},
+					{Txid: "tx2", Type: cb.HeaderType_CONFIG, TxValidationCode: pb.TxValidationCode_NOT_VALIDATED},
+				},
+			})).To(BeTrue())
+		})
+
+		It("reports the validation codes of validated transactions", func() {
+			flags := txflags.New(2)
+			flags.SetFlag(0, pb.TxValidationCode_VALID)
+			flags.SetFlag(1, pb.TxValidationCode_MVCC_READ_CONFLICT)
+			block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+
+			filteredBlock, err := deliver.FilterBlock(block)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filteredBlock.FilteredTransactions).To(HaveLen(2))
+			Expect(filteredBlock.FilteredTransactions[0].TxValidationCode).To(Equal(pb.TxValidationCode_VALID))
+			Expect(filteredBlock.FilteredTransactions[1].TxValidationCode).To(Equal(pb.TxValidationCode_MVCC_READ_CONFLICT))
+		})
+
+		It("fails on malformed transactions", func() {
+			block.Data.Data[1] = []byte("garbage")
+			_, err := deliver.FilterBlock(block)
+			Expect(err).To(MatchError(ContainSubstring("error getting transaction 1 from block 7")))
+		})
+	})
+})
@@ -0,0 +1,252 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/osdi23p228/fabric/common/ledger/util/leveldbhelper"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// BlockSignatureVerifier verifies the metadata signatures of the blocks of a ledger.
+// It is invoked for every block of the ledger, in order.
+type BlockSignatureVerifier interface {
+	VerifyBlockSignatures(block *common.Block) error
+}
+
+// BlockIssue is a problem found with a block of the block store.
+type BlockIssue struct {
+	BlockNum uint64
+	Reason   string
+}
+
+func (bi *BlockIssue) String() string {
+	return fmt.Sprintf("block [%d]: %s", bi.BlockNum, bi.Reason)
+}
+
+// VerificationReport is the outcome of the verification of a block store.
+type VerificationReport struct {
+	LedgerID       string
+	FirstBlockNum  uint64
+	LastBlockNum   uint64
+	BlocksVerified uint64
+
+	// FirstBrokenBlock is the first block which does not fit in the hash chain,
+	// either because its number, its PreviousHash or its DataHash is wrong.
+	FirstBrokenBlock  *BlockIssue
+	InvalidSignatures []*BlockIssue
+	IndexMismatches   []*BlockIssue
+	// IndexMissing is set when an index config is given but the block index does not exist.
+	IndexMissing bool
+	// Truncated is set when the last block file ends with a partially written block.
+	Truncated bool
+}
+
+// OK returns whether no problem was found.
+func (r *VerificationReport) OK() bool {
+	return r.FirstBrokenBlock == nil && len(r.InvalidSignatures) == 0 && len(r.IndexMismatches) == 0 && !r.IndexMissing && !r.Truncated
+}
+
+func (r *VerificationReport) String() string {
+	buf := &strings.Builder{}
+	if r.BlocksVerified == 0 {
+		fmt.Fprintf(buf, "Ledger %s: no blocks found\n", r.LedgerID)
+	} else {
+		fmt.Fprintf(buf, "Ledger %s: verified %d blocks [%d, %d]\n", r.LedgerID, r.BlocksVerified, r.FirstBlockNum, r.LastBlockNum)
+	}
+	if r.FirstBrokenBlock != nil {
+		fmt.Fprintf(buf, "Hash chain broken at %s\n", r.FirstBrokenBlock)
+	}
+	for _, issue := range r.InvalidSignatures {
+		fmt.Fprintf(buf, "Invalid signatures on %s\n", issue)
+	}
+	for _, issue := range r.IndexMismatches {
+		fmt.Fprintf(buf, "Index mismatch for %s\n", issue)
+	}
+	if r.IndexMissing {
+		fmt.Fprintf(buf, "Block index does not exist\n")
+	}
+	if r.Truncated {
+		fmt.Fprintf(buf, "Last block file ends with a partially written block\n")
+	}
+	if r.OK() {
+		fmt.Fprintf(buf, "Result: OK\n")
+	} else {
+		fmt.Fprintf(buf, "Result: FAILED\n")
+	}
+	return buf.String()
+}
+
+// VerifyBlockStore walks the block files of the given ledger and checks that every block
+// links to its predecessor through its PreviousHash, that its DataHash matches its data,
+// and that the block index, if an index config is given, points to the location of the block.
+// If a signature verifier is given, the metadata signatures of every block are verified as well.
+// It must only be run when the peer or orderer owning the block store is stopped.
+func VerifyBlockStore(blockStorageDir, ledgerID string, indexConfig *IndexConfig, sigVerifier BlockSignatureVerifier) (*VerificationReport, error) {
+	conf := &Conf{blockStorageDir: blockStorageDir}
+	ledgerDir := conf.getLedgerBlockDir(ledgerID)
+	exists, err := pathExists(ledgerDir)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.Errorf("ledger directory %s does not exist", ledgerDir)
+	}
+
+	report := &VerificationReport{LedgerID: ledgerID}
+
+	var index *blockIndex
+	if indexConfig != nil {
+		indexExists, err := pathExists(conf.getIndexDir())
+		if err != nil {
+			return nil, err
+		}
+		report.IndexMissing = !indexExists
+	}
+	if indexConfig != nil && !report.IndexMissing {
+		dbProvider, err := leveldbhelper.NewProvider(
+			&leveldbhelper.Conf{
+				DBPath:         conf.getIndexDir(),
+				ExpectedFormat: dataFormatVersion(indexConfig),
+				ReadOnly:       true,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer dbProvider.Close()
+		if index, err = newBlockIndex(indexConfig, dbProvider.GetDBHandle(ledgerID)); err != nil {
+			return nil, err
+		}
+	}
+
+	bsi, err := loadBootstrappingSnapshotInfo(ledgerDir)
+	if err != nil {
+		return nil, err
+	}
+
+	lastFileNum, err := retrieveLastFileSuffix(ledgerDir)
+	if err != nil {
+		return nil, err
+	}
+	if lastFileNum < 0 {
+		return report, nil
+	}
+
+	stream, err := newBlockStream(ledgerDir, 0, 0, lastFileNum)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.close()
+
+	var previousHash []byte
+	if bsi != nil {
+		previousHash = bsi.LastBlockHash
+	}
+
+	for {
+		blockBytes, placement, err := stream.nextBlockBytesAndPlacementInfo()
+		if err == ErrUnexpectedEndOfBlockfile {
+			report.Truncated = true
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if blockBytes == nil {
+			break
+		}
+
+		block, err := deserializeBlock(blockBytes)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "error deserializing block %d of ledger %s", report.BlocksVerified, ledgerID)
+		}
+
+		number := block.Header.Number
+		if report.BlocksVerified == 0 {
+			report.FirstBlockNum = number
+			if bsi != nil && number != bsi.LastBlockNum+1 {
+				report.brokenAt(number, fmt.Sprintf("expected first block [%d] after bootstrapping snapshot", bsi.LastBlockNum+1))
+			}
+		} else if number != report.LastBlockNum+1 {
+			report.brokenAt(number, fmt.Sprintf("expected block [%d]", report.LastBlockNum+1))
+		}
+
+		if previousHash != nil && !bytes.Equal(block.Header.PreviousHash, previousHash) {
+			report.brokenAt(number, fmt.Sprintf("PreviousHash %x does not match the hash of the previous block header %x", block.Header.PreviousHash, previousHash))
+		}
+		if dataHash := protoutil.BlockDataHash(block.Data); !bytes.Equal(block.Header.DataHash, dataHash) {
+			report.brokenAt(number, fmt.Sprintf("DataHash %x does not match the hash of the block data %x", block.Header.DataHash, dataHash))
+		}
+
+		if sigVerifier != nil {
+			if err := sigVerifier.VerifyBlockSignatures(block); err != nil {
+				report.InvalidSignatures = append(report.InvalidSignatures, &BlockIssue{BlockNum: number, Reason: err.Error()})
+			}
+		}
+
+		headerHash := protoutil.BlockHeaderHash(block.Header)
+		if index != nil {
+			report.checkIndex(index, number, headerHash, placement)
+		}
+
+		previousHash = headerHash
+		report.LastBlockNum = number
+		report.BlocksVerified++
+	}
+
+	if index != nil && report.BlocksVerified > 0 {
+		lastIndexed, err := index.getLastBlockIndexed()
+		switch {
+		case err == errIndexSavePointKeyNotPresent:
+			report.IndexMismatches = append(report.IndexMismatches, &BlockIssue{BlockNum: report.LastBlockNum, Reason: "no block is indexed"})
+		case err != nil:
+			return nil, err
+		case lastIndexed != report.LastBlockNum:
+			report.IndexMismatches = append(report.IndexMismatches, &BlockIssue{
+				BlockNum: report.LastBlockNum,
+				Reason:   fmt.Sprintf("last block in block files is [%d] but last indexed block is [%d]", report.LastBlockNum, lastIndexed),
+			})
+		}
+	}
+
+	return report, nil
+}
+
+func (r *VerificationReport) brokenAt(number uint64, reason string) {
+	if r.FirstBrokenBlock == nil {
+		r.FirstBrokenBlock = &BlockIssue{BlockNum: number, Reason: reason}
+	}
+}
+
+func (r *VerificationReport) checkIndex(index *blockIndex, number uint64, headerHash []byte, placement *blockPlacementInfo) {
+	check := func(attr string, flp *fileLocPointer, err error) {
+		switch {
+		case err == ErrAttrNotIndexed:
+		case err == ErrNotFoundInIndex:
+			r.IndexMismatches = append(r.IndexMismatches, &BlockIssue{BlockNum: number, Reason: fmt.Sprintf("block %s not indexed", attr)})
+		case err != nil:
+			r.IndexMismatches = append(r.IndexMismatches, &BlockIssue{BlockNum: number, Reason: fmt.Sprintf("error reading index by block %s: %s", attr, err)})
+		case flp.fileSuffixNum != placement.fileNum || int64(flp.offset) != placement.blockStartOffset:
+			r.IndexMismatches = append(r.IndexMismatches, &BlockIssue{
+				BlockNum: number,
+				Reason: fmt.Sprintf("index by block %s points to file [%d] offset [%d], block is at file [%d] offset [%d]",
+					attr, flp.fileSuffixNum, flp.offset, placement.fileNum, placement.blockStartOffset),
+			})
+		}
+	}
+
+	flp, err := index.getBlockLocByBlockNum(number)
+	check("number", flp, err)
+	flp, err = index.getBlockLocByHash(headerHash)
+	check("hash", flp, err)
+}
@@ -0,0 +1,163 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/osdi23p228/fabric/common/ledger/testutil"
+	"github.com/osdi23p228/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type failingSignatureVerifier struct {
+	failOn uint64
+}
+
+func (fsv *failingSignatureVerifier) VerifyBlockSignatures(block *common.Block) error {
+	if block.Header.Number == fsv.failOn {
+		return errors.New("signature policy not satisfied")
+	}
+	return nil
+}
+
+func createVerifyTestStore(t *testing.T, blocks []*common.Block) string {
+	path := testPath()
+	env := newTestEnv(t, NewConf(path, 0))
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	env.provider.Close()
+	return path
+}
+
+// rewriteBlockfile replaces the content of the single block file of the test ledger with the given blocks.
+func rewriteBlockfile(t *testing.T, path string, blocks []*common.Block) {
+	var content []byte
+	for _, block := range blocks {
+		blockBytes, _, err := serializeBlock(block)
+		require.NoError(t, err)
+		content = append(content, proto.EncodeVarint(uint64(len(blockBytes)))...)
+		content = append(content, blockBytes...)
+	}
+	ledgerDir := (&Conf{blockStorageDir: path}).getLedgerBlockDir("testLedger")
+	require.NoError(t, ioutil.WriteFile(deriveBlockfilePath(ledgerDir, 0), content, 0600))
+}
+
+func TestVerifyBlockStore(t *testing.T) {
+	indexConfig := &IndexConfig{AttrsToIndex: attrsToIndex}
+
+	t.Run("intact ledger", func(t *testing.T) {
+		blocks := testutil.ConstructTestBlocks(t, 10)
+		path := createVerifyTestStore(t, blocks)
+		defer os.RemoveAll(path)
+
+		report, err := VerifyBlockStore(path, "testLedger", indexConfig, &failingSignatureVerifier{failOn: 100})
+		require.NoError(t, err)
+		require.True(t, report.OK(), report.String())
+		require.Equal(t, uint64(10), report.BlocksVerified)
+		require.Equal(t, uint64(0), report.FirstBlockNum)
+		require.Equal(t, uint64(9), report.LastBlockNum)
+		require.Contains(t, report.String(), "Result: OK")
+	})
+
+	t.Run("broken hash chain", func(t *testing.T) {
+		blocks := testutil.ConstructTestBlocks(t, 10)
+		path := createVerifyTestStore(t, blocks)
+		defer os.RemoveAll(path)
+
+		tampered := proto.Clone(blocks[3].Header).(*common.BlockHeader)
+		tampered.DataHash[0] ^= 0xff
+		blocks[3].Header = tampered
+		rewriteBlockfile(t, path, blocks)
+
+		report, err := VerifyBlockStore(path, "testLedger", nil, nil)
+		require.NoError(t, err)
+		require.False(t, report.OK())
+		require.NotNil(t, report.FirstBrokenBlock)
+		require.Equal(t, uint64(3), report.FirstBrokenBlock.BlockNum)
+		require.Contains(t, report.FirstBrokenBlock.Reason, "DataHash")
+		require.Equal(t, uint64(10), report.BlocksVerified)
+	})
+
+	t.Run("invalid signatures", func(t *testing.T) {
+		blocks := testutil.ConstructTestBlocks(t, 5)
+		path := createVerifyTestStore(t, blocks)
+		defer os.RemoveAll(path)
+
+		report, err := VerifyBlockStore(path, "testLedger", nil, &failingSignatureVerifier{failOn: 2})
+		require.NoError(t, err)
+		require.False(t, report.OK())
+		require.Nil(t, report.FirstBrokenBlock)
+		require.Equal(t, []*BlockIssue{{BlockNum: 2, Reason: "signature policy not satisfied"}}, report.InvalidSignatures)
+		require.Contains(t, report.String(), "Invalid signatures on block [2]: signature policy not satisfied")
+	})
+
+	t.Run("index mismatch", func(t *testing.T) {
+		blocks := testutil.ConstructTestBlocks(t, 5)
+		path := createVerifyTestStore(t, blocks)
+		defer os.RemoveAll(path)
+
+		conf := &Conf{blockStorageDir: path}
+		dbProvider, err := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: conf.getIndexDir(), ExpectedFormat: dataFormatVersion(indexConfig)})
+		require.NoError(t, err)
+		require.NoError(t, dbProvider.GetDBHandle("testLedger").Delete(constructBlockNumKey(3), true))
+		dbProvider.Close()
+
+		report, err := VerifyBlockStore(path, "testLedger", indexConfig, nil)
+		require.NoError(t, err)
+		require.False(t, report.OK())
+		require.Equal(t, []*BlockIssue{{BlockNum: 3, Reason: "block number not indexed"}}, report.IndexMismatches)
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		blocks := testutil.ConstructTestBlocks(t, 5)
+		path := createVerifyTestStore(t, blocks)
+		defer os.RemoveAll(path)
+
+		indexDir := (&Conf{blockStorageDir: path}).getIndexDir()
+		require.NoError(t, os.RemoveAll(indexDir))
+
+		report, err := VerifyBlockStore(path, "testLedger", indexConfig, nil)
+		require.NoError(t, err)
+		require.False(t, report.OK())
+		require.True(t, report.IndexMissing)
+		require.Empty(t, report.IndexMismatches)
+		require.Contains(t, report.String(), "Block index does not exist")
+		require.NoDirExists(t, indexDir)
+	})
+
+	t.Run("truncated block file", func(t *testing.T) {
+		blocks := testutil.ConstructTestBlocks(t, 5)
+		path := createVerifyTestStore(t, blocks)
+		defer os.RemoveAll(path)
+
+		ledgerDir := (&Conf{blockStorageDir: path}).getLedgerBlockDir("testLedger")
+		f, err := os.OpenFile(deriveBlockfilePath(ledgerDir, 0), os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		_, err = f.Write([]byte{0x20, 1, 2})
+		require.NoError(t, err)
+		f.Close()
+
+		report, err := VerifyBlockStore(path, "testLedger", nil, nil)
+		require.NoError(t, err)
+		require.True(t, report.Truncated)
+		require.Equal(t, uint64(5), report.BlocksVerified)
+	})
+
+	t.Run("missing ledger", func(t *testing.T) {
+		path := testPath()
+		defer os.RemoveAll(path)
+		_, err := VerifyBlockStore(path, "testLedger", nil, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not exist")
+	})
+}
@@ -67,10 +67,15 @@ func (dbInst *DB) Open() {
 	dbPath := dbInst.conf.DBPath
 	var err error
 	var dirEmpty bool
-	if dirEmpty, err = util.CreateDirIfMissing(dbPath); err != nil {
-		panic(fmt.Sprintf("Error creating dir if missing: %s", err))
+	if dbInst.conf.ReadOnly {
+		dbOpts.ReadOnly = true
+		dbOpts.ErrorIfMissing = true
+	} else {
+		if dirEmpty, err = util.CreateDirIfMissing(dbPath); err != nil {
+			panic(fmt.Sprintf("Error creating dir if missing: %s", err))
+		}
+		dbOpts.ErrorIfMissing = !dirEmpty
 	}
-	dbOpts.ErrorIfMissing = !dirEmpty
 	if dbInst.db, err = leveldb.OpenFile(dbPath, dbOpts); err != nil {
 		panic(fmt.Sprintf("Error opening leveldb: %s", err))
 	}
@@ -42,9 +42,13 @@ type closeFunc func()
 // either the db is empty (i.e., opening for the first time) or the value
 // of the formatVersionKey is equal to `ExpectedFormat`. Otherwise, an error is returned.
 // A nil value for ExpectedFormat indicates that the format is never set and hence there is no such record.
+//
+// `ReadOnly` opens an existing db without creating or modifying it, in which case the format is only checked
+// when the db is not empty.
 type Conf struct {
 	DBPath         string
 	ExpectedFormat string
+	ReadOnly       bool
 }
 
 // Provider enables to use a single leveldb as multiple logical leveldbs
@@ -87,6 +91,10 @@ func openDBAndCheckFormat(conf *Conf) (d *DB, e error) {
 		return nil, err
 	}
 
+	if dbEmpty && conf.ReadOnly {
+		return db, nil
+	}
+
 	if dbEmpty && conf.ExpectedFormat != "" {
 		logger.Infof("DB is empty Setting db format as %s", conf.ExpectedFormat)
 		if err := internalDB.Put(formatVersionKey, []byte(conf.ExpectedFormat), true); err != nil {
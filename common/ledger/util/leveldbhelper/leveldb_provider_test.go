@@ -348,6 +348,31 @@ func TestFormatCheck(t *testing.T) {
 	}
 }
 
+func TestReadOnly(t *testing.T) {
+	assert.NoError(t, os.RemoveAll(testDBPath))
+	defer os.RemoveAll(testDBPath)
+
+	p, err := NewProvider(&Conf{DBPath: testDBPath, ExpectedFormat: "2.0"})
+	assert.NoError(t, err)
+	assert.NoError(t, p.GetDBHandle("testdb").Put([]byte("key"), []byte("value"), true))
+	p.Close()
+
+	p, err = NewProvider(&Conf{DBPath: testDBPath, ExpectedFormat: "2.0", ReadOnly: true})
+	assert.NoError(t, err)
+	val, err := p.GetDBHandle("testdb").Get([]byte("key"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), val)
+	assert.Error(t, p.GetDBHandle("testdb").Put([]byte("key"), []byte("other-value"), true))
+	p.Close()
+
+	_, err = NewProvider(&Conf{DBPath: testDBPath, ExpectedFormat: "3.0", ReadOnly: true})
+	assert.Equal(t, &dataformat.ErrFormatMismatch{
+		Format:         "2.0",
+		ExpectedFormat: "3.0",
+		DBInfo:         fmt.Sprintf("leveldb at [%s]", testDBPath),
+	}, err)
+}
+
 func TestClose(t *testing.T) {
 	env := newTestProviderEnv(t, testDBPath)
 	defer env.cleanup()
@@ -13,6 +13,7 @@ package resources
 const (
 	// _lifecycle resources
 	Lifecycle_InstallChaincode                   = "_lifecycle/InstallChaincode"
+	Lifecycle_UninstallChaincode                 = "_lifecycle/UninstallChaincode"
 	Lifecycle_QueryInstalledChaincode            = "_lifecycle/QueryInstalledChaincode"
 	Lifecycle_GetInstalledChaincodePackage       = "_lifecycle/GetInstalledChaincodePackage"
 	Lifecycle_QueryInstalledChaincodes           = "_lifecycle/QueryInstalledChaincodes"
@@ -22,6 +23,7 @@ const (
 	Lifecycle_QueryChaincodeDefinition           = "_lifecycle/QueryChaincodeDefinition"
 	Lifecycle_QueryChaincodeDefinitions          = "_lifecycle/QueryChaincodeDefinitions"
 	Lifecycle_CheckCommitReadiness               = "_lifecycle/CheckCommitReadiness"
+	Lifecycle_QueryPendingApprovals              = "_lifecycle/QueryPendingApprovals"
 
 	//Lscc resources
 	Lscc_Install                   = "lscc/Install"
@@ -53,4 +55,7 @@ const (
 	//Events
 	Event_Block         = "event/Block"
 	Event_FilteredBlock = "event/FilteredBlock"
+
+	//Gateway resources
+	Gateway_CommitStatus = "gateway/CommitStatus"
 )
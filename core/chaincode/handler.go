@@ -15,11 +15,13 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/osdi23p228/fabric/common/channelconfig"
 	"github.com/osdi23p228/fabric/common/flogging"
 	commonledger "github.com/osdi23p228/fabric/common/ledger"
 	"github.com/osdi23p228/fabric/core/aclmgmt/resources"
+	"github.com/osdi23p228/fabric/core/chaincode/msgs"
 	"github.com/osdi23p228/fabric/core/common/ccprovider"
 	"github.com/osdi23p228/fabric/core/common/privdata"
 	"github.com/osdi23p228/fabric/core/common/sysccprovider"
@@ -200,6 +202,8 @@ func (h *Handler) handleMessageReadyState(msg *pb.ChaincodeMessage) error {
 		go h.HandleTransaction(msg, h.HandleGetStateMetadata)
 	case pb.ChaincodeMessage_PUT_STATE_METADATA:
 		go h.HandleTransaction(msg, h.HandlePutStateMetadata)
+	case msgs.TypeExtendedRequest:
+		go h.HandleTransaction(msg, h.HandleExtendedRequest)
 	default:
 		return fmt.Errorf("[%s] Fabric side handler cannot handle message (%s) while in ready state", msg.Txid, msg.Type)
 	}
@@ -218,7 +222,8 @@ type handleFunc func(*pb.ChaincodeMessage, *TransactionContext) (*pb.ChaincodeMe
 // returned by the delegate are sent to the chat stream. Any errors returned by the
 // delegate are packaged as chaincode error messages.
 func (h *Handler) HandleTransaction(msg *pb.ChaincodeMessage, delegate handleFunc) {
-	chaincodeLogger.Debugf("[%s] handling %s from chaincode", shorttxid(msg.Txid), msg.Type.String())
+	requestType := msgs.RequestType(msg)
+	chaincodeLogger.Debugf("[%s] handling %s from chaincode", shorttxid(msg.Txid), requestType)
 	if !h.registerTxid(msg) {
 		return
 	}
@@ -233,7 +238,7 @@ func (h *Handler) HandleTransaction(msg *pb.ChaincodeMessage, delegate handleFun
 	}
 
 	meterLabels := []string{
-		"type", msg.Type.String(),
+		"type", requestType,
 		"channel", msg.ChannelId,
 		"chaincode", h.chaincodeID,
 	}
@@ -245,12 +250,14 @@ func (h *Handler) HandleTransaction(msg *pb.ChaincodeMessage, delegate handleFun
 	}
 
 	if err != nil {
-		err = errors.Wrapf(err, "%s failed: transaction ID: %s", msg.Type, msg.Txid)
-		chaincodeLogger.Errorf("[%s] Failed to handle %s. error: %+v", shorttxid(msg.Txid), msg.Type, err)
+		err = errors.Wrapf(err, "%s failed: transaction ID: %s", requestType, msg.Txid)
+		chaincodeLogger.Errorf("[%s] Failed to handle %s. error: %+v", shorttxid(msg.Txid), requestType, err)
 		resp = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid, ChannelId: msg.ChannelId}
+	} else {
+		txContext.UsageRecorder.Record(requestUsage(msg, resp))
 	}
 
-	chaincodeLogger.Debugf("[%s] Completed %s. Sending %s", shorttxid(msg.Txid), msg.Type, resp.Type)
+	chaincodeLogger.Debugf("[%s] Completed %s. Sending %s", shorttxid(msg.Txid), requestType, resp.Type)
 	h.ActiveTransactions.Remove(msg.ChannelId, msg.Txid)
 	h.serialSendAsync(resp)
 
@@ -259,6 +266,50 @@ func (h *Handler) HandleTransaction(msg *pb.ChaincodeMessage, delegate handleFun
 	h.Metrics.ShimRequestsCompleted.With(meterLabels...).Add(1)
 }
 
+// requestUsage returns the ledger access accounted to a successful shim request.
+// Every key accessed by a batch is accounted as a call of its own.
+func requestUsage(msg, resp *pb.ChaincodeMessage) ccprovider.ExecutionUsage {
+	switch msg.Type {
+	case msgs.TypeExtendedRequest:
+		return extendedRequestUsage(msg, resp)
+	case pb.ChaincodeMessage_GET_STATE, pb.ChaincodeMessage_GET_PRIVATE_DATA_HASH, pb.ChaincodeMessage_GET_STATE_METADATA:
+		return ccprovider.ExecutionUsage{GetStateCalls: 1, BytesRead: uint64(len(resp.Payload))}
+	case pb.ChaincodeMessage_PUT_STATE, pb.ChaincodeMessage_DEL_STATE, pb.ChaincodeMessage_PUT_STATE_METADATA:
+		return ccprovider.ExecutionUsage{PutStateCalls: 1, BytesWritten: uint64(len(msg.Payload))}
+	case pb.ChaincodeMessage_GET_STATE_BY_RANGE, pb.ChaincodeMessage_GET_QUERY_RESULT, pb.ChaincodeMessage_GET_HISTORY_FOR_KEY:
+		return ccprovider.ExecutionUsage{RangeQueryCalls: 1, BytesRead: uint64(len(resp.Payload))}
+	case pb.ChaincodeMessage_QUERY_STATE_NEXT:
+		return ccprovider.ExecutionUsage{BytesRead: uint64(len(resp.Payload))}
+	default:
+		return ccprovider.ExecutionUsage{}
+	}
+}
+
+func extendedRequestUsage(msg, resp *pb.ChaincodeMessage) ccprovider.ExecutionUsage {
+	requestType, request, err := msgs.UnwrapExtendedRequest(msg)
+	if err != nil {
+		return ccprovider.ExecutionUsage{}
+	}
+	switch requestType {
+	case msgs.ExtendedRequest_GET_STATE_MULTIPLE:
+		getStateMultiple := &msgs.GetStateMultiple{}
+		if err := proto.Unmarshal(request.Payload, getStateMultiple); err != nil {
+			return ccprovider.ExecutionUsage{}
+		}
+		return ccprovider.ExecutionUsage{GetStateCalls: uint64(len(getStateMultiple.Keys)), BytesRead: uint64(len(resp.Payload))}
+	case msgs.ExtendedRequest_PUT_STATE_BATCH:
+		putStateBatch := &msgs.PutStateBatch{}
+		if err := proto.Unmarshal(request.Payload, putStateBatch); err != nil {
+			return ccprovider.ExecutionUsage{}
+		}
+		return ccprovider.ExecutionUsage{PutStateCalls: uint64(len(putStateBatch.Records)), BytesWritten: uint64(len(request.Payload))}
+	case msgs.ExtendedRequest_PURGE_PRIVATE_DATA:
+		return ccprovider.ExecutionUsage{PutStateCalls: 1, BytesWritten: uint64(len(request.Payload))}
+	default:
+		return ccprovider.ExecutionUsage{}
+	}
+}
+
 func shorttxid(txid string) string {
 	if len(txid) < 8 {
 		return txid
@@ -544,6 +595,39 @@ func (h *Handler) checkMetadataCap(msg *pb.ChaincodeMessage) error {
 	return nil
 }
 
+func (h *Handler) checkBatchStateAccessCap(msg *pb.ChaincodeMessage) error {
+	ac, exists := h.AppConfig.GetApplicationConfig(msg.ChannelId)
+	if !exists {
+		return errors.Errorf("application config does not exist for %s", msg.ChannelId)
+	}
+
+	if !ac.Capabilities().BatchStateAccess() {
+		return errors.New("batch state access is not enabled, channel application capability of V2_5 or later is required")
+	}
+	return nil
+}
+
+func (h *Handler) checkPurgePvtDataCap(msg *pb.ChaincodeMessage) error {
+	ac, exists := h.AppConfig.GetApplicationConfig(msg.ChannelId)
+	if !exists {
+		return errors.Errorf("application config does not exist for %s", msg.ChannelId)
+	}
+
+	if !ac.Capabilities().PurgePvtData() {
+		return errors.New("purge of private data is not enabled, channel application capability of V2_5 or later is required")
+	}
+	return nil
+}
+
+// checkBatchSize ensures a batch does not access more keys than queries may
+// return.
+func (h *Handler) checkBatchSize(size int) error {
+	if size > h.TotalQueryLimit {
+		return errors.Errorf("batch of %d keys exceeds the limit of %d", size, h.TotalQueryLimit)
+	}
+	return nil
+}
+
 func errorIfCreatorHasNoReadPermission(chaincodeName, collection string, txContext *TransactionContext) error {
 	rwPermission, err := getReadWritePermission(chaincodeName, collection, txContext)
 	if err != nil {
@@ -625,6 +709,71 @@ func (h *Handler) HandleGetState(msg *pb.ChaincodeMessage, txContext *Transactio
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
 }
 
+// HandleExtendedRequest handles the requests carried by the messages of type
+// msgs.TypeExtendedRequest according to the type of the request.
+func (h *Handler) HandleExtendedRequest(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	requestType, request, err := msgs.UnwrapExtendedRequest(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch requestType {
+	case msgs.ExtendedRequest_GET_STATE_MULTIPLE:
+		return h.HandleGetStateMultiple(request, txContext)
+	case msgs.ExtendedRequest_PUT_STATE_BATCH:
+		return h.HandlePutStateBatch(request, txContext)
+	case msgs.ExtendedRequest_PURGE_PRIVATE_DATA:
+		return h.HandlePurgePrivateData(request, txContext)
+	default:
+		return nil, errors.Errorf("unknown request type %s", requestType)
+	}
+}
+
+// HandleGetStateMultiple reads the values of multiple keys with a single
+// GET_STATE_MULTIPLE request.
+func (h *Handler) HandleGetStateMultiple(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	err := h.checkBatchStateAccessCap(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	getStateMultiple := &msgs.GetStateMultiple{}
+	err = proto.Unmarshal(msg.Payload, getStateMultiple)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+	if err := h.checkBatchSize(len(getStateMultiple.Keys)); err != nil {
+		return nil, err
+	}
+
+	var values [][]byte
+	namespaceID := txContext.NamespaceID
+	collection := getStateMultiple.Collection
+	chaincodeLogger.Debugf("[%s] getting state of %d keys for chaincode %s, channel %s", shorttxid(msg.Txid), len(getStateMultiple.Keys), namespaceID, txContext.ChannelID)
+
+	if isCollectionSet(collection) {
+		if txContext.IsInitTransaction {
+			return nil, errors.New("private data APIs are not allowed in chaincode Init()")
+		}
+		if err := errorIfCreatorHasNoReadPermission(namespaceID, collection, txContext); err != nil {
+			return nil, err
+		}
+		values, err = txContext.TXSimulator.GetPrivateDataMultipleKeys(namespaceID, collection, getStateMultiple.Keys)
+	} else {
+		values, err = txContext.TXSimulator.GetStateMultipleKeys(namespaceID, getStateMultiple.Keys)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	payload, err := proto.Marshal(&msgs.GetStateMultipleResult{Values: values})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal failed")
+	}
+
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payload, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
 func (h *Handler) HandleGetPrivateDataHash(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
 	getState := &pb.GetState{}
 	err := proto.Unmarshal(msg.Payload, getState)
@@ -1007,6 +1156,51 @@ func (h *Handler) HandlePutState(msg *pb.ChaincodeMessage, txContext *Transactio
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
 }
 
+// HandlePutStateBatch writes multiple keys with a single PUT_STATE_BATCH
+// request. The permissions of all the writes are checked before any of them
+// is applied.
+func (h *Handler) HandlePutStateBatch(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	err := h.checkBatchStateAccessCap(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	putStateBatch := &msgs.PutStateBatch{}
+	err = proto.Unmarshal(msg.Payload, putStateBatch)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+	if err := h.checkBatchSize(len(putStateBatch.Records)); err != nil {
+		return nil, err
+	}
+
+	namespaceID := txContext.NamespaceID
+	for _, record := range putStateBatch.Records {
+		if !isCollectionSet(record.Collection) {
+			continue
+		}
+		if txContext.IsInitTransaction {
+			return nil, errors.New("private data APIs are not allowed in chaincode Init()")
+		}
+		if err := errorIfCreatorHasNoWritePermission(namespaceID, record.Collection, txContext); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, record := range putStateBatch.Records {
+		if isCollectionSet(record.Collection) {
+			err = txContext.TXSimulator.SetPrivateData(namespaceID, record.Collection, record.Key, record.Value)
+		} else {
+			err = txContext.TXSimulator.SetState(namespaceID, record.Key, record.Value)
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
 func (h *Handler) HandlePutStateMetadata(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
 	err := h.checkMetadataCap(msg)
 	if err != nil {
@@ -1070,6 +1264,42 @@ func (h *Handler) HandleDelState(msg *pb.ChaincodeMessage, txContext *Transactio
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
 }
 
+// HandlePurgePrivateData purges the private data of a key with a
+// PURGE_PRIVATE_DATA request. The key is deleted like with DEL_STATE and,
+// once committed, its private values are removed from the private data of
+// the past blocks on the peers.
+func (h *Handler) HandlePurgePrivateData(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	err := h.checkPurgePvtDataCap(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	delState := &pb.DelState{}
+	err = proto.Unmarshal(msg.Payload, delState)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+
+	namespaceID := txContext.NamespaceID
+	collection := delState.Collection
+	if !isCollectionSet(collection) {
+		return nil, errors.New("only private data can be purged")
+	}
+	if txContext.IsInitTransaction {
+		return nil, errors.New("private data APIs are not allowed in chaincode Init()")
+	}
+	if err := errorIfCreatorHasNoWritePermission(namespaceID, collection, txContext); err != nil {
+		return nil, err
+	}
+	err = txContext.TXSimulator.PurgePrivateData(namespaceID, collection, delState.Key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// Send response msg back to chaincode.
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
 // Handles requests that modify ledger state
 func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
 	chaincodeLogger.Debugf("[%s] C-call-C", shorttxid(msg.Txid))
@@ -1113,6 +1343,7 @@ func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *Tra
 		Proposal:             txContext.Proposal,
 		TXSimulator:          txContext.TXSimulator,
 		HistoryQueryExecutor: txContext.HistoryQueryExecutor,
+		UsageRecorder:        txContext.UsageRecorder,
 	}
 
 	if targetInstance.ChannelID != txContext.ChannelID {
@@ -1142,6 +1373,15 @@ func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *Tra
 		return nil, errors.Wrap(err, "execute failed")
 	}
 
+	// The transaction is committed to the caller's channel only, so the state
+	// written by a chaincode on another channel is discarded. There is no
+	// coordination between the ordering and validation of channels which
+	// would allow committing writes to several channels atomically.
+	if targetInstance.ChannelID != txContext.ChannelID && containsWrites(txParams.TXSimulator) {
+		chaincodeLogger.Warningf("[%s] C-call-C %s on channel %s wrote to the state, the writes are discarded as only read queries are supported across channels",
+			shorttxid(msg.Txid), targetInstance.ChaincodeName, targetInstance.ChannelID)
+	}
+
 	// payload is marshalled and sent to the calling chaincode's shim which unmarshals and
 	// sends it to chaincode
 	res, err := proto.Marshal(responseMessage)
@@ -1152,6 +1392,30 @@ func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *Tra
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
 }
 
+// containsWrites returns whether a simulation wrote public or private data.
+func containsWrites(sim ledger.TxSimulator) bool {
+	results, err := sim.GetTxSimulationResults()
+	if err != nil || results == nil {
+		return false
+	}
+	if results.ContainsPvtWrites() {
+		return true
+	}
+	if results.PubSimulationResults == nil {
+		return false
+	}
+	for _, nsRWSet := range results.PubSimulationResults.NsRwset {
+		kvRWSet := &kvrwset.KVRWSet{}
+		if err := proto.Unmarshal(nsRWSet.Rwset, kvRWSet); err != nil {
+			continue
+		}
+		if len(kvRWSet.Writes)+len(kvRWSet.MetadataWrites) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *Handler) Execute(txParams *ccprovider.TransactionParams, namespace string, msg *pb.ChaincodeMessage, timeout time.Duration) (*pb.ChaincodeMessage, error) {
 	chaincodeLogger.Debugf("Entry")
 	defer chaincodeLogger.Debugf("Exit")
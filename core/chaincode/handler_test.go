@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -21,9 +23,12 @@ import (
 	"github.com/osdi23p228/fabric/core/chaincode"
 	"github.com/osdi23p228/fabric/core/chaincode/fake"
 	"github.com/osdi23p228/fabric/core/chaincode/mock"
+	"github.com/osdi23p228/fabric/core/chaincode/msgs"
 	"github.com/osdi23p228/fabric/core/common/ccprovider"
 	"github.com/osdi23p228/fabric/core/common/sysccprovider"
+	"github.com/osdi23p228/fabric/core/ledger"
 	"github.com/osdi23p228/fabric/core/scc"
+	"github.com/osdi23p228/fabric/protoutil"
 	"github.com/pkg/errors"
 )
 
@@ -260,6 +265,58 @@ var _ = Describe("Handler", func() {
 			Expect(fakeShimRequestDuration.ObserveArgsForCall(0)).To(BeNumerically("<", 1.0))
 		})
 
+		Context("when the transaction context records usage", func() {
+			BeforeEach(func() {
+				txContext.UsageRecorder = &ccprovider.UsageRecorder{}
+			})
+
+			It("records the ledger accesses of the requests", func() {
+				handler.HandleTransaction(incomingMessage, fakeMessageHandler.Handle)
+
+				writeMessage := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_PUT_STATE, Payload: []byte("put-state"), Txid: "tx-id", ChannelId: "channel-id"}
+				handler.HandleTransaction(writeMessage, fakeMessageHandler.Handle)
+
+				queryMessage := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE_BY_RANGE, Txid: "tx-id", ChannelId: "channel-id"}
+				handler.HandleTransaction(queryMessage, fakeMessageHandler.Handle)
+
+				Expect(txContext.UsageRecorder.Usage()).To(Equal(ccprovider.ExecutionUsage{
+					GetStateCalls:   1,
+					PutStateCalls:   1,
+					RangeQueryCalls: 1,
+					BytesRead:       48,
+					BytesWritten:    9,
+				}))
+			})
+
+			It("records every key accessed by a batch as a call", func() {
+				getStateMultiple, err := proto.Marshal(&msgs.GetStateMultiple{Keys: []string{"key1", "key2", "key3"}})
+				Expect(err).NotTo(HaveOccurred())
+				readMessage, err := msgs.NewExtendedRequestMessage(msgs.ExtendedRequest_GET_STATE_MULTIPLE, getStateMultiple, "channel-id", "tx-id")
+				Expect(err).NotTo(HaveOccurred())
+				handler.HandleTransaction(readMessage, fakeMessageHandler.Handle)
+
+				putStateBatch, err := proto.Marshal(&msgs.PutStateBatch{Records: []*msgs.PutStateRecord{{Key: "key1"}, {Key: "key2"}}})
+				Expect(err).NotTo(HaveOccurred())
+				writeMessage, err := msgs.NewExtendedRequestMessage(msgs.ExtendedRequest_PUT_STATE_BATCH, putStateBatch, "channel-id", "tx-id")
+				Expect(err).NotTo(HaveOccurred())
+				handler.HandleTransaction(writeMessage, fakeMessageHandler.Handle)
+
+				usage := txContext.UsageRecorder.Usage()
+				Expect(usage.GetStateCalls).To(Equal(uint64(3)))
+				Expect(usage.PutStateCalls).To(Equal(uint64(2)))
+				Expect(usage.BytesWritten).To(Equal(uint64(len(putStateBatch))))
+
+				Expect(fakeShimRequestsReceived.WithArgsForCall(0)).To(ContainElement("GET_STATE_MULTIPLE"))
+				Expect(fakeShimRequestsReceived.WithArgsForCall(1)).To(ContainElement("PUT_STATE_BATCH"))
+			})
+
+			It("does not record failed requests", func() {
+				fakeMessageHandler.HandleReturns(nil, errors.New("I am a total failure"))
+				handler.HandleTransaction(incomingMessage, fakeMessageHandler.Handle)
+				Expect(txContext.UsageRecorder.Usage()).To(Equal(ccprovider.ExecutionUsage{}))
+			})
+		})
+
 		Context("when the transaction returns an error", func() {
 			BeforeEach(func() {
 				fakeMessageHandler.HandleReturns(nil, errors.New("I am a total failure"))
@@ -777,6 +834,313 @@ var _ = Describe("Handler", func() {
 		})
 	})
 
+	Describe("HandleExtendedRequest", func() {
+		BeforeEach(func() {
+			fakeCapabilites.BatchStateAccessReturns(true)
+			handler.TotalQueryLimit = 10
+		})
+
+		It("handles the request according to its type", func() {
+			payload, err := proto.Marshal(&msgs.PutStateBatch{Records: []*msgs.PutStateRecord{{Key: "key1", Value: []byte("value1")}}})
+			Expect(err).NotTo(HaveOccurred())
+			incomingMessage, err := msgs.NewExtendedRequestMessage(msgs.ExtendedRequest_PUT_STATE_BATCH, payload, "channel-id", "tx-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := handler.HandleExtendedRequest(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Type).To(Equal(pb.ChaincodeMessage_RESPONSE))
+			Expect(fakeTxSimulator.SetStateCallCount()).To(Equal(1))
+			_, key, value := fakeTxSimulator.SetStateArgsForCall(0)
+			Expect(key).To(Equal("key1"))
+			Expect(value).To(Equal([]byte("value1")))
+		})
+
+		It("purges private data with PURGE_PRIVATE_DATA requests", func() {
+			fakeCapabilites.PurgePvtDataReturns(true)
+			fakeCollectionStore.RetrieveReadWritePermissionReturns(false, true, nil)
+			payload, err := proto.Marshal(&pb.DelState{Key: "key1", Collection: "collection-name"})
+			Expect(err).NotTo(HaveOccurred())
+			incomingMessage, err := msgs.NewExtendedRequestMessage(msgs.ExtendedRequest_PURGE_PRIVATE_DATA, payload, "channel-id", "tx-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = handler.HandleExtendedRequest(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeTxSimulator.PurgePrivateDataCallCount()).To(Equal(1))
+		})
+
+		Context("when the request type is unknown", func() {
+			It("returns an error", func() {
+				incomingMessage, err := msgs.NewExtendedRequestMessage(msgs.ExtendedRequest_UNDEFINED, nil, "channel-id", "tx-id")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = handler.HandleExtendedRequest(incomingMessage, txContext)
+				Expect(err).To(MatchError("unknown request type UNDEFINED"))
+			})
+		})
+
+		Context("when the request can't be unmarshaled", func() {
+			It("returns an error", func() {
+				incomingMessage := &pb.ChaincodeMessage{Type: msgs.TypeExtendedRequest, Payload: []byte("garbage"), Txid: "tx-id", ChannelId: "channel-id"}
+				_, err := handler.HandleExtendedRequest(incomingMessage, txContext)
+				Expect(err).To(MatchError(ContainSubstring("unmarshal failed")))
+			})
+		})
+	})
+
+	Describe("HandlePutStateBatch", func() {
+		var incomingMessage *pb.ChaincodeMessage
+		var request *msgs.PutStateBatch
+
+		BeforeEach(func() {
+			fakeCapabilites.BatchStateAccessReturns(true)
+			handler.TotalQueryLimit = 10
+
+			request = &msgs.PutStateBatch{
+				Records: []*msgs.PutStateRecord{
+					{Key: "key1", Value: []byte("value1")},
+					{Key: "key2", Value: []byte("value2"), Collection: "collection-name"},
+				},
+			}
+			payload, err := proto.Marshal(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:      msgs.TypeExtendedRequest,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}
+			fakeCollectionStore.RetrieveReadWritePermissionReturns(false, true, nil)
+		})
+
+		It("returns a response message", func() {
+			resp, err := handler.HandlePutStateBatch(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(Equal(&pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_RESPONSE,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}))
+		})
+
+		It("writes the public and private records", func() {
+			_, err := handler.HandlePutStateBatch(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTxSimulator.SetStateCallCount()).To(Equal(1))
+			ccname, key, value := fakeTxSimulator.SetStateArgsForCall(0)
+			Expect(ccname).To(Equal("cc-instance-name"))
+			Expect(key).To(Equal("key1"))
+			Expect(value).To(Equal([]byte("value1")))
+
+			Expect(fakeTxSimulator.SetPrivateDataCallCount()).To(Equal(1))
+			ccname, collection, key, value := fakeTxSimulator.SetPrivateDataArgsForCall(0)
+			Expect(ccname).To(Equal("cc-instance-name"))
+			Expect(collection).To(Equal("collection-name"))
+			Expect(key).To(Equal("key2"))
+			Expect(value).To(Equal([]byte("value2")))
+		})
+
+		Context("when SetState fails", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.SetStateReturns(errors.New("king-kong"))
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePutStateBatch(incomingMessage, txContext)
+				Expect(err).To(MatchError("king-kong"))
+			})
+		})
+
+		Context("when the creator has no write access to the collection", func() {
+			BeforeEach(func() {
+				fakeCollectionStore.RetrieveReadWritePermissionReturns(false, false, nil)
+			})
+
+			It("returns an error without writing any record", func() {
+				_, err := handler.HandlePutStateBatch(incomingMessage, txContext)
+				Expect(err).To(MatchError("tx creator does not have write access" +
+					" permission on privatedata in chaincodeName:cc-instance-name" +
+					" collectionName: collection-name"))
+				Expect(fakeTxSimulator.SetStateCallCount()).To(Equal(0))
+				Expect(fakeTxSimulator.SetPrivateDataCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when a collection is written by an Init transaction", func() {
+			BeforeEach(func() {
+				txContext.IsInitTransaction = true
+			})
+
+			It("returns an error without writing any record", func() {
+				_, err := handler.HandlePutStateBatch(incomingMessage, txContext)
+				Expect(err).To(MatchError("private data APIs are not allowed in chaincode Init()"))
+				Expect(fakeTxSimulator.SetStateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the batch exceeds the total query limit", func() {
+			BeforeEach(func() {
+				handler.TotalQueryLimit = 1
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePutStateBatch(incomingMessage, txContext)
+				Expect(err).To(MatchError("batch of 2 keys exceeds the limit of 1"))
+			})
+		})
+
+		Context("when unmarshaling the request fails", func() {
+			BeforeEach(func() {
+				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePutStateBatch(incomingMessage, txContext)
+				Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+			})
+		})
+
+		Context("when the batch state access capability is not enabled", func() {
+			BeforeEach(func() {
+				fakeCapabilites.BatchStateAccessReturns(false)
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePutStateBatch(incomingMessage, txContext)
+				Expect(err).To(MatchError("batch state access is not enabled, channel application capability of V2_5 or later is required"))
+			})
+		})
+
+		Context("when the application config does not exist", func() {
+			BeforeEach(func() {
+				fakeApplicationConfigRetriever.GetApplicationConfigReturns(nil, false)
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePutStateBatch(incomingMessage, txContext)
+				Expect(err).To(MatchError("application config does not exist for channel-id"))
+			})
+		})
+	})
+
+	Describe("HandlePurgePrivateData", func() {
+		var incomingMessage *pb.ChaincodeMessage
+		var request *pb.DelState
+
+		BeforeEach(func() {
+			fakeCapabilites.PurgePvtDataReturns(true)
+
+			request = &pb.DelState{
+				Key:        "purge-key",
+				Collection: "collection-name",
+			}
+			payload, err := proto.Marshal(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:      msgs.TypeExtendedRequest,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}
+			fakeCollectionStore.RetrieveReadWritePermissionReturns(false, true, nil)
+		})
+
+		It("returns a response message", func() {
+			resp, err := handler.HandlePurgePrivateData(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(Equal(&pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_RESPONSE,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}))
+		})
+
+		It("calls PurgePrivateData on the transaction simulator", func() {
+			_, err := handler.HandlePurgePrivateData(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTxSimulator.PurgePrivateDataCallCount()).To(Equal(1))
+			ccname, collection, key := fakeTxSimulator.PurgePrivateDataArgsForCall(0)
+			Expect(ccname).To(Equal("cc-instance-name"))
+			Expect(collection).To(Equal("collection-name"))
+			Expect(key).To(Equal("purge-key"))
+		})
+
+		Context("when PurgePrivateData fails", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.PurgePrivateDataReturns(errors.New("papaya"))
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePurgePrivateData(incomingMessage, txContext)
+				Expect(err).To(MatchError("papaya"))
+			})
+		})
+
+		Context("when collection is not set", func() {
+			BeforeEach(func() {
+				request.Collection = ""
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePurgePrivateData(incomingMessage, txContext)
+				Expect(err).To(MatchError("only private data can be purged"))
+				Expect(fakeTxSimulator.PurgePrivateDataCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when called from an Init transaction", func() {
+			BeforeEach(func() {
+				txContext.IsInitTransaction = true
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePurgePrivateData(incomingMessage, txContext)
+				Expect(err).To(MatchError("private data APIs are not allowed in chaincode Init()"))
+			})
+		})
+
+		Context("when the creator has no write access to the collection", func() {
+			BeforeEach(func() {
+				fakeCollectionStore.RetrieveReadWritePermissionReturns(false, false, nil)
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePurgePrivateData(incomingMessage, txContext)
+				Expect(err).To(MatchError("tx creator does not have write access" +
+					" permission on privatedata in chaincodeName:cc-instance-name" +
+					" collectionName: collection-name"))
+				Expect(fakeTxSimulator.PurgePrivateDataCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when unmarshaling the request fails", func() {
+			BeforeEach(func() {
+				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePurgePrivateData(incomingMessage, txContext)
+				Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+			})
+		})
+
+		Context("when the purge private data capability is not enabled", func() {
+			BeforeEach(func() {
+				fakeCapabilites.PurgePvtDataReturns(false)
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandlePurgePrivateData(incomingMessage, txContext)
+				Expect(err).To(MatchError("purge of private data is not enabled, channel application capability of V2_5 or later is required"))
+			})
+		})
+	})
+
 	Describe("HandleDelState", func() {
 		var incomingMessage *pb.ChaincodeMessage
 		var request *pb.DelState
@@ -1088,6 +1452,144 @@ var _ = Describe("Handler", func() {
 		})
 	})
 
+	Describe("HandleGetStateMultiple", func() {
+		var incomingMessage *pb.ChaincodeMessage
+		var request *msgs.GetStateMultiple
+
+		BeforeEach(func() {
+			fakeCapabilites.BatchStateAccessReturns(true)
+			handler.TotalQueryLimit = 10
+
+			request = &msgs.GetStateMultiple{Keys: []string{"key1", "key2"}}
+			payload, err := proto.Marshal(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:      msgs.TypeExtendedRequest,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}
+			fakeTxSimulator.GetStateMultipleKeysReturns([][]byte{[]byte("value1"), nil}, nil)
+		})
+
+		It("returns the values in a response message", func() {
+			resp, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Type).To(Equal(pb.ChaincodeMessage_RESPONSE))
+			Expect(resp.Txid).To(Equal("tx-id"))
+			Expect(resp.ChannelId).To(Equal("channel-id"))
+
+			result := &msgs.GetStateMultipleResult{}
+			Expect(proto.Unmarshal(resp.Payload, result)).To(Succeed())
+			Expect(result.Values).To(HaveLen(2))
+			Expect(result.Values[0]).To(Equal([]byte("value1")))
+			Expect(result.Values[1]).To(BeEmpty())
+		})
+
+		It("gets the state of the keys from the transaction simulator", func() {
+			_, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTxSimulator.GetStateMultipleKeysCallCount()).To(Equal(1))
+			ccname, keys := fakeTxSimulator.GetStateMultipleKeysArgsForCall(0)
+			Expect(ccname).To(Equal("cc-instance-name"))
+			Expect(keys).To(Equal([]string{"key1", "key2"}))
+		})
+
+		Context("when GetStateMultipleKeys fails", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.GetStateMultipleKeysReturns(nil, errors.New("tiktok"))
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+				Expect(err).To(MatchError("tiktok"))
+			})
+		})
+
+		Context("when the collection is provided", func() {
+			BeforeEach(func() {
+				request.Collection = "collection-name"
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+				fakeCollectionStore.RetrieveReadWritePermissionReturns(true, false, nil)
+				fakeTxSimulator.GetPrivateDataMultipleKeysReturns([][]byte{[]byte("value1"), []byte("value2")}, nil)
+			})
+
+			It("gets the private data of the keys from the transaction simulator", func() {
+				_, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeTxSimulator.GetPrivateDataMultipleKeysCallCount()).To(Equal(1))
+				ccname, collection, keys := fakeTxSimulator.GetPrivateDataMultipleKeysArgsForCall(0)
+				Expect(ccname).To(Equal("cc-instance-name"))
+				Expect(collection).To(Equal("collection-name"))
+				Expect(keys).To(Equal([]string{"key1", "key2"}))
+				Expect(fakeTxSimulator.GetStateMultipleKeysCallCount()).To(Equal(0))
+			})
+
+			Context("when the creator has no read access to the collection", func() {
+				BeforeEach(func() {
+					fakeCollectionStore.RetrieveReadWritePermissionReturns(false, false, nil)
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+					Expect(err).To(MatchError("tx creator does not have read access" +
+						" permission on privatedata in chaincodeName:cc-instance-name" +
+						" collectionName: collection-name"))
+					Expect(fakeTxSimulator.GetPrivateDataMultipleKeysCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the transaction is an Init transaction", func() {
+				BeforeEach(func() {
+					txContext.IsInitTransaction = true
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+					Expect(err).To(MatchError("private data APIs are not allowed in chaincode Init()"))
+				})
+			})
+		})
+
+		Context("when the batch exceeds the total query limit", func() {
+			BeforeEach(func() {
+				handler.TotalQueryLimit = 1
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+				Expect(err).To(MatchError("batch of 2 keys exceeds the limit of 1"))
+			})
+		})
+
+		Context("when unmarshaling the request fails", func() {
+			BeforeEach(func() {
+				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+				Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+			})
+		})
+
+		Context("when the batch state access capability is not enabled", func() {
+			BeforeEach(func() {
+				fakeCapabilites.BatchStateAccessReturns(false)
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetStateMultiple(incomingMessage, txContext)
+				Expect(err).To(MatchError("batch state access is not enabled, channel application capability of V2_5 or later is required"))
+			})
+		})
+	})
+
 	Describe("HandleGetPrivateDataHash", func() {
 		var (
 			incomingMessage  *pb.ChaincodeMessage
@@ -2181,6 +2683,22 @@ var _ = Describe("Handler", func() {
 			Expect(proposal).To(Equal(expectedSignedProp))
 		})
 
+		It("records the usage of the target execution with the caller", func() {
+			txContext.UsageRecorder = &ccprovider.UsageRecorder{}
+			_, err := handler.HandleInvokeChaincode(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeInvoker.InvokeCallCount()).To(Equal(1))
+			txParams, _, _ := fakeInvoker.InvokeArgsForCall(0)
+			Expect(txParams.UsageRecorder).To(BeIdenticalTo(txContext.UsageRecorder))
+		})
+
+		It("keeps the writes of a target on the same channel", func() {
+			_, err := handler.HandleInvokeChaincode(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeTxSimulator.GetTxSimulationResultsCallCount()).To(Equal(0))
+		})
+
 		Context("when the target channel is different from the context", func() {
 			BeforeEach(func() {
 				request = &pb.ChaincodeSpec{
@@ -2259,6 +2777,24 @@ var _ = Describe("Handler", func() {
 				Expect(newTxSimulator.DoneCallCount()).To(Equal(1))
 			})
 
+			It("inspects the simulation results of the target for discarded writes", func() {
+				newTxSimulator.GetTxSimulationResultsReturns(&ledger.TxSimulationResults{
+					PubSimulationResults: &rwset.TxReadWriteSet{
+						NsRwset: []*rwset.NsReadWriteSet{{
+							Namespace: "target-chaincode-name",
+							Rwset: protoutil.MarshalOrPanic(&kvrwset.KVRWSet{
+								Writes: []*kvrwset.KVWrite{{Key: "key", Value: []byte("value")}},
+							}),
+						}},
+					},
+				}, nil)
+				_, err := handler.HandleInvokeChaincode(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(newTxSimulator.GetTxSimulationResultsCallCount()).To(Equal(1))
+				Expect(fakeTxSimulator.GetTxSimulationResultsCallCount()).To(Equal(0))
+			})
+
 			Context("when getting the ledger for the target channel fails", func() {
 				BeforeEach(func() {
 					fakeLedgerGetter.GetLedgerReturns(nil)
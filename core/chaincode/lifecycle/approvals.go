@@ -0,0 +1,380 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mspprotos "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/osdi23p228/fabric/common/policies"
+	"github.com/osdi23p228/fabric/common/policies/inquire"
+	"github.com/osdi23p228/fabric/core/ledger"
+	"github.com/pkg/errors"
+)
+
+// approvalNameMatcher splits the name of a definition stored in an org's
+// implicit collection, '<chaincode>#<sequence>', into its parts.
+var approvalNameMatcher = regexp.MustCompile("^(.+)#([0-9]+)$")
+
+// PendingApproval is a chaincode definition approved by one or more orgs for
+// the next sequence of a chaincode, which has not been committed yet.
+type PendingApproval struct {
+	Name string
+
+	// Definition is the approved chaincode definition. When the definition
+	// was only approved by other orgs than the peer's org, only its sequence
+	// is known as the other orgs' approvals are only visible as hashes.
+	Definition *ChaincodeDefinition
+
+	// OtherOrgsOnly is true when the definition was only approved by other
+	// orgs than the peer's org.
+	OtherOrgsOnly bool
+
+	// Approvals contains whether each org of the channel approved the
+	// same definition.
+	Approvals map[string]bool
+
+	// Committable is true when the orgs which approved the definition
+	// can together satisfy the lifecycle endorsement policy.
+	Committable bool
+}
+
+// RangeableReadableState is the state of an org whose approvals may be
+// listed.
+type RangeableReadableState interface {
+	ReadableState
+	RangeableState
+}
+
+//go:generate counterfeiter -o mock/approval_listener.go --fake-name ApprovalListener . ApprovalListener
+
+// ApprovalListener is notified when a definition approved by the peer's org
+// becomes committable.
+type ApprovalListener interface {
+	HandleChaincodeDefinitionCommittable(channelID string, approval *PendingApproval)
+}
+
+// HandleChaincodeDefinitionCommittableFunc is triggered when a definition
+// approved by the peer's org becomes committable.
+type HandleChaincodeDefinitionCommittableFunc func(channelID string, approval *PendingApproval)
+
+// HandleChaincodeDefinitionCommittable runs whenever a definition approved by
+// the peer's org becomes committable on the channel.
+func (handleCommittable HandleChaincodeDefinitionCommittableFunc) HandleChaincodeDefinitionCommittable(channelID string, approval *PendingApproval) {
+	handleCommittable(channelID, approval)
+}
+
+//go:generate counterfeiter -o mock/pending_approvals_querier.go --fake-name PendingApprovalsQuerier . PendingApprovalsQuerier
+
+// PendingApprovalsQuerier retrieves the pending approvals from the
+// committed state of a channel.
+type PendingApprovalsQuerier interface {
+	QueryPendingApprovals(channelID string) ([]*PendingApproval, error)
+}
+
+// QueryPendingApprovals returns the definitions approved by the orgs whose
+// orgStates were provided for the next sequence of a chaincode, along with
+// whether or not each org approved the same definition. The chaincodes
+// considered are those defined in the public state and those with a
+// definition in the org state. The definitions in the org state are returned
+// in full; those which only other orgs approved are grouped by the hashes of
+// their parameters and returned with their sequence only. A chaincode which
+// was never defined and which the peer's org has not approved cannot be found
+// from the hashes of the other orgs' approvals, so it is not returned.
+func (ef *ExternalFunctions) QueryPendingApprovals(chname string, publicState RangeableReadableState, orgState RangeableReadableState, orgStates []OpaqueState) ([]*PendingApproval, error) {
+	metadatas, err := ef.Resources.Serializer.DeserializeAllMetadata(NamespacesName, orgState)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not query approved definitions")
+	}
+
+	// nextSequences holds the next sequence of each chaincode considered and
+	// orgApprovals the org approvals of the definition in the org state
+	nextSequences := map[string]int64{}
+	orgApprovals := map[string]map[string]bool{}
+	var pendingApprovals []*PendingApproval
+	for privateName, metadata := range metadatas {
+		if metadata.Datatype != ChaincodeParametersType {
+			continue
+		}
+		matches := approvalNameMatcher.FindStringSubmatch(privateName)
+		if len(matches) != 3 {
+			continue
+		}
+		name := matches[1]
+		sequence, err := strconv.ParseInt(matches[2], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		currentSequence, err := ef.Resources.Serializer.DeserializeFieldAsInt64(NamespacesName, name, "Sequence", publicState)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not get current sequence for chaincode '%s'", name)
+		}
+		nextSequences[name] = currentSequence + 1
+		if sequence != currentSequence+1 {
+			continue
+		}
+
+		ccParameters := &ChaincodeParameters{}
+		if err := ef.Resources.Serializer.Deserialize(NamespacesName, privateName, metadata, ccParameters, orgState); err != nil {
+			return nil, errors.WithMessagef(err, "could not deserialize chaincode parameters for %s", privateName)
+		}
+
+		cd := &ChaincodeDefinition{
+			Sequence:        sequence,
+			EndorsementInfo: ccParameters.EndorsementInfo,
+			ValidationInfo:  ccParameters.ValidationInfo,
+			Collections:     ccParameters.Collections,
+		}
+		approvals, err := ef.QueryOrgApprovals(name, cd, orgStates)
+		if err != nil {
+			return nil, err
+		}
+		committable, err := ef.Resources.ApprovalsSatisfyLifecyclePolicy(chname, approvals)
+		if err != nil {
+			return nil, err
+		}
+
+		orgApprovals[name] = approvals
+		pendingApprovals = append(pendingApprovals, &PendingApproval{
+			Name:        name,
+			Definition:  cd,
+			Approvals:   approvals,
+			Committable: committable,
+		})
+	}
+
+	definitions, err := ef.Resources.Serializer.DeserializeAllMetadata(NamespacesName, publicState)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not query defined chaincodes")
+	}
+	for name, metadata := range definitions {
+		if _, ok := nextSequences[name]; ok || metadata.Datatype != ChaincodeDefinitionType {
+			continue
+		}
+		currentSequence, err := ef.Resources.Serializer.DeserializeFieldAsInt64(NamespacesName, name, "Sequence", publicState)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not get current sequence for chaincode '%s'", name)
+		}
+		nextSequences[name] = currentSequence + 1
+	}
+
+	for name, sequence := range nextSequences {
+		otherApprovals, err := ef.queryOtherOrgsApprovals(chname, name, sequence, orgApprovals[name], orgStates)
+		if err != nil {
+			return nil, err
+		}
+		pendingApprovals = append(pendingApprovals, otherApprovals...)
+	}
+
+	logger.Infof("Successfully queried %d pending approvals on channel '%s'", len(pendingApprovals), chname)
+
+	return pendingApprovals, nil
+}
+
+// queryOtherOrgsApprovals returns the definitions approved for the sequence
+// of the chaincode by the orgs which did not approve the definition in the
+// peer's org state, whose approvals are given by knownApprovals. The orgs are
+// grouped by the hashes of the parameters they approved.
+func (ef *ExternalFunctions) queryOtherOrgsApprovals(chname, name string, sequence int64, knownApprovals map[string]bool, orgStates []OpaqueState) ([]*PendingApproval, error) {
+	privateName := fmt.Sprintf("%s#%d", name, sequence)
+	_, fields, err := ef.Resources.Serializer.SerializableChecks(&ChaincodeParameters{})
+	if err != nil {
+		return nil, err
+	}
+
+	var parametersHashes []string
+	approvingOrgs := map[string]map[string]bool{}
+	for _, orgState := range orgStates {
+		org := OrgFromImplicitCollectionName(orgState.CollectionName())
+		if knownApprovals[org] {
+			continue
+		}
+		approved, err := ef.Resources.Serializer.IsMetadataSerialized(NamespacesName, privateName, &ChaincodeParameters{}, orgState)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not query approval of org '%s' for %s", org, privateName)
+		}
+		if !approved {
+			continue
+		}
+
+		var parametersHash []byte
+		for _, field := range fields {
+			fieldKey := FieldKey(NamespacesName, privateName, field)
+			hash, err := orgState.GetStateHash(fieldKey)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "could not get state hash for key %s", fieldKey)
+			}
+			parametersHash = append(parametersHash, hash...)
+		}
+		if _, ok := approvingOrgs[string(parametersHash)]; !ok {
+			parametersHashes = append(parametersHashes, string(parametersHash))
+			approvingOrgs[string(parametersHash)] = map[string]bool{}
+		}
+		approvingOrgs[string(parametersHash)][org] = true
+	}
+
+	var pendingApprovals []*PendingApproval
+	for _, parametersHash := range parametersHashes {
+		approvals := map[string]bool{}
+		for _, orgState := range orgStates {
+			org := OrgFromImplicitCollectionName(orgState.CollectionName())
+			approvals[org] = approvingOrgs[parametersHash][org]
+		}
+		committable, err := ef.Resources.ApprovalsSatisfyLifecyclePolicy(chname, approvals)
+		if err != nil {
+			return nil, err
+		}
+
+		pendingApprovals = append(pendingApprovals, &PendingApproval{
+			Name:          name,
+			Definition:    &ChaincodeDefinition{Sequence: sequence},
+			OtherOrgsOnly: true,
+			Approvals:     approvals,
+			Committable:   committable,
+		})
+	}
+	return pendingApprovals, nil
+}
+
+// ApprovalsSatisfyLifecyclePolicy returns whether the orgs which approved a
+// definition can together satisfy the lifecycle endorsement policy of the
+// channel, which the endorsements of its commit must satisfy. An org is
+// assumed to be able to satisfy any principal of its MSP.
+func (r *Resources) ApprovalsSatisfyLifecyclePolicy(channelID string, approvals map[string]bool) (bool, error) {
+	policyBytes, err := r.LifecycleEndorsementPolicyAsBytes(channelID)
+	if err != nil {
+		return false, err
+	}
+
+	ap := &cb.ApplicationPolicy{}
+	if err := proto.Unmarshal(policyBytes, ap); err != nil {
+		return false, errors.Wrap(err, "could not unmarshal lifecycle endorsement policy")
+	}
+
+	var spe *cb.SignaturePolicyEnvelope
+	switch policy := ap.Type.(type) {
+	case *cb.ApplicationPolicy_SignaturePolicy:
+		spe = policy.SignaturePolicy
+	case *cb.ApplicationPolicy_ChannelConfigPolicyReference:
+		p, ok := r.ChannelConfigSource.GetStableChannelConfig(channelID).PolicyManager().GetPolicy(policy.ChannelConfigPolicyReference)
+		if !ok {
+			return false, errors.Errorf("could not retrieve policy for reference '%s' on channel '%s'", policy.ChannelConfigPolicyReference, channelID)
+		}
+		cp, ok := p.(policies.Converter)
+		if !ok {
+			return false, errors.Errorf("policy with reference '%s' on channel '%s' is not convertible to SignaturePolicyEnvelope", policy.ChannelConfigPolicyReference, channelID)
+		}
+		if spe, err = cp.Convert(); err != nil {
+			return false, errors.WithMessagef(err, "error converting policy with reference '%s' on channel '%s' to SignaturePolicyEnvelope", policy.ChannelConfigPolicyReference, channelID)
+		}
+	default:
+		return false, errors.Errorf("unsupported policy type %T on channel '%s'", policy, channelID)
+	}
+
+	for _, principalSet := range inquire.NewInquireableSignaturePolicy(spe).SatisfiedBy() {
+		if principalsApproved(principalSet, approvals) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func principalsApproved(principalSet policies.PrincipalSet, approvals map[string]bool) bool {
+	for _, principal := range principalSet {
+		if !approvals[principalMSPID(principal)] {
+			return false
+		}
+	}
+	return true
+}
+
+// principalMSPID returns the MSP ID of a principal, or an empty string for
+// principals which do not belong to an MSP.
+func principalMSPID(principal *mspprotos.MSPPrincipal) string {
+	switch principal.PrincipalClassification {
+	case mspprotos.MSPPrincipal_ROLE:
+		role := &mspprotos.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err == nil {
+			return role.MspIdentifier
+		}
+	case mspprotos.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mspprotos.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err == nil {
+			return ou.MspIdentifier
+		}
+	case mspprotos.MSPPrincipal_IDENTITY:
+		sid := &mspprotos.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, sid); err == nil {
+			return sid.Mspid
+		}
+	}
+	return ""
+}
+
+// LedgerGetter retrieves the ledger of a channel.
+type LedgerGetter interface {
+	GetLedger(channelID string) ledger.PeerLedger
+}
+
+// LedgerPendingApprovals queries the pending approvals from the committed
+// state of the channel ledgers.
+type LedgerPendingApprovals struct {
+	Functions           *ExternalFunctions
+	ChannelConfigSource ChannelConfigSource
+	LedgerGetter        LedgerGetter
+	OrgMSPID            string
+}
+
+// QueryPendingApprovals implements the PendingApprovalsQuerier interface.
+func (lpa *LedgerPendingApprovals) QueryPendingApprovals(channelID string) ([]*PendingApproval, error) {
+	channelConfig := lpa.ChannelConfigSource.GetStableChannelConfig(channelID)
+	if channelConfig == nil {
+		return nil, errors.Errorf("could not get channel config for channel '%s'", channelID)
+	}
+	ac, ok := channelConfig.ApplicationConfig()
+	if !ok {
+		return nil, errors.Errorf("could not get application config for channel '%s'", channelID)
+	}
+
+	l := lpa.LedgerGetter.GetLedger(channelID)
+	if l == nil {
+		return nil, errors.Errorf("could not get ledger for channel '%s'", channelID)
+	}
+	qe, err := l.NewQueryExecutor()
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not create query executor")
+	}
+	defer qe.Done()
+
+	var orgStates []OpaqueState
+	for _, org := range ac.Organizations() {
+		orgStates = append(orgStates, &PrivateQueryExecutorShim{
+			Namespace:  LifecycleNamespace,
+			Collection: ImplicitCollectionNameForOrg(org.MSPID()),
+			State:      qe,
+		})
+	}
+
+	return lpa.Functions.QueryPendingApprovals(
+		channelID,
+		&SimpleQueryExecutorShim{
+			Namespace:           LifecycleNamespace,
+			SimpleQueryExecutor: qe,
+		},
+		&PrivateStateQueryExecutorShim{
+			Namespace:  LifecycleNamespace,
+			Collection: ImplicitCollectionNameForOrg(lpa.OrgMSPID),
+			State:      qe,
+		},
+		orgStates,
+	)
+}
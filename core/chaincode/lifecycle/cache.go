@@ -84,6 +84,19 @@ type Cache struct {
 	eventBroker     *EventBroker
 	MetadataHandler MetadataHandler
 
+	// PendingApprovalsQuerier, when set, is used to process the pending
+	// approvals of the org whenever the approvals on a channel are updated.
+	PendingApprovalsQuerier PendingApprovalsQuerier
+
+	// approvalsUpdated is the set of channels whose approvals were updated
+	// by the state updates being committed.
+	approvalsUpdated map[string]struct{}
+
+	// approvalsMutex serializes the processing of pending approvals so that
+	// approvals queried from an older state are never processed after those
+	// queried from a newer one.
+	approvalsMutex sync.Mutex
+
 	chaincodeCustodian *ChaincodeCustodian
 }
 
@@ -131,6 +144,7 @@ func NewCache(resources *Resources, myOrgMSPID string, metadataManager MetadataH
 		MyOrgMSPID:         myOrgMSPID,
 		eventBroker:        NewEventBroker(resources.ChaincodeStore, resources.PackageParser, ebMetadata),
 		MetadataHandler:    metadataManager,
+		approvalsUpdated:   map[string]struct{}{},
 	}
 }
 
@@ -258,6 +272,36 @@ func (c *Cache) handleChaincodeInstalledWhileLocked(initializing bool, md *persi
 	}
 }
 
+// HandleChaincodeUninstalled should be invoked whenever a chaincode is uninstalled
+func (c *Cache) HandleChaincodeUninstalled(packageID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	encodedCCHash := protoutil.MarshalOrPanic(&lb.StateData{
+		Type: &lb.StateData_String_{String_: packageID},
+	})
+	hashOfCCHash := string(util.ComputeSHA256(encodedCCHash))
+	localChaincode, ok := c.localChaincodes[hashOfCCHash]
+	if !ok || localChaincode.Info == nil {
+		return
+	}
+
+	localChaincode.Info = nil
+	for channelID, channelCache := range localChaincode.References {
+		for chaincodeName, cachedChaincode := range channelCache {
+			cachedChaincode.InstallInfo = nil
+			logger.Infof("Uninstalled chaincode with package ID '%s' no longer available on channel %s for chaincode definition %s:%s", packageID, channelID, chaincodeName, cachedChaincode.Definition.EndorsementInfo.Version)
+		}
+	}
+	c.chaincodeCustodian.NotifyStoppable(packageID)
+
+	if len(localChaincode.References) == 0 {
+		delete(c.localChaincodes, hashOfCCHash)
+		return
+	}
+	c.handleMetadataUpdates(localChaincode)
+}
+
 // HandleStateUpdates is required to implement the ledger state listener interface.  It applies
 // any state updates to the cache.
 func (c *Cache) HandleStateUpdates(trigger *ledger.StateUpdateTrigger) error {
@@ -278,6 +322,13 @@ func (c *Cache) HandleStateUpdates(trigger *ledger.StateUpdateTrigger) error {
 		}
 
 		dirtyChaincodes[matches[1]] = struct{}{}
+		c.approvalsUpdated[channelID] = struct{}{}
+	}
+
+	for collection := range updates.CollHashUpdates {
+		if ImplicitCollectionMatcher.MatchString(collection) {
+			c.approvalsUpdated[channelID] = struct{}{}
+		}
 	}
 
 	channelCache, ok := c.definedChaincodes[channelID]
@@ -330,6 +381,35 @@ func (c *Cache) StateCommitDone(channelName string) {
 	// must detect and cope with as necessary.  Note, the cache will always be _at least_
 	// as current as the committed state.
 	c.eventBroker.ApproveOrDefineCommitted(channelName)
+
+	c.mutex.Lock()
+	_, approvalsUpdated := c.approvalsUpdated[channelName]
+	delete(c.approvalsUpdated, channelName)
+	c.mutex.Unlock()
+
+	if approvalsUpdated && c.PendingApprovalsQuerier != nil {
+		// the pending approvals are queried from the ledger, so they are
+		// processed outside of the commit path
+		go c.processPendingApprovals(channelName)
+	}
+}
+
+func (c *Cache) processPendingApprovals(channelID string) {
+	c.approvalsMutex.Lock()
+	defer c.approvalsMutex.Unlock()
+
+	pendingApprovals, err := c.PendingApprovalsQuerier.QueryPendingApprovals(channelID)
+	if err != nil {
+		logger.Warningf("Could not query pending approvals on channel '%s': %s", channelID, err)
+		return
+	}
+	c.eventBroker.ProcessPendingApprovals(channelID, pendingApprovals)
+}
+
+// RegisterApprovalListener registers an event listener for receiving an event when a chaincode
+// definition approved by the peer's org becomes committable
+func (c *Cache) RegisterApprovalListener(channelID string, listener ApprovalListener) {
+	c.eventBroker.RegisterApprovalListener(channelID, listener)
 }
 
 // ChaincodeInfo returns the chaincode definition and its install info.
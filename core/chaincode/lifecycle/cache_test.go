@@ -337,6 +337,65 @@ var _ = Describe("Cache", func() {
 		})
 	})
 
+	Describe("HandleChaincodeUninstalled", func() {
+		BeforeEach(func() {
+			channelCache.Chaincodes["chaincode-name"].InstallInfo = localChaincodes[string(util.ComputeSHA256(protoutil.MarshalOrPanic(&lb.StateData{
+				Type: &lb.StateData_String_{String_: "packageID"},
+			})))].Info
+		})
+
+		It("removes the installed chaincode and its install info from the referencing definitions", func() {
+			c.HandleChaincodeUninstalled("packageID")
+
+			_, err := c.GetInstalledChaincode("packageID")
+			Expect(err).To(MatchError("could not find chaincode with package id 'packageID'"))
+			Expect(c.ListInstalledChaincodes()).To(BeEmpty())
+			Expect(channelCache.Chaincodes["chaincode-name"].InstallInfo).To(BeNil())
+
+			localInfo, err := c.ChaincodeInfo("channel-id", "chaincode-name")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(localInfo.InstallInfo).To(BeNil())
+		})
+
+		It("updates the metadata of the referencing channels", func() {
+			c.HandleChaincodeUninstalled("packageID")
+			Expect(fakeMetadataHandler.UpdateMetadataCallCount()).To(Equal(1))
+			channelID, _ := fakeMetadataHandler.UpdateMetadataArgsForCall(0)
+			Expect(channelID).To(Equal("channel-id"))
+		})
+
+		It("tells the custodian to stop the chaincode", func() {
+			c.HandleChaincodeUninstalled("packageID")
+
+			fakeLauncher := &mock.ChaincodeLauncher{}
+			go chaincodeCustodian.Work(&container.BuildRegistry{}, &mock.ChaincodeBuilder{}, fakeLauncher)
+			Eventually(fakeLauncher.StopCallCount).Should(Equal(1))
+			Expect(fakeLauncher.StopArgsForCall(0)).To(Equal("packageID"))
+		})
+
+		Context("when the chaincode is not referenced by any definition", func() {
+			BeforeEach(func() {
+				c.HandleChaincodeInstalled(&persistence.ChaincodePackageMetadata{
+					Type: "cc-type",
+					Path: "cc-path",
+				}, "unreferenced-packageID")
+			})
+
+			It("forgets the chaincode entirely", func() {
+				c.HandleChaincodeUninstalled("unreferenced-packageID")
+				Expect(localChaincodes).To(HaveLen(2))
+			})
+		})
+
+		Context("when the chaincode is not installed", func() {
+			It("does nothing", func() {
+				c.HandleChaincodeUninstalled("notinstalled-packageID")
+				Expect(localChaincodes).To(HaveLen(2))
+				Expect(fakeMetadataHandler.UpdateMetadataCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Describe("InitializeLocalChaincodes", func() {
 		It("loads the already installed chaincodes into the cache", func() {
 			Expect(channelCache.Chaincodes["chaincode-name"].InstallInfo).To(BeNil())
@@ -896,6 +955,87 @@ var _ = Describe("Cache", func() {
 					Expect(err).To(MatchError("no state updates for promised namespace _lifecycle"))
 				})
 			})
+
+			Context("when a pending approvals querier is set", func() {
+				var (
+					fakePendingApprovalsQuerier *mock.PendingApprovalsQuerier
+					fakeApprovalListener        *mock.ApprovalListener
+					pendingApproval             *lifecycle.PendingApproval
+				)
+
+				BeforeEach(func() {
+					pendingApproval = &lifecycle.PendingApproval{
+						Name:        "chaincode-name",
+						Definition:  &lifecycle.ChaincodeDefinition{Sequence: 8},
+						Committable: true,
+					}
+					fakePendingApprovalsQuerier = &mock.PendingApprovalsQuerier{}
+					fakePendingApprovalsQuerier.QueryPendingApprovalsReturns([]*lifecycle.PendingApproval{pendingApproval}, nil)
+					fakeApprovalListener = &mock.ApprovalListener{}
+					c.PendingApprovalsQuerier = fakePendingApprovalsQuerier
+					c.RegisterApprovalListener("channel-id", fakeApprovalListener)
+				})
+
+				It("processes the pending approvals once the state is committed", func() {
+					err := c.HandleStateUpdates(trigger)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(fakePendingApprovalsQuerier.QueryPendingApprovalsCallCount()).To(Equal(0))
+
+					c.StateCommitDone("channel-id")
+					Eventually(fakeApprovalListener.HandleChaincodeDefinitionCommittableCallCount).Should(Equal(1))
+					Expect(fakePendingApprovalsQuerier.QueryPendingApprovalsArgsForCall(0)).To(Equal("channel-id"))
+					channelID, approval := fakeApprovalListener.HandleChaincodeDefinitionCommittableArgsForCall(0)
+					Expect(channelID).To(Equal("channel-id"))
+					Expect(approval).To(Equal(pendingApproval))
+				})
+
+				Context("when another org's approval is updated", func() {
+					BeforeEach(func() {
+						trigger.StateUpdates["_lifecycle"].PublicUpdates = nil
+						trigger.StateUpdates["_lifecycle"].CollHashUpdates["_implicit_org_other-mspid"] = []*kvrwset.KVWriteHash{
+							{KeyHash: util.ComputeSHA256([]byte("namespaces/fields/chaincode-name#8/EndorsementInfo"))},
+						}
+					})
+
+					It("processes the pending approvals", func() {
+						err := c.HandleStateUpdates(trigger)
+						Expect(err).NotTo(HaveOccurred())
+						c.StateCommitDone("channel-id")
+						Eventually(fakePendingApprovalsQuerier.QueryPendingApprovalsCallCount).Should(Equal(1))
+						Eventually(fakeApprovalListener.HandleChaincodeDefinitionCommittableCallCount).Should(Equal(1))
+					})
+				})
+
+				Context("when no approvals are updated", func() {
+					BeforeEach(func() {
+						trigger.StateUpdates["_lifecycle"].PublicUpdates = nil
+						trigger.StateUpdates["_lifecycle"].CollHashUpdates["random-collection"] = []*kvrwset.KVWriteHash{
+							{KeyHash: util.ComputeSHA256([]byte("namespaces/fields/chaincode-name#8/EndorsementInfo"))},
+						}
+					})
+
+					It("does not process the pending approvals", func() {
+						err := c.HandleStateUpdates(trigger)
+						Expect(err).NotTo(HaveOccurred())
+						c.StateCommitDone("channel-id")
+						Consistently(fakePendingApprovalsQuerier.QueryPendingApprovalsCallCount).Should(Equal(0))
+					})
+				})
+
+				Context("when the pending approvals cannot be queried", func() {
+					BeforeEach(func() {
+						fakePendingApprovalsQuerier.QueryPendingApprovalsReturns(nil, fmt.Errorf("query-error"))
+					})
+
+					It("does not invoke the approval listeners", func() {
+						err := c.HandleStateUpdates(trigger)
+						Expect(err).NotTo(HaveOccurred())
+						c.StateCommitDone("channel-id")
+						Eventually(fakePendingApprovalsQuerier.QueryPendingApprovalsCallCount).Should(Equal(1))
+						Consistently(fakeApprovalListener.HandleChaincodeDefinitionCommittableCallCount).Should(Equal(0))
+					})
+				})
+			})
 		})
 	})
 
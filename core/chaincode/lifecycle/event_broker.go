@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package lifecycle
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/osdi23p228/fabric/core/container/externalbuilder"
@@ -23,6 +24,10 @@ type EventBroker struct {
 
 	mutex     sync.Mutex
 	listeners map[string][]ledger.ChaincodeLifecycleEventListener
+
+	approvalMutex     sync.Mutex
+	approvalListeners map[string][]ApprovalListener
+	committable       map[string]map[string]struct{}
 }
 
 func NewEventBroker(chaincodeStore ChaincodeStore, pkgParser PackageParser, ebMetadata *externalbuilder.MetadataProvider) *EventBroker {
@@ -32,6 +37,8 @@ func NewEventBroker(chaincodeStore ChaincodeStore, pkgParser PackageParser, ebMe
 		pkgParser:            pkgParser,
 		listeners:            make(map[string][]ledger.ChaincodeLifecycleEventListener),
 		defineCallbackStatus: &sync.Map{},
+		approvalListeners:    make(map[string][]ApprovalListener),
+		committable:          make(map[string]map[string]struct{}),
 	}
 }
 
@@ -41,6 +48,41 @@ func (b *EventBroker) RegisterListener(channelID string, listener ledger.Chainco
 	b.listeners[channelID] = append(b.listeners[channelID], listener)
 }
 
+// RegisterApprovalListener registers a listener notified when a definition
+// approved by the peer's org becomes committable on the channel.
+func (b *EventBroker) RegisterApprovalListener(channelID string, listener ApprovalListener) {
+	b.approvalMutex.Lock()
+	defer b.approvalMutex.Unlock()
+	b.approvalListeners[channelID] = append(b.approvalListeners[channelID], listener)
+}
+
+// ProcessPendingApprovals gets invoked with the pending approvals of a
+// channel after its approvals were updated. The approval listeners are
+// invoked for each definition approved by the peer's org which was not
+// committable the last time the pending approvals were processed.
+func (b *EventBroker) ProcessPendingApprovals(channelID string, pendingApprovals []*PendingApproval) {
+	b.approvalMutex.Lock()
+	defer b.approvalMutex.Unlock()
+
+	previous := b.committable[channelID]
+	committable := map[string]struct{}{}
+	for _, approval := range pendingApprovals {
+		if !approval.Committable || approval.OtherOrgsOnly {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s", approval.Name, approval.Definition)
+		committable[key] = struct{}{}
+		if _, ok := previous[key]; ok {
+			continue
+		}
+		logger.Debugf("Chaincode definition for chaincode '%s' on channel '%s' is committable with definition {%s}", approval.Name, channelID, approval.Definition)
+		for _, l := range b.approvalListeners[channelID] {
+			l.HandleChaincodeDefinitionCommittable(channelID, approval)
+		}
+	}
+	b.committable[channelID] = committable
+}
+
 // ProcessInstallEvent gets invoked when a chaincode is installed
 func (b *EventBroker) ProcessInstallEvent(localChaincode *LocalChaincode) {
 	logger.Debugf("ProcessInstallEvent() - localChaincode = %s", localChaincode.Info)
@@ -9,6 +9,7 @@ package lifecycle_test
 import (
 	"archive/tar"
 	"bytes"
+	"fmt"
 	"io"
 
 	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
@@ -246,3 +247,87 @@ var _ = Describe("EventBroker", func() {
 		})
 	})
 })
+
+var _ = Describe("EventBroker approvals", func() {
+	var (
+		fakeApprovalListener *mock.ApprovalListener
+		eventBroker          *lifecycle.EventBroker
+		pendingApprovals     []*lifecycle.PendingApproval
+	)
+
+	BeforeEach(func() {
+		fakeApprovalListener = &mock.ApprovalListener{}
+		eventBroker = lifecycle.NewEventBroker(&mock.ChaincodeStore{}, &mock.PackageParser{}, &externalbuilder.MetadataProvider{})
+		eventBroker.RegisterApprovalListener("channel-1", fakeApprovalListener)
+		pendingApprovals = []*lifecycle.PendingApproval{
+			{
+				Name: "chaincode-1",
+				Definition: &lifecycle.ChaincodeDefinition{
+					Sequence:        2,
+					EndorsementInfo: &lb.ChaincodeEndorsementInfo{Version: "v2"},
+				},
+				Committable: true,
+			},
+			{
+				Name: "chaincode-2",
+				Definition: &lifecycle.ChaincodeDefinition{
+					Sequence:        1,
+					EndorsementInfo: &lb.ChaincodeEndorsementInfo{Version: "v1"},
+				},
+			},
+		}
+	})
+
+	It("invokes the listener for the committable definitions", func() {
+		eventBroker.ProcessPendingApprovals("channel-1", pendingApprovals)
+		Expect(fakeApprovalListener.HandleChaincodeDefinitionCommittableCallCount()).To(Equal(1))
+		channelID, approval := fakeApprovalListener.HandleChaincodeDefinitionCommittableArgsForCall(0)
+		Expect(channelID).To(Equal("channel-1"))
+		Expect(approval).To(Equal(pendingApprovals[0]))
+	})
+
+	It("does not invoke the listener for the same committable definition twice", func() {
+		eventBroker.ProcessPendingApprovals("channel-1", pendingApprovals)
+		eventBroker.ProcessPendingApprovals("channel-1", pendingApprovals)
+		Expect(fakeApprovalListener.HandleChaincodeDefinitionCommittableCallCount()).To(Equal(1))
+	})
+
+	It("invokes the listener when a definition becomes committable", func() {
+		eventBroker.ProcessPendingApprovals("channel-1", pendingApprovals)
+		pendingApprovals[1].Committable = true
+		eventBroker.ProcessPendingApprovals("channel-1", pendingApprovals)
+		Expect(fakeApprovalListener.HandleChaincodeDefinitionCommittableCallCount()).To(Equal(2))
+		_, approval := fakeApprovalListener.HandleChaincodeDefinitionCommittableArgsForCall(1)
+		Expect(approval).To(Equal(pendingApprovals[1]))
+	})
+
+	It("invokes the listener again when a different definition is approved", func() {
+		eventBroker.ProcessPendingApprovals("channel-1", pendingApprovals)
+		pendingApprovals[0].Definition.EndorsementInfo.Version = "v2.1"
+		eventBroker.ProcessPendingApprovals("channel-1", pendingApprovals)
+		Expect(fakeApprovalListener.HandleChaincodeDefinitionCommittableCallCount()).To(Equal(2))
+	})
+
+	It("does not invoke the listener for the definitions only approved by other orgs", func() {
+		pendingApprovals[1].Committable = true
+		pendingApprovals[1].OtherOrgsOnly = true
+		eventBroker.ProcessPendingApprovals("channel-1", pendingApprovals)
+		Expect(fakeApprovalListener.HandleChaincodeDefinitionCommittableCallCount()).To(Equal(1))
+		_, approval := fakeApprovalListener.HandleChaincodeDefinitionCommittableArgsForCall(0)
+		Expect(approval).To(Equal(pendingApprovals[0]))
+	})
+
+	It("invokes the listeners registered as functions", func() {
+		var committable []string
+		eventBroker.RegisterApprovalListener("channel-1", lifecycle.HandleChaincodeDefinitionCommittableFunc(func(channelID string, approval *lifecycle.PendingApproval) {
+			committable = append(committable, fmt.Sprintf("%s/%s#%d", channelID, approval.Name, approval.Definition.Sequence))
+		}))
+		eventBroker.ProcessPendingApprovals("channel-1", pendingApprovals)
+		Expect(committable).To(Equal([]string{"channel-1/chaincode-1#2"}))
+	})
+
+	It("does not invoke the listeners of other channels", func() {
+		eventBroker.ProcessPendingApprovals("channel-2", pendingApprovals)
+		Expect(fakeApprovalListener.HandleChaincodeDefinitionCommittableCallCount()).To(Equal(0))
+	})
+})
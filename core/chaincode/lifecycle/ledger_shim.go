@@ -180,6 +180,38 @@ func (pqes *PrivateQueryExecutorShim) CollectionName() string {
 	return pqes.Collection
 }
 
+// PrivateStateQueryExecutorShim implements the ReadableState, RangeableState
+// and OpaqueState interfaces for a collection based on an underlying
+// ledger.QueryExecutor, which must be able to read the private data of the
+// collection.
+type PrivateStateQueryExecutorShim struct {
+	Namespace  string
+	Collection string
+	State      ledger.QueryExecutor
+}
+
+func (psqes *PrivateStateQueryExecutorShim) GetState(key string) ([]byte, error) {
+	return psqes.State.GetPrivateData(psqes.Namespace, psqes.Collection, key)
+}
+
+// GetStateRange performs a range query in the configured collection for all keys beginning
+// with a particular prefix.  This function assumes that keys contain only ascii chars from \x00 to \x7e.
+func (psqes *PrivateStateQueryExecutorShim) GetStateRange(prefix string) (map[string][]byte, error) {
+	itr, err := psqes.State.GetPrivateDataRangeScanIterator(psqes.Namespace, psqes.Collection, prefix, prefix+"\x7f")
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not get state iterator")
+	}
+	return StateIteratorToMap(&ResultsIteratorShim{ResultsIterator: itr})
+}
+
+func (psqes *PrivateStateQueryExecutorShim) GetStateHash(key string) ([]byte, error) {
+	return psqes.State.GetPrivateDataHash(psqes.Namespace, psqes.Collection, key)
+}
+
+func (psqes *PrivateStateQueryExecutorShim) CollectionName() string {
+	return psqes.Collection
+}
+
 // DummyQueryExecutorShim implements the ReadableState interface. It is
 // used to ensure channel-less system chaincode calls don't panic and return
 // and error when an invalid operation is attempted (i.e. an InstallChaincode
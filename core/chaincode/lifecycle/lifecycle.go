@@ -217,6 +217,11 @@ type InstallListener interface {
 	HandleChaincodeInstalled(md *persistence.ChaincodePackageMetadata, packageID string)
 }
 
+//go:generate counterfeiter -o mock/uninstall_listener.go --fake-name UninstallListener . UninstallListener
+type UninstallListener interface {
+	HandleChaincodeUninstalled(packageID string)
+}
+
 //go:generate counterfeiter -o mock/installed_chaincodes_lister.go --fake-name InstalledChaincodesLister . InstalledChaincodesLister
 type InstalledChaincodesLister interface {
 	ListInstalledChaincodes() []*chaincode.InstalledChaincode
@@ -306,11 +311,12 @@ func (r *Resources) LifecycleEndorsementPolicyAsBytes(channelID string) ([]byte,
 type ExternalFunctions struct {
 	Resources                 *Resources
 	InstallListener           InstallListener
+	UninstallListener         UninstallListener
 	InstalledChaincodesLister InstalledChaincodesLister
 	ChaincodeBuilder          ChaincodeBuilder
 	BuildRegistry             *container.BuildRegistry
 	mutex                     sync.Mutex
-	BuildLocks                map[string]sync.Mutex
+	BuildLocks                map[string]*sync.Mutex
 }
 
 // CheckCommitReadiness takes a chaincode definition, checks that
@@ -711,20 +717,52 @@ func (ef *ExternalFunctions) InstallChaincode(chaincodeInstallPackage []byte) (*
 	}, nil
 }
 
+// UninstallChaincode removes the chaincode install package with the supplied
+// package ID from the peer. Any running instance of the chaincode is stopped
+// and the package will no longer be available to chaincode definitions which
+// reference it.
+func (ef *ExternalFunctions) UninstallChaincode(packageID string) error {
+	buildLock := ef.getBuildLock(packageID)
+	buildLock.Lock()
+	defer buildLock.Unlock()
+
+	if _, err := ef.Resources.ChaincodeStore.Load(packageID); err != nil {
+		return errors.WithMessage(err, "could not load cc install package")
+	}
+
+	if err := ef.Resources.ChaincodeStore.Delete(packageID); err != nil {
+		return errors.WithMessage(err, "could not delete cc install package")
+	}
+
+	// the listener is only notified once the package is actually gone, so
+	// that the cache never reports an installed package as uninstalled
+	if ef.UninstallListener != nil {
+		ef.UninstallListener.HandleChaincodeUninstalled(packageID)
+	}
+
+	// a later install of the same package must build it again
+	ef.BuildRegistry.RemoveBuildStatus(packageID)
+
+	logger.Infof("Successfully uninstalled chaincode with package ID '%s'", packageID)
+
+	return nil
+}
+
 func (ef *ExternalFunctions) getBuildLock(packageID string) *sync.Mutex {
 	ef.mutex.Lock()
 	defer ef.mutex.Unlock()
 
 	if ef.BuildLocks == nil {
-		ef.BuildLocks = map[string]sync.Mutex{}
+		ef.BuildLocks = map[string]*sync.Mutex{}
 	}
 
 	buildLock, ok := ef.BuildLocks[packageID]
 	if !ok {
-		ef.BuildLocks[packageID] = sync.Mutex{}
+		buildLock = &sync.Mutex{}
+		ef.BuildLocks[packageID] = buildLock
 	}
 
-	return &buildLock
+	return buildLock
 }
 
 // GetInstalledChaincodePackage retrieves the installed chaincode with the given package ID
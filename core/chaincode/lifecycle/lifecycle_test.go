@@ -15,6 +15,7 @@ import (
 	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
 	"github.com/osdi23p228/fabric/common/chaincode"
 	"github.com/osdi23p228/fabric/common/channelconfig"
+	"github.com/osdi23p228/fabric/common/policydsl"
 	"github.com/osdi23p228/fabric/core/chaincode/lifecycle"
 	"github.com/osdi23p228/fabric/core/chaincode/lifecycle/mock"
 	"github.com/osdi23p228/fabric/core/chaincode/persistence"
@@ -264,6 +265,87 @@ var _ = Describe("Resources", func() {
 			})
 		})
 	})
+	Describe("ApprovalsSatisfyLifecyclePolicy", func() {
+		var fakePolicy *mock.ConvertiblePolicy
+
+		BeforeEach(func() {
+			fakePolicy = &mock.ConvertiblePolicy{}
+			fakePolicy.ConvertReturns(policydsl.SignedByAnyMember([]string{"first-mspid", "second-mspid"}), nil)
+			fakePolicyManager.GetPolicyReturns(fakePolicy, true)
+		})
+
+		It("evaluates the approvals against the referenced lifecycle endorsement policy", func() {
+			committable, err := resources.ApprovalsSatisfyLifecyclePolicy("channel-id", map[string]bool{
+				"first-mspid":  false,
+				"second-mspid": true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(committable).To(BeTrue())
+			Expect(fakePolicyManager.GetPolicyArgsForCall(1)).To(Equal("/Channel/Application/LifecycleEndorsement"))
+
+			committable, err = resources.ApprovalsSatisfyLifecyclePolicy("channel-id", map[string]bool{
+				"first-mspid":  false,
+				"second-mspid": false,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(committable).To(BeFalse())
+		})
+
+		Context("when the lifecycle endorsement policy is not defined", func() {
+			BeforeEach(func() {
+				fakePolicyManager.GetPolicyReturns(nil, false)
+			})
+
+			It("evaluates the approvals against the default majority policy", func() {
+				committable, err := resources.ApprovalsSatisfyLifecyclePolicy("channel-id", map[string]bool{
+					"first-mspid":  true,
+					"second-mspid": false,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(committable).To(BeFalse())
+
+				committable, err = resources.ApprovalsSatisfyLifecyclePolicy("channel-id", map[string]bool{
+					"first-mspid":  true,
+					"second-mspid": true,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(committable).To(BeTrue())
+			})
+		})
+
+		Context("when the referenced policy is not convertible", func() {
+			BeforeEach(func() {
+				fakePolicyManager.GetPolicyReturns(&mock.InconvertiblePolicy{}, true)
+			})
+
+			It("returns an error", func() {
+				_, err := resources.ApprovalsSatisfyLifecyclePolicy("channel-id", map[string]bool{})
+				Expect(err).To(MatchError("policy with reference '/Channel/Application/LifecycleEndorsement' on channel 'channel-id' is not convertible to SignaturePolicyEnvelope"))
+			})
+		})
+
+		Context("when the referenced policy cannot be converted", func() {
+			BeforeEach(func() {
+				fakePolicy.ConvertReturns(nil, errors.New("convert-error"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := resources.ApprovalsSatisfyLifecyclePolicy("channel-id", map[string]bool{})
+				Expect(err).To(MatchError("error converting policy with reference '/Channel/Application/LifecycleEndorsement' on channel 'channel-id' to SignaturePolicyEnvelope: convert-error"))
+			})
+		})
+
+		Context("when the channel config cannot be retrieved", func() {
+			BeforeEach(func() {
+				fakeChannelConfigSource.GetStableChannelConfigReturns(nil)
+			})
+
+			It("returns an error", func() {
+				_, err := resources.ApprovalsSatisfyLifecyclePolicy("channel-id", map[string]bool{})
+				Expect(err).To(MatchError("could not get channel config for channel 'channel-id'"))
+			})
+		})
+	})
 })
 
 var _ = Describe("ExternalFunctions", func() {
@@ -274,6 +356,7 @@ var _ = Describe("ExternalFunctions", func() {
 		fakeChaincodeBuilder    *mock.ChaincodeBuilder
 		fakeParser              *mock.PackageParser
 		fakeListener            *mock.InstallListener
+		fakeUninstallListener   *mock.UninstallListener
 		fakeLister              *mock.InstalledChaincodesLister
 		fakeChannelConfigSource *mock.ChannelConfigSource
 		fakeChannelConfig       *mock.ChannelConfig
@@ -287,6 +370,7 @@ var _ = Describe("ExternalFunctions", func() {
 		fakeChaincodeBuilder = &mock.ChaincodeBuilder{}
 		fakeParser = &mock.PackageParser{}
 		fakeListener = &mock.InstallListener{}
+		fakeUninstallListener = &mock.UninstallListener{}
 		fakeLister = &mock.InstalledChaincodesLister{}
 		fakeChannelConfigSource = &mock.ChannelConfigSource{}
 		fakeChannelConfig = &mock.ChannelConfig{}
@@ -315,6 +399,7 @@ var _ = Describe("ExternalFunctions", func() {
 		ef = &lifecycle.ExternalFunctions{
 			Resources:                 resources,
 			InstallListener:           fakeListener,
+			UninstallListener:         fakeUninstallListener,
 			InstalledChaincodesLister: fakeLister,
 			ChaincodeBuilder:          fakeChaincodeBuilder,
 			BuildRegistry:             &container.BuildRegistry{},
@@ -450,6 +535,58 @@ var _ = Describe("ExternalFunctions", func() {
 		})
 	})
 
+	Describe("UninstallChaincode", func() {
+		BeforeEach(func() {
+			fakeCCStore.LoadReturns([]byte("code-package"), nil)
+		})
+
+		It("removes the chaincode and notifies the uninstall listener", func() {
+			err := ef.UninstallChaincode("some-package-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeUninstallListener.HandleChaincodeUninstalledCallCount()).To(Equal(1))
+			Expect(fakeUninstallListener.HandleChaincodeUninstalledArgsForCall(0)).To(Equal("some-package-id"))
+			Expect(fakeCCStore.DeleteCallCount()).To(Equal(1))
+			Expect(fakeCCStore.DeleteArgsForCall(0)).To(Equal("some-package-id"))
+		})
+
+		It("forgets the build status of the chaincode", func() {
+			bs, _ := ef.BuildRegistry.BuildStatus("some-package-id")
+			bs.Notify(nil)
+
+			err := ef.UninstallChaincode("some-package-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, ok := ef.BuildRegistry.BuildStatus("some-package-id")
+			Expect(ok).To(BeFalse())
+		})
+
+		Context("when the chaincode is not installed", func() {
+			BeforeEach(func() {
+				fakeCCStore.LoadReturns(nil, persistence.CodePackageNotFoundErr{PackageID: "some-package-id"})
+			})
+
+			It("returns the error and leaves everything untouched", func() {
+				err := ef.UninstallChaincode("some-package-id")
+				Expect(err).To(MatchError("could not load cc install package: chaincode install package 'some-package-id' not found"))
+				Expect(fakeUninstallListener.HandleChaincodeUninstalledCallCount()).To(Equal(0))
+				Expect(fakeCCStore.DeleteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when deleting the chaincode fails", func() {
+			BeforeEach(func() {
+				fakeCCStore.DeleteReturns(fmt.Errorf("fake-error"))
+			})
+
+			It("wraps and returns the error without notifying the uninstall listener", func() {
+				err := ef.UninstallChaincode("some-package-id")
+				Expect(err).To(MatchError("could not delete cc install package: fake-error"))
+				Expect(fakeUninstallListener.HandleChaincodeUninstalledCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Describe("GetInstalledChaincodePackage", func() {
 		BeforeEach(func() {
 			fakeCCStore.LoadReturns([]byte("code-package"), nil)
@@ -1765,6 +1902,218 @@ var _ = Describe("ExternalFunctions", func() {
 		})
 	})
 
+	Describe("QueryPendingApprovals", func() {
+		var (
+			fakePublicState *mock.ReadWritableState
+			fakeOrgStates   []*mock.ReadWritableState
+
+			testDefinition *lifecycle.ChaincodeDefinition
+
+			publicKVS, org0KVS, org1KVS MapLedgerShim
+		)
+
+		BeforeEach(func() {
+			fakePolicyManager.GetPolicyReturns(nil, false)
+
+			testDefinition = &lifecycle.ChaincodeDefinition{
+				Sequence: 5,
+				EndorsementInfo: &lb.ChaincodeEndorsementInfo{
+					Version:           "version",
+					EndorsementPlugin: "endorsement-plugin",
+				},
+				ValidationInfo: &lb.ChaincodeValidationInfo{
+					ValidationPlugin:    "validation-plugin",
+					ValidationParameter: []byte("validation-parameter"),
+				},
+				Collections: &pb.CollectionConfigPackage{},
+			}
+
+			publicKVS = MapLedgerShim(map[string][]byte{})
+			fakePublicState = &mock.ReadWritableState{}
+			fakePublicState.GetStateStub = publicKVS.GetState
+			fakePublicState.GetStateRangeStub = publicKVS.GetStateRange
+
+			resources.Serializer.Serialize("namespaces", "cc-name", &lifecycle.ChaincodeDefinition{
+				Sequence: 4,
+				EndorsementInfo: &lb.ChaincodeEndorsementInfo{
+					Version: "version",
+				},
+				ValidationInfo: &lb.ChaincodeValidationInfo{},
+			}, publicKVS)
+			resources.Serializer.Serialize("namespaces", "defined-name", &lifecycle.ChaincodeDefinition{
+				Sequence: 2,
+				EndorsementInfo: &lb.ChaincodeEndorsementInfo{
+					Version: "version",
+				},
+				ValidationInfo: &lb.ChaincodeValidationInfo{},
+			}, publicKVS)
+
+			org0KVS = MapLedgerShim(map[string][]byte{})
+			org1KVS = MapLedgerShim(map[string][]byte{})
+			fakeOrg0State := &mock.ReadWritableState{}
+			fakeOrg0State.CollectionNameReturns("_implicit_org_first-mspid")
+			fakeOrg1State := &mock.ReadWritableState{}
+			fakeOrg1State.CollectionNameReturns("_implicit_org_second-mspid")
+			fakeOrgStates = []*mock.ReadWritableState{
+				fakeOrg0State,
+				fakeOrg1State,
+			}
+			for i, kvs := range []MapLedgerShim{org0KVS, org1KVS} {
+				kvs := kvs
+				fakeOrgStates[i].GetStateStub = kvs.GetState
+				fakeOrgStates[i].GetStateHashStub = kvs.GetStateHash
+				fakeOrgStates[i].GetStateRangeStub = kvs.GetStateRange
+				fakeOrgStates[i].PutStateStub = kvs.PutState
+			}
+
+			resources.Serializer.Serialize("namespaces", "cc-name#4", &lifecycle.ChaincodeParameters{}, fakeOrgStates[0])
+			resources.Serializer.Serialize("namespaces", "cc-name#5", testDefinition.Parameters(), fakeOrgStates[0])
+			resources.Serializer.Serialize("namespaces", "cc-name#5", testDefinition.Parameters(), fakeOrgStates[1])
+			resources.Serializer.Serialize("namespaces", "other-name#1", testDefinition.Parameters(), fakeOrgStates[0])
+			resources.Serializer.Serialize("namespaces", "other-name#1", &lifecycle.ChaincodeParameters{}, fakeOrgStates[1])
+			resources.Serializer.Serialize("namespaces", "defined-name#2", testDefinition.Parameters(), fakeOrgStates[1])
+			resources.Serializer.Serialize("namespaces", "defined-name#3", testDefinition.Parameters(), fakeOrgStates[1])
+		})
+
+		It("returns the definitions approved for the next sequence along with the org approvals", func() {
+			pendingApprovals, err := ef.QueryPendingApprovals("my-channel", fakePublicState, fakeOrgStates[0], []lifecycle.OpaqueState{fakeOrgStates[0], fakeOrgStates[1]})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pendingApprovals).To(ConsistOf(
+				&lifecycle.PendingApproval{
+					Name:       "cc-name",
+					Definition: testDefinition,
+					Approvals: map[string]bool{
+						"first-mspid":  true,
+						"second-mspid": true,
+					},
+					Committable: true,
+				},
+				&lifecycle.PendingApproval{
+					Name: "other-name",
+					Definition: &lifecycle.ChaincodeDefinition{
+						Sequence:        1,
+						EndorsementInfo: testDefinition.EndorsementInfo,
+						ValidationInfo:  testDefinition.ValidationInfo,
+						Collections:     testDefinition.Collections,
+					},
+					Approvals: map[string]bool{
+						"first-mspid":  true,
+						"second-mspid": false,
+					},
+					Committable: false,
+				},
+				&lifecycle.PendingApproval{
+					Name:          "other-name",
+					Definition:    &lifecycle.ChaincodeDefinition{Sequence: 1},
+					OtherOrgsOnly: true,
+					Approvals: map[string]bool{
+						"first-mspid":  false,
+						"second-mspid": true,
+					},
+					Committable: false,
+				},
+				&lifecycle.PendingApproval{
+					Name:          "defined-name",
+					Definition:    &lifecycle.ChaincodeDefinition{Sequence: 3},
+					OtherOrgsOnly: true,
+					Approvals: map[string]bool{
+						"first-mspid":  false,
+						"second-mspid": true,
+					},
+					Committable: false,
+				},
+			))
+		})
+
+		Context("when other orgs approved different definitions", func() {
+			BeforeEach(func() {
+				fakeOrg2State := &mock.ReadWritableState{}
+				fakeOrg2State.CollectionNameReturns("_implicit_org_third-mspid")
+				org2KVS := MapLedgerShim(map[string][]byte{})
+				fakeOrg2State.GetStateHashStub = org2KVS.GetStateHash
+				fakeOrg2State.PutStateStub = org2KVS.PutState
+				fakeOrgStates = append(fakeOrgStates, fakeOrg2State)
+				resources.Serializer.Serialize("namespaces", "defined-name#3", &lifecycle.ChaincodeParameters{}, fakeOrgStates[2])
+			})
+
+			It("groups the orgs by the definition they approved", func() {
+				pendingApprovals, err := ef.QueryPendingApprovals("my-channel", fakePublicState, fakeOrgStates[0], []lifecycle.OpaqueState{fakeOrgStates[0], fakeOrgStates[1], fakeOrgStates[2]})
+				Expect(err).NotTo(HaveOccurred())
+				var definedNameApprovals []map[string]bool
+				for _, pendingApproval := range pendingApprovals {
+					if pendingApproval.Name == "defined-name" {
+						definedNameApprovals = append(definedNameApprovals, pendingApproval.Approvals)
+					}
+				}
+				Expect(definedNameApprovals).To(ConsistOf(
+					map[string]bool{"first-mspid": false, "second-mspid": true, "third-mspid": false},
+					map[string]bool{"first-mspid": false, "second-mspid": false, "third-mspid": true},
+				))
+			})
+		})
+
+		Context("when the approved definitions cannot be listed", func() {
+			BeforeEach(func() {
+				fakeOrgStates[0].GetStateRangeReturns(nil, errors.New("range-error"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := ef.QueryPendingApprovals("my-channel", fakePublicState, fakeOrgStates[0], []lifecycle.OpaqueState{fakeOrgStates[0], fakeOrgStates[1]})
+				Expect(err).To(MatchError("could not query approved definitions: could not get state range for namespace namespaces: range-error"))
+			})
+		})
+
+		Context("when the defined chaincodes cannot be listed", func() {
+			BeforeEach(func() {
+				fakePublicState.GetStateRangeReturns(nil, errors.New("range-error"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := ef.QueryPendingApprovals("my-channel", fakePublicState, fakeOrgStates[0], []lifecycle.OpaqueState{fakeOrgStates[0], fakeOrgStates[1]})
+				Expect(err).To(MatchError("could not query defined chaincodes: could not get state range for namespace namespaces: range-error"))
+			})
+		})
+
+		Context("when the approval hashes of another org cannot be retrieved", func() {
+			BeforeEach(func() {
+				fakeOrgStates[1].GetStateHashStub = func(key string) ([]byte, error) {
+					if key == "namespaces/fields/defined-name#3/EndorsementInfo" {
+						return nil, errors.New("hash-error")
+					}
+					return org1KVS.GetStateHash(key)
+				}
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := ef.QueryPendingApprovals("my-channel", fakePublicState, fakeOrgStates[0], []lifecycle.OpaqueState{fakeOrgStates[0], fakeOrgStates[1]})
+				Expect(err).To(MatchError("could not get state hash for key namespaces/fields/defined-name#3/EndorsementInfo: hash-error"))
+			})
+		})
+
+		Context("when the current sequence cannot be retrieved", func() {
+			BeforeEach(func() {
+				fakePublicState.GetStateReturns(nil, errors.New("state-error"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := ef.QueryPendingApprovals("my-channel", fakePublicState, fakeOrgStates[0], []lifecycle.OpaqueState{fakeOrgStates[0], fakeOrgStates[1]})
+				Expect(err).To(MatchError(ContainSubstring("could not get current sequence for chaincode")))
+				Expect(err).To(MatchError(ContainSubstring("state-error")))
+			})
+		})
+
+		Context("when the org approvals cannot be queried", func() {
+			BeforeEach(func() {
+				fakeOrgStates[1].GetStateHashReturns(nil, errors.New("hash-error"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := ef.QueryPendingApprovals("my-channel", fakePublicState, fakeOrgStates[0], []lifecycle.OpaqueState{fakeOrgStates[0], fakeOrgStates[1]})
+				Expect(err).To(MatchError(ContainSubstring("hash-error")))
+			})
+		})
+	})
+
 	Describe("QueryNamespaceDefinitions", func() {
 		var (
 			fakePublicState *mock.ReadWritableState
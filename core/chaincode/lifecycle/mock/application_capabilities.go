@@ -16,6 +16,16 @@ type ApplicationCapabilities struct {
 	aCLsReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	BatchStateAccessStub        func() bool
+	batchStateAccessMutex       sync.RWMutex
+	batchStateAccessArgsForCall []struct {
+	}
+	batchStateAccessReturns struct {
+		result1 bool
+	}
+	batchStateAccessReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	CollectionUpgradeStub        func() bool
 	collectionUpgradeMutex       sync.RWMutex
 	collectionUpgradeArgsForCall []struct {
@@ -76,6 +86,16 @@ type ApplicationCapabilities struct {
 	privateChannelDataReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	PurgePvtDataStub        func() bool
+	purgePvtDataMutex       sync.RWMutex
+	purgePvtDataArgsForCall []struct {
+	}
+	purgePvtDataReturns struct {
+		result1 bool
+	}
+	purgePvtDataReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	StorePvtDataOfInvalidTxStub        func() bool
 	storePvtDataOfInvalidTxMutex       sync.RWMutex
 	storePvtDataOfInvalidTxArgsForCall []struct {
@@ -145,15 +165,16 @@ func (fake *ApplicationCapabilities) ACLs() bool {
 	ret, specificReturn := fake.aCLsReturnsOnCall[len(fake.aCLsArgsForCall)]
 	fake.aCLsArgsForCall = append(fake.aCLsArgsForCall, struct {
 	}{})
+	stub := fake.ACLsStub
+	fakeReturns := fake.aCLsReturns
 	fake.recordInvocation("ACLs", []interface{}{})
 	fake.aCLsMutex.Unlock()
-	if fake.ACLsStub != nil {
-		return fake.ACLsStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.aCLsReturns
 	return fakeReturns.result1
 }
 
@@ -192,20 +213,74 @@ func (fake *ApplicationCapabilities) ACLsReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *ApplicationCapabilities) BatchStateAccess() bool {
+	fake.batchStateAccessMutex.Lock()
+	ret, specificReturn := fake.batchStateAccessReturnsOnCall[len(fake.batchStateAccessArgsForCall)]
+	fake.batchStateAccessArgsForCall = append(fake.batchStateAccessArgsForCall, struct {
+	}{})
+	stub := fake.BatchStateAccessStub
+	fakeReturns := fake.batchStateAccessReturns
+	fake.recordInvocation("BatchStateAccess", []interface{}{})
+	fake.batchStateAccessMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ApplicationCapabilities) BatchStateAccessCallCount() int {
+	fake.batchStateAccessMutex.RLock()
+	defer fake.batchStateAccessMutex.RUnlock()
+	return len(fake.batchStateAccessArgsForCall)
+}
+
+func (fake *ApplicationCapabilities) BatchStateAccessCalls(stub func() bool) {
+	fake.batchStateAccessMutex.Lock()
+	defer fake.batchStateAccessMutex.Unlock()
+	fake.BatchStateAccessStub = stub
+}
+
+func (fake *ApplicationCapabilities) BatchStateAccessReturns(result1 bool) {
+	fake.batchStateAccessMutex.Lock()
+	defer fake.batchStateAccessMutex.Unlock()
+	fake.BatchStateAccessStub = nil
+	fake.batchStateAccessReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ApplicationCapabilities) BatchStateAccessReturnsOnCall(i int, result1 bool) {
+	fake.batchStateAccessMutex.Lock()
+	defer fake.batchStateAccessMutex.Unlock()
+	fake.BatchStateAccessStub = nil
+	if fake.batchStateAccessReturnsOnCall == nil {
+		fake.batchStateAccessReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.batchStateAccessReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *ApplicationCapabilities) CollectionUpgrade() bool {
 	fake.collectionUpgradeMutex.Lock()
 	ret, specificReturn := fake.collectionUpgradeReturnsOnCall[len(fake.collectionUpgradeArgsForCall)]
 	fake.collectionUpgradeArgsForCall = append(fake.collectionUpgradeArgsForCall, struct {
 	}{})
+	stub := fake.CollectionUpgradeStub
+	fakeReturns := fake.collectionUpgradeReturns
 	fake.recordInvocation("CollectionUpgrade", []interface{}{})
 	fake.collectionUpgradeMutex.Unlock()
-	if fake.CollectionUpgradeStub != nil {
-		return fake.CollectionUpgradeStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.collectionUpgradeReturns
 	return fakeReturns.result1
 }
 
@@ -249,15 +324,16 @@ func (fake *ApplicationCapabilities) ForbidDuplicateTXIdInBlock() bool {
 	ret, specificReturn := fake.forbidDuplicateTXIdInBlockReturnsOnCall[len(fake.forbidDuplicateTXIdInBlockArgsForCall)]
 	fake.forbidDuplicateTXIdInBlockArgsForCall = append(fake.forbidDuplicateTXIdInBlockArgsForCall, struct {
 	}{})
+	stub := fake.ForbidDuplicateTXIdInBlockStub
+	fakeReturns := fake.forbidDuplicateTXIdInBlockReturns
 	fake.recordInvocation("ForbidDuplicateTXIdInBlock", []interface{}{})
 	fake.forbidDuplicateTXIdInBlockMutex.Unlock()
-	if fake.ForbidDuplicateTXIdInBlockStub != nil {
-		return fake.ForbidDuplicateTXIdInBlockStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.forbidDuplicateTXIdInBlockReturns
 	return fakeReturns.result1
 }
 
@@ -301,15 +377,16 @@ func (fake *ApplicationCapabilities) KeyLevelEndorsement() bool {
 	ret, specificReturn := fake.keyLevelEndorsementReturnsOnCall[len(fake.keyLevelEndorsementArgsForCall)]
 	fake.keyLevelEndorsementArgsForCall = append(fake.keyLevelEndorsementArgsForCall, struct {
 	}{})
+	stub := fake.KeyLevelEndorsementStub
+	fakeReturns := fake.keyLevelEndorsementReturns
 	fake.recordInvocation("KeyLevelEndorsement", []interface{}{})
 	fake.keyLevelEndorsementMutex.Unlock()
-	if fake.KeyLevelEndorsementStub != nil {
-		return fake.KeyLevelEndorsementStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.keyLevelEndorsementReturns
 	return fakeReturns.result1
 }
 
@@ -353,15 +430,16 @@ func (fake *ApplicationCapabilities) LifecycleV20() bool {
 	ret, specificReturn := fake.lifecycleV20ReturnsOnCall[len(fake.lifecycleV20ArgsForCall)]
 	fake.lifecycleV20ArgsForCall = append(fake.lifecycleV20ArgsForCall, struct {
 	}{})
+	stub := fake.LifecycleV20Stub
+	fakeReturns := fake.lifecycleV20Returns
 	fake.recordInvocation("LifecycleV20", []interface{}{})
 	fake.lifecycleV20Mutex.Unlock()
-	if fake.LifecycleV20Stub != nil {
-		return fake.LifecycleV20Stub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.lifecycleV20Returns
 	return fakeReturns.result1
 }
 
@@ -405,15 +483,16 @@ func (fake *ApplicationCapabilities) MetadataLifecycle() bool {
 	ret, specificReturn := fake.metadataLifecycleReturnsOnCall[len(fake.metadataLifecycleArgsForCall)]
 	fake.metadataLifecycleArgsForCall = append(fake.metadataLifecycleArgsForCall, struct {
 	}{})
+	stub := fake.MetadataLifecycleStub
+	fakeReturns := fake.metadataLifecycleReturns
 	fake.recordInvocation("MetadataLifecycle", []interface{}{})
 	fake.metadataLifecycleMutex.Unlock()
-	if fake.MetadataLifecycleStub != nil {
-		return fake.MetadataLifecycleStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.metadataLifecycleReturns
 	return fakeReturns.result1
 }
 
@@ -457,15 +536,16 @@ func (fake *ApplicationCapabilities) PrivateChannelData() bool {
 	ret, specificReturn := fake.privateChannelDataReturnsOnCall[len(fake.privateChannelDataArgsForCall)]
 	fake.privateChannelDataArgsForCall = append(fake.privateChannelDataArgsForCall, struct {
 	}{})
+	stub := fake.PrivateChannelDataStub
+	fakeReturns := fake.privateChannelDataReturns
 	fake.recordInvocation("PrivateChannelData", []interface{}{})
 	fake.privateChannelDataMutex.Unlock()
-	if fake.PrivateChannelDataStub != nil {
-		return fake.PrivateChannelDataStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.privateChannelDataReturns
 	return fakeReturns.result1
 }
 
@@ -504,20 +584,74 @@ func (fake *ApplicationCapabilities) PrivateChannelDataReturnsOnCall(i int, resu
 	}{result1}
 }
 
+func (fake *ApplicationCapabilities) PurgePvtData() bool {
+	fake.purgePvtDataMutex.Lock()
+	ret, specificReturn := fake.purgePvtDataReturnsOnCall[len(fake.purgePvtDataArgsForCall)]
+	fake.purgePvtDataArgsForCall = append(fake.purgePvtDataArgsForCall, struct {
+	}{})
+	stub := fake.PurgePvtDataStub
+	fakeReturns := fake.purgePvtDataReturns
+	fake.recordInvocation("PurgePvtData", []interface{}{})
+	fake.purgePvtDataMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ApplicationCapabilities) PurgePvtDataCallCount() int {
+	fake.purgePvtDataMutex.RLock()
+	defer fake.purgePvtDataMutex.RUnlock()
+	return len(fake.purgePvtDataArgsForCall)
+}
+
+func (fake *ApplicationCapabilities) PurgePvtDataCalls(stub func() bool) {
+	fake.purgePvtDataMutex.Lock()
+	defer fake.purgePvtDataMutex.Unlock()
+	fake.PurgePvtDataStub = stub
+}
+
+func (fake *ApplicationCapabilities) PurgePvtDataReturns(result1 bool) {
+	fake.purgePvtDataMutex.Lock()
+	defer fake.purgePvtDataMutex.Unlock()
+	fake.PurgePvtDataStub = nil
+	fake.purgePvtDataReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ApplicationCapabilities) PurgePvtDataReturnsOnCall(i int, result1 bool) {
+	fake.purgePvtDataMutex.Lock()
+	defer fake.purgePvtDataMutex.Unlock()
+	fake.PurgePvtDataStub = nil
+	if fake.purgePvtDataReturnsOnCall == nil {
+		fake.purgePvtDataReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.purgePvtDataReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *ApplicationCapabilities) StorePvtDataOfInvalidTx() bool {
 	fake.storePvtDataOfInvalidTxMutex.Lock()
 	ret, specificReturn := fake.storePvtDataOfInvalidTxReturnsOnCall[len(fake.storePvtDataOfInvalidTxArgsForCall)]
 	fake.storePvtDataOfInvalidTxArgsForCall = append(fake.storePvtDataOfInvalidTxArgsForCall, struct {
 	}{})
+	stub := fake.StorePvtDataOfInvalidTxStub
+	fakeReturns := fake.storePvtDataOfInvalidTxReturns
 	fake.recordInvocation("StorePvtDataOfInvalidTx", []interface{}{})
 	fake.storePvtDataOfInvalidTxMutex.Unlock()
-	if fake.StorePvtDataOfInvalidTxStub != nil {
-		return fake.StorePvtDataOfInvalidTxStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.storePvtDataOfInvalidTxReturns
 	return fakeReturns.result1
 }
 
@@ -561,15 +695,16 @@ func (fake *ApplicationCapabilities) Supported() error {
 	ret, specificReturn := fake.supportedReturnsOnCall[len(fake.supportedArgsForCall)]
 	fake.supportedArgsForCall = append(fake.supportedArgsForCall, struct {
 	}{})
+	stub := fake.SupportedStub
+	fakeReturns := fake.supportedReturns
 	fake.recordInvocation("Supported", []interface{}{})
 	fake.supportedMutex.Unlock()
-	if fake.SupportedStub != nil {
-		return fake.SupportedStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.supportedReturns
 	return fakeReturns.result1
 }
 
@@ -613,15 +748,16 @@ func (fake *ApplicationCapabilities) V1_1Validation() bool {
 	ret, specificReturn := fake.v1_1ValidationReturnsOnCall[len(fake.v1_1ValidationArgsForCall)]
 	fake.v1_1ValidationArgsForCall = append(fake.v1_1ValidationArgsForCall, struct {
 	}{})
+	stub := fake.V1_1ValidationStub
+	fakeReturns := fake.v1_1ValidationReturns
 	fake.recordInvocation("V1_1Validation", []interface{}{})
 	fake.v1_1ValidationMutex.Unlock()
-	if fake.V1_1ValidationStub != nil {
-		return fake.V1_1ValidationStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.v1_1ValidationReturns
 	return fakeReturns.result1
 }
 
@@ -665,15 +801,16 @@ func (fake *ApplicationCapabilities) V1_2Validation() bool {
 	ret, specificReturn := fake.v1_2ValidationReturnsOnCall[len(fake.v1_2ValidationArgsForCall)]
 	fake.v1_2ValidationArgsForCall = append(fake.v1_2ValidationArgsForCall, struct {
 	}{})
+	stub := fake.V1_2ValidationStub
+	fakeReturns := fake.v1_2ValidationReturns
 	fake.recordInvocation("V1_2Validation", []interface{}{})
 	fake.v1_2ValidationMutex.Unlock()
-	if fake.V1_2ValidationStub != nil {
-		return fake.V1_2ValidationStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.v1_2ValidationReturns
 	return fakeReturns.result1
 }
 
@@ -717,15 +854,16 @@ func (fake *ApplicationCapabilities) V1_3Validation() bool {
 	ret, specificReturn := fake.v1_3ValidationReturnsOnCall[len(fake.v1_3ValidationArgsForCall)]
 	fake.v1_3ValidationArgsForCall = append(fake.v1_3ValidationArgsForCall, struct {
 	}{})
+	stub := fake.V1_3ValidationStub
+	fakeReturns := fake.v1_3ValidationReturns
 	fake.recordInvocation("V1_3Validation", []interface{}{})
 	fake.v1_3ValidationMutex.Unlock()
-	if fake.V1_3ValidationStub != nil {
-		return fake.V1_3ValidationStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.v1_3ValidationReturns
 	return fakeReturns.result1
 }
 
@@ -769,15 +907,16 @@ func (fake *ApplicationCapabilities) V2_0Validation() bool {
 	ret, specificReturn := fake.v2_0ValidationReturnsOnCall[len(fake.v2_0ValidationArgsForCall)]
 	fake.v2_0ValidationArgsForCall = append(fake.v2_0ValidationArgsForCall, struct {
 	}{})
+	stub := fake.V2_0ValidationStub
+	fakeReturns := fake.v2_0ValidationReturns
 	fake.recordInvocation("V2_0Validation", []interface{}{})
 	fake.v2_0ValidationMutex.Unlock()
-	if fake.V2_0ValidationStub != nil {
-		return fake.V2_0ValidationStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.v2_0ValidationReturns
 	return fakeReturns.result1
 }
 
@@ -821,6 +960,8 @@ func (fake *ApplicationCapabilities) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.aCLsMutex.RLock()
 	defer fake.aCLsMutex.RUnlock()
+	fake.batchStateAccessMutex.RLock()
+	defer fake.batchStateAccessMutex.RUnlock()
 	fake.collectionUpgradeMutex.RLock()
 	defer fake.collectionUpgradeMutex.RUnlock()
 	fake.forbidDuplicateTXIdInBlockMutex.RLock()
@@ -833,6 +974,8 @@ func (fake *ApplicationCapabilities) Invocations() map[string][][]interface{} {
 	defer fake.metadataLifecycleMutex.RUnlock()
 	fake.privateChannelDataMutex.RLock()
 	defer fake.privateChannelDataMutex.RUnlock()
+	fake.purgePvtDataMutex.RLock()
+	defer fake.purgePvtDataMutex.RUnlock()
 	fake.storePvtDataOfInvalidTxMutex.RLock()
 	defer fake.storePvtDataOfInvalidTxMutex.RUnlock()
 	fake.supportedMutex.RLock()
@@ -0,0 +1,78 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"sync"
+
+	"github.com/osdi23p228/fabric/core/chaincode/lifecycle"
+)
+
+type ApprovalListener struct {
+	HandleChaincodeDefinitionCommittableStub        func(string, *lifecycle.PendingApproval)
+	handleChaincodeDefinitionCommittableMutex       sync.RWMutex
+	handleChaincodeDefinitionCommittableArgsForCall []struct {
+		arg1 string
+		arg2 *lifecycle.PendingApproval
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ApprovalListener) HandleChaincodeDefinitionCommittable(arg1 string, arg2 *lifecycle.PendingApproval) {
+	fake.handleChaincodeDefinitionCommittableMutex.Lock()
+	fake.handleChaincodeDefinitionCommittableArgsForCall = append(fake.handleChaincodeDefinitionCommittableArgsForCall, struct {
+		arg1 string
+		arg2 *lifecycle.PendingApproval
+	}{arg1, arg2})
+	stub := fake.HandleChaincodeDefinitionCommittableStub
+	fake.recordInvocation("HandleChaincodeDefinitionCommittable", []interface{}{arg1, arg2})
+	fake.handleChaincodeDefinitionCommittableMutex.Unlock()
+	if stub != nil {
+		fake.HandleChaincodeDefinitionCommittableStub(arg1, arg2)
+	}
+}
+
+func (fake *ApprovalListener) HandleChaincodeDefinitionCommittableCallCount() int {
+	fake.handleChaincodeDefinitionCommittableMutex.RLock()
+	defer fake.handleChaincodeDefinitionCommittableMutex.RUnlock()
+	return len(fake.handleChaincodeDefinitionCommittableArgsForCall)
+}
+
+func (fake *ApprovalListener) HandleChaincodeDefinitionCommittableCalls(stub func(string, *lifecycle.PendingApproval)) {
+	fake.handleChaincodeDefinitionCommittableMutex.Lock()
+	defer fake.handleChaincodeDefinitionCommittableMutex.Unlock()
+	fake.HandleChaincodeDefinitionCommittableStub = stub
+}
+
+func (fake *ApprovalListener) HandleChaincodeDefinitionCommittableArgsForCall(i int) (string, *lifecycle.PendingApproval) {
+	fake.handleChaincodeDefinitionCommittableMutex.RLock()
+	defer fake.handleChaincodeDefinitionCommittableMutex.RUnlock()
+	argsForCall := fake.handleChaincodeDefinitionCommittableArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ApprovalListener) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.handleChaincodeDefinitionCommittableMutex.RLock()
+	defer fake.handleChaincodeDefinitionCommittableMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *ApprovalListener) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ lifecycle.ApprovalListener = new(ApprovalListener)
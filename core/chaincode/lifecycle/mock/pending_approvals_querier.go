@@ -0,0 +1,116 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"sync"
+
+	"github.com/osdi23p228/fabric/core/chaincode/lifecycle"
+)
+
+type PendingApprovalsQuerier struct {
+	QueryPendingApprovalsStub        func(string) ([]*lifecycle.PendingApproval, error)
+	queryPendingApprovalsMutex       sync.RWMutex
+	queryPendingApprovalsArgsForCall []struct {
+		arg1 string
+	}
+	queryPendingApprovalsReturns struct {
+		result1 []*lifecycle.PendingApproval
+		result2 error
+	}
+	queryPendingApprovalsReturnsOnCall map[int]struct {
+		result1 []*lifecycle.PendingApproval
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PendingApprovalsQuerier) QueryPendingApprovals(arg1 string) ([]*lifecycle.PendingApproval, error) {
+	fake.queryPendingApprovalsMutex.Lock()
+	ret, specificReturn := fake.queryPendingApprovalsReturnsOnCall[len(fake.queryPendingApprovalsArgsForCall)]
+	fake.queryPendingApprovalsArgsForCall = append(fake.queryPendingApprovalsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.QueryPendingApprovalsStub
+	fakeReturns := fake.queryPendingApprovalsReturns
+	fake.recordInvocation("QueryPendingApprovals", []interface{}{arg1})
+	fake.queryPendingApprovalsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PendingApprovalsQuerier) QueryPendingApprovalsCallCount() int {
+	fake.queryPendingApprovalsMutex.RLock()
+	defer fake.queryPendingApprovalsMutex.RUnlock()
+	return len(fake.queryPendingApprovalsArgsForCall)
+}
+
+func (fake *PendingApprovalsQuerier) QueryPendingApprovalsCalls(stub func(string) ([]*lifecycle.PendingApproval, error)) {
+	fake.queryPendingApprovalsMutex.Lock()
+	defer fake.queryPendingApprovalsMutex.Unlock()
+	fake.QueryPendingApprovalsStub = stub
+}
+
+func (fake *PendingApprovalsQuerier) QueryPendingApprovalsArgsForCall(i int) string {
+	fake.queryPendingApprovalsMutex.RLock()
+	defer fake.queryPendingApprovalsMutex.RUnlock()
+	argsForCall := fake.queryPendingApprovalsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PendingApprovalsQuerier) QueryPendingApprovalsReturns(result1 []*lifecycle.PendingApproval, result2 error) {
+	fake.queryPendingApprovalsMutex.Lock()
+	defer fake.queryPendingApprovalsMutex.Unlock()
+	fake.QueryPendingApprovalsStub = nil
+	fake.queryPendingApprovalsReturns = struct {
+		result1 []*lifecycle.PendingApproval
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PendingApprovalsQuerier) QueryPendingApprovalsReturnsOnCall(i int, result1 []*lifecycle.PendingApproval, result2 error) {
+	fake.queryPendingApprovalsMutex.Lock()
+	defer fake.queryPendingApprovalsMutex.Unlock()
+	fake.QueryPendingApprovalsStub = nil
+	if fake.queryPendingApprovalsReturnsOnCall == nil {
+		fake.queryPendingApprovalsReturnsOnCall = make(map[int]struct {
+			result1 []*lifecycle.PendingApproval
+			result2 error
+		})
+	}
+	fake.queryPendingApprovalsReturnsOnCall[i] = struct {
+		result1 []*lifecycle.PendingApproval
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PendingApprovalsQuerier) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.queryPendingApprovalsMutex.RLock()
+	defer fake.queryPendingApprovalsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PendingApprovalsQuerier) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ lifecycle.PendingApprovalsQuerier = new(PendingApprovalsQuerier)
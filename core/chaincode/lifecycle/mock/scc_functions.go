@@ -167,6 +167,33 @@ type SCCFunctions struct {
 		result1 map[string]bool
 		result2 error
 	}
+	QueryPendingApprovalsStub        func(string, lifecycle.RangeableReadableState, lifecycle.RangeableReadableState, []lifecycle.OpaqueState) ([]*lifecycle.PendingApproval, error)
+	queryPendingApprovalsMutex       sync.RWMutex
+	queryPendingApprovalsArgsForCall []struct {
+		arg1 string
+		arg2 lifecycle.RangeableReadableState
+		arg3 lifecycle.RangeableReadableState
+		arg4 []lifecycle.OpaqueState
+	}
+	queryPendingApprovalsReturns struct {
+		result1 []*lifecycle.PendingApproval
+		result2 error
+	}
+	queryPendingApprovalsReturnsOnCall map[int]struct {
+		result1 []*lifecycle.PendingApproval
+		result2 error
+	}
+	UninstallChaincodeStub        func(string) error
+	uninstallChaincodeMutex       sync.RWMutex
+	uninstallChaincodeArgsForCall []struct {
+		arg1 string
+	}
+	uninstallChaincodeReturns struct {
+		result1 error
+	}
+	uninstallChaincodeReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -182,15 +209,16 @@ func (fake *SCCFunctions) ApproveChaincodeDefinitionForOrg(arg1 string, arg2 str
 		arg5 lifecycle.ReadableState
 		arg6 lifecycle.ReadWritableState
 	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.ApproveChaincodeDefinitionForOrgStub
+	fakeReturns := fake.approveChaincodeDefinitionForOrgReturns
 	fake.recordInvocation("ApproveChaincodeDefinitionForOrg", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.approveChaincodeDefinitionForOrgMutex.Unlock()
-	if fake.ApproveChaincodeDefinitionForOrgStub != nil {
-		return fake.ApproveChaincodeDefinitionForOrgStub(arg1, arg2, arg3, arg4, arg5, arg6)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.approveChaincodeDefinitionForOrgReturns
 	return fakeReturns.result1
 }
 
@@ -251,15 +279,16 @@ func (fake *SCCFunctions) CheckCommitReadiness(arg1 string, arg2 string, arg3 *l
 		arg4 lifecycle.ReadWritableState
 		arg5 []lifecycle.OpaqueState
 	}{arg1, arg2, arg3, arg4, arg5Copy})
+	stub := fake.CheckCommitReadinessStub
+	fakeReturns := fake.checkCommitReadinessReturns
 	fake.recordInvocation("CheckCommitReadiness", []interface{}{arg1, arg2, arg3, arg4, arg5Copy})
 	fake.checkCommitReadinessMutex.Unlock()
-	if fake.CheckCommitReadinessStub != nil {
-		return fake.CheckCommitReadinessStub(arg1, arg2, arg3, arg4, arg5)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.checkCommitReadinessReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -323,15 +352,16 @@ func (fake *SCCFunctions) CommitChaincodeDefinition(arg1 string, arg2 string, ar
 		arg4 lifecycle.ReadWritableState
 		arg5 []lifecycle.OpaqueState
 	}{arg1, arg2, arg3, arg4, arg5Copy})
+	stub := fake.CommitChaincodeDefinitionStub
+	fakeReturns := fake.commitChaincodeDefinitionReturns
 	fake.recordInvocation("CommitChaincodeDefinition", []interface{}{arg1, arg2, arg3, arg4, arg5Copy})
 	fake.commitChaincodeDefinitionMutex.Unlock()
-	if fake.CommitChaincodeDefinitionStub != nil {
-		return fake.CommitChaincodeDefinitionStub(arg1, arg2, arg3, arg4, arg5)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.commitChaincodeDefinitionReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -386,15 +416,16 @@ func (fake *SCCFunctions) GetInstalledChaincodePackage(arg1 string) ([]byte, err
 	fake.getInstalledChaincodePackageArgsForCall = append(fake.getInstalledChaincodePackageArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.GetInstalledChaincodePackageStub
+	fakeReturns := fake.getInstalledChaincodePackageReturns
 	fake.recordInvocation("GetInstalledChaincodePackage", []interface{}{arg1})
 	fake.getInstalledChaincodePackageMutex.Unlock()
-	if fake.GetInstalledChaincodePackageStub != nil {
-		return fake.GetInstalledChaincodePackageStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getInstalledChaincodePackageReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -454,15 +485,16 @@ func (fake *SCCFunctions) InstallChaincode(arg1 []byte) (*chaincode.InstalledCha
 	fake.installChaincodeArgsForCall = append(fake.installChaincodeArgsForCall, struct {
 		arg1 []byte
 	}{arg1Copy})
+	stub := fake.InstallChaincodeStub
+	fakeReturns := fake.installChaincodeReturns
 	fake.recordInvocation("InstallChaincode", []interface{}{arg1Copy})
 	fake.installChaincodeMutex.Unlock()
-	if fake.InstallChaincodeStub != nil {
-		return fake.InstallChaincodeStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.installChaincodeReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -521,15 +553,16 @@ func (fake *SCCFunctions) QueryApprovedChaincodeDefinition(arg1 string, arg2 str
 		arg4 lifecycle.ReadableState
 		arg5 lifecycle.ReadableState
 	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.QueryApprovedChaincodeDefinitionStub
+	fakeReturns := fake.queryApprovedChaincodeDefinitionReturns
 	fake.recordInvocation("QueryApprovedChaincodeDefinition", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.queryApprovedChaincodeDefinitionMutex.Unlock()
-	if fake.QueryApprovedChaincodeDefinitionStub != nil {
-		return fake.QueryApprovedChaincodeDefinitionStub(arg1, arg2, arg3, arg4, arg5)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.queryApprovedChaincodeDefinitionReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -585,15 +618,16 @@ func (fake *SCCFunctions) QueryChaincodeDefinition(arg1 string, arg2 lifecycle.R
 		arg1 string
 		arg2 lifecycle.ReadableState
 	}{arg1, arg2})
+	stub := fake.QueryChaincodeDefinitionStub
+	fakeReturns := fake.queryChaincodeDefinitionReturns
 	fake.recordInvocation("QueryChaincodeDefinition", []interface{}{arg1, arg2})
 	fake.queryChaincodeDefinitionMutex.Unlock()
-	if fake.QueryChaincodeDefinitionStub != nil {
-		return fake.QueryChaincodeDefinitionStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.queryChaincodeDefinitionReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -648,15 +682,16 @@ func (fake *SCCFunctions) QueryInstalledChaincode(arg1 string) (*chaincode.Insta
 	fake.queryInstalledChaincodeArgsForCall = append(fake.queryInstalledChaincodeArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.QueryInstalledChaincodeStub
+	fakeReturns := fake.queryInstalledChaincodeReturns
 	fake.recordInvocation("QueryInstalledChaincode", []interface{}{arg1})
 	fake.queryInstalledChaincodeMutex.Unlock()
-	if fake.QueryInstalledChaincodeStub != nil {
-		return fake.QueryInstalledChaincodeStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.queryInstalledChaincodeReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -710,15 +745,16 @@ func (fake *SCCFunctions) QueryInstalledChaincodes() []*chaincode.InstalledChain
 	ret, specificReturn := fake.queryInstalledChaincodesReturnsOnCall[len(fake.queryInstalledChaincodesArgsForCall)]
 	fake.queryInstalledChaincodesArgsForCall = append(fake.queryInstalledChaincodesArgsForCall, struct {
 	}{})
+	stub := fake.QueryInstalledChaincodesStub
+	fakeReturns := fake.queryInstalledChaincodesReturns
 	fake.recordInvocation("QueryInstalledChaincodes", []interface{}{})
 	fake.queryInstalledChaincodesMutex.Unlock()
-	if fake.QueryInstalledChaincodesStub != nil {
-		return fake.QueryInstalledChaincodesStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.queryInstalledChaincodesReturns
 	return fakeReturns.result1
 }
 
@@ -763,15 +799,16 @@ func (fake *SCCFunctions) QueryNamespaceDefinitions(arg1 lifecycle.RangeableStat
 	fake.queryNamespaceDefinitionsArgsForCall = append(fake.queryNamespaceDefinitionsArgsForCall, struct {
 		arg1 lifecycle.RangeableState
 	}{arg1})
+	stub := fake.QueryNamespaceDefinitionsStub
+	fakeReturns := fake.queryNamespaceDefinitionsReturns
 	fake.recordInvocation("QueryNamespaceDefinitions", []interface{}{arg1})
 	fake.queryNamespaceDefinitionsMutex.Unlock()
-	if fake.QueryNamespaceDefinitionsStub != nil {
-		return fake.QueryNamespaceDefinitionsStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.queryNamespaceDefinitionsReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -833,15 +870,16 @@ func (fake *SCCFunctions) QueryOrgApprovals(arg1 string, arg2 *lifecycle.Chainco
 		arg2 *lifecycle.ChaincodeDefinition
 		arg3 []lifecycle.OpaqueState
 	}{arg1, arg2, arg3Copy})
+	stub := fake.QueryOrgApprovalsStub
+	fakeReturns := fake.queryOrgApprovalsReturns
 	fake.recordInvocation("QueryOrgApprovals", []interface{}{arg1, arg2, arg3Copy})
 	fake.queryOrgApprovalsMutex.Unlock()
-	if fake.QueryOrgApprovalsStub != nil {
-		return fake.QueryOrgApprovalsStub(arg1, arg2, arg3)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.queryOrgApprovalsReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -890,6 +928,139 @@ func (fake *SCCFunctions) QueryOrgApprovalsReturnsOnCall(i int, result1 map[stri
 	}{result1, result2}
 }
 
+func (fake *SCCFunctions) QueryPendingApprovals(arg1 string, arg2 lifecycle.RangeableReadableState, arg3 lifecycle.RangeableReadableState, arg4 []lifecycle.OpaqueState) ([]*lifecycle.PendingApproval, error) {
+	var arg4Copy []lifecycle.OpaqueState
+	if arg4 != nil {
+		arg4Copy = make([]lifecycle.OpaqueState, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.queryPendingApprovalsMutex.Lock()
+	ret, specificReturn := fake.queryPendingApprovalsReturnsOnCall[len(fake.queryPendingApprovalsArgsForCall)]
+	fake.queryPendingApprovalsArgsForCall = append(fake.queryPendingApprovalsArgsForCall, struct {
+		arg1 string
+		arg2 lifecycle.RangeableReadableState
+		arg3 lifecycle.RangeableReadableState
+		arg4 []lifecycle.OpaqueState
+	}{arg1, arg2, arg3, arg4Copy})
+	stub := fake.QueryPendingApprovalsStub
+	fakeReturns := fake.queryPendingApprovalsReturns
+	fake.recordInvocation("QueryPendingApprovals", []interface{}{arg1, arg2, arg3, arg4Copy})
+	fake.queryPendingApprovalsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SCCFunctions) QueryPendingApprovalsCallCount() int {
+	fake.queryPendingApprovalsMutex.RLock()
+	defer fake.queryPendingApprovalsMutex.RUnlock()
+	return len(fake.queryPendingApprovalsArgsForCall)
+}
+
+func (fake *SCCFunctions) QueryPendingApprovalsCalls(stub func(string, lifecycle.RangeableReadableState, lifecycle.RangeableReadableState, []lifecycle.OpaqueState) ([]*lifecycle.PendingApproval, error)) {
+	fake.queryPendingApprovalsMutex.Lock()
+	defer fake.queryPendingApprovalsMutex.Unlock()
+	fake.QueryPendingApprovalsStub = stub
+}
+
+func (fake *SCCFunctions) QueryPendingApprovalsArgsForCall(i int) (string, lifecycle.RangeableReadableState, lifecycle.RangeableReadableState, []lifecycle.OpaqueState) {
+	fake.queryPendingApprovalsMutex.RLock()
+	defer fake.queryPendingApprovalsMutex.RUnlock()
+	argsForCall := fake.queryPendingApprovalsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *SCCFunctions) QueryPendingApprovalsReturns(result1 []*lifecycle.PendingApproval, result2 error) {
+	fake.queryPendingApprovalsMutex.Lock()
+	defer fake.queryPendingApprovalsMutex.Unlock()
+	fake.QueryPendingApprovalsStub = nil
+	fake.queryPendingApprovalsReturns = struct {
+		result1 []*lifecycle.PendingApproval
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SCCFunctions) QueryPendingApprovalsReturnsOnCall(i int, result1 []*lifecycle.PendingApproval, result2 error) {
+	fake.queryPendingApprovalsMutex.Lock()
+	defer fake.queryPendingApprovalsMutex.Unlock()
+	fake.QueryPendingApprovalsStub = nil
+	if fake.queryPendingApprovalsReturnsOnCall == nil {
+		fake.queryPendingApprovalsReturnsOnCall = make(map[int]struct {
+			result1 []*lifecycle.PendingApproval
+			result2 error
+		})
+	}
+	fake.queryPendingApprovalsReturnsOnCall[i] = struct {
+		result1 []*lifecycle.PendingApproval
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SCCFunctions) UninstallChaincode(arg1 string) error {
+	fake.uninstallChaincodeMutex.Lock()
+	ret, specificReturn := fake.uninstallChaincodeReturnsOnCall[len(fake.uninstallChaincodeArgsForCall)]
+	fake.uninstallChaincodeArgsForCall = append(fake.uninstallChaincodeArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.UninstallChaincodeStub
+	fakeReturns := fake.uninstallChaincodeReturns
+	fake.recordInvocation("UninstallChaincode", []interface{}{arg1})
+	fake.uninstallChaincodeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *SCCFunctions) UninstallChaincodeCallCount() int {
+	fake.uninstallChaincodeMutex.RLock()
+	defer fake.uninstallChaincodeMutex.RUnlock()
+	return len(fake.uninstallChaincodeArgsForCall)
+}
+
+func (fake *SCCFunctions) UninstallChaincodeCalls(stub func(string) error) {
+	fake.uninstallChaincodeMutex.Lock()
+	defer fake.uninstallChaincodeMutex.Unlock()
+	fake.UninstallChaincodeStub = stub
+}
+
+func (fake *SCCFunctions) UninstallChaincodeArgsForCall(i int) string {
+	fake.uninstallChaincodeMutex.RLock()
+	defer fake.uninstallChaincodeMutex.RUnlock()
+	argsForCall := fake.uninstallChaincodeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *SCCFunctions) UninstallChaincodeReturns(result1 error) {
+	fake.uninstallChaincodeMutex.Lock()
+	defer fake.uninstallChaincodeMutex.Unlock()
+	fake.UninstallChaincodeStub = nil
+	fake.uninstallChaincodeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *SCCFunctions) UninstallChaincodeReturnsOnCall(i int, result1 error) {
+	fake.uninstallChaincodeMutex.Lock()
+	defer fake.uninstallChaincodeMutex.Unlock()
+	fake.UninstallChaincodeStub = nil
+	if fake.uninstallChaincodeReturnsOnCall == nil {
+		fake.uninstallChaincodeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.uninstallChaincodeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *SCCFunctions) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -915,6 +1086,10 @@ func (fake *SCCFunctions) Invocations() map[string][][]interface{} {
 	defer fake.queryNamespaceDefinitionsMutex.RUnlock()
 	fake.queryOrgApprovalsMutex.RLock()
 	defer fake.queryOrgApprovalsMutex.RUnlock()
+	fake.queryPendingApprovalsMutex.RLock()
+	defer fake.queryPendingApprovalsMutex.RUnlock()
+	fake.uninstallChaincodeMutex.RLock()
+	defer fake.uninstallChaincodeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
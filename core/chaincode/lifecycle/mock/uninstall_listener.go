@@ -0,0 +1,76 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"sync"
+
+	"github.com/osdi23p228/fabric/core/chaincode/lifecycle"
+)
+
+type UninstallListener struct {
+	HandleChaincodeUninstalledStub        func(string)
+	handleChaincodeUninstalledMutex       sync.RWMutex
+	handleChaincodeUninstalledArgsForCall []struct {
+		arg1 string
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *UninstallListener) HandleChaincodeUninstalled(arg1 string) {
+	fake.handleChaincodeUninstalledMutex.Lock()
+	fake.handleChaincodeUninstalledArgsForCall = append(fake.handleChaincodeUninstalledArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.HandleChaincodeUninstalledStub
+	fake.recordInvocation("HandleChaincodeUninstalled", []interface{}{arg1})
+	fake.handleChaincodeUninstalledMutex.Unlock()
+	if stub != nil {
+		fake.HandleChaincodeUninstalledStub(arg1)
+	}
+}
+
+func (fake *UninstallListener) HandleChaincodeUninstalledCallCount() int {
+	fake.handleChaincodeUninstalledMutex.RLock()
+	defer fake.handleChaincodeUninstalledMutex.RUnlock()
+	return len(fake.handleChaincodeUninstalledArgsForCall)
+}
+
+func (fake *UninstallListener) HandleChaincodeUninstalledCalls(stub func(string)) {
+	fake.handleChaincodeUninstalledMutex.Lock()
+	defer fake.handleChaincodeUninstalledMutex.Unlock()
+	fake.HandleChaincodeUninstalledStub = stub
+}
+
+func (fake *UninstallListener) HandleChaincodeUninstalledArgsForCall(i int) string {
+	fake.handleChaincodeUninstalledMutex.RLock()
+	defer fake.handleChaincodeUninstalledMutex.RUnlock()
+	argsForCall := fake.handleChaincodeUninstalledArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *UninstallListener) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.handleChaincodeUninstalledMutex.RLock()
+	defer fake.handleChaincodeUninstalledMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *UninstallListener) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ lifecycle.UninstallListener = new(UninstallListener)
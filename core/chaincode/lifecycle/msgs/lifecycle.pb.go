@@ -0,0 +1,332 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: lifecycle.proto
+
+package msgs
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	peer "github.com/hyperledger/fabric-protos-go/peer"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// UninstallChaincodeArgs is the message used as the argument to
+// '_lifecycle.UninstallChaincode'.
+type UninstallChaincodeArgs struct {
+	PackageId            string   `protobuf:"bytes,1,opt,name=package_id,json=packageId,proto3" json:"package_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UninstallChaincodeArgs) Reset()         { *m = UninstallChaincodeArgs{} }
+func (m *UninstallChaincodeArgs) String() string { return proto.CompactTextString(m) }
+func (*UninstallChaincodeArgs) ProtoMessage()    {}
+func (*UninstallChaincodeArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84f7c7eee8484930, []int{0}
+}
+
+func (m *UninstallChaincodeArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UninstallChaincodeArgs.Unmarshal(m, b)
+}
+func (m *UninstallChaincodeArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UninstallChaincodeArgs.Marshal(b, m, deterministic)
+}
+func (m *UninstallChaincodeArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UninstallChaincodeArgs.Merge(m, src)
+}
+func (m *UninstallChaincodeArgs) XXX_Size() int {
+	return xxx_messageInfo_UninstallChaincodeArgs.Size(m)
+}
+func (m *UninstallChaincodeArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_UninstallChaincodeArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UninstallChaincodeArgs proto.InternalMessageInfo
+
+func (m *UninstallChaincodeArgs) GetPackageId() string {
+	if m != nil {
+		return m.PackageId
+	}
+	return ""
+}
+
+// UninstallChaincodeResult is the message returned by
+// '_lifecycle.UninstallChaincode'.
+type UninstallChaincodeResult struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UninstallChaincodeResult) Reset()         { *m = UninstallChaincodeResult{} }
+func (m *UninstallChaincodeResult) String() string { return proto.CompactTextString(m) }
+func (*UninstallChaincodeResult) ProtoMessage()    {}
+func (*UninstallChaincodeResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84f7c7eee8484930, []int{1}
+}
+
+func (m *UninstallChaincodeResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UninstallChaincodeResult.Unmarshal(m, b)
+}
+func (m *UninstallChaincodeResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UninstallChaincodeResult.Marshal(b, m, deterministic)
+}
+func (m *UninstallChaincodeResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UninstallChaincodeResult.Merge(m, src)
+}
+func (m *UninstallChaincodeResult) XXX_Size() int {
+	return xxx_messageInfo_UninstallChaincodeResult.Size(m)
+}
+func (m *UninstallChaincodeResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_UninstallChaincodeResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UninstallChaincodeResult proto.InternalMessageInfo
+
+// QueryPendingApprovalsArgs is the message used as the argument to
+// '_lifecycle.QueryPendingApprovals'.
+type QueryPendingApprovalsArgs struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryPendingApprovalsArgs) Reset()         { *m = QueryPendingApprovalsArgs{} }
+func (m *QueryPendingApprovalsArgs) String() string { return proto.CompactTextString(m) }
+func (*QueryPendingApprovalsArgs) ProtoMessage()    {}
+func (*QueryPendingApprovalsArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84f7c7eee8484930, []int{2}
+}
+
+func (m *QueryPendingApprovalsArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryPendingApprovalsArgs.Unmarshal(m, b)
+}
+func (m *QueryPendingApprovalsArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryPendingApprovalsArgs.Marshal(b, m, deterministic)
+}
+func (m *QueryPendingApprovalsArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryPendingApprovalsArgs.Merge(m, src)
+}
+func (m *QueryPendingApprovalsArgs) XXX_Size() int {
+	return xxx_messageInfo_QueryPendingApprovalsArgs.Size(m)
+}
+func (m *QueryPendingApprovalsArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryPendingApprovalsArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryPendingApprovalsArgs proto.InternalMessageInfo
+
+// QueryPendingApprovalsResult is the message returned by
+// '_lifecycle.QueryPendingApprovals'. It lists the chaincode definitions
+// approved by the peer's org which have not been committed yet, along with
+// the orgs which approved the same definition.
+type QueryPendingApprovalsResult struct {
+	PendingApprovals     []*QueryPendingApprovalsResult_PendingApproval `protobuf:"bytes,1,rep,name=pending_approvals,json=pendingApprovals,proto3" json:"pending_approvals,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                                       `json:"-"`
+	XXX_unrecognized     []byte                                         `json:"-"`
+	XXX_sizecache        int32                                          `json:"-"`
+}
+
+func (m *QueryPendingApprovalsResult) Reset()         { *m = QueryPendingApprovalsResult{} }
+func (m *QueryPendingApprovalsResult) String() string { return proto.CompactTextString(m) }
+func (*QueryPendingApprovalsResult) ProtoMessage()    {}
+func (*QueryPendingApprovalsResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84f7c7eee8484930, []int{3}
+}
+
+func (m *QueryPendingApprovalsResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryPendingApprovalsResult.Unmarshal(m, b)
+}
+func (m *QueryPendingApprovalsResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryPendingApprovalsResult.Marshal(b, m, deterministic)
+}
+func (m *QueryPendingApprovalsResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryPendingApprovalsResult.Merge(m, src)
+}
+func (m *QueryPendingApprovalsResult) XXX_Size() int {
+	return xxx_messageInfo_QueryPendingApprovalsResult.Size(m)
+}
+func (m *QueryPendingApprovalsResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryPendingApprovalsResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryPendingApprovalsResult proto.InternalMessageInfo
+
+func (m *QueryPendingApprovalsResult) GetPendingApprovals() []*QueryPendingApprovalsResult_PendingApproval {
+	if m != nil {
+		return m.PendingApprovals
+	}
+	return nil
+}
+
+type QueryPendingApprovalsResult_PendingApproval struct {
+	Name                 string                        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Sequence             int64                         `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Version              string                        `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	EndorsementPlugin    string                        `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin,proto3" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin     string                        `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin,proto3" json:"validation_plugin,omitempty"`
+	ValidationParameter  []byte                        `protobuf:"bytes,6,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections          *peer.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections,proto3" json:"collections,omitempty"`
+	InitRequired         bool                          `protobuf:"varint,8,opt,name=init_required,json=initRequired,proto3" json:"init_required,omitempty"`
+	Approvals            map[string]bool               `protobuf:"bytes,9,rep,name=approvals,proto3" json:"approvals,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Committable          bool                          `protobuf:"varint,10,opt,name=committable,proto3" json:"committable,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
+}
+
+func (m *QueryPendingApprovalsResult_PendingApproval) Reset() {
+	*m = QueryPendingApprovalsResult_PendingApproval{}
+}
+func (m *QueryPendingApprovalsResult_PendingApproval) String() string {
+	return proto.CompactTextString(m)
+}
+func (*QueryPendingApprovalsResult_PendingApproval) ProtoMessage() {}
+func (*QueryPendingApprovalsResult_PendingApproval) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84f7c7eee8484930, []int{3, 0}
+}
+
+func (m *QueryPendingApprovalsResult_PendingApproval) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryPendingApprovalsResult_PendingApproval.Unmarshal(m, b)
+}
+func (m *QueryPendingApprovalsResult_PendingApproval) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryPendingApprovalsResult_PendingApproval.Marshal(b, m, deterministic)
+}
+func (m *QueryPendingApprovalsResult_PendingApproval) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryPendingApprovalsResult_PendingApproval.Merge(m, src)
+}
+func (m *QueryPendingApprovalsResult_PendingApproval) XXX_Size() int {
+	return xxx_messageInfo_QueryPendingApprovalsResult_PendingApproval.Size(m)
+}
+func (m *QueryPendingApprovalsResult_PendingApproval) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryPendingApprovalsResult_PendingApproval.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryPendingApprovalsResult_PendingApproval proto.InternalMessageInfo
+
+func (m *QueryPendingApprovalsResult_PendingApproval) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *QueryPendingApprovalsResult_PendingApproval) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *QueryPendingApprovalsResult_PendingApproval) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *QueryPendingApprovalsResult_PendingApproval) GetEndorsementPlugin() string {
+	if m != nil {
+		return m.EndorsementPlugin
+	}
+	return ""
+}
+
+func (m *QueryPendingApprovalsResult_PendingApproval) GetValidationPlugin() string {
+	if m != nil {
+		return m.ValidationPlugin
+	}
+	return ""
+}
+
+func (m *QueryPendingApprovalsResult_PendingApproval) GetValidationParameter() []byte {
+	if m != nil {
+		return m.ValidationParameter
+	}
+	return nil
+}
+
+func (m *QueryPendingApprovalsResult_PendingApproval) GetCollections() *peer.CollectionConfigPackage {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+func (m *QueryPendingApprovalsResult_PendingApproval) GetInitRequired() bool {
+	if m != nil {
+		return m.InitRequired
+	}
+	return false
+}
+
+func (m *QueryPendingApprovalsResult_PendingApproval) GetApprovals() map[string]bool {
+	if m != nil {
+		return m.Approvals
+	}
+	return nil
+}
+
+func (m *QueryPendingApprovalsResult_PendingApproval) GetCommittable() bool {
+	if m != nil {
+		return m.Committable
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*UninstallChaincodeArgs)(nil), "msgs.UninstallChaincodeArgs")
+	proto.RegisterType((*UninstallChaincodeResult)(nil), "msgs.UninstallChaincodeResult")
+	proto.RegisterType((*QueryPendingApprovalsArgs)(nil), "msgs.QueryPendingApprovalsArgs")
+	proto.RegisterType((*QueryPendingApprovalsResult)(nil), "msgs.QueryPendingApprovalsResult")
+	proto.RegisterType((*QueryPendingApprovalsResult_PendingApproval)(nil), "msgs.QueryPendingApprovalsResult.PendingApproval")
+	proto.RegisterMapType((map[string]bool)(nil), "msgs.QueryPendingApprovalsResult.PendingApproval.ApprovalsEntry")
+}
+
+func init() { proto.RegisterFile("lifecycle.proto", fileDescriptor_84f7c7eee8484930) }
+
+var fileDescriptor_84f7c7eee8484930 = []byte{
+	// 480 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0x4f, 0x6f, 0xd3, 0x30,
+	0x18, 0xc6, 0x95, 0xb5, 0xdd, 0xda, 0xb7, 0x83, 0xb5, 0x66, 0x20, 0xd3, 0x09, 0x11, 0x95, 0x4b,
+	0x24, 0x44, 0xa2, 0x75, 0x07, 0xa6, 0x69, 0x07, 0x4a, 0xc5, 0x81, 0x5b, 0x89, 0xc4, 0x85, 0xc3,
+	0x2a, 0xd7, 0x79, 0x9b, 0x59, 0x73, 0xec, 0xcc, 0x76, 0x2a, 0xf5, 0x6b, 0xec, 0x13, 0xa3, 0x24,
+	0xfd, 0x47, 0x41, 0x48, 0xbb, 0xf9, 0x7d, 0x7e, 0xcf, 0x63, 0xe5, 0xcd, 0x93, 0xc0, 0x99, 0x14,
+	0x0b, 0xe4, 0x2b, 0x2e, 0x31, 0xcc, 0x8d, 0x76, 0x9a, 0x34, 0x33, 0x9b, 0xda, 0xc1, 0xeb, 0x1c,
+	0xd1, 0x44, 0x5c, 0x4b, 0x89, 0xdc, 0x09, 0xad, 0x6a, 0x38, 0xfc, 0x0c, 0x6f, 0x7e, 0x2a, 0xa1,
+	0xac, 0x63, 0x52, 0x4e, 0xee, 0x99, 0x50, 0x5c, 0x27, 0x38, 0x36, 0xa9, 0x25, 0xef, 0x00, 0x72,
+	0xc6, 0x1f, 0x58, 0x8a, 0x33, 0x91, 0x50, 0xcf, 0xf7, 0x82, 0x4e, 0xdc, 0x59, 0x2b, 0xdf, 0x93,
+	0xe1, 0x00, 0xe8, 0xdf, 0xc1, 0x18, 0x6d, 0x21, 0xdd, 0xf0, 0x02, 0xde, 0xfe, 0x28, 0xd0, 0xac,
+	0xa6, 0xa8, 0x12, 0xa1, 0xd2, 0x71, 0x9e, 0x1b, 0xbd, 0x64, 0xd2, 0x96, 0xf7, 0x0e, 0x9f, 0x5a,
+	0x70, 0xf1, 0x4f, 0x5a, 0x87, 0xc9, 0x1d, 0xf4, 0xf3, 0x9a, 0xcc, 0xd8, 0x06, 0x51, 0xcf, 0x6f,
+	0x04, 0xdd, 0xd1, 0x65, 0x58, 0xae, 0x12, 0xfe, 0x27, 0x1d, 0x1e, 0xc8, 0x71, 0x2f, 0x3f, 0xf0,
+	0x0d, 0x9e, 0x9a, 0x70, 0x76, 0xe0, 0x22, 0x04, 0x9a, 0x8a, 0x65, 0xb8, 0xde, 0xb2, 0x3a, 0x93,
+	0x01, 0xb4, 0x2d, 0x3e, 0x16, 0xa8, 0x38, 0xd2, 0x23, 0xdf, 0x0b, 0x1a, 0xf1, 0x76, 0x26, 0x14,
+	0x4e, 0x96, 0x68, 0xac, 0xd0, 0x8a, 0x36, 0xaa, 0xc8, 0x66, 0x24, 0x9f, 0x80, 0xa0, 0x4a, 0xb4,
+	0xb1, 0x98, 0xa1, 0x72, 0xb3, 0x5c, 0x16, 0xa9, 0x50, 0xb4, 0x59, 0x99, 0xfa, 0x7b, 0x64, 0x5a,
+	0x01, 0xf2, 0x11, 0xfa, 0x4b, 0x26, 0x45, 0xc2, 0xca, 0x4a, 0x36, 0xee, 0x56, 0xe5, 0xee, 0xed,
+	0xc0, 0xda, 0x7c, 0x09, 0xe7, 0xfb, 0x66, 0x66, 0x58, 0x86, 0x0e, 0x0d, 0x3d, 0xf6, 0xbd, 0xe0,
+	0x34, 0x7e, 0xb5, 0xe7, 0xdf, 0x20, 0x32, 0x86, 0xee, 0xae, 0x72, 0x4b, 0x4f, 0x7c, 0x2f, 0xe8,
+	0x8e, 0xde, 0xd7, 0xdd, 0xdb, 0x70, 0xb2, 0x45, 0x13, 0xad, 0x16, 0x22, 0x9d, 0xd6, 0xed, 0xc6,
+	0xfb, 0x19, 0xf2, 0x01, 0x5e, 0x08, 0x25, 0xdc, 0xcc, 0xe0, 0x63, 0x21, 0x0c, 0x26, 0xb4, 0xed,
+	0x7b, 0x41, 0x3b, 0x3e, 0x2d, 0xc5, 0x78, 0xad, 0x91, 0x3b, 0xe8, 0xec, 0xca, 0xea, 0x54, 0x65,
+	0x7d, 0x79, 0x76, 0x59, 0xe1, 0x96, 0x7f, 0x53, 0xce, 0xac, 0xe2, 0xdd, 0x95, 0xc4, 0x2f, 0xf7,
+	0xc8, 0x32, 0xe1, 0x1c, 0x9b, 0x4b, 0xa4, 0x50, 0x3d, 0xc2, 0xbe, 0x34, 0xb8, 0x85, 0x97, 0x7f,
+	0xc6, 0x49, 0x0f, 0x1a, 0x0f, 0xb8, 0x5a, 0x77, 0x5a, 0x1e, 0xc9, 0x39, 0xb4, 0x96, 0x4c, 0x16,
+	0x75, 0x9f, 0xed, 0xb8, 0x1e, 0x6e, 0x8e, 0xae, 0xbd, 0xaf, 0xb7, 0xbf, 0x6e, 0x52, 0xe1, 0xee,
+	0x8b, 0x79, 0xc8, 0x75, 0x16, 0x69, 0x9b, 0x88, 0xd1, 0x55, 0x3e, 0x1a, 0x5d, 0x47, 0x0b, 0x36,
+	0x37, 0x82, 0x47, 0x5c, 0x1b, 0x8c, 0xf8, 0xe6, 0x33, 0x8f, 0xb6, 0xbf, 0x58, 0x54, 0xee, 0x38,
+	0x3f, 0xae, 0xde, 0xe7, 0xd5, 0xef, 0x01, 0x00, 0x38, 0xe1, 0xdf, 0x46, 0x7b, 0x03, 0x00, 0x00,
+}
@@ -9,6 +9,7 @@ package lifecycle
 import (
 	"fmt"
 	"regexp"
+	"sort"
 
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -19,6 +20,7 @@ import (
 	"github.com/osdi23p228/fabric/common/chaincode"
 	"github.com/osdi23p228/fabric/common/channelconfig"
 	"github.com/osdi23p228/fabric/core/aclmgmt"
+	"github.com/osdi23p228/fabric/core/chaincode/lifecycle/msgs"
 	"github.com/osdi23p228/fabric/core/chaincode/persistence"
 	"github.com/osdi23p228/fabric/core/dispatcher"
 	"github.com/osdi23p228/fabric/core/ledger"
@@ -38,6 +40,10 @@ const (
 	// a chaincode
 	InstallChaincodeFuncName = "InstallChaincode"
 
+	// UninstallChaincodeFuncName is the chaincode function name used to
+	// uninstall a chaincode
+	UninstallChaincodeFuncName = "UninstallChaincode"
+
 	// QueryInstalledChaincodeFuncName is the chaincode function name used to
 	// query an installed chaincode
 	QueryInstalledChaincodeFuncName = "QueryInstalledChaincode"
@@ -70,6 +76,11 @@ const (
 	// QueryChaincodeDefinitionsFuncName is the chaincode function name used to
 	// query the committed chaincode definitions in a channel.
 	QueryChaincodeDefinitionsFuncName = "QueryChaincodeDefinitions"
+
+	// QueryPendingApprovalsFuncName is the chaincode function name used to
+	// query the chaincode definitions approved by the user's own org which
+	// have not been committed in a channel.
+	QueryPendingApprovalsFuncName = "QueryPendingApprovals"
 )
 
 // SCCFunctions provides a backing implementation with concrete arguments
@@ -78,6 +89,10 @@ type SCCFunctions interface {
 	// InstallChaincode persists a chaincode definition to disk
 	InstallChaincode([]byte) (*chaincode.InstalledChaincode, error)
 
+	// UninstallChaincode removes the chaincode package with the supplied
+	// package ID from disk and stops any running instance of it.
+	UninstallChaincode(packageID string) error
+
 	// QueryInstalledChaincode returns metadata for the chaincode with the supplied package ID.
 	QueryInstalledChaincode(packageID string) (*chaincode.InstalledChaincode, error)
 
@@ -115,6 +130,11 @@ type SCCFunctions interface {
 
 	// QueryNamespaceDefinitions returns all defined namespaces
 	QueryNamespaceDefinitions(publicState RangeableState) (map[string]string, error)
+
+	// QueryPendingApprovals returns the definitions approved by the orgs
+	// whose orgStates were supplied which have not been committed, and
+	// whether or not each of these orgs has approved them.
+	QueryPendingApprovals(chname string, publicState RangeableReadableState, orgState RangeableReadableState, orgStates []OpaqueState) ([]*PendingApproval, error)
 }
 
 //go:generate counterfeiter -o mock/channel_config_source.go --fake-name ChannelConfigSource . ChannelConfigSource
@@ -273,6 +293,20 @@ func (i *Invocation) InstallChaincode(input *lb.InstallChaincodeArgs) (proto.Mes
 	}, nil
 }
 
+// UninstallChaincode is a SCC function that may be dispatched to which routes
+// to the underlying lifecycle implementation.
+func (i *Invocation) UninstallChaincode(input *msgs.UninstallChaincodeArgs) (proto.Message, error) {
+	logger.Debugf("received invocation of UninstallChaincode for install package ID '%s'",
+		input.PackageId,
+	)
+
+	if err := i.SCC.Functions.UninstallChaincode(input.PackageId); err != nil {
+		return nil, err
+	}
+
+	return &msgs.UninstallChaincodeResult{}, nil
+}
+
 // QueryInstalledChaincode is a SCC function that may be dispatched to which
 // routes to the underlying lifecycle implementation.
 func (i *Invocation) QueryInstalledChaincode(input *lb.QueryInstalledChaincodeArgs) (proto.Message, error) {
@@ -634,6 +668,53 @@ func (i *Invocation) QueryChaincodeDefinitions(input *lb.QueryChaincodeDefinitio
 	}, nil
 }
 
+// QueryPendingApprovals is a SCC function that may be dispatched
+// to which routes to the underlying lifecycle implementation.
+func (i *Invocation) QueryPendingApprovals(input *msgs.QueryPendingApprovalsArgs) (proto.Message, error) {
+	logger.Debugf("received invocation of QueryPendingApprovals on channel '%s'",
+		i.Stub.GetChannelID(),
+	)
+
+	opaqueStates, err := i.createOpaqueStates()
+	if err != nil {
+		return nil, err
+	}
+
+	pendingApprovals, err := i.SCC.Functions.QueryPendingApprovals(
+		i.Stub.GetChannelID(),
+		&ChaincodePublicLedgerShim{ChaincodeStubInterface: i.Stub},
+		&ChaincodePrivateLedgerShim{
+			Collection: ImplicitCollectionNameForOrg(i.SCC.OrgMSPID),
+			Stub:       i.Stub,
+		},
+		opaqueStates,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &msgs.QueryPendingApprovalsResult{}
+	for _, approval := range pendingApprovals {
+		result.PendingApprovals = append(result.PendingApprovals, &msgs.QueryPendingApprovalsResult_PendingApproval{
+			Name:                approval.Name,
+			Sequence:            approval.Definition.Sequence,
+			Version:             approval.Definition.EndorsementInfo.GetVersion(),
+			EndorsementPlugin:   approval.Definition.EndorsementInfo.GetEndorsementPlugin(),
+			ValidationPlugin:    approval.Definition.ValidationInfo.GetValidationPlugin(),
+			ValidationParameter: approval.Definition.ValidationInfo.GetValidationParameter(),
+			InitRequired:        approval.Definition.EndorsementInfo.GetInitRequired(),
+			Collections:         approval.Definition.Collections,
+			Approvals:           approval.Approvals,
+			Committable:         approval.Committable,
+		})
+	}
+	sort.Slice(result.PendingApprovals, func(i, j int) bool {
+		return result.PendingApprovals[i].Name < result.PendingApprovals[j].Name
+	})
+
+	return result, nil
+}
+
 var (
 	// NOTE the chaincode name/version regular expressions should stay in sync
 	// with those defined in core/scc/lscc/lscc.go until LSCC has been removed.
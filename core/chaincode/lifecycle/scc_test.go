@@ -23,6 +23,7 @@ import (
 	"github.com/osdi23p228/fabric/common/policydsl"
 	"github.com/osdi23p228/fabric/core/chaincode/lifecycle"
 	"github.com/osdi23p228/fabric/core/chaincode/lifecycle/mock"
+	"github.com/osdi23p228/fabric/core/chaincode/lifecycle/msgs"
 	"github.com/osdi23p228/fabric/core/chaincode/persistence"
 	"github.com/osdi23p228/fabric/core/dispatcher"
 	"github.com/osdi23p228/fabric/msp"
@@ -325,6 +326,49 @@ var _ = Describe("SCC", func() {
 			})
 		})
 
+		Describe("UninstallChaincode", func() {
+			var (
+				arg          *msgs.UninstallChaincodeArgs
+				marshaledArg []byte
+			)
+
+			BeforeEach(func() {
+				arg = &msgs.UninstallChaincodeArgs{
+					PackageId: "package-id",
+				}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeStub.GetArgsReturns([][]byte{[]byte("UninstallChaincode"), marshaledArg})
+			})
+
+			It("passes the arguments to and returns the results from the backing scc function implementation", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &msgs.UninstallChaincodeResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSCCFuncs.UninstallChaincodeCallCount()).To(Equal(1))
+				packageID := fakeSCCFuncs.UninstallChaincodeArgsForCall(0)
+				Expect(packageID).To(Equal("package-id"))
+			})
+
+			Context("when the underlying function implementation fails", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.UninstallChaincodeReturns(fmt.Errorf("underlying-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing implementation of 'UninstallChaincode': underlying-error"))
+				})
+			})
+		})
+
 		Describe("QueryInstalledChaincodes", func() {
 			var (
 				arg          *lb.QueryInstalledChaincodesArgs
@@ -1650,6 +1694,180 @@ var _ = Describe("SCC", func() {
 				})
 			})
 		})
+
+		Describe("QueryPendingApprovals", func() {
+			var (
+				arg            *msgs.QueryPendingApprovalsArgs
+				marshaledArg   []byte
+				fakeOrgConfigs []*mock.ApplicationOrgConfig
+			)
+
+			BeforeEach(func() {
+				arg = &msgs.QueryPendingApprovalsArgs{}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeStub.GetArgsReturns([][]byte{[]byte("QueryPendingApprovals"), marshaledArg})
+
+				fakeOrgConfigs = []*mock.ApplicationOrgConfig{{}, {}}
+				fakeOrgConfigs[0].MSPIDReturns("fake-mspid")
+				fakeOrgConfigs[1].MSPIDReturns("other-mspid")
+
+				fakeApplicationConfig.OrganizationsReturns(map[string]channelconfig.ApplicationOrg{
+					"org0": fakeOrgConfigs[0],
+					"org1": fakeOrgConfigs[1],
+				})
+
+				fakeSCCFuncs.QueryPendingApprovalsReturns([]*lifecycle.PendingApproval{
+					{
+						Name: "woo",
+						Definition: &lifecycle.ChaincodeDefinition{
+							Sequence: 5,
+							EndorsementInfo: &lb.ChaincodeEndorsementInfo{
+								Version:           "version",
+								EndorsementPlugin: "endorsement-plugin",
+							},
+							ValidationInfo: &lb.ChaincodeValidationInfo{
+								ValidationPlugin:    "validation-plugin",
+								ValidationParameter: []byte("validation-parameter"),
+							},
+							Collections: &pb.CollectionConfigPackage{},
+						},
+						Approvals: map[string]bool{
+							"fake-mspid":  true,
+							"other-mspid": false,
+						},
+					},
+					{
+						Name: "foo",
+						Definition: &lifecycle.ChaincodeDefinition{
+							Sequence: 2,
+							EndorsementInfo: &lb.ChaincodeEndorsementInfo{
+								Version:      "version",
+								InitRequired: true,
+							},
+							ValidationInfo: &lb.ChaincodeValidationInfo{},
+						},
+						Approvals: map[string]bool{
+							"fake-mspid":  true,
+							"other-mspid": true,
+						},
+						Committable: true,
+					},
+				}, nil)
+			})
+
+			It("passes the arguments to and returns the results from the backing scc function implementation", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Message).To(Equal(""))
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &msgs.QueryPendingApprovalsResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proto.Equal(payload, &msgs.QueryPendingApprovalsResult{
+					PendingApprovals: []*msgs.QueryPendingApprovalsResult_PendingApproval{
+						{
+							Name:         "foo",
+							Sequence:     2,
+							Version:      "version",
+							InitRequired: true,
+							Approvals: map[string]bool{
+								"fake-mspid":  true,
+								"other-mspid": true,
+							},
+							Committable: true,
+						},
+						{
+							Name:                "woo",
+							Sequence:            5,
+							Version:             "version",
+							EndorsementPlugin:   "endorsement-plugin",
+							ValidationPlugin:    "validation-plugin",
+							ValidationParameter: []byte("validation-parameter"),
+							Collections:         &pb.CollectionConfigPackage{},
+							Approvals: map[string]bool{
+								"fake-mspid":  true,
+								"other-mspid": false,
+							},
+						},
+					},
+				})).To(BeTrue())
+
+				Expect(fakeSCCFuncs.QueryPendingApprovalsCallCount()).To(Equal(1))
+				chname, pubState, orgState, orgStates := fakeSCCFuncs.QueryPendingApprovalsArgsForCall(0)
+				Expect(chname).To(Equal("test-channel"))
+				Expect(pubState).To(Equal(&lifecycle.ChaincodePublicLedgerShim{ChaincodeStubInterface: fakeStub}))
+				Expect(orgState).To(BeAssignableToTypeOf(&lifecycle.ChaincodePrivateLedgerShim{}))
+				Expect(orgState.(*lifecycle.ChaincodePrivateLedgerShim).Collection).To(Equal("_implicit_org_fake-mspid"))
+				Expect(orgStates).To(HaveLen(2))
+				collection0 := orgStates[0].(*lifecycle.ChaincodePrivateLedgerShim).Collection
+				collection1 := orgStates[1].(*lifecycle.ChaincodePrivateLedgerShim).Collection
+				Expect([]string{collection0, collection1}).To(ConsistOf("_implicit_org_fake-mspid", "_implicit_org_other-mspid"))
+			})
+
+			Context("when a definition was only approved by other orgs", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.QueryPendingApprovalsReturns([]*lifecycle.PendingApproval{
+						{
+							Name:          "woo",
+							Definition:    &lifecycle.ChaincodeDefinition{Sequence: 5},
+							OtherOrgsOnly: true,
+							Approvals: map[string]bool{
+								"fake-mspid":  false,
+								"other-mspid": true,
+							},
+						},
+					}, nil)
+				})
+
+				It("returns the definition with its sequence only", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Message).To(Equal(""))
+					Expect(res.Status).To(Equal(int32(200)))
+					payload := &msgs.QueryPendingApprovalsResult{}
+					err := proto.Unmarshal(res.Payload, payload)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(proto.Equal(payload, &msgs.QueryPendingApprovalsResult{
+						PendingApprovals: []*msgs.QueryPendingApprovalsResult_PendingApproval{
+							{
+								Name:     "woo",
+								Sequence: 5,
+								Approvals: map[string]bool{
+									"fake-mspid":  false,
+									"other-mspid": true,
+								},
+							},
+						},
+					})).To(BeTrue())
+				})
+			})
+
+			Context("when there is no application config", func() {
+				BeforeEach(func() {
+					fakeChannelConfig.ApplicationConfigReturns(nil, false)
+				})
+
+				It("returns an error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("could not get application config for channel 'test-channel'"))
+				})
+			})
+
+			Context("when the underlying function implementation fails", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.QueryPendingApprovalsReturns(nil, fmt.Errorf("underlying-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing implementation of 'QueryPendingApprovals': underlying-error"))
+				})
+			})
+		})
 	})
 })
 
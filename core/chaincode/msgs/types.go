@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgs
+
+import (
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+)
+
+// TypeExtendedRequest is the type of the chaincode shim protocol messages
+// whose payload is an ExtendedRequest. The requests which batch state accesses
+// or purge private data aren't part of the ChaincodeMessage_Type enumeration of the peer protos, so
+// they are carried in messages of the otherwise unused UNDEFINED type, which
+// peers that don't support them reject. They are only accepted on channels
+// with the V2_5 application capability.
+const TypeExtendedRequest = pb.ChaincodeMessage_UNDEFINED
+
+// NewExtendedRequestMessage returns a message of the given channel and
+// transaction carrying a request of the given type and payload.
+func NewExtendedRequestMessage(requestType ExtendedRequest_Type, payload []byte, channelID, txID string) (*pb.ChaincodeMessage, error) {
+	request, err := proto.Marshal(&ExtendedRequest{Type: requestType, Payload: payload})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal failed")
+	}
+	return &pb.ChaincodeMessage{Type: TypeExtendedRequest, Payload: request, ChannelId: channelID, Txid: txID}, nil
+}
+
+// UnwrapExtendedRequest returns the type of the request carried by a message
+// of type TypeExtendedRequest, and a copy of the message whose payload is the
+// payload of the request.
+func UnwrapExtendedRequest(msg *pb.ChaincodeMessage) (ExtendedRequest_Type, *pb.ChaincodeMessage, error) {
+	request := &ExtendedRequest{}
+	if err := proto.Unmarshal(msg.Payload, request); err != nil {
+		return ExtendedRequest_UNDEFINED, nil, errors.Wrap(err, "unmarshal failed")
+	}
+	unwrapped := proto.Clone(msg).(*pb.ChaincodeMessage)
+	unwrapped.Payload = request.Payload
+	return request.Type, unwrapped, nil
+}
+
+// RequestType returns the name of the type of a message, which for the
+// messages of type TypeExtendedRequest is the type of the request they carry.
+func RequestType(msg *pb.ChaincodeMessage) string {
+	if msg.Type != TypeExtendedRequest {
+		return msg.Type.String()
+	}
+	request := &ExtendedRequest{}
+	if err := proto.Unmarshal(msg.Payload, request); err != nil {
+		return msg.Type.String()
+	}
+	if _, ok := ExtendedRequest_Type_name[int32(request.Type)]; !ok {
+		return msg.Type.String()
+	}
+	return request.Type.String()
+}
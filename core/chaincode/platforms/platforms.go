@@ -107,6 +107,22 @@ func (r *Registry) GenerateDockerfile(ccType string) (string, error) {
 	return contents, nil
 }
 
+// DockerBuildOptions returns the options of the platform of type ccType for
+// building the chaincode at path.
+func (r *Registry) DockerBuildOptions(ccType, path string) (util.DockerBuildOptions, error) {
+	platform, ok := r.Platforms[ccType]
+	if !ok {
+		return util.DockerBuildOptions{}, fmt.Errorf("could not find platform of type: %s", ccType)
+	}
+
+	buildOptions, err := platform.DockerBuildOptions(path)
+	if err != nil {
+		return util.DockerBuildOptions{}, errors.Wrap(err, "platform failed to create docker build options")
+	}
+
+	return buildOptions, nil
+}
+
 func (r *Registry) StreamDockerBuild(ccType, path string, codePackage io.Reader, inputFiles map[string][]byte, tw *tar.Writer, client *docker.Client) error {
 	var err error
 
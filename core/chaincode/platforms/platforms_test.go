@@ -66,6 +66,31 @@ ENV CORE_CHAINCODE_BUILDLEVEL=%s`, metadata.Version, metadata.Version)
 		})
 	})
 
+	Describe("DockerBuildOptions", func() {
+		It("returns the build options of the underlying platform", func() {
+			fakePlatform.DockerBuildOptionsReturns(util.DockerBuildOptions{Image: "builder", Cmd: "make"}, nil)
+			opts, err := registry.DockerBuildOptions("fakeType", "fake-path")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(opts).To(Equal(util.DockerBuildOptions{Image: "builder", Cmd: "make"}))
+			Expect(fakePlatform.DockerBuildOptionsArgsForCall(0)).To(Equal("fake-path"))
+		})
+
+		Context("when the underlying platform returns an error", func() {
+			It("returns the error", func() {
+				fakePlatform.DockerBuildOptionsReturns(util.DockerBuildOptions{}, errors.New("fake-error"))
+				_, err := registry.DockerBuildOptions("fakeType", "fake-path")
+				Expect(err).To(MatchError("platform failed to create docker build options: fake-error"))
+			})
+		})
+
+		Context("when the platform is unknown", func() {
+			It("returns an error", func() {
+				_, err := registry.DockerBuildOptions("badType", "fake-path")
+				Expect(err).To(MatchError("could not find platform of type: badType"))
+			})
+		})
+	})
+
 	Describe("the pieces which deal with packaging", func() {
 		var (
 			buf    *bytes.Buffer
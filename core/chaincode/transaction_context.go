@@ -11,6 +11,7 @@ import (
 
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	commonledger "github.com/osdi23p228/fabric/common/ledger"
+	"github.com/osdi23p228/fabric/core/common/ccprovider"
 	"github.com/osdi23p228/fabric/core/common/privdata"
 	"github.com/osdi23p228/fabric/core/ledger"
 )
@@ -25,6 +26,7 @@ type TransactionContext struct {
 	HistoryQueryExecutor ledger.HistoryQueryExecutor
 	CollectionStore      privdata.CollectionStore
 	IsInitTransaction    bool
+	UsageRecorder        *ccprovider.UsageRecorder
 
 	// tracks open iterators used for range queries
 	queryMutex          sync.Mutex
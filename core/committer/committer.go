@@ -49,6 +49,10 @@ type Committer interface {
 	// about a given block number.
 	DoesPvtDataInfoExistInLedger(blockNum uint64) (bool, error)
 
+	// TxIDExists returns true if a transaction with the given ID was committed
+	// to the ledger, whether it was valid or not.
+	TxIDExists(txID string) (bool, error)
+
 	// Gets blocks with sequence numbers provided in the slice
 	GetBlocks(blockSeqs []uint64) []*common.Block
 
@@ -8,6 +8,7 @@ package committer
 
 import (
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/osdi23p228/fabric/common/flogging"
 	"github.com/osdi23p228/fabric/core/ledger"
 )
@@ -34,6 +35,8 @@ type PeerLedgerSupport interface {
 
 	DoesPvtDataInfoExist(blockNum uint64) (bool, error)
 
+	GetTransactionByID(txID string) (*peer.ProcessedTransaction, error)
+
 	GetBlockByNumber(blockNumber uint64) (*common.Block, error)
 
 	GetConfigHistoryRetriever() (ledger.ConfigHistoryRetriever, error)
@@ -48,6 +51,8 @@ type PeerLedgerSupport interface {
 // chain information
 type LedgerCommitter struct {
 	PeerLedgerSupport
+	channelID string
+	notifier  *CommitNotifier
 }
 
 // NewLedgerCommitter is a factory function to create an instance of the committer
@@ -56,6 +61,17 @@ func NewLedgerCommitter(ledger PeerLedgerSupport) *LedgerCommitter {
 	return &LedgerCommitter{PeerLedgerSupport: ledger}
 }
 
+// NewNotifyingLedgerCommitter creates a committer which, in addition, notifies
+// the waiters registered with the notifier of the transactions of each block
+// committed into the ledger of the given channel. The notifier may be nil.
+func NewNotifyingLedgerCommitter(ledger PeerLedgerSupport, channelID string, notifier *CommitNotifier) *LedgerCommitter {
+	return &LedgerCommitter{
+		PeerLedgerSupport: ledger,
+		channelID:         channelID,
+		notifier:          notifier,
+	}
+}
+
 // CommitLegacy commits blocks atomically with private data
 func (lc *LedgerCommitter) CommitLegacy(blockAndPvtData *ledger.BlockAndPvtData, commitOpts *ledger.CommitOptions) error {
 	// Committing new block
@@ -63,6 +79,10 @@ func (lc *LedgerCommitter) CommitLegacy(blockAndPvtData *ledger.BlockAndPvtData,
 		return err
 	}
 
+	if lc.notifier != nil {
+		lc.notifier.notify(lc.channelID, blockAndPvtData.Block)
+	}
+
 	return nil
 }
 
@@ -88,6 +108,20 @@ func (lc *LedgerCommitter) DoesPvtDataInfoExistInLedger(blockNum uint64) (bool,
 	return lc.DoesPvtDataInfoExist(blockNum)
 }
 
+// TxIDExists returns true if a transaction with the given ID was committed to
+// the ledger, whether it was valid or not.
+func (lc *LedgerCommitter) TxIDExists(txID string) (bool, error) {
+	_, err := lc.GetTransactionByID(txID)
+	switch err.(type) {
+	case nil:
+		return true, nil
+	case ledger.NotFoundInIndexErr:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
 // GetBlocks used to retrieve blocks with sequence numbers provided in the slice
 func (lc *LedgerCommitter) GetBlocks(blockSeqs []uint64) []*common.Block {
 	var blocks []*common.Block
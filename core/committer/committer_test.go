@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package committer
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -160,3 +161,22 @@ func TestKVLedgerBlockStorage(t *testing.T) {
 	assert.Equal(t, 1, len(blocks))
 	assert.NoError(t, err)
 }
+
+func TestTxIDExists(t *testing.T) {
+	ledger := &mockLedger{}
+	ledger.On("GetTransactionByID", "committed").Return(&peer.ProcessedTransaction{}, nil)
+	ledger.On("GetTransactionByID", "pending").Return((*peer.ProcessedTransaction)(nil), ledger2.NotFoundInIndexErr("pending"))
+	ledger.On("GetTransactionByID", "failing").Return((*peer.ProcessedTransaction)(nil), errors.New("index unavailable"))
+	committer := NewLedgerCommitter(ledger)
+
+	exists, err := committer.TxIDExists("committed")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = committer.TxIDExists("pending")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = committer.TxIDExists("failing")
+	assert.EqualError(t, err, "index unavailable")
+}
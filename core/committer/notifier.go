@@ -0,0 +1,173 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package committer
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/internal/pkg/txflags"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// TxStatus is the outcome of the validation of a committed transaction.
+type TxStatus struct {
+	TxID           string
+	ValidationCode peer.TxValidationCode
+	BlockNumber    uint64
+}
+
+type txKey struct {
+	channelID string
+	txID      string
+}
+
+// BlockListener is notified of the blocks committed into the ledgers of all
+// channels.
+type BlockListener interface {
+	BlockCommitted(channelID string, block *common.Block)
+}
+
+// CommitNotifier notifies the parties waiting for transactions to commit when the
+// blocks containing them are committed.
+type CommitNotifier struct {
+	mutex          sync.Mutex
+	blockListeners []BlockListener
+	waiters        map[txKey][]chan *TxStatus
+	// waitersByChannel counts the waiters of each channel, so that the transactions
+	// of a block are only unpacked when someone is waiting on its channel.
+	waitersByChannel map[string]int
+}
+
+// NewCommitNotifier creates a CommitNotifier.
+func NewCommitNotifier() *CommitNotifier {
+	return &CommitNotifier{
+		waiters:          map[txKey][]chan *TxStatus{},
+		waitersByChannel: map[string]int{},
+	}
+}
+
+// AddBlockListener adds a listener notified of every committed block, in commit
+// order per channel, before the waiters of its transactions.
+func (n *CommitNotifier) AddBlockListener(listener BlockListener) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.blockListeners = append(n.blockListeners, listener)
+}
+
+// RegisterTx registers an interest in the commit of a transaction of a channel. The
+// returned channel receives the status of the transaction once a block containing it
+// is committed, after which no more values are sent on it. The returned function
+// must be called to release the registration when the caller stops waiting.
+func (n *CommitNotifier) RegisterTx(channelID, txID string) (<-chan *TxStatus, func()) {
+	key := txKey{channelID: channelID, txID: txID}
+	statusC := make(chan *TxStatus, 1)
+
+	n.mutex.Lock()
+	n.waiters[key] = append(n.waiters[key], statusC)
+	n.waitersByChannel[channelID]++
+	n.mutex.Unlock()
+
+	var once sync.Once
+	return statusC, func() {
+		once.Do(func() {
+			n.mutex.Lock()
+			defer n.mutex.Unlock()
+			n.remove(key, statusC)
+		})
+	}
+}
+
+func (n *CommitNotifier) remove(key txKey, statusC chan *TxStatus) {
+	waiters := n.waiters[key]
+	for i, c := range waiters {
+		if c != statusC {
+			continue
+		}
+		waiters = append(waiters[:i], waiters[i+1:]...)
+		if len(waiters) == 0 {
+			delete(n.waiters, key)
+		} else {
+			n.waiters[key] = waiters
+		}
+		n.waitersByChannel[key.channelID]--
+		if n.waitersByChannel[key.channelID] == 0 {
+			delete(n.waitersByChannel, key.channelID)
+		}
+		return
+	}
+}
+
+// notify sends the status of the transactions of a committed block to their waiters.
+func (n *CommitNotifier) notify(channelID string, block *common.Block) {
+	n.mutex.Lock()
+	listeners := n.blockListeners
+	n.mutex.Unlock()
+	for _, listener := range listeners {
+		listener.BlockCommitted(channelID, block)
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if n.waitersByChannel[channelID] == 0 {
+		return
+	}
+
+	flags := txflags.ValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for i, envBytes := range block.Data.Data {
+		txID, err := txIDOf(envBytes)
+		if err != nil {
+			logger.Debugf("Skipping transaction %d of block [%d] of channel %s: %s", i, block.Header.Number, channelID, err)
+			continue
+		}
+		key := txKey{channelID: channelID, txID: txID}
+		waiters, ok := n.waiters[key]
+		if !ok {
+			continue
+		}
+
+		status := &TxStatus{
+			TxID:           txID,
+			ValidationCode: peer.TxValidationCode_NOT_VALIDATED,
+			BlockNumber:    block.Header.Number,
+		}
+		if i < len(flags) {
+			status.ValidationCode = flags.Flag(i)
+		}
+		for _, statusC := range waiters {
+			statusC <- status
+		}
+		delete(n.waiters, key)
+		n.waitersByChannel[channelID] -= len(waiters)
+		if n.waitersByChannel[channelID] == 0 {
+			delete(n.waitersByChannel, channelID)
+			return
+		}
+	}
+}
+
+func txIDOf(envBytes []byte) (string, error) {
+	env, err := protoutil.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return "", err
+	}
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return "", err
+	}
+	if payload.Header == nil {
+		return "", errors.New("missing payload header")
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return "", err
+	}
+	return chdr.TxId, nil
+}
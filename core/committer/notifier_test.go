@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package committer
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	ledger2 "github.com/osdi23p228/fabric/core/ledger"
+	"github.com/osdi23p228/fabric/internal/pkg/txflags"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func blockWithTxs(number uint64, txIDs []string, codes []peer.TxValidationCode) *common.Block {
+	block := protoutil.NewBlock(number, nil)
+	for _, txID := range txIDs {
+		env := &common.Envelope{
+			Payload: protoutil.MarshalOrPanic(&common.Payload{
+				Header: &common.Header{
+					ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{TxId: txID}),
+				},
+			}),
+		}
+		block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(env))
+	}
+	flags := txflags.New(len(codes))
+	for i, code := range codes {
+		flags.SetFlag(i, code)
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+	return block
+}
+
+func TestCommitNotifier(t *testing.T) {
+	notifier := NewCommitNotifier()
+
+	tx1C, release1 := notifier.RegisterTx("mychannel", "tx1")
+	defer release1()
+	tx2C, release2 := notifier.RegisterTx("mychannel", "tx2")
+	otherC, releaseOther := notifier.RegisterTx("otherchannel", "tx1")
+	defer releaseOther()
+
+	block := blockWithTxs(5, []string{"tx0", "tx1", "tx2"}, []peer.TxValidationCode{
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_MVCC_READ_CONFLICT,
+	})
+	release2()
+	notifier.notify("mychannel", block)
+
+	assert.Equal(t, &TxStatus{TxID: "tx1", ValidationCode: peer.TxValidationCode_VALID, BlockNumber: 5}, <-tx1C)
+	assert.Empty(t, tx2C)
+	assert.Empty(t, otherC)
+	assert.NotContains(t, notifier.waitersByChannel, "mychannel")
+	assert.Equal(t, 1, notifier.waitersByChannel["otherchannel"])
+
+	// a later block with the same transaction does not notify again
+	notifier.notify("mychannel", block)
+	assert.Empty(t, tx1C)
+}
+
+func TestCommitNotifierMultipleWaiters(t *testing.T) {
+	notifier := NewCommitNotifier()
+	c1, release1 := notifier.RegisterTx("mychannel", "tx1")
+	c2, release2 := notifier.RegisterTx("mychannel", "tx1")
+	c3, release3 := notifier.RegisterTx("mychannel", "tx1")
+	release2()
+	release2()
+
+	notifier.notify("mychannel", blockWithTxs(1, []string{"tx1"}, []peer.TxValidationCode{peer.TxValidationCode_VALID}))
+	assert.Equal(t, uint64(1), (<-c1).BlockNumber)
+	assert.Equal(t, uint64(1), (<-c3).BlockNumber)
+	assert.Empty(t, c2)
+
+	release1()
+	release3()
+	assert.Empty(t, notifier.waiters)
+	assert.Empty(t, notifier.waitersByChannel)
+}
+
+func TestCommitNotifierMalformedTransactions(t *testing.T) {
+	notifier := NewCommitNotifier()
+	c, release := notifier.RegisterTx("mychannel", "tx1")
+	defer release()
+
+	block := blockWithTxs(1, []string{"tx1"}, nil)
+	block.Data.Data = append([][]byte{[]byte("garbage")}, block.Data.Data...)
+	notifier.notify("mychannel", block)
+	assert.Equal(t, &TxStatus{TxID: "tx1", ValidationCode: peer.TxValidationCode_NOT_VALIDATED, BlockNumber: 1}, <-c)
+}
+
+type blockRecorder struct {
+	channels []string
+	blocks   []uint64
+}
+
+func (r *blockRecorder) BlockCommitted(channelID string, block *common.Block) {
+	r.channels = append(r.channels, channelID)
+	r.blocks = append(r.blocks, block.Header.Number)
+}
+
+func TestCommitNotifierBlockListeners(t *testing.T) {
+	notifier := NewCommitNotifier()
+	listener1, listener2 := &blockRecorder{}, &blockRecorder{}
+	notifier.AddBlockListener(listener1)
+	notifier.AddBlockListener(listener2)
+
+	notifier.notify("mychannel", blockWithTxs(1, []string{"tx1"}, []peer.TxValidationCode{peer.TxValidationCode_VALID}))
+	notifier.notify("otherchannel", blockWithTxs(7, nil, nil))
+
+	for _, listener := range []*blockRecorder{listener1, listener2} {
+		assert.Equal(t, []string{"mychannel", "otherchannel"}, listener.channels)
+		assert.Equal(t, []uint64{1, 7}, listener.blocks)
+	}
+}
+
+func TestNotifyingLedgerCommitter(t *testing.T) {
+	gb, ledger := createLedger("TestLedger")
+	block1 := blockWithTxs(1, []string{"tx1"}, []peer.TxValidationCode{peer.TxValidationCode_VALID})
+	block1.Header.PreviousHash = gb.Header.DataHash
+	ledger.On("CommitLegacy", mock.Anything).Return(nil)
+
+	notifier := NewCommitNotifier()
+	statusC, release := notifier.RegisterTx("TestLedger", "tx1")
+	defer release()
+
+	committer := NewNotifyingLedgerCommitter(ledger, "TestLedger", notifier)
+	err := committer.CommitLegacy(&ledger2.BlockAndPvtData{Block: block1}, &ledger2.CommitOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, &TxStatus{TxID: "tx1", ValidationCode: peer.TxValidationCode_VALID, BlockNumber: 1}, <-statusC)
+}
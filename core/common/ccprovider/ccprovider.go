@@ -415,4 +415,8 @@ type TransactionParams struct {
 
 	// this is additional data passed to the chaincode
 	ProposalDecorations map[string][]byte
+
+	// UsageRecorder, when set, accumulates the ledger accesses made by the
+	// chaincode invoked for the transaction and the chaincodes it calls.
+	UsageRecorder *UsageRecorder
 }
@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccprovider
+
+import "sync"
+
+// ExecutionUsage accounts for the ledger accesses made by chaincode while
+// executing a transaction.
+type ExecutionUsage struct {
+	// GetStateCalls is the number of requests reading keys by name.
+	GetStateCalls uint64
+	// PutStateCalls is the number of requests writing or deleting keys.
+	PutStateCalls uint64
+	// RangeQueryCalls is the number of range, rich and history queries.
+	RangeQueryCalls uint64
+	// BytesRead is the size of the responses to the read requests.
+	BytesRead uint64
+	// BytesWritten is the size of the write requests.
+	BytesWritten uint64
+}
+
+// Add returns the sum of two usages.
+func (u ExecutionUsage) Add(o ExecutionUsage) ExecutionUsage {
+	return ExecutionUsage{
+		GetStateCalls:   u.GetStateCalls + o.GetStateCalls,
+		PutStateCalls:   u.PutStateCalls + o.PutStateCalls,
+		RangeQueryCalls: u.RangeQueryCalls + o.RangeQueryCalls,
+		BytesRead:       u.BytesRead + o.BytesRead,
+		BytesWritten:    u.BytesWritten + o.BytesWritten,
+	}
+}
+
+// UsageRecorder accumulates the usage of a transaction across the concurrent
+// requests of the chaincodes it invokes. A nil UsageRecorder records nothing.
+type UsageRecorder struct {
+	mutex sync.Mutex
+	usage ExecutionUsage
+}
+
+// Record adds the usage of a request.
+func (r *UsageRecorder) Record(u ExecutionUsage) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	r.usage = r.usage.Add(u)
+	r.mutex.Unlock()
+}
+
+// Usage returns the usage recorded so far.
+func (r *UsageRecorder) Usage() ExecutionUsage {
+	if r == nil {
+		return ExecutionUsage{}
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.usage
+}
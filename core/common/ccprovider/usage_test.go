@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccprovider_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/osdi23p228/fabric/core/common/ccprovider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageRecorder(t *testing.T) {
+	recorder := &ccprovider.UsageRecorder{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorder.Record(ccprovider.ExecutionUsage{GetStateCalls: 1, BytesRead: 10})
+			recorder.Record(ccprovider.ExecutionUsage{PutStateCalls: 1, RangeQueryCalls: 2, BytesWritten: 5})
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, ccprovider.ExecutionUsage{
+		GetStateCalls:   10,
+		PutStateCalls:   10,
+		RangeQueryCalls: 20,
+		BytesRead:       100,
+		BytesWritten:    50,
+	}, recorder.Usage())
+}
+
+func TestNilUsageRecorder(t *testing.T) {
+	var recorder *ccprovider.UsageRecorder
+	recorder.Record(ccprovider.ExecutionUsage{GetStateCalls: 1})
+	assert.Equal(t, ccprovider.ExecutionUsage{}, recorder.Usage())
+}
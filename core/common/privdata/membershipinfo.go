@@ -34,9 +34,10 @@ func NewMembershipInfoProvider(mspID string, selfSignedData protoutil.SignedData
 func (m *MembershipProvider) AmMemberOf(channelName string, collectionPolicyConfig *peer.CollectionPolicyConfig) (bool, error) {
 	deserializer := m.IdentityDeserializerFactory(channelName)
 
-	// Do a simple check to see if the mspid matches any principal identities in the SignaturePolicy - FAB-17059
+	// Do a simple check to see if the mspid matches any principal identities in the SignaturePolicy - FAB-17059.
+	// Principals restricted to an OU or a role of the org are left to the policy evaluation.
 	if collectionPolicyConfig.GetSignaturePolicy() != nil {
-		memberOrgs := getMemberOrgs(collectionPolicyConfig.GetSignaturePolicy().GetIdentities(), deserializer)
+		memberOrgs := getOrgWideMemberOrgs(collectionPolicyConfig.GetSignaturePolicy().GetIdentities(), deserializer)
 
 		if _, ok := memberOrgs[m.mspID]; ok {
 			return true, nil
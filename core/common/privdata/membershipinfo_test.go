@@ -9,11 +9,14 @@ package privdata
 import (
 	"testing"
 
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/osdi23p228/fabric/common/policydsl"
 	"github.com/osdi23p228/fabric/msp"
 	"github.com/osdi23p228/fabric/protoutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMembershipInfoProvider(t *testing.T) {
@@ -63,6 +66,58 @@ func TestMembershipInfoProvider(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestMembershipInfoProviderSubOrgPrincipals(t *testing.T) {
+	ouPrincipal := &mb.MSPPrincipal{
+		PrincipalClassification: mb.MSPPrincipal_ORGANIZATION_UNIT,
+		Principal: protoutil.MarshalOrPanic(&mb.OrganizationUnit{
+			MspIdentifier:                "Org1MSP",
+			OrganizationalUnitIdentifier: "dept1",
+		}),
+	}
+	peerRolePrincipal := &mb.MSPPrincipal{
+		PrincipalClassification: mb.MSPPrincipal_ROLE,
+		Principal:               protoutil.MarshalOrPanic(&mb.MSPRole{MspIdentifier: "Org2MSP", Role: mb.MSPRole_PEER}),
+	}
+	memberRolePrincipal := &mb.MSPPrincipal{
+		PrincipalClassification: mb.MSPPrincipal_ROLE,
+		Principal:               protoutil.MarshalOrPanic(&mb.MSPRole{MspIdentifier: "Org3MSP", Role: mb.MSPRole_MEMBER}),
+	}
+	collectionPolicyConfig := createCollectionPolicyConfig(&cb.SignaturePolicyEnvelope{
+		Rule:       policydsl.Or(policydsl.Or(policydsl.SignedBy(0), policydsl.SignedBy(1)), policydsl.SignedBy(2)),
+		Identities: []*mb.MSPPrincipal{ouPrincipal, peerRolePrincipal, memberRolePrincipal},
+	})
+
+	identityDeserializer := func(chainID string) msp.IdentityDeserializer {
+		return &mockDeserializer{}
+	}
+	// the mock identities satisfy the principals with the same bytes as their own
+	signedData := func(identity []byte) protoutil.SignedData {
+		return protoutil.SignedData{Identity: identity, Signature: []byte{1, 2, 3}, Data: []byte{4, 5, 6}}
+	}
+
+	tests := []struct {
+		name       string
+		mspID      string
+		signedData protoutil.SignedData
+		expected   bool
+	}{
+		{"peer of the OU", "Org1MSP", signedData(ouPrincipal.Principal), true},
+		{"peer outside of the OU", "Org1MSP", signedData([]byte("peer0.org1")), false},
+		{"peer with the role", "Org2MSP", signedData(peerRolePrincipal.Principal), true},
+		{"peer without the role", "Org2MSP", signedData([]byte("peer0.org2")), false},
+		{"peer of an org-wide member", "Org3MSP", protoutil.SignedData{}, true},
+		{"peer of a non member org", "Org4MSP", signedData([]byte("peer0.org4")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			membershipProvider := NewMembershipInfoProvider(tt.mspID, tt.signedData, identityDeserializer)
+			res, err := membershipProvider.AmMemberOf("test1", collectionPolicyConfig)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
 func getAccessPolicy(signers []string) *peer.CollectionPolicyConfig {
 	var data [][]byte
 	for _, signer := range signers {
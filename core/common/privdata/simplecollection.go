@@ -20,6 +20,7 @@ type SimpleCollection struct {
 	name         string
 	accessPolicy policies.Policy
 	memberOrgs   map[string]struct{}
+	orgWideOrgs  map[string]struct{}
 	conf         peer.StaticCollectionConfig
 }
 
@@ -45,6 +46,13 @@ func (sc *SimpleCollection) MemberOrgs() map[string]struct{} {
 	return sc.memberOrgs
 }
 
+// OrgWideMemberOrgs returns the MSP IDs of the orgs whose peers are members of this
+// collection regardless of their OU or role. The peers of the other member orgs are
+// members only if they satisfy the member access policy of this collection.
+func (sc *SimpleCollection) OrgWideMemberOrgs() map[string]struct{} {
+	return sc.orgWideOrgs
+}
+
 // RequiredPeerCount returns the minimum number of peers
 // required to send private data to
 func (sc *SimpleCollection) RequiredPeerCount() int {
@@ -106,6 +114,7 @@ func (sc *SimpleCollection) Setup(collectionConfig *peer.StaticCollectionConfig,
 
 	// get member org MSP IDs from the envelope, identities that fail to deserialize will not be returned
 	sc.memberOrgs = getMemberOrgs(accessPolicyEnvelope.Identities, deserializer)
+	sc.orgWideOrgs = getOrgWideMemberOrgs(accessPolicyEnvelope.Identities, deserializer)
 
 	return nil
 }
@@ -137,6 +137,46 @@ func TestNewSimpleCollectionWithGoodConfig(t *testing.T) {
 	assert.True(t, sc.RequiredPeerCount() == 1)
 }
 
+func TestSetupSubOrgPrincipalsCollection(t *testing.T) {
+	principals := []*mb.MSPPrincipal{
+		{
+			PrincipalClassification: mb.MSPPrincipal_ORGANIZATION_UNIT,
+			Principal:               protoutil.MarshalOrPanic(&mb.OrganizationUnit{MspIdentifier: "Org1MSP", OrganizationalUnitIdentifier: "dept1"}),
+		},
+		{
+			PrincipalClassification: mb.MSPPrincipal_ROLE,
+			Principal:               protoutil.MarshalOrPanic(&mb.MSPRole{MspIdentifier: "Org2MSP", Role: mb.MSPRole_PEER}),
+		},
+		{
+			PrincipalClassification: mb.MSPPrincipal_ROLE,
+			Principal:               protoutil.MarshalOrPanic(&mb.MSPRole{MspIdentifier: "Org3MSP", Role: mb.MSPRole_MEMBER}),
+		},
+	}
+	policyEnvelope := &cb.SignaturePolicyEnvelope{
+		Rule:       policydsl.NOutOf(1, []*cb.SignaturePolicy{policydsl.SignedBy(0), policydsl.SignedBy(1), policydsl.SignedBy(2)}),
+		Identities: principals,
+	}
+	collectionConfig := &pb.StaticCollectionConfig{
+		Name:              "test collection",
+		RequiredPeerCount: 1,
+		MemberOrgsPolicy:  createCollectionPolicyConfig(policyEnvelope),
+	}
+
+	var sc SimpleCollection
+	err := sc.Setup(collectionConfig, &mockDeserializer{})
+	assert.NoError(t, err)
+
+	// all the orgs of the principals are member orgs, but only the org
+	// of the member role principal is a member regardless of the OU or role
+	assert.Equal(t, map[string]struct{}{"Org1MSP": {}, "Org2MSP": {}, "Org3MSP": {}}, sc.MemberOrgs())
+	assert.Equal(t, map[string]struct{}{"Org3MSP": {}}, sc.OrgWideMemberOrgs())
+
+	accessFilter := sc.AccessFilter()
+	assert.True(t, accessFilter(protoutil.SignedData{Identity: principals[0].Principal}))
+	assert.True(t, accessFilter(protoutil.SignedData{Identity: principals[1].Principal}))
+	assert.False(t, accessFilter(protoutil.SignedData{Identity: []byte("peer0.org1")}))
+}
+
 func TestSetupWithBadConfig(t *testing.T) {
 	// set up simple collection with invalid data
 	var sc SimpleCollection
@@ -68,3 +68,29 @@ func getMemberOrgs(identities []*mspp.MSPPrincipal, deserializer msp.IdentityDes
 	}
 	return memberOrgs
 }
+
+// getOrgWideMemberOrgs returns a map containing the orgs whose membership of a collection
+// does not depend on the OU or the role of their identities. It skips the OU principals
+// and the role principals other than member, which only admit part of the identities of
+// an org, as well as the principals it fails to process.
+func getOrgWideMemberOrgs(identities []*mspp.MSPPrincipal, deserializer msp.IdentityDeserializer) map[string]struct{} {
+	memberOrgs := map[string]struct{}{}
+
+	for _, principal := range identities {
+		switch principal.PrincipalClassification {
+		case mspp.MSPPrincipal_ROLE:
+			mspRole := &mspp.MSPRole{}
+			err := proto.Unmarshal(principal.Principal, mspRole)
+			if err == nil && mspRole.Role == mspp.MSPRole_MEMBER {
+				memberOrgs[mspRole.MspIdentifier] = struct{}{}
+			}
+		case mspp.MSPPrincipal_IDENTITY:
+			// identity principals keep admitting their whole org, as they always did - FAB-17059
+			principalId, err := deserializer.DeserializeIdentity(principal.Principal)
+			if err == nil {
+				memberOrgs[principalId.GetMSPIdentifier()] = struct{}{}
+			}
+		}
+	}
+	return memberOrgs
+}
@@ -49,6 +49,16 @@ func (br *BuildRegistry) ResetBuildStatus(ccid string) *BuildStatus {
 	return bs
 }
 
+// RemoveBuildStatus discards the BuildStatus for the ccid, so that the next
+// caller of BuildStatus is responsible for building it again. The caller must
+// use external locking to ensure no build of the ccid is in progress.
+func (br *BuildRegistry) RemoveBuildStatus(ccid string) {
+	br.mutex.Lock()
+	defer br.mutex.Unlock()
+
+	delete(br.builds, ccid)
+}
+
 type BuildStatus struct {
 	mutex sync.Mutex
 	doneC chan struct{}
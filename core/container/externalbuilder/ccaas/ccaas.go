@@ -0,0 +1,174 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ccaas implements the detect, build and release steps of the
+// chaincode-as-a-service external builder. Chaincode packages of type "ccaas"
+// do not contain chaincode source, only the connection.json describing how
+// the peer connects to the externally running chaincode server, along with
+// optional statedb metadata under META-INF.
+package ccaas
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// PackageType is the chaincode package type handled by the builder.
+	PackageType = "ccaas"
+
+	// ConfigEnvVar is the environment variable holding the JSON object
+	// whose values are substituted into the connection.json template of
+	// a package, e.g. {"peername": "peer0org1"}. It must be listed in the
+	// propagateEnvironment of the builder in core.yaml.
+	ConfigEnvVar = "CHAINCODE_AS_A_SERVICE_BUILDER_CONFIG"
+
+	connectionFile = "connection.json"
+	metadataDir    = "META-INF"
+	serverDir      = "chaincode/server"
+)
+
+// Detect returns nil when the package metadata in metadataDir declares a
+// chaincode-as-a-service package.
+func Detect(metadataDir string) error {
+	mdBytes, err := ioutil.ReadFile(filepath.Join(metadataDir, "metadata.json"))
+	if err != nil {
+		return errors.WithMessage(err, "could not read metadata.json")
+	}
+
+	var metadata struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(mdBytes, &metadata); err != nil {
+		return errors.WithMessage(err, "malformed metadata.json")
+	}
+
+	if !strings.EqualFold(metadata.Type, PackageType) {
+		return errors.Errorf("chaincode type '%s' is not '%s'", metadata.Type, PackageType)
+	}
+
+	return nil
+}
+
+// ParseConfig parses the value of ConfigEnvVar. An empty value yields an
+// empty configuration.
+func ParseConfig(value string) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	if value == "" {
+		return config, nil
+	}
+	if err := json.Unmarshal([]byte(value), &config); err != nil {
+		return nil, errors.Wrapf(err, "malformed %s", ConfigEnvVar)
+	}
+	return config, nil
+}
+
+// Build renders the connection.json template of the package in sourceDir
+// with the supplied configuration and writes it, along with any META-INF
+// directory of the package, to outputDir.
+func Build(sourceDir, outputDir string, config map[string]interface{}) error {
+	connPath := filepath.Join(sourceDir, connectionFile)
+	tmplBytes, err := ioutil.ReadFile(connPath)
+	if err != nil {
+		return errors.WithMessagef(err, "could not read %s", connectionFile)
+	}
+
+	tmpl, err := template.New(connectionFile).Option("missingkey=error").Parse(string(tmplBytes))
+	if err != nil {
+		return errors.Wrapf(err, "could not parse %s template", connectionFile)
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, config); err != nil {
+		return errors.Wrapf(err, "could not render %s", connectionFile)
+	}
+
+	var conn struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &conn); err != nil {
+		return errors.Wrapf(err, "malformed %s", connectionFile)
+	}
+	if conn.Address == "" {
+		return errors.Errorf("%s does not specify an address", connectionFile)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(outputDir, connectionFile), buf.Bytes(), 0600); err != nil {
+		return errors.WithMessagef(err, "could not write %s", connectionFile)
+	}
+
+	return copyDirIfExists(filepath.Join(sourceDir, metadataDir), filepath.Join(outputDir, metadataDir))
+}
+
+// Release publishes the build output in bldDir to releaseDir: the rendered
+// connection.json goes to chaincode/server, where the peer reads it to
+// connect to the chaincode, and the content of META-INF to the top level.
+func Release(bldDir, releaseDir string) error {
+	connBytes, err := ioutil.ReadFile(filepath.Join(bldDir, connectionFile))
+	if err != nil {
+		return errors.WithMessagef(err, "could not read %s", connectionFile)
+	}
+
+	serverPath := filepath.Join(releaseDir, serverDir)
+	if err := os.MkdirAll(serverPath, 0700); err != nil {
+		return errors.WithMessagef(err, "could not create %s", serverPath)
+	}
+	if err := ioutil.WriteFile(filepath.Join(serverPath, connectionFile), connBytes, 0600); err != nil {
+		return errors.WithMessagef(err, "could not write %s", connectionFile)
+	}
+
+	return copyDirIfExists(filepath.Join(bldDir, metadataDir), releaseDir)
+}
+
+func copyDirIfExists(srcDir, destDir string) error {
+	if _, err := os.Stat(srcDir); os.IsNotExist(err) {
+		return nil
+	}
+
+	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		destPath := filepath.Join(destDir, relPath)
+		if info.IsDir() {
+			return os.MkdirAll(destPath, 0700)
+		}
+		if !info.Mode().IsRegular() {
+			return errors.Errorf("%s is not a regular file", relPath)
+		}
+		return copyFile(path, destPath)
+	})
+	return errors.WithMessagef(err, "could not copy %s", srcDir)
+}
+
+func copyFile(srcPath, destPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dest, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dest, src); err != nil {
+		dest.Close()
+		return err
+	}
+	return dest.Close()
+}
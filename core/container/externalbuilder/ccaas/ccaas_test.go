@@ -0,0 +1,159 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccaas
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+		errMsg   string
+	}{
+		{name: "ccaas", metadata: `{"type":"ccaas","label":"mycc"}`},
+		{name: "case insensitive", metadata: `{"type":"CCAAS","label":"mycc"}`},
+		{name: "other type", metadata: `{"type":"golang","label":"mycc"}`, errMsg: "chaincode type 'golang' is not 'ccaas'"},
+		{name: "malformed", metadata: `{`, errMsg: "malformed metadata.json: unexpected end of JSON input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "ccaas-detect")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			writeFile(t, filepath.Join(dir, "metadata.json"), tt.metadata)
+
+			err = Detect(dir)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.errMsg)
+			}
+		})
+	}
+
+	t.Run("missing metadata", func(t *testing.T) {
+		err := Detect("/nonexistent")
+		assert.Contains(t, err.Error(), "could not read metadata.json")
+	})
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig("")
+	assert.NoError(t, err)
+	assert.Empty(t, config)
+
+	config, err = ParseConfig(`{"peername":"peer0org1"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"peername": "peer0org1"}, config)
+
+	_, err = ParseConfig(`peer0org1`)
+	assert.EqualError(t, err, "malformed CHAINCODE_AS_A_SERVICE_BUILDER_CONFIG: invalid character 'p' looking for beginning of value")
+}
+
+func TestBuildAndRelease(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ccaas-build")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	srcDir := filepath.Join(dir, "src")
+	bldDir := filepath.Join(dir, "bld")
+	releaseDir := filepath.Join(dir, "release")
+	require.NoError(t, os.MkdirAll(bldDir, 0700))
+	require.NoError(t, os.MkdirAll(releaseDir, 0700))
+	writeFile(t, filepath.Join(srcDir, "connection.json"), `{"address":"{{.peername}}_mycc:9999","dial_timeout":"10s","tls_required":false}`)
+	writeFile(t, filepath.Join(srcDir, "META-INF", "statedb", "couchdb", "indexes", "index.json"), `{"index":{}}`)
+
+	err = Build(srcDir, bldDir, map[string]interface{}{"peername": "peer0org1"})
+	require.NoError(t, err)
+
+	conn, err := ioutil.ReadFile(filepath.Join(bldDir, "connection.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"address":"peer0org1_mycc:9999","dial_timeout":"10s","tls_required":false}`, string(conn))
+	assert.FileExists(t, filepath.Join(bldDir, "META-INF", "statedb", "couchdb", "indexes", "index.json"))
+
+	err = Release(bldDir, releaseDir)
+	require.NoError(t, err)
+
+	released, err := ioutil.ReadFile(filepath.Join(releaseDir, "chaincode", "server", "connection.json"))
+	require.NoError(t, err)
+	assert.Equal(t, conn, released)
+	index, err := ioutil.ReadFile(filepath.Join(releaseDir, "statedb", "couchdb", "indexes", "index.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"index":{}}`, string(index))
+}
+
+func TestBuildFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		connection string
+		config     map[string]interface{}
+		errMsg     string
+	}{
+		{
+			name:       "missing template value",
+			connection: `{"address":"{{.peername}}:9999"}`,
+			errMsg:     `could not render connection.json: template: connection.json:1:14: executing "connection.json" at <.peername>: map has no entry for key "peername"`,
+		},
+		{
+			name:       "bad template",
+			connection: `{"address":"{{.peername"}`,
+			errMsg:     `could not parse connection.json template: template: connection.json:1: bad character U+0022 '"'`,
+		},
+		{
+			name:       "malformed json",
+			connection: `{"address":`,
+			errMsg:     "malformed connection.json: unexpected end of JSON input",
+		},
+		{
+			name:       "no address",
+			connection: `{"tls_required":false}`,
+			errMsg:     "connection.json does not specify an address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "ccaas-build")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			writeFile(t, filepath.Join(dir, "src", "connection.json"), tt.connection)
+
+			err = Build(filepath.Join(dir, "src"), dir, tt.config)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+
+	t.Run("missing connection.json", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "ccaas-build")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		err = Build(dir, dir, nil)
+		assert.Contains(t, err.Error(), "could not read connection.json")
+	})
+}
+
+func TestReleaseMissingBuildOutput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ccaas-release")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	err = Release(dir, dir)
+	assert.Contains(t, err.Error(), "could not read connection.json")
+}
@@ -0,0 +1,123 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+	"github.com/osdi23p228/fabric/core/container/externalbuilder"
+	"github.com/osdi23p228/fabric/core/peer"
+)
+
+var _ = Describe("chaincode-as-a-service builder", func() {
+	var (
+		builderDir  string
+		durablePath string
+		detector    *externalbuilder.Detector
+	)
+
+	BeforeEach(func() {
+		var err error
+		builderDir, err = ioutil.TempDir("", "ccaas-builder")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Mkdir(filepath.Join(builderDir, "bin"), 0700)).To(Succeed())
+		for _, step := range []string{"detect", "build", "release"} {
+			binary, err := gexec.Build("github.com/osdi23p228/fabric/cmd/ccaas_builder/" + step)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Rename(binary, filepath.Join(builderDir, "bin", step))).To(Succeed())
+		}
+
+		durablePath, err = ioutil.TempDir("", "ccaas-durable")
+		Expect(err).NotTo(HaveOccurred())
+
+		detector = &externalbuilder.Detector{
+			Builders: externalbuilder.CreateBuilders([]peer.ExternalBuilder{
+				{
+					Path:                 builderDir,
+					Name:                 "ccaas_builder",
+					PropagateEnvironment: []string{"CHAINCODE_AS_A_SERVICE_BUILDER_CONFIG"},
+				},
+			}, "mspid"),
+			DurablePath: durablePath,
+		}
+
+		os.Setenv("CHAINCODE_AS_A_SERVICE_BUILDER_CONFIG", `{"peername":"peer0org1"}`)
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("CHAINCODE_AS_A_SERVICE_BUILDER_CONFIG")
+		os.RemoveAll(builderDir)
+		os.RemoveAll(durablePath)
+		gexec.CleanupBuildArtifacts()
+	})
+
+	It("builds ccaas packages into instances connecting to the templated address", func() {
+		codePackage := tarGz(map[string]string{
+			"connection.json": `{"address":"{{.peername}}-mycc:9999","dial_timeout":"10s"}`,
+			"META-INF/statedb/couchdb/indexes/index.json": `{"index":{}}`,
+		})
+
+		instance, err := detector.Build("mycc:1234", []byte(`{"type":"ccaas","label":"mycc"}`), codePackage)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instance).NotTo(BeNil())
+		Expect(instance.Builder.Name).To(Equal("ccaas_builder"))
+
+		serverInfo, err := instance.ChaincodeServerInfo()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(serverInfo.Address).To(Equal("peer0org1-mycc:9999"))
+		Expect(serverInfo.ClientConfig.Timeout).To(Equal(10 * time.Second))
+
+		metadata, err := (&externalbuilder.MetadataProvider{DurablePath: durablePath}).PackageMetadata("mycc:1234")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(metadata)).To(ContainSubstring("META-INF/statedb/couchdb/indexes/index.json"))
+	})
+
+	It("does not detect packages of other types", func() {
+		codePackage := tarGz(map[string]string{"main.go": "package main"})
+		instance, err := detector.Build("mycc:1234", []byte(`{"type":"golang","label":"mycc"}`), codePackage)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instance).To(BeNil())
+	})
+
+	When("the connection template references unknown values", func() {
+		It("fails the build", func() {
+			codePackage := tarGz(map[string]string{
+				"connection.json": `{"address":"{{.hostname}}:9999"}`,
+			})
+			_, err := detector.Build("mycc:1234", []byte(`{"type":"ccaas","label":"mycc"}`), codePackage)
+			Expect(err).To(MatchError(ContainSubstring("external builder failed to build")))
+		})
+	})
+})
+
+func tarGz(files map[string]string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0600,
+			Size: int64(len(content)),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = tw.Write([]byte(content))
+		Expect(err).NotTo(HaveOccurred())
+	}
+	Expect(tw.Close()).To(Succeed())
+	Expect(gw.Close()).To(Succeed())
+	return buf
+}
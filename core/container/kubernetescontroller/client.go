@@ -0,0 +1,446 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kubernetescontroller
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNotFound is returned by a Client when the requested resource does not
+// exist.
+var ErrNotFound = errors.New("resource not found")
+
+//go:generate counterfeiter -o mock/client.go --fake-name Client . Client
+
+// Client manages the jobs and pods of chaincode in a Kubernetes namespace.
+type Client interface {
+	// CreateJob creates a job, returns an error in case of failure
+	CreateJob(job *Job) error
+	// GetJob returns the named job, or ErrNotFound if it does not exist
+	GetJob(name string) (*Job, error)
+	// DeleteJob deletes the named job along with its pods
+	DeleteJob(name string) error
+	// CreatePod creates a pod, returns an error in case of failure
+	CreatePod(pod *Pod) error
+	// GetPod returns the named pod, or ErrNotFound if it does not exist
+	GetPod(name string) (*Pod, error)
+	// ListPods returns the pods matching the label selector
+	ListPods(labelSelector string) ([]Pod, error)
+	// DeletePod deletes the named pod without a grace period
+	DeletePod(name string) error
+	// PodLogs returns the output of the named pod. When follow is true the
+	// stream remains open until the pod terminates.
+	PodLogs(name string, follow bool) (io.ReadCloser, error)
+	// CreateSecret creates a secret, returns an error in case of failure
+	CreateSecret(secret *Secret) error
+	// DeleteSecret deletes the named secret
+	DeleteSecret(name string) error
+	// Ping checks that the API server is reachable and healthy
+	Ping(ctx context.Context) error
+}
+
+// ObjectMeta is the subset of the Kubernetes object metadata used by the
+// controller.
+type ObjectMeta struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type EnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type VolumeMount struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mountPath"`
+	SubPath   string `json:"subPath,omitempty"`
+	ReadOnly  bool   `json:"readOnly,omitempty"`
+}
+
+type Container struct {
+	Name         string        `json:"name"`
+	Image        string        `json:"image"`
+	Command      []string      `json:"command,omitempty"`
+	Env          []EnvVar      `json:"env,omitempty"`
+	VolumeMounts []VolumeMount `json:"volumeMounts,omitempty"`
+}
+
+type PersistentVolumeClaimVolumeSource struct {
+	ClaimName string `json:"claimName"`
+}
+
+type SecretVolumeSource struct {
+	SecretName  string `json:"secretName"`
+	DefaultMode *int32 `json:"defaultMode,omitempty"`
+}
+
+type Volume struct {
+	Name                  string                             `json:"name"`
+	PersistentVolumeClaim *PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+	Secret                *SecretVolumeSource                `json:"secret,omitempty"`
+}
+
+type PodSpec struct {
+	Containers    []Container `json:"containers"`
+	Volumes       []Volume    `json:"volumes,omitempty"`
+	RestartPolicy string      `json:"restartPolicy,omitempty"`
+}
+
+type ContainerStateTerminated struct {
+	ExitCode int    `json:"exitCode"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type ContainerState struct {
+	Terminated *ContainerStateTerminated `json:"terminated,omitempty"`
+}
+
+type ContainerStatus struct {
+	Name  string         `json:"name"`
+	State ContainerState `json:"state"`
+}
+
+// Pod phases reported in PodStatus.
+const (
+	PodPending   = "Pending"
+	PodRunning   = "Running"
+	PodSucceeded = "Succeeded"
+	PodFailed    = "Failed"
+)
+
+type PodStatus struct {
+	Phase             string            `json:"phase,omitempty"`
+	ContainerStatuses []ContainerStatus `json:"containerStatuses,omitempty"`
+}
+
+type Pod struct {
+	APIVersion string     `json:"apiVersion"`
+	Kind       string     `json:"kind"`
+	Metadata   ObjectMeta `json:"metadata"`
+	Spec       PodSpec    `json:"spec"`
+	Status     PodStatus  `json:"status,omitempty"`
+}
+
+type PodTemplateSpec struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     PodSpec    `json:"spec"`
+}
+
+type JobSpec struct {
+	Template     PodTemplateSpec `json:"template"`
+	BackoffLimit *int32          `json:"backoffLimit,omitempty"`
+}
+
+type JobStatus struct {
+	Succeeded int32 `json:"succeeded,omitempty"`
+	Failed    int32 `json:"failed,omitempty"`
+}
+
+type Job struct {
+	APIVersion string     `json:"apiVersion"`
+	Kind       string     `json:"kind"`
+	Metadata   ObjectMeta `json:"metadata"`
+	Spec       JobSpec    `json:"spec"`
+	Status     JobStatus  `json:"status,omitempty"`
+}
+
+type Secret struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   ObjectMeta        `json:"metadata"`
+	Data       map[string][]byte `json:"data,omitempty"`
+}
+
+type podList struct {
+	Items []Pod `json:"items"`
+}
+
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// tokenFileRefreshInterval is how long a token read from a TokenFile is used
+// before the file is read again.
+const tokenFileRefreshInterval = time.Minute
+
+// RESTClient is a Client talking to the Kubernetes API server over HTTP.
+type RESTClient struct {
+	// URL is the base URL of the API server, e.g. https://10.0.0.1:443
+	URL string
+	// Namespace holds the jobs and pods of the chaincode
+	Namespace string
+	// Token is the bearer token used to authenticate to the API server
+	Token string
+	// TokenFile, when set, holds the bearer token in place of Token. The
+	// file is read again every minute and once the API server rejects the
+	// token, since the kubelet rotates projected service account tokens.
+	TokenFile  string
+	HTTPClient *http.Client
+
+	tokenMutex  sync.Mutex
+	fileToken   string
+	tokenExpiry time.Time
+}
+
+// NewInClusterClient creates a RESTClient from the service account and
+// environment Kubernetes provides to the pods it runs. When namespace is
+// empty, the namespace of the service account is used.
+func NewInClusterClient(namespace string) (*RESTClient, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT must be set")
+	}
+
+	tokenFile := filepath.Join(serviceAccountDir, "token")
+	if _, err := ioutil.ReadFile(tokenFile); err != nil {
+		return nil, errors.WithMessage(err, "could not read service account token")
+	}
+
+	caPEM, err := ioutil.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not read service account CA certificate")
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("service account CA certificate contains no certificates")
+	}
+
+	if namespace == "" {
+		ns, err := ioutil.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not read service account namespace")
+		}
+		namespace = strings.TrimSpace(string(ns))
+	}
+
+	return &RESTClient{
+		URL:       "https://" + net.JoinHostPort(host, port),
+		Namespace: namespace,
+		TokenFile: tokenFile,
+		HTTPClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: rootCAs},
+			},
+		},
+	}, nil
+}
+
+// bearerToken returns the token to authenticate with, reading it from the
+// TokenFile when the last token read from it is stale.
+func (c *RESTClient) bearerToken() (string, error) {
+	if c.TokenFile == "" {
+		return c.Token, nil
+	}
+
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	if c.fileToken != "" && time.Now().Before(c.tokenExpiry) {
+		return c.fileToken, nil
+	}
+	token, err := ioutil.ReadFile(c.TokenFile)
+	if err != nil {
+		return "", errors.WithMessage(err, "could not read bearer token")
+	}
+	c.fileToken = strings.TrimSpace(string(token))
+	c.tokenExpiry = time.Now().Add(tokenFileRefreshInterval)
+	return c.fileToken, nil
+}
+
+// expireToken forces the token to be read again from the TokenFile.
+func (c *RESTClient) expireToken() {
+	c.tokenMutex.Lock()
+	c.tokenExpiry = time.Time{}
+	c.tokenMutex.Unlock()
+}
+
+func (c *RESTClient) jobsPath() string {
+	return fmt.Sprintf("/apis/batch/v1/namespaces/%s/jobs", url.PathEscape(c.Namespace))
+}
+
+func (c *RESTClient) podsPath() string {
+	return fmt.Sprintf("/api/v1/namespaces/%s/pods", url.PathEscape(c.Namespace))
+}
+
+func (c *RESTClient) secretsPath() string {
+	return fmt.Sprintf("/api/v1/namespaces/%s/secrets", url.PathEscape(c.Namespace))
+}
+
+func (c *RESTClient) CreateJob(job *Job) error {
+	job.APIVersion, job.Kind = "batch/v1", "Job"
+	return c.do(http.MethodPost, c.jobsPath(), nil, job, nil)
+}
+
+func (c *RESTClient) GetJob(name string) (*Job, error) {
+	job := &Job{}
+	if err := c.do(http.MethodGet, c.jobsPath()+"/"+url.PathEscape(name), nil, nil, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (c *RESTClient) DeleteJob(name string) error {
+	query := url.Values{"propagationPolicy": []string{"Background"}}
+	return c.do(http.MethodDelete, c.jobsPath()+"/"+url.PathEscape(name), query, nil, nil)
+}
+
+func (c *RESTClient) CreatePod(pod *Pod) error {
+	pod.APIVersion, pod.Kind = "v1", "Pod"
+	return c.do(http.MethodPost, c.podsPath(), nil, pod, nil)
+}
+
+func (c *RESTClient) GetPod(name string) (*Pod, error) {
+	pod := &Pod{}
+	if err := c.do(http.MethodGet, c.podsPath()+"/"+url.PathEscape(name), nil, nil, pod); err != nil {
+		return nil, err
+	}
+	return pod, nil
+}
+
+func (c *RESTClient) ListPods(labelSelector string) ([]Pod, error) {
+	pods := &podList{}
+	query := url.Values{"labelSelector": []string{labelSelector}}
+	if err := c.do(http.MethodGet, c.podsPath(), query, nil, pods); err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+func (c *RESTClient) DeletePod(name string) error {
+	query := url.Values{"gracePeriodSeconds": []string{"0"}}
+	return c.do(http.MethodDelete, c.podsPath()+"/"+url.PathEscape(name), query, nil, nil)
+}
+
+func (c *RESTClient) PodLogs(name string, follow bool) (io.ReadCloser, error) {
+	query := url.Values{}
+	if follow {
+		query.Set("follow", "true")
+	}
+	resp, err := c.request(http.MethodGet, c.podsPath()+"/"+url.PathEscape(name)+"/log", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (c *RESTClient) CreateSecret(secret *Secret) error {
+	secret.APIVersion, secret.Kind = "v1", "Secret"
+	return c.do(http.MethodPost, c.secretsPath(), nil, secret, nil)
+}
+
+func (c *RESTClient) DeleteSecret(name string) error {
+	return c.do(http.MethodDelete, c.secretsPath()+"/"+url.PathEscape(name), nil, nil, nil)
+}
+
+func (c *RESTClient) Ping(ctx context.Context) error {
+	resp, err := c.requestWithContext(ctx, http.MethodGet, "/healthz", nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (c *RESTClient) do(method, path string, query url.Values, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return errors.Wrap(err, "could not marshal request")
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	resp, err := c.request(method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Wrapf(err, "could not decode response of %s %s", method, path)
+	}
+	return nil
+}
+
+// request issues the request and returns the response if its status is
+// successful. The caller must close the body of the returned response.
+func (c *RESTClient) request(method, path string, query url.Values, body io.Reader) (*http.Response, error) {
+	return c.requestWithContext(context.Background(), method, path, query, body)
+}
+
+func (c *RESTClient) requestWithContext(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Response, error) {
+	u := c.URL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not create request %s %s", method, path)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	token, err := c.bearerToken()
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s failed", method, path)
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.WithMessagef(ErrNotFound, "%s %s", method, path)
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		c.expireToken()
+	}
+
+	// The API server reports failures as a Status object
+	status := struct {
+		Message string `json:"message"`
+	}{}
+	respBody, _ := ioutil.ReadAll(resp.Body)
+	if json.Unmarshal(respBody, &status) != nil || status.Message == "" {
+		status.Message = strings.TrimSpace(string(respBody))
+	}
+	return nil, errors.Errorf("%s %s failed with status %d: %s", method, path, resp.StatusCode, status.Message)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kubernetescontroller
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTClientJobs(t *testing.T) {
+	server := newFakeAPIServer("fabric")
+	defer server.Close()
+	server.buildLogs = "compiling\n"
+	client := &RESTClient{URL: server.URL, Namespace: "fabric", Token: "secret"}
+
+	err := client.CreateJob(&Job{Metadata: ObjectMeta{Name: "cc-build"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret", server.authorization())
+
+	err = client.CreateJob(&Job{Metadata: ObjectMeta{Name: "cc-build"}})
+	assert.EqualError(t, err, "POST /apis/batch/v1/namespaces/fabric/jobs failed with status 409: job already exists")
+
+	job, err := client.GetJob("cc-build")
+	require.NoError(t, err)
+	assert.Equal(t, "batch/v1", job.APIVersion)
+	assert.Equal(t, "Job", job.Kind)
+	assert.Equal(t, int32(1), job.Status.Succeeded)
+
+	pods, err := client.ListPods("job-name=cc-build")
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+
+	logs, err := client.PodLogs(pods[0].Metadata.Name, false)
+	require.NoError(t, err)
+	output, err := ioutil.ReadAll(logs)
+	require.NoError(t, err)
+	logs.Close()
+	assert.Equal(t, "compiling\n", string(output))
+
+	require.NoError(t, client.DeleteJob("cc-build"))
+	_, err = client.GetJob("cc-build")
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+	pods, err = client.ListPods("job-name=cc-build")
+	require.NoError(t, err)
+	assert.Empty(t, pods)
+}
+
+func TestRESTClientPods(t *testing.T) {
+	server := newFakeAPIServer("fabric")
+	defer server.Close()
+	client := &RESTClient{URL: server.URL, Namespace: "fabric"}
+
+	err := client.CreatePod(&Pod{Metadata: ObjectMeta{Name: "cc"}})
+	require.NoError(t, err)
+	assert.Empty(t, server.authorization())
+
+	pod, err := client.GetPod("cc")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", pod.APIVersion)
+	assert.Equal(t, "Pod", pod.Kind)
+	assert.Equal(t, PodRunning, pod.Status.Phase)
+
+	require.NoError(t, client.DeletePod("cc"))
+	_, err = client.GetPod("cc")
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+	err = client.DeletePod("cc")
+	assert.EqualError(t, err, "DELETE /api/v1/namespaces/fabric/pods/cc: resource not found")
+	_, err = client.PodLogs("cc", true)
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+}
+
+func TestRESTClientSecrets(t *testing.T) {
+	server := newFakeAPIServer("fabric")
+	defer server.Close()
+	client := &RESTClient{URL: server.URL, Namespace: "fabric"}
+
+	err := client.CreateSecret(&Secret{Metadata: ObjectMeta{Name: "cc-tls"}, Data: map[string][]byte{"client.key": []byte("key")}})
+	require.NoError(t, err)
+	secret := server.getSecret("cc-tls")
+	require.NotNil(t, secret)
+	assert.Equal(t, "v1", secret.APIVersion)
+	assert.Equal(t, "Secret", secret.Kind)
+	assert.Equal(t, []byte("key"), secret.Data["client.key"])
+
+	require.NoError(t, client.DeleteSecret("cc-tls"))
+	err = client.DeleteSecret("cc-tls")
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+}
+
+func TestRESTClientPing(t *testing.T) {
+	server := newFakeAPIServer("fabric")
+	client := &RESTClient{URL: server.URL, Namespace: "fabric"}
+	require.NoError(t, client.Ping(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := client.Ping(ctx)
+	assert.Contains(t, err.Error(), "GET /healthz failed: ")
+
+	server.Close()
+	err = client.Ping(context.Background())
+	assert.Contains(t, err.Error(), "GET /healthz failed: ")
+}
+
+func TestRESTClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/namespaces/fabric/pods/forbidden":
+			http.Error(w, "forbidden by policy", http.StatusForbidden)
+		default:
+			w.Write([]byte("{"))
+		}
+	}))
+	defer server.Close()
+	client := &RESTClient{URL: server.URL, Namespace: "fabric"}
+
+	_, err := client.GetPod("forbidden")
+	assert.EqualError(t, err, "GET /api/v1/namespaces/fabric/pods/forbidden failed with status 403: forbidden by policy")
+
+	_, err = client.GetPod("malformed")
+	assert.EqualError(t, err, "could not decode response of GET /api/v1/namespaces/fabric/pods/malformed: unexpected EOF")
+
+	server.Close()
+	_, err = client.GetJob("cc")
+	assert.Contains(t, err.Error(), "GET /apis/batch/v1/namespaces/fabric/jobs/cc failed: ")
+}
+
+func TestRESTClientTokenFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "kubernetescontroller")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	tokenFile := filepath.Join(tempDir, "token")
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("token1\n"), 0600))
+
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		if authorization != "Bearer token2" {
+			http.Error(w, "token expired", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	client := &RESTClient{URL: server.URL, Namespace: "fabric", Token: "ignored", TokenFile: tokenFile}
+
+	err = client.Ping(context.Background())
+	assert.EqualError(t, err, "GET /healthz failed with status 401: token expired")
+	assert.Equal(t, "Bearer token1", authorization)
+
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("token2\n"), 0600))
+	require.NoError(t, client.Ping(context.Background()), "the rejected token is read again")
+	assert.Equal(t, "Bearer token2", authorization)
+
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("token3\n"), 0600))
+	require.NoError(t, client.Ping(context.Background()), "the accepted token is used until it is stale")
+	assert.Equal(t, "Bearer token2", authorization)
+
+	client.tokenExpiry = time.Now()
+	err = client.Ping(context.Background())
+	assert.EqualError(t, err, "GET /healthz failed with status 401: token expired")
+	assert.Equal(t, "Bearer token3", authorization)
+
+	require.NoError(t, os.Remove(tokenFile))
+	client.expireToken()
+	err = client.Ping(context.Background())
+	assert.Contains(t, err.Error(), "could not read bearer token: open "+tokenFile)
+}
+
+func TestNewInClusterClient(t *testing.T) {
+	os.Unsetenv("KUBERNETES_SERVICE_HOST")
+	_, err := NewInClusterClient("fabric")
+	assert.EqualError(t, err, "KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT must be set")
+}
@@ -0,0 +1,224 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kubernetescontroller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// fakeAPIServer is an in-memory stand-in for the jobs, pods and secrets endpoints
+// of the Kubernetes API server of a single namespace. Jobs complete as soon as
+// they are created, each with a single pod whose logs are buildLogs.
+type fakeAPIServer struct {
+	*httptest.Server
+
+	mutex      sync.Mutex
+	jobs       map[string]*Job
+	pods       map[string]*Pod
+	secrets    map[string]*Secret
+	logs       map[string]string
+	failJobs   bool
+	buildLogs  string
+	authHeader string
+}
+
+func newFakeAPIServer(namespace string) *fakeAPIServer {
+	s := &fakeAPIServer{
+		jobs:    map[string]*Job{},
+		pods:    map[string]*Pod{},
+		secrets: map[string]*Secret{},
+		logs:    map[string]string{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apis/batch/v1/namespaces/"+namespace+"/jobs", s.handleJobs)
+	mux.HandleFunc("/apis/batch/v1/namespaces/"+namespace+"/jobs/", s.handleJobs)
+	mux.HandleFunc("/api/v1/namespaces/"+namespace+"/pods", s.handlePods)
+	mux.HandleFunc("/api/v1/namespaces/"+namespace+"/pods/", s.handlePods)
+	mux.HandleFunc("/api/v1/namespaces/"+namespace+"/secrets", s.handleSecrets)
+	mux.HandleFunc("/api/v1/namespaces/"+namespace+"/secrets/", s.handleSecrets)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) })
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *fakeAPIServer) handleJobs(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.authHeader = r.Header.Get("Authorization")
+
+	name := resourceName(r.URL.Path, "jobs")
+	switch {
+	case r.Method == http.MethodPost:
+		job := &Job{}
+		if err := json.NewDecoder(r.Body).Decode(job); err != nil {
+			writeStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, ok := s.jobs[job.Metadata.Name]; ok {
+			writeStatus(w, http.StatusConflict, "job already exists")
+			return
+		}
+		podName := job.Metadata.Name + "-x7k2p"
+		phase, exitCode := PodSucceeded, 0
+		job.Status.Succeeded = 1
+		if s.failJobs {
+			phase, exitCode = PodFailed, 2
+			job.Status = JobStatus{Failed: 1}
+		}
+		s.jobs[job.Metadata.Name] = job
+		s.pods[podName] = &Pod{
+			Metadata: ObjectMeta{Name: podName, Labels: map[string]string{"job-name": job.Metadata.Name}},
+			Spec:     job.Spec.Template.Spec,
+			Status:   terminatedStatus(phase, exitCode),
+		}
+		s.logs[podName] = s.buildLogs
+		json.NewEncoder(w).Encode(job)
+	case r.Method == http.MethodGet && s.jobs[name] != nil:
+		json.NewEncoder(w).Encode(s.jobs[name])
+	case r.Method == http.MethodDelete && s.jobs[name] != nil:
+		delete(s.jobs, name)
+		for podName, pod := range s.pods {
+			if pod.Metadata.Labels["job-name"] == name {
+				delete(s.pods, podName)
+			}
+		}
+		writeStatus(w, http.StatusOK, "")
+	default:
+		writeStatus(w, http.StatusNotFound, "job not found")
+	}
+}
+
+func (s *fakeAPIServer) handlePods(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.authHeader = r.Header.Get("Authorization")
+
+	name := resourceName(r.URL.Path, "pods")
+	switch {
+	case r.Method == http.MethodPost:
+		pod := &Pod{}
+		if err := json.NewDecoder(r.Body).Decode(pod); err != nil {
+			writeStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, ok := s.pods[pod.Metadata.Name]; ok {
+			writeStatus(w, http.StatusConflict, "pod already exists")
+			return
+		}
+		pod.Status.Phase = PodRunning
+		s.pods[pod.Metadata.Name] = pod
+		s.logs[pod.Metadata.Name] = ""
+		json.NewEncoder(w).Encode(pod)
+	case r.Method == http.MethodGet && name == "":
+		selector := strings.SplitN(r.URL.Query().Get("labelSelector"), "=", 2)
+		list := podList{Items: []Pod{}}
+		for _, pod := range s.pods {
+			if len(selector) == 2 && pod.Metadata.Labels[selector[0]] == selector[1] {
+				list.Items = append(list.Items, *pod)
+			}
+		}
+		json.NewEncoder(w).Encode(list)
+	case r.Method == http.MethodGet && strings.HasSuffix(name, "/log"):
+		logs, ok := s.logs[strings.TrimSuffix(name, "/log")]
+		if !ok {
+			writeStatus(w, http.StatusNotFound, "pod not found")
+			return
+		}
+		w.Write([]byte(logs))
+	case r.Method == http.MethodGet && s.pods[name] != nil:
+		json.NewEncoder(w).Encode(s.pods[name])
+	case r.Method == http.MethodDelete && s.pods[name] != nil:
+		delete(s.pods, name)
+		delete(s.logs, name)
+		writeStatus(w, http.StatusOK, "")
+	default:
+		writeStatus(w, http.StatusNotFound, "pod not found")
+	}
+}
+
+func (s *fakeAPIServer) handleSecrets(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.authHeader = r.Header.Get("Authorization")
+
+	name := resourceName(r.URL.Path, "secrets")
+	switch {
+	case r.Method == http.MethodPost:
+		secret := &Secret{}
+		if err := json.NewDecoder(r.Body).Decode(secret); err != nil {
+			writeStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, ok := s.secrets[secret.Metadata.Name]; ok {
+			writeStatus(w, http.StatusConflict, "secret already exists")
+			return
+		}
+		s.secrets[secret.Metadata.Name] = secret
+		json.NewEncoder(w).Encode(secret)
+	case r.Method == http.MethodDelete && s.secrets[name] != nil:
+		delete(s.secrets, name)
+		writeStatus(w, http.StatusOK, "")
+	default:
+		writeStatus(w, http.StatusNotFound, "secret not found")
+	}
+}
+
+func (s *fakeAPIServer) getSecret(name string) *Secret {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.secrets[name]
+}
+
+func (s *fakeAPIServer) getPod(name string) *Pod {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.pods[name]
+}
+
+// terminatePod marks the named pod as terminated with the exit code and
+// gives it the supplied output.
+func (s *fakeAPIServer) terminatePod(name string, exitCode int, logs string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	phase := PodSucceeded
+	if exitCode != 0 {
+		phase = PodFailed
+	}
+	s.pods[name].Status = terminatedStatus(phase, exitCode)
+	s.logs[name] = logs
+}
+
+func (s *fakeAPIServer) authorization() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.authHeader
+}
+
+func terminatedStatus(phase string, exitCode int) PodStatus {
+	return PodStatus{
+		Phase: phase,
+		ContainerStatuses: []ContainerStatus{{
+			Name:  containerName,
+			State: ContainerState{Terminated: &ContainerStateTerminated{ExitCode: exitCode}},
+		}},
+	}
+}
+
+// resourceName returns the part of the path following the resource type.
+func resourceName(path, resource string) string {
+	parts := strings.SplitN(path, "/"+resource, 2)
+	return strings.TrimPrefix(parts[1], "/")
+}
+
+func writeStatus(w http.ResponseWriter, code int, message string) {
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{"kind": "Status", "code": code, "message": message})
+}
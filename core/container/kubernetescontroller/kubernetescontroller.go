@@ -0,0 +1,634 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kubernetescontroller
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/common/flogging"
+	"github.com/osdi23p228/fabric/common/util"
+	"github.com/osdi23p228/fabric/core/chaincode/persistence"
+	platformutil "github.com/osdi23p228/fabric/core/chaincode/platforms/util"
+	"github.com/osdi23p228/fabric/core/container"
+	"github.com/osdi23p228/fabric/core/container/ccintf"
+	"github.com/osdi23p228/fabric/core/container/externalbuilder"
+	"github.com/pkg/errors"
+)
+
+var (
+	kubernetesLogger = flogging.MustGetLogger("kubernetescontroller")
+	nameRegExp       = regexp.MustCompile("[^a-z0-9-]")
+)
+
+const (
+	// maxNameLength leaves room for the build job suffix within the 63
+	// characters Kubernetes allows for the names of jobs and pods.
+	maxNameLength   = 57
+	nameHashLength  = 12
+	buildJobSuffix  = "-build"
+	tlsSecretSuffix = "-tls"
+
+	// builtMarker is written to the chaincode directory on the shared
+	// volume once the build job succeeded.
+	builtMarker = ".built"
+
+	volumeName    = "chaincode"
+	tlsVolumeName = "tls"
+	containerName = "chaincode"
+	chaincodeRoot = "/chaincode"
+
+	defaultBuildTimeout = 10 * time.Minute
+	defaultPollInterval = time.Second
+	podDeletionTimeout  = time.Minute
+)
+
+const (
+	// Mutual TLS auth client key and cert paths in the chaincode container
+	TLSClientKeyPath      string = "/etc/hyperledger/fabric/client.key"
+	TLSClientCertPath     string = "/etc/hyperledger/fabric/client.crt"
+	TLSClientKeyFile      string = "/etc/hyperledger/fabric/client_pem.key"
+	TLSClientCertFile     string = "/etc/hyperledger/fabric/client_pem.crt"
+	TLSClientRootCertFile string = "/etc/hyperledger/fabric/peer.crt"
+)
+
+//go:generate counterfeiter -o mock/platform_builder.go --fake-name PlatformBuilder . PlatformBuilder
+
+// PlatformBuilder provides the build and runtime environment of a chaincode
+// platform.
+type PlatformBuilder interface {
+	GenerateDockerfile(ccType string) (string, error)
+	DockerBuildOptions(ccType, path string) (platformutil.DockerBuildOptions, error)
+}
+
+type ContainerInstance struct {
+	CCID         string
+	Type         string
+	KubernetesVM *KubernetesVM
+}
+
+func (ci *ContainerInstance) Start(peerConnection *ccintf.PeerConnection) error {
+	return ci.KubernetesVM.Start(ci.CCID, ci.Type, peerConnection)
+}
+
+func (ci *ContainerInstance) ChaincodeServerInfo() (*ccintf.ChaincodeServerInfo, error) {
+	return nil, nil
+}
+
+func (ci *ContainerInstance) Stop() error {
+	return ci.KubernetesVM.Stop(ci.CCID)
+}
+
+func (ci *ContainerInstance) Wait() (int, error) {
+	return ci.KubernetesVM.Wait(ci.CCID)
+}
+
+// KubernetesVM is a vm running chaincode as pods in a Kubernetes namespace.
+// Chaincode is compiled by a build job and the build output is handed to the
+// chaincode pod through a persistent volume claim shared with the peer.
+type KubernetesVM struct {
+	PeerID          string
+	NetworkID       string
+	BuildMetrics    *BuildMetrics
+	Client          Client
+	AttachStdOut    bool
+	PlatformBuilder PlatformBuilder
+	LoggingEnv      []string
+	MSPID           string
+
+	// ClaimName is the persistent volume claim shared by the peer, the build
+	// jobs and the chaincode pods.
+	ClaimName string
+	// SharedPath is where the claim is mounted in the peer.
+	SharedPath string
+	// BuildTimeout bounds the time a build job may take.
+	BuildTimeout time.Duration
+	// PollInterval is the period at which jobs and pods are polled for
+	// status changes.
+	PollInterval time.Duration
+}
+
+// HealthCheck checks if the KubernetesVM is able to communicate with the
+// Kubernetes API server.
+func (vm *KubernetesVM) HealthCheck(ctx context.Context) error {
+	if err := vm.Client.Ping(ctx); err != nil {
+		return errors.Wrap(err, "failed to ping the Kubernetes API server")
+	}
+	return nil
+}
+
+// Build runs a build job for the chaincode unless a previous build output
+// exists on the shared volume.
+func (vm *KubernetesVM) Build(ccid string, metadata *persistence.ChaincodePackageMetadata, codePackage io.Reader) (container.Instance, error) {
+	name := vm.GetVMName(ccid)
+
+	// This is an awkward translation, but the old enum types are capital,
+	// while lifecycle tools allow type to be set lower case.
+	ccType := strings.ToUpper(metadata.Type)
+
+	_, err := os.Stat(filepath.Join(vm.SharedPath, name, builtMarker))
+	switch {
+	case os.IsNotExist(err):
+		err = vm.buildChaincode(ccid, ccType, metadata.Path, codePackage)
+		if err != nil {
+			return nil, errors.WithMessage(err, "kubernetes chaincode build failed")
+		}
+	case err != nil:
+		return nil, errors.WithMessage(err, "could not check for existing build output")
+	}
+
+	return &ContainerInstance{
+		KubernetesVM: vm,
+		CCID:         ccid,
+		Type:         ccType,
+	}, nil
+}
+
+func (vm *KubernetesVM) buildChaincode(ccid, ccType, path string, codePackage io.Reader) error {
+	opts, err := vm.PlatformBuilder.DockerBuildOptions(ccType, path)
+	if err != nil {
+		return errors.WithMessage(err, "platform builder failed")
+	}
+	if opts.Image == "" {
+		opts.Image = platformutil.GetDockerImageFromConfig("chaincode.builder")
+		if opts.Image == "" {
+			return errors.New("no image provided and \"chaincode.builder\" default does not exist")
+		}
+	}
+
+	name := vm.GetVMName(ccid)
+	dir := filepath.Join(vm.SharedPath, name)
+	if err := os.RemoveAll(dir); err != nil {
+		return errors.WithMessage(err, "could not remove previous build directory")
+	}
+	for _, d := range []string{"input", "output"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			return errors.WithMessage(err, "could not create build directory")
+		}
+	}
+	if err := externalbuilder.Untar(codePackage, filepath.Join(dir, "input")); err != nil {
+		return errors.WithMessage(err, "could not untar source package")
+	}
+
+	var env []EnvVar
+	for _, e := range opts.Env {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) == 2 {
+			env = append(env, EnvVar{Name: kv[0], Value: kv[1]})
+		}
+	}
+
+	jobName := name + buildJobSuffix
+	backoffLimit := int32(0)
+	job := &Job{
+		Metadata: ObjectMeta{Name: jobName, Labels: vm.labels(name)},
+		Spec: JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: PodTemplateSpec{
+				Metadata: ObjectMeta{Labels: vm.labels(name)},
+				Spec: PodSpec{
+					RestartPolicy: "Never",
+					Containers: []Container{{
+						Name:    containerName,
+						Image:   opts.Image,
+						Command: []string{"/bin/sh", "-c", opts.Cmd},
+						Env:     env,
+						VolumeMounts: []VolumeMount{{
+							Name:      volumeName,
+							MountPath: chaincodeRoot,
+							SubPath:   name,
+						}},
+					}},
+					Volumes: vm.volumes(),
+				},
+			},
+		},
+	}
+
+	// a job left behind by an interrupted build prevents creating a new one
+	vm.Client.DeleteJob(jobName)
+
+	startTime := time.Now()
+	err = vm.runJob(job)
+
+	vm.BuildMetrics.ChaincodeBuildDuration.With(
+		"chaincode", ccid,
+		"success", strconv.FormatBool(err == nil),
+	).Observe(time.Since(startTime).Seconds())
+
+	if err != nil {
+		kubernetesLogger.Errorf("Error building chaincode: %s", err)
+		kubernetesLogger.Errorf("Build Output:\n********************\n%s\n********************", vm.jobOutput(jobName))
+	}
+
+	deleteErr := vm.Client.DeleteJob(jobName)
+	kubernetesLogger.Debugw("delete build job result", "job", jobName, "error", deleteErr)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, builtMarker), nil, 0644); err != nil {
+		return errors.WithMessage(err, "could not record build output")
+	}
+
+	kubernetesLogger.Debugf("Built chaincode %s with job %s", ccid, jobName)
+	return nil
+}
+
+func (vm *KubernetesVM) runJob(job *Job) error {
+	name := job.Metadata.Name
+	if err := vm.Client.CreateJob(job); err != nil {
+		return errors.WithMessagef(err, "could not create build job %s", name)
+	}
+
+	deadline := time.Now().Add(vm.buildTimeout())
+	for {
+		j, err := vm.Client.GetJob(name)
+		if err != nil {
+			return errors.WithMessagef(err, "could not get build job %s", name)
+		}
+		switch {
+		case j.Status.Succeeded > 0:
+			return nil
+		case j.Status.Failed > 0:
+			return errors.Errorf("build job %s failed", name)
+		case time.Now().After(deadline):
+			return errors.Errorf("build job %s did not complete within %s", name, vm.buildTimeout())
+		}
+		time.Sleep(vm.pollInterval())
+	}
+}
+
+// jobOutput collects the output of the pods of a job for diagnostics.
+func (vm *KubernetesVM) jobOutput(jobName string) string {
+	pods, err := vm.Client.ListPods("job-name=" + jobName)
+	if err != nil {
+		return fmt.Sprintf("could not list pods of job %s: %s", jobName, err)
+	}
+
+	var output []string
+	for _, pod := range pods {
+		logs, err := vm.Client.PodLogs(pod.Metadata.Name, false)
+		if err != nil {
+			output = append(output, fmt.Sprintf("could not get logs of pod %s: %s", pod.Metadata.Name, err))
+			continue
+		}
+		b, err := ioutil.ReadAll(logs)
+		logs.Close()
+		if err != nil {
+			output = append(output, fmt.Sprintf("could not read logs of pod %s: %s", pod.Metadata.Name, err))
+		}
+		output = append(output, string(b))
+	}
+	return strings.Join(output, "\n")
+}
+
+// In order to support starting chaincode built with Fabric v1.4 and earlier,
+// we must check for the precense of the start.sh script for Node.js chaincode before
+// attempting to call it.
+var nodeStartScript = `
+set -e
+if [ -x /chaincode/start.sh ]; then
+	/chaincode/start.sh --peer.address %[1]s
+else
+	cd /usr/local/src
+	npm start -- --peer.address %[1]s
+fi
+`
+
+func (vm *KubernetesVM) GetArgs(ccType string, peerAddress string) ([]string, error) {
+	switch ccType {
+	case pb.ChaincodeSpec_GOLANG.String(), pb.ChaincodeSpec_CAR.String():
+		return []string{"chaincode", fmt.Sprintf("-peer.address=%s", peerAddress)}, nil
+	case pb.ChaincodeSpec_JAVA.String():
+		return []string{"/root/chaincode-java/start", "--peerAddress", peerAddress}, nil
+	case pb.ChaincodeSpec_NODE.String():
+		return []string{"/bin/sh", "-c", fmt.Sprintf(nodeStartScript, peerAddress)}, nil
+	default:
+		return nil, errors.Errorf("unknown chaincodeType: %s", ccType)
+	}
+}
+
+func (vm *KubernetesVM) GetEnv(ccid string, tlsConfig *ccintf.TLSConfig) []EnvVar {
+	// CORE_CHAINCODE_ID_NAME carries the package ID, see FAB-14630
+	envs := []string{fmt.Sprintf("CORE_CHAINCODE_ID_NAME=%s", ccid)}
+	envs = append(envs, vm.LoggingEnv...)
+
+	if tlsConfig != nil {
+		envs = append(envs, "CORE_PEER_TLS_ENABLED=true")
+		envs = append(envs, fmt.Sprintf("CORE_TLS_CLIENT_KEY_PATH=%s", TLSClientKeyPath))
+		envs = append(envs, fmt.Sprintf("CORE_TLS_CLIENT_CERT_PATH=%s", TLSClientCertPath))
+		envs = append(envs, fmt.Sprintf("CORE_TLS_CLIENT_KEY_FILE=%s", TLSClientKeyFile))
+		envs = append(envs, fmt.Sprintf("CORE_TLS_CLIENT_CERT_FILE=%s", TLSClientCertFile))
+		envs = append(envs, fmt.Sprintf("CORE_PEER_TLS_ROOTCERT_FILE=%s", TLSClientRootCertFile))
+	} else {
+		envs = append(envs, "CORE_PEER_TLS_ENABLED=false")
+	}
+
+	envs = append(envs, fmt.Sprintf("CORE_PEER_LOCALMSPID=%s", vm.MSPID))
+
+	var envVars []EnvVar
+	for _, e := range envs {
+		kv := strings.SplitN(e, "=", 2)
+		envVars = append(envVars, EnvVar{Name: kv[0], Value: kv[1]})
+	}
+	return envVars
+}
+
+// runtime returns the image chaincode of ccType runs in and the directory
+// the build output is installed to, as described by the platform Dockerfile.
+func (vm *KubernetesVM) runtime(ccType string) (image, installDir string, err error) {
+	dockerfile, err := vm.PlatformBuilder.GenerateDockerfile(ccType)
+	if err != nil {
+		return "", "", errors.WithMessage(err, "platform builder failed")
+	}
+
+	for _, line := range strings.Split(dockerfile, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 2 && fields[0] == "FROM":
+			image = fields[1]
+		case len(fields) == 3 && fields[0] == "ADD" && fields[1] == "binpackage.tar":
+			installDir = fields[2]
+		}
+	}
+	if image == "" || installDir == "" {
+		return "", "", errors.Errorf("could not determine runtime image and install directory for chaincode type %s", ccType)
+	}
+
+	return image, installDir, nil
+}
+
+// Start creates a chaincode pod running the build output of the chaincode
+func (vm *KubernetesVM) Start(ccid string, ccType string, peerConnection *ccintf.PeerConnection) error {
+	podName := vm.GetVMName(ccid)
+	logger := kubernetesLogger.With("podName", podName)
+
+	if err := vm.stopInternal(podName); err != nil {
+		return errors.WithMessagef(err, "could not remove previous pod %s", podName)
+	}
+
+	image, installDir, err := vm.runtime(ccType)
+	if err != nil {
+		return err
+	}
+
+	args, err := vm.GetArgs(ccType, peerConnection.Address)
+	if err != nil {
+		return errors.WithMessage(err, "could not get args")
+	}
+	logger.Debugf("start pod with args: %s", strings.Join(args, " "))
+
+	mounts := []VolumeMount{{
+		Name:      volumeName,
+		MountPath: installDir,
+		SubPath:   path.Join(podName, "output"),
+		ReadOnly:  true,
+	}}
+
+	// Remove the TLS files written to the shared volume by earlier versions of the
+	// peer, as the volume is mounted by every build job and chaincode pod.
+	if err := os.RemoveAll(filepath.Join(vm.SharedPath, podName, "tls")); err != nil {
+		return errors.WithMessage(err, "could not remove previous TLS files")
+	}
+
+	volumes := vm.volumes()
+	// the TLS files are handed to the pod in a secret, which only the pod mounts
+	if peerConnection.TLSConfig != nil {
+		// Note, we goofily base64 encode 2 of the TLS artifacts but not the other for strange historical reasons
+		files := map[string][]byte{
+			TLSClientKeyPath:      []byte(base64.StdEncoding.EncodeToString(peerConnection.TLSConfig.ClientKey)),
+			TLSClientCertPath:     []byte(base64.StdEncoding.EncodeToString(peerConnection.TLSConfig.ClientCert)),
+			TLSClientKeyFile:      peerConnection.TLSConfig.ClientKey,
+			TLSClientCertFile:     peerConnection.TLSConfig.ClientCert,
+			TLSClientRootCertFile: peerConnection.TLSConfig.RootCert,
+		}
+		secret := &Secret{
+			Metadata: ObjectMeta{Name: tlsSecretName(podName), Labels: vm.labels(podName)},
+			Data:     map[string][]byte{},
+		}
+		for name, contents := range files {
+			secret.Data[path.Base(name)] = contents
+		}
+		if err := vm.Client.CreateSecret(secret); err != nil {
+			return errors.WithMessagef(err, "could not create TLS secret %s", secret.Metadata.Name)
+		}
+
+		mounts = append(mounts, VolumeMount{
+			Name:      tlsVolumeName,
+			MountPath: path.Dir(TLSClientKeyPath),
+			ReadOnly:  true,
+		})
+		volumes = append(volumes, Volume{
+			Name:   tlsVolumeName,
+			Secret: &SecretVolumeSource{SecretName: secret.Metadata.Name},
+		})
+	}
+
+	pod := &Pod{
+		Metadata: ObjectMeta{Name: podName, Labels: vm.labels(podName)},
+		Spec: PodSpec{
+			RestartPolicy: "Never",
+			Containers: []Container{{
+				Name:         containerName,
+				Image:        image,
+				Command:      args,
+				Env:          vm.GetEnv(ccid, peerConnection.TLSConfig),
+				VolumeMounts: mounts,
+			}},
+			Volumes: volumes,
+		},
+	}
+
+	if err := vm.Client.CreatePod(pod); err != nil {
+		logger.Errorf("create pod failed: %s", err)
+		return err
+	}
+
+	// stream the pod output to the chaincode logger
+	if vm.AttachStdOut {
+		podLogger := flogging.MustGetLogger("peer.chaincode." + podName)
+		go streamOutput(kubernetesLogger, vm.Client, podName, podLogger, vm.pollInterval())
+	}
+
+	logger.Debugf("Started pod %s", podName)
+	return nil
+}
+
+// streamOutput mirrors output from the named pod to a fabric logger once the
+// pod started running.
+func streamOutput(logger *flogging.FabricLogger, client Client, podName string, podLogger *flogging.FabricLogger, pollInterval time.Duration) {
+	for {
+		pod, err := client.GetPod(podName)
+		if err != nil {
+			logger.Errorf("Could not get pod %s to stream its output: %s", podName, err)
+			return
+		}
+		if pod.Status.Phase != "" && pod.Status.Phase != PodPending {
+			break
+		}
+		time.Sleep(pollInterval)
+	}
+
+	logs, err := client.PodLogs(podName, true)
+	if err != nil {
+		logger.Errorf("Could not stream output of pod %s: %s", podName, err)
+		return
+	}
+	defer logs.Close()
+
+	is := bufio.NewReader(logs)
+	for {
+		// Loop forever dumping lines of text into the podLogger
+		// until the stream is closed
+		line, err := is.ReadString('\n')
+		if len(line) > 0 {
+			podLogger.Info(line)
+		}
+		switch err {
+		case nil:
+		case io.EOF:
+			logger.Infof("Pod %s has closed its IO channel", podName)
+			return
+		default:
+			logger.Errorf("Error reading pod output: %s", err)
+			return
+		}
+	}
+}
+
+// Stop deletes the pod of a running chaincode
+func (vm *KubernetesVM) Stop(ccid string) error {
+	return vm.stopInternal(vm.GetVMName(ccid))
+}
+
+// Wait blocks until the pod terminates and returns the exit code of the
+// chaincode container.
+func (vm *KubernetesVM) Wait(ccid string) (int, error) {
+	podName := vm.GetVMName(ccid)
+	for {
+		pod, err := vm.Client.GetPod(podName)
+		if err != nil {
+			return 0, errors.WithMessagef(err, "could not get pod %s", podName)
+		}
+		if pod.Status.Phase == PodSucceeded || pod.Status.Phase == PodFailed {
+			for _, status := range pod.Status.ContainerStatuses {
+				if status.Name == containerName && status.State.Terminated != nil {
+					return status.State.Terminated.ExitCode, nil
+				}
+			}
+			return 0, errors.Errorf("pod %s terminated without a container exit code", podName)
+		}
+		time.Sleep(vm.pollInterval())
+	}
+}
+
+func (vm *KubernetesVM) stopInternal(podName string) error {
+	logger := kubernetesLogger.With("podName", podName)
+
+	logger.Debugw("deleting TLS secret")
+	err := vm.Client.DeleteSecret(tlsSecretName(podName))
+	logger.Debugw("delete TLS secret result", "error", err)
+	if err != nil && errors.Cause(err) != ErrNotFound {
+		return errors.WithMessage(err, "could not delete TLS secret")
+	}
+
+	logger.Debugw("deleting pod")
+	err = vm.Client.DeletePod(podName)
+	logger.Debugw("delete pod result", "error", err)
+	if errors.Cause(err) == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// pods are deleted asynchronously; wait for the name to be released
+	deadline := time.Now().Add(podDeletionTimeout)
+	for {
+		_, err := vm.Client.GetPod(podName)
+		if errors.Cause(err) == ErrNotFound {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("pod %s was not deleted within %s", podName, podDeletionTimeout)
+		}
+		time.Sleep(vm.pollInterval())
+	}
+}
+
+// tlsSecretName returns the name of the secret holding the TLS files of a pod
+func tlsSecretName(podName string) string {
+	return podName + tlsSecretSuffix
+}
+
+func (vm *KubernetesVM) labels(name string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/managed-by": "hyperledger-fabric-peer",
+		"fabric-chaincode":             name,
+	}
+}
+
+func (vm *KubernetesVM) volumes() []Volume {
+	return []Volume{{
+		Name:                  volumeName,
+		PersistentVolumeClaim: &PersistentVolumeClaimVolumeSource{ClaimName: vm.ClaimName},
+	}}
+}
+
+func (vm *KubernetesVM) buildTimeout() time.Duration {
+	if vm.BuildTimeout == 0 {
+		return defaultBuildTimeout
+	}
+	return vm.BuildTimeout
+}
+
+func (vm *KubernetesVM) pollInterval() time.Duration {
+	if vm.PollInterval == 0 {
+		return defaultPollInterval
+	}
+	return vm.PollInterval
+}
+
+// GetVMName generates the name of the pod of a chaincode from peer
+// information. Kubernetes names are lower case DNS labels, so the sanitized
+// name is truncated and suffixed with a hash of the unsanitized name to keep
+// it unique.
+func (vm *KubernetesVM) GetVMName(ccid string) string {
+	name := ccid
+	if vm.NetworkID != "" && vm.PeerID != "" {
+		name = fmt.Sprintf("%s-%s-%s", vm.NetworkID, vm.PeerID, name)
+	} else if vm.NetworkID != "" {
+		name = fmt.Sprintf("%s-%s", vm.NetworkID, name)
+	} else if vm.PeerID != "" {
+		name = fmt.Sprintf("%s-%s", vm.PeerID, name)
+	}
+
+	hash := hex.EncodeToString(util.ComputeSHA256([]byte(name)))[:nameHashLength]
+	saniName := strings.Trim(nameRegExp.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if maxLen := maxNameLength - nameHashLength - 1; len(saniName) > maxLen {
+		saniName = strings.TrimRight(saniName[:maxLen], "-")
+	}
+	if saniName == "" {
+		return "cc-" + hash
+	}
+
+	return fmt.Sprintf("%s-%s", saniName, hash)
+}
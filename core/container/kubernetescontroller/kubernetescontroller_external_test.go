@@ -0,0 +1,203 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kubernetescontroller_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osdi23p228/fabric/common/metrics/disabled"
+	"github.com/osdi23p228/fabric/core/chaincode/persistence"
+	platformutil "github.com/osdi23p228/fabric/core/chaincode/platforms/util"
+	"github.com/osdi23p228/fabric/core/container/ccintf"
+	. "github.com/osdi23p228/fabric/core/container/kubernetescontroller"
+	"github.com/osdi23p228/fabric/core/container/kubernetescontroller/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPlatformBuilder() *mock.PlatformBuilder {
+	platformBuilder := &mock.PlatformBuilder{}
+	platformBuilder.DockerBuildOptionsReturns(platformutil.DockerBuildOptions{
+		Image: "hyperledger/fabric-ccenv:latest",
+		Cmd:   "go build -o /chaincode/output/chaincode",
+	}, nil)
+	platformBuilder.GenerateDockerfileReturns("FROM hyperledger/fabric-baseos:latest\nADD binpackage.tar /usr/local/bin", nil)
+	return platformBuilder
+}
+
+// emptyCodePackage is a gzipped tar without entries.
+func emptyCodePackage(t *testing.T) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	require.NoError(t, tar.NewWriter(gw).Close())
+	require.NoError(t, gw.Close())
+	return buf
+}
+
+func TestBuildFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(*mock.Client, *mock.PlatformBuilder)
+		pkg     func() *bytes.Buffer
+		timeout time.Duration
+		errMsg  string
+	}{
+		{
+			name: "platform builder failure",
+			setup: func(_ *mock.Client, pb *mock.PlatformBuilder) {
+				pb.DockerBuildOptionsReturns(platformutil.DockerBuildOptions{}, errors.New("unknown platform"))
+			},
+			errMsg: "kubernetes chaincode build failed: platform builder failed: unknown platform",
+		},
+		{
+			name: "malformed package",
+			pkg: func() *bytes.Buffer {
+				return bytes.NewBufferString("not a tar")
+			},
+			errMsg: "kubernetes chaincode build failed: could not untar source package: unexpected EOF",
+		},
+		{
+			name: "create job failure",
+			setup: func(c *mock.Client, _ *mock.PlatformBuilder) {
+				c.CreateJobReturns(errors.New("quota exceeded"))
+			},
+			errMsg: "kubernetes chaincode build failed: could not create build job %s-build: quota exceeded",
+		},
+		{
+			name: "get job failure",
+			setup: func(c *mock.Client, _ *mock.PlatformBuilder) {
+				c.GetJobReturns(nil, errors.New("connection refused"))
+			},
+			errMsg: "kubernetes chaincode build failed: could not get build job %s-build: connection refused",
+		},
+		{
+			name: "build timeout",
+			setup: func(c *mock.Client, _ *mock.PlatformBuilder) {
+				c.GetJobReturns(&Job{}, nil)
+			},
+			timeout: 50 * time.Millisecond,
+			errMsg:  "kubernetes chaincode build failed: build job %s-build did not complete within 50ms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sharedPath, err := ioutil.TempDir("", "kubernetescontroller")
+			require.NoError(t, err)
+			defer os.RemoveAll(sharedPath)
+
+			client := &mock.Client{}
+			platformBuilder := newPlatformBuilder()
+			if tt.setup != nil {
+				tt.setup(client, platformBuilder)
+			}
+			pkg := emptyCodePackage(t)
+			if tt.pkg != nil {
+				pkg = tt.pkg()
+			}
+			vm := &KubernetesVM{
+				BuildMetrics:    NewBuildMetrics(&disabled.Provider{}),
+				Client:          client,
+				PlatformBuilder: platformBuilder,
+				SharedPath:      sharedPath,
+				BuildTimeout:    tt.timeout,
+				PollInterval:    10 * time.Millisecond,
+			}
+
+			_, err = vm.Build("mycc:1234", &persistence.ChaincodePackageMetadata{Type: "golang"}, pkg)
+			errMsg := tt.errMsg
+			if strings.Contains(errMsg, "%s") {
+				errMsg = strings.Replace(errMsg, "%s", vm.GetVMName("mycc:1234"), 1)
+			}
+			assert.EqualError(t, err, errMsg)
+		})
+	}
+}
+
+func TestStartFailures(t *testing.T) {
+	peerConnection := &ccintf.PeerConnection{Address: "peer0:7052"}
+
+	t.Run("previous pod cannot be deleted", func(t *testing.T) {
+		client := &mock.Client{}
+		client.DeletePodReturns(errors.New("forbidden"))
+		vm := &KubernetesVM{Client: client, PlatformBuilder: newPlatformBuilder()}
+		err := vm.Start("mycc:1234", "GOLANG", peerConnection)
+		assert.EqualError(t, err, "could not remove previous pod "+vm.GetVMName("mycc:1234")+": forbidden")
+	})
+
+	t.Run("previous TLS secret cannot be deleted", func(t *testing.T) {
+		client := &mock.Client{}
+		client.DeleteSecretReturns(errors.New("forbidden"))
+		vm := &KubernetesVM{Client: client, PlatformBuilder: newPlatformBuilder()}
+		err := vm.Start("mycc:1234", "GOLANG", peerConnection)
+		assert.EqualError(t, err, "could not remove previous pod "+vm.GetVMName("mycc:1234")+": could not delete TLS secret: forbidden")
+	})
+
+	t.Run("unknown runtime", func(t *testing.T) {
+		client := &mock.Client{}
+		client.DeletePodReturns(ErrNotFound)
+		platformBuilder := newPlatformBuilder()
+		platformBuilder.GenerateDockerfileReturns("FROM scratch", nil)
+		vm := &KubernetesVM{Client: client, PlatformBuilder: platformBuilder}
+		err := vm.Start("mycc:1234", "GOLANG", peerConnection)
+		assert.EqualError(t, err, "could not determine runtime image and install directory for chaincode type GOLANG")
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		client := &mock.Client{}
+		client.DeletePodReturns(ErrNotFound)
+		vm := &KubernetesVM{Client: client, PlatformBuilder: newPlatformBuilder()}
+		err := vm.Start("mycc:1234", "FOO", peerConnection)
+		assert.EqualError(t, err, "could not get args: unknown chaincodeType: FOO")
+	})
+
+	t.Run("create TLS secret failure", func(t *testing.T) {
+		client := &mock.Client{}
+		client.DeletePodReturns(ErrNotFound)
+		client.CreateSecretReturns(errors.New("quota exceeded"))
+		vm := &KubernetesVM{Client: client, PlatformBuilder: newPlatformBuilder()}
+		err := vm.Start("mycc:1234", "GOLANG", &ccintf.PeerConnection{Address: "peer0:7052", TLSConfig: &ccintf.TLSConfig{}})
+		assert.EqualError(t, err, "could not create TLS secret "+vm.GetVMName("mycc:1234")+"-tls: quota exceeded")
+		assert.Equal(t, 0, client.CreatePodCallCount())
+	})
+
+	t.Run("create pod failure", func(t *testing.T) {
+		client := &mock.Client{}
+		client.DeletePodReturns(ErrNotFound)
+		client.CreatePodReturns(errors.New("quota exceeded"))
+		vm := &KubernetesVM{Client: client, PlatformBuilder: newPlatformBuilder()}
+		err := vm.Start("mycc:1234", "GOLANG", peerConnection)
+		assert.EqualError(t, err, "quota exceeded")
+	})
+}
+
+func TestWaitWithoutExitCode(t *testing.T) {
+	client := &mock.Client{}
+	client.GetPodReturns(&Pod{Status: PodStatus{Phase: PodFailed}}, nil)
+	vm := &KubernetesVM{Client: client}
+	_, err := vm.Wait("mycc:1234")
+	assert.EqualError(t, err, "pod "+vm.GetVMName("mycc:1234")+" terminated without a container exit code")
+}
+
+func TestHealthCheck(t *testing.T) {
+	client := &mock.Client{}
+	vm := &KubernetesVM{Client: client}
+	assert.NoError(t, vm.HealthCheck(context.Background()))
+
+	client.PingReturns(errors.New("connection refused"))
+	err := vm.HealthCheck(context.Background())
+	assert.EqualError(t, err, "failed to ping the Kubernetes API server: connection refused")
+}
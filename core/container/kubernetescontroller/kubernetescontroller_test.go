@@ -0,0 +1,279 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kubernetescontroller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/osdi23p228/fabric/common/flogging"
+	"github.com/osdi23p228/fabric/common/flogging/floggingtest"
+	"github.com/osdi23p228/fabric/common/metrics/metricsfakes"
+	"github.com/osdi23p228/fabric/core/chaincode/persistence"
+	platformutil "github.com/osdi23p228/fabric/core/chaincode/platforms/util"
+	"github.com/osdi23p228/fabric/core/container/ccintf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const golangDockerfile = `FROM hyperledger/fabric-baseos:latest
+ADD binpackage.tar /usr/local/bin
+LABEL org.hyperledger.fabric.chaincode.type="GOLANG" \
+      org.hyperledger.fabric.version="2.2.0"
+ENV CORE_CHAINCODE_BUILDLEVEL=2.2.0`
+
+// fakePlatformBuilder describes the golang platform and records the
+// requested build options.
+type fakePlatformBuilder struct {
+	buildOptionsCalls []string
+}
+
+func (f *fakePlatformBuilder) GenerateDockerfile(ccType string) (string, error) {
+	return golangDockerfile, nil
+}
+
+func (f *fakePlatformBuilder) DockerBuildOptions(ccType, path string) (platformutil.DockerBuildOptions, error) {
+	f.buildOptionsCalls = append(f.buildOptionsCalls, ccType+" "+path)
+	return platformutil.DockerBuildOptions{
+		Image: "hyperledger/fabric-ccenv:latest",
+		Cmd:   "go build -o /chaincode/output/chaincode",
+		Env:   []string{"GOPROXY=https://proxy.golang.org", "GO111MODULE=on"},
+	}, nil
+}
+
+func codePackage(t *testing.T) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	contents := []byte("package main")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "src/main.go", Mode: 0644, Size: int64(len(contents))}))
+	_, err := tw.Write(contents)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf
+}
+
+func TestBuildStartWaitStop(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	server := newFakeAPIServer("fabric")
+	defer server.Close()
+
+	sharedPath, err := ioutil.TempDir("", "kubernetescontroller")
+	require.NoError(t, err)
+	defer os.RemoveAll(sharedPath)
+
+	fakeBuildDuration := &metricsfakes.Histogram{}
+	fakeBuildDuration.WithReturns(fakeBuildDuration)
+	platformBuilder := &fakePlatformBuilder{}
+	vm := &KubernetesVM{
+		PeerID:          "peer0",
+		NetworkID:       "dev",
+		BuildMetrics:    &BuildMetrics{ChaincodeBuildDuration: fakeBuildDuration},
+		Client:          &RESTClient{URL: server.URL, Namespace: "fabric"},
+		PlatformBuilder: platformBuilder,
+		LoggingEnv:      []string{"CORE_CHAINCODE_LOGGING_LEVEL=info"},
+		MSPID:           "Org1MSP",
+		ClaimName:       "chaincode-claim",
+		SharedPath:      sharedPath,
+		PollInterval:    10 * time.Millisecond,
+	}
+	podName := vm.GetVMName("mycc:1234")
+
+	instance, err := vm.Build("mycc:1234", &persistence.ChaincodePackageMetadata{Type: "golang", Path: "github.com/mycc"}, codePackage(t))
+	require.NoError(t, err)
+	assert.Equal(t, &ContainerInstance{CCID: "mycc:1234", Type: "GOLANG", KubernetesVM: vm}, instance)
+
+	assert.Equal(t, []string{"GOLANG github.com/mycc"}, platformBuilder.buildOptionsCalls)
+	assert.FileExists(t, filepath.Join(sharedPath, podName, "input", "src", "main.go"))
+	assert.DirExists(t, filepath.Join(sharedPath, podName, "output"))
+	assert.FileExists(t, filepath.Join(sharedPath, podName, builtMarker))
+	assert.Empty(t, server.jobs, "build job should be deleted")
+	assert.Empty(t, server.pods, "build pod should be deleted")
+
+	require.Equal(t, 1, fakeBuildDuration.WithCallCount())
+	assert.Equal(t, []string{"chaincode", "mycc:1234", "success", "true"}, fakeBuildDuration.WithArgsForCall(0))
+	require.Equal(t, 1, fakeBuildDuration.ObserveCallCount())
+
+	// the build output on the shared volume is reused
+	_, err = vm.Build("mycc:1234", &persistence.ChaincodePackageMetadata{Type: "golang", Path: "github.com/mycc"}, codePackage(t))
+	require.NoError(t, err)
+	assert.Len(t, platformBuilder.buildOptionsCalls, 1)
+
+	err = instance.Start(&ccintf.PeerConnection{
+		Address: "peer0:7052",
+		TLSConfig: &ccintf.TLSConfig{
+			ClientKey:  []byte("client-key"),
+			ClientCert: []byte("client-cert"),
+			RootCert:   []byte("root-cert"),
+		},
+	})
+	require.NoError(t, err)
+
+	pod := server.getPod(podName)
+	require.NotNil(t, pod)
+	assert.Equal(t, "Never", pod.Spec.RestartPolicy)
+	assert.Equal(t, []Volume{
+		{Name: "chaincode", PersistentVolumeClaim: &PersistentVolumeClaimVolumeSource{ClaimName: "chaincode-claim"}},
+		{Name: "tls", Secret: &SecretVolumeSource{SecretName: podName + "-tls"}},
+	}, pod.Spec.Volumes)
+	require.Len(t, pod.Spec.Containers, 1)
+	c := pod.Spec.Containers[0]
+	assert.Equal(t, "hyperledger/fabric-baseos:latest", c.Image)
+	assert.Equal(t, []string{"chaincode", "-peer.address=peer0:7052"}, c.Command)
+	assert.Contains(t, c.Env, EnvVar{Name: "CORE_CHAINCODE_ID_NAME", Value: "mycc:1234"})
+	assert.Contains(t, c.Env, EnvVar{Name: "CORE_PEER_TLS_ENABLED", Value: "true"})
+	assert.Contains(t, c.Env, EnvVar{Name: "CORE_PEER_LOCALMSPID", Value: "Org1MSP"})
+	assert.Equal(t, []VolumeMount{
+		{Name: "chaincode", MountPath: "/usr/local/bin", SubPath: podName + "/output", ReadOnly: true},
+		{Name: "tls", MountPath: "/etc/hyperledger/fabric", ReadOnly: true},
+	}, c.VolumeMounts)
+
+	// the TLS files are held by a secret rather than written to the shared volume
+	secret := server.getSecret(podName + "-tls")
+	require.NotNil(t, secret)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("client-key")), string(secret.Data["client.key"]))
+	assert.Equal(t, "client-key", string(secret.Data["client_pem.key"]))
+	assert.Equal(t, "root-cert", string(secret.Data["peer.crt"]))
+	assert.NoDirExists(t, filepath.Join(sharedPath, podName, "tls"))
+
+	exitCh := make(chan int, 1)
+	go func() {
+		exitCode, err := instance.Wait()
+		assert.NoError(t, err)
+		exitCh <- exitCode
+	}()
+	gt.Consistently(exitCh).ShouldNot(Receive())
+	server.terminatePod(podName, 7, "")
+	gt.Eventually(exitCh).Should(Receive(Equal(7)))
+
+	// restarting replaces the terminated pod
+	err = instance.Start(&ccintf.PeerConnection{Address: "peer0:7052"})
+	require.NoError(t, err)
+	pod = server.getPod(podName)
+	require.NotNil(t, pod)
+	assert.Equal(t, PodRunning, pod.Status.Phase)
+	assert.Contains(t, pod.Spec.Containers[0].Env, EnvVar{Name: "CORE_PEER_TLS_ENABLED", Value: "false"})
+	assert.Len(t, pod.Spec.Containers[0].VolumeMounts, 1)
+	assert.Nil(t, server.getSecret(podName+"-tls"), "TLS secret of the previous pod should be deleted")
+
+	require.NoError(t, instance.Start(&ccintf.PeerConnection{Address: "peer0:7052", TLSConfig: &ccintf.TLSConfig{}}))
+	require.NotNil(t, server.getSecret(podName+"-tls"))
+	require.NoError(t, instance.Stop())
+	assert.Nil(t, server.getPod(podName))
+	assert.Nil(t, server.getSecret(podName+"-tls"), "TLS secret should be deleted")
+	_, err = instance.Wait()
+	assert.EqualError(t, err, "could not get pod "+podName+": GET /api/v1/namespaces/fabric/pods/"+podName+": resource not found")
+
+	// stopping a stopped chaincode is not an error
+	assert.NoError(t, instance.Stop())
+}
+
+func TestBuildJobFailure(t *testing.T) {
+	server := newFakeAPIServer("fabric")
+	defer server.Close()
+	server.failJobs = true
+	server.buildLogs = "main.go:1: syntax error"
+
+	sharedPath, err := ioutil.TempDir("", "kubernetescontroller")
+	require.NoError(t, err)
+	defer os.RemoveAll(sharedPath)
+
+	fakeBuildDuration := &metricsfakes.Histogram{}
+	fakeBuildDuration.WithReturns(fakeBuildDuration)
+	vm := &KubernetesVM{
+		BuildMetrics:    &BuildMetrics{ChaincodeBuildDuration: fakeBuildDuration},
+		Client:          &RESTClient{URL: server.URL, Namespace: "fabric"},
+		PlatformBuilder: &fakePlatformBuilder{},
+		SharedPath:      sharedPath,
+		PollInterval:    10 * time.Millisecond,
+	}
+	name := vm.GetVMName("mycc:1234")
+
+	logger, recorder := floggingtest.NewTestLogger(t)
+	defer func(old *flogging.FabricLogger) { kubernetesLogger = old }(kubernetesLogger)
+	kubernetesLogger = logger
+
+	_, err = vm.Build("mycc:1234", &persistence.ChaincodePackageMetadata{Type: "golang"}, codePackage(t))
+	assert.EqualError(t, err, "kubernetes chaincode build failed: build job "+name+"-build failed")
+	assert.Contains(t, string(recorder.Buffer().Contents()), "main.go:1: syntax error")
+	assert.Equal(t, []string{"chaincode", "mycc:1234", "success", "false"}, fakeBuildDuration.WithArgsForCall(0))
+	assert.Empty(t, server.jobs, "build job should be deleted")
+	_, err = os.Stat(filepath.Join(sharedPath, name, builtMarker))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStreamOutput(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	logger, recorder := floggingtest.NewTestLogger(t)
+	podLogger, podRecorder := floggingtest.NewTestLogger(t)
+
+	server := newFakeAPIServer("fabric")
+	defer server.Close()
+	client := &RESTClient{URL: server.URL, Namespace: "fabric"}
+	require.NoError(t, client.CreatePod(&Pod{Metadata: ObjectMeta{Name: "pod-name"}}))
+	server.terminatePod("pod-name", 0, "message-one\nmessage-two")
+
+	streamOutput(logger, client, "pod-name", podLogger, time.Millisecond)
+	gt.Expect(podRecorder).To(gbytes.Say("message-one"))
+	gt.Expect(podRecorder).To(gbytes.Say("message-two"))
+	gt.Expect(podRecorder.Entries()).To(HaveLen(2))
+	gt.Expect(recorder).To(gbytes.Say("Pod pod-name has closed its IO channel"))
+
+	streamOutput(logger, client, "missing-pod", podLogger, time.Millisecond)
+	gt.Expect(recorder).To(gbytes.Say("Could not get pod missing-pod to stream its output: GET /api/v1/namespaces/fabric/pods/missing-pod: resource not found"))
+}
+
+func TestGetVMName(t *testing.T) {
+	tests := []struct {
+		name     string
+		vm       *KubernetesVM
+		ccid     string
+		expected string
+	}{
+		{
+			name:     "peer and network",
+			vm:       &KubernetesVM{PeerID: "Peer0", NetworkID: "Dev"},
+			ccid:     "mycc:1234",
+			expected: "dev-peer0-mycc-1234-",
+		},
+		{
+			name:     "ccid only",
+			vm:       &KubernetesVM{},
+			ccid:     "_my_cc_:1234",
+			expected: "my-cc--1234-",
+		},
+		{
+			name:     "long label",
+			vm:       &KubernetesVM{PeerID: "peer0"},
+			ccid:     strings.Repeat("a", 80) + ":" + strings.Repeat("0", 64),
+			expected: "peer0-" + strings.Repeat("a", 38) + "-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := tt.vm.GetVMName(tt.ccid)
+			assert.True(t, strings.HasPrefix(name, tt.expected), "%s does not start with %s", name, tt.expected)
+			assert.Len(t, name, len(tt.expected)+nameHashLength)
+			assert.LessOrEqual(t, len(name+buildJobSuffix), 63)
+			assert.Regexp(t, "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$", name)
+		})
+	}
+
+	assert.NotEqual(t, (&KubernetesVM{}).GetVMName("my_cc:1"), (&KubernetesVM{}).GetVMName("my.cc:1"))
+}
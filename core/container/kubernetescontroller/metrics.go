@@ -0,0 +1,29 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kubernetescontroller
+
+import "github.com/osdi23p228/fabric/common/metrics"
+
+var (
+	chaincodeBuildDuration = metrics.HistogramOpts{
+		Namespace:    "kubernetescontroller",
+		Name:         "chaincode_build_duration",
+		Help:         "The time to build chaincode with a build job in seconds.",
+		LabelNames:   []string{"chaincode", "success"},
+		StatsdFormat: "%{#fqname}.%{chaincode}.%{success}",
+	}
+)
+
+type BuildMetrics struct {
+	ChaincodeBuildDuration metrics.Histogram
+}
+
+func NewBuildMetrics(p metrics.Provider) *BuildMetrics {
+	return &BuildMetrics{
+		ChaincodeBuildDuration: p.NewHistogram(chaincodeBuildDuration),
+	}
+}
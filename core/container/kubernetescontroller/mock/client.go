@@ -0,0 +1,875 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/osdi23p228/fabric/core/container/kubernetescontroller"
+)
+
+type Client struct {
+	CreateJobStub        func(*kubernetescontroller.Job) error
+	createJobMutex       sync.RWMutex
+	createJobArgsForCall []struct {
+		arg1 *kubernetescontroller.Job
+	}
+	createJobReturns struct {
+		result1 error
+	}
+	createJobReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreatePodStub        func(*kubernetescontroller.Pod) error
+	createPodMutex       sync.RWMutex
+	createPodArgsForCall []struct {
+		arg1 *kubernetescontroller.Pod
+	}
+	createPodReturns struct {
+		result1 error
+	}
+	createPodReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateSecretStub        func(*kubernetescontroller.Secret) error
+	createSecretMutex       sync.RWMutex
+	createSecretArgsForCall []struct {
+		arg1 *kubernetescontroller.Secret
+	}
+	createSecretReturns struct {
+		result1 error
+	}
+	createSecretReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteJobStub        func(string) error
+	deleteJobMutex       sync.RWMutex
+	deleteJobArgsForCall []struct {
+		arg1 string
+	}
+	deleteJobReturns struct {
+		result1 error
+	}
+	deleteJobReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeletePodStub        func(string) error
+	deletePodMutex       sync.RWMutex
+	deletePodArgsForCall []struct {
+		arg1 string
+	}
+	deletePodReturns struct {
+		result1 error
+	}
+	deletePodReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteSecretStub        func(string) error
+	deleteSecretMutex       sync.RWMutex
+	deleteSecretArgsForCall []struct {
+		arg1 string
+	}
+	deleteSecretReturns struct {
+		result1 error
+	}
+	deleteSecretReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetJobStub        func(string) (*kubernetescontroller.Job, error)
+	getJobMutex       sync.RWMutex
+	getJobArgsForCall []struct {
+		arg1 string
+	}
+	getJobReturns struct {
+		result1 *kubernetescontroller.Job
+		result2 error
+	}
+	getJobReturnsOnCall map[int]struct {
+		result1 *kubernetescontroller.Job
+		result2 error
+	}
+	GetPodStub        func(string) (*kubernetescontroller.Pod, error)
+	getPodMutex       sync.RWMutex
+	getPodArgsForCall []struct {
+		arg1 string
+	}
+	getPodReturns struct {
+		result1 *kubernetescontroller.Pod
+		result2 error
+	}
+	getPodReturnsOnCall map[int]struct {
+		result1 *kubernetescontroller.Pod
+		result2 error
+	}
+	ListPodsStub        func(string) ([]kubernetescontroller.Pod, error)
+	listPodsMutex       sync.RWMutex
+	listPodsArgsForCall []struct {
+		arg1 string
+	}
+	listPodsReturns struct {
+		result1 []kubernetescontroller.Pod
+		result2 error
+	}
+	listPodsReturnsOnCall map[int]struct {
+		result1 []kubernetescontroller.Pod
+		result2 error
+	}
+	PingStub        func(context.Context) error
+	pingMutex       sync.RWMutex
+	pingArgsForCall []struct {
+		arg1 context.Context
+	}
+	pingReturns struct {
+		result1 error
+	}
+	pingReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PodLogsStub        func(string, bool) (io.ReadCloser, error)
+	podLogsMutex       sync.RWMutex
+	podLogsArgsForCall []struct {
+		arg1 string
+		arg2 bool
+	}
+	podLogsReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	podLogsReturnsOnCall map[int]struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Client) CreateJob(arg1 *kubernetescontroller.Job) error {
+	fake.createJobMutex.Lock()
+	ret, specificReturn := fake.createJobReturnsOnCall[len(fake.createJobArgsForCall)]
+	fake.createJobArgsForCall = append(fake.createJobArgsForCall, struct {
+		arg1 *kubernetescontroller.Job
+	}{arg1})
+	stub := fake.CreateJobStub
+	fakeReturns := fake.createJobReturns
+	fake.recordInvocation("CreateJob", []interface{}{arg1})
+	fake.createJobMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Client) CreateJobCallCount() int {
+	fake.createJobMutex.RLock()
+	defer fake.createJobMutex.RUnlock()
+	return len(fake.createJobArgsForCall)
+}
+
+func (fake *Client) CreateJobCalls(stub func(*kubernetescontroller.Job) error) {
+	fake.createJobMutex.Lock()
+	defer fake.createJobMutex.Unlock()
+	fake.CreateJobStub = stub
+}
+
+func (fake *Client) CreateJobArgsForCall(i int) *kubernetescontroller.Job {
+	fake.createJobMutex.RLock()
+	defer fake.createJobMutex.RUnlock()
+	argsForCall := fake.createJobArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Client) CreateJobReturns(result1 error) {
+	fake.createJobMutex.Lock()
+	defer fake.createJobMutex.Unlock()
+	fake.CreateJobStub = nil
+	fake.createJobReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) CreateJobReturnsOnCall(i int, result1 error) {
+	fake.createJobMutex.Lock()
+	defer fake.createJobMutex.Unlock()
+	fake.CreateJobStub = nil
+	if fake.createJobReturnsOnCall == nil {
+		fake.createJobReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createJobReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) CreatePod(arg1 *kubernetescontroller.Pod) error {
+	fake.createPodMutex.Lock()
+	ret, specificReturn := fake.createPodReturnsOnCall[len(fake.createPodArgsForCall)]
+	fake.createPodArgsForCall = append(fake.createPodArgsForCall, struct {
+		arg1 *kubernetescontroller.Pod
+	}{arg1})
+	stub := fake.CreatePodStub
+	fakeReturns := fake.createPodReturns
+	fake.recordInvocation("CreatePod", []interface{}{arg1})
+	fake.createPodMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Client) CreatePodCallCount() int {
+	fake.createPodMutex.RLock()
+	defer fake.createPodMutex.RUnlock()
+	return len(fake.createPodArgsForCall)
+}
+
+func (fake *Client) CreatePodCalls(stub func(*kubernetescontroller.Pod) error) {
+	fake.createPodMutex.Lock()
+	defer fake.createPodMutex.Unlock()
+	fake.CreatePodStub = stub
+}
+
+func (fake *Client) CreatePodArgsForCall(i int) *kubernetescontroller.Pod {
+	fake.createPodMutex.RLock()
+	defer fake.createPodMutex.RUnlock()
+	argsForCall := fake.createPodArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Client) CreatePodReturns(result1 error) {
+	fake.createPodMutex.Lock()
+	defer fake.createPodMutex.Unlock()
+	fake.CreatePodStub = nil
+	fake.createPodReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) CreatePodReturnsOnCall(i int, result1 error) {
+	fake.createPodMutex.Lock()
+	defer fake.createPodMutex.Unlock()
+	fake.CreatePodStub = nil
+	if fake.createPodReturnsOnCall == nil {
+		fake.createPodReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createPodReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) CreateSecret(arg1 *kubernetescontroller.Secret) error {
+	fake.createSecretMutex.Lock()
+	ret, specificReturn := fake.createSecretReturnsOnCall[len(fake.createSecretArgsForCall)]
+	fake.createSecretArgsForCall = append(fake.createSecretArgsForCall, struct {
+		arg1 *kubernetescontroller.Secret
+	}{arg1})
+	stub := fake.CreateSecretStub
+	fakeReturns := fake.createSecretReturns
+	fake.recordInvocation("CreateSecret", []interface{}{arg1})
+	fake.createSecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Client) CreateSecretCallCount() int {
+	fake.createSecretMutex.RLock()
+	defer fake.createSecretMutex.RUnlock()
+	return len(fake.createSecretArgsForCall)
+}
+
+func (fake *Client) CreateSecretCalls(stub func(*kubernetescontroller.Secret) error) {
+	fake.createSecretMutex.Lock()
+	defer fake.createSecretMutex.Unlock()
+	fake.CreateSecretStub = stub
+}
+
+func (fake *Client) CreateSecretArgsForCall(i int) *kubernetescontroller.Secret {
+	fake.createSecretMutex.RLock()
+	defer fake.createSecretMutex.RUnlock()
+	argsForCall := fake.createSecretArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Client) CreateSecretReturns(result1 error) {
+	fake.createSecretMutex.Lock()
+	defer fake.createSecretMutex.Unlock()
+	fake.CreateSecretStub = nil
+	fake.createSecretReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) CreateSecretReturnsOnCall(i int, result1 error) {
+	fake.createSecretMutex.Lock()
+	defer fake.createSecretMutex.Unlock()
+	fake.CreateSecretStub = nil
+	if fake.createSecretReturnsOnCall == nil {
+		fake.createSecretReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createSecretReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) DeleteJob(arg1 string) error {
+	fake.deleteJobMutex.Lock()
+	ret, specificReturn := fake.deleteJobReturnsOnCall[len(fake.deleteJobArgsForCall)]
+	fake.deleteJobArgsForCall = append(fake.deleteJobArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.DeleteJobStub
+	fakeReturns := fake.deleteJobReturns
+	fake.recordInvocation("DeleteJob", []interface{}{arg1})
+	fake.deleteJobMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Client) DeleteJobCallCount() int {
+	fake.createSecretMutex.RLock()
+	defer fake.createSecretMutex.RUnlock()
+	fake.deleteJobMutex.RLock()
+	defer fake.deleteJobMutex.RUnlock()
+	return len(fake.deleteJobArgsForCall)
+}
+
+func (fake *Client) DeleteJobCalls(stub func(string) error) {
+	fake.deleteJobMutex.Lock()
+	defer fake.deleteJobMutex.Unlock()
+	fake.DeleteJobStub = stub
+}
+
+func (fake *Client) DeleteJobArgsForCall(i int) string {
+	fake.deleteJobMutex.RLock()
+	defer fake.deleteJobMutex.RUnlock()
+	argsForCall := fake.deleteJobArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Client) DeleteJobReturns(result1 error) {
+	fake.deleteJobMutex.Lock()
+	defer fake.deleteJobMutex.Unlock()
+	fake.DeleteJobStub = nil
+	fake.deleteJobReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) DeleteJobReturnsOnCall(i int, result1 error) {
+	fake.deleteJobMutex.Lock()
+	defer fake.deleteJobMutex.Unlock()
+	fake.DeleteJobStub = nil
+	if fake.deleteJobReturnsOnCall == nil {
+		fake.deleteJobReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteJobReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) DeletePod(arg1 string) error {
+	fake.deletePodMutex.Lock()
+	ret, specificReturn := fake.deletePodReturnsOnCall[len(fake.deletePodArgsForCall)]
+	fake.deletePodArgsForCall = append(fake.deletePodArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.DeletePodStub
+	fakeReturns := fake.deletePodReturns
+	fake.recordInvocation("DeletePod", []interface{}{arg1})
+	fake.deletePodMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Client) DeletePodCallCount() int {
+	fake.deletePodMutex.RLock()
+	defer fake.deletePodMutex.RUnlock()
+	return len(fake.deletePodArgsForCall)
+}
+
+func (fake *Client) DeletePodCalls(stub func(string) error) {
+	fake.deletePodMutex.Lock()
+	defer fake.deletePodMutex.Unlock()
+	fake.DeletePodStub = stub
+}
+
+func (fake *Client) DeletePodArgsForCall(i int) string {
+	fake.deletePodMutex.RLock()
+	defer fake.deletePodMutex.RUnlock()
+	argsForCall := fake.deletePodArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Client) DeletePodReturns(result1 error) {
+	fake.deletePodMutex.Lock()
+	defer fake.deletePodMutex.Unlock()
+	fake.DeletePodStub = nil
+	fake.deletePodReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) DeletePodReturnsOnCall(i int, result1 error) {
+	fake.deletePodMutex.Lock()
+	defer fake.deletePodMutex.Unlock()
+	fake.DeletePodStub = nil
+	if fake.deletePodReturnsOnCall == nil {
+		fake.deletePodReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deletePodReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) DeleteSecret(arg1 string) error {
+	fake.deleteSecretMutex.Lock()
+	ret, specificReturn := fake.deleteSecretReturnsOnCall[len(fake.deleteSecretArgsForCall)]
+	fake.deleteSecretArgsForCall = append(fake.deleteSecretArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.DeleteSecretStub
+	fakeReturns := fake.deleteSecretReturns
+	fake.recordInvocation("DeleteSecret", []interface{}{arg1})
+	fake.deleteSecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Client) DeleteSecretCallCount() int {
+	fake.deleteSecretMutex.RLock()
+	defer fake.deleteSecretMutex.RUnlock()
+	return len(fake.deleteSecretArgsForCall)
+}
+
+func (fake *Client) DeleteSecretCalls(stub func(string) error) {
+	fake.deleteSecretMutex.Lock()
+	defer fake.deleteSecretMutex.Unlock()
+	fake.DeleteSecretStub = stub
+}
+
+func (fake *Client) DeleteSecretArgsForCall(i int) string {
+	fake.deleteSecretMutex.RLock()
+	defer fake.deleteSecretMutex.RUnlock()
+	argsForCall := fake.deleteSecretArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Client) DeleteSecretReturns(result1 error) {
+	fake.deleteSecretMutex.Lock()
+	defer fake.deleteSecretMutex.Unlock()
+	fake.DeleteSecretStub = nil
+	fake.deleteSecretReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) DeleteSecretReturnsOnCall(i int, result1 error) {
+	fake.deleteSecretMutex.Lock()
+	defer fake.deleteSecretMutex.Unlock()
+	fake.DeleteSecretStub = nil
+	if fake.deleteSecretReturnsOnCall == nil {
+		fake.deleteSecretReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteSecretReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) GetJob(arg1 string) (*kubernetescontroller.Job, error) {
+	fake.getJobMutex.Lock()
+	ret, specificReturn := fake.getJobReturnsOnCall[len(fake.getJobArgsForCall)]
+	fake.getJobArgsForCall = append(fake.getJobArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetJobStub
+	fakeReturns := fake.getJobReturns
+	fake.recordInvocation("GetJob", []interface{}{arg1})
+	fake.getJobMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Client) GetJobCallCount() int {
+	fake.deleteSecretMutex.RLock()
+	defer fake.deleteSecretMutex.RUnlock()
+	fake.getJobMutex.RLock()
+	defer fake.getJobMutex.RUnlock()
+	return len(fake.getJobArgsForCall)
+}
+
+func (fake *Client) GetJobCalls(stub func(string) (*kubernetescontroller.Job, error)) {
+	fake.getJobMutex.Lock()
+	defer fake.getJobMutex.Unlock()
+	fake.GetJobStub = stub
+}
+
+func (fake *Client) GetJobArgsForCall(i int) string {
+	fake.getJobMutex.RLock()
+	defer fake.getJobMutex.RUnlock()
+	argsForCall := fake.getJobArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Client) GetJobReturns(result1 *kubernetescontroller.Job, result2 error) {
+	fake.getJobMutex.Lock()
+	defer fake.getJobMutex.Unlock()
+	fake.GetJobStub = nil
+	fake.getJobReturns = struct {
+		result1 *kubernetescontroller.Job
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Client) GetJobReturnsOnCall(i int, result1 *kubernetescontroller.Job, result2 error) {
+	fake.getJobMutex.Lock()
+	defer fake.getJobMutex.Unlock()
+	fake.GetJobStub = nil
+	if fake.getJobReturnsOnCall == nil {
+		fake.getJobReturnsOnCall = make(map[int]struct {
+			result1 *kubernetescontroller.Job
+			result2 error
+		})
+	}
+	fake.getJobReturnsOnCall[i] = struct {
+		result1 *kubernetescontroller.Job
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Client) GetPod(arg1 string) (*kubernetescontroller.Pod, error) {
+	fake.getPodMutex.Lock()
+	ret, specificReturn := fake.getPodReturnsOnCall[len(fake.getPodArgsForCall)]
+	fake.getPodArgsForCall = append(fake.getPodArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetPodStub
+	fakeReturns := fake.getPodReturns
+	fake.recordInvocation("GetPod", []interface{}{arg1})
+	fake.getPodMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Client) GetPodCallCount() int {
+	fake.getPodMutex.RLock()
+	defer fake.getPodMutex.RUnlock()
+	return len(fake.getPodArgsForCall)
+}
+
+func (fake *Client) GetPodCalls(stub func(string) (*kubernetescontroller.Pod, error)) {
+	fake.getPodMutex.Lock()
+	defer fake.getPodMutex.Unlock()
+	fake.GetPodStub = stub
+}
+
+func (fake *Client) GetPodArgsForCall(i int) string {
+	fake.getPodMutex.RLock()
+	defer fake.getPodMutex.RUnlock()
+	argsForCall := fake.getPodArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Client) GetPodReturns(result1 *kubernetescontroller.Pod, result2 error) {
+	fake.getPodMutex.Lock()
+	defer fake.getPodMutex.Unlock()
+	fake.GetPodStub = nil
+	fake.getPodReturns = struct {
+		result1 *kubernetescontroller.Pod
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Client) GetPodReturnsOnCall(i int, result1 *kubernetescontroller.Pod, result2 error) {
+	fake.getPodMutex.Lock()
+	defer fake.getPodMutex.Unlock()
+	fake.GetPodStub = nil
+	if fake.getPodReturnsOnCall == nil {
+		fake.getPodReturnsOnCall = make(map[int]struct {
+			result1 *kubernetescontroller.Pod
+			result2 error
+		})
+	}
+	fake.getPodReturnsOnCall[i] = struct {
+		result1 *kubernetescontroller.Pod
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Client) ListPods(arg1 string) ([]kubernetescontroller.Pod, error) {
+	fake.listPodsMutex.Lock()
+	ret, specificReturn := fake.listPodsReturnsOnCall[len(fake.listPodsArgsForCall)]
+	fake.listPodsArgsForCall = append(fake.listPodsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ListPodsStub
+	fakeReturns := fake.listPodsReturns
+	fake.recordInvocation("ListPods", []interface{}{arg1})
+	fake.listPodsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Client) ListPodsCallCount() int {
+	fake.listPodsMutex.RLock()
+	defer fake.listPodsMutex.RUnlock()
+	return len(fake.listPodsArgsForCall)
+}
+
+func (fake *Client) ListPodsCalls(stub func(string) ([]kubernetescontroller.Pod, error)) {
+	fake.listPodsMutex.Lock()
+	defer fake.listPodsMutex.Unlock()
+	fake.ListPodsStub = stub
+}
+
+func (fake *Client) ListPodsArgsForCall(i int) string {
+	fake.listPodsMutex.RLock()
+	defer fake.listPodsMutex.RUnlock()
+	argsForCall := fake.listPodsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Client) ListPodsReturns(result1 []kubernetescontroller.Pod, result2 error) {
+	fake.listPodsMutex.Lock()
+	defer fake.listPodsMutex.Unlock()
+	fake.ListPodsStub = nil
+	fake.listPodsReturns = struct {
+		result1 []kubernetescontroller.Pod
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Client) ListPodsReturnsOnCall(i int, result1 []kubernetescontroller.Pod, result2 error) {
+	fake.listPodsMutex.Lock()
+	defer fake.listPodsMutex.Unlock()
+	fake.ListPodsStub = nil
+	if fake.listPodsReturnsOnCall == nil {
+		fake.listPodsReturnsOnCall = make(map[int]struct {
+			result1 []kubernetescontroller.Pod
+			result2 error
+		})
+	}
+	fake.listPodsReturnsOnCall[i] = struct {
+		result1 []kubernetescontroller.Pod
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Client) Ping(arg1 context.Context) error {
+	fake.pingMutex.Lock()
+	ret, specificReturn := fake.pingReturnsOnCall[len(fake.pingArgsForCall)]
+	fake.pingArgsForCall = append(fake.pingArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.PingStub
+	fakeReturns := fake.pingReturns
+	fake.recordInvocation("Ping", []interface{}{arg1})
+	fake.pingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Client) PingCallCount() int {
+	fake.pingMutex.RLock()
+	defer fake.pingMutex.RUnlock()
+	return len(fake.pingArgsForCall)
+}
+
+func (fake *Client) PingCalls(stub func(context.Context) error) {
+	fake.pingMutex.Lock()
+	defer fake.pingMutex.Unlock()
+	fake.PingStub = stub
+}
+
+func (fake *Client) PingArgsForCall(i int) context.Context {
+	fake.pingMutex.RLock()
+	defer fake.pingMutex.RUnlock()
+	argsForCall := fake.pingArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Client) PingReturns(result1 error) {
+	fake.pingMutex.Lock()
+	defer fake.pingMutex.Unlock()
+	fake.PingStub = nil
+	fake.pingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) PingReturnsOnCall(i int, result1 error) {
+	fake.pingMutex.Lock()
+	defer fake.pingMutex.Unlock()
+	fake.PingStub = nil
+	if fake.pingReturnsOnCall == nil {
+		fake.pingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Client) PodLogs(arg1 string, arg2 bool) (io.ReadCloser, error) {
+	fake.podLogsMutex.Lock()
+	ret, specificReturn := fake.podLogsReturnsOnCall[len(fake.podLogsArgsForCall)]
+	fake.podLogsArgsForCall = append(fake.podLogsArgsForCall, struct {
+		arg1 string
+		arg2 bool
+	}{arg1, arg2})
+	stub := fake.PodLogsStub
+	fakeReturns := fake.podLogsReturns
+	fake.recordInvocation("PodLogs", []interface{}{arg1, arg2})
+	fake.podLogsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Client) PodLogsCallCount() int {
+	fake.pingMutex.RLock()
+	defer fake.pingMutex.RUnlock()
+	fake.podLogsMutex.RLock()
+	defer fake.podLogsMutex.RUnlock()
+	return len(fake.podLogsArgsForCall)
+}
+
+func (fake *Client) PodLogsCalls(stub func(string, bool) (io.ReadCloser, error)) {
+	fake.podLogsMutex.Lock()
+	defer fake.podLogsMutex.Unlock()
+	fake.PodLogsStub = stub
+}
+
+func (fake *Client) PodLogsArgsForCall(i int) (string, bool) {
+	fake.podLogsMutex.RLock()
+	defer fake.podLogsMutex.RUnlock()
+	argsForCall := fake.podLogsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Client) PodLogsReturns(result1 io.ReadCloser, result2 error) {
+	fake.podLogsMutex.Lock()
+	defer fake.podLogsMutex.Unlock()
+	fake.PodLogsStub = nil
+	fake.podLogsReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Client) PodLogsReturnsOnCall(i int, result1 io.ReadCloser, result2 error) {
+	fake.podLogsMutex.Lock()
+	defer fake.podLogsMutex.Unlock()
+	fake.PodLogsStub = nil
+	if fake.podLogsReturnsOnCall == nil {
+		fake.podLogsReturnsOnCall = make(map[int]struct {
+			result1 io.ReadCloser
+			result2 error
+		})
+	}
+	fake.podLogsReturnsOnCall[i] = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Client) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createJobMutex.RLock()
+	defer fake.createJobMutex.RUnlock()
+	fake.createPodMutex.RLock()
+	defer fake.createPodMutex.RUnlock()
+	fake.deleteJobMutex.RLock()
+	defer fake.deleteJobMutex.RUnlock()
+	fake.deletePodMutex.RLock()
+	defer fake.deletePodMutex.RUnlock()
+	fake.getJobMutex.RLock()
+	defer fake.getJobMutex.RUnlock()
+	fake.getPodMutex.RLock()
+	defer fake.getPodMutex.RUnlock()
+	fake.listPodsMutex.RLock()
+	defer fake.listPodsMutex.RUnlock()
+	fake.podLogsMutex.RLock()
+	defer fake.podLogsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *Client) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kubernetescontroller.Client = new(Client)
@@ -0,0 +1,198 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"sync"
+
+	"github.com/osdi23p228/fabric/core/chaincode/platforms/util"
+	"github.com/osdi23p228/fabric/core/container/kubernetescontroller"
+)
+
+type PlatformBuilder struct {
+	DockerBuildOptionsStub        func(string, string) (util.DockerBuildOptions, error)
+	dockerBuildOptionsMutex       sync.RWMutex
+	dockerBuildOptionsArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	dockerBuildOptionsReturns struct {
+		result1 util.DockerBuildOptions
+		result2 error
+	}
+	dockerBuildOptionsReturnsOnCall map[int]struct {
+		result1 util.DockerBuildOptions
+		result2 error
+	}
+	GenerateDockerfileStub        func(string) (string, error)
+	generateDockerfileMutex       sync.RWMutex
+	generateDockerfileArgsForCall []struct {
+		arg1 string
+	}
+	generateDockerfileReturns struct {
+		result1 string
+		result2 error
+	}
+	generateDockerfileReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PlatformBuilder) DockerBuildOptions(arg1 string, arg2 string) (util.DockerBuildOptions, error) {
+	fake.dockerBuildOptionsMutex.Lock()
+	ret, specificReturn := fake.dockerBuildOptionsReturnsOnCall[len(fake.dockerBuildOptionsArgsForCall)]
+	fake.dockerBuildOptionsArgsForCall = append(fake.dockerBuildOptionsArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DockerBuildOptionsStub
+	fakeReturns := fake.dockerBuildOptionsReturns
+	fake.recordInvocation("DockerBuildOptions", []interface{}{arg1, arg2})
+	fake.dockerBuildOptionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PlatformBuilder) DockerBuildOptionsCallCount() int {
+	fake.dockerBuildOptionsMutex.RLock()
+	defer fake.dockerBuildOptionsMutex.RUnlock()
+	return len(fake.dockerBuildOptionsArgsForCall)
+}
+
+func (fake *PlatformBuilder) DockerBuildOptionsCalls(stub func(string, string) (util.DockerBuildOptions, error)) {
+	fake.dockerBuildOptionsMutex.Lock()
+	defer fake.dockerBuildOptionsMutex.Unlock()
+	fake.DockerBuildOptionsStub = stub
+}
+
+func (fake *PlatformBuilder) DockerBuildOptionsArgsForCall(i int) (string, string) {
+	fake.dockerBuildOptionsMutex.RLock()
+	defer fake.dockerBuildOptionsMutex.RUnlock()
+	argsForCall := fake.dockerBuildOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PlatformBuilder) DockerBuildOptionsReturns(result1 util.DockerBuildOptions, result2 error) {
+	fake.dockerBuildOptionsMutex.Lock()
+	defer fake.dockerBuildOptionsMutex.Unlock()
+	fake.DockerBuildOptionsStub = nil
+	fake.dockerBuildOptionsReturns = struct {
+		result1 util.DockerBuildOptions
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PlatformBuilder) DockerBuildOptionsReturnsOnCall(i int, result1 util.DockerBuildOptions, result2 error) {
+	fake.dockerBuildOptionsMutex.Lock()
+	defer fake.dockerBuildOptionsMutex.Unlock()
+	fake.DockerBuildOptionsStub = nil
+	if fake.dockerBuildOptionsReturnsOnCall == nil {
+		fake.dockerBuildOptionsReturnsOnCall = make(map[int]struct {
+			result1 util.DockerBuildOptions
+			result2 error
+		})
+	}
+	fake.dockerBuildOptionsReturnsOnCall[i] = struct {
+		result1 util.DockerBuildOptions
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PlatformBuilder) GenerateDockerfile(arg1 string) (string, error) {
+	fake.generateDockerfileMutex.Lock()
+	ret, specificReturn := fake.generateDockerfileReturnsOnCall[len(fake.generateDockerfileArgsForCall)]
+	fake.generateDockerfileArgsForCall = append(fake.generateDockerfileArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GenerateDockerfileStub
+	fakeReturns := fake.generateDockerfileReturns
+	fake.recordInvocation("GenerateDockerfile", []interface{}{arg1})
+	fake.generateDockerfileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PlatformBuilder) GenerateDockerfileCallCount() int {
+	fake.generateDockerfileMutex.RLock()
+	defer fake.generateDockerfileMutex.RUnlock()
+	return len(fake.generateDockerfileArgsForCall)
+}
+
+func (fake *PlatformBuilder) GenerateDockerfileCalls(stub func(string) (string, error)) {
+	fake.generateDockerfileMutex.Lock()
+	defer fake.generateDockerfileMutex.Unlock()
+	fake.GenerateDockerfileStub = stub
+}
+
+func (fake *PlatformBuilder) GenerateDockerfileArgsForCall(i int) string {
+	fake.generateDockerfileMutex.RLock()
+	defer fake.generateDockerfileMutex.RUnlock()
+	argsForCall := fake.generateDockerfileArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PlatformBuilder) GenerateDockerfileReturns(result1 string, result2 error) {
+	fake.generateDockerfileMutex.Lock()
+	defer fake.generateDockerfileMutex.Unlock()
+	fake.GenerateDockerfileStub = nil
+	fake.generateDockerfileReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PlatformBuilder) GenerateDockerfileReturnsOnCall(i int, result1 string, result2 error) {
+	fake.generateDockerfileMutex.Lock()
+	defer fake.generateDockerfileMutex.Unlock()
+	fake.GenerateDockerfileStub = nil
+	if fake.generateDockerfileReturnsOnCall == nil {
+		fake.generateDockerfileReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.generateDockerfileReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PlatformBuilder) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.dockerBuildOptionsMutex.RLock()
+	defer fake.dockerBuildOptionsMutex.RUnlock()
+	fake.generateDockerfileMutex.RLock()
+	defer fake.generateDockerfileMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PlatformBuilder) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kubernetescontroller.PlatformBuilder = new(PlatformBuilder)
@@ -100,22 +100,37 @@ type Endorser struct {
 	Support                Support
 	PvtRWSetAssembler      PvtRWSetAssembler
 	Metrics                *Metrics
+	// QueryCache, when set, answers repeated read-only proposals without
+	// simulating them again.
+	QueryCache *QueryCache
 }
 
 // call specified chaincode (system or user)
 func (e *Endorser) callChaincode(txParams *ccprovider.TransactionParams, input *pb.ChaincodeInput, chaincodeName string) (*pb.Response, *pb.ChaincodeEvent, error) {
-	defer func(start time.Time) {
-		logger := endorserLogger.WithOptions(zap.AddCallerSkip(1))
-		logger = decorateLogger(logger, txParams)
-		elapsedMillisec := time.Since(start).Milliseconds()
-		logger.Infof("finished chaincode: %s duration: %dms", chaincodeName, elapsedMillisec)
-	}(time.Now())
-
 	meterLabels := []string{
 		"channel", txParams.ChannelID,
 		"chaincode", chaincodeName,
 	}
 
+	if txParams.UsageRecorder == nil {
+		txParams.UsageRecorder = &ccprovider.UsageRecorder{}
+	}
+	defer func(start time.Time) {
+		elapsed := time.Since(start)
+		usage := txParams.UsageRecorder.Usage()
+		e.Metrics.ChaincodeExecutionDuration.With(meterLabels...).Observe(elapsed.Seconds())
+		e.Metrics.ChaincodeGetStateCalls.With(meterLabels...).Add(float64(usage.GetStateCalls))
+		e.Metrics.ChaincodePutStateCalls.With(meterLabels...).Add(float64(usage.PutStateCalls))
+		e.Metrics.ChaincodeRangeQueryCalls.With(meterLabels...).Add(float64(usage.RangeQueryCalls))
+		e.Metrics.ChaincodeBytesRead.With(meterLabels...).Add(float64(usage.BytesRead))
+		e.Metrics.ChaincodeBytesWritten.With(meterLabels...).Add(float64(usage.BytesWritten))
+
+		logger := endorserLogger.WithOptions(zap.AddCallerSkip(1))
+		logger = decorateLogger(logger, txParams)
+		logger.Infof("finished chaincode: %s function: %s duration: %dms get state calls: %d put state calls: %d range query calls: %d bytes read: %d bytes written: %d",
+			chaincodeName, chaincodeFunction(input), elapsed.Milliseconds(), usage.GetStateCalls, usage.PutStateCalls, usage.RangeQueryCalls, usage.BytesRead, usage.BytesWritten)
+	}(time.Now())
+
 	res, ccevent, err := e.Support.Execute(txParams, chaincodeName, input)
 	if err != nil {
 		e.Metrics.SimulationFailure.With(meterLabels...).Add(1)
@@ -364,6 +379,14 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 
 	logger := decorateLogger(endorserLogger, txParams)
 
+	// the cache height must be read before the simulator is acquired so that
+	// results simulated against a state superseded in the meantime are not cached
+	useCache := e.QueryCache != nil && up.ChannelID() != "" && !e.Support.IsSysCC(up.ChaincodeName)
+	var cacheHeight uint64
+	if useCache {
+		cacheHeight = e.QueryCache.Height(up.ChannelID())
+	}
+
 	if acquireTxSimulator(up.ChannelHeader.ChannelId, up.ChaincodeName) {
 		txSim, err := e.Support.GetTxSimulator(up.ChannelID(), up.TxID())
 		if err != nil {
@@ -393,10 +416,41 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 		return nil, errors.WithMessagef(err, "make sure the chaincode %s has been successfully defined on channel %s and try again", up.ChaincodeName, up.ChannelID())
 	}
 
-	// 1 -- simulate
-	res, simulationResult, ccevent, err := e.SimulateProposal(txParams, up.ChaincodeName, up.Input)
-	if err != nil {
-		return nil, errors.WithMessage(err, "error in simulation")
+	// if error, capture endorsement failure metric
+	meterLabels := []string{
+		"channel", up.ChannelID(),
+		"chaincode", up.ChaincodeName,
+	}
+
+	// 1 -- simulate, unless the result of an identical query is cached
+	var (
+		res              *pb.Response
+		simulationResult []byte
+		ccevent          *pb.ChaincodeEvent
+		cacheKey         string
+		cached           bool
+	)
+	if useCache {
+		cacheKey = queryDigest(up, cdLedger)
+		res, simulationResult, cached = e.QueryCache.Get(up.ChannelID(), cacheKey)
+	}
+	if cached {
+		e.Metrics.QueryCacheHits.With(meterLabels...).Add(1)
+		logger.Debugf("using cached query result for chaincode %s", up.ChaincodeName)
+		if txParams.TXSimulator != nil {
+			txParams.TXSimulator.Done()
+		}
+	} else {
+		if useCache {
+			e.Metrics.QueryCacheMisses.With(meterLabels...).Add(1)
+		}
+		res, simulationResult, ccevent, err = e.SimulateProposal(txParams, up.ChaincodeName, up.Input)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error in simulation")
+		}
+		if useCache && ccevent == nil && res.Status < shim.ERRORTHRESHOLD {
+			e.QueryCache.Put(up.ChannelID(), cacheKey, cacheHeight, up.ChaincodeName, res, simulationResult)
+		}
 	}
 
 	cceventBytes, err := CreateCCEventBytes(ccevent)
@@ -413,12 +467,6 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 		return nil, errors.WithMessage(err, "failed to create the proposal response")
 	}
 
-	// if error, capture endorsement failure metric
-	meterLabels := []string{
-		"channel", up.ChannelID(),
-		"chaincode", up.ChaincodeName,
-	}
-
 	switch {
 	case res.Status >= shim.ERROR:
 		return &pb.ProposalResponse{
@@ -497,6 +545,14 @@ func CreateCCEventBytes(ccevent *pb.ChaincodeEvent) ([]byte, error) {
 	return proto.Marshal(ccevent)
 }
 
+// chaincodeFunction returns the function invoked by the chaincode input.
+func chaincodeFunction(input *pb.ChaincodeInput) string {
+	if len(input.Args) == 0 {
+		return ""
+	}
+	return string(input.Args[0])
+}
+
 func decorateLogger(logger *flogging.FabricLogger, txParams *ccprovider.TransactionParams) *flogging.FabricLogger {
 	return logger.With("channel", txParams.ChannelID, "txID", shorttxid(txParams.TxID))
 }
@@ -20,6 +20,7 @@ import (
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/osdi23p228/fabric/common/metrics/metricsfakes"
 	"github.com/osdi23p228/fabric/core/chaincode/lifecycle"
+	"github.com/osdi23p228/fabric/core/common/ccprovider"
 	"github.com/osdi23p228/fabric/core/endorser"
 	"github.com/osdi23p228/fabric/core/endorser/fake"
 	"github.com/osdi23p228/fabric/core/ledger"
@@ -40,6 +41,12 @@ var _ = Describe("Endorser", func() {
 		fakeEndorsementsFailed       *metricsfakes.Counter
 		fakeDuplicateTxsFailure      *metricsfakes.Counter
 		fakeSimulateFailure          *metricsfakes.Counter
+		fakeChaincodeDuration        *metricsfakes.Histogram
+		fakeChaincodeGetStateCalls   *metricsfakes.Counter
+		fakeChaincodePutStateCalls   *metricsfakes.Counter
+		fakeChaincodeRangeQueryCalls *metricsfakes.Counter
+		fakeChaincodeBytesRead       *metricsfakes.Counter
+		fakeChaincodeBytesWritten    *metricsfakes.Counter
 
 		fakeLocalIdentity                *fake.Identity
 		fakeLocalMSPIdentityDeserializer *fake.IdentityDeserializer
@@ -89,6 +96,19 @@ var _ = Describe("Endorser", func() {
 		fakeSimulateFailure = &metricsfakes.Counter{}
 		fakeSimulateFailure.WithReturns(fakeSimulateFailure)
 
+		fakeChaincodeDuration = &metricsfakes.Histogram{}
+		fakeChaincodeDuration.WithReturns(fakeChaincodeDuration)
+		fakeChaincodeGetStateCalls = &metricsfakes.Counter{}
+		fakeChaincodeGetStateCalls.WithReturns(fakeChaincodeGetStateCalls)
+		fakeChaincodePutStateCalls = &metricsfakes.Counter{}
+		fakeChaincodePutStateCalls.WithReturns(fakeChaincodePutStateCalls)
+		fakeChaincodeRangeQueryCalls = &metricsfakes.Counter{}
+		fakeChaincodeRangeQueryCalls.WithReturns(fakeChaincodeRangeQueryCalls)
+		fakeChaincodeBytesRead = &metricsfakes.Counter{}
+		fakeChaincodeBytesRead.WithReturns(fakeChaincodeBytesRead)
+		fakeChaincodeBytesWritten = &metricsfakes.Counter{}
+		fakeChaincodeBytesWritten.WithReturns(fakeChaincodeBytesWritten)
+
 		fakeLocalIdentity = &fake.Identity{}
 		fakeLocalMSPIdentityDeserializer = &fake.IdentityDeserializer{}
 		fakeLocalMSPIdentityDeserializer.DeserializeIdentityReturns(fakeLocalIdentity, nil)
@@ -173,6 +193,13 @@ var _ = Describe("Endorser", func() {
 				EndorsementsFailed:       fakeEndorsementsFailed,
 				DuplicateTxsFailure:      fakeDuplicateTxsFailure,
 				SimulationFailure:        fakeSimulateFailure,
+
+				ChaincodeExecutionDuration: fakeChaincodeDuration,
+				ChaincodeGetStateCalls:     fakeChaincodeGetStateCalls,
+				ChaincodePutStateCalls:     fakeChaincodePutStateCalls,
+				ChaincodeRangeQueryCalls:   fakeChaincodeRangeQueryCalls,
+				ChaincodeBytesRead:         fakeChaincodeBytesRead,
+				ChaincodeBytesWritten:      fakeChaincodeBytesWritten,
 			},
 			Support:        fakeSupport,
 			ChannelFetcher: fakeChannelFetcher,
@@ -276,6 +303,164 @@ var _ = Describe("Endorser", func() {
 		Expect(txid).To(Equal("6f142589e4ef6a1e62c9c816e2074f70baa9f7cf67c2f0c287d4ef907d6d2015"))
 	})
 
+	It("records the chaincode usage", func() {
+		fakeSupport.ExecuteStub = func(txParams *ccprovider.TransactionParams, name string, input *pb.ChaincodeInput) (*pb.Response, *pb.ChaincodeEvent, error) {
+			txParams.UsageRecorder.Record(ccprovider.ExecutionUsage{
+				GetStateCalls:   3,
+				PutStateCalls:   2,
+				RangeQueryCalls: 1,
+				BytesRead:       100,
+				BytesWritten:    40,
+			})
+			return chaincodeResponse, chaincodeEvent, nil
+		}
+
+		_, err := e.ProcessProposal(context.Background(), signedProposal)
+		Expect(err).NotTo(HaveOccurred())
+
+		meterLabels := []string{
+			"channel", "channel-id",
+			"chaincode", "chaincode-name",
+		}
+		Expect(fakeChaincodeDuration.WithArgsForCall(0)).To(Equal(meterLabels))
+		Expect(fakeChaincodeDuration.ObserveCallCount()).To(Equal(1))
+		for counter, value := range map[*metricsfakes.Counter]float64{
+			fakeChaincodeGetStateCalls:   3,
+			fakeChaincodePutStateCalls:   2,
+			fakeChaincodeRangeQueryCalls: 1,
+			fakeChaincodeBytesRead:       100,
+			fakeChaincodeBytesWritten:    40,
+		} {
+			Expect(counter.WithArgsForCall(0)).To(Equal(meterLabels))
+			Expect(counter.AddCallCount()).To(Equal(1))
+			Expect(counter.AddArgsForCall(0)).To(Equal(value))
+		}
+	})
+
+	Context("when the query cache is enabled", func() {
+		var (
+			fakeQueryCacheHits   *metricsfakes.Counter
+			fakeQueryCacheMisses *metricsfakes.Counter
+		)
+
+		BeforeEach(func() {
+			fakeQueryCacheHits = &metricsfakes.Counter{}
+			fakeQueryCacheHits.WithReturns(fakeQueryCacheHits)
+			fakeQueryCacheMisses = &metricsfakes.Counter{}
+			fakeQueryCacheMisses.WithReturns(fakeQueryCacheMisses)
+
+			e.Metrics.QueryCacheHits = fakeQueryCacheHits
+			e.Metrics.QueryCacheMisses = fakeQueryCacheMisses
+			e.QueryCache = endorser.NewQueryCache(10)
+
+			fakeSupport.ExecuteReturns(chaincodeResponse, nil, nil)
+		})
+
+		It("endorses repeated queries without simulating them again", func() {
+			for i := 0; i < 2; i++ {
+				proposalResponse, err := e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proto.Equal(proposalResponse.Response, &pb.Response{
+					Status:  200,
+					Payload: []byte("response-payload"),
+				})).To(BeTrue())
+			}
+
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(1))
+			Expect(fakeSupport.EndorseWithPluginCallCount()).To(Equal(2))
+			Expect(fakeQueryCacheMisses.AddCallCount()).To(Equal(1))
+			Expect(fakeQueryCacheHits.AddCallCount()).To(Equal(1))
+			Expect(fakeQueryCacheHits.WithArgsForCall(0)).To(Equal([]string{
+				"channel", "channel-id",
+				"chaincode", "chaincode-name",
+			}))
+
+			_, _, propRespPayloadBytes, _ := fakeSupport.EndorseWithPluginArgsForCall(1)
+			prp := &pb.ProposalResponsePayload{}
+			err := proto.Unmarshal(propRespPayloadBytes, prp)
+			Expect(err).NotTo(HaveOccurred())
+			ccAct := &pb.ChaincodeAction{}
+			err = proto.Unmarshal(prp.Extension, ccAct)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ccAct.ChaincodeId).To(Equal(&pb.ChaincodeID{
+				Name:    "chaincode-name",
+				Version: "chaincode-definition-version",
+			}))
+		})
+
+		It("simulates the query again once a block has been committed", func() {
+			_, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+
+			e.QueryCache.BlockCommitted("channel-id", &cb.Block{Header: &cb.BlockHeader{Number: 7}})
+
+			_, err = e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			Expect(fakeQueryCacheMisses.AddCallCount()).To(Equal(2))
+			Expect(fakeQueryCacheHits.AddCallCount()).To(Equal(0))
+		})
+
+		It("simulates the query again once the chaincode runs another package", func() {
+			_, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeSupport.ChaincodeEndorsementInfoReturns(&lifecycle.ChaincodeEndorsementInfo{
+				Version:           "chaincode-definition-version",
+				ChaincodeID:       "other-package-id",
+				EndorsementPlugin: "plugin-name",
+			}, nil)
+
+			_, err = e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			Expect(fakeQueryCacheHits.AddCallCount()).To(Equal(0))
+		})
+
+		Context("when the chaincode sets an event", func() {
+			BeforeEach(func() {
+				fakeSupport.ExecuteReturns(chaincodeResponse, chaincodeEvent, nil)
+			})
+
+			It("does not cache the result", func() {
+				for i := 0; i < 2; i++ {
+					_, err := e.ProcessProposal(context.Background(), signedProposal)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when the chaincode returns an error", func() {
+			BeforeEach(func() {
+				fakeSupport.ExecuteReturns(&pb.Response{Status: 500, Message: "chaincode-error"}, nil, nil)
+			})
+
+			It("does not cache the result", func() {
+				for i := 0; i < 2; i++ {
+					_, err := e.ProcessProposal(context.Background(), signedProposal)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when the chaincode is a system chaincode", func() {
+			BeforeEach(func() {
+				fakeSupport.IsSysCCReturns(true)
+			})
+
+			It("does not use the cache", func() {
+				for i := 0; i < 2; i++ {
+					_, err := e.ProcessProposal(context.Background(), signedProposal)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+				Expect(fakeQueryCacheMisses.AddCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Context("when the txid is duplicated", func() {
 		BeforeEach(func() {
 			fakeSupport.GetTransactionByIDReturns(nil, nil)
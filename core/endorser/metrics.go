@@ -74,6 +74,70 @@ var (
 		LabelNames:   []string{"channel", "chaincode"},
 		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
 	}
+
+	chaincodeExecutionDurationHistogramOpts = metrics.HistogramOpts{
+		Namespace:    "endorser",
+		Name:         "chaincode_execution_duration",
+		Help:         "The time to execute chaincode during proposal simulation in seconds.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	chaincodeGetStateCallsCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "chaincode_get_state_calls",
+		Help:         "The number of requests reading keys by name made by chaincode during proposal simulation.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	chaincodePutStateCallsCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "chaincode_put_state_calls",
+		Help:         "The number of requests writing or deleting keys made by chaincode during proposal simulation.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	chaincodeRangeQueryCallsCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "chaincode_range_query_calls",
+		Help:         "The number of range, rich and history queries made by chaincode during proposal simulation.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	chaincodeBytesReadCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "chaincode_bytes_read",
+		Help:         "The number of bytes read from the ledger by chaincode during proposal simulation.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	chaincodeBytesWrittenCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "chaincode_bytes_written",
+		Help:         "The number of bytes of ledger writes requested by chaincode during proposal simulation.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	queryCacheHitsCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "query_cache_hits",
+		Help:         "The number of proposals answered from the query result cache.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	queryCacheMissesCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "query_cache_misses",
+		Help:         "The number of proposals not found in the query result cache.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
 )
 
 type Metrics struct {
@@ -86,6 +150,15 @@ type Metrics struct {
 	EndorsementsFailed       metrics.Counter
 	DuplicateTxsFailure      metrics.Counter
 	SimulationFailure        metrics.Counter
+	QueryCacheHits           metrics.Counter
+	QueryCacheMisses         metrics.Counter
+
+	ChaincodeExecutionDuration metrics.Histogram
+	ChaincodeGetStateCalls     metrics.Counter
+	ChaincodePutStateCalls     metrics.Counter
+	ChaincodeRangeQueryCalls   metrics.Counter
+	ChaincodeBytesRead         metrics.Counter
+	ChaincodeBytesWritten      metrics.Counter
 }
 
 func NewMetrics(p metrics.Provider) *Metrics {
@@ -99,5 +172,14 @@ func NewMetrics(p metrics.Provider) *Metrics {
 		EndorsementsFailed:       p.NewCounter(endorsementFailureCounterOpts),
 		DuplicateTxsFailure:      p.NewCounter(duplicateTxsFailureCounterOpts),
 		SimulationFailure:        p.NewCounter(simulationFailureCounterOpts),
+		QueryCacheHits:           p.NewCounter(queryCacheHitsCounterOpts),
+		QueryCacheMisses:         p.NewCounter(queryCacheMissesCounterOpts),
+
+		ChaincodeExecutionDuration: p.NewHistogram(chaincodeExecutionDurationHistogramOpts),
+		ChaincodeGetStateCalls:     p.NewCounter(chaincodeGetStateCallsCounterOpts),
+		ChaincodePutStateCalls:     p.NewCounter(chaincodePutStateCallsCounterOpts),
+		ChaincodeRangeQueryCalls:   p.NewCounter(chaincodeRangeQueryCallsCounterOpts),
+		ChaincodeBytesRead:         p.NewCounter(chaincodeBytesReadCounterOpts),
+		ChaincodeBytesWritten:      p.NewCounter(chaincodeBytesWrittenCounterOpts),
 	}
 }
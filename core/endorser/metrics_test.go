@@ -31,14 +31,24 @@ func TestNewMetrics(t *testing.T) {
 		EndorsementsFailed:       &metricsfakes.Counter{},
 		DuplicateTxsFailure:      &metricsfakes.Counter{},
 		SimulationFailure:        &metricsfakes.Counter{},
+		QueryCacheHits:           &metricsfakes.Counter{},
+		QueryCacheMisses:         &metricsfakes.Counter{},
+
+		ChaincodeExecutionDuration: &metricsfakes.Histogram{},
+		ChaincodeGetStateCalls:     &metricsfakes.Counter{},
+		ChaincodePutStateCalls:     &metricsfakes.Counter{},
+		ChaincodeRangeQueryCalls:   &metricsfakes.Counter{},
+		ChaincodeBytesRead:         &metricsfakes.Counter{},
+		ChaincodeBytesWritten:      &metricsfakes.Counter{},
 	}))
 
-	gt.Expect(provider.NewHistogramCallCount()).To(Equal(1))
+	gt.Expect(provider.NewHistogramCallCount()).To(Equal(2))
 	gt.Expect(provider.Invocations()["NewHistogram"]).To(ConsistOf([][]interface{}{
 		{proposalDurationHistogramOpts},
+		{chaincodeExecutionDurationHistogramOpts},
 	}))
 
-	gt.Expect(provider.NewCounterCallCount()).To(Equal(8))
+	gt.Expect(provider.NewCounterCallCount()).To(Equal(15))
 	gt.Expect(provider.Invocations()["NewCounter"]).To(ConsistOf([][]interface{}{
 		{receivedProposalsCounterOpts},
 		{successfulProposalsCounterOpts},
@@ -48,5 +58,12 @@ func TestNewMetrics(t *testing.T) {
 		{endorsementFailureCounterOpts},
 		{duplicateTxsFailureCounterOpts},
 		{simulationFailureCounterOpts},
+		{queryCacheHitsCounterOpts},
+		{queryCacheMissesCounterOpts},
+		{chaincodeGetStateCallsCounterOpts},
+		{chaincodePutStateCallsCounterOpts},
+		{chaincodeRangeQueryCallsCounterOpts},
+		{chaincodeBytesReadCounterOpts},
+		{chaincodeBytesWrittenCounterOpts},
 	}))
 }
@@ -0,0 +1,290 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"container/list"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/common/util"
+	"github.com/osdi23p228/fabric/core/chaincode/lifecycle"
+	"github.com/osdi23p228/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/osdi23p228/fabric/internal/pkg/txflags"
+	"github.com/osdi23p228/fabric/protoutil"
+)
+
+// DefaultQueryCacheMaxEntries is the number of results kept by a QueryCache
+// created without a limit.
+const DefaultQueryCacheMaxEntries = 10000
+
+type queryCacheKey struct {
+	channelID string
+	digest    string
+}
+
+type namespaceKey struct {
+	channelID string
+	namespace string
+}
+
+type queryCacheEntry struct {
+	key              queryCacheKey
+	response         *pb.Response
+	simulationResult []byte
+	namespaces       []string
+}
+
+// QueryCache caches the simulation results of read-only proposals so that
+// identical queries are answered without invoking the chaincode again. A
+// result remains cached until a block writing to one of the namespaces it
+// read from is committed, or until it is evicted as the least recently used
+// once the cache is full.
+//
+// Caching is opt-in: a chaincode whose query results depend on anything but
+// its arguments, the creator and the state it reads, such as the
+// transaction timestamp, may return the result of an earlier proposal.
+type QueryCache struct {
+	maxEntries int
+
+	mutex       sync.Mutex
+	heights     map[string]uint64
+	entries     map[queryCacheKey]*list.Element
+	byNamespace map[namespaceKey]map[queryCacheKey]struct{}
+	lru         *list.List
+}
+
+// NewQueryCache creates a QueryCache holding up to maxEntries results.
+func NewQueryCache(maxEntries int) *QueryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultQueryCacheMaxEntries
+	}
+	return &QueryCache{
+		maxEntries:  maxEntries,
+		heights:     map[string]uint64{},
+		entries:     map[queryCacheKey]*list.Element{},
+		byNamespace: map[namespaceKey]map[queryCacheKey]struct{}{},
+		lru:         list.New(),
+	}
+}
+
+// queryDigest identifies the result of a proposal: the chaincode, the
+// version of its definition and the package it runs, the creator and the
+// proposal payload, which carries the function, the arguments and the
+// transient data of the invocation.
+func queryDigest(up *UnpackedProposal, info *lifecycle.ChaincodeEndorsementInfo) string {
+	return hex.EncodeToString(util.ConcatenateBytes(
+		util.ComputeSHA256([]byte(up.ChaincodeName)),
+		util.ComputeSHA256([]byte(info.Version)),
+		util.ComputeSHA256([]byte(info.ChaincodeID)),
+		util.ComputeSHA256(up.SignatureHeader.Creator),
+		util.ComputeSHA256(up.Proposal.Payload),
+	))
+}
+
+// Height returns the height of the channel state known to the cache. It must
+// be read before simulating a proposal whose result is passed to Put.
+func (c *QueryCache) Height(channelID string) uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.heights[channelID]
+}
+
+// Get returns the cached result of the proposal identified by digest.
+func (c *QueryCache) Get(channelID, digest string) (*pb.Response, []byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	elem, ok := c.entries[queryCacheKey{channelID: channelID, digest: digest}]
+	if !ok {
+		return nil, nil, false
+	}
+	c.lru.MoveToFront(elem)
+	entry := elem.Value.(*queryCacheEntry)
+	return entry.response, entry.simulationResult, true
+}
+
+// Put caches the result of a proposal of chaincodeName simulated against the
+// channel state at the given height. Results which write to the state, which
+// access private data, or whose height was superseded by a block committed
+// in the meantime are not cached. It returns whether the result was cached.
+func (c *QueryCache) Put(channelID, digest string, height uint64, chaincodeName string, response *pb.Response, simulationResult []byte) bool {
+	namespaces, ok := readNamespaces(simulationResult)
+	if !ok {
+		return false
+	}
+	namespaces = append(namespaces, chaincodeName)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.heights[channelID] != height {
+		return false
+	}
+
+	key := queryCacheKey{channelID: channelID, digest: digest}
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+
+	entry := &queryCacheEntry{
+		key:              key,
+		response:         response,
+		simulationResult: simulationResult,
+		namespaces:       namespaces,
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for _, ns := range namespaces {
+		nsKey := namespaceKey{channelID: channelID, namespace: ns}
+		if c.byNamespace[nsKey] == nil {
+			c.byNamespace[nsKey] = map[queryCacheKey]struct{}{}
+		}
+		c.byNamespace[nsKey][key] = struct{}{}
+	}
+
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+
+	return true
+}
+
+// BlockCommitted drops the results which read from the namespaces written by
+// the valid transactions of a committed block, and the results of the
+// chaincodes whose definition they update, or all the results of the channel
+// when the block contains transactions other than endorser transactions.
+func (c *QueryCache) BlockCommitted(channelID string, block *cb.Block) {
+	namespaces, all := writtenNamespaces(block)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.heights[channelID] = block.Header.Number + 1
+
+	if all {
+		for key, elem := range c.entries {
+			if key.channelID == channelID {
+				c.remove(elem)
+			}
+		}
+		return
+	}
+
+	for ns := range namespaces {
+		for key := range c.byNamespace[namespaceKey{channelID: channelID, namespace: ns}] {
+			c.remove(c.entries[key])
+		}
+	}
+}
+
+func (c *QueryCache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*queryCacheEntry)
+	delete(c.entries, entry.key)
+	for _, ns := range entry.namespaces {
+		nsKey := namespaceKey{channelID: entry.key.channelID, namespace: ns}
+		delete(c.byNamespace[nsKey], entry.key)
+		if len(c.byNamespace[nsKey]) == 0 {
+			delete(c.byNamespace, nsKey)
+		}
+	}
+}
+
+// readNamespaces returns the namespaces a simulation result reads from, and
+// false if the result writes to the state or accesses private data.
+func readNamespaces(simulationResult []byte) ([]string, bool) {
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(simulationResult); err != nil {
+		return nil, false
+	}
+
+	var namespaces []string
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if len(nsRWSet.CollHashedRwSets) != 0 {
+			return nil, false
+		}
+		if nsRWSet.KvRwSet != nil && len(nsRWSet.KvRwSet.Writes)+len(nsRWSet.KvRwSet.MetadataWrites) != 0 {
+			return nil, false
+		}
+		namespaces = append(namespaces, nsRWSet.NameSpace)
+	}
+	return namespaces, true
+}
+
+// writtenNamespaces returns the namespaces written by the valid transactions
+// of a block, including the namespaces of the chaincodes whose _lifecycle
+// definition is written, or true if the effects of the block cannot be
+// determined.
+func writtenNamespaces(block *cb.Block) (map[string]struct{}, bool) {
+	if block.Data == nil || block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		return nil, true
+	}
+
+	namespaces := map[string]struct{}{}
+	flags := txflags.ValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for i, envBytes := range block.Data.Data {
+		if i < len(flags) && !flags.IsValid(i) {
+			continue
+		}
+
+		env, err := protoutil.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			return nil, true
+		}
+		payload, err := protoutil.UnmarshalPayload(env.Payload)
+		if err != nil || payload.Header == nil {
+			return nil, true
+		}
+		chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil || cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+			return nil, true
+		}
+		action, err := protoutil.GetActionFromEnvelopeMsg(env)
+		if err != nil {
+			return nil, true
+		}
+		txRWSet := &rwsetutil.TxRwSet{}
+		if err := txRWSet.FromProtoBytes(action.Results); err != nil {
+			return nil, true
+		}
+
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			if nsRWSet.KvRwSet != nil && len(nsRWSet.KvRwSet.Writes)+len(nsRWSet.KvRwSet.MetadataWrites) != 0 {
+				namespaces[nsRWSet.NameSpace] = struct{}{}
+			}
+			if nsRWSet.NameSpace == lifecycle.LifecycleNamespace && nsRWSet.KvRwSet != nil {
+				for _, write := range nsRWSet.KvRwSet.Writes {
+					if name, ok := definedNamespace(write.Key); ok {
+						namespaces[name] = struct{}{}
+					}
+				}
+			}
+			for _, collRWSet := range nsRWSet.CollHashedRwSets {
+				if collRWSet.HashedRwSet != nil && len(collRWSet.HashedRwSet.HashedWrites)+len(collRWSet.HashedRwSet.MetadataWrites) != 0 {
+					namespaces[nsRWSet.NameSpace] = struct{}{}
+				}
+			}
+		}
+	}
+	return namespaces, false
+}
+
+// definedNamespace returns the chaincode whose definition is stored under a
+// key of the _lifecycle namespace.
+func definedNamespace(key string) (string, bool) {
+	for _, prefix := range []string{
+		lifecycle.NamespacesName + "/metadata/",
+		lifecycle.NamespacesName + "/fields/",
+	} {
+		if strings.HasPrefix(key, prefix) {
+			return strings.SplitN(strings.TrimPrefix(key, prefix), "/", 2)[0], true
+		}
+	}
+	return "", false
+}
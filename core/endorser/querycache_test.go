@@ -0,0 +1,222 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/core/endorser"
+	"github.com/osdi23p228/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/osdi23p228/fabric/internal/pkg/txflags"
+	"github.com/osdi23p228/fabric/protoutil"
+)
+
+var _ = Describe("QueryCache", func() {
+	var (
+		queryCache *endorser.QueryCache
+		response   *pb.Response
+	)
+
+	BeforeEach(func() {
+		queryCache = endorser.NewQueryCache(3)
+		response = &pb.Response{Status: 200, Payload: []byte("query-result")}
+	})
+
+	It("returns cached results", func() {
+		result := simulationResult(readSet("cc1"), readSet("cc2"))
+		Expect(queryCache.Put("channel", "digest", 0, "cc1", response, result)).To(BeTrue())
+
+		res, simResult, ok := queryCache.Get("channel", "digest")
+		Expect(ok).To(BeTrue())
+		Expect(res).To(Equal(response))
+		Expect(simResult).To(Equal(result))
+
+		_, _, ok = queryCache.Get("other-channel", "digest")
+		Expect(ok).To(BeFalse())
+		_, _, ok = queryCache.Get("channel", "other-digest")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("does not cache results which write to the state", func() {
+		Expect(queryCache.Put("channel", "digest", 0, "cc1", response, simulationResult(writeSet("cc1")))).To(BeFalse())
+		_, _, ok := queryCache.Get("channel", "digest")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("does not cache results which access private data", func() {
+		nsRWSet := readSet("cc1")
+		nsRWSet.CollHashedRwSets = []*rwsetutil.CollHashedRwSet{{
+			CollectionName: "coll",
+			HashedRwSet:    &kvrwset.HashedRWSet{HashedReads: []*kvrwset.KVReadHash{{KeyHash: []byte("key-hash")}}},
+		}}
+		Expect(queryCache.Put("channel", "digest", 0, "cc1", response, simulationResult(nsRWSet))).To(BeFalse())
+	})
+
+	It("does not cache malformed results", func() {
+		Expect(queryCache.Put("channel", "digest", 0, "cc1", response, []byte("garbage"))).To(BeFalse())
+	})
+
+	It("does not cache results simulated before the last committed block", func() {
+		queryCache.BlockCommitted("channel", block(4, writeSet("cc3")))
+		Expect(queryCache.Height("channel")).To(Equal(uint64(5)))
+		Expect(queryCache.Height("other-channel")).To(Equal(uint64(0)))
+
+		Expect(queryCache.Put("channel", "digest", 4, "cc1", response, simulationResult(readSet("cc1")))).To(BeFalse())
+		Expect(queryCache.Put("channel", "digest", 5, "cc1", response, simulationResult(readSet("cc1")))).To(BeTrue())
+	})
+
+	It("evicts the least recently used results", func() {
+		for _, digest := range []string{"digest1", "digest2", "digest3"} {
+			Expect(queryCache.Put("channel", digest, 0, "cc1", response, simulationResult())).To(BeTrue())
+		}
+		_, _, ok := queryCache.Get("channel", "digest1")
+		Expect(ok).To(BeTrue())
+
+		Expect(queryCache.Put("channel", "digest4", 0, "cc1", response, simulationResult())).To(BeTrue())
+		_, _, ok = queryCache.Get("channel", "digest2")
+		Expect(ok).To(BeFalse())
+		for _, digest := range []string{"digest1", "digest3", "digest4"} {
+			_, _, ok = queryCache.Get("channel", digest)
+			Expect(ok).To(BeTrue())
+		}
+	})
+
+	Describe("BlockCommitted", func() {
+		BeforeEach(func() {
+			Expect(queryCache.Put("channel", "reads-cc1", 0, "cc1", response, simulationResult(readSet("cc1")))).To(BeTrue())
+			Expect(queryCache.Put("channel", "reads-cc2", 0, "cc1", response, simulationResult(readSet("cc2")))).To(BeTrue())
+			Expect(queryCache.Put("other-channel", "reads-cc2", 0, "cc1", response, simulationResult(readSet("cc2")))).To(BeTrue())
+		})
+
+		cached := func(channelID, digest string) bool {
+			_, _, ok := queryCache.Get(channelID, digest)
+			return ok
+		}
+
+		It("drops the results reading from the written namespaces", func() {
+			queryCache.BlockCommitted("channel", block(0, writeSet("cc2")))
+			Expect(cached("channel", "reads-cc1")).To(BeTrue())
+			Expect(cached("channel", "reads-cc2")).To(BeFalse())
+			Expect(cached("other-channel", "reads-cc2")).To(BeTrue())
+		})
+
+		It("drops the results of the invoked chaincode", func() {
+			queryCache.BlockCommitted("channel", block(0, writeSet("cc1")))
+			Expect(cached("channel", "reads-cc1")).To(BeFalse())
+			Expect(cached("channel", "reads-cc2")).To(BeFalse())
+		})
+
+		It("drops the results of the chaincodes whose definition is written", func() {
+			queryCache.BlockCommitted("channel", block(0, &rwsetutil.NsRwSet{
+				NameSpace: "_lifecycle",
+				KvRwSet: &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{
+					{Key: "namespaces/fields/cc1/Sequence", Value: []byte("2")},
+					{Key: "namespaces/metadata/cc1", Value: []byte("metadata")},
+				}},
+			}))
+			Expect(cached("channel", "reads-cc1")).To(BeFalse())
+			Expect(cached("channel", "reads-cc2")).To(BeFalse())
+			Expect(cached("other-channel", "reads-cc2")).To(BeTrue())
+		})
+
+		It("keeps the results of chaincodes whose definition is not written", func() {
+			queryCache.BlockCommitted("channel", block(0, &rwsetutil.NsRwSet{
+				NameSpace: "_lifecycle",
+				KvRwSet:   &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "namespaces/fields/cc3/Sequence", Value: []byte("2")}}},
+			}))
+			Expect(cached("channel", "reads-cc1")).To(BeTrue())
+			Expect(cached("channel", "reads-cc2")).To(BeTrue())
+		})
+
+		It("ignores the writes of invalid transactions", func() {
+			b := block(0, writeSet("cc2"))
+			b.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = txflags.NewWithValues(1, pb.TxValidationCode_MVCC_READ_CONFLICT)
+			queryCache.BlockCommitted("channel", b)
+			Expect(cached("channel", "reads-cc2")).To(BeTrue())
+		})
+
+		It("drops the results reading from namespaces with private writes", func() {
+			nsRWSet := readSet("cc2")
+			nsRWSet.CollHashedRwSets = []*rwsetutil.CollHashedRwSet{{
+				CollectionName: "coll",
+				HashedRwSet:    &kvrwset.HashedRWSet{HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte("key-hash")}}},
+			}}
+			queryCache.BlockCommitted("channel", block(0, nsRWSet))
+			Expect(cached("channel", "reads-cc1")).To(BeTrue())
+			Expect(cached("channel", "reads-cc2")).To(BeFalse())
+		})
+
+		It("drops all the results of the channel for other transactions", func() {
+			b := block(0)
+			b.Data.Data = [][]byte{protoutil.MarshalOrPanic(&cb.Envelope{
+				Payload: protoutil.MarshalOrPanic(&cb.Payload{
+					Header: &cb.Header{
+						ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{Type: int32(cb.HeaderType_CONFIG)}),
+					},
+				}),
+			})}
+			queryCache.BlockCommitted("channel", b)
+			Expect(cached("channel", "reads-cc1")).To(BeFalse())
+			Expect(cached("channel", "reads-cc2")).To(BeFalse())
+			Expect(cached("other-channel", "reads-cc2")).To(BeTrue())
+		})
+	})
+})
+
+func readSet(namespace string) *rwsetutil.NsRwSet {
+	return &rwsetutil.NsRwSet{
+		NameSpace: namespace,
+		KvRwSet:   &kvrwset.KVRWSet{Reads: []*kvrwset.KVRead{{Key: "key"}}},
+	}
+}
+
+func writeSet(namespace string) *rwsetutil.NsRwSet {
+	return &rwsetutil.NsRwSet{
+		NameSpace: namespace,
+		KvRwSet:   &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "key", Value: []byte("value")}}},
+	}
+}
+
+func simulationResult(nsRWSets ...*rwsetutil.NsRwSet) []byte {
+	txRWSet := &rwsetutil.TxRwSet{NsRwSets: nsRWSets}
+	result, err := txRWSet.ToProtoBytes()
+	Expect(err).NotTo(HaveOccurred())
+	return result
+}
+
+// block returns a block with a single valid endorser transaction having the
+// supplied read-write sets.
+func block(number uint64, nsRWSets ...*rwsetutil.NsRwSet) *cb.Block {
+	prp := protoutil.MarshalOrPanic(&pb.ProposalResponsePayload{
+		Extension: protoutil.MarshalOrPanic(&pb.ChaincodeAction{
+			Results: simulationResult(nsRWSets...),
+		}),
+	})
+	env := &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{Type: int32(cb.HeaderType_ENDORSER_TRANSACTION)}),
+			},
+			Data: protoutil.MarshalOrPanic(&pb.Transaction{
+				Actions: []*pb.TransactionAction{{
+					Payload: protoutil.MarshalOrPanic(&pb.ChaincodeActionPayload{
+						Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: prp},
+					}),
+				}},
+			}),
+		}),
+	}
+
+	b := protoutil.NewBlock(number, nil)
+	b.Data.Data = [][]byte{protoutil.MarshalOrPanic(env)}
+	b.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = txflags.NewWithValues(1, pb.TxValidationCode_VALID)
+	return b
+}
@@ -9,17 +9,29 @@ package kvledger
 import (
 	"bytes"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	"github.com/osdi23p228/fabric/common/ledger/blkstorage"
 	"github.com/osdi23p228/fabric/common/util"
 	"github.com/osdi23p228/fabric/core/ledger"
 	"github.com/osdi23p228/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/osdi23p228/fabric/core/ledger/pvtdatastorage"
+	ledgerutil "github.com/osdi23p228/fabric/core/ledger/util"
 	"github.com/osdi23p228/fabric/protoutil"
 )
 
+// purgedKeyIndex looks up the private keys whose private data was purged, so that the
+// pvt data of a collection served by a peer which removed the purged writes is verified
+// against the hashes of the individual writes of the transaction
+type purgedKeyIndex interface {
+	HasPurgedKeys(ns, coll string) (bool, error)
+	IsPurged(key *pvtdatastorage.PurgedKey, blkNum, txNum uint64) (bool, error)
+}
+
 // constructValidAndInvalidPvtData computes the valid pvt data and hash mismatch list
 // from a received pvt data list of old blocks.
-func constructValidAndInvalidPvtData(reconciledPvtdata []*ledger.ReconciledPvtdata, blockStore *blkstorage.BlockStore) (
+func constructValidAndInvalidPvtData(reconciledPvtdata []*ledger.ReconciledPvtdata, blockStore *blkstorage.BlockStore, purgedKeys purgedKeyIndex) (
 	map[uint64][]*ledger.TxPvtData, []*ledger.PvtdataHashMismatch, error,
 ) {
 	// for each block, for each transaction, retrieve the txEnvelope to
@@ -30,7 +42,7 @@ func constructValidAndInvalidPvtData(reconciledPvtdata []*ledger.ReconciledPvtda
 	var invalidPvtData []*ledger.PvtdataHashMismatch
 
 	for _, pvtdata := range reconciledPvtdata {
-		validData, invalidData, err := findValidAndInvalidPvtdata(pvtdata, blockStore)
+		validData, invalidData, err := findValidAndInvalidPvtdata(pvtdata, blockStore, purgedKeys)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -42,7 +54,7 @@ func constructValidAndInvalidPvtData(reconciledPvtdata []*ledger.ReconciledPvtda
 	return validPvtData, invalidPvtData, nil
 }
 
-func findValidAndInvalidPvtdata(reconciledPvtdata *ledger.ReconciledPvtdata, blockStore *blkstorage.BlockStore) (
+func findValidAndInvalidPvtdata(reconciledPvtdata *ledger.ReconciledPvtdata, blockStore *blkstorage.BlockStore, purgedKeys purgedKeyIndex) (
 	[]*ledger.TxPvtData, []*ledger.PvtdataHashMismatch, error,
 ) {
 	var validPvtData []*ledger.TxPvtData
@@ -58,7 +70,10 @@ func findValidAndInvalidPvtdata(reconciledPvtdata *ledger.ReconciledPvtdata, blo
 		// (2) validate passed pvtData against the pvtData hash in the tx rwset.
 		logger.Debugf("Constructing valid and invalid pvtData using rwset of blockNum:[%d], txNum:[%d]",
 			reconciledPvtdata.BlockNum, txPvtData.SeqInBlock)
-		validData, invalidData := findValidAndInvalidTxPvtData(txPvtData, txRWSet, reconciledPvtdata.BlockNum)
+		validData, invalidData, err := findValidAndInvalidTxPvtData(txPvtData, txRWSet, reconciledPvtdata.BlockNum, purgedKeys)
+		if err != nil {
+			return nil, nil, err
+		}
 
 		// (3) append validData to validPvtDataPvt list of this block and
 		// invalidData to invalidPvtData list
@@ -89,8 +104,8 @@ func retrieveRwsetForTx(blkNum uint64, txNum uint64, blockStore *blkstorage.Bloc
 	return txRWSet, nil
 }
 
-func findValidAndInvalidTxPvtData(txPvtData *ledger.TxPvtData, txRWSet *rwsetutil.TxRwSet, blkNum uint64) (
-	*ledger.TxPvtData, []*ledger.PvtdataHashMismatch,
+func findValidAndInvalidTxPvtData(txPvtData *ledger.TxPvtData, txRWSet *rwsetutil.TxRwSet, blkNum uint64, purgedKeys purgedKeyIndex) (
+	*ledger.TxPvtData, []*ledger.PvtdataHashMismatch, error,
 ) {
 	var invalidPvtData []*ledger.PvtdataHashMismatch
 	var toDeleteNsColl []*nsColl
@@ -98,7 +113,10 @@ func findValidAndInvalidTxPvtData(txPvtData *ledger.TxPvtData, txRWSet *rwsetuti
 	// find valid and invalid pvt data
 	for _, nsRwset := range txPvtData.WriteSet.NsPvtRwset {
 		txNum := txPvtData.SeqInBlock
-		invalidData, invalidNsColl := findInvalidNsPvtData(nsRwset, txRWSet, blkNum, txNum)
+		invalidData, invalidNsColl, err := findInvalidNsPvtData(nsRwset, txRWSet, blkNum, txNum, purgedKeys)
+		if err != nil {
+			return nil, nil, err
+		}
 		invalidPvtData = append(invalidPvtData, invalidData...)
 		toDeleteNsColl = append(toDeleteNsColl, invalidNsColl...)
 	}
@@ -108,9 +126,9 @@ func findValidAndInvalidTxPvtData(txPvtData *ledger.TxPvtData, txRWSet *rwsetuti
 	if len(txPvtData.WriteSet.NsPvtRwset) == 0 {
 		// denotes that all namespaces had
 		// invalid pvt data
-		return nil, invalidPvtData
+		return nil, invalidPvtData, nil
 	}
-	return txPvtData, invalidPvtData
+	return txPvtData, invalidPvtData, nil
 }
 
 // Remove removes the rwset for the given <ns, coll> tuple. If after this removal,
@@ -144,8 +162,8 @@ type nsColl struct {
 	ns, coll string
 }
 
-func findInvalidNsPvtData(nsRwset *rwset.NsPvtReadWriteSet, txRWSet *rwsetutil.TxRwSet, blkNum, txNum uint64) (
-	[]*ledger.PvtdataHashMismatch, []*nsColl,
+func findInvalidNsPvtData(nsRwset *rwset.NsPvtReadWriteSet, txRWSet *rwsetutil.TxRwSet, blkNum, txNum uint64, purgedKeys purgedKeyIndex) (
+	[]*ledger.PvtdataHashMismatch, []*nsColl, error,
 ) {
 	var invalidPvtData []*ledger.PvtdataHashMismatch
 	var invalidNsColl []*nsColl
@@ -162,6 +180,14 @@ func findInvalidNsPvtData(nsRwset *rwset.NsPvtReadWriteSet, txRWSet *rwsetutil.T
 		}
 
 		if !bytes.Equal(util.ComputeSHA256(collPvtRwset.Rwset), rwsetHash) {
+			// the writes of purged keys may have been removed from the pvt data
+			purgedWritesOnly, err := hasPurgedWritesOnly(collPvtRwset, txRWSet, ns, blkNum, txNum, purgedKeys)
+			if err != nil {
+				return nil, nil, err
+			}
+			if purgedWritesOnly {
+				continue
+			}
 			invalidPvtData = append(invalidPvtData, &ledger.PvtdataHashMismatch{
 				BlockNum:     blkNum,
 				TxNum:        txNum,
@@ -171,5 +197,85 @@ func findInvalidNsPvtData(nsRwset *rwset.NsPvtReadWriteSet, txRWSet *rwsetutil.T
 			invalidNsColl = append(invalidNsColl, &nsColl{ns, coll})
 		}
 	}
-	return invalidPvtData, invalidNsColl
+	return invalidPvtData, invalidNsColl, nil
+}
+
+// hasPurgedWritesOnly returns true if the pvt data of the collection matches the hashed
+// writes of the transaction one by one, except for the writes of the keys purged by a
+// later transaction, which may be missing. It is only checked when the collection has
+// purged keys, as the pvt data of other collections always matches the hash of the
+// collection read-write set
+func hasPurgedWritesOnly(collPvtRwset *rwset.CollectionPvtReadWriteSet, txRWSet *rwsetutil.TxRwSet, ns string,
+	blkNum, txNum uint64, purgedKeys purgedKeyIndex) (bool, error) {
+	coll := collPvtRwset.CollectionName
+	hasPurgedKeys, err := purgedKeys.HasPurgedKeys(ns, coll)
+	if err != nil || !hasPurgedKeys {
+		return false, err
+	}
+	hashedRwSet := getHashedRwSet(txRWSet, ns, coll)
+	if hashedRwSet == nil {
+		return false, nil
+	}
+	kvRWSet := &kvrwset.KVRWSet{}
+	if err := proto.Unmarshal(collPvtRwset.Rwset, kvRWSet); err != nil {
+		return false, nil
+	}
+	if len(kvRWSet.Reads) != 0 || len(kvRWSet.RangeQueriesInfo) != 0 {
+		return false, nil
+	}
+
+	hashedWrites := make(map[string]*kvrwset.KVWriteHash, len(hashedRwSet.HashedWrites))
+	for _, hashedWrite := range hashedRwSet.HashedWrites {
+		hashedWrites[string(hashedWrite.KeyHash)] = hashedWrite
+	}
+	for _, write := range kvRWSet.Writes {
+		keyHash := string(ledgerutil.ComputeStringHash(write.Key))
+		hashedWrite, ok := hashedWrites[keyHash]
+		if !ok || hashedWrite.IsDelete != rwsetutil.IsKVWriteDelete(write) {
+			return false, nil
+		}
+		if !hashedWrite.IsDelete && !bytes.Equal(ledgerutil.ComputeHash(write.Value), hashedWrite.ValueHash) {
+			return false, nil
+		}
+		delete(hashedWrites, keyHash)
+	}
+	// the pvt data of a metadata write carries the key only
+	hashedMetadataWrites := make(map[string]struct{}, len(hashedRwSet.MetadataWrites))
+	for _, hashedMetadataWrite := range hashedRwSet.MetadataWrites {
+		hashedMetadataWrites[string(hashedMetadataWrite.KeyHash)] = struct{}{}
+	}
+	for _, metadataWrite := range kvRWSet.MetadataWrites {
+		keyHash := string(ledgerutil.ComputeStringHash(metadataWrite.Key))
+		if _, ok := hashedMetadataWrites[keyHash]; !ok {
+			return false, nil
+		}
+		delete(hashedMetadataWrites, keyHash)
+	}
+
+	// the keys of the hashed writes left out of the pvt data must have been purged
+	missingKeyHashes := hashedMetadataWrites
+	for keyHash := range hashedWrites {
+		missingKeyHashes[keyHash] = struct{}{}
+	}
+	for keyHash := range missingKeyHashes {
+		purged, err := purgedKeys.IsPurged(&pvtdatastorage.PurgedKey{Namespace: ns, Collection: coll, KeyHash: []byte(keyHash)}, blkNum, txNum)
+		if err != nil || !purged {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func getHashedRwSet(txRWSet *rwsetutil.TxRwSet, ns, coll string) *kvrwset.HashedRWSet {
+	for _, nsRwSet := range txRWSet.NsRwSets {
+		if nsRwSet.NameSpace != ns {
+			continue
+		}
+		for _, collHashedRwSet := range nsRwSet.CollHashedRwSets {
+			if collHashedRwSet.CollectionName == coll {
+				return collHashedRwSet.HashedRwSet
+			}
+		}
+	}
+	return nil
 }
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
@@ -113,7 +114,7 @@ func TestConstructValidInvalidBlocksPvtData(t *testing.T) {
 		Block:          blk1,
 		PvtData:        pvtDataBlk1,
 		MissingPvtData: missingData}
-	require.NoError(t, lg.(*kvLedger).commitToPvtAndBlockStore(blockAndPvtData1))
+	require.NoError(t, lg.(*kvLedger).commitToPvtAndBlockStore(blockAndPvtData1, false))
 
 	// construct pvtData from missing data in tx3, tx6, and tx7
 	pvtdata := []*ledger.ReconciledPvtdata{
@@ -135,7 +136,7 @@ func TestConstructValidInvalidBlocksPvtData(t *testing.T) {
 		},
 	}
 
-	blocksValidPvtData, hashMismatched, err := constructValidAndInvalidPvtData(pvtdata, lg.(*kvLedger).blockStore)
+	blocksValidPvtData, hashMismatched, err := constructValidAndInvalidPvtData(pvtdata, lg.(*kvLedger).blockStore, lg.(*kvLedger).pvtdataStore)
 	require.NoError(t, err)
 	require.Equal(t, len(expectedValidBlocksPvtData), len(blocksValidPvtData))
 	require.ElementsMatch(t, expectedValidBlocksPvtData[1], blocksValidPvtData[1])
@@ -164,13 +165,127 @@ func TestConstructValidInvalidBlocksPvtData(t *testing.T) {
 		},
 	}
 
-	blocksValidPvtData, hashMismatches, err := constructValidAndInvalidPvtData(pvtdata, lg.(*kvLedger).blockStore)
+	blocksValidPvtData, hashMismatches, err := constructValidAndInvalidPvtData(pvtdata, lg.(*kvLedger).blockStore, lg.(*kvLedger).pvtdataStore)
 	require.NoError(t, err)
 	require.Len(t, blocksValidPvtData, 0)
 
 	require.ElementsMatch(t, expectedHashMismatches, hashMismatches)
 }
 
+func TestConstructValidPvtDataAfterPurge(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProviderWithCollectionConfig(
+		t,
+		[]*nsCollBtlConfig{
+			{
+				namespace: "ns-1",
+				btlConfig: map[string]uint64{"coll-1": 0},
+			},
+		},
+		conf,
+	)
+	defer provider.Close()
+
+	_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	lg, _ := provider.Create(gb)
+	defer lg.Close()
+	kvl := lg.(*kvLedger)
+
+	// block1 writes key-1 and key-2 in ns-1:coll-1, the pvtData of which is missing
+	builder := rwsetutil.NewRWSetBuilder()
+	builder.AddToPvtAndHashedWriteSet("ns-1", "coll-1", "key-1", []byte("value-1"))
+	builder.AddToPvtAndHashedWriteSet("ns-1", "coll-1", "key-2", []byte("value-2"))
+	simRes, err := builder.GetTxSimulationResults()
+	require.NoError(t, err)
+	pubSimResBytesBlk1Tx0, err := proto.Marshal(simRes.PubSimulationResults)
+	require.NoError(t, err)
+	blk1 := testutil.ConstructBlock(t, 1, protoutil.BlockHeaderHash(gb.Header), [][]byte{pubSimResBytesBlk1Tx0}, false)
+	missingData := make(ledger.TxMissingPvtDataMap)
+	missingData.Add(0, "ns-1", "coll-1", true)
+	require.NoError(t, kvl.commitToPvtAndBlockStore(&ledger.BlockAndPvtData{Block: blk1, MissingPvtData: missingData}, true))
+
+	// block2 purges key-1
+	builder = rwsetutil.NewRWSetBuilder()
+	builder.AddToPvtAndHashedPurgeSet("ns-1", "coll-1", "key-1")
+	simRes, err = builder.GetTxSimulationResults()
+	require.NoError(t, err)
+	pubSimResBytesBlk2Tx0, err := proto.Marshal(simRes.PubSimulationResults)
+	require.NoError(t, err)
+	blk2 := testutil.ConstructBlock(t, 2, protoutil.BlockHeaderHash(blk1.Header), [][]byte{pubSimResBytesBlk2Tx0}, false)
+	require.NoError(t, kvl.commitToPvtAndBlockStore(&ledger.BlockAndPvtData{
+		Block:   blk2,
+		PvtData: ledger.TxPvtDataMap{0: {SeqInBlock: 0, WriteSet: simRes.PvtSimulationResults}},
+	}, true))
+	require.Eventually(t, func() bool {
+		hasPurgedKeys, err := kvl.pvtdataStore.HasPurgedKeys("ns-1", "coll-1")
+		require.NoError(t, err)
+		return hasPurgedKeys
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// a peer that purged key-1 serves the pvtData of block1 without the write of key-1
+	pvtDataBlk1Tx0 := func(writes map[string][]byte) *ledger.TxPvtData {
+		builder := rwsetutil.NewRWSetBuilder()
+		for key, value := range writes {
+			builder.AddToPvtAndHashedWriteSet("ns-1", "coll-1", key, value)
+		}
+		simRes, err := builder.GetTxSimulationResults()
+		require.NoError(t, err)
+		return &ledger.TxPvtData{SeqInBlock: 0, WriteSet: simRes.PvtSimulationResults}
+	}
+	purgedPvtData := pvtDataBlk1Tx0(map[string][]byte{"key-2": []byte("value-2")})
+	blocksValidPvtData, hashMismatches, err := constructValidAndInvalidPvtData(
+		[]*ledger.ReconciledPvtdata{{BlockNum: 1, WriteSets: ledger.TxPvtDataMap{0: purgedPvtData}}},
+		kvl.blockStore,
+		kvl.pvtdataStore,
+	)
+	require.NoError(t, err)
+	require.Len(t, hashMismatches, 0)
+	require.Equal(t, map[uint64][]*ledger.TxPvtData{1: {purgedPvtData}}, blocksValidPvtData)
+
+	// the write of a key that is not purged cannot be left out or altered
+	for _, writes := range []map[string][]byte{
+		{"key-1": []byte("value-1")},
+		{"key-2": []byte("value-3")},
+		{"key-2": []byte("value-2"), "key-3": []byte("value-3")},
+	} {
+		blocksValidPvtData, hashMismatches, err := constructValidAndInvalidPvtData(
+			[]*ledger.ReconciledPvtdata{{BlockNum: 1, WriteSets: ledger.TxPvtDataMap{0: pvtDataBlk1Tx0(writes)}}},
+			kvl.blockStore,
+			kvl.pvtdataStore,
+		)
+		require.NoError(t, err)
+		require.Len(t, blocksValidPvtData, 0)
+		require.Equal(t, []*ledger.PvtdataHashMismatch{
+			{
+				BlockNum:     1,
+				TxNum:        0,
+				Namespace:    "ns-1",
+				Collection:   "coll-1",
+				ExpectedHash: simResPvtRwsetHash(t, pubSimResBytesBlk1Tx0),
+			},
+		}, hashMismatches)
+	}
+
+	// the reconciled pvtData is committed to the pvtdata store
+	hashMismatches, err = lg.CommitPvtDataOfOldBlocks(
+		[]*ledger.ReconciledPvtdata{{BlockNum: 1, WriteSets: ledger.TxPvtDataMap{0: purgedPvtData}}},
+		nil,
+	)
+	require.NoError(t, err)
+	require.Len(t, hashMismatches, 0)
+	blk1PvtData, err := kvl.pvtdataStore.GetPvtDataByBlockNum(1, nil)
+	require.NoError(t, err)
+	require.Len(t, blk1PvtData, 1)
+	require.True(t, proto.Equal(purgedPvtData.WriteSet, blk1PvtData[0].WriteSet))
+}
+
+func simResPvtRwsetHash(t *testing.T, pubSimResBytes []byte) []byte {
+	pubSimulationResults := &rwset.TxReadWriteSet{}
+	require.NoError(t, proto.Unmarshal(pubSimResBytes, pubSimulationResults))
+	return pubSimulationResults.NsRwset[0].CollectionHashedRwset[0].PvtRwsetHash
+}
+
 func produceSamplePvtdata(t *testing.T, txNum uint64, nsColls []string, values [][]byte) (*ledger.TxPvtData, []byte) {
 	builder := rwsetutil.NewRWSetBuilder()
 	for index, nsColl := range nsColls {
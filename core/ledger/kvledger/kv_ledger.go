@@ -26,6 +26,7 @@ import (
 	"github.com/osdi23p228/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/osdi23p228/fabric/core/ledger/kvledger/history"
 	"github.com/osdi23p228/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/osdi23p228/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/osdi23p228/fabric/core/ledger/kvledger/txmgmt/txmgr"
 	"github.com/osdi23p228/fabric/core/ledger/kvledger/txmgmt/validation"
 	"github.com/osdi23p228/fabric/core/ledger/pvtdatapolicy"
@@ -487,7 +488,7 @@ func (l *kvLedger) CommitLegacy(pvtdataAndBlock *ledger.BlockAndPvtData, commitO
 	logger.Debugf("[%s] Committing pvtdata and block [%d] to storage", l.ledgerID, blockNo)
 	l.blockAPIsRWLock.Lock()
 	defer l.blockAPIsRWLock.Unlock()
-	if err = l.commitToPvtAndBlockStore(pvtdataAndBlock); err != nil {
+	if err = l.commitToPvtAndBlockStore(pvtdataAndBlock, commitOpts.PurgePvtData); err != nil {
 		return err
 	}
 	elapsedBlockstorageAndPvtdataCommit := time.Since(startBlockstorageAndPvtdataCommit)
@@ -526,7 +527,7 @@ func (l *kvLedger) CommitLegacy(pvtdataAndBlock *ledger.BlockAndPvtData, commitO
 	return nil
 }
 
-func (l *kvLedger) commitToPvtAndBlockStore(blockAndPvtdata *ledger.BlockAndPvtData) error {
+func (l *kvLedger) commitToPvtAndBlockStore(blockAndPvtdata *ledger.BlockAndPvtData, purgePvtData bool) error {
 	pvtdataStoreHt, err := l.pvtdataStore.LastCommittedBlockHeight()
 	if err != nil {
 		return err
@@ -545,6 +546,14 @@ func (l *kvLedger) commitToPvtAndBlockStore(blockAndPvtdata *ledger.BlockAndPvtD
 		// too in the pvtdataStore as we do for the publicdata in the case of blockStore.
 		// Hence, we pass all pvtData present in the block to the pvtdataStore committer.
 		pvtData, missingPvtData := constructPvtDataAndMissingData(blockAndPvtdata)
+		// The purges of private data are recorded before the pvtdata of the block is
+		// committed, so that they get processed even if the peer crashes in between.
+		if purgePvtData {
+			purges := constructPvtDataPurges(blockAndPvtdata.Block)
+			if err := l.pvtdataStore.RecordPurges(blockNum, purges); err != nil {
+				return err
+			}
+		}
 		if err := l.pvtdataStore.Commit(blockNum, pvtData, missingPvtData); err != nil {
 			return err
 		}
@@ -567,6 +576,23 @@ func (l *kvLedger) commitToPvtAndBlockStore(blockAndPvtdata *ledger.BlockAndPvtD
 	return nil
 }
 
+// constructPvtDataPurges returns the private keys purged by the valid transactions
+// of the validated block
+func constructPvtDataPurges(block *common.Block) map[uint64][]*pvtdatastorage.PurgedKey {
+	purgedKeys := rwsetutil.PurgedKeysOfBlock(block)
+	purges := make(map[uint64][]*pvtdatastorage.PurgedKey, len(purgedKeys))
+	for txNum, keys := range purgedKeys {
+		for _, key := range keys {
+			purges[txNum] = append(purges[txNum], &pvtdatastorage.PurgedKey{
+				Namespace:  key.Namespace,
+				Collection: key.Collection,
+				KeyHash:    key.KeyHash,
+			})
+		}
+	}
+	return purges
+}
+
 func convertTxPvtDataArrayToMap(txPvtData []*ledger.TxPvtData) ledger.TxPvtDataMap {
 	txPvtDataMap := make(ledger.TxPvtDataMap)
 	for _, pvtData := range txPvtData {
@@ -604,6 +630,23 @@ func (l *kvLedger) GetMissingPvtDataInfoForMostRecentBlocks(maxBlock int) (ledge
 	return l.pvtdataStore.GetMissingPvtDataInfoForMostRecentBlocks(maxBlock)
 }
 
+// GetMissingPvtDataInfoForBlockRange returns the missing private data information of the eligible
+// collections for the most recent `maxBlock` blocks within the range [startBlock, endBlock], restricted
+// to the collections in the filter if one is provided
+func (l *kvLedger) GetMissingPvtDataInfoForBlockRange(startBlock, endBlock uint64, maxBlock int, filter ledger.PvtNsCollFilter) (ledger.MissingPvtDataInfo, error) {
+	// as in GetMissingPvtDataInfoForMostRecentBlocks, the missing pvtData info cannot be
+	// returned while the pvtdataStore is ahead of the blockStore
+	if l.isPvtstoreAheadOfBlkstore.Load().(bool) {
+		return nil, nil
+	}
+	return l.pvtdataStore.GetMissingPvtDataInfoForBlockRange(startBlock, endBlock, maxBlock, filter)
+}
+
+// GetMissingPvtDataCounts returns the number of the private data missing on the peer
+func (l *kvLedger) GetMissingPvtDataCounts() (*ledger.MissingPvtDataCounts, error) {
+	return l.pvtdataStore.GetMissingPvtDataCounts()
+}
+
 func (l *kvLedger) addBlockCommitHash(block *common.Block, updateBatchBytes []byte) {
 	var valueBytes []byte
 
@@ -673,7 +716,7 @@ func (l *kvLedger) CommitPvtDataOfOldBlocks(reconciledPvtdata []*ledger.Reconcil
 	logger.Debugf("[%s:] Comparing pvtData of [%d] old blocks against the hashes in transaction's rwset to find valid and invalid data",
 		l.ledgerID, len(reconciledPvtdata))
 
-	hashVerifiedPvtData, hashMismatches, err := constructValidAndInvalidPvtData(reconciledPvtdata, l.blockStore)
+	hashVerifiedPvtData, hashMismatches, err := constructValidAndInvalidPvtData(reconciledPvtdata, l.blockStore, l.pvtdataStore)
 	if err != nil {
 		return nil, err
 	}
@@ -307,7 +307,7 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 
 	_, _, err = ledger1.(*kvLedger).txmgr.ValidateAndPrepare(blockAndPvtdata2, true)
 	require.NoError(t, err)
-	require.NoError(t, ledger1.(*kvLedger).commitToPvtAndBlockStore(blockAndPvtdata2))
+	require.NoError(t, ledger1.(*kvLedger).commitToPvtAndBlockStore(blockAndPvtdata2, false))
 
 	// block storage should be as of block-2 but the state and history db should be as of block-1
 	checkBCSummaryForTest(t, ledger1,
@@ -362,7 +362,7 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 	)
 	_, _, err = ledger2.(*kvLedger).txmgr.ValidateAndPrepare(blockAndPvtdata3, true)
 	require.NoError(t, err)
-	require.NoError(t, ledger2.(*kvLedger).commitToPvtAndBlockStore(blockAndPvtdata3))
+	require.NoError(t, ledger2.(*kvLedger).commitToPvtAndBlockStore(blockAndPvtdata3, false))
 	// committing the transaction to state DB
 	require.NoError(t, ledger2.(*kvLedger).txmgr.Commit())
 
@@ -421,7 +421,7 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 
 	_, _, err = ledger3.(*kvLedger).txmgr.ValidateAndPrepare(blockAndPvtdata4, true)
 	require.NoError(t, err)
-	require.NoError(t, ledger3.(*kvLedger).commitToPvtAndBlockStore(blockAndPvtdata4))
+	require.NoError(t, ledger3.(*kvLedger).commitToPvtAndBlockStore(blockAndPvtdata4, false))
 	require.NoError(t, ledger3.(*kvLedger).historyDB.Commit(blockAndPvtdata4.Block))
 
 	checkBCSummaryForTest(t, ledger3,
@@ -599,7 +599,7 @@ func TestPvtDataAPIs(t *testing.T) {
 
 	sampleData := sampleDataWithPvtdataForSelectiveTx(t, bg)
 	for _, sampleDatum := range sampleData {
-		require.NoError(t, kvlgr.commitToPvtAndBlockStore(sampleDatum))
+		require.NoError(t, kvlgr.commitToPvtAndBlockStore(sampleDatum, false))
 	}
 
 	// block 2 has no pvt data
@@ -687,7 +687,7 @@ func TestCrashAfterPvtdataStoreCommit(t *testing.T) {
 	dataAtCrash := sampleData[3]
 
 	for _, sampleDatum := range dataBeforeCrash {
-		require.NoError(t, lgr.(*kvLedger).commitToPvtAndBlockStore(sampleDatum))
+		require.NoError(t, lgr.(*kvLedger).commitToPvtAndBlockStore(sampleDatum, false))
 	}
 	blockNumAtCrash := dataAtCrash.Block.Header.Number
 	var pvtdataAtCrash []*ledger.TxPvtData
@@ -739,7 +739,7 @@ func TestCrashAfterPvtdataStoreCommit(t *testing.T) {
 			},
 		},
 	}
-	require.NoError(t, lgr1.(*kvLedger).commitToPvtAndBlockStore(dataAtCrash))
+	require.NoError(t, lgr1.(*kvLedger).commitToPvtAndBlockStore(dataAtCrash, false))
 	testVerifyPvtData(t, lgr1, blockNumAtCrash, expectedPvtData)
 	bcInfo, err = lgr1.GetBlockchainInfo()
 	require.NoError(t, err)
@@ -791,7 +791,7 @@ func TestPvtStoreAheadOfBlockStore(t *testing.T) {
 
 	sampleData := sampleDataWithPvtdataForSelectiveTx(t, bg)
 	for _, d := range sampleData[0:9] { // commit block number 0 to 8
-		require.NoError(t, kvlgr.commitToPvtAndBlockStore(d))
+		require.NoError(t, kvlgr.commitToPvtAndBlockStore(d, false))
 	}
 
 	isPvtStoreAhead, err = kvlgr.isPvtDataStoreAheadOfBlockStore()
@@ -850,7 +850,7 @@ func TestPvtStoreAheadOfBlockStore(t *testing.T) {
 	require.True(t, isPvtStoreAhead)
 
 	// bring the height of BlockStore equal to pvtdataStore
-	require.NoError(t, kvlgr.commitToPvtAndBlockStore(lastBlkAndPvtData))
+	require.NoError(t, kvlgr.commitToPvtAndBlockStore(lastBlkAndPvtData, false))
 	info, err = lgr2.GetBlockchainInfo()
 	require.NoError(t, err)
 	require.Equal(t, uint64(11), info.Height)
@@ -885,19 +885,19 @@ func TestCommitToPvtAndBlockstoreError(t *testing.T) {
 	kvlgr := lgr1.(*kvLedger)
 	sampleData := sampleDataWithPvtdataForSelectiveTx(t, bg)
 	for _, d := range sampleData[0:9] { // commit block number 1 to 9
-		require.NoError(t, kvlgr.commitToPvtAndBlockStore(d))
+		require.NoError(t, kvlgr.commitToPvtAndBlockStore(d, false))
 	}
 
 	// try to write the last block again. The function should return an
 	// error from the private data store.
-	err = kvlgr.commitToPvtAndBlockStore(sampleData[8]) // block 9
+	err = kvlgr.commitToPvtAndBlockStore(sampleData[8], false) // block 9
 	require.EqualError(t, err, "Expected block number=10, received block number=9")
 
 	lastBlkAndPvtData := sampleData[9] // block 10
 	// Add the block directly to blockstore
 	kvlgr.blockStore.AddBlock(lastBlkAndPvtData.Block)
 	// Adding the same block should cause passing on the error caused by the block storgae
-	err = kvlgr.commitToPvtAndBlockStore(lastBlkAndPvtData)
+	err = kvlgr.commitToPvtAndBlockStore(lastBlkAndPvtData, false)
 	require.EqualError(t, err, "block number should have been 11 but was 10")
 	// At the end, the pvt store status should be changed
 	pvtStoreCommitHt, err := kvlgr.pvtdataStore.LastCommittedBlockHeight()
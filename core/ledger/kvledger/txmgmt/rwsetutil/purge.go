@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rwsetutil
+
+import (
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/osdi23p228/fabric/internal/pkg/txflags"
+	"github.com/osdi23p228/fabric/protoutil"
+)
+
+// PurgeMarkerEntry is the name of the metadata entry which marks the hashed delete
+// of a private key as a purge of the private data of the key. The rwset protos
+// carry no purge flag, so the marker is added as a metadata write on the hashed key.
+// Chaincode cannot set a metadata entry with this name on private data
+const PurgeMarkerEntry = "PURGE_PRIVATE_DATA"
+
+// PurgedKey identifies a private key whose private data is purged by a transaction
+type PurgedKey struct {
+	Namespace  string
+	Collection string
+	KeyHash    []byte
+}
+
+// IsPurgeMarker returns true if the hashed metadata write marks the delete of the key as a purge
+func IsPurgeMarker(metadataWrite *kvrwset.KVMetadataWriteHash) bool {
+	for _, entry := range metadataWrite.Entries {
+		if entry.Name == PurgeMarkerEntry {
+			return true
+		}
+	}
+	return false
+}
+
+// PurgedKeys returns the private keys purged by the transaction. A key is purged if the
+// transaction deletes the key and marks the delete with the purge marker
+func (txRwSet *TxRwSet) PurgedKeys() []*PurgedKey {
+	var purgedKeys []*PurgedKey
+	for _, nsRwSet := range txRwSet.NsRwSets {
+		for _, collHashedRwSet := range nsRwSet.CollHashedRwSets {
+			if collHashedRwSet.HashedRwSet == nil {
+				continue
+			}
+			deletes := map[string]bool{}
+			for _, hashedWrite := range collHashedRwSet.HashedRwSet.HashedWrites {
+				if IsKVWriteHashDelete(hashedWrite) {
+					deletes[string(hashedWrite.KeyHash)] = true
+				}
+			}
+			for _, metadataWrite := range collHashedRwSet.HashedRwSet.MetadataWrites {
+				if !deletes[string(metadataWrite.KeyHash)] || !IsPurgeMarker(metadataWrite) {
+					continue
+				}
+				purgedKeys = append(purgedKeys, &PurgedKey{
+					Namespace:  nsRwSet.NameSpace,
+					Collection: collHashedRwSet.CollectionName,
+					KeyHash:    metadataWrite.KeyHash,
+				})
+			}
+		}
+	}
+	return purgedKeys
+}
+
+// PurgedKeysOfBlock returns the private keys purged by the valid transactions of a
+// validated block, keyed by the transaction number. The transactions which cannot be
+// parsed are skipped, as the validation marks them invalid
+func PurgedKeysOfBlock(block *common.Block) map[uint64][]*PurgedKey {
+	txsFilter := txflags.ValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	purgedKeys := map[uint64][]*PurgedKey{}
+	for txNum, envBytes := range block.Data.Data {
+		if txsFilter.IsInvalid(txNum) {
+			continue
+		}
+		txRWSet, err := endorserTxRwSet(envBytes)
+		if err != nil || txRWSet == nil {
+			continue
+		}
+		if keys := txRWSet.PurgedKeys(); len(keys) > 0 {
+			purgedKeys[uint64(txNum)] = keys
+		}
+	}
+	return purgedKeys
+}
+
+func endorserTxRwSet(envBytes []byte) (*TxRwSet, error) {
+	env, err := protoutil.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+		return nil, nil
+	}
+	respPayload, err := protoutil.GetActionFromEnvelope(envBytes)
+	if err != nil {
+		return nil, err
+	}
+	txRWSet := &TxRwSet{}
+	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+		return nil, err
+	}
+	return txRWSet, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rwsetutil
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/common/ledger/testutil"
+	"github.com/osdi23p228/fabric/core/ledger/util"
+	"github.com/osdi23p228/fabric/internal/pkg/txflags"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeSet(t *testing.T) {
+	rwsetBuilder := NewRWSetBuilder()
+	rwsetBuilder.AddToPvtAndHashedPurgeSet("ns", "coll", "key1")
+	rwsetBuilder.AddToPvtAndHashedWriteSet("ns", "coll", "key2", nil)
+	rwsetBuilder.AddToPvtAndHashedWriteSet("ns", "coll", "key3", []byte("value3"))
+	rwsetBuilder.AddToHashedMetadataWriteSet("ns", "coll", "key3", map[string][]byte{PurgeMarkerEntry: {}})
+
+	simulationResults, err := rwsetBuilder.GetTxSimulationResults()
+	require.NoError(t, err)
+
+	hashedRWSet := &kvrwset.HashedRWSet{}
+	require.NoError(
+		t,
+		proto.Unmarshal(simulationResults.PubSimulationResults.NsRwset[0].CollectionHashedRwset[0].HashedRwset, hashedRWSet),
+	)
+	require.Len(t, hashedRWSet.MetadataWrites, 2)
+	require.True(t, IsPurgeMarker(hashedRWSet.MetadataWrites[0]))
+
+	pvtWSet := &kvrwset.KVRWSet{}
+	require.NoError(
+		t,
+		proto.Unmarshal(simulationResults.PvtSimulationResults.NsPvtRwset[0].CollectionPvtRwset[0].Rwset, pvtWSet),
+	)
+	require.Equal(t, &kvrwset.KVWrite{Key: "key1", IsDelete: true}, pvtWSet.Writes[0])
+	require.Len(t, pvtWSet.MetadataWrites, 1)
+	require.Equal(t, "key3", pvtWSet.MetadataWrites[0].Key)
+
+	txRWSet, err := TxRwSetFromProtoMsg(simulationResults.PubSimulationResults)
+	require.NoError(t, err)
+	require.Equal(t,
+		[]*PurgedKey{
+			{Namespace: "ns", Collection: "coll", KeyHash: util.ComputeStringHash("key1")},
+		},
+		txRWSet.PurgedKeys(),
+	)
+}
+
+func TestIsPurgeMarker(t *testing.T) {
+	require.False(t, IsPurgeMarker(&kvrwset.KVMetadataWriteHash{}))
+	require.False(t, IsPurgeMarker(&kvrwset.KVMetadataWriteHash{
+		Entries: []*kvrwset.KVMetadataEntry{{Name: "VALIDATION_PARAMETER"}},
+	}))
+	require.True(t, IsPurgeMarker(&kvrwset.KVMetadataWriteHash{
+		Entries: []*kvrwset.KVMetadataEntry{{Name: "VALIDATION_PARAMETER"}, {Name: PurgeMarkerEntry}},
+	}))
+}
+
+func TestPurgedKeysOfBlock(t *testing.T) {
+	simulationResults := func(key string) []byte {
+		rwsetBuilder := NewRWSetBuilder()
+		rwsetBuilder.AddToPvtAndHashedPurgeSet("ns", "coll", key)
+		simRes, err := rwsetBuilder.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimulationResults, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		return pubSimulationResults
+	}
+
+	bg, _ := testutil.NewBlockGenerator(t, "testchannel", false)
+	block := bg.NextBlock([][]byte{simulationResults("key1"), simulationResults("key2"), simulationResults("key3"), []byte("garbage")})
+	txsFilter := txflags.NewWithValues(4, peer.TxValidationCode_VALID)
+	txsFilter.SetFlag(1, peer.TxValidationCode_MVCC_READ_CONFLICT)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
+
+	require.Equal(t,
+		map[uint64][]*PurgedKey{
+			0: {{Namespace: "ns", Collection: "coll", KeyHash: util.ComputeStringHash("key1")}},
+			2: {{Namespace: "ns", Collection: "coll", KeyHash: util.ComputeStringHash("key3")}},
+		},
+		PurgedKeysOfBlock(block),
+	)
+}
@@ -127,6 +127,15 @@ func (b *RWSetBuilder) AddToHashedMetadataWriteSet(ns, coll, key string, metadat
 		metadataWriteMap[key] = mapToMetadataWriteHash(key, metadata)
 }
 
+// AddToPvtAndHashedPurgeSet adds the delete of a key to the private and hashed write-set
+// and marks the hashed write as a purge of the private data of the key. The marker is
+// a metadata entry in the hashed write-set only, so that it is visible to all the peers
+func (b *RWSetBuilder) AddToPvtAndHashedPurgeSet(ns, coll, key string) {
+	b.AddToPvtAndHashedWriteSet(ns, coll, key, nil)
+	b.getOrCreateCollHashedRwBuilder(ns, coll).
+		metadataWriteMap[key] = mapToMetadataWriteHash(key, map[string][]byte{PurgeMarkerEntry: {}})
+}
+
 // GetTxSimulationResults returns the proto bytes of public rwset
 // (public data + hashes of private data) and the private rwset for the transaction
 func (b *RWSetBuilder) GetTxSimulationResults() (*ledger.TxSimulationResults, error) {
@@ -88,6 +88,19 @@ func (s *txSimulator) DeletePrivateData(ns, coll, key string) error {
 	return s.SetPrivateData(ns, coll, key, nil)
 }
 
+// PurgePrivateData implements method in interface `ledger.TxSimulator`
+func (s *txSimulator) PurgePrivateData(ns, coll, key string) error {
+	if err := s.queryExecutor.validateCollName(ns, coll); err != nil {
+		return err
+	}
+	if err := s.checkWritePrecondition(key, nil); err != nil {
+		return err
+	}
+	s.writePerformed = true
+	s.rwsetBuilder.AddToPvtAndHashedPurgeSet(ns, coll, key)
+	return nil
+}
+
 // SetPrivateDataMultipleKeys implements method in interface `ledger.TxSimulator`
 func (s *txSimulator) SetPrivateDataMultipleKeys(ns, coll string, kvs map[string][]byte) error {
 	for k, v := range kvs {
@@ -114,6 +127,9 @@ func (s *txSimulator) SetPrivateDataMetadata(namespace, collection, key string,
 	if err := s.checkWritePrecondition(key, nil); err != nil {
 		return err
 	}
+	if _, ok := metadata[rwsetutil.PurgeMarkerEntry]; ok {
+		return errors.Errorf("metadata entry name [%s] is reserved for purging private data", rwsetutil.PurgeMarkerEntry)
+	}
 	s.rwsetBuilder.AddToHashedMetadataWriteSet(namespace, collection, key, metadata)
 	return nil
 }
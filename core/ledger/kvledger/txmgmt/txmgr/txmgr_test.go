@@ -1545,6 +1545,61 @@ func testTxWithPvtdataMetadata(t *testing.T, env testEnv, ns, coll string) {
 	qe.Done()
 }
 
+func TestTxWithPvtdataPurge(t *testing.T) {
+	ledgerid, ns, coll := "testtxwithpvtdatapurge", "ns", "coll"
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns", "coll"}: 1000,
+		},
+	)
+	for _, testEnv := range testEnvs {
+		t.Logf("Running test for TestEnv = %s", testEnv.getName())
+		testEnv.init(t, ledgerid, btlPolicy)
+		testTxWithPvtdataPurge(t, testEnv, ns, coll)
+		testEnv.cleanup()
+	}
+}
+
+func testTxWithPvtdataPurge(t *testing.T, env testEnv, ns, coll string) {
+	ledgerid := "testtxwithpvtdatapurge"
+	txMgr := env.getTxMgr()
+	bg, _ := testutil.NewBlockGenerator(t, ledgerid, false)
+
+	populateCollConfigForTest(t, txMgr, []collConfigkey{{"ns", "coll"}}, version.NewHeight(1, 1))
+
+	// Simulate and commit tx1 - set val and metadata for key1 and val for key2
+	s1, _ := txMgr.NewTxSimulator("test_tx1")
+	key1, value1, metadata1 := "key1", []byte("value1"), map[string][]byte{"entry1": []byte("meatadata1-entry1")}
+	key2, value2 := "key2", []byte("value2")
+	s1.SetPrivateData(ns, coll, key1, value1)
+	s1.SetPrivateDataMetadata(ns, coll, key1, metadata1)
+	s1.SetPrivateData(ns, coll, key2, value2)
+	s1.Done()
+
+	blkAndPvtdata1 := prepareNextBlockForTestFromSimulator(t, bg, s1)
+	_, _, err := txMgr.ValidateAndPrepare(blkAndPvtdata1, true)
+	require.NoError(t, err)
+	require.NoError(t, txMgr.Commit())
+
+	// Simulate and commit tx2 - purge key1
+	s2, _ := txMgr.NewTxSimulator("test_tx2")
+	require.NoError(t, s2.PurgePrivateData(ns, coll, key1))
+	err = s2.SetPrivateDataMetadata(ns, coll, key2, map[string][]byte{rwsetutil.PurgeMarkerEntry: {}})
+	require.EqualError(t, err, "metadata entry name [PURGE_PRIVATE_DATA] is reserved for purging private data")
+	s2.Done()
+
+	blkAndPvtdata2 := prepareNextBlockForTestFromSimulator(t, bg, s2)
+	_, _, err = txMgr.ValidateAndPrepare(blkAndPvtdata2, true)
+	require.NoError(t, err)
+	require.NoError(t, txMgr.Commit())
+
+	// Run query - key1 should be removed along with its hash and metadata. Key2 should be unaffected
+	qe, _ := txMgr.NewQueryExecutor("test_tx3")
+	checkPvtdataTestQueryResults(t, qe, ns, coll, key1, nil, nil)
+	checkPvtdataTestQueryResults(t, qe, ns, coll, key2, value2, nil)
+	qe.Done()
+}
+
 func prepareNextBlockForTest(t *testing.T, txMgr *LockBasedTxMgr, bg *testutil.BlockGenerator,
 	txid string, pubKVs map[string]string, pvtKVs map[string]string, isMissing bool) *ledger.BlockAndPvtData {
 	simulator, _ := txMgr.NewTxSimulator(txid)
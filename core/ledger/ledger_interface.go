@@ -295,6 +295,9 @@ type TxSimulator interface {
 	SetPrivateDataMultipleKeys(namespace, collection string, kvs map[string][]byte) error
 	// DeletePrivateData deletes the given tuple <namespace, collection, key> from private data
 	DeletePrivateData(namespace, collection, key string) error
+	// PurgePrivateData deletes the given tuple <namespace, collection, key> from private data and, once
+	// committed, purges the private values previously committed for the key from all the peers
+	PurgePrivateData(namespace, collection, key string) error
 	// SetPrivateDataMetadata sets the metadata associated with an existing key-tuple <namespace, collection, key>
 	SetPrivateDataMetadata(namespace, collection, key string, metadata map[string][]byte) error
 	// DeletePrivateDataMetadata deletes the metadata associated with an existing key-tuple <namespace, collection, key>
@@ -394,6 +397,9 @@ type BlockPvtdata struct {
 // CommitOptions encapsulates options associated with a block commit.
 type CommitOptions struct {
 	FetchPvtDataFromLedger bool
+	// PurgePvtData indicates that the private data of the keys purged by the block
+	// is to be purged from the pvtdata store, as the channel has the capability
+	PurgePvtData bool
 }
 
 // PvtCollFilter represents the set of the collection names (as keys of the map with value 'true')
@@ -513,6 +519,19 @@ type ConfigHistoryRetriever interface {
 // MissingPvtDataTracker allows getting information about the private data that is not missing on the peer
 type MissingPvtDataTracker interface {
 	GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (MissingPvtDataInfo, error)
+	// GetMissingPvtDataInfoForBlockRange returns the missing private data information of the eligible
+	// collections, including the deprioritized ones, for the most recent `maxBlocks` blocks within the
+	// range [startBlock, endBlock]. A non-nil filter restricts the information to its collections
+	GetMissingPvtDataInfoForBlockRange(startBlock, endBlock uint64, maxBlocks int, filter PvtNsCollFilter) (MissingPvtDataInfo, error)
+	// GetMissingPvtDataCounts returns the number of the private data missing on the peer
+	GetMissingPvtDataCounts() (*MissingPvtDataCounts, error)
+}
+
+// MissingPvtDataCounts contains the number of the private data, one per transaction and collection,
+// missing on the peer for the collections the peer is eligible and ineligible for
+type MissingPvtDataCounts struct {
+	Eligible   uint64
+	Ineligible uint64
 }
 
 // MissingPvtDataInfo is a map of block number to MissingBlockPvtdataInfo
@@ -28,6 +28,12 @@ var (
 	collElgKeyPrefix                 = []byte{6}
 	lastUpdatedOldBlocksKey          = []byte{7}
 	elgDeprioritizedMissingDataGroup = []byte{8}
+	purgeMarkerKeyPrefix             = []byte{9}
+	purgedKeyIndexPrefix             = []byte{10}
+	keyWriteIndexPrefix              = []byte{11}
+	keyWriteIndexBuiltKey            = []byte{12}
+	elgMissingDataCollIndexPrefix    = []byte{13}
+	elgMissingDataCollIndexBuiltKey  = []byte{14}
 
 	nilByte    = byte(0)
 	emptyValue = []byte{}
@@ -108,6 +114,104 @@ func decodeDataValue(datavalueBytes []byte) (*rwset.CollectionPvtReadWriteSet, e
 	return collPvtdata, err
 }
 
+func encodePurgeMarkerKey(marker *purgeMarker) []byte {
+	key := append(purgeMarkerKeyPrefix, version.NewHeight(marker.blkNum, marker.txNum).ToBytes()...)
+	return append(key, encodePurgedKey(marker.key)...)
+}
+
+func decodePurgeMarkerKey(keyBytes []byte) (*purgeMarker, error) {
+	height, n, err := version.NewHeightFromBytes(keyBytes[1:])
+	if err != nil {
+		return nil, err
+	}
+	splittedKey := bytes.SplitN(keyBytes[n+1:], []byte{nilByte}, 3)
+	if len(splittedKey) != 3 {
+		return nil, errors.Errorf("invalid purge marker key: %x", keyBytes)
+	}
+	return &purgeMarker{
+		blkNum: height.BlockNum,
+		txNum:  height.TxNum,
+		key: &PurgedKey{
+			Namespace:  string(splittedKey[0]),
+			Collection: string(splittedKey[1]),
+			KeyHash:    splittedKey[2],
+		},
+	}, nil
+}
+
+func encodePurgedKeyIndexKey(key *PurgedKey) []byte {
+	return append(purgedKeyIndexPrefix, encodePurgedKey(key)...)
+}
+
+// getPurgedKeyIndexKeysForRangeScan returns the range of the index entries of the purged
+// keys of the collection
+func getPurgedKeyIndexKeysForRangeScan(ns, coll string) ([]byte, []byte) {
+	startKey := append(purgedKeyIndexPrefix, []byte(ns)...)
+	startKey = append(startKey, nilByte)
+	startKey = append(startKey, []byte(coll)...)
+	startKey = append(startKey, nilByte)
+	endKey := append([]byte{}, startKey...)
+	endKey[len(endKey)-1]++
+	return startKey, endKey
+}
+
+func encodePurgedKey(key *PurgedKey) []byte {
+	keyBytes := append([]byte(key.Namespace), nilByte)
+	keyBytes = append(keyBytes, []byte(key.Collection)...)
+	keyBytes = append(keyBytes, nilByte)
+	return append(keyBytes, key.KeyHash...)
+}
+
+// encodeKeyWriteIndexKey encodes the key of the index entry recording that the transaction
+// at the height `blkNum`/`txNum` wrote the key. The hash of the key is prefixed with its length,
+// so that the entries of a key can be scanned by the height of the transactions
+func encodeKeyWriteIndexKey(key *PurgedKey, blkNum, txNum uint64) []byte {
+	return append(encodeKeyWriteIndexKeyPrefix(key), version.NewHeight(blkNum, txNum).ToBytes()...)
+}
+
+func encodeKeyWriteIndexKeyPrefix(key *PurgedKey) []byte {
+	keyBytes := append(keyWriteIndexPrefix, []byte(key.Namespace)...)
+	keyBytes = append(keyBytes, nilByte)
+	keyBytes = append(keyBytes, []byte(key.Collection)...)
+	keyBytes = append(keyBytes, nilByte)
+	keyBytes = append(keyBytes, proto.EncodeVarint(uint64(len(key.KeyHash)))...)
+	return append(keyBytes, key.KeyHash...)
+}
+
+func decodeKeyWriteIndexKey(keyBytes []byte) (*PurgedKey, *version.Height, error) {
+	splittedKey := bytes.SplitN(keyBytes[1:], []byte{nilByte}, 3)
+	if len(splittedKey) != 3 {
+		return nil, nil, errors.Errorf("invalid key write index key: %x", keyBytes)
+	}
+	keyHashLen, n := proto.DecodeVarint(splittedKey[2])
+	if n == 0 || uint64(len(splittedKey[2])) < uint64(n)+keyHashLen {
+		return nil, nil, errors.Errorf("invalid key write index key: %x", keyBytes)
+	}
+	height, _, err := version.NewHeightFromBytes(splittedKey[2][uint64(n)+keyHashLen:])
+	if err != nil {
+		return nil, nil, err
+	}
+	return &PurgedKey{
+		Namespace:  string(splittedKey[0]),
+		Collection: string(splittedKey[1]),
+		KeyHash:    splittedKey[2][n : uint64(n)+keyHashLen],
+	}, height, nil
+}
+
+// getKeyWriteIndexKeysForRangeScan returns the range of the index entries of the writes
+// of the key by the transactions preceding the height `blkNum`/`txNum`
+func getKeyWriteIndexKeysForRangeScan(key *PurgedKey, blkNum, txNum uint64) ([]byte, []byte) {
+	startKey := encodeKeyWriteIndexKey(key, 0, 0)
+	endKey := encodeKeyWriteIndexKey(key, blkNum, txNum)
+	return startKey, endKey
+}
+
+func getPurgeMarkerKeysForRangeScan(maxBlkNum uint64) ([]byte, []byte) {
+	startKey := append(purgeMarkerKeyPrefix, version.NewHeight(0, 0).ToBytes()...)
+	endKey := append(purgeMarkerKeyPrefix, version.NewHeight(maxBlkNum+1, 0).ToBytes()...)
+	return startKey, endKey
+}
+
 func encodeElgPrioMissingDataKey(key *missingDataKey) []byte {
 	// When missing pvtData reconciler asks for missing data info,
 	// it is necessary to pass the missing pvtdata info associated with
@@ -140,6 +244,26 @@ func decodeElgMissingDataKey(keyBytes []byte) *missingDataKey {
 	return key
 }
 
+// encodeElgMissingDataCollIndexKey encodes the key of the index entry which denotes that
+// the block misses private data of the eligible collection, whether prioritized or not.
+// As for the ineligible missing data, the entries are ordered by collection first.
+func encodeElgMissingDataCollIndexKey(key *missingDataKey) []byte {
+	encKey := append(elgMissingDataCollIndexPrefix, []byte(key.ns)...)
+	encKey = append(encKey, nilByte)
+	encKey = append(encKey, []byte(key.coll)...)
+	encKey = append(encKey, nilByte)
+	return append(encKey, []byte(encodeReverseOrderVarUint64(key.blkNum))...)
+}
+
+func decodeElgMissingDataCollIndexKey(keyBytes []byte) *missingDataKey {
+	key := &missingDataKey{nsCollBlk: nsCollBlk{}}
+	splittedKey := bytes.SplitN(keyBytes[1:], []byte{nilByte}, 3) //encoded bytes for blknum may contain empty bytes
+	key.ns = string(splittedKey[0])
+	key.coll = string(splittedKey[1])
+	key.blkNum, _ = decodeReverseOrderVarUint64(splittedKey[2])
+	return key
+}
+
 func encodeInelgMissingDataKey(key *missingDataKey) []byte {
 	encKey := append(inelgMissingDataGroup, []byte(key.ns)...)
 	encKey = append(encKey, nilByte)
@@ -197,6 +321,41 @@ func createRangeScanKeysForElgMissingData(blkNum uint64, group []byte) ([]byte,
 	return startKey, endKey
 }
 
+func createRangeScanKeysForElgMissingDataInRange(startBlkNum, endBlkNum uint64, group []byte) ([]byte, []byte) {
+	startKey := append(group, encodeReverseOrderVarUint64(endBlkNum)...)
+	endKey := append(group, encodeReverseOrderVarUint64(0)...)
+	if startBlkNum > 0 {
+		endKey = append(group, encodeReverseOrderVarUint64(startBlkNum-1)...)
+	}
+	return startKey, endKey
+}
+
+func createRangeScanKeysForElgMissingDataCollIndex(startBlkNum, endBlkNum uint64, ns, coll string) ([]byte, []byte) {
+	startKey := encodeElgMissingDataCollIndexKey(
+		&missingDataKey{
+			nsCollBlk: nsCollBlk{
+				ns:     ns,
+				coll:   coll,
+				blkNum: endBlkNum,
+			},
+		},
+	)
+	var endBlkNumExcl uint64
+	if startBlkNum > 0 {
+		endBlkNumExcl = startBlkNum - 1
+	}
+	endKey := encodeElgMissingDataCollIndexKey(
+		&missingDataKey{
+			nsCollBlk: nsCollBlk{
+				ns:     ns,
+				coll:   coll,
+				blkNum: endBlkNumExcl,
+			},
+		},
+	)
+	return startKey, endKey
+}
+
 func createRangeScanKeysForInelgMissingData(maxBlkNum uint64, ns, coll string) ([]byte, []byte) {
 	startKey := encodeInelgMissingDataKey(
 		&missingDataKey{
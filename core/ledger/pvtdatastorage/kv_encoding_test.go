@@ -11,6 +11,7 @@ import (
 	math "math"
 	"testing"
 
+	"github.com/osdi23p228/fabric/core/ledger/internal/version"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,6 +22,61 @@ func TestDataKeyEncoding(t *testing.T) {
 	require.Equal(t, dataKey1, datakey2)
 }
 
+func TestPurgeMarkerKeyEncoding(t *testing.T) {
+	marker := &purgeMarker{
+		blkNum: 2,
+		txNum:  5,
+		key:    &PurgedKey{Namespace: "ns1", Collection: "coll1", KeyHash: []byte{0, 1, 0, 2}},
+	}
+	decodedMarker, err := decodePurgeMarkerKey(encodePurgeMarkerKey(marker))
+	require.NoError(t, err)
+	require.Equal(t, marker, decodedMarker)
+
+	startKey, endKey := getPurgeMarkerKeysForRangeScan(2)
+	require.True(t, bytes.Compare(startKey, encodePurgeMarkerKey(marker)) <= 0)
+	require.True(t, bytes.Compare(encodePurgeMarkerKey(marker), endKey) < 0)
+	marker.blkNum = 3
+	require.True(t, bytes.Compare(encodePurgeMarkerKey(marker), endKey) >= 0)
+}
+
+func TestKeyWriteIndexKeyEncoding(t *testing.T) {
+	key := &PurgedKey{Namespace: "ns1", Collection: "coll1", KeyHash: []byte{0, 1, 0, 2}}
+	decodedKey, height, err := decodeKeyWriteIndexKey(encodeKeyWriteIndexKey(key, 2, 5))
+	require.NoError(t, err)
+	require.Equal(t, key, decodedKey)
+	require.Equal(t, version.NewHeight(2, 5), height)
+
+	startKey, endKey := getKeyWriteIndexKeysForRangeScan(key, 2, 5)
+	require.True(t, bytes.Compare(startKey, encodeKeyWriteIndexKey(key, 2, 4)) <= 0)
+	require.True(t, bytes.Compare(encodeKeyWriteIndexKey(key, 2, 4), endKey) < 0)
+	require.True(t, bytes.Compare(encodeKeyWriteIndexKey(key, 2, 5), endKey) >= 0)
+
+	// the writes of a key whose hash is prefixed by the hash of the key are out of the range
+	otherKey := &PurgedKey{Namespace: "ns1", Collection: "coll1", KeyHash: []byte{0, 1, 0, 2, 0}}
+	otherKeyBytes := encodeKeyWriteIndexKey(otherKey, 1, 0)
+	require.False(t, bytes.Compare(startKey, otherKeyBytes) <= 0 && bytes.Compare(otherKeyBytes, endKey) < 0)
+}
+
+func TestElgMissingDataCollIndexKeyEncoding(t *testing.T) {
+	key := &missingDataKey{nsCollBlk: nsCollBlk{ns: "ns1", coll: "coll1", blkNum: 256}}
+	require.Equal(t, key, decodeElgMissingDataCollIndexKey(encodeElgMissingDataCollIndexKey(key)))
+
+	keyOfBlock := func(blkNum uint64) []byte {
+		return encodeElgMissingDataCollIndexKey(&missingDataKey{nsCollBlk: nsCollBlk{ns: "ns1", coll: "coll1", blkNum: blkNum}})
+	}
+	startKey, endKey := createRangeScanKeysForElgMissingDataCollIndex(5, 10, "ns1", "coll1")
+	for blkNum := uint64(5); blkNum <= 10; blkNum++ {
+		require.True(t, bytes.Compare(startKey, keyOfBlock(blkNum)) <= 0)
+		require.True(t, bytes.Compare(keyOfBlock(blkNum), endKey) < 0)
+	}
+	require.True(t, bytes.Compare(keyOfBlock(11), startKey) < 0)
+	require.True(t, bytes.Compare(keyOfBlock(4), endKey) >= 0)
+
+	// the entries of a collection whose name is prefixed by the name of the collection are out of the range
+	otherKey := encodeElgMissingDataCollIndexKey(&missingDataKey{nsCollBlk: nsCollBlk{ns: "ns1", coll: "coll10", blkNum: 7}})
+	require.False(t, bytes.Compare(startKey, otherKey) <= 0 && bytes.Compare(otherKey, endKey) < 0)
+}
+
 func TestDataKeyRange(t *testing.T) {
 	blockNum := uint64(20)
 	startKey, endKey := datakeyRange(blockNum)
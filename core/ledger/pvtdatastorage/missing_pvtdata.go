@@ -0,0 +1,172 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/osdi23p228/fabric/core/ledger"
+)
+
+// missingDataCounts keeps the number of the private data of the eligible and the
+// ineligible collections which are missing on the peer, counted per transaction and
+// collection. The counts are computed when the store is opened and are then adjusted
+// as the missing data entries are written, reconciled and purged.
+type missingDataCounts struct {
+	eligible   int64
+	ineligible int64
+}
+
+func (c *missingDataCounts) add(eligible, ineligible int64) {
+	atomic.AddInt64(&c.eligible, eligible)
+	atomic.AddInt64(&c.ineligible, ineligible)
+}
+
+func (c *missingDataCounts) get() *ledger.MissingPvtDataCounts {
+	return &ledger.MissingPvtDataCounts{
+		Eligible:   uint64(atomic.LoadInt64(&c.eligible)),
+		Ineligible: uint64(atomic.LoadInt64(&c.ineligible)),
+	}
+}
+
+func (s *Store) initMissingDataCounts() error {
+	s.missingDataCounts = &missingDataCounts{}
+	for _, group := range [][]byte{elgPrioritizedMissingDataGroup, elgDeprioritizedMissingDataGroup, inelgMissingDataGroup} {
+		itr, err := s.db.GetIterator(group, []byte{group[0] + 1})
+		if err != nil {
+			return err
+		}
+		count := int64(0)
+		for itr.Next() {
+			bitmap, err := decodeMissingDataValue(itr.Value())
+			if err != nil {
+				itr.Release()
+				return err
+			}
+			count += int64(bitmap.Count())
+		}
+		itr.Release()
+		if group[0] == inelgMissingDataGroup[0] {
+			s.missingDataCounts.add(0, count)
+		} else {
+			s.missingDataCounts.add(count, 0)
+		}
+	}
+	return nil
+}
+
+// countMissingData returns the number of the transactions missing the private data
+// of the given missing data entry
+func (s *Store) countMissingData(key []byte) (int64, error) {
+	encMissingData, err := s.db.Get(key)
+	if err != nil || encMissingData == nil {
+		return 0, err
+	}
+	bitmap, err := decodeMissingDataValue(encMissingData)
+	if err != nil {
+		return 0, err
+	}
+	return int64(bitmap.Count()), nil
+}
+
+// buildElgMissingDataCollIndex indexes the eligible missing data entries by collection
+// for a store committed before the index was introduced
+func (s *Store) buildElgMissingDataCollIndex() error {
+	built, err := s.db.Get(elgMissingDataCollIndexBuiltKey)
+	if err != nil || built != nil {
+		return err
+	}
+	batch := s.db.NewUpdateBatch()
+	indexedEntries := 0
+	for _, group := range [][]byte{elgPrioritizedMissingDataGroup, elgDeprioritizedMissingDataGroup} {
+		itr, err := s.db.GetIterator(group, []byte{group[0] + 1})
+		if err != nil {
+			return err
+		}
+		for itr.Next() {
+			batch.Put(encodeElgMissingDataCollIndexKey(decodeElgMissingDataKey(itr.Key())), emptyValue)
+			indexedEntries++
+			if batch.Len() > s.maxBatchSize {
+				if err := s.db.WriteBatch(batch, true); err != nil {
+					itr.Release()
+					return err
+				}
+				batch.Reset()
+			}
+		}
+		itr.Release()
+	}
+	batch.Put(elgMissingDataCollIndexBuiltKey, emptyValue)
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Infof("[%s] - Indexed [%d] eligible missing data entries by collection", s.ledgerid, indexedEntries)
+	return nil
+}
+
+// getMissingDataOfCollections returns the missing private data information of the collections
+// in the filter, for the most recent `maxBlock` blocks within the range [startBlock, endBlock].
+// Only the index entries of these collections are scanned.
+func (s *Store) getMissingDataOfCollections(startBlock, endBlock uint64, maxBlock int, filter ledger.PvtNsCollFilter) (ledger.MissingPvtDataInfo, error) {
+	lastCommittedBlock := atomic.LoadUint64(&s.lastCommittedBlock)
+
+	// each collection contributes the entries of up to `maxBlock` blocks
+	var missingDataKeys []*missingDataKey
+	for ns, colls := range filter {
+		for coll := range colls {
+			startKey, endKey := createRangeScanKeysForElgMissingDataCollIndex(startBlock, endBlock, ns, coll)
+			itr, err := s.db.GetIterator(startKey, endKey)
+			if err != nil {
+				return nil, err
+			}
+			numBlocks := 0
+			for numBlocks < maxBlock && itr.Next() {
+				missingDataKey := decodeElgMissingDataCollIndexKey(itr.Key())
+				expired, err := isExpired(missingDataKey.nsCollBlk, s.btlPolicy, lastCommittedBlock)
+				if err != nil {
+					itr.Release()
+					return nil, err
+				}
+				if expired {
+					continue
+				}
+				missingDataKeys = append(missingDataKeys, missingDataKey)
+				numBlocks++
+			}
+			itr.Release()
+		}
+	}
+
+	// only the entries of the most recent `maxBlock` blocks are retained
+	sort.Slice(missingDataKeys, func(i, j int) bool {
+		return missingDataKeys[i].blkNum > missingDataKeys[j].blkNum
+	})
+	missingPvtDataInfo := make(ledger.MissingPvtDataInfo)
+	for _, missingDataKey := range missingDataKeys {
+		if _, ok := missingPvtDataInfo[missingDataKey.blkNum]; !ok && len(missingPvtDataInfo) == maxBlock {
+			break
+		}
+		for _, key := range [][]byte{encodeElgPrioMissingDataKey(missingDataKey), encodeElgDeprioMissingDataKey(missingDataKey)} {
+			encMissingData, err := s.db.Get(key)
+			if err != nil {
+				return nil, err
+			}
+			if encMissingData == nil {
+				continue
+			}
+			bitmap, err := decodeMissingDataValue(encMissingData)
+			if err != nil {
+				return nil, err
+			}
+			for index, isSet := bitmap.NextSet(0); isSet; index, isSet = bitmap.NextSet(index + 1) {
+				missingPvtDataInfo.Add(missingDataKey.blkNum, uint64(index), missingDataKey.ns, missingDataKey.coll)
+			}
+		}
+	}
+	return missingPvtDataInfo, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"testing"
+
+	"github.com/osdi23p228/fabric/core/ledger"
+	btltestutil "github.com/osdi23p228/fabric/core/ledger/pvtdatapolicy/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElgMissingDataCollIndexBuiltOnOpen(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+			{"ns-1", "coll-2"}: 0,
+		},
+	)
+	env := NewTestStoreEnv(t, "TestElgMissingDataCollIndexBuiltOnOpen", btlPolicy, pvtDataConf())
+	defer env.Cleanup()
+	store := env.TestStore
+
+	require.NoError(t, store.Commit(0, nil, nil))
+	blk1MissingData := make(ledger.TxMissingPvtDataMap)
+	blk1MissingData.Add(1, "ns-1", "coll-1", true)
+	blk1MissingData.Add(2, "ns-1", "coll-2", true)
+	require.NoError(t, store.Commit(1, nil, blk1MissingData))
+
+	// emulate a store committed before the index was introduced
+	batch := store.db.NewUpdateBatch()
+	batch.Delete(elgMissingDataCollIndexBuiltKey)
+	for _, coll := range []string{"coll-1", "coll-2"} {
+		batch.Delete(encodeElgMissingDataCollIndexKey(&missingDataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: coll, blkNum: 1}}))
+	}
+	require.NoError(t, store.db.WriteBatch(batch, true))
+
+	filter := ledger.NewPvtNsCollFilter()
+	filter.Add("ns-1", "coll-2")
+	missingDataInfo, err := store.GetMissingPvtDataInfoForBlockRange(0, 1, 10, filter)
+	require.NoError(t, err)
+	require.Empty(t, missingDataInfo)
+
+	env.CloseAndReopen()
+	store = env.TestStore
+	missingDataInfo, err = store.GetMissingPvtDataInfoForBlockRange(0, 1, 10, filter)
+	require.NoError(t, err)
+	expectedMissingDataInfo := make(ledger.MissingPvtDataInfo)
+	expectedMissingDataInfo.Add(1, 2, "ns-1", "coll-2")
+	require.Equal(t, expectedMissingDataInfo, missingDataInfo)
+}
+
+func TestMissingDataCountsOnCollElgEnabled(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+		},
+	)
+	env := NewTestStoreEnv(t, "TestMissingDataCountsOnCollElgEnabled", btlPolicy, pvtDataConf())
+	defer env.Cleanup()
+	store := env.TestStore
+
+	require.NoError(t, store.Commit(0, nil, nil))
+	blk1MissingData := make(ledger.TxMissingPvtDataMap)
+	blk1MissingData.Add(1, "ns-1", "coll-1", false)
+	blk1MissingData.Add(2, "ns-1", "coll-1", false)
+	require.NoError(t, store.Commit(1, nil, blk1MissingData))
+
+	counts, err := store.GetMissingPvtDataCounts()
+	require.NoError(t, err)
+	require.Equal(t, &ledger.MissingPvtDataCounts{Ineligible: 2}, counts)
+
+	require.NoError(t, store.ProcessCollsEligibilityEnabled(2, map[string][]string{"ns-1": {"coll-1"}}))
+	testutilWaitForCollElgProcToFinish(store)
+	require.NoError(t, store.Commit(2, nil, nil))
+
+	counts, err = store.GetMissingPvtDataCounts()
+	require.NoError(t, err)
+	require.Equal(t, &ledger.MissingPvtDataCounts{Eligible: 2}, counts)
+
+	// the converted missing data can be looked up by collection
+	filter := ledger.NewPvtNsCollFilter()
+	filter.Add("ns-1", "coll-1")
+	missingDataInfo, err := store.GetMissingPvtDataInfoForBlockRange(0, 2, 10, filter)
+	require.NoError(t, err)
+	require.Len(t, missingDataInfo[1], 2)
+}
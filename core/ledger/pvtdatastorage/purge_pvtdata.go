@@ -0,0 +1,382 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/osdi23p228/fabric/common/ledger/util/leveldbhelper"
+	"github.com/osdi23p228/fabric/core/ledger/internal/version"
+	"github.com/osdi23p228/fabric/core/ledger/util"
+)
+
+// PurgedKey identifies a private key whose private data is purged by a transaction
+type PurgedKey struct {
+	Namespace  string
+	Collection string
+	KeyHash    []byte
+}
+
+// purgeMarker records that the transaction at the height `blkNum`/`txNum`
+// purged a key, until the private data of the key written by the preceding
+// transactions is removed from the store
+type purgeMarker struct {
+	blkNum, txNum uint64
+	key           *PurgedKey
+}
+
+// RecordPurges records the private keys purged by the transactions of the block `blockNum`,
+// keyed by the transaction number. It is expected to be invoked before the pvt data of the
+// block is committed. Once the block is committed, the private data of the keys written by
+// the preceding transactions is removed from the store in the background.
+func (s *Store) RecordPurges(blockNum uint64, purges map[uint64][]*PurgedKey) error {
+	if len(purges) == 0 {
+		return nil
+	}
+	batch := s.db.NewUpdateBatch()
+	for txNum, keys := range purges {
+		for _, key := range keys {
+			batch.Put(encodePurgeMarkerKey(&purgeMarker{blkNum: blockNum, txNum: txNum, key: key}), emptyValue)
+		}
+	}
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	s.purgesRecorded = true
+	return nil
+}
+
+func (s *Store) launchPurgeProc() {
+	go func() {
+		defer close(s.purgeProcDone)
+		for {
+			// process the purge markers when the store is opened - in case
+			// there are unprocessed markers from previous run
+			if err := s.processPurgeMarkers(); err != nil {
+				logger.Errorw("failed to purge private data", "err", err)
+			}
+			select {
+			case <-s.purgeNotification:
+			case <-s.purgeProcStop:
+				return
+			}
+		}
+	}()
+}
+
+// stopPurgeProc stops the processing of the purge markers, and waits for the
+// processing in progress to complete
+func (s *Store) stopPurgeProc() {
+	close(s.purgeProcStop)
+	<-s.purgeProcDone
+}
+
+func (s *Store) notifyPurges() {
+	select {
+	case s.purgeNotification <- struct{}{}:
+	default:
+	}
+}
+
+// processPurgeMarkers removes the writes of the purged keys from the data entries
+// preceding the purges of the committed blocks. The data entries are looked up from
+// the index of the key writes. The markers are then replaced by an index of the
+// purged keys, so that the private data of old blocks committed later on is
+// filtered as well.
+func (s *Store) processPurgeMarkers() error {
+	s.purgerLock.Lock()
+	defer s.purgerLock.Unlock()
+
+	// the emptiness of the store is read from the db, as the field of the
+	// store is updated by the commits concurrently
+	isEmpty, lastCommittedBlock, err := s.getLastCommittedBlockNum()
+	if err != nil || isEmpty {
+		return err
+	}
+	markers, err := s.retrievePurgeMarkers(lastCommittedBlock)
+	if err != nil || len(markers) == 0 {
+		return err
+	}
+
+	batch := s.db.NewUpdateBatch()
+	purgedDataEntries := make(map[string]*rwset.CollectionPvtReadWriteSet)
+	for _, marker := range markers {
+		if err := s.purgeKeyWrites(marker, purgedDataEntries, batch); err != nil {
+			return err
+		}
+	}
+	for dataKeyBytes, dataValue := range purgedDataEntries {
+		dataValueBytes, err := encodeDataValue(dataValue)
+		if err != nil {
+			return err
+		}
+		batch.Put([]byte(dataKeyBytes), dataValueBytes)
+	}
+
+	// markers are retrieved in the order of their height, so that the index
+	// ends up with the height of the latest purge of a key
+	for _, marker := range markers {
+		batch.Delete(encodePurgeMarkerKey(marker))
+		batch.Put(encodePurgedKeyIndexKey(marker.key), version.NewHeight(marker.blkNum, marker.txNum).ToBytes())
+	}
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+
+	logger.Infof("[%s] - Purged the private data of [%d] keys till block number [%d]", s.ledgerid, len(markers), lastCommittedBlock)
+	return nil
+}
+
+// purgeKeyWrites removes the writes of the key purged by the marker from the data entries
+// of the preceding transactions, and deletes their index entries. The data entries modified
+// are accumulated in `purgedDataEntries`, keyed by the encoded data key, so that a data entry
+// is modified consistently by multiple markers
+func (s *Store) purgeKeyWrites(marker *purgeMarker, purgedDataEntries map[string]*rwset.CollectionPvtReadWriteSet, batch *leveldbhelper.UpdateBatch) error {
+	startKey, endKey := getKeyWriteIndexKeysForRangeScan(marker.key, marker.blkNum, marker.txNum)
+	itr, err := s.db.GetIterator(startKey, endKey)
+	if err != nil {
+		return err
+	}
+	defer itr.Release()
+
+	for itr.Next() {
+		indexKeyBytes := itr.Key()
+		_, height, err := decodeKeyWriteIndexKey(indexKeyBytes)
+		if err != nil {
+			return err
+		}
+		batch.Delete(indexKeyBytes)
+
+		dataKeyBytes := encodeDataKey(&dataKey{
+			nsCollBlk: nsCollBlk{ns: marker.key.Namespace, coll: marker.key.Collection, blkNum: height.BlockNum},
+			txNum:     height.TxNum,
+		})
+		dataValue, ok := purgedDataEntries[string(dataKeyBytes)]
+		if !ok {
+			dataValueBytes, err := s.db.Get(dataKeyBytes)
+			if err != nil {
+				return err
+			}
+			if dataValueBytes == nil {
+				continue
+			}
+			if dataValue, err = decodeDataValue(dataValueBytes); err != nil {
+				return err
+			}
+		}
+		purged, err := removeWrites(dataValue, func(keyHash []byte) bool {
+			return bytes.Equal(marker.key.KeyHash, keyHash)
+		})
+		if err != nil {
+			return err
+		}
+		if purged {
+			purgedDataEntries[string(dataKeyBytes)] = dataValue
+		}
+	}
+	return nil
+}
+
+// buildKeyWriteIndex indexes the writes of the data entries committed before the
+// index of the key writes was introduced. The data entries in v11 format are not
+// indexed, as their private data is not purged
+func (s *Store) buildKeyWriteIndex() error {
+	built, err := s.db.Get(keyWriteIndexBuiltKey)
+	if err != nil || built != nil {
+		return err
+	}
+	batch := s.db.NewUpdateBatch()
+	itr, err := s.db.GetIterator(pvtDataKeyPrefix, []byte{pvtDataKeyPrefix[0] + 1})
+	if err != nil {
+		return err
+	}
+	defer itr.Release()
+
+	indexedEntries := 0
+	for itr.Next() {
+		dataKeyBytes := itr.Key()
+		v11Fmt, err := v11Format(dataKeyBytes)
+		if err != nil {
+			return err
+		}
+		if v11Fmt {
+			continue
+		}
+		dataKey, err := decodeDatakey(dataKeyBytes)
+		if err != nil {
+			return err
+		}
+		dataValue, err := decodeDataValue(itr.Value())
+		if err != nil {
+			return err
+		}
+		for _, indexKey := range keyWriteIndexKeys(dataKey, dataValue) {
+			batch.Put(indexKey, emptyValue)
+		}
+		indexedEntries++
+		if batch.Len() > s.maxBatchSize {
+			if err := s.db.WriteBatch(batch, true); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	batch.Put(keyWriteIndexBuiltKey, emptyValue)
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Infof("[%s] - Indexed the key writes of [%d] private data entries", s.ledgerid, indexedEntries)
+	return nil
+}
+
+// keyWriteIndexKeys returns the keys of the index entries of the keys written, or
+// whose metadata is written, by the data entry. The store does not otherwise interpret
+// the read-write set, so a data entry whose read-write set can't be decoded is not
+// indexed, as none of its keys could be purged anyway
+func keyWriteIndexKeys(dataKey *dataKey, collPvtdata *rwset.CollectionPvtReadWriteSet) [][]byte {
+	kvRWSet := &kvrwset.KVRWSet{}
+	if err := proto.Unmarshal(collPvtdata.Rwset, kvRWSet); err != nil {
+		logger.Debugf("Not indexing the key writes of the data entry [%#v], as its read-write set can't be decoded: %s", dataKey, err)
+		return nil
+	}
+
+	var indexKeys [][]byte
+	indexed := make(map[string]struct{})
+	addIndexKey := func(key string) {
+		if _, ok := indexed[key]; ok {
+			return
+		}
+		indexed[key] = struct{}{}
+		indexKeys = append(indexKeys, encodeKeyWriteIndexKey(
+			&PurgedKey{Namespace: dataKey.ns, Collection: dataKey.coll, KeyHash: util.ComputeStringHash(key)},
+			dataKey.blkNum, dataKey.txNum,
+		))
+	}
+	for _, write := range kvRWSet.Writes {
+		addIndexKey(write.Key)
+	}
+	for _, metadataWrite := range kvRWSet.MetadataWrites {
+		addIndexKey(metadataWrite.Key)
+	}
+	return indexKeys
+}
+
+func (s *Store) retrievePurgeMarkers(maxBlkNum uint64) ([]*purgeMarker, error) {
+	startKey, endKey := getPurgeMarkerKeysForRangeScan(maxBlkNum)
+	itr, err := s.db.GetIterator(startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Release()
+
+	var markers []*purgeMarker
+	for itr.Next() {
+		marker, err := decodePurgeMarkerKey(itr.Key())
+		if err != nil {
+			return nil, err
+		}
+		markers = append(markers, marker)
+	}
+	return markers, nil
+}
+
+// HasPurgedKeys returns true if the private data of any key of the collection was purged
+// and the purge was processed, i.e., the data entries of the collection may lack writes
+// recorded in the hashed read-write sets of their transactions
+func (s *Store) HasPurgedKeys(ns, coll string) (bool, error) {
+	startKey, endKey := getPurgedKeyIndexKeysForRangeScan(ns, coll)
+	itr, err := s.db.GetIterator(startKey, endKey)
+	if err != nil {
+		return false, err
+	}
+	defer itr.Release()
+	return itr.Next(), itr.Error()
+}
+
+// IsPurged returns true if the write of the key by the transaction at the height
+// `blkNum`/`txNum` was purged by a later transaction
+func (s *Store) IsPurged(key *PurgedKey, blkNum, txNum uint64) (bool, error) {
+	purgeHeightBytes, err := s.db.Get(encodePurgedKeyIndexKey(key))
+	if err != nil || purgeHeightBytes == nil {
+		return false, err
+	}
+	purgeHeight, _, err := version.NewHeightFromBytes(purgeHeightBytes)
+	if err != nil {
+		return false, err
+	}
+	return version.NewHeight(blkNum, txNum).Compare(purgeHeight) < 0, nil
+}
+
+// removePurgedWrites removes from the data entry the writes of the keys which
+// were purged by a later transaction
+func (s *Store) removePurgedWrites(dataKey *dataKey, dataValue *rwset.CollectionPvtReadWriteSet) error {
+	dataHeight := version.NewHeight(dataKey.blkNum, dataKey.txNum)
+	var lookupErr error
+	_, err := removeWrites(dataValue, func(keyHash []byte) bool {
+		purgeHeightBytes, err := s.db.Get(encodePurgedKeyIndexKey(&PurgedKey{dataKey.ns, dataKey.coll, keyHash}))
+		if err != nil {
+			lookupErr = err
+			return false
+		}
+		if purgeHeightBytes == nil {
+			return false
+		}
+		purgeHeight, _, err := version.NewHeightFromBytes(purgeHeightBytes)
+		if err != nil {
+			lookupErr = err
+			return false
+		}
+		return dataHeight.Compare(purgeHeight) < 0
+	})
+	if lookupErr != nil {
+		return lookupErr
+	}
+	return err
+}
+
+// removeWrites removes the writes and the metadata writes of the keys whose
+// hash is matched from the collection pvt data, and returns whether any write
+// was removed
+func removeWrites(collPvtdata *rwset.CollectionPvtReadWriteSet, isPurged func(keyHash []byte) bool) (bool, error) {
+	kvRWSet := &kvrwset.KVRWSet{}
+	if err := proto.Unmarshal(collPvtdata.Rwset, kvRWSet); err != nil {
+		return false, err
+	}
+
+	purged := false
+	var writes []*kvrwset.KVWrite
+	for _, write := range kvRWSet.Writes {
+		if isPurged(util.ComputeStringHash(write.Key)) {
+			purged = true
+			continue
+		}
+		writes = append(writes, write)
+	}
+	var metadataWrites []*kvrwset.KVMetadataWrite
+	for _, metadataWrite := range kvRWSet.MetadataWrites {
+		if isPurged(util.ComputeStringHash(metadataWrite.Key)) {
+			purged = true
+			continue
+		}
+		metadataWrites = append(metadataWrites, metadataWrite)
+	}
+	if !purged {
+		return false, nil
+	}
+
+	kvRWSet.Writes = writes
+	kvRWSet.MetadataWrites = metadataWrites
+	rwsetBytes, err := proto.Marshal(kvRWSet)
+	if err != nil {
+		return false, err
+	}
+	collPvtdata.Rwset = rwsetBytes
+	return true, nil
+}
@@ -0,0 +1,223 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/osdi23p228/fabric/core/ledger"
+	btltestutil "github.com/osdi23p228/fabric/core/ledger/pvtdatapolicy/testutil"
+	"github.com/osdi23p228/fabric/core/ledger/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgePvtData(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+			{"ns-1", "coll-2"}: 0,
+		},
+	)
+	env := NewTestStoreEnv(t, "TestPurgePvtData", btlPolicy, pvtDataConf())
+	defer env.Cleanup()
+	store := env.TestStore
+
+	purgedKey := &PurgedKey{
+		Namespace:  "ns-1",
+		Collection: "coll-1",
+		KeyHash:    util.ComputeStringHash("key-ns-1-coll-1"),
+	}
+
+	blk1MissingData := make(ledger.TxMissingPvtDataMap)
+	blk1MissingData.Add(4, "ns-1", "coll-1", true)
+	require.NoError(t, store.Commit(0, nil, nil))
+	require.NoError(t, store.Commit(1, []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+	}, blk1MissingData))
+
+	// block 2 purges the key in tx 1, which is written again by tx 3
+	require.NoError(t, store.RecordPurges(2, map[uint64][]*PurgedKey{1: {purgedKey}}))
+	require.NoError(t, store.Commit(2, []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 0, []string{"ns-1:coll-1"}),
+		produceSamplePvtdata(t, 3, []string{"ns-1:coll-1"}),
+	}, nil))
+
+	require.Eventually(t, func() bool {
+		markers, err := store.retrievePurgeMarkers(2)
+		require.NoError(t, err)
+		return len(markers) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	for _, coll := range []struct {
+		name      string
+		hasPurged bool
+	}{{"coll-1", true}, {"coll-2", false}, {"coll", false}} {
+		hasPurged, err := store.HasPurgedKeys("ns-1", coll.name)
+		require.NoError(t, err)
+		require.Equal(t, coll.hasPurged, hasPurged, coll.name)
+	}
+	for _, write := range []struct {
+		blkNum, txNum uint64
+		purged        bool
+	}{{1, 2, true}, {2, 0, true}, {2, 1, false}, {2, 3, false}} {
+		purged, err := store.IsPurged(purgedKey, write.blkNum, write.txNum)
+		require.NoError(t, err)
+		require.Equal(t, write.purged, purged, "write at %d/%d", write.blkNum, write.txNum)
+	}
+	purged, err := store.IsPurged(&PurgedKey{Namespace: "ns-1", Collection: "coll-2", KeyHash: purgedKey.KeyHash}, 1, 2)
+	require.NoError(t, err)
+	require.False(t, purged)
+
+	blk1PvtData, err := store.GetPvtDataByBlockNum(1, nil)
+	require.NoError(t, err)
+	require.Len(t, blk1PvtData, 1)
+	require.Equal(t, []string{}, testWrittenKeys(t, blk1PvtData[0], "ns-1", "coll-1"))
+	require.Equal(t, []string{"key-ns-1-coll-2"}, testWrittenKeys(t, blk1PvtData[0], "ns-1", "coll-2"))
+
+	blk2PvtData, err := store.GetPvtDataByBlockNum(2, nil)
+	require.NoError(t, err)
+	require.Len(t, blk2PvtData, 2)
+	require.Equal(t, []string{}, testWrittenKeys(t, blk2PvtData[0], "ns-1", "coll-1"))
+	require.Equal(t, []string{"key-ns-1-coll-1"}, testWrittenKeys(t, blk2PvtData[1], "ns-1", "coll-1"))
+
+	// the private data of the key reconciled for an old block is purged as well
+	require.NoError(t, store.CommitPvtDataOfOldBlocks(map[uint64][]*ledger.TxPvtData{
+		1: {produceSamplePvtdata(t, 4, []string{"ns-1:coll-1"})},
+	}, nil))
+	blk1PvtData, err = store.GetPvtDataByBlockNum(1, nil)
+	require.NoError(t, err)
+	require.Len(t, blk1PvtData, 2)
+	require.Equal(t, []string{}, testWrittenKeys(t, blk1PvtData[1], "ns-1", "coll-1"))
+}
+
+func TestPurgeMarkersProcessedOnOpen(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+		},
+	)
+	env := NewTestStoreEnv(t, "TestPurgeMarkersProcessedOnOpen", btlPolicy, pvtDataConf())
+	defer env.Cleanup()
+	store := env.TestStore
+
+	require.NoError(t, store.Commit(0, nil, nil))
+	require.NoError(t, store.Commit(1, []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1"}),
+	}, nil))
+
+	// the markers of a block which is not committed yet are left pending
+	require.NoError(t, store.RecordPurges(2, map[uint64][]*PurgedKey{
+		0: {{Namespace: "ns-1", Collection: "coll-1", KeyHash: util.ComputeStringHash("key-ns-1-coll-1")}},
+	}))
+	require.NoError(t, store.processPurgeMarkers())
+	markers, err := store.retrievePurgeMarkers(2)
+	require.NoError(t, err)
+	require.Len(t, markers, 1)
+
+	// commit the block without notifying the purge of its markers, as if
+	// the peer crashed before processing them
+	store.purgesRecorded = false
+	require.NoError(t, store.Commit(2, nil, nil))
+
+	env.CloseAndReopen()
+	store = env.TestStore
+	require.Eventually(t, func() bool {
+		markers, err := store.retrievePurgeMarkers(2)
+		require.NoError(t, err)
+		return len(markers) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	blk1PvtData, err := store.GetPvtDataByBlockNum(1, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{}, testWrittenKeys(t, blk1PvtData[0], "ns-1", "coll-1"))
+}
+
+func TestKeyWriteIndex(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 1,
+		},
+	)
+	env := NewTestStoreEnv(t, "TestKeyWriteIndex", btlPolicy, pvtDataConf())
+	defer env.Cleanup()
+	store := env.TestStore
+	key := &PurgedKey{
+		Namespace:  "ns-1",
+		Collection: "coll-1",
+		KeyHash:    util.ComputeStringHash("key-ns-1-coll-1"),
+	}
+
+	require.NoError(t, store.Commit(0, nil, nil))
+	require.NoError(t, store.Commit(1, []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1"}),
+	}, nil))
+	require.Equal(t, []uint64{2}, testKeyWriteTxNums(t, store, key))
+
+	// the index is rebuilt for a store committed before it was introduced
+	require.NoError(t, store.db.Delete(keyWriteIndexBuiltKey, true))
+	require.NoError(t, store.db.Delete(encodeKeyWriteIndexKey(key, 1, 2), true))
+	env.CloseAndReopen()
+	store = env.TestStore
+	require.Equal(t, []uint64{2}, testKeyWriteTxNums(t, store, key))
+
+	// the index entries are deleted along with the expired private data
+	require.NoError(t, store.purgeExpiredData(0, 3))
+	require.Empty(t, testKeyWriteTxNums(t, store, key))
+}
+
+func testKeyWriteTxNums(t *testing.T, store *Store, key *PurgedKey) []uint64 {
+	startKey, endKey := getKeyWriteIndexKeysForRangeScan(key, math.MaxUint64, 0)
+	itr, err := store.db.GetIterator(startKey, endKey)
+	require.NoError(t, err)
+	defer itr.Release()
+
+	var txNums []uint64
+	for itr.Next() {
+		_, height, err := decodeKeyWriteIndexKey(itr.Key())
+		require.NoError(t, err)
+		txNums = append(txNums, height.TxNum)
+	}
+	return txNums
+}
+
+func testWrittenKeys(t *testing.T, txPvtData *ledger.TxPvtData, ns, coll string) []string {
+	keys := []string{}
+	for _, nsPvtRwset := range txPvtData.WriteSet.NsPvtRwset {
+		if nsPvtRwset.Namespace != ns {
+			continue
+		}
+		for _, collPvtRwset := range nsPvtRwset.CollectionPvtRwset {
+			if collPvtRwset.CollectionName != coll {
+				continue
+			}
+			kvRWSet := &kvrwset.KVRWSet{}
+			require.NoError(t, proto.Unmarshal(collPvtRwset.Rwset, kvRWSet))
+			for _, write := range kvRWSet.Writes {
+				keys = append(keys, write.Key)
+			}
+		}
+	}
+	return keys
+}
+
+func TestPurgeProcStoppedOnClose(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(map[[2]string]uint64{})
+	env := NewTestStoreEnv(t, "TestPurgeProcStoppedOnClose", btlPolicy, pvtDataConf())
+	defer env.Cleanup()
+	store := env.TestStore
+
+	env.CloseAndReopen()
+	select {
+	case <-store.purgeProcDone:
+	default:
+		t.Fatal("the purge markers are still processed after the provider is closed")
+	}
+}
@@ -62,12 +62,19 @@ func (s *Store) CommitPvtDataOfOldBlocks(
 	if err != nil {
 		return err
 	}
-	return s.db.WriteBatch(batch, true)
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	s.missingDataCounts.add(-p.reconciledMissingData, 0)
+	return nil
 }
 
 type oldBlockDataProcessor struct {
 	*Store
 	entries *entriesForPvtDataOfOldBlocks
+	// reconciledMissingData is the number of the missing
+	// data entries cleared by the reconciled data
+	reconciledMissingData int64
 }
 
 func (p *oldBlockDataProcessor) prepareDataAndExpiryEntries(blocksPvtData map[uint64][]*ledger.TxPvtData) error {
@@ -82,6 +89,10 @@ func (p *oldBlockDataProcessor) prepareDataAndExpiryEntries(blocksPvtData map[ui
 		nsCollBlk := dataEntry.key.nsCollBlk
 		txNum := dataEntry.key.txNum
 
+		if err := p.removePurgedWrites(dataEntry.key, dataEntry.value); err != nil {
+			return err
+		}
+
 		expKey, err := p.constructExpiryKey(dataEntry)
 		if err != nil {
 			return err
@@ -122,6 +133,7 @@ func (p *oldBlockDataProcessor) prepareMissingDataEntriesToReflectReconciledData
 		}
 		if prioMissingData != nil && prioMissingData.Test(txNum) {
 			p.entries.prioritizedMissingDataEntries[key] = prioMissingData.Clear(txNum)
+			p.reconciledMissingData++
 			continue
 		}
 
@@ -131,6 +143,7 @@ func (p *oldBlockDataProcessor) prepareMissingDataEntriesToReflectReconciledData
 		}
 		if deprioMissingData != nil && deprioMissingData.Test(txNum) {
 			p.entries.deprioritizedMissingDataEntries[key] = deprioMissingData.Clear(txNum)
+			p.reconciledMissingData++
 		}
 	}
 
@@ -272,9 +285,43 @@ func (p *oldBlockDataProcessor) constructDBUpdateBatch() (*leveldbhelper.UpdateB
 		return nil, errors.WithMessage(err, "error while adding eligible deprioritized missing data entries to the update batch")
 	}
 
+	if err := p.addElgMissingDataCollIndexEntriesTo(batch); err != nil {
+		return nil, errors.WithMessage(err, "error while adding eligible missing data index entries to the update batch")
+	}
+
 	return batch, nil
 }
 
+// addElgMissingDataCollIndexEntriesTo keeps the index entry of each updated eligible
+// missing data entry only while some prioritized or deprioritized missing data remains
+func (p *oldBlockDataProcessor) addElgMissingDataCollIndexEntriesTo(batch *leveldbhelper.UpdateBatch) error {
+	updated := make(map[nsCollBlk]struct{})
+	for nsCollBlk := range p.entries.prioritizedMissingDataEntries {
+		updated[nsCollBlk] = struct{}{}
+	}
+	for nsCollBlk := range p.entries.deprioritizedMissingDataEntries {
+		updated[nsCollBlk] = struct{}{}
+	}
+
+	for nsCollBlk := range updated {
+		prioMissingData, err := p.getPrioMissingDataFromEntriesOrStore(nsCollBlk)
+		if err != nil {
+			return err
+		}
+		deprioMissingData, err := p.getDeprioMissingDataFromEntriesOrStore(nsCollBlk)
+		if err != nil {
+			return err
+		}
+		key := encodeElgMissingDataCollIndexKey(&missingDataKey{nsCollBlk: nsCollBlk})
+		if (prioMissingData == nil || prioMissingData.None()) && (deprioMissingData == nil || deprioMissingData.None()) {
+			batch.Delete(key)
+			continue
+		}
+		batch.Put(key, emptyValue)
+	}
+	return nil
+}
+
 type entriesForPvtDataOfOldBlocks struct {
 	dataEntries                     map[dataKey]*rwset.CollectionPvtReadWriteSet
 	expiryEntries                   map[expiryKey]*ExpiryData
@@ -292,6 +339,10 @@ func (e *entriesForPvtDataOfOldBlocks) addDataEntriesTo(batch *leveldbhelper.Upd
 			return errors.Wrap(err, "error while encoding data value")
 		}
 		batch.Put(key, val)
+
+		for _, indexKey := range keyWriteIndexKeys(&dataKey, pvtData) {
+			batch.Put(indexKey, emptyValue)
+		}
 	}
 	return nil
 }
@@ -8,6 +8,7 @@ package pvtdatastorage
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -30,6 +31,11 @@ var (
 type Provider struct {
 	dbProvider *leveldbhelper.Provider
 	pvtData    *PrivateDataConfig
+
+	// stores are the stores opened by the provider, whose background
+	// processing is stopped when the provider is closed
+	storesLock sync.Mutex
+	stores     []*Store
 }
 
 // PrivateDataConfig encapsulates the configuration for private data storage on the ledger
@@ -55,6 +61,18 @@ type Store struct {
 	lastCommittedBlock uint64
 	purgerLock         sync.Mutex
 	collElgProcSync    *collElgProcSync
+	// purgesRecorded is set when the purges of the block being
+	// committed are recorded, so that they get processed once
+	// the block is committed
+	purgesRecorded    bool
+	purgeNotification chan struct{}
+	// purgeProcStop stops the processing of the purge markers,
+	// and purgeProcDone is closed once it is stopped
+	purgeProcStop chan struct{}
+	purgeProcDone chan struct{}
+	// missingDataCounts is the number of the private data missing
+	// on the peer, as reported by GetMissingPvtDataCounts
+	missingDataCounts *missingDataCounts
 	// After committing the pvtdata of old blocks,
 	// the `isLastUpdatedOldBlocksSet` is set to true.
 	// Once the stateDB is updated with these pvtdata,
@@ -142,11 +160,27 @@ func (p *Provider) OpenStore(ledgerid string) (*Store, error) {
 			notification: make(chan bool, 1),
 			procComplete: make(chan bool, 1),
 		},
+		purgeNotification: make(chan struct{}, 1),
+		purgeProcStop:     make(chan struct{}),
+		purgeProcDone:     make(chan struct{}),
 	}
 	if err := s.initState(); err != nil {
 		return nil, err
 	}
+	if err := s.buildKeyWriteIndex(); err != nil {
+		return nil, err
+	}
+	if err := s.buildElgMissingDataCollIndex(); err != nil {
+		return nil, err
+	}
+	if err := s.initMissingDataCounts(); err != nil {
+		return nil, err
+	}
 	s.launchCollElgProc()
+	s.launchPurgeProc()
+	p.storesLock.Lock()
+	p.stores = append(p.stores, s)
+	p.storesLock.Unlock()
 	logger.Debugf("Pvtdata store opened. Initial state: isEmpty [%t], lastCommittedBlock [%d]",
 		s.isEmpty, s.lastCommittedBlock)
 	return s, nil
@@ -154,6 +188,13 @@ func (p *Provider) OpenStore(ledgerid string) (*Store, error) {
 
 // Close closes the store
 func (p *Provider) Close() {
+	p.storesLock.Lock()
+	stores := p.stores
+	p.stores = nil
+	p.storesLock.Unlock()
+	for _, s := range stores {
+		s.stopPurgeProc()
+	}
 	p.dbProvider.Close()
 }
 
@@ -227,6 +268,10 @@ func (s *Store) Commit(blockNum uint64, pvtData []*ledger.TxPvtData, missingPvtD
 			return err
 		}
 		batch.Put(key, val)
+
+		for _, indexKey := range keyWriteIndexKeys(dataEntry.key, dataEntry.value) {
+			batch.Put(indexKey, emptyValue)
+		}
 	}
 
 	for _, expiryEntry := range storeEntries.expiryEntries {
@@ -237,6 +282,7 @@ func (s *Store) Commit(blockNum uint64, pvtData []*ledger.TxPvtData, missingPvtD
 		batch.Put(key, val)
 	}
 
+	var elgMissingData, inelgMissingData int64
 	for missingDataKey, missingDataValue := range storeEntries.elgMissingDataEntries {
 		key = encodeElgPrioMissingDataKey(&missingDataKey)
 
@@ -244,6 +290,8 @@ func (s *Store) Commit(blockNum uint64, pvtData []*ledger.TxPvtData, missingPvtD
 			return err
 		}
 		batch.Put(key, val)
+		batch.Put(encodeElgMissingDataCollIndexKey(&missingDataKey), emptyValue)
+		elgMissingData += int64(missingDataValue.Count())
 	}
 
 	for missingDataKey, missingDataValue := range storeEntries.inelgMissingDataEntries {
@@ -253,6 +301,7 @@ func (s *Store) Commit(blockNum uint64, pvtData []*ledger.TxPvtData, missingPvtD
 			return err
 		}
 		batch.Put(key, val)
+		inelgMissingData += int64(missingDataValue.Count())
 	}
 
 	committingBlockNum := s.nextBlockNum()
@@ -264,8 +313,13 @@ func (s *Store) Commit(blockNum uint64, pvtData []*ledger.TxPvtData, missingPvtD
 
 	s.isEmpty = false
 	atomic.StoreUint64(&s.lastCommittedBlock, committingBlockNum)
+	s.missingDataCounts.add(elgMissingData, inelgMissingData)
 	logger.Debugf("Committed private data for block [%d]", committingBlockNum)
 	s.performPurgeIfScheduled(committingBlockNum)
+	if s.purgesRecorded {
+		s.purgesRecorded = false
+		s.notifyPurges()
+	}
 	return nil
 }
 
@@ -425,18 +479,80 @@ func (s *Store) GetMissingPvtDataInfoForMostRecentBlocks(maxBlock int) (ledger.M
 	return s.getMissingData(elgPrioritizedMissingDataGroup, maxBlock)
 }
 
-func (s *Store) getMissingData(group []byte, maxBlock int) (ledger.MissingPvtDataInfo, error) {
+// GetMissingPvtDataInfoForBlockRange returns the missing private data information for the most
+// recent `maxBlock` blocks within the range [startBlock, endBlock] which miss at least a private
+// data of a eligible collection. Unlike GetMissingPvtDataInfoForMostRecentBlocks, both the
+// prioritized and the deprioritized missing data are returned. When a filter is provided, only
+// the missing private data of the collections in the filter is returned.
+func (s *Store) GetMissingPvtDataInfoForBlockRange(startBlock, endBlock uint64, maxBlock int, filter ledger.PvtNsCollFilter) (ledger.MissingPvtDataInfo, error) {
+	if maxBlock < 1 {
+		return nil, nil
+	}
+	lastCommittedBlock := atomic.LoadUint64(&s.lastCommittedBlock)
+	if endBlock > lastCommittedBlock {
+		endBlock = lastCommittedBlock
+	}
+	if startBlock > endBlock {
+		return nil, nil
+	}
+	if filter != nil {
+		return s.getMissingDataOfCollections(startBlock, endBlock, maxBlock, filter)
+	}
+
 	missingPvtDataInfo := make(ledger.MissingPvtDataInfo)
-	numberOfBlockProcessed := 0
-	lastProcessedBlock := uint64(0)
-	isMaxBlockLimitReached := false
+	for _, group := range [][]byte{elgPrioritizedMissingDataGroup, elgDeprioritizedMissingDataGroup} {
+		startKey, endKey := createRangeScanKeysForElgMissingDataInRange(startBlock, endBlock, group)
+		groupMissingPvtDataInfo, err := s.getMissingDataInRange(startKey, endKey, maxBlock)
+		if err != nil {
+			return nil, err
+		}
+		for blkNum, blockMissingPvtDataInfo := range groupMissingPvtDataInfo {
+			for txNum, collsMissingPvtDataInfo := range blockMissingPvtDataInfo {
+				for _, collMissingPvtDataInfo := range collsMissingPvtDataInfo {
+					missingPvtDataInfo.Add(blkNum, txNum, collMissingPvtDataInfo.Namespace, collMissingPvtDataInfo.Collection)
+				}
+			}
+		}
+	}
+
+	// each group contributes up to `maxBlock` blocks, only the most recent ones are retained
+	if len(missingPvtDataInfo) > maxBlock {
+		blkNums := make([]uint64, 0, len(missingPvtDataInfo))
+		for blkNum := range missingPvtDataInfo {
+			blkNums = append(blkNums, blkNum)
+		}
+		sort.Slice(blkNums, func(i, j int) bool { return blkNums[i] > blkNums[j] })
+		for _, blkNum := range blkNums[maxBlock:] {
+			delete(missingPvtDataInfo, blkNum)
+		}
+	}
+	return missingPvtDataInfo, nil
+}
 
+// GetMissingPvtDataCounts returns the number of the private data of the eligible and the
+// ineligible collections which are missing on the peer, counted per transaction and collection.
+// The counts are maintained as the missing data is committed, reconciled and purged, so the
+// missing data which is expired is counted until the purger removes it.
+func (s *Store) GetMissingPvtDataCounts() (*ledger.MissingPvtDataCounts, error) {
+	return s.missingDataCounts.get(), nil
+}
+
+func (s *Store) getMissingData(group []byte, maxBlock int) (ledger.MissingPvtDataInfo, error) {
 	// as we are not acquiring a read lock, new blocks can get committed while we
 	// construct the MissingPvtDataInfo. As a result, lastCommittedBlock can get
 	// changed. To ensure consistency, we atomically load the lastCommittedBlock value
 	lastCommittedBlock := atomic.LoadUint64(&s.lastCommittedBlock)
 
 	startKey, endKey := createRangeScanKeysForElgMissingData(lastCommittedBlock, group)
+	return s.getMissingDataInRange(startKey, endKey, maxBlock)
+}
+
+func (s *Store) getMissingDataInRange(startKey, endKey []byte, maxBlock int) (ledger.MissingPvtDataInfo, error) {
+	missingPvtDataInfo := make(ledger.MissingPvtDataInfo)
+	numberOfBlockProcessed := 0
+	lastProcessedBlock := uint64(0)
+	isMaxBlockLimitReached := false
+
 	dbItr, err := s.db.GetIterator(startKey, endKey)
 	if err != nil {
 		return nil, err
@@ -461,7 +577,7 @@ func (s *Store) getMissingData(group []byte, maxBlock int) (ledger.MissingPvtDat
 		// data (less possibility of expiring now), such scenario would be rare. In the
 		// best case, we can load the latest lastCommittedBlock value here atomically to
 		// make this scenario very rare.
-		lastCommittedBlock = atomic.LoadUint64(&s.lastCommittedBlock)
+		lastCommittedBlock := atomic.LoadUint64(&s.lastCommittedBlock)
 		expired, err := isExpired(missingDataKey.nsCollBlk, s.btlPolicy, lastCommittedBlock)
 		if err != nil {
 			return nil, err
@@ -547,24 +663,39 @@ func (s *Store) purgeExpiredData(minBlkNum, maxBlkNum uint64) error {
 		dataKeys, missingDataKeys := deriveKeys(expiryEntry)
 
 		for _, dataKey := range dataKeys {
+			if err := s.deleteKeyWriteIndexEntries(dataKey, batch); err != nil {
+				return err
+			}
 			batch.Delete(encodeDataKey(dataKey))
 		}
 
+		var elgMissingData, inelgMissingData int64
 		for _, missingDataKey := range missingDataKeys {
+			for _, key := range [][]byte{encodeElgPrioMissingDataKey(missingDataKey), encodeElgDeprioMissingDataKey(missingDataKey)} {
+				count, err := s.countMissingData(key)
+				if err != nil {
+					return err
+				}
+				elgMissingData += count
+				batch.Delete(key)
+			}
+			count, err := s.countMissingData(encodeInelgMissingDataKey(missingDataKey))
+			if err != nil {
+				return err
+			}
+			inelgMissingData += count
 			batch.Delete(
-				encodeElgPrioMissingDataKey(missingDataKey),
-			)
-			batch.Delete(
-				encodeElgDeprioMissingDataKey(missingDataKey),
+				encodeInelgMissingDataKey(missingDataKey),
 			)
 			batch.Delete(
-				encodeInelgMissingDataKey(missingDataKey),
+				encodeElgMissingDataCollIndexKey(missingDataKey),
 			)
 		}
 
 		if err := s.db.WriteBatch(batch, false); err != nil {
 			return err
 		}
+		s.missingDataCounts.add(-elgMissingData, -inelgMissingData)
 		batch.Reset()
 	}
 
@@ -572,6 +703,22 @@ func (s *Store) purgeExpiredData(minBlkNum, maxBlkNum uint64) error {
 	return nil
 }
 
+// deleteKeyWriteIndexEntries deletes the index entries of the writes of the data entry
+func (s *Store) deleteKeyWriteIndexEntries(dataKey *dataKey, batch *leveldbhelper.UpdateBatch) error {
+	dataValueBytes, err := s.db.Get(encodeDataKey(dataKey))
+	if err != nil || dataValueBytes == nil {
+		return err
+	}
+	dataValue, err := decodeDataValue(dataValueBytes)
+	if err != nil {
+		return err
+	}
+	for _, indexKey := range keyWriteIndexKeys(dataKey, dataValue) {
+		batch.Delete(indexKey)
+	}
+	return nil
+}
+
 func (s *Store) retrieveExpiryEntries(minBlkNum, maxBlkNum uint64) ([]*expiryEntry, error) {
 	startKey, endKey := getExpiryKeysForRangeScan(minBlkNum, maxBlkNum)
 	logger.Debugf("retrieveExpiryEntries(): startKey=%#v, endKey=%#v", startKey, endKey)
@@ -628,6 +775,17 @@ func (s *Store) processCollElgEvents() error {
 	defer eventItr.Release()
 	batch := s.db.NewUpdateBatch()
 	totalEntriesConverted := 0
+	// convertedMissingData is the number of the missing data converted
+	// to eligible in the batch
+	var convertedMissingData int64
+	writeBatch := func() {
+		if err := s.db.WriteBatch(batch, true); err != nil {
+			logger.Errorw("failed to convert ineligible missing data entries to eligible", "err", err)
+			return
+		}
+		s.missingDataCounts.add(convertedMissingData, -convertedMissingData)
+		convertedMissingData = 0
+	}
 
 	for eventItr.Next() {
 		collElgKey, collElgVal := eventItr.Key(), eventItr.Value()
@@ -659,9 +817,16 @@ func (s *Store) processCollElgEvents() error {
 						encodeElgPrioMissingDataKey(modifiedKey),
 						copyVal,
 					)
+					batch.Put(encodeElgMissingDataCollIndexKey(modifiedKey), emptyValue)
+					bitmap, err := decodeMissingDataValue(copyVal)
+					if err != nil {
+						collItr.Release()
+						return err
+					}
+					convertedMissingData += int64(bitmap.Count())
 					collEntriesConverted++
 					if batch.Len() > s.maxBatchSize {
-						s.db.WriteBatch(batch, true)
+						writeBatch()
 						batch.Reset()
 						sleepTime := time.Duration(s.batchesInterval)
 						logger.Infof("Going to sleep for %d milliseconds between batches. Entries for [ns=%s, coll=%s] converted so far = %d",
@@ -680,7 +845,7 @@ func (s *Store) processCollElgEvents() error {
 		batch.Delete(collElgKey) // delete the collection eligibility event key as well
 	} // event loop
 
-	s.db.WriteBatch(batch, true)
+	writeBatch()
 	logger.Debugf("Converted [%d] ineligible missing data entries to eligible", totalEntriesConverted)
 	return nil
 }
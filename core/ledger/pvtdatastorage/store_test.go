@@ -297,6 +297,155 @@ func TestGetMissingDataInfo(t *testing.T) {
 
 }
 
+func TestGetMissingDataInfoForBlockRange(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+			{"ns-1", "coll-2"}: 0,
+		},
+	)
+	env := NewTestStoreEnv(t, "TestGetMissingDataInfoForBlockRange", btlPolicy, pvtDataConf())
+	defer env.Cleanup()
+	store := env.TestStore
+
+	require.NoError(t, store.Commit(0, nil, nil))
+	for blkNum := uint64(1); blkNum <= 4; blkNum++ {
+		missingData := make(ledger.TxMissingPvtDataMap)
+		missingData.Add(1, "ns-1", "coll-1", true)
+		missingData.Add(2, "ns-1", "coll-2", true)
+		require.NoError(t, store.Commit(blkNum, nil, missingData))
+	}
+
+	// deprioritize the missing data of block 3 and 4 for the collection coll-2
+	deprioritizedList := make(ledger.MissingPvtDataInfo)
+	deprioritizedList.Add(3, 2, "ns-1", "coll-2")
+	deprioritizedList.Add(4, 2, "ns-1", "coll-2")
+	require.NoError(t, store.CommitPvtDataOfOldBlocks(nil, deprioritizedList))
+
+	// the missing data of coll-1 in block 2 is reconciled
+	require.NoError(t, store.CommitPvtDataOfOldBlocks(map[uint64][]*ledger.TxPvtData{
+		2: {produceSamplePvtdata(t, 1, []string{"ns-1:coll-1"})},
+	}, nil))
+
+	expectedMissingDataInfo := func(colls []string, blkNums ...uint64) ledger.MissingPvtDataInfo {
+		missingDataInfo := make(ledger.MissingPvtDataInfo)
+		for _, blkNum := range blkNums {
+			for _, coll := range colls {
+				if coll == "coll-1" && blkNum != 2 {
+					missingDataInfo.Add(blkNum, 1, "ns-1", "coll-1")
+				}
+				if coll == "coll-2" {
+					missingDataInfo.Add(blkNum, 2, "ns-1", "coll-2")
+				}
+			}
+		}
+		return missingDataInfo
+	}
+	bothColls := []string{"coll-1", "coll-2"}
+	coll1Filter := ledger.NewPvtNsCollFilter()
+	coll1Filter.Add("ns-1", "coll-1")
+	coll2Filter := ledger.NewPvtNsCollFilter()
+	coll2Filter.Add("ns-1", "coll-2")
+	bothCollsFilter := ledger.NewPvtNsCollFilter()
+	bothCollsFilter.Add("ns-1", "coll-1")
+	bothCollsFilter.Add("ns-1", "coll-2")
+	otherCollFilter := ledger.NewPvtNsCollFilter()
+	otherCollFilter.Add("ns-2", "coll-1")
+
+	testCases := []struct {
+		name                 string
+		startBlock, endBlock uint64
+		maxBlocks            int
+		filter               ledger.PvtNsCollFilter
+		expected             ledger.MissingPvtDataInfo
+	}{
+		{"full range", 0, 4, 10, nil, expectedMissingDataInfo(bothColls, 1, 2, 3, 4)},
+		{"inner range", 2, 3, 10, nil, expectedMissingDataInfo(bothColls, 2, 3)},
+		{"end beyond last committed block", 3, 100, 10, nil, expectedMissingDataInfo(bothColls, 3, 4)},
+		{"max blocks", 1, 4, 3, nil, expectedMissingDataInfo(bothColls, 2, 3, 4)},
+		{"start beyond last committed block", 5, 10, 10, nil, nil},
+		{"zero max blocks", 1, 4, 0, nil, nil},
+		{"prioritized collection", 0, 4, 10, coll1Filter, expectedMissingDataInfo([]string{"coll-1"}, 1, 3, 4)},
+		{"deprioritized collection", 0, 4, 10, coll2Filter, expectedMissingDataInfo([]string{"coll-2"}, 1, 2, 3, 4)},
+		{"collection in range", 2, 3, 10, coll1Filter, expectedMissingDataInfo([]string{"coll-1"}, 3)},
+		{"collections with max blocks", 0, 4, 2, bothCollsFilter, expectedMissingDataInfo(bothColls, 3, 4)},
+		{"collection without missing data", 0, 4, 10, otherCollFilter, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			missingDataInfo, err := store.GetMissingPvtDataInfoForBlockRange(tc.startBlock, tc.endBlock, tc.maxBlocks, tc.filter)
+			require.NoError(t, err)
+			require.Len(t, missingDataInfo, len(tc.expected))
+			for blkNum, txsMissingData := range tc.expected {
+				for txNum, expectedMissingData := range txsMissingData {
+					require.ElementsMatch(t, expectedMissingData, missingDataInfo[blkNum][txNum])
+				}
+			}
+		})
+	}
+}
+
+func TestGetMissingPvtDataCounts(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+			{"ns-1", "coll-2"}: 0,
+			{"ns-2", "coll-1"}: 1,
+		},
+	)
+	env := NewTestStoreEnv(t, "TestGetMissingPvtDataCounts", btlPolicy, pvtDataConf())
+	defer env.Cleanup()
+	store := env.TestStore
+
+	counts, err := store.GetMissingPvtDataCounts()
+	require.NoError(t, err)
+	require.Equal(t, &ledger.MissingPvtDataCounts{}, counts)
+
+	blk1MissingData := make(ledger.TxMissingPvtDataMap)
+	blk1MissingData.Add(1, "ns-1", "coll-1", true)
+	blk1MissingData.Add(2, "ns-1", "coll-1", true)
+	blk1MissingData.Add(2, "ns-1", "coll-2", true)
+	blk1MissingData.Add(3, "ns-1", "coll-2", false)
+	blk1MissingData.Add(3, "ns-2", "coll-1", true)
+	require.NoError(t, store.Commit(0, nil, nil))
+	require.NoError(t, store.Commit(1, nil, blk1MissingData))
+
+	deprioritizedList := make(ledger.MissingPvtDataInfo)
+	deprioritizedList.Add(1, 2, "ns-1", "coll-2")
+	require.NoError(t, store.CommitPvtDataOfOldBlocks(nil, deprioritizedList))
+
+	counts, err = store.GetMissingPvtDataCounts()
+	require.NoError(t, err)
+	require.Equal(t, &ledger.MissingPvtDataCounts{Eligible: 4, Ineligible: 1}, counts)
+
+	// the reconciled data is no longer counted
+	require.NoError(t, store.CommitPvtDataOfOldBlocks(map[uint64][]*ledger.TxPvtData{
+		1: {produceSamplePvtdata(t, 2, []string{"ns-1:coll-2"})},
+	}, nil))
+	counts, err = store.GetMissingPvtDataCounts()
+	require.NoError(t, err)
+	require.Equal(t, &ledger.MissingPvtDataCounts{Eligible: 3, Ineligible: 1}, counts)
+
+	// the counts are recomputed when the store is reopened
+	env.CloseAndReopen()
+	store = env.TestStore
+	counts, err = store.GetMissingPvtDataCounts()
+	require.NoError(t, err)
+	require.Equal(t, &ledger.MissingPvtDataCounts{Eligible: 3, Ineligible: 1}, counts)
+
+	// the missing data of the collection ns-2/coll-1 in block 1 expires at block 3,
+	// and is no longer counted once purged at block 4
+	require.NoError(t, store.Commit(2, nil, nil))
+	require.NoError(t, store.Commit(3, nil, nil))
+	require.NoError(t, store.Commit(4, nil, nil))
+	require.Eventually(t, func() bool {
+		counts, err := store.GetMissingPvtDataCounts()
+		require.NoError(t, err)
+		return counts.Eligible == 2 && counts.Ineligible == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
 func TestExpiryDataNotIncluded(t *testing.T) {
 	ledgerid := "TestExpiryDataNotIncluded"
 	btlPolicy := btltestutil.SampleBTLPolicy(
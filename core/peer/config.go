@@ -26,6 +26,7 @@ import (
 	"net"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/osdi23p228/fabric/core/config"
@@ -34,6 +35,15 @@ import (
 	"github.com/spf13/viper"
 )
 
+const (
+	// DefaultGatewayEndorsementTimeout is the default duration the gateway waits
+	// for a response from other endorsing peers.
+	DefaultGatewayEndorsementTimeout = 30 * time.Second
+	// DefaultGatewayDialTimeout is the default duration the gateway waits for a
+	// connection to other endorsing peers and orderers.
+	DefaultGatewayDialTimeout = 2 * time.Minute
+)
+
 // ExternalBuilder represents the configuration structure of
 // a chaincode external builder
 type ExternalBuilder struct {
@@ -110,6 +120,44 @@ type Config struct {
 	// after overpopulation purge.
 	DiscoveryAuthCachePurgeRetentionRatio float64
 
+	// ----- Gateway -----
+
+	// The gateway service allows clients to evaluate, endorse, submit and track
+	// transactions through a single peer, which computes the endorsement plan and
+	// forwards the requests to the endorsing peers and the ordering service.
+
+	// GatewayEnabled is used to enable the gateway service.
+	GatewayEnabled bool
+	// GatewayEndorsementTimeout is the duration the gateway waits for a response
+	// from other endorsing peers.
+	GatewayEndorsementTimeout time.Duration
+	// GatewayDialTimeout is the duration the gateway waits for a connection to
+	// other endorsing peers and orderers to be established.
+	GatewayDialTimeout time.Duration
+
+	// ----- Query Result Cache -----
+	// The query result cache answers repeated read-only proposals with the
+	// results of an earlier simulation until a block writing to the namespaces
+	// they read from is committed.
+
+	// QueryResultCacheEnabled is used to enable the query result cache of the
+	// endorser.
+	QueryResultCacheEnabled bool
+	// QueryResultCacheMaxEntries is the maximum number of query results kept in
+	// the cache.
+	QueryResultCacheMaxEntries int
+
+	// ----- Transient Store -----
+	// The transient store holds the private write sets of endorsed transactions
+	// until they are committed or purged.
+
+	// TransientStoreEncryptionEnabled is used to encrypt the private write sets
+	// stored in the transient store with a key managed by the BCCSP.
+	TransientStoreEncryptionEnabled bool
+	// TransientStoreMaxSizePerChannel is the maximum size in bytes of the private
+	// write sets stored in the transient store for each channel. 0 means unlimited.
+	TransientStoreMaxSizePerChannel uint64
+
 	// ----- Limits -----
 	// Limits is used to configure some internal resource limits.
 	// TODO: create separate sub-struct for Limits config.
@@ -154,6 +202,26 @@ type Config struct {
 	// VMNetworkMode sets the networking mode for the container.
 	VMNetworkMode string
 
+	// ----- vm.kubernetes -----
+
+	// VMKubernetesEnabled runs chaincode as Kubernetes pods instead of Docker
+	// containers.
+	VMKubernetesEnabled bool
+	// VMKubernetesNamespace is the namespace of the chaincode jobs and pods.
+	// The namespace of the peer is used when empty.
+	VMKubernetesNamespace string
+	// VMKubernetesClaimName is the persistent volume claim shared by the peer
+	// and the chaincode jobs and pods.
+	VMKubernetesClaimName string
+	// VMKubernetesSharedPath is the path the shared claim is mounted at in the
+	// peer.
+	VMKubernetesSharedPath string
+	// VMKubernetesAttachStdout enables/disables the mirroring of the chaincode
+	// pod output to the peer log.
+	VMKubernetesAttachStdout bool
+	// VMKubernetesBuildTimeout bounds the duration of chaincode build jobs.
+	VMKubernetesBuildTimeout time.Duration
+
 	// ChaincodePull enables/disables force pulling of the base docker image.
 	ChaincodePull bool
 	// ExternalBuilders represents the builders and launchers for
@@ -249,6 +317,24 @@ func (c *Config) load() error {
 	c.DiscoveryAuthCacheEnabled = viper.GetBool("peer.discovery.authCacheEnabled")
 	c.DiscoveryAuthCacheMaxSize = viper.GetInt("peer.discovery.authCacheMaxSize")
 	c.DiscoveryAuthCachePurgeRetentionRatio = viper.GetFloat64("peer.discovery.authCachePurgeRetentionRatio")
+	c.GatewayEnabled = viper.GetBool("peer.gateway.enabled")
+	c.GatewayEndorsementTimeout = viper.GetDuration("peer.gateway.endorsementTimeout")
+	if c.GatewayEndorsementTimeout <= 0 {
+		c.GatewayEndorsementTimeout = DefaultGatewayEndorsementTimeout
+	}
+	c.GatewayDialTimeout = viper.GetDuration("peer.gateway.dialTimeout")
+	if c.GatewayDialTimeout <= 0 {
+		c.GatewayDialTimeout = DefaultGatewayDialTimeout
+	}
+	c.QueryResultCacheEnabled = viper.GetBool("peer.queryResultCache.enabled")
+	c.QueryResultCacheMaxEntries = viper.GetInt("peer.queryResultCache.maxEntries")
+	c.TransientStoreEncryptionEnabled = viper.GetBool("peer.transientStore.encryption.enabled")
+	c.TransientStoreMaxSizePerChannel = uint64(viper.GetSizeInBytes("peer.transientStore.maxSizePerChannel"))
+	// The PKCS11 BCCSP generates AES keys in software without a keystore to
+	// persist them to, so the transient store couldn't retrieve its keys.
+	if c.TransientStoreEncryptionEnabled && strings.EqualFold(viper.GetString("peer.BCCSP.Default"), "PKCS11") {
+		return errors.New("peer.transientStore.encryption.enabled isn't supported with the PKCS11 BCCSP, which can't persist the keys the transient store is encrypted with")
+	}
 	c.ChaincodeListenAddress = viper.GetString("peer.chaincodeListenAddress")
 	c.ChaincodeAddress = viper.GetString("peer.chaincodeAddress")
 
@@ -274,6 +360,13 @@ func (c *Config) load() error {
 		c.VMNetworkMode = "host"
 	}
 
+	c.VMKubernetesEnabled = viper.GetBool("vm.kubernetes.enabled")
+	c.VMKubernetesNamespace = viper.GetString("vm.kubernetes.namespace")
+	c.VMKubernetesClaimName = viper.GetString("vm.kubernetes.sharedVolume.claimName")
+	c.VMKubernetesSharedPath = config.GetPath("vm.kubernetes.sharedVolume.path")
+	c.VMKubernetesAttachStdout = viper.GetBool("vm.kubernetes.attachStdout")
+	c.VMKubernetesBuildTimeout = viper.GetDuration("vm.kubernetes.buildTimeout")
+
 	c.ChaincodePull = viper.GetBool("chaincode.pull")
 	var externalBuilders []ExternalBuilder
 	err = viper.UnmarshalKey("chaincode.externalBuilders", &externalBuilders)
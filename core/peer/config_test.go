@@ -234,6 +234,13 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("peer.discovery.authCacheEnabled", true)
 	viper.Set("peer.discovery.authCacheMaxSize", 1000)
 	viper.Set("peer.discovery.authCachePurgeRetentionRatio", 0.75)
+	viper.Set("peer.gateway.enabled", true)
+	viper.Set("peer.gateway.endorsementTimeout", "10s")
+	viper.Set("peer.gateway.dialTimeout", "1m")
+	viper.Set("peer.queryResultCache.enabled", true)
+	viper.Set("peer.queryResultCache.maxEntries", 500)
+	viper.Set("peer.transientStore.encryption.enabled", true)
+	viper.Set("peer.transientStore.maxSizePerChannel", "64MB")
 	viper.Set("peer.chaincodeListenAddress", "0.0.0.0:7052")
 	viper.Set("peer.chaincodeAddress", "0.0.0.0:7052")
 	viper.Set("peer.validatorPoolSize", 1)
@@ -245,6 +252,12 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("vm.docker.tls.cert.file", "test/vm/tls/cert/file")
 	viper.Set("vm.docker.tls.key.file", "test/vm/tls/key/file")
 	viper.Set("vm.docker.tls.ca.file", "test/vm/tls/ca/file")
+	viper.Set("vm.kubernetes.enabled", true)
+	viper.Set("vm.kubernetes.namespace", "fabric")
+	viper.Set("vm.kubernetes.sharedVolume.claimName", "chaincode-claim")
+	viper.Set("vm.kubernetes.sharedVolume.path", "/var/hyperledger/chaincode")
+	viper.Set("vm.kubernetes.attachStdout", true)
+	viper.Set("vm.kubernetes.buildTimeout", "5m")
 
 	viper.Set("operations.listenAddress", "127.0.0.1:9443")
 	viper.Set("operations.tls.enabled", false)
@@ -291,6 +304,13 @@ func TestGlobalConfig(t *testing.T) {
 		DiscoveryAuthCacheEnabled:             true,
 		DiscoveryAuthCacheMaxSize:             1000,
 		DiscoveryAuthCachePurgeRetentionRatio: 0.75,
+		GatewayEnabled:                        true,
+		GatewayEndorsementTimeout:             10 * time.Second,
+		GatewayDialTimeout:                    time.Minute,
+		QueryResultCacheEnabled:               true,
+		QueryResultCacheMaxEntries:            500,
+		TransientStoreEncryptionEnabled:       true,
+		TransientStoreMaxSizePerChannel:       64 * 1024 * 1024,
 		ChaincodeListenAddress:                "0.0.0.0:7052",
 		ChaincodeAddress:                      "0.0.0.0:7052",
 		ValidatorPoolSize:                     1,
@@ -301,6 +321,13 @@ func TestGlobalConfig(t *testing.T) {
 		VMDockerAttachStdout: false,
 		VMNetworkMode:        "TestingHost",
 
+		VMKubernetesEnabled:      true,
+		VMKubernetesNamespace:    "fabric",
+		VMKubernetesClaimName:    "chaincode-claim",
+		VMKubernetesSharedPath:   "/var/hyperledger/chaincode",
+		VMKubernetesAttachStdout: true,
+		VMKubernetesBuildTimeout: 5 * time.Minute,
+
 		ChaincodePull: false,
 		ExternalBuilders: []ExternalBuilder{
 			{
@@ -349,6 +376,8 @@ func TestGlobalConfigDefault(t *testing.T) {
 		ValidatorPoolSize:             runtime.NumCPU(),
 		VMNetworkMode:                 "host",
 		DeliverClientKeepaliveOptions: comm.DefaultKeepaliveOptions,
+		GatewayEndorsementTimeout:     DefaultGatewayEndorsementTimeout,
+		GatewayDialTimeout:            DefaultGatewayDialTimeout,
 	}
 
 	assert.Equal(t, expectedConfig, coreConfig)
@@ -384,6 +413,8 @@ func TestPropagateEnvironment(t *testing.T) {
 		ValidatorPoolSize:             runtime.NumCPU(),
 		VMNetworkMode:                 "host",
 		DeliverClientKeepaliveOptions: comm.DefaultKeepaliveOptions,
+		GatewayEndorsementTimeout:     DefaultGatewayEndorsementTimeout,
+		GatewayDialTimeout:            DefaultGatewayDialTimeout,
 		ExternalBuilders: []ExternalBuilder{
 			{
 				Name:                 "testName",
@@ -419,6 +450,19 @@ func TestMissingExternalBuilderPath(t *testing.T) {
 	assert.EqualError(t, err, "invalid external builder configuration, path attribute missing in one or more builders")
 }
 
+func TestTransientStoreEncryptionWithPKCS11(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+	viper.Set("peer.transientStore.encryption.enabled", true)
+	viper.Set("peer.BCCSP.Default", "PKCS11")
+	_, err := GlobalConfig()
+	assert.EqualError(t, err, "peer.transientStore.encryption.enabled isn't supported with the PKCS11 BCCSP, which can't persist the keys the transient store is encrypted with")
+
+	viper.Set("peer.BCCSP.Default", "SW")
+	_, err = GlobalConfig()
+	assert.NoError(t, err)
+}
+
 func TestMissingExternalBuilderName(t *testing.T) {
 	defer viper.Reset()
 	viper.Set("peer.address", "localhost:8080")
@@ -181,6 +181,9 @@ type Peer struct {
 	LedgerMgr                *ledgermgmt.LedgerMgr
 	OrdererEndpointOverrides map[string]*orderers.Endpoint
 	CryptoProvider           bccsp.BCCSP
+	// CommitNotifier, if set, is notified of the transactions of the blocks
+	// committed into the ledgers of all channels.
+	CommitNotifier *committer.CommitNotifier
 
 	// validationWorkersSemaphore is used to limit the number of concurrent validation
 	// go routines.
@@ -324,7 +327,7 @@ func (p *Peer) createChannel(
 		channel.bundleUpdate,
 	)
 
-	committer := committer.NewLedgerCommitter(l)
+	committer := committer.NewNotifyingLedgerCommitter(l, cid, p.CommitNotifier)
 	validator := &txvalidator.ValidationRouter{
 		CapabilityProvider: channel,
 		V14Validator: validatorv14.NewTxValidator(
@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transientstore
+
+import "github.com/osdi23p228/fabric/common/metrics"
+
+var (
+	sizeOpts = metrics.GaugeOpts{
+		Namespace:    "transientstore",
+		Name:         "size_bytes",
+		Help:         "The size in bytes of the private write sets stored in the transient store.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	entriesOpts = metrics.GaugeOpts{
+		Namespace:    "transientstore",
+		Name:         "entries",
+		Help:         "The number of private write sets stored in the transient store.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	evictedEntriesOpts = metrics.CounterOpts{
+		Namespace:    "transientstore",
+		Name:         "evicted_entries",
+		Help:         "The number of private write sets evicted from the transient store because it exceeded its maximum size.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+)
+
+type stats struct {
+	size           metrics.Gauge
+	entries        metrics.Gauge
+	evictedEntries metrics.Counter
+}
+
+func newStats(metricsProvider metrics.Provider) *stats {
+	return &stats{
+		size:           metricsProvider.NewGauge(sizeOpts),
+		entries:        metricsProvider.NewGauge(entriesOpts),
+		evictedEntries: metricsProvider.NewCounter(evictedEntriesOpts),
+	}
+}
@@ -7,15 +7,22 @@ SPDX-License-Identifier: Apache-2.0
 package transientstore
 
 import (
-	"errors"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric-protos-go/transientstore"
+	"github.com/osdi23p228/fabric/bccsp"
 	"github.com/osdi23p228/fabric/common/flogging"
 	"github.com/osdi23p228/fabric/common/ledger/util/leveldbhelper"
+	"github.com/osdi23p228/fabric/common/metrics"
+	"github.com/osdi23p228/fabric/common/metrics/disabled"
 	"github.com/osdi23p228/fabric/common/util"
 	"github.com/osdi23p228/fabric/core/ledger"
+	"github.com/osdi23p228/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerutil "github.com/osdi23p228/fabric/core/ledger/util"
+	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 )
 
@@ -24,6 +31,9 @@ var logger = flogging.MustGetLogger("transientstore")
 var (
 	emptyValue = []byte{}
 	nilByte    = byte('\x00')
+	// encryptedByte is prepended to encrypted private write sets. A marshaled
+	// message can never start with it, as 1 isn't a valid field tag.
+	encryptedByte = byte('\x01')
 	// ErrStoreEmpty is used to indicate that there are no entries in transient store
 	ErrStoreEmpty = errors.New("Transient store is empty")
 )
@@ -58,17 +68,48 @@ type EndorserPvtSimulationResults struct {
 // Implementation
 /////////////////////////////////////////////
 
+// Config is the configuration of a TransientStoreProvider
+type Config struct {
+	// Path is the directory the transient store is stored in
+	Path string
+	// EncryptionEnabled enables the encryption at rest of the private write sets
+	EncryptionEnabled bool
+	// CryptoProvider generates and retrieves the per channel keys the private
+	// write sets are encrypted with. It is required if the private write sets
+	// are, or were previously, encrypted.
+	CryptoProvider bccsp.BCCSP
+	// MaxSizePerChannel is the maximum size in bytes of the private write sets
+	// stored for a channel. Once it is exceeded, the private write sets received
+	// at the lowest block heights are evicted. 0 means unlimited.
+	MaxSizePerChannel uint64
+	// MetricsProvider provides the metrics of the transient store
+	MetricsProvider metrics.Provider
+}
+
 // storeProvider encapsulates a leveldb provider which is used to store
 // private write sets of simulated transactions, and implements TransientStoreProvider
 // interface.
 type storeProvider struct {
 	dbProvider *leveldbhelper.Provider
+	config     Config
+	stats      *stats
 }
 
 // store holds an instance of a levelDB.
 type Store struct {
 	db       *leveldbhelper.DBHandle
 	ledgerID string
+
+	// key encrypts the private write sets, or is nil if they are stored in plaintext
+	key     bccsp.Key
+	csp     bccsp.BCCSP
+	maxSize uint64
+	stats   *stats
+
+	// lock serializes the updates of the store, so that its size is kept consistent
+	lock    sync.Mutex
+	size    uint64
+	entries uint64
 }
 
 // RwsetScanner helps iterating over results
@@ -76,21 +117,191 @@ type RwsetScanner struct {
 	txid   string
 	dbItr  iterator.Iterator
 	filter ledger.PvtNsCollFilter
+	store  *Store
 }
 
 // NewStoreProvider instantiates TransientStoreProvider
 func NewStoreProvider(path string) (StoreProvider, error) {
-	dbProvider, err := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: path})
+	return NewStoreProviderWithConfig(Config{Path: path})
+}
+
+// NewStoreProviderWithConfig instantiates TransientStoreProvider with the given configuration
+func NewStoreProviderWithConfig(config Config) (StoreProvider, error) {
+	if config.EncryptionEnabled && config.CryptoProvider == nil {
+		return nil, errors.New("a crypto provider is required to encrypt the transient store")
+	}
+	if config.MetricsProvider == nil {
+		config.MetricsProvider = &disabled.Provider{}
+	}
+	dbProvider, err := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: config.Path})
 	if err != nil {
 		return nil, err
 	}
-	return &storeProvider{dbProvider: dbProvider}, nil
+	return &storeProvider{
+		dbProvider: dbProvider,
+		config:     config,
+		stats:      newStats(config.MetricsProvider),
+	}, nil
 }
 
 // OpenStore returns a handle to a ledgerId in Store
 func (provider *storeProvider) OpenStore(ledgerID string) (*Store, error) {
 	dbHandle := provider.dbProvider.GetDBHandle(ledgerID)
-	return &Store{db: dbHandle, ledgerID: ledgerID}, nil
+	s := &Store{
+		db:       dbHandle,
+		ledgerID: ledgerID,
+		csp:      provider.config.CryptoProvider,
+		maxSize:  provider.config.MaxSizePerChannel,
+		stats:    provider.stats,
+	}
+	if err := s.loadKey(provider.config.EncryptionEnabled); err != nil {
+		return nil, err
+	}
+	if err := s.loadSize(); err != nil {
+		return nil, errors.WithMessagef(err, "failed computing the size of the transient store of channel %s", ledgerID)
+	}
+	return s, nil
+}
+
+// loadKey retrieves the key the private write sets of the channel are encrypted with.
+// If encryption is enabled and the channel doesn't have a key yet, a key is generated.
+func (s *Store) loadKey(encryptionEnabled bool) error {
+	ski, err := s.db.Get(encryptionKeySKIKey)
+	if err != nil {
+		return err
+	}
+	if ski != nil {
+		if s.csp == nil {
+			return errors.Errorf("the transient store of channel %s is encrypted, but no crypto provider is configured", s.ledgerID)
+		}
+		key, err := s.csp.GetKey(ski)
+		if err != nil {
+			return errors.WithMessagef(err, "failed retrieving the encryption key of the transient store of channel %s", s.ledgerID)
+		}
+		if encryptionEnabled {
+			s.key = key
+		}
+		return nil
+	}
+	if !encryptionEnabled {
+		return nil
+	}
+
+	key, err := s.csp.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: false})
+	if err != nil {
+		return errors.WithMessagef(err, "failed generating the encryption key of the transient store of channel %s", s.ledgerID)
+	}
+	if err := s.db.Put(encryptionKeySKIKey, key.SKI(), true); err != nil {
+		return err
+	}
+	s.key = key
+	return nil
+}
+
+// loadSize computes the size of the private write sets stored for the channel
+func (s *Store) loadSize() error {
+	iter, err := s.db.GetIterator(createPurgeIndexByHeightRangeStartKey(0), []byte{purgeIndexByHeightPrefix + 1})
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+	for iter.Next() {
+		txid, uuid, blockHeight, err := splitCompositeKeyOfPurgeIndexByHeight(iter.Key())
+		if err != nil {
+			return err
+		}
+		size, err := s.entrySize(iter.Value(), createCompositeKeyForPvtRWSet(txid, uuid, blockHeight))
+		if err != nil {
+			return err
+		}
+		s.size += size
+		s.entries++
+	}
+	s.reportSize()
+	return nil
+}
+
+// entrySize returns the size of a private write set, as recorded in the value of
+// its purge indexes. Entries persisted before the size was recorded are read.
+func (s *Store) entrySize(purgeIndexValue []byte, compositeKeyPvtRWSet []byte) (uint64, error) {
+	if len(purgeIndexValue) != 0 {
+		size, n := proto.DecodeVarint(purgeIndexValue)
+		if n == 0 {
+			return 0, errors.New("invalid purge index value")
+		}
+		return size, nil
+	}
+	value, err := s.db.Get(compositeKeyPvtRWSet)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(len(value)), nil
+}
+
+// released updates the size of the store after entries of the given total size were removed
+func (s *Store) released(entries, size uint64) {
+	if size > s.size {
+		size = s.size
+	}
+	if entries > s.entries {
+		entries = s.entries
+	}
+	s.size -= size
+	s.entries -= entries
+	s.reportSize()
+}
+
+func (s *Store) reportSize() {
+	if s.stats == nil {
+		return
+	}
+	s.stats.size.With("channel", s.ledgerID).Set(float64(s.size))
+	s.stats.entries.With("channel", s.ledgerID).Set(float64(s.entries))
+}
+
+// encodeValue encodes a private write set to be stored, encrypting it if encryption is enabled
+func (s *Store) encodeValue(privateSimulationResultsWithConfigBytes []byte) ([]byte, error) {
+	value := append([]byte{nilByte}, privateSimulationResultsWithConfigBytes...)
+	if s.key == nil {
+		return value, nil
+	}
+	ciphertext, err := s.csp.Encrypt(s.key, value, &bccsp.AESCBCPKCS7ModeOpts{})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed encrypting private write set")
+	}
+	return append([]byte{encryptedByte}, ciphertext...), nil
+}
+
+// decodeValue returns a stored private write set, decrypting it if it is encrypted
+func (s *Store) decodeValue(dbVal []byte) ([]byte, error) {
+	if len(dbVal) == 0 || dbVal[0] != encryptedByte {
+		return dbVal, nil
+	}
+	key, err := s.decryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	value, err := s.csp.Decrypt(key, dbVal[1:], &bccsp.AESCBCPKCS7ModeOpts{})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed decrypting private write set")
+	}
+	return value, nil
+}
+
+// decryptionKey returns the key the private write sets of the channel were encrypted with,
+// even if encryption was disabled since
+func (s *Store) decryptionKey() (bccsp.Key, error) {
+	if s.key != nil {
+		return s.key, nil
+	}
+	ski, err := s.db.Get(encryptionKeySKIKey)
+	if err != nil {
+		return nil, err
+	}
+	if ski == nil || s.csp == nil {
+		return nil, errors.Errorf("private write set is encrypted, but the encryption key of the transient store of channel %s is unavailable", s.ledgerID)
+	}
+	return s.csp.GetKey(ski)
 }
 
 // Close closes the TransientStoreProvider
@@ -105,6 +316,9 @@ func (s *Store) Persist(txid string, blockHeight uint64,
 
 	logger.Debugf("Persisting private data to transient store for txid [%s] at block height [%d]", txid, blockHeight)
 
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
 	dbBatch := s.db.NewUpdateBatch()
 
 	// Create compositeKey with appropriate prefix, txid, uuid and blockHeight
@@ -122,9 +336,21 @@ func (s *Store) Persist(txid string, blockHeight uint64,
 	// retrieving, a nil byte is prepended to the new proto, i.e., privateSimulationResultsWithConfigBytes,
 	// as a marshaled message can never start with a nil byte. In v1.3, we can avoid prepending the
 	// nil byte.
-	value := append([]byte{nilByte}, privateSimulationResultsWithConfigBytes...)
+	// If encryption is enabled, the value is encrypted and prepended with encryptedByte instead.
+	value, err := s.encodeValue(privateSimulationResultsWithConfigBytes)
+	if err != nil {
+		return err
+	}
+	size := uint64(len(value))
+	if s.maxSize > 0 && size > s.maxSize {
+		return errors.Errorf("private data of txid [%s] is %d bytes long, which exceeds the maximum size of the transient store of %d bytes", txid, size, s.maxSize)
+	}
 	dbBatch.Put(compositeKeyPvtRWSet, value)
 
+	// The size of the private write set is recorded in the value of its purge indexes,
+	// so that purging it doesn't require reading it
+	sizeValue := proto.EncodeVarint(size)
+
 	// Create two index: (i) by txid, and (ii) by height
 
 	// Create compositeKey for purge index by height with appropriate prefix, blockHeight,
@@ -133,7 +359,7 @@ func (s *Store) Persist(txid string, blockHeight uint64,
 	// by PurgeTxids()) using BTL policy by PurgeBelowHeight(). Note that orphan entries are due to transaction
 	// that gets endorsed but not submitted by the client for commit)
 	compositeKeyPurgeIndexByHeight := createCompositeKeyForPurgeIndexByHeight(blockHeight, txid, uuid)
-	dbBatch.Put(compositeKeyPurgeIndexByHeight, emptyValue)
+	dbBatch.Put(compositeKeyPurgeIndexByHeight, sizeValue)
 
 	// Create compositeKey for purge index by txid with appropriate prefix, txid, uuid,
 	// blockHeight and store the compositeKey (purge index) with a nil byte as value.
@@ -146,9 +372,57 @@ func (s *Store) Persist(txid string, blockHeight uint64,
 	// with purgeIndexByTxidPrefix. For code readability and to be expressive, we use a
 	// createCompositeKeyForPurgeIndexByTxid() instead.
 	compositeKeyPurgeIndexByTxid := createCompositeKeyForPurgeIndexByTxid(txid, uuid, blockHeight)
-	dbBatch.Put(compositeKeyPurgeIndexByTxid, emptyValue)
+	dbBatch.Put(compositeKeyPurgeIndexByTxid, sizeValue)
+
+	var evictedEntries, evictedSize uint64
+	if s.maxSize > 0 && s.size+size > s.maxSize {
+		evictedEntries, evictedSize, err = s.evict(dbBatch, s.size+size-s.maxSize)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.db.WriteBatch(dbBatch, true); err != nil {
+		return err
+	}
+	s.size += size
+	s.entries++
+	s.released(evictedEntries, evictedSize)
+	if evictedEntries > 0 {
+		logger.Warningf("Evicted %d private write sets (%d bytes) from the transient store of channel %s, which exceeded its maximum size of %d bytes",
+			evictedEntries, evictedSize, s.ledgerID, s.maxSize)
+		s.stats.evictedEntries.With("channel", s.ledgerID).Add(float64(evictedEntries))
+	}
+	return nil
+}
 
-	return s.db.WriteBatch(dbBatch, true)
+// evict adds to the batch the removal of the private write sets received at the
+// lowest block heights, until at least the given size is freed
+func (s *Store) evict(dbBatch *leveldbhelper.UpdateBatch, sizeToFree uint64) (entries uint64, size uint64, err error) {
+	iter, err := s.db.GetIterator(createPurgeIndexByHeightRangeStartKey(0), []byte{purgeIndexByHeightPrefix + 1})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer iter.Release()
+	for size < sizeToFree && iter.Next() {
+		compositeKeyPurgeIndexByHeight := iter.Key()
+		txid, uuid, blockHeight, err := splitCompositeKeyOfPurgeIndexByHeight(compositeKeyPurgeIndexByHeight)
+		if err != nil {
+			return 0, 0, err
+		}
+		compositeKeyPvtRWSet := createCompositeKeyForPvtRWSet(txid, uuid, blockHeight)
+		entrySize, err := s.entrySize(iter.Value(), compositeKeyPvtRWSet)
+		if err != nil {
+			return 0, 0, err
+		}
+		logger.Debugf("Evicting from transient store private data simulated at block [%d]: txid [%s] uuid [%s]", blockHeight, txid, uuid)
+		dbBatch.Delete(compositeKeyPvtRWSet)
+		dbBatch.Delete(createCompositeKeyForPurgeIndexByTxid(txid, uuid, blockHeight))
+		dbBatch.Delete(compositeKeyPurgeIndexByHeight)
+		entries++
+		size += entrySize
+	}
+	return entries, size, nil
 }
 
 // GetTxPvtRWSetByTxid returns an iterator due to the fact that the txid may have multiple private
@@ -165,7 +439,7 @@ func (s *Store) GetTxPvtRWSetByTxid(txid string, filter ledger.PvtNsCollFilter)
 	if err != nil {
 		return nil, err
 	}
-	return &RwsetScanner{txid, iter, filter}, nil
+	return &RwsetScanner{txid, iter, filter, s}, nil
 }
 
 // PurgeByTxids removes private write sets of a given set of transactions from the
@@ -175,6 +449,10 @@ func (s *Store) PurgeByTxids(txids []string) error {
 
 	logger.Debug("Purging private data from transient store for committed txids")
 
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var purgedEntries, purgedSize uint64
 	dbBatch := s.db.NewUpdateBatch()
 
 	for _, txid := range txids {
@@ -201,6 +479,11 @@ func (s *Store) PurgeByTxids(txids []string) error {
 				return err
 			}
 			compositeKeyPvtRWSet := createCompositeKeyForPvtRWSet(txid, uuid, blockHeight)
+			size, err := s.entrySize(iter.Value(), compositeKeyPvtRWSet)
+			if err != nil {
+				iter.Release()
+				return err
+			}
 			dbBatch.Delete(compositeKeyPvtRWSet)
 
 			// Remove purge index -- purgeIndexByHeight
@@ -209,12 +492,19 @@ func (s *Store) PurgeByTxids(txids []string) error {
 
 			// Remove purge index -- purgeIndexByTxid
 			dbBatch.Delete(compositeKeyPurgeIndexByTxid)
+
+			purgedEntries++
+			purgedSize += size
 		}
 		iter.Release()
 	}
 	// If peer fails before/while writing the batch to golevelDB, these entries will be
 	// removed as per BTL policy later by PurgeBelowHeight()
-	return s.db.WriteBatch(dbBatch, true)
+	if err := s.db.WriteBatch(dbBatch, true); err != nil {
+		return err
+	}
+	s.released(purgedEntries, purgedSize)
+	return nil
 }
 
 // PurgeBelowHeight removes private write sets at block height lesser than
@@ -227,6 +517,9 @@ func (s *Store) PurgeBelowHeight(maxBlockNumToRetain uint64) error {
 
 	logger.Debugf("Purging orphaned private data from transient store received prior to block [%d]", maxBlockNumToRetain)
 
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
 	// Do a range query with 0 as startKey and maxBlockNumToRetain-1 as endKey
 	startKey := createPurgeIndexByHeightRangeStartKey(0)
 	endKey := createPurgeIndexByHeightRangeEndKey(maxBlockNumToRetain - 1)
@@ -235,6 +528,7 @@ func (s *Store) PurgeBelowHeight(maxBlockNumToRetain uint64) error {
 		return err
 	}
 
+	var purgedEntries, purgedSize uint64
 	dbBatch := s.db.NewUpdateBatch()
 
 	// Get all txid and uuid from above result and remove it from transient store (both
@@ -251,6 +545,11 @@ func (s *Store) PurgeBelowHeight(maxBlockNumToRetain uint64) error {
 		logger.Debugf("Purging from transient store private data simulated at block [%d]: txid [%s] uuid [%s]", blockHeight, txid, uuid)
 
 		compositeKeyPvtRWSet := createCompositeKeyForPvtRWSet(txid, uuid, blockHeight)
+		size, err := s.entrySize(iter.Value(), compositeKeyPvtRWSet)
+		if err != nil {
+			iter.Release()
+			return err
+		}
 		dbBatch.Delete(compositeKeyPvtRWSet)
 
 		// Remove purge index -- purgeIndexByTxid
@@ -259,10 +558,147 @@ func (s *Store) PurgeBelowHeight(maxBlockNumToRetain uint64) error {
 
 		// Remove purge index -- purgeIndexByHeight
 		dbBatch.Delete(compositeKeyPurgeIndexByHeight)
+
+		purgedEntries++
+		purgedSize += size
 	}
 	iter.Release()
 
-	return s.db.WriteBatch(dbBatch, true)
+	if err := s.db.WriteBatch(dbBatch, true); err != nil {
+		return err
+	}
+	s.released(purgedEntries, purgedSize)
+	return nil
+}
+
+// PurgeByKeys removes the private write sets received at block height of maxBlockNumToPurge
+// or lower, i.e., which were simulated before the purge got committed, which hold writes of
+// the purged keys and whose transaction is already in the ledger, valid or invalid, as told
+// by txCommitted. The private write sets of pending transactions are left as is, as the
+// writes of a transaction ordered after the purge are not affected by it. PurgeByKeys() is
+// expected to be called by coordinator after committing a block which purges private data.
+func (s *Store) PurgeByKeys(purgedKeys []*rwsetutil.PurgedKey, maxBlockNumToPurge uint64, txCommitted func(txid string) (bool, error)) error {
+	if len(purgedKeys) == 0 {
+		return nil
+	}
+
+	logger.Debugf("Purging private data of %d keys from transient store received up to block [%d]", len(purgedKeys), maxBlockNumToPurge)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	keyHashes := map[string]map[string]bool{}
+	for _, purgedKey := range purgedKeys {
+		nsColl := purgedKey.Namespace + string(compositeKeySep) + purgedKey.Collection
+		if keyHashes[nsColl] == nil {
+			keyHashes[nsColl] = map[string]bool{}
+		}
+		keyHashes[nsColl][string(purgedKey.KeyHash)] = true
+	}
+
+	startKey := createPurgeIndexByHeightRangeStartKey(0)
+	endKey := createPurgeIndexByHeightRangeEndKey(maxBlockNumToPurge)
+	iter, err := s.db.GetIterator(startKey, endKey)
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+
+	var purgedEntries, purgedSize uint64
+	committed := map[string]bool{}
+	dbBatch := s.db.NewUpdateBatch()
+	for iter.Next() {
+		compositeKeyPurgeIndexByHeight := iter.Key()
+		txid, uuid, blockHeight, err := splitCompositeKeyOfPurgeIndexByHeight(compositeKeyPurgeIndexByHeight)
+		if err != nil {
+			return err
+		}
+
+		compositeKeyPvtRWSet := createCompositeKeyForPvtRWSet(txid, uuid, blockHeight)
+		encodedVal, err := s.db.Get(compositeKeyPvtRWSet)
+		if err != nil {
+			return err
+		}
+		if encodedVal == nil {
+			continue
+		}
+		dbVal, err := s.decodeValue(encodedVal)
+		if err != nil {
+			return err
+		}
+		// entries of the old proto are left to be removed by PurgeBelowHeight()
+		if dbVal[0] != nilByte {
+			continue
+		}
+		txPvtRWSetWithConfig := &transientstore.TxPvtReadWriteSetWithConfigInfo{}
+		if err := proto.Unmarshal(dbVal[1:], txPvtRWSetWithConfig); err != nil {
+			return err
+		}
+		writes, err := writesPurgedKeys(txPvtRWSetWithConfig.GetPvtRwset(), keyHashes)
+		if err != nil {
+			return err
+		}
+		if !writes {
+			continue
+		}
+
+		txDone, ok := committed[txid]
+		if !ok {
+			if txDone, err = txCommitted(txid); err != nil {
+				return errors.WithMessagef(err, "failed to look up transaction [%s]", txid)
+			}
+			committed[txid] = txDone
+		}
+		if !txDone {
+			continue
+		}
+		logger.Debugf("Purging from transient store private data of purged keys received at block [%d]: txid [%s] uuid [%s]", blockHeight, txid, uuid)
+
+		size, err := s.entrySize(iter.Value(), compositeKeyPvtRWSet)
+		if err != nil {
+			return err
+		}
+		dbBatch.Delete(compositeKeyPvtRWSet)
+		dbBatch.Delete(createCompositeKeyForPurgeIndexByTxid(txid, uuid, blockHeight))
+		dbBatch.Delete(compositeKeyPurgeIndexByHeight)
+
+		purgedEntries++
+		purgedSize += size
+	}
+
+	if err := s.db.WriteBatch(dbBatch, true); err != nil {
+		return err
+	}
+	s.released(purgedEntries, purgedSize)
+	return nil
+}
+
+// writesPurgedKeys returns true if the private write set writes one of the keys whose
+// hash is in the keyHashes of its namespace and collection
+func writesPurgedKeys(txPvtRWSet *rwset.TxPvtReadWriteSet, keyHashes map[string]map[string]bool) (bool, error) {
+	for _, ns := range txPvtRWSet.GetNsPvtRwset() {
+		for _, coll := range ns.CollectionPvtRwset {
+			collKeyHashes := keyHashes[ns.Namespace+string(compositeKeySep)+coll.CollectionName]
+			if len(collKeyHashes) == 0 {
+				continue
+			}
+			kvRWSet := &kvrwset.KVRWSet{}
+			if err := proto.Unmarshal(coll.Rwset, kvRWSet); err != nil {
+				return false, err
+			}
+			for _, write := range kvRWSet.Writes {
+				if collKeyHashes[string(ledgerutil.ComputeStringHash(write.Key))] {
+					return true, nil
+				}
+			}
+			for _, metadataWrite := range kvRWSet.MetadataWrites {
+				if collKeyHashes[string(ledgerutil.ComputeStringHash(metadataWrite.Key))] {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
 }
 
 // GetMinTransientBlkHt returns the lowest block height remaining in transient store
@@ -272,7 +708,7 @@ func (s *Store) GetMinTransientBlkHt() (uint64, error) {
 	// the lowest block height remaining in transient store. An alternative approach
 	// is to explicitly store the minBlockHeight in the transientStore.
 	startKey := createPurgeIndexByHeightRangeStartKey(0)
-	iter, err := s.db.GetIterator(startKey, nil)
+	iter, err := s.db.GetIterator(startKey, []byte{purgeIndexByHeightPrefix + 1})
 	if err != nil {
 		return 0, err
 	}
@@ -300,11 +736,14 @@ func (scanner *RwsetScanner) Next() (*EndorserPvtSimulationResults, error) {
 		return nil, nil
 	}
 	dbKey := scanner.dbItr.Key()
-	dbVal := scanner.dbItr.Value()
 	_, blockHeight, err := splitCompositeKeyOfPvtRWSet(dbKey)
 	if err != nil {
 		return nil, err
 	}
+	dbVal, err := scanner.store.decodeValue(scanner.dbItr.Value())
+	if err != nil {
+		return nil, err
+	}
 
 	txPvtRWSet := &rwset.TxPvtReadWriteSet{}
 	txPvtRWSetWithConfig := &transientstore.TxPvtReadWriteSetWithConfigInfo{}
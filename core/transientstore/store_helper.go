@@ -20,7 +20,11 @@ var (
 	prwsetPrefix             = []byte("P")[0] // key prefix for storing private write set in transient store.
 	purgeIndexByHeightPrefix = []byte("H")[0] // key prefix for storing index on private write set using received at block height.
 	purgeIndexByTxidPrefix   = []byte("T")[0] // key prefix for storing index on private write set using txid
+	metadataPrefix           = []byte("M")[0] // key prefix for storing metadata of the transient store
 	compositeKeySep          = byte(0x00)
+
+	// encryptionKeySKIKey is the key the SKI of the key encrypting the private write sets is stored at
+	encryptionKeySKIKey = []byte{metadataPrefix, compositeKeySep, 'K'}
 )
 
 // createCompositeKeyForPvtRWSet creates a key for storing private write set
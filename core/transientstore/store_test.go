@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"sort"
 	"testing"
 
@@ -18,9 +19,13 @@ import (
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-protos-go/transientstore"
+	"github.com/osdi23p228/fabric/bccsp/sw"
+	"github.com/osdi23p228/fabric/common/metrics"
+	"github.com/osdi23p228/fabric/common/metrics/metricsfakes"
 	"github.com/osdi23p228/fabric/common/policydsl"
 	commonutil "github.com/osdi23p228/fabric/common/util"
 	"github.com/osdi23p228/fabric/core/ledger"
+	"github.com/osdi23p228/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/osdi23p228/fabric/core/ledger/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -498,6 +503,244 @@ func TestTransientStorePurgeBelowHeight(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestTransientStorePurgeByKeys(t *testing.T) {
+	env.initTestEnv(t)
+	defer env.cleanup()
+	testStore := env.store
+
+	pvtRWSetWithConfig := func() *transientstore.TxPvtReadWriteSetWithConfigInfo {
+		builder := rwsetutil.NewRWSetBuilder()
+		builder.AddToPvtAndHashedWriteSet("ns-1", "coll-1", "key-1", []byte("value-1"))
+		builder.AddToPvtAndHashedWriteSet("ns-1", "coll-1", "key-2", []byte("value-2"))
+		builder.AddToPvtAndHashedWriteSet("ns-1", "coll-2", "key-1", []byte("value-1"))
+		simRes, err := builder.GetTxSimulationResults()
+		require.NoError(t, err)
+		return &transientstore.TxPvtReadWriteSetWithConfigInfo{PvtRwset: simRes.PvtSimulationResults}
+	}
+	otherPvtRWSetWithConfig := func() *transientstore.TxPvtReadWriteSetWithConfigInfo {
+		builder := rwsetutil.NewRWSetBuilder()
+		builder.AddToPvtAndHashedWriteSet("ns-1", "coll-1", "key-2", []byte("value-2"))
+		simRes, err := builder.GetTxSimulationResults()
+		require.NoError(t, err)
+		return &transientstore.TxPvtReadWriteSetWithConfigInfo{PvtRwset: simRes.PvtSimulationResults}
+	}
+	require.NoError(t, testStore.Persist("committed-tx", 10, pvtRWSetWithConfig()))
+	require.NoError(t, testStore.Persist("committed-tx", 11, pvtRWSetWithConfig()))
+	require.NoError(t, testStore.Persist("invalidated-tx", 10, pvtRWSetWithConfig()))
+	require.NoError(t, testStore.Persist("pending-tx", 10, pvtRWSetWithConfig()))
+	require.NoError(t, testStore.Persist("other-keys-tx", 10, otherPvtRWSetWithConfig()))
+	require.NoError(t, testStore.Persist("later-tx", 12, pvtRWSetWithConfig()))
+
+	purgedKeys := []*rwsetutil.PurgedKey{
+		{Namespace: "ns-1", Collection: "coll-1", KeyHash: util.ComputeStringHash("key-1")},
+	}
+	var lookedUp []string
+	txCommitted := func(txid string) (bool, error) {
+		lookedUp = append(lookedUp, txid)
+		return txid == "committed-tx" || txid == "invalidated-tx" || txid == "later-tx", nil
+	}
+	require.NoError(t, testStore.PurgeByKeys(purgedKeys, 11, txCommitted))
+
+	entries := func(txid string) int {
+		iter, err := testStore.GetTxPvtRWSetByTxid(txid, nil)
+		require.NoError(t, err)
+		defer iter.Close()
+		n := 0
+		for {
+			res, err := iter.Next()
+			require.NoError(t, err)
+			if res == nil {
+				return n
+			}
+			n++
+		}
+	}
+
+	// only the write sets of transactions already in the ledger are removed, and
+	// the ones received after the purge are left as is
+	require.Equal(t, 0, entries("committed-tx"))
+	require.Equal(t, 0, entries("invalidated-tx"))
+	require.Equal(t, 1, entries("pending-tx"))
+	require.Equal(t, 1, entries("other-keys-tx"))
+	require.Equal(t, 1, entries("later-tx"))
+	require.ElementsMatch(t, []string{"committed-tx", "invalidated-tx", "pending-tx"}, lookedUp)
+
+	// the write sets of pending transactions are not rewritten
+	iter, err := testStore.GetTxPvtRWSetByTxid("pending-tx", nil)
+	require.NoError(t, err)
+	res, err := iter.Next()
+	require.NoError(t, err)
+	iter.Close()
+	require.True(t, proto.Equal(pvtRWSetWithConfig(), res.PvtSimulationResultsWithConfig))
+
+	// removed entries are released from the indexes
+	require.NoError(t, testStore.PurgeByTxids([]string{"pending-tx", "other-keys-tx"}))
+	minHeight, err := testStore.GetMinTransientBlkHt()
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), minHeight)
+
+	err = testStore.PurgeByKeys(purgedKeys, 12, func(string) (bool, error) { return false, fmt.Errorf("ledger unavailable") })
+	require.EqualError(t, err, "failed to look up transaction [later-tx]: ledger unavailable")
+}
+
+func TestTransientStoreEncryption(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "ts")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempdir)
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevel(filepath.Join(tempdir, "keystore"))
+	require.NoError(t, err)
+	config := Config{
+		Path:              filepath.Join(tempdir, "transientstore"),
+		EncryptionEnabled: true,
+		CryptoProvider:    cryptoProvider,
+	}
+
+	samplePvtRWSetWithConfig := samplePvtDataWithConfigInfo(t)
+	requireRetrieved := func(store *Store, txid string) {
+		iter, err := store.GetTxPvtRWSetByTxid(txid, nil)
+		require.NoError(t, err)
+		defer iter.Close()
+		result, err := iter.Next()
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, uint64(10), result.ReceivedAtBlockHeight)
+		require.True(t, proto.Equal(samplePvtRWSetWithConfig, result.PvtSimulationResultsWithConfig))
+		result, err = iter.Next()
+		require.NoError(t, err)
+		require.Nil(t, result)
+	}
+
+	storedValue := func(store *Store, txid string) []byte {
+		iter, err := store.db.GetIterator(createTxidRangeStartKey(txid), createTxidRangeEndKey(txid))
+		require.NoError(t, err)
+		defer iter.Release()
+		require.True(t, iter.Next())
+		return append([]byte{}, iter.Value()...)
+	}
+
+	plaintext, err := proto.Marshal(samplePvtRWSetWithConfig)
+	require.NoError(t, err)
+
+	storeProvider, err := NewStoreProviderWithConfig(config)
+	require.NoError(t, err)
+	store, err := storeProvider.OpenStore("TestStore")
+	require.NoError(t, err)
+	require.NoError(t, store.Persist("txid-1", 10, samplePvtRWSetWithConfig))
+
+	// the private write set is encrypted at rest
+	dbVal := storedValue(store, "txid-1")
+	require.Equal(t, encryptedByte, dbVal[0])
+	require.NotContains(t, string(dbVal), string(plaintext))
+	requireRetrieved(store, "txid-1")
+	storeProvider.Close()
+
+	// the key is retrieved from the keystore when the store is reopened,
+	// including once encryption is disabled
+	config.EncryptionEnabled = false
+	storeProvider, err = NewStoreProviderWithConfig(config)
+	require.NoError(t, err)
+	store, err = storeProvider.OpenStore("TestStore")
+	require.NoError(t, err)
+	requireRetrieved(store, "txid-1")
+
+	require.NoError(t, store.Persist("txid-2", 10, samplePvtRWSetWithConfig))
+	require.Equal(t, nilByte, storedValue(store, "txid-2")[0])
+	storeProvider.Close()
+
+	// an encrypted store can't be opened without a crypto provider
+	storeProvider, err = NewStoreProviderWithConfig(Config{Path: config.Path})
+	require.NoError(t, err)
+	defer storeProvider.Close()
+	_, err = storeProvider.OpenStore("TestStore")
+	require.EqualError(t, err, "the transient store of channel TestStore is encrypted, but no crypto provider is configured")
+
+	_, err = NewStoreProviderWithConfig(Config{Path: config.Path, EncryptionEnabled: true})
+	require.EqualError(t, err, "a crypto provider is required to encrypt the transient store")
+}
+
+func TestTransientStoreMaxSize(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "ts")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempdir)
+
+	samplePvtRWSetWithConfig := samplePvtDataWithConfigInfo(t)
+	plaintext, err := proto.Marshal(samplePvtRWSetWithConfig)
+	require.NoError(t, err)
+	entrySize := uint64(len(plaintext) + 1)
+
+	sizeGauge := &metricsfakes.Gauge{}
+	sizeGauge.WithReturns(sizeGauge)
+	entriesGauge := &metricsfakes.Gauge{}
+	entriesGauge.WithReturns(entriesGauge)
+	evictedCounter := &metricsfakes.Counter{}
+	evictedCounter.WithReturns(evictedCounter)
+	metricsProvider := &metricsfakes.Provider{}
+	metricsProvider.NewGaugeStub = func(opts metrics.GaugeOpts) metrics.Gauge {
+		if opts.Name == sizeOpts.Name {
+			return sizeGauge
+		}
+		return entriesGauge
+	}
+	metricsProvider.NewCounterReturns(evictedCounter)
+
+	config := Config{
+		Path:              tempdir,
+		MaxSizePerChannel: 2*entrySize + entrySize/2,
+		MetricsProvider:   metricsProvider,
+	}
+	storeProvider, err := NewStoreProviderWithConfig(config)
+	require.NoError(t, err)
+	store, err := storeProvider.OpenStore("TestStore")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Persist("txid-1", 12, samplePvtRWSetWithConfig))
+	require.NoError(t, store.Persist("txid-2", 10, samplePvtRWSetWithConfig))
+	require.Equal(t, 0, evictedCounter.AddCallCount())
+
+	// the private write set received at the lowest height is evicted
+	require.NoError(t, store.Persist("txid-3", 11, samplePvtRWSetWithConfig))
+	require.Equal(t, 1, evictedCounter.AddCallCount())
+	require.Equal(t, float64(1), evictedCounter.AddArgsForCall(0))
+	require.Equal(t, []string{"channel", "TestStore"}, evictedCounter.WithArgsForCall(0))
+	require.Equal(t, float64(2*entrySize), sizeGauge.SetArgsForCall(sizeGauge.SetCallCount()-1))
+	require.Equal(t, float64(2), entriesGauge.SetArgsForCall(entriesGauge.SetCallCount()-1))
+
+	minHt, err := store.GetMinTransientBlkHt()
+	require.NoError(t, err)
+	require.Equal(t, uint64(11), minHt)
+	iter, err := store.GetTxPvtRWSetByTxid("txid-2", nil)
+	require.NoError(t, err)
+	result, err := iter.Next()
+	require.NoError(t, err)
+	require.Nil(t, result)
+	iter.Close()
+
+	// purges release their share of the size
+	require.NoError(t, store.PurgeByTxids([]string{"txid-3"}))
+	require.Equal(t, entrySize, store.size)
+	require.Equal(t, float64(1), entriesGauge.SetArgsForCall(entriesGauge.SetCallCount()-1))
+
+	// private write sets larger than the maximum size are rejected
+	config.MaxSizePerChannel = entrySize - 1
+	storeProvider.Close()
+	storeProvider, err = NewStoreProviderWithConfig(config)
+	require.NoError(t, err)
+	defer storeProvider.Close()
+	store, err = storeProvider.OpenStore("TestStore")
+	require.NoError(t, err)
+	// the size is recomputed when the store is reopened
+	require.Equal(t, entrySize, store.size)
+	require.Equal(t, uint64(1), store.entries)
+
+	err = store.Persist("txid-4", 13, samplePvtRWSetWithConfig)
+	require.EqualError(t, err, fmt.Sprintf("private data of txid [txid-4] is %d bytes long, which exceeds the maximum size of the transient store of %d bytes", entrySize, entrySize-1))
+
+	require.NoError(t, store.PurgeBelowHeight(13))
+	require.Equal(t, uint64(0), store.size)
+	require.Equal(t, uint64(0), store.entries)
+}
+
 func TestTransientStoreRetrievalWithFilter(t *testing.T) {
 	env.initTestEnv(t)
 	defer env.cleanup()
@@ -26,6 +26,7 @@ import (
 	"github.com/osdi23p228/fabric/gossip/util"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
 
@@ -83,11 +84,14 @@ func NewCommInstance(s *grpc.Server, certs *common.TLSCertificates, idStore iden
 		connTimeout:     config.ConnTimeout,
 		recvBuffSize:    config.RecvBuffSize,
 		sendBuffSize:    config.SendBuffSize,
+		compression:     config.Compression,
+		joinedChannel:   config.JoinedChannel,
 	}
 
 	connConfig := ConnConfig{
-		RecvBuffSize: config.RecvBuffSize,
-		SendBuffSize: config.SendBuffSize,
+		RecvBuffSize:  config.RecvBuffSize,
+		SendBuffSize:  config.SendBuffSize,
+		JoinedChannel: config.JoinedChannel,
 	}
 
 	commInst.connStore = newConnStore(commInst, commInst.logger, connConfig)
@@ -103,6 +107,10 @@ type CommConfig struct {
 	ConnTimeout  time.Duration // Connection timeout
 	RecvBuffSize int           // Buffer size of received messages
 	SendBuffSize int           // Buffer size of sending messages
+	Compression  bool          // Compress messages on connections with peers which support it
+	// JoinedChannel reports whether the peer has joined the given channel. Received
+	// bytes of messages of other channels aren't labelled with their channel.
+	JoinedChannel func(channel common.ChannelID) bool
 }
 
 type commImpl struct {
@@ -129,6 +137,8 @@ type commImpl struct {
 	connTimeout     time.Duration
 	recvBuffSize    int
 	sendBuffSize    int
+	compression     bool
+	joinedChannel   func(channel common.ChannelID) bool
 }
 
 func (c *commImpl) createConnection(endpoint string, expectedPKIID common.PKIidType) (*connection, error) {
@@ -166,6 +176,9 @@ func (c *commImpl) createConnection(endpoint string, expectedPKIID common.PKIidT
 	}
 
 	ctx, cancel = context.WithCancel(context.Background())
+	if c.compression {
+		ctx = offerCompression(ctx)
+	}
 	if stream, err = cl.GossipStream(ctx); err == nil {
 		connInfo, err = c.authenticateRemotePeer(stream, true, false)
 		if err == nil {
@@ -188,14 +201,20 @@ func (c *commImpl) createConnection(endpoint string, expectedPKIID common.PKIidT
 				}
 			}
 			connConfig := ConnConfig{
-				RecvBuffSize: c.recvBuffSize,
-				SendBuffSize: c.sendBuffSize,
+				RecvBuffSize:  c.recvBuffSize,
+				SendBuffSize:  c.sendBuffSize,
+				JoinedChannel: c.joinedChannel,
 			}
 			conn := newConnection(cl, cc, stream, c.metrics, connConfig)
 			conn.pkiID = pkiID
 			conn.info = connInfo
 			conn.logger = c.logger
 			conn.cancel = cancel
+			if c.compression {
+				// the header is received along with the connection message of the remote peer
+				header, err := stream.Header()
+				conn.compressed = err == nil && compressionAccepted(header)
+			}
 
 			h := func(m *protoext.SignedGossipMessage) {
 				c.logger.Debug("Got message:", m)
@@ -564,6 +583,15 @@ func (c *commImpl) GossipStream(stream proto.Gossip_GossipStreamServer) error {
 	if c.isStopping() {
 		return fmt.Errorf("Shutting down")
 	}
+	compressed := c.compression && compressionOffered(stream.Context())
+	if compressed {
+		// the header is sent along with our connection message
+		if err := stream.SetHeader(metadata.Pairs(compressionMetadataKey, gzipCompression)); err != nil {
+			c.logger.Warningf("Failed accepting compression of stream from %s: %v", extractRemoteAddress(stream), err)
+			compressed = false
+		}
+	}
+
 	connInfo, err := c.authenticateRemotePeer(stream, false, false)
 
 	if err == errProbe {
@@ -578,6 +606,7 @@ func (c *commImpl) GossipStream(stream proto.Gossip_GossipStreamServer) error {
 	c.logger.Debug("Servicing", extractRemoteAddress(stream))
 
 	conn := c.connStore.onConnected(stream, connInfo, c.metrics)
+	conn.compressed = compressed
 
 	h := func(m *protoext.SignedGossipMessage) {
 		c.msgPublisher.DeMultiplex(&ReceivedMessageImpl{
@@ -128,6 +128,12 @@ func (*naiveSecProvider) VerifyByChannel(_ common.ChannelID, _ api.PeerIdentityT
 func newCommInstanceOnlyWithMetrics(t *testing.T, commMetrics *metrics.CommMetrics, sec *naiveSecProvider,
 	gRPCServer *comm.GRPCServer, certs *common.TLSCertificates,
 	secureDialOpts api.PeerSecureDialOpts, dialOpts ...grpc.DialOption) Comm {
+	return newCommInstanceOnlyWithConfig(t, commMetrics, testCommConfig, sec, gRPCServer, certs, secureDialOpts, dialOpts...)
+}
+
+func newCommInstanceOnlyWithConfig(t *testing.T, commMetrics *metrics.CommMetrics, config CommConfig, sec *naiveSecProvider,
+	gRPCServer *comm.GRPCServer, certs *common.TLSCertificates,
+	secureDialOpts api.PeerSecureDialOpts, dialOpts ...grpc.DialOption) Comm {
 
 	_, portString, err := net.SplitHostPort(gRPCServer.Address())
 	assert.NoError(t, err)
@@ -137,7 +143,7 @@ func newCommInstanceOnlyWithMetrics(t *testing.T, commMetrics *metrics.CommMetri
 	identityMapper := identity.NewIdentityMapper(sec, id, noopPurgeIdentity, sec)
 
 	commInst, err := NewCommInstance(gRPCServer.Server(), certs, identityMapper, id, secureDialOpts,
-		sec, commMetrics, testCommConfig, dialOpts...)
+		sec, commMetrics, config, dialOpts...)
 	assert.NoError(t, err)
 
 	go func() {
@@ -931,7 +937,7 @@ func TestReadFromStream(t *testing.T) {
 	stream.On("Recv").Return(&proto.Envelope{Payload: []byte{1}}, nil).Once()
 	stream.On("Recv").Return(nil, errors.New("stream closed")).Once()
 
-	conn := newConnection(nil, nil, stream, disabledMetrics, ConnConfig{1, 1})
+	conn := newConnection(nil, nil, stream, disabledMetrics, ConnConfig{RecvBuffSize: 1, SendBuffSize: 1})
 	conn.logger = flogging.MustGetLogger("test")
 
 	errChan := make(chan error, 2)
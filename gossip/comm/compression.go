@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	protolib "github.com/golang/protobuf/proto"
+	proto "github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// compressionMetadataKey is the gRPC metadata key used to negotiate the compression
+	// of the messages of a gossip stream. The initiator of the stream offers a compression
+	// in the metadata of the stream, and the responder accepts it by setting the same
+	// compression in the header of the stream.
+	compressionMetadataKey = "gossip-compression"
+	gzipCompression        = "gzip"
+)
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// offerCompression returns a context which offers the gzip compression to the
+// remote peer of a stream created with it
+func offerCompression(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, compressionMetadataKey, gzipCompression)
+}
+
+// compressionOffered returns whether the initiator of the stream offered the
+// gzip compression
+func compressionOffered(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	return ok && containsCompression(md)
+}
+
+// compressionAccepted returns whether the header received from the responder of
+// the stream accepts the gzip compression
+func compressionAccepted(header metadata.MD) bool {
+	return containsCompression(header)
+}
+
+func containsCompression(md metadata.MD) bool {
+	for _, compression := range md.Get(compressionMetadataKey) {
+		if compression == gzipCompression {
+			return true
+		}
+	}
+	return false
+}
+
+// compressEnvelope returns an envelope whose payload is the gzip compressed
+// serialization of the given envelope
+func compressEnvelope(envelope *proto.Envelope) (*proto.Envelope, error) {
+	envelopeBytes, err := protolib.Marshal(envelope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshaling envelope")
+	}
+
+	buf := &bytes.Buffer{}
+	w := gzipWriterPool.Get().(*gzip.Writer)
+	defer gzipWriterPool.Put(w)
+	w.Reset(buf)
+	if _, err := w.Write(envelopeBytes); err != nil {
+		return nil, errors.Wrap(err, "failed compressing envelope")
+	}
+	if err := w.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed compressing envelope")
+	}
+
+	return &proto.Envelope{Payload: buf.Bytes()}, nil
+}
+
+// decompressEnvelope returns the envelope compressed in the payload of the given
+// envelope by compressEnvelope. Envelopes which decompress to more than maxSize
+// bytes are rejected, so that a small payload can't expand beyond the size of the
+// messages received uncompressed
+func decompressEnvelope(envelope *proto.Envelope, maxSize int) (*proto.Envelope, error) {
+	r, err := gzip.NewReader(bytes.NewReader(envelope.Payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed decompressing envelope")
+	}
+	defer r.Close()
+	envelopeBytes, err := ioutil.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed decompressing envelope")
+	}
+	if len(envelopeBytes) > maxSize {
+		return nil, errors.Errorf("decompressed envelope exceeds the max receive message size of %d bytes", maxSize)
+	}
+
+	decompressed := &proto.Envelope{}
+	if err := protolib.Unmarshal(envelopeBytes, decompressed); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling envelope")
+	}
+	return decompressed, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	protolib "github.com/golang/protobuf/proto"
+	proto "github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/osdi23p228/fabric/gossip/common"
+	"github.com/osdi23p228/fabric/gossip/protoext"
+	"github.com/osdi23p228/fabric/gossip/util"
+	"github.com/osdi23p228/fabric/internal/pkg/comm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressEnvelope(t *testing.T) {
+	msg, err := protoext.NoopSign(&proto.GossipMessage{
+		Tag:     proto.GossipMessage_CHAN_ONLY,
+		Channel: []byte("mychannel"),
+		Content: &proto.GossipMessage_DataMsg{
+			DataMsg: &proto.DataMessage{
+				Payload: &proto.Payload{SeqNum: 1, Data: bytes.Repeat([]byte("block"), 1000)},
+			},
+		},
+	})
+	require.NoError(t, err)
+	msg.Envelope.Signature = []byte("signature")
+
+	compressed, err := compressEnvelope(msg.Envelope)
+	require.NoError(t, err)
+	assert.Nil(t, compressed.Signature)
+	assert.True(t, protolib.Size(compressed) < protolib.Size(msg.Envelope))
+
+	decompressed, err := decompressEnvelope(compressed, protolib.Size(msg.Envelope))
+	require.NoError(t, err)
+	assert.True(t, protolib.Equal(msg.Envelope, decompressed))
+
+	_, err = decompressEnvelope(compressed, protolib.Size(msg.Envelope)-1)
+	assert.EqualError(t, err, fmt.Sprintf("decompressed envelope exceeds the max receive message size of %d bytes", protolib.Size(msg.Envelope)-1))
+
+	_, err = decompressEnvelope(&proto.Envelope{Payload: []byte("not compressed")}, comm.MaxRecvMsgSize)
+	assert.EqualError(t, err, "failed decompressing envelope: gzip: invalid header")
+}
+
+func TestCompressionNegotiation(t *testing.T) {
+	tests := []struct {
+		name                string
+		initiatorCompresses bool
+		responderCompresses bool
+		expectedCompressed  bool
+	}{
+		{"both enabled", true, true, true},
+		{"initiator only", true, false, false},
+		{"responder only", false, true, false},
+		{"both disabled", false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newComm := func(compression bool) (*commGRPC, int) {
+				config := testCommConfig
+				config.Compression = compression
+				port, gRPCServer, certs, secureDialOpts, dialOpts := util.CreateGRPCLayer()
+				c := newCommInstanceOnlyWithConfig(t, disabledMetrics, config, naiveSec, gRPCServer, certs, secureDialOpts, dialOpts...)
+				return c.(*commGRPC), port
+			}
+			initiator, initiatorPort := newComm(tt.initiatorCompresses)
+			defer initiator.Stop()
+			responder, responderPort := newComm(tt.responderCompresses)
+			defer responder.Stop()
+
+			fromInitiator := responder.Accept(acceptAll)
+			fromResponder := initiator.Accept(acceptAll)
+
+			initiator.Send(createGossipMsg(), remotePeer(responderPort))
+			select {
+			case <-fromInitiator:
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for the message of the initiator")
+			}
+			responder.Send(createGossipMsg(), remotePeer(initiatorPort))
+			select {
+			case <-fromResponder:
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for the message of the responder")
+			}
+
+			assert.Equal(t, tt.expectedCompressed, isCompressed(initiator, remotePeer(responderPort).PKIID))
+			assert.Equal(t, tt.expectedCompressed, isCompressed(responder, remotePeer(initiatorPort).PKIID))
+		})
+	}
+}
+
+func isCompressed(c *commGRPC, pkiID common.PKIidType) bool {
+	c.connStore.RLock()
+	defer c.connStore.RUnlock()
+	return c.connStore.pki2Conn[string(pkiID)].compressed
+}
+
+func TestMessageType(t *testing.T) {
+	tests := []struct {
+		msg      *proto.GossipMessage
+		expected string
+	}{
+		{&proto.GossipMessage{Content: &proto.GossipMessage_AliveMsg{}}, "alive"},
+		{&proto.GossipMessage{Content: &proto.GossipMessage_MemReq{}}, "membership"},
+		{&proto.GossipMessage{Content: &proto.GossipMessage_DataMsg{}}, "data"},
+		{&proto.GossipMessage{Content: &proto.GossipMessage_DataDig{}}, "pull"},
+		{&proto.GossipMessage{Content: &proto.GossipMessage_StateInfo{}}, "state_info"},
+		{&proto.GossipMessage{Content: &proto.GossipMessage_StateSnapshot{}}, "state_info"},
+		{&proto.GossipMessage{Content: &proto.GossipMessage_StateResponse{}}, "state_transfer"},
+		{&proto.GossipMessage{Content: &proto.GossipMessage_LeadershipMsg{}}, "leadership"},
+		{&proto.GossipMessage{Content: &proto.GossipMessage_PeerIdentity{}}, "identity"},
+		{&proto.GossipMessage{Content: &proto.GossipMessage_PrivateData{}}, "privdata"},
+		{&proto.GossipMessage{Content: &proto.GossipMessage_PrivateReq{}}, "privdata"},
+		{&proto.GossipMessage{Content: &proto.GossipMessage_Ack{}}, "other"},
+		{nil, "other"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, messageType(tt.msg))
+	}
+}
@@ -10,11 +10,13 @@ import (
 	"context"
 	"sync"
 
+	protolib "github.com/golang/protobuf/proto"
 	proto "github.com/hyperledger/fabric-protos-go/gossip"
 	"github.com/osdi23p228/fabric/gossip/common"
 	"github.com/osdi23p228/fabric/gossip/metrics"
 	"github.com/osdi23p228/fabric/gossip/protoext"
 	"github.com/osdi23p228/fabric/gossip/util"
+	"github.com/osdi23p228/fabric/internal/pkg/comm"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 )
@@ -188,37 +190,41 @@ func (cs *connectionStore) closeConnByPKIid(pkiID common.PKIidType) {
 
 func newConnection(cl proto.GossipClient, c *grpc.ClientConn, s stream, metrics *metrics.CommMetrics, config ConnConfig) *connection {
 	connection := &connection{
-		metrics:      metrics,
-		outBuff:      make(chan *msgSending, config.SendBuffSize),
-		cl:           cl,
-		conn:         c,
-		gossipStream: s,
-		stopChan:     make(chan struct{}, 1),
-		recvBuffSize: config.RecvBuffSize,
+		metrics:       metrics,
+		outBuff:       make(chan *msgSending, config.SendBuffSize),
+		cl:            cl,
+		conn:          c,
+		gossipStream:  s,
+		stopChan:      make(chan struct{}, 1),
+		recvBuffSize:  config.RecvBuffSize,
+		joinedChannel: config.JoinedChannel,
 	}
 	return connection
 }
 
 // ConnConfig is the configuration required to initialize a new conn
 type ConnConfig struct {
-	RecvBuffSize int
-	SendBuffSize int
+	RecvBuffSize  int
+	SendBuffSize  int
+	JoinedChannel func(channel common.ChannelID) bool
 }
 
 type connection struct {
-	recvBuffSize int
-	metrics      *metrics.CommMetrics
-	cancel       context.CancelFunc
-	info         *protoext.ConnectionInfo
-	outBuff      chan *msgSending
-	logger       util.Logger        // logger
-	pkiID        common.PKIidType   // pkiID of the remote endpoint
-	handler      handler            // function to invoke upon a message reception
-	conn         *grpc.ClientConn   // gRPC connection to remote endpoint
-	cl           proto.GossipClient // gRPC stub of remote endpoint
-	gossipStream stream             // there can only be one
-	stopChan     chan struct{}      // a method to stop the server-side gRPC call from a different go-routine
-	stopOnce     sync.Once          // once to ensure close is called only once
+	recvBuffSize  int
+	joinedChannel func(channel common.ChannelID) bool
+	metrics       *metrics.CommMetrics
+	cancel        context.CancelFunc
+	info          *protoext.ConnectionInfo
+	outBuff       chan *msgSending
+	logger        util.Logger        // logger
+	pkiID         common.PKIidType   // pkiID of the remote endpoint
+	handler       handler            // function to invoke upon a message reception
+	conn          *grpc.ClientConn   // gRPC connection to remote endpoint
+	cl            proto.GossipClient // gRPC stub of remote endpoint
+	gossipStream  stream             // there can only be one
+	compressed    bool               // whether the messages of the stream are compressed
+	stopChan      chan struct{}      // a method to stop the server-side gRPC call from a different go-routine
+	stopOnce      sync.Once          // once to ensure close is called only once
 }
 
 func (conn *connection) close() {
@@ -239,6 +245,8 @@ func (conn *connection) send(msg *protoext.SignedGossipMessage, onErr func(error
 	m := &msgSending{
 		envelope: msg.Envelope,
 		onErr:    onErr,
+		msgType:  messageType(msg.GossipMessage),
+		channel:  string(msg.GossipMessage.GetChannel()),
 	}
 
 	select {
@@ -289,12 +297,21 @@ func (conn *connection) writeToStream() {
 	for {
 		select {
 		case m := <-conn.outBuff:
-			err := stream.Send(m.envelope)
+			envelope := m.envelope
+			if conn.compressed {
+				var err error
+				if envelope, err = compressEnvelope(envelope); err != nil {
+					go m.onErr(err)
+					return
+				}
+			}
+			err := stream.Send(envelope)
 			if err != nil {
 				go m.onErr(err)
 				return
 			}
 			conn.metrics.SentMessages.Add(1)
+			conn.metrics.SentBytes.With("type", m.msgType, "channel", m.channel).Add(float64(protolib.Size(envelope)))
 		case <-conn.stopChan:
 			conn.logger.Debug("Closing writing to stream")
 			return
@@ -316,12 +333,21 @@ func (conn *connection) readFromStream(errChan chan error, msgChan chan *protoex
 				return
 			}
 			conn.metrics.ReceivedMessages.Add(1)
+			receivedBytes := protolib.Size(envelope)
+			if conn.compressed {
+				if envelope, err = decompressEnvelope(envelope, comm.MaxRecvMsgSize); err != nil {
+					errChan <- err
+					conn.logger.Warningf("Got error, aborting: %v", err)
+					return
+				}
+			}
 			msg, err := protoext.EnvelopeToGossipMessage(envelope)
 			if err != nil {
 				errChan <- err
 				conn.logger.Warningf("Got error, aborting: %v", err)
 				return
 			}
+			conn.metrics.ReceivedBytes.With("type", messageType(msg.GossipMessage), "channel", conn.channelLabel(msg.Channel)).Add(float64(receivedBytes))
 			select {
 			case <-conn.stopChan:
 			case msgChan <- msg:
@@ -330,9 +356,51 @@ func (conn *connection) readFromStream(errChan chan error, msgChan chan *protoex
 	}
 }
 
+// channelLabel returns the channel label of a received message. The channel is
+// set by the remote peer, so channels this peer hasn't joined are all labelled
+// as "other" to keep the number of time series bounded.
+func (conn *connection) channelLabel(channel []byte) string {
+	if len(channel) == 0 {
+		return ""
+	}
+	if conn.joinedChannel != nil && conn.joinedChannel(common.ChannelID(channel)) {
+		return string(channel)
+	}
+	return "other"
+}
+
 type msgSending struct {
 	envelope *proto.Envelope
 	onErr    func(error)
+	msgType  string
+	channel  string
+}
+
+// messageType returns the type of a gossip message as reported by the
+// bytes sent and received metrics
+func messageType(m *proto.GossipMessage) string {
+	switch m.GetContent().(type) {
+	case *proto.GossipMessage_AliveMsg:
+		return "alive"
+	case *proto.GossipMessage_MemReq, *proto.GossipMessage_MemRes:
+		return "membership"
+	case *proto.GossipMessage_DataMsg:
+		return "data"
+	case *proto.GossipMessage_Hello, *proto.GossipMessage_DataDig, *proto.GossipMessage_DataReq, *proto.GossipMessage_DataUpdate:
+		return "pull"
+	case *proto.GossipMessage_StateInfo, *proto.GossipMessage_StateSnapshot, *proto.GossipMessage_StateInfoPullReq:
+		return "state_info"
+	case *proto.GossipMessage_StateRequest, *proto.GossipMessage_StateResponse:
+		return "state_transfer"
+	case *proto.GossipMessage_LeadershipMsg:
+		return "leadership"
+	case *proto.GossipMessage_PeerIdentity:
+		return "identity"
+	case *proto.GossipMessage_PrivateReq, *proto.GossipMessage_PrivateRes, *proto.GossipMessage_PrivateData:
+		return "privdata"
+	default:
+		return "other"
+	}
 }
 
 //go:generate mockery -dir . -name MockStream -case underscore -output mocks/
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/osdi23p228/fabric/gossip/common"
 	"github.com/osdi23p228/fabric/gossip/metrics"
 	"github.com/osdi23p228/fabric/gossip/metrics/mocks"
 	"github.com/osdi23p228/fabric/gossip/util"
@@ -80,5 +81,25 @@ func TestMetrics(t *testing.T) {
 		testMetricProvider.FakeBufferOverflow.AddArgsForCall(0),
 	)
 
+	assert.Equal(t, []string{"type", "data", "channel", ""}, testMetricProvider.FakeSentBytes.WithArgsForCall(0))
+	assert.True(t, testMetricProvider.FakeSentBytes.AddArgsForCall(0) > 0)
+	assert.Equal(t, []string{"type", "data", "channel", ""}, testMetricProvider.FakeReceivedBytes.WithArgsForCall(0))
+	assert.True(t, testMetricProvider.FakeReceivedBytes.AddArgsForCall(0) > 0)
+
 	assert.Equal(t, uint32(1), atomic.LoadUint32(&overflown))
 }
+
+func TestReceivedBytesChannelLabel(t *testing.T) {
+	conn := newConnection(nil, nil, nil, nil, ConnConfig{})
+	assert.Equal(t, "", conn.channelLabel(nil))
+	assert.Equal(t, "other", conn.channelLabel([]byte("A")))
+
+	conn = newConnection(nil, nil, nil, nil, ConnConfig{
+		JoinedChannel: func(channel common.ChannelID) bool {
+			return string(channel) == "A"
+		},
+	})
+	assert.Equal(t, "", conn.channelLabel(nil))
+	assert.Equal(t, "A", conn.channelLabel([]byte("A")))
+	assert.Equal(t, "other", conn.channelLabel([]byte("B")))
+}
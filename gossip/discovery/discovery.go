@@ -155,6 +155,12 @@ type Discovery interface {
 	// GetMembership returns the alive members in the view
 	GetMembership() []NetworkMember
 
+	// GetDeadMembership returns the members in the view that are presumed dead
+	GetDeadMembership() []NetworkMember
+
+	// Purge removes a member from the view, whether it is alive or presumed dead
+	Purge(PKIID common.PKIidType)
+
 	// InitiateSync makes the instance ask a given number of peers
 	// for their membership information
 	InitiateSync(peerNum int)
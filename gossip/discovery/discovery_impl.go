@@ -566,6 +566,11 @@ func (d *gossipDiscoveryImpl) purge(id common.PKIidType) {
 	delete(d.aliveLastTS, string(id))
 }
 
+// Purge removes a member from the view, whether it is alive or presumed dead
+func (d *gossipDiscoveryImpl) Purge(id common.PKIidType) {
+	d.purge(id)
+}
+
 func (d *gossipDiscoveryImpl) isSentByMe(m *protoext.SignedGossipMessage) bool {
 	pkiID := m.GetAliveMsg().Membership.PkiId
 	if !equalPKIid(pkiID, d.self.PKIid) {
@@ -963,6 +968,30 @@ func (d *gossipDiscoveryImpl) GetMembership() []NetworkMember {
 
 }
 
+func (d *gossipDiscoveryImpl) GetDeadMembership() []NetworkMember {
+	if d.toDie() {
+		return []NetworkMember{}
+	}
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	response := []NetworkMember{}
+	for _, m := range d.deadMembership.ToSlice() {
+		member := m.GetAliveMsg()
+		networkMember := NetworkMember{
+			PKIid:    member.Membership.PkiId,
+			Endpoint: member.Membership.Endpoint,
+			Metadata: member.Membership.Metadata,
+			Envelope: m.Envelope,
+		}
+		if known := d.id2Member[string(member.Membership.PkiId)]; known != nil {
+			networkMember.InternalEndpoint = known.InternalEndpoint
+		}
+		response = append(response, networkMember)
+	}
+	return response
+}
+
 func tsToTime(ts uint64) time.Time {
 	return time.Unix(int64(0), int64(ts))
 }
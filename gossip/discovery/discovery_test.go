@@ -897,6 +897,21 @@ func TestExpiration(t *testing.T) {
 	waitUntilOrFailBlocking(t, instances[nodeNum-2].Stop)
 
 	assertMembership(t, instances[:len(instances)-2], nodeNum-3)
+	for _, inst := range instances[:len(instances)-2] {
+		deadEndpoints := []string{}
+		for _, member := range inst.GetDeadMembership() {
+			deadEndpoints = append(deadEndpoints, member.Endpoint)
+		}
+		assert.ElementsMatch(t, []string{bootPeer(2614), bootPeer(2615)}, deadEndpoints)
+	}
+
+	// A purged member is removed from the view, even if it is presumed dead
+	purged := instances[0].GetDeadMembership()[0]
+	instances[0].Purge(purged.PKIid)
+	for _, member := range instances[0].GetDeadMembership() {
+		assert.NotEqual(t, purged.Endpoint, member.Endpoint)
+	}
+	assert.Len(t, instances[0].GetDeadMembership(), 1)
 
 	stopAction := &sync.WaitGroup{}
 	for i, inst := range instances {
@@ -9,6 +9,7 @@ package election
 import (
 	"bytes"
 	"encoding/hex"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,7 +22,10 @@ import (
 // - Peers break symmetry by comparing IDs
 // - Each peer is either a leader or a follower,
 //   and the aim is to have exactly 1 leader if the membership view
-//   is the same for all peers
+//   is the same for all peers, or as many leaders as the Strategy
+//   in use allows
+// - Peers rank candidates according to the Strategy in use, which by
+//   default ranks them by their IDs
 // - If the network is partitioned into 2 or more sets, the number of leaders
 //   is the number of network partitions, but when the partition heals,
 //   only 1 leader should be left eventually
@@ -35,7 +39,8 @@ import (
 //
 // Invariant:
 //	Peer listens for messages from remote peers
-//	and whenever it receives a leadership declaration,
+//	and whenever it receives a leadership declaration and the
+//	number of recent leaders reaches the maximum number of leaders,
 //	leaderKnown is set to true
 //
 // Startup():
@@ -49,8 +54,8 @@ import (
 // 			LeaderElection()
 //		If you are the leader:
 //			Broadcast leadership declaration
-//			If a leadership declaration was received from
-// 			a peer with a lower ID,
+//			If leadership declarations were received from
+// 			as many better ranked peers as the maximum number of leaders,
 //			become a follower
 //		Else, you're a follower:
 //			If haven't received a leadership declaration within
@@ -60,11 +65,11 @@ import (
 // LeaderElection():
 // 	Gossip leadership proposal message
 //	Collect messages from other peers sent within a time period
-//	If received a leadership declaration:
+//	If leaderKnown is true:
 //		return
 //	Iterate over all proposal messages collected.
-// 	If a proposal message from a peer with an ID lower
-// 	than yourself was received, return.
+// 	If the number of proposals from better ranked peers
+// 	reaches the number of leaders missing, return.
 //	Else, declare yourself a leader
 
 // LeaderElectionAdapter is used by the leader election module
@@ -99,6 +104,10 @@ type LeaderElectionService interface {
 	// Yield relinquishes the leadership until a new leader is elected,
 	// or a timeout expires
 	Yield()
+
+	// Leaders returns the IDs of the peers this peer considers to be the leaders,
+	// best ranked first, or nil if no peer declared itself as a leader recently
+	Leaders() [][]byte
 }
 
 type peerID []byte
@@ -141,6 +150,9 @@ type ElectionConfig struct {
 	MembershipSampleInterval time.Duration
 	LeaderAliveThreshold     time.Duration
 	LeaderElectionDuration   time.Duration
+	// Strategy ranks the candidates for leadership and sets the number
+	// of leaders. If nil, the single peer with the lowest ID is elected.
+	Strategy Strategy
 }
 
 // NewLeaderElectionService returns a new LeaderElectionService
@@ -157,6 +169,11 @@ func NewLeaderElectionService(adapter LeaderElectionAdapter, id string, callback
 		logger:        util.GetLogger(util.ElectionLogger, ""),
 		callback:      noopCallback,
 		config:        config,
+		strategy:      config.Strategy,
+		declarations:  make(map[string]time.Time),
+	}
+	if le.strategy == nil {
+		le.strategy = NewLowestIDStrategy(1)
 	}
 
 	if callback != nil {
@@ -184,6 +201,10 @@ type leaderElectionSvcImpl struct {
 	callback      leadershipCallback
 	yieldTimer    *time.Timer
 	config        ElectionConfig
+	strategy      Strategy
+	// declarations maps the IDs of the peers that declared themselves as leaders
+	// to the time their last declaration was received
+	declarations map[string]time.Time
 }
 
 func (le *leaderElectionSvcImpl) start() {
@@ -224,11 +245,19 @@ func (le *leaderElectionSvcImpl) handleMessage(msg Msg) {
 	if msg.IsProposal() {
 		le.proposals.Add(string(msg.SenderID()))
 	} else if msg.IsDeclaration() {
+		le.declarations[string(msg.SenderID())] = time.Now()
+		leaders := le.recentLeaders()
+		if le.IsLeader() {
+			leaders = append(leaders, le.id)
+		}
+		if len(leaders) < le.strategy.MaxLeaders() {
+			return
+		}
 		atomic.StoreInt32(&le.leaderExists, int32(1))
 		if le.sleeping && len(le.interruptChan) == 0 {
 			le.interruptChan <- struct{}{}
 		}
-		if bytes.Compare(msg.SenderID(), le.id) < 0 && le.IsLeader() {
+		if le.IsLeader() && le.betterThanSelf(le.recentLeaders()) >= le.strategy.MaxLeaders() {
 			le.stopBeingLeader()
 		}
 	} else {
@@ -306,14 +335,21 @@ func (le *leaderElectionSvcImpl) leaderElection() {
 		le.logger.Debug(le.id, ": Aborting leader election because yielding")
 		return
 	}
-	// Leader doesn't exist, let's see if there is a better candidate than us
-	// for being a leader
+	// Not enough leaders exist, let's see if there are enough better
+	// candidates than us for being the missing leaders
+	le.Lock()
+	leaders := le.recentLeaders()
+	var candidates []peerID
 	for _, o := range le.proposals.ToArray() {
-		id := o.(string)
-		if bytes.Compare(peerID(id), le.id) < 0 {
-			return
+		id := peerID(o.(string))
+		if !containsPeer(leaders, id) {
+			candidates = append(candidates, id)
 		}
 	}
+	le.Unlock()
+	if le.betterThanSelf(candidates) >= le.strategy.MaxLeaders()-len(leaders) {
+		return
+	}
 	// If we got here, there is no one that proposed being a leader
 	// that's a better candidate than us.
 	le.beLeader()
@@ -394,6 +430,61 @@ func (le *leaderElectionSvcImpl) IsLeader() bool {
 	return isLeader
 }
 
+// Leaders returns the IDs of the peers this peer considers to be the leaders,
+// best ranked first, or nil if no peer declared itself as a leader recently
+func (le *leaderElectionSvcImpl) Leaders() [][]byte {
+	le.Lock()
+	leaders := le.recentLeaders()
+	le.Unlock()
+	if le.IsLeader() && !containsPeer(leaders, le.id) {
+		leaders = append(leaders, le.id)
+	}
+	sort.Slice(leaders, func(i, j int) bool {
+		return le.strategy.Less(leaders[i], leaders[j])
+	})
+	var ids [][]byte
+	for _, leader := range leaders {
+		ids = append(ids, leader)
+	}
+	return ids
+}
+
+// recentLeaders returns the peers that declared themselves as leaders within
+// the leader alive threshold, and forgets the others. Must be called while
+// holding the lock.
+func (le *leaderElectionSvcImpl) recentLeaders() []peerID {
+	var leaders []peerID
+	for id, t := range le.declarations {
+		if time.Since(t) > le.config.LeaderAliveThreshold {
+			delete(le.declarations, id)
+			continue
+		}
+		leaders = append(leaders, peerID(id))
+	}
+	return leaders
+}
+
+// betterThanSelf returns the number of the given peers that are better
+// candidates for leadership than this peer
+func (le *leaderElectionSvcImpl) betterThanSelf(peers []peerID) int {
+	count := 0
+	for _, p := range peers {
+		if le.strategy.Less(p, le.id) {
+			count++
+		}
+	}
+	return count
+}
+
+func containsPeer(peers []peerID, id peerID) bool {
+	for _, p := range peers {
+		if bytes.Equal(p, id) {
+			return true
+		}
+	}
+	return false
+}
+
 func (le *leaderElectionSvcImpl) beLeader() {
 	le.logger.Info(le.id, ": Becoming a leader")
 	atomic.StoreInt32(&le.isLeader, int32(1))
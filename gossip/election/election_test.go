@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package election
 
 import (
+	"bytes"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -145,11 +146,15 @@ func (p *peer) isCallbackInvoked() bool {
 }
 
 func createPeers(spawnInterval time.Duration, ids ...int) []*peer {
+	return createPeersWithStrategy(spawnInterval, nil, ids...)
+}
+
+func createPeersWithStrategy(spawnInterval time.Duration, strategy Strategy, ids ...int) []*peer {
 	peers := make([]*peer, len(ids))
 	peerMap := make(map[string]*peer)
 	l := &sync.RWMutex{}
 	for i, id := range ids {
-		p := createPeer(id, peerMap, l)
+		p := createPeerWithStrategy(id, peerMap, l, func(mock.Arguments) {}, strategy)
 		if spawnInterval != 0 {
 			time.Sleep(spawnInterval)
 		}
@@ -159,6 +164,10 @@ func createPeers(spawnInterval time.Duration, ids ...int) []*peer {
 }
 
 func createPeerWithCostumeMetrics(id int, peerMap map[string]*peer, l *sync.RWMutex, f func(mock.Arguments)) *peer {
+	return createPeerWithStrategy(id, peerMap, l, f, nil)
+}
+
+func createPeerWithStrategy(id int, peerMap map[string]*peer, l *sync.RWMutex, f func(mock.Arguments), strategy Strategy) *peer {
 	idStr := fmt.Sprintf("p%d", id)
 	c := make(chan Msg, 100)
 	p := &peer{id: idStr, peers: peerMap, sharedLock: l, msgChan: c, mockedMethods: make(map[string]struct{}), leaderFromCallback: false, callbackInvoked: false}
@@ -168,6 +177,7 @@ func createPeerWithCostumeMetrics(id int, peerMap map[string]*peer, l *sync.RWMu
 		MembershipSampleInterval: testMembershipSampleInterval,
 		LeaderAliveThreshold:     testLeaderAliveThreshold,
 		LeaderElectionDuration:   testLeaderElectionDuration,
+		Strategy:                 strategy,
 	}
 	p.LeaderElectionService = NewLeaderElectionService(p, idStr, p.leaderCallback, config)
 	l.Lock()
@@ -259,6 +269,12 @@ func TestInitPeersStartAtIntervals(t *testing.T) {
 	peers := createPeers(testStartupGracePeriod+testLeadershipDeclarationInterval, 3, 2, 1, 0)
 	waitForLeaderElection(t, peers)
 	assert.True(t, peers[0].IsLeader())
+	for _, p := range peers {
+		waitForBoolFunc(t, func() bool {
+			leaders := p.Leaders()
+			return len(leaders) == 1 && bytes.Equal(leaders[0], []byte("p3"))
+		}, true, p.id, "doesn't consider p3 as the leader")
+	}
 }
 
 func TestStop(t *testing.T) {
@@ -354,6 +370,40 @@ func TestLeadershipTakeover(t *testing.T) {
 	assert.Equal(t, "p2", leaders[0])
 }
 
+func TestMultipleLeaders(t *testing.T) {
+	// Scenario: Peers spawn at the same time with a strategy of 2 leaders.
+	// After a while, one of the leaders stops.
+	// expected outcome: the 2 peers with the lowest IDs are the leaders,
+	// and the peer with the next lowest ID takes over from the stopped leader
+	peers := createPeersWithStrategy(0, NewLowestIDStrategy(2), 5, 4, 3, 2, 1, 0)
+	leaders := waitForMultipleLeadersElection(t, peers, 2)
+	assert.ElementsMatch(t, []string{"p0", "p1"}, leaders)
+	for _, p := range peers {
+		waitForBoolFunc(t, func() bool {
+			leaders := p.Leaders()
+			return len(leaders) == 2 && bytes.Equal(leaders[0], []byte("p0")) && bytes.Equal(leaders[1], []byte("p1"))
+		}, true, p.id, "doesn't consider p0 and p1 as the leaders")
+	}
+
+	peers[len(peers)-1].Stop()
+	time.Sleep(testLeadershipDeclarationInterval + testLeaderAliveThreshold*3)
+	leaders = waitForMultipleLeadersElection(t, peers[:len(peers)-1], 2)
+	assert.ElementsMatch(t, []string{"p1", "p2"}, leaders)
+}
+
+func TestLowestScoreStrategy(t *testing.T) {
+	// Scenario: Peers spawn at the same time with a strategy that ranks them by scores
+	// expected outcome: the peer with the lowest score is the leader
+	scores := map[string]uint64{"p0": 5, "p1": 1, "p2": 3}
+	strategy := NewLowestScoreStrategy(1, func(id []byte) (uint64, bool) {
+		score, exists := scores[string(id)]
+		return score, exists
+	})
+	peers := createPeersWithStrategy(0, strategy, 3, 2, 1, 0)
+	leaders := waitForLeaderElection(t, peers)
+	assert.Equal(t, []string{"p1"}, leaders)
+}
+
 func TestYield(t *testing.T) {
 	// Scenario: Peers spawn and a leader is elected.
 	// After a while, the leader yields.
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package election
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Strategy determines how many peers of an organization lead a channel
+// concurrently, and how peers rank as candidates for leading it.
+// All the peers of an organization must use the same strategy.
+type Strategy interface {
+	// MaxLeaders returns the number of peers that lead the channel concurrently
+	MaxLeaders() int
+
+	// Less returns whether the peer with ID p1 is a better candidate
+	// for leading the channel than the peer with ID p2
+	Less(p1, p2 []byte) bool
+}
+
+type lowestIDStrategy struct {
+	maxLeaders int
+}
+
+// NewLowestIDStrategy returns a Strategy that elects the maxLeaders peers
+// with the lowest IDs
+func NewLowestIDStrategy(maxLeaders int) Strategy {
+	if maxLeaders < 1 {
+		maxLeaders = 1
+	}
+	return &lowestIDStrategy{maxLeaders: maxLeaders}
+}
+
+func (s *lowestIDStrategy) MaxLeaders() int {
+	return s.maxLeaders
+}
+
+func (s *lowestIDStrategy) Less(p1, p2 []byte) bool {
+	return bytes.Compare(p1, p2) < 0
+}
+
+// ScoreSource returns the score a peer published for leading the channel,
+// or false if the peer didn't publish any
+type ScoreSource func(id []byte) (score uint64, exists bool)
+
+type lowestScoreStrategy struct {
+	lowestIDStrategy
+	scores ScoreSource
+}
+
+// NewLowestScoreStrategy returns a Strategy that elects the maxLeaders peers with
+// the lowest scores. Peers that didn't publish a score rank after those that did,
+// and ties are broken by comparing IDs.
+func NewLowestScoreStrategy(maxLeaders int, scores ScoreSource) Strategy {
+	s := &lowestScoreStrategy{scores: scores}
+	s.lowestIDStrategy = *NewLowestIDStrategy(maxLeaders).(*lowestIDStrategy)
+	return s
+}
+
+func (s *lowestScoreStrategy) Less(p1, p2 []byte) bool {
+	score1, exists1 := s.scores(p1)
+	score2, exists2 := s.scores(p2)
+	switch {
+	case exists1 && !exists2:
+		return true
+	case !exists1 && exists2:
+		return false
+	case exists1 && exists2 && score1 != score2:
+		return score1 < score2
+	default:
+		return s.lowestIDStrategy.Less(p1, p2)
+	}
+}
+
+// electionMetadata is published by peers in the metadata of their alive messages
+type electionMetadata struct {
+	// Scores maps channels to the score of the peer for leading them
+	Scores map[string]uint64 `json:"election_scores"`
+}
+
+// EncodeScores encodes the scores of the peer for leading channels,
+// to be published in the metadata of its alive messages
+func EncodeScores(scores map[string]uint64) []byte {
+	md, _ := json.Marshal(&electionMetadata{Scores: scores})
+	return md
+}
+
+// ScoreFromMetadata returns the score for leading the given channel encoded
+// by EncodeScores in the metadata of a peer, or false if there is none
+func ScoreFromMetadata(md []byte, channel string) (uint64, bool) {
+	if len(md) == 0 {
+		return 0, false
+	}
+	var metadata electionMetadata
+	if err := json.Unmarshal(md, &metadata); err != nil {
+		return 0, false
+	}
+	score, exists := metadata.Scores[channel]
+	return score, exists
+}
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package election
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLowestIDStrategy(t *testing.T) {
+	s := NewLowestIDStrategy(0)
+	assert.Equal(t, 1, s.MaxLeaders())
+	assert.True(t, s.Less([]byte("p0"), []byte("p1")))
+	assert.False(t, s.Less([]byte("p1"), []byte("p0")))
+	assert.False(t, s.Less([]byte("p0"), []byte("p0")))
+
+	assert.Equal(t, 3, NewLowestIDStrategy(3).MaxLeaders())
+}
+
+func TestLowestScoreStrategy_Less(t *testing.T) {
+	scores := map[string]uint64{"p0": 7, "p1": 2, "p2": 2}
+	s := NewLowestScoreStrategy(2, func(id []byte) (uint64, bool) {
+		score, exists := scores[string(id)]
+		return score, exists
+	})
+	assert.Equal(t, 2, s.MaxLeaders())
+
+	// The lowest score wins
+	assert.True(t, s.Less([]byte("p1"), []byte("p0")))
+	assert.False(t, s.Less([]byte("p0"), []byte("p1")))
+	// Ties are broken by IDs
+	assert.True(t, s.Less([]byte("p1"), []byte("p2")))
+	assert.False(t, s.Less([]byte("p2"), []byte("p1")))
+	// Peers without a score rank last
+	assert.True(t, s.Less([]byte("p0"), []byte("p3")))
+	assert.False(t, s.Less([]byte("p3"), []byte("p0")))
+	assert.True(t, s.Less([]byte("p3"), []byte("p4")))
+}
+
+func TestScoreMetadata(t *testing.T) {
+	md := EncodeScores(map[string]uint64{"A": 3, "B": 0})
+
+	score, exists := ScoreFromMetadata(md, "A")
+	assert.True(t, exists)
+	assert.Equal(t, uint64(3), score)
+	score, exists = ScoreFromMetadata(md, "B")
+	assert.True(t, exists)
+	assert.Equal(t, uint64(0), score)
+	_, exists = ScoreFromMetadata(md, "C")
+	assert.False(t, exists)
+
+	_, exists = ScoreFromMetadata(nil, "A")
+	assert.False(t, exists)
+	_, exists = ScoreFromMetadata([]byte("not json"), "A")
+	assert.False(t, exists)
+}
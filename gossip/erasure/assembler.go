@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package erasure
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type blockKey struct {
+	seqNum uint64
+	hash   string
+}
+
+type pendingBlock struct {
+	size         int
+	dataShards   int
+	parityShards int
+	shards       [][]byte
+	received     int
+}
+
+// Assembler collects the fragments of blocks until enough of them are
+// received to reconstruct the blocks
+type Assembler struct {
+	lock      sync.Mutex
+	maxBlocks int
+	pending   map[blockKey]*pendingBlock
+	order     []blockKey
+}
+
+// NewAssembler creates an Assembler which keeps the fragments of at most
+// maxBlocks blocks at a time, dropping the fragments of the oldest blocks
+// first
+func NewAssembler(maxBlocks int) *Assembler {
+	return &Assembler{
+		maxBlocks: maxBlocks,
+		pending:   make(map[blockKey]*pendingBlock),
+	}
+}
+
+// Add adds a fragment, and returns the block once enough of its fragments
+// have been added to reconstruct it
+func (a *Assembler) Add(f *Fragment) ([]byte, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	key := blockKey{seqNum: f.SeqNum, hash: string(f.BlockHash)}
+	pb, exists := a.pending[key]
+	if !exists {
+		pb = &pendingBlock{
+			size:         int(f.BlockSize),
+			dataShards:   int(f.DataShards),
+			parityShards: int(f.ParityShards),
+			shards:       make([][]byte, int(f.DataShards)+int(f.ParityShards)),
+		}
+		a.pending[key] = pb
+		a.order = append(a.order, key)
+		a.evict()
+	}
+
+	if pb.size != int(f.BlockSize) || pb.dataShards != int(f.DataShards) || pb.parityShards != int(f.ParityShards) {
+		return nil, errors.Errorf("fragment %d of block %d doesn't match the encoding of the other fragments", f.Index, f.SeqNum)
+	}
+	if pb.shards[f.Index] != nil {
+		return nil, nil
+	}
+	pb.shards[f.Index] = f.Data
+	pb.received++
+	if pb.received < pb.dataShards {
+		return nil, nil
+	}
+
+	a.remove(key)
+	block, err := Join(pb.shards, pb.dataShards, pb.parityShards, pb.size)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed reconstructing block %d", f.SeqNum)
+	}
+	hash := sha256.Sum256(block)
+	if !bytes.Equal(hash[:], f.BlockHash) {
+		return nil, errors.Errorf("reconstructed block %d doesn't match the hash of its fragments", f.SeqNum)
+	}
+	return block, nil
+}
+
+func (a *Assembler) evict() {
+	for len(a.order) > a.maxBlocks {
+		delete(a.pending, a.order[0])
+		a.order = a.order[1:]
+	}
+}
+
+func (a *Assembler) remove(key blockKey) {
+	delete(a.pending, key)
+	for i, k := range a.order {
+		if k == key {
+			a.order = append(a.order[:i], a.order[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package erasure
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFragmentSerialization(t *testing.T) {
+	fragments, err := Fragments(10, bytes.Repeat([]byte("block"), 100), 3, 2)
+	require.NoError(t, err)
+	require.Len(t, fragments, 5)
+
+	f := fragments[4]
+	f.Relay = true
+	serialized := f.Bytes()
+	assert.True(t, IsFragment(serialized))
+
+	parsed, err := ParseFragment(serialized)
+	require.NoError(t, err)
+	assert.Equal(t, f, parsed)
+
+	_, err = ParseFragment([]byte{0x0a, 1, 2})
+	assert.EqualError(t, err, "not a fragment")
+	_, err = ParseFragment(serialized[:20])
+	assert.EqualError(t, err, "fragment is too short: 20 bytes")
+
+	serialized[1] = 2
+	_, err = ParseFragment(serialized)
+	assert.EqualError(t, err, "unsupported fragment version 2")
+
+	f.Index = 5
+	_, err = ParseFragment(f.Bytes())
+	assert.EqualError(t, err, "fragment index 5 out of range")
+}
+
+func TestAssembler(t *testing.T) {
+	block := bytes.Repeat([]byte("block"), 100)
+	fragments, err := Fragments(10, block, 3, 2)
+	require.NoError(t, err)
+
+	a := NewAssembler(2)
+
+	// the block is reconstructed once 3 distinct fragments are added
+	reconstructed, err := a.Add(fragments[4])
+	require.NoError(t, err)
+	assert.Nil(t, reconstructed)
+	reconstructed, err = a.Add(fragments[4])
+	require.NoError(t, err)
+	assert.Nil(t, reconstructed)
+	reconstructed, err = a.Add(fragments[1])
+	require.NoError(t, err)
+	assert.Nil(t, reconstructed)
+	reconstructed, err = a.Add(fragments[3])
+	require.NoError(t, err)
+	assert.Equal(t, block, reconstructed)
+	assert.Empty(t, a.pending)
+
+	// fragments with a different encoding are rejected
+	_, err = a.Add(fragments[0])
+	require.NoError(t, err)
+	mismatching := *fragments[1]
+	mismatching.DataShards = 2
+	_, err = a.Add(&mismatching)
+	assert.EqualError(t, err, "fragment 1 of block 10 doesn't match the encoding of the other fragments")
+
+	// corrupted fragments are detected by the hash of the block
+	corrupted := *fragments[2]
+	corrupted.Data = bytes.Repeat([]byte{0}, len(corrupted.Data))
+	_, err = a.Add(fragments[1])
+	require.NoError(t, err)
+	_, err = a.Add(&corrupted)
+	assert.EqualError(t, err, "reconstructed block 10 doesn't match the hash of its fragments")
+
+	// the fragments of the oldest blocks are evicted
+	for seq := uint64(11); seq <= 13; seq++ {
+		fragments, err := Fragments(seq, block, 3, 2)
+		require.NoError(t, err)
+		_, err = a.Add(fragments[0])
+		require.NoError(t, err)
+	}
+	assert.Len(t, a.pending, 2)
+	assert.Equal(t, []uint64{12, 13}, []uint64{a.order[0].seqNum, a.order[1].seqNum})
+}
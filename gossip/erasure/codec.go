@@ -0,0 +1,218 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package erasure
+
+import (
+	"github.com/pkg/errors"
+)
+
+// MaxShards is the maximum number of data and parity shards of an encoding
+const MaxShards = 256
+
+// The codec is a systematic Reed-Solomon code over GF(2^8): the first shards
+// are the data split into equal parts, and each parity shard is a linear
+// combination of the data shards with the coefficients of a row of a Cauchy
+// matrix. Any square sub-matrix of the identity stacked over a Cauchy matrix
+// is invertible, therefore any dataShards shards reconstruct the data.
+
+var (
+	expTable [2 * 255]byte
+	logTable [256]byte
+	mulTable [256][256]byte
+)
+
+func init() {
+	// generate the field with the primitive polynomial x^8+x^4+x^3+x^2+1
+	x := 1
+	for i := 0; i < 255; i++ {
+		expTable[i] = byte(x)
+		expTable[i+255] = byte(x)
+		logTable[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	for a := 1; a < 256; a++ {
+		for b := 1; b < 256; b++ {
+			mulTable[a][b] = expTable[int(logTable[a])+int(logTable[b])]
+		}
+	}
+}
+
+func inv(a byte) byte {
+	return expTable[255-int(logTable[a])]
+}
+
+// coefficients returns the row of the encoding matrix of the given shard
+func coefficients(shard, dataShards int) []byte {
+	row := make([]byte, dataShards)
+	if shard < dataShards {
+		row[shard] = 1
+		return row
+	}
+	// the Cauchy matrix 1/(x_i + y_j) with x_i = i, y_j = MaxShards - 1 - j only
+	// requires the x_i and y_j to be distinct, which holds for up to MaxShards shards
+	for j := range row {
+		row[j] = inv(byte(shard-dataShards) ^ byte(MaxShards-1-j))
+	}
+	return row
+}
+
+// Split splits the data into dataShards shards of equal size, padding the last
+// one with zeros, and computes parityShards parity shards from them.
+func Split(data []byte, dataShards, parityShards int) ([][]byte, error) {
+	if err := checkShards(dataShards, parityShards); err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("no data to split")
+	}
+
+	shardSize := (len(data) + dataShards - 1) / dataShards
+	padded := make([]byte, shardSize*(dataShards+parityShards))
+	copy(padded, data)
+
+	shards := make([][]byte, dataShards+parityShards)
+	for i := range shards {
+		shards[i] = padded[i*shardSize : (i+1)*shardSize]
+	}
+	for i := dataShards; i < len(shards); i++ {
+		encodeShard(shards[i], coefficients(i, dataShards), shards[:dataShards])
+	}
+	return shards, nil
+}
+
+// Join reconstructs the data of the given size from the shards produced by Split.
+// Missing shards are nil, and at least dataShards shards must be present.
+func Join(shards [][]byte, dataShards, parityShards, size int) ([]byte, error) {
+	if err := checkShards(dataShards, parityShards); err != nil {
+		return nil, err
+	}
+	if len(shards) != dataShards+parityShards {
+		return nil, errors.Errorf("expected %d shards, got %d", dataShards+parityShards, len(shards))
+	}
+
+	var present []int
+	shardSize := -1
+	for i, shard := range shards {
+		if shard == nil {
+			continue
+		}
+		if shardSize == -1 {
+			shardSize = len(shard)
+		}
+		if len(shard) != shardSize {
+			return nil, errors.Errorf("shard %d has size %d, expected %d", i, len(shard), shardSize)
+		}
+		present = append(present, i)
+	}
+	if len(present) < dataShards {
+		return nil, errors.Errorf("not enough shards to reconstruct the data, need %d but have %d", dataShards, len(present))
+	}
+	if size > shardSize*dataShards {
+		return nil, errors.Errorf("data size %d exceeds the size of the shards", size)
+	}
+	present = present[:dataShards]
+
+	data := make([]byte, shardSize*dataShards)
+	dataShard := func(i int) []byte { return data[i*shardSize : (i+1)*shardSize] }
+
+	// take the shortcut if all the data shards are present
+	if present[dataShards-1] == dataShards-1 {
+		for i := 0; i < dataShards; i++ {
+			copy(dataShard(i), shards[i])
+		}
+		return data[:size], nil
+	}
+
+	matrix := make([][]byte, dataShards)
+	inputs := make([][]byte, dataShards)
+	for i, shard := range present {
+		matrix[i] = coefficients(shard, dataShards)
+		inputs[i] = shards[shard]
+	}
+	decoding, err := invert(matrix)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < dataShards; i++ {
+		encodeShard(dataShard(i), decoding[i], inputs)
+	}
+	return data[:size], nil
+}
+
+func checkShards(dataShards, parityShards int) error {
+	if dataShards <= 0 || parityShards < 0 {
+		return errors.Errorf("invalid number of shards: %d data shards, %d parity shards", dataShards, parityShards)
+	}
+	if dataShards+parityShards > MaxShards {
+		return errors.Errorf("too many shards: %d, maximum is %d", dataShards+parityShards, MaxShards)
+	}
+	return nil
+}
+
+// encodeShard sets out to the linear combination of the inputs with the coefficients
+func encodeShard(out []byte, coefficients []byte, inputs [][]byte) {
+	for i := range out {
+		out[i] = 0
+	}
+	for j, c := range coefficients {
+		if c == 0 {
+			continue
+		}
+		table := &mulTable[c]
+		for i, b := range inputs[j] {
+			out[i] ^= table[b]
+		}
+	}
+}
+
+// invert returns the inverse of a square matrix by Gauss-Jordan elimination
+func invert(matrix [][]byte) ([][]byte, error) {
+	n := len(matrix)
+	work := make([][]byte, n)
+	for i := range matrix {
+		work[i] = make([]byte, 2*n)
+		copy(work[i], matrix[i])
+		work[i][n+i] = 1
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := -1
+		for row := col; row < n; row++ {
+			if work[row][col] != 0 {
+				pivot = row
+				break
+			}
+		}
+		if pivot == -1 {
+			return nil, errors.New("matrix is singular")
+		}
+		work[col], work[pivot] = work[pivot], work[col]
+
+		scale := &mulTable[inv(work[col][col])]
+		for k := range work[col] {
+			work[col][k] = scale[work[col][k]]
+		}
+		for row := 0; row < n; row++ {
+			if row == col || work[row][col] == 0 {
+				continue
+			}
+			factor := &mulTable[work[row][col]]
+			for k := range work[row] {
+				work[row][k] ^= factor[work[col][k]]
+			}
+		}
+	}
+
+	inverse := make([][]byte, n)
+	for i := range work {
+		inverse[i] = work[i][n:]
+	}
+	return inverse, nil
+}
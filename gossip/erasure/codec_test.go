@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package erasure
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitJoin(t *testing.T) {
+	data := make([]byte, 10001)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		dataShards   int
+		parityShards int
+		missing      []int
+	}{
+		{"no parity", 4, 0, nil},
+		{"all shards", 4, 2, nil},
+		{"missing data shards", 4, 2, []int{0, 3}},
+		{"missing parity shards", 4, 2, []int{4, 5}},
+		{"missing data and parity shards", 5, 3, []int{1, 2, 6}},
+		{"single data shard", 1, 3, []int{0, 1, 2}},
+		{"maximum shards", 200, 56, []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100, 110}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shards, err := Split(data, tt.dataShards, tt.parityShards)
+			require.NoError(t, err)
+			require.Len(t, shards, tt.dataShards+tt.parityShards)
+			for _, i := range tt.missing {
+				shards[i] = nil
+			}
+
+			joined, err := Join(shards, tt.dataShards, tt.parityShards, len(data))
+			require.NoError(t, err)
+			assert.Equal(t, data, joined)
+		})
+	}
+}
+
+func TestSplitJoinErrors(t *testing.T) {
+	_, err := Split([]byte{1, 2, 3}, 0, 1)
+	assert.EqualError(t, err, "invalid number of shards: 0 data shards, 1 parity shards")
+
+	_, err = Split([]byte{1, 2, 3}, 200, 57)
+	assert.EqualError(t, err, "too many shards: 257, maximum is 256")
+
+	_, err = Split(nil, 2, 1)
+	assert.EqualError(t, err, "no data to split")
+
+	shards, err := Split([]byte{1, 2, 3, 4, 5}, 2, 1)
+	require.NoError(t, err)
+
+	_, err = Join(shards[:2], 2, 1, 5)
+	assert.EqualError(t, err, "expected 3 shards, got 2")
+
+	_, err = Join([][]byte{shards[0], nil, nil}, 2, 1, 5)
+	assert.EqualError(t, err, "not enough shards to reconstruct the data, need 2 but have 1")
+
+	_, err = Join([][]byte{shards[0], shards[1][:1], nil}, 2, 1, 5)
+	assert.EqualError(t, err, "shard 1 has size 1, expected 3")
+
+	_, err = Join(shards, 2, 1, 7)
+	assert.EqualError(t, err, "data size 7 exceeds the size of the shards")
+}
@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package erasure
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// fragmentMarker starts the serialization of a fragment. A serialized block
+	// never starts with it, as 0 isn't a valid protobuf field tag.
+	fragmentMarker  = byte(0)
+	fragmentVersion = byte(1)
+
+	flagRelay = byte(1)
+
+	// marker, version, flags, sequence, block size, data shards, parity shards, index, block hash
+	fragmentHeaderSize = 1 + 1 + 1 + 8 + 4 + 2 + 2 + 2 + sha256.Size
+)
+
+// Fragment is an erasure coded shard of a block
+type Fragment struct {
+	SeqNum       uint64
+	BlockHash    []byte
+	BlockSize    uint32
+	DataShards   uint16
+	ParityShards uint16
+	Index        uint16
+	// Relay indicates that the receiver of the fragment should forward
+	// it to the other peers the block is disseminated to
+	Relay bool
+	Data  []byte
+}
+
+// Fragments splits a block into erasure coded fragments, so that any
+// dataShards of the dataShards+parityShards fragments reconstruct it
+func Fragments(seqNum uint64, block []byte, dataShards, parityShards int) ([]*Fragment, error) {
+	shards, err := Split(block, dataShards, parityShards)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(block)
+	fragments := make([]*Fragment, len(shards))
+	for i, shard := range shards {
+		fragments[i] = &Fragment{
+			SeqNum:       seqNum,
+			BlockHash:    hash[:],
+			BlockSize:    uint32(len(block)),
+			DataShards:   uint16(dataShards),
+			ParityShards: uint16(parityShards),
+			Index:        uint16(i),
+			Data:         shard,
+		}
+	}
+	return fragments, nil
+}
+
+// IsFragment returns whether the data of a block message is a serialized fragment
+func IsFragment(data []byte) bool {
+	return len(data) > 0 && data[0] == fragmentMarker
+}
+
+// Bytes serializes the fragment
+func (f *Fragment) Bytes() []byte {
+	buf := make([]byte, fragmentHeaderSize, fragmentHeaderSize+len(f.Data))
+	buf[0] = fragmentMarker
+	buf[1] = fragmentVersion
+	if f.Relay {
+		buf[2] = flagRelay
+	}
+	binary.BigEndian.PutUint64(buf[3:], f.SeqNum)
+	binary.BigEndian.PutUint32(buf[11:], f.BlockSize)
+	binary.BigEndian.PutUint16(buf[15:], f.DataShards)
+	binary.BigEndian.PutUint16(buf[17:], f.ParityShards)
+	binary.BigEndian.PutUint16(buf[19:], f.Index)
+	copy(buf[21:], f.BlockHash)
+	return append(buf, f.Data...)
+}
+
+// ParseFragment deserializes a fragment serialized by Bytes
+func ParseFragment(data []byte) (*Fragment, error) {
+	if !IsFragment(data) {
+		return nil, errors.New("not a fragment")
+	}
+	if len(data) < fragmentHeaderSize {
+		return nil, errors.Errorf("fragment is too short: %d bytes", len(data))
+	}
+	if data[1] != fragmentVersion {
+		return nil, errors.Errorf("unsupported fragment version %d", data[1])
+	}
+	f := &Fragment{
+		Relay:        data[2]&flagRelay != 0,
+		SeqNum:       binary.BigEndian.Uint64(data[3:]),
+		BlockSize:    binary.BigEndian.Uint32(data[11:]),
+		DataShards:   binary.BigEndian.Uint16(data[15:]),
+		ParityShards: binary.BigEndian.Uint16(data[17:]),
+		Index:        binary.BigEndian.Uint16(data[19:]),
+		BlockHash:    data[21:fragmentHeaderSize],
+		Data:         data[fragmentHeaderSize:],
+	}
+	if err := checkShards(int(f.DataShards), int(f.ParityShards)); err != nil {
+		return nil, err
+	}
+	if int(f.Index) >= int(f.DataShards)+int(f.ParityShards) {
+		return nil, errors.Errorf("fragment index %d out of range", f.Index)
+	}
+	return f, nil
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/osdi23p228/fabric/gossip/common"
 	"github.com/osdi23p228/fabric/gossip/discovery"
 	"github.com/osdi23p228/fabric/gossip/election"
+	"github.com/osdi23p228/fabric/gossip/erasure"
 	"github.com/osdi23p228/fabric/gossip/filter"
 	"github.com/osdi23p228/fabric/gossip/gossip/algo"
 	"github.com/osdi23p228/fabric/gossip/gossip/msgstore"
@@ -50,6 +51,11 @@ type Config struct {
 	RequestWaitTime             time.Duration
 	ResponseWaitTime            time.Duration
 	MsgExpirationTimeout        time.Duration
+	// ErasureCoding enables disseminating blocks of at least ErasureCodingMinBlockSize
+	// bytes as erasure coded fragments, ErasureCodingParityShards of which may be lost
+	ErasureCoding             bool
+	ErasureCodingMinBlockSize int
+	ErasureCodingParityShards int
 }
 
 // GossipChannel defines an object that deals with all channel-related messages
@@ -88,6 +94,12 @@ type GossipChannel interface {
 	// AddToMsgStore adds a given GossipMessage to the message store
 	AddToMsgStore(msg *protoext.SignedGossipMessage)
 
+	// DisseminateFragments splits the block of the given message into erasure coded
+	// fragments, and sends a fragment to each peer of the organization in the channel,
+	// which relays it to the other peers. Returns false if the block isn't disseminated
+	// as fragments and should be gossiped as is.
+	DisseminateFragments(msg *protoext.SignedGossipMessage) bool
+
 	// ConfigureChannel (re)configures the list of organizations
 	// that are eligible to be in the channel
 	ConfigureChannel(joinMsg api.JoinChannelMessage)
@@ -162,6 +174,7 @@ type gossipChannel struct {
 	incTime                   uint64
 	leftChannel               int32
 	membershipTracker         *membershipTracker
+	fragmentAssembler         *erasure.Assembler
 }
 
 type membershipFilter struct {
@@ -209,6 +222,7 @@ func NewGossipChannel(pkiID common.PKIidType, org api.OrgIdentityType, mcs api.M
 	}
 
 	gc.memFilter = &membershipFilter{adapter: gc.Adapter, gossipChannel: gc}
+	gc.fragmentAssembler = erasure.NewAssembler(adapter.GetConf().MaxBlockCountToStore + 1)
 
 	comparator := protoext.NewGossipMessageComparator(adapter.GetConf().MaxBlockCountToStore)
 
@@ -637,6 +651,10 @@ func (gc *gossipChannel) HandleMessage(msg protoext.ReceivedMessage) {
 				gc.logger.Warning("Payload is empty, got it from", msg.GetConnectionInfo().ID)
 				return
 			}
+			if erasure.IsFragment(m.GetDataMsg().Payload.Data) {
+				gc.handleFragment(msg)
+				return
+			}
 			// Would this block go into the message store if it was verified?
 			if !gc.blockMsgStore.CheckValid(msg.GetGossipMessage()) {
 				return
@@ -750,6 +768,136 @@ func (gc *gossipChannel) HandleMessage(msg protoext.ReceivedMessage) {
 	}
 }
 
+// DisseminateFragments splits the block of the given message into erasure coded
+// fragments, and sends a fragment to each peer of the organization in the channel
+func (gc *gossipChannel) DisseminateFragments(msg *protoext.SignedGossipMessage) bool {
+	conf := gc.GetConf()
+	payload := msg.GetDataMsg().GetPayload()
+	if !conf.ErasureCoding || payload == nil || len(payload.Data) < conf.ErasureCodingMinBlockSize {
+		return false
+	}
+
+	peers := gc.memFilter.GetMembership()
+	if len(peers) > erasure.MaxShards {
+		peers = peers[:erasure.MaxShards]
+	}
+	// Splitting the block into less than 2 data shards doesn't
+	// reduce the amount of data sent by this peer
+	dataShards := len(peers) - conf.ErasureCodingParityShards
+	if dataShards < 2 {
+		return false
+	}
+
+	fragments, err := erasure.Fragments(payload.SeqNum, payload.Data, dataShards, conf.ErasureCodingParityShards)
+	if err != nil {
+		gc.logger.Warningf("Failed splitting block %d into fragments: %+v", payload.SeqNum, err)
+		return false
+	}
+	for i, f := range fragments {
+		f.Relay = true
+		fragmentMsg, err := gc.createFragmentMsg(f)
+		if err != nil {
+			gc.logger.Warningf("Failed creating fragment %d of block %d: %+v", i, payload.SeqNum, err)
+			return false
+		}
+		gc.Send(fragmentMsg, &comm.RemotePeer{Endpoint: peers[i].PreferredEndpoint(), PKIID: peers[i].PKIid})
+	}
+	gc.logger.Debugf("Disseminated block %d as %d data and %d parity fragments", payload.SeqNum, dataShards, conf.ErasureCodingParityShards)
+	return true
+}
+
+func (gc *gossipChannel) createFragmentMsg(f *erasure.Fragment) (*protoext.SignedGossipMessage, error) {
+	return gc.createDataMsg(f.SeqNum, f.Bytes())
+}
+
+func (gc *gossipChannel) createDataMsg(seqNum uint64, data []byte) (*protoext.SignedGossipMessage, error) {
+	return protoext.NoopSign(&proto.GossipMessage{
+		Channel: []byte(gc.chainID),
+		Nonce:   0,
+		Tag:     proto.GossipMessage_CHAN_AND_ORG,
+		Content: &proto.GossipMessage_DataMsg{
+			DataMsg: &proto.DataMessage{
+				Payload: &proto.Payload{
+					SeqNum: seqNum,
+					Data:   data,
+				},
+			},
+		},
+	})
+}
+
+// handleFragment relays a fragment of a block to the other peers of the organization
+// if needed, and reconstructs the block once enough of its fragments are received
+func (gc *gossipChannel) handleFragment(msg protoext.ReceivedMessage) {
+	sender := msg.GetConnectionInfo().ID
+	if !gc.eligibleForChannelAndSameOrg(discovery.NetworkMember{PKIid: sender}) {
+		gc.logger.Warning(msg.GetConnectionInfo(), "sent a block fragment but isn't eligible for receiving blocks of", string(gc.chainID))
+		return
+	}
+	payload := msg.GetGossipMessage().GetDataMsg().Payload
+	f, err := erasure.ParseFragment(payload.Data)
+	if err != nil {
+		gc.logger.Warningf("Received invalid block fragment from %v: %+v", sender, err)
+		return
+	}
+	if f.SeqNum != payload.SeqNum {
+		gc.logger.Warning("Received fragment of block", f.SeqNum, "in a message of block", payload.SeqNum, "from", sender)
+		return
+	}
+	// Would this block go into the message store if it was reconstructed?
+	if !gc.blockMsgStore.CheckValid(msg.GetGossipMessage()) {
+		return
+	}
+
+	if f.Relay {
+		f.Relay = false
+		relayed, err := gc.createFragmentMsg(f)
+		if err != nil {
+			gc.logger.Warningf("Failed relaying fragment %d of block %d: %+v", f.Index, f.SeqNum, err)
+			return
+		}
+		var peers []*comm.RemotePeer
+		for _, peer := range gc.memFilter.GetMembership() {
+			if bytes.Equal(peer.PKIid, sender) {
+				continue
+			}
+			peers = append(peers, &comm.RemotePeer{Endpoint: peer.PreferredEndpoint(), PKIID: peer.PKIid})
+		}
+		gc.Send(relayed, peers...)
+	}
+
+	rawBlock, err := gc.fragmentAssembler.Add(f)
+	if err != nil {
+		gc.logger.Warningf("Failed reconstructing block %d from fragments: %+v", f.SeqNum, err)
+		return
+	}
+	if rawBlock == nil {
+		return
+	}
+
+	blockMsg, err := gc.createDataMsg(f.SeqNum, rawBlock)
+	if err != nil {
+		gc.logger.Warningf("Failed creating message of block %d: %+v", f.SeqNum, err)
+		return
+	}
+	if !gc.verifyBlock(blockMsg.GossipMessage, sender) {
+		gc.logger.Warning("Failed verifying block", f.SeqNum, "reconstructed from fragments")
+		return
+	}
+	gc.Lock()
+	added := gc.blockMsgStore.Add(blockMsg)
+	if added {
+		gc.logger.Debugf("Adding %v to the block puller", blockMsg)
+		gc.blocksPuller.Add(blockMsg)
+	}
+	gc.Unlock()
+
+	// The block isn't forwarded, as the other peers reconstruct it from the fragments
+	if added {
+		gc.DeMultiplex(blockMsg)
+	}
+}
+
 func (gc *gossipChannel) handleStateInfSnapshot(m *proto.GossipMessage, sender common.PKIidType) {
 	chanName := string(gc.chainID)
 	for _, envelope := range m.GetStateSnapshot().Elements {
@@ -26,12 +26,15 @@ import (
 	"github.com/osdi23p228/fabric/gossip/comm"
 	"github.com/osdi23p228/fabric/gossip/common"
 	"github.com/osdi23p228/fabric/gossip/discovery"
+	"github.com/osdi23p228/fabric/gossip/erasure"
 	"github.com/osdi23p228/fabric/gossip/metrics"
 	"github.com/osdi23p228/fabric/gossip/metrics/mocks"
 	"github.com/osdi23p228/fabric/gossip/protoext"
 	"github.com/osdi23p228/fabric/gossip/util"
+	"github.com/osdi23p228/fabric/protoutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -1763,6 +1766,131 @@ func TestChannelGetPeers(t *testing.T) {
 	assert.Len(t, gc.GetPeers(), 0)
 }
 
+func TestChannelErasureCoding(t *testing.T) {
+	// Scenario: A peer disseminates a block as erasure coded fragments to the 3 other
+	// peers of its organization. A peer that receives a fragment relays it to the other
+	// peers, and reconstructs the block once it has 2 fragments of it.
+	leader := common.PKIidType("leader")
+	p1 := common.PKIidType("p1")
+	p2 := common.PKIidType("p2")
+	p3 := common.PKIidType("p3")
+
+	conf := conf
+	conf.ErasureCoding = true
+	conf.ErasureCodingMinBlockSize = 1000
+	conf.ErasureCodingParityShards = 1
+
+	type sentMsg struct {
+		msg   *protoext.SignedGossipMessage
+		peers []*comm.RemotePeer
+	}
+
+	newChannel := func(self common.PKIidType, conf Config, members ...common.PKIidType) (GossipChannel, chan sentMsg, chan *protoext.SignedGossipMessage) {
+		sent := make(chan sentMsg, 10)
+		delivered := make(chan *protoext.SignedGossipMessage, 10)
+		cs := &cryptoService{}
+		cs.On("VerifyBlock", mock.Anything).Return(nil)
+		adapter := new(gossipAdapterMock)
+		adapter.On("GetConf").Return(conf)
+		var networkMembers []discovery.NetworkMember
+		for _, member := range members {
+			adapter.On("GetOrgOfPeer", member).Return(orgInChannelA)
+			networkMembers = append(networkMembers, discovery.NetworkMember{PKIid: member, Endpoint: string(member)})
+		}
+		configureAdapter(adapter, networkMembers...)
+		adapter.On("Gossip", mock.Anything)
+		adapter.On("Forward", mock.Anything)
+		adapter.On("Send", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			sent <- sentMsg{msg: args.Get(0).(*protoext.SignedGossipMessage), peers: args.Get(1).([]*comm.RemotePeer)}
+		})
+		adapter.On("DeMultiplex", mock.Anything).Run(func(args mock.Arguments) {
+			delivered <- args.Get(0).(*protoext.SignedGossipMessage)
+		})
+		gc := NewGossipChannel(self, orgInChannelA, cs, channelA, adapter, &joinChanMsg{}, disabledMetrics, nil)
+		for _, member := range members {
+			gc.HandleMessage(&receivedMsg{PKIID: member, msg: createStateInfoMsg(1, member, channelA)})
+			<-delivered
+		}
+		return gc, sent, delivered
+	}
+
+	block := protoutil.MarshalOrPanic(&cb.Block{
+		Header: &cb.BlockHeader{Number: 5},
+		Data:   &cb.BlockData{Data: [][]byte{bytes.Repeat([]byte{1, 2, 3}, 1000)}},
+	})
+	blockMsg := createDataMsg(5, channelA)
+	blockMsg.GetDataMsg().Payload.Data = block
+
+	// Small blocks, and blocks disseminated to too few peers, are gossiped as is
+	gc, _, _ := newChannel(leader, conf, p1, p2, p3)
+	assert.False(t, gc.DisseminateFragments(createDataMsg(5, channelA)))
+	gc.Stop()
+	gc, _, _ = newChannel(leader, conf, p1, p2)
+	assert.False(t, gc.DisseminateFragments(blockMsg))
+	gc.Stop()
+	disabledConf := conf
+	disabledConf.ErasureCoding = false
+	gc, _, _ = newChannel(leader, disabledConf, p1, p2, p3)
+	assert.False(t, gc.DisseminateFragments(blockMsg))
+	gc.Stop()
+
+	// The leader sends a different fragment to each peer
+	gc, sent, _ := newChannel(leader, conf, p1, p2, p3)
+	defer gc.Stop()
+	assert.True(t, gc.DisseminateFragments(blockMsg))
+	require.Len(t, sent, 3)
+	fragmentMsgs := map[string]*protoext.SignedGossipMessage{}
+	for i := 0; i < 3; i++ {
+		s := <-sent
+		require.Len(t, s.peers, 1)
+		f, err := erasure.ParseFragment(s.msg.GetDataMsg().Payload.Data)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5), f.SeqNum)
+		assert.Equal(t, uint16(i), f.Index)
+		assert.Equal(t, uint16(2), f.DataShards)
+		assert.Equal(t, uint16(1), f.ParityShards)
+		assert.True(t, f.Relay)
+		fragmentMsgs[string(s.peers[0].PKIID)] = s.msg
+	}
+	require.Len(t, fragmentMsgs, 3)
+
+	// p1 relays the fragment it got from the leader to p2 and p3
+	gc1, sent1, delivered1 := newChannel(p1, conf, leader, p2, p3)
+	defer gc1.Stop()
+	gc1.HandleMessage(&receivedMsg{PKIID: leader, msg: fragmentMsgs[string(p1)]})
+	require.Len(t, sent1, 1)
+	relayed := <-sent1
+	assert.Equal(t, []*comm.RemotePeer{{Endpoint: "p2", PKIID: p2}, {Endpoint: "p3", PKIID: p3}}, relayed.peers)
+	f, err := erasure.ParseFragment(relayed.msg.GetDataMsg().Payload.Data)
+	require.NoError(t, err)
+	assert.False(t, f.Relay)
+	assert.Len(t, delivered1, 0)
+
+	// p1 doesn't relay the fragment relayed by p3, and reconstructs the block from it
+	relayedByP3 := fragmentMsgs[string(p3)]
+	f, err = erasure.ParseFragment(relayedByP3.GetDataMsg().Payload.Data)
+	require.NoError(t, err)
+	f.Relay = false
+	relayedByP3 = createDataMsg(5, channelA)
+	relayedByP3.GetDataMsg().Payload.Data = f.Bytes()
+	gc1.HandleMessage(&receivedMsg{PKIID: p3, msg: relayedByP3})
+	assert.Len(t, sent1, 0)
+	require.Len(t, delivered1, 1)
+	assert.Equal(t, block, (<-delivered1).GetDataMsg().Payload.Data)
+
+	// Fragments of a block that was already reconstructed are ignored
+	gc1.HandleMessage(&receivedMsg{PKIID: p2, msg: fragmentMsgs[string(p2)]})
+	assert.Len(t, sent1, 0)
+	assert.Len(t, delivered1, 0)
+
+	// Fragments from peers of other organizations are ignored
+	gc2, sent2, delivered2 := newChannel(p2, conf, leader, p1, p3)
+	defer gc2.Stop()
+	gc2.HandleMessage(&receivedMsg{PKIID: pkiIDinOrg2, msg: fragmentMsgs[string(p2)]})
+	assert.Len(t, sent2, 0)
+	assert.Len(t, delivered2, 0)
+}
+
 func TestOnDemandGossip(t *testing.T) {
 	// Scenario: update the metadata and ensure only 1 dissemination
 	// takes place when membership is not empty
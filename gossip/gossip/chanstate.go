@@ -132,6 +132,9 @@ func (ga *gossipAdapterImpl) GetConf() channel.Config {
 		RequestWaitTime:             ga.conf.RequestWaitTime,
 		ResponseWaitTime:            ga.conf.ResponseWaitTime,
 		MsgExpirationTimeout:        ga.conf.MsgExpirationTimeout,
+		ErasureCoding:               ga.conf.ErasureCoding,
+		ErasureCodingMinBlockSize:   ga.conf.ErasureCodingMinBlockSize,
+		ErasureCodingParityShards:   ga.conf.ErasureCodingParityShards,
 	}
 }
 
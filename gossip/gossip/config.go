@@ -49,6 +49,13 @@ type Config struct {
 	// SkipBlockVerification controls either we skip verifying block message or not.
 	SkipBlockVerification bool
 
+	// ErasureCoding enables disseminating blocks as erasure coded fragments.
+	ErasureCoding bool
+	// ErasureCodingMinBlockSize is the minimal size of a block disseminated as fragments.
+	ErasureCodingMinBlockSize int
+	// ErasureCodingParityShards is the number of parity fragments of a block.
+	ErasureCodingParityShards int
+
 	// PublishCertPeriod is the time from startup certificates are included in Alive message.
 	PublishCertPeriod time.Duration
 	// PublishStateInfoInterval determines frequency of pushing state info messages to peers.
@@ -81,6 +88,8 @@ type Config struct {
 	RecvBuffSize int
 	// SendBuffSize is the buffer size of sending message.
 	SendBuffSize int
+	// Compression enables the compression of messages on connections with peers which support it.
+	Compression bool
 
 	// MsgExpirationTimeout indicate leadership message expiration timeout.
 	MsgExpirationTimeout time.Duration
@@ -132,6 +141,9 @@ func (c *Config) loadConfig(endpoint string, certs *common.TLSCertificates, boot
 	c.RequestStateInfoInterval = util.GetDurationOrDefault("peer.gossip.requestStateInfoInterval", 4*time.Second)
 	c.PublishStateInfoInterval = util.GetDurationOrDefault("peer.gossip.publishStateInfoInterval", 4*time.Second)
 	c.SkipBlockVerification = viper.GetBool("peer.gossip.skipBlockVerification")
+	c.ErasureCoding = viper.GetBool("peer.gossip.erasureCoding.enabled")
+	c.ErasureCodingMinBlockSize = util.GetIntOrDefault("peer.gossip.erasureCoding.minBlockSize", 1024*1024)
+	c.ErasureCodingParityShards = util.GetIntOrDefault("peer.gossip.erasureCoding.parityShards", 1)
 	c.TLSCerts = certs
 	c.TimeForMembershipTracker = util.GetDurationOrDefault("peer.gossip.membershipTrackerInterval", 5*time.Second)
 	c.DigestWaitTime = util.GetDurationOrDefault("peer.gossip.digestWaitTime", algo.DefDigestWaitTime)
@@ -141,6 +153,7 @@ func (c *Config) loadConfig(endpoint string, certs *common.TLSCertificates, boot
 	c.ConnTimeout = util.GetDurationOrDefault("peer.gossip.connTimeout", comm.DefConnTimeout)
 	c.RecvBuffSize = util.GetIntOrDefault("peer.gossip.recvBuffSize", comm.DefRecvBuffSize)
 	c.SendBuffSize = util.GetIntOrDefault("peer.gossip.sendBuffSize", comm.DefSendBuffSize)
+	c.Compression = viper.GetBool("peer.gossip.compression")
 	c.MsgExpirationTimeout = util.GetDurationOrDefault("peer.gossip.election.leaderAliveThreshold", election.DefLeaderAliveThreshold) * 10
 	c.AliveTimeInterval = util.GetDurationOrDefault("peer.gossip.aliveTimeInterval", discovery.DefAliveTimeInterval)
 	c.AliveExpirationTimeout = util.GetDurationOrDefault("peer.gossip.aliveExpirationTimeout", 5*c.AliveTimeInterval)
@@ -43,6 +43,9 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("peer.gossip.requestStateInfoInterval", "9s")
 	viper.Set("peer.gossip.publishStateInfoInterval", "10s")
 	viper.Set("peer.gossip.skipBlockVerification", true)
+	viper.Set("peer.gossip.erasureCoding.enabled", true)
+	viper.Set("peer.gossip.erasureCoding.minBlockSize", 23)
+	viper.Set("peer.gossip.erasureCoding.parityShards", 24)
 	viper.Set("peer.gossip.membershipTrackerInterval", "11s")
 	viper.Set("peer.gossip.digestWaitTime", "12s")
 	viper.Set("peer.gossip.requestWaitTime", "13s")
@@ -51,6 +54,7 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("peer.gossip.connTimeout", "16s")
 	viper.Set("peer.gossip.recvBuffSize", 17)
 	viper.Set("peer.gossip.sendBuffSize", 18)
+	viper.Set("peer.gossip.compression", true)
 	viper.Set("peer.gossip.election.leaderAliveThreshold", "19s")
 	viper.Set("peer.gossip.aliveTimeInterval", "20s")
 	viper.Set("peer.gossip.aliveExpirationTimeout", "21s")
@@ -83,6 +87,9 @@ func TestGlobalConfig(t *testing.T) {
 		RequestStateInfoInterval:     9 * time.Second,
 		PublishStateInfoInterval:     10 * time.Second,
 		SkipBlockVerification:        true,
+		ErasureCoding:                true,
+		ErasureCodingMinBlockSize:    23,
+		ErasureCodingParityShards:    24,
 		TLSCerts:                     nil,
 		TimeForMembershipTracker:     11 * time.Second,
 		DigestWaitTime:               12 * time.Second,
@@ -92,6 +99,7 @@ func TestGlobalConfig(t *testing.T) {
 		ConnTimeout:                  16 * time.Second,
 		RecvBuffSize:                 17,
 		SendBuffSize:                 18,
+		Compression:                  true,
 		MsgExpirationTimeout:         19 * time.Second * 10, // LeaderAliveThreshold * 10
 		AliveTimeInterval:            20 * time.Second,
 		AliveExpirationTimeout:       21 * time.Second,
@@ -138,6 +146,9 @@ func TestGlobalConfigDefaults(t *testing.T) {
 		RequestStateInfoInterval:     4 * time.Second,
 		PublishStateInfoInterval:     4 * time.Second,
 		SkipBlockVerification:        false,
+		ErasureCoding:                false,
+		ErasureCodingMinBlockSize:    1024 * 1024,
+		ErasureCodingParityShards:    1,
 		TLSCerts:                     nil,
 		TimeForMembershipTracker:     5 * time.Second,
 		DigestWaitTime:               algo.DefDigestWaitTime,
@@ -147,6 +158,7 @@ func TestGlobalConfigDefaults(t *testing.T) {
 		ConnTimeout:                  comm.DefConnTimeout,
 		RecvBuffSize:                 comm.DefRecvBuffSize,
 		SendBuffSize:                 comm.DefSendBuffSize,
+		Compression:                  false,
 		MsgExpirationTimeout:         election.DefLeaderAliveThreshold * 10,
 		AliveTimeInterval:            discovery.DefAliveTimeInterval,
 		AliveExpirationTimeout:       5 * discovery.DefAliveTimeInterval,
@@ -95,6 +95,7 @@ func New(conf *Config, s *grpc.Server, sa api.SecurityAdvisor,
 	}
 	g.stateInfoMsgStore = g.newStateInfoMsgStore()
 
+	g.chanState = newChannelState(g)
 	g.idMapper = identity.NewIdentityMapper(mcs, selfIdentity, func(pkiID common.PKIidType, identity api.PeerIdentityType) {
 		g.comm.CloseConn(&comm.RemotePeer{PKIID: pkiID})
 		g.certPuller.Remove(string(pkiID))
@@ -105,6 +106,10 @@ func New(conf *Config, s *grpc.Server, sa api.SecurityAdvisor,
 		ConnTimeout:  conf.ConnTimeout,
 		RecvBuffSize: conf.RecvBuffSize,
 		SendBuffSize: conf.SendBuffSize,
+		Compression:  conf.Compression,
+		JoinedChannel: func(channel common.ChannelID) bool {
+			return g.chanState.getGossipChannelByChainID(channel) != nil
+		},
 	}
 	g.comm, err = comm.NewCommInstance(s, conf.TLSCerts, g.idMapper, selfIdentity, secureDialOpts, sa,
 		gossipMetrics.CommMetrics, commConfig)
@@ -114,7 +119,6 @@ func New(conf *Config, s *grpc.Server, sa api.SecurityAdvisor,
 		return nil
 	}
 
-	g.chanState = newChannelState(g)
 	g.emitter = newBatchingEmitter(conf.PropagateIterations,
 		conf.MaxPropagationBurstSize, conf.MaxPropagationBurstLatency,
 		g.sendGossipBatch)
@@ -209,9 +213,14 @@ func (g *Node) LeaveChan(channelID common.ChannelID) {
 }
 
 // SuspectPeers makes the gossip instance validate identities of suspected peers, and close
-// any connections to peers with identities that are found invalid
+// any connections to peers with identities that are found invalid.
+// Peers with revoked identities are removed from the membership view right away,
+// instead of when their alive messages expire.
 func (g *Node) SuspectPeers(isSuspected api.PeerSuspector) {
-	g.certStore.suspectPeers(isSuspected)
+	for _, pkiID := range g.certStore.suspectPeers(isSuspected) {
+		g.logger.Warningf("Identity of %s was revoked, removing it from membership", pkiID)
+		g.disc.Purge(pkiID)
+	}
 }
 
 func (g *Node) learnAnchorPeers(channel string, orgOfAnchorPeers api.OrgIdentityType, anchorPeers []api.AnchorPeer) {
@@ -682,6 +691,10 @@ func (g *Node) Gossip(msg *pg.GossipMessage) {
 		}
 		if protoext.IsDataMsg(msg) {
 			gc.AddToMsgStore(sMsg)
+			// Blocks disseminated as fragments aren't gossiped as is
+			if gc.DisseminateFragments(sMsg) {
+				return
+			}
 		}
 	}
 
@@ -711,6 +724,11 @@ func (g *Node) Peers() []discovery.NetworkMember {
 	return g.disc.GetMembership()
 }
 
+// DeadPeers returns the NetworkMembers presumed dead
+func (g *Node) DeadPeers() []discovery.NetworkMember {
+	return g.disc.GetDeadMembership()
+}
+
 // PeersOfChannel returns the NetworkMembers considered alive
 // and also subscribed to the channel given
 func (g *Node) PeersOfChannel(channel common.ChannelID) []discovery.NetworkMember {
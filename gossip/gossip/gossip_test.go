@@ -1472,6 +1472,51 @@ func TestIdentityExpiration(t *testing.T) {
 	g5.Stop()
 }
 
+func TestRevokedPeerPurgedFromMembership(t *testing.T) {
+	// Scenario: spawn 3 peers, and make the first one find the identity of the last one revoked.
+	// The revoked peer should be removed from the membership view of the first peer right away,
+	// rather than when its alive messages expire, and should not be learned about again.
+	var expirationTimesLock sync.RWMutex
+	expirationTimes := map[string]time.Time{}
+
+	port1, grpc1, certs1, secDialOpts1, _ := util.CreateGRPCLayer()
+	g1 := newGossipInstanceWithExpiration(expirationTimes, &expirationTimesLock, 1, port1, grpc1, certs1, secDialOpts1, 100)
+	port2, grpc2, certs2, secDialOpts2, _ := util.CreateGRPCLayer()
+	g2 := newGossipInstanceWithExpiration(expirationTimes, &expirationTimesLock, 2, port2, grpc2, certs2, secDialOpts2, 100, port1)
+	port3, grpc3, certs3, secDialOpts3, _ := util.CreateGRPCLayer()
+	g3 := newGossipInstanceWithExpiration(expirationTimes, &expirationTimesLock, 3, port3, grpc3, certs3, secDialOpts3, 100, port1)
+	peers := []*gossipGRPC{g1, g2, g3}
+	defer stopPeers(peers)
+
+	seeAllNeighbors := func() bool {
+		for _, p := range peers {
+			if len(p.Peers()) != 2 {
+				return false
+			}
+		}
+		return true
+	}
+	waitUntilOrFail(t, seeAllNeighbors, "waiting for all instances to form uniform membership view")
+
+	revokedPKIID := common.PKIidType(fmt.Sprintf("127.0.0.1:%d", port3))
+	g1.Node.mcs.(*naiveCryptoService).revoke(revokedPKIID)
+	g1.SuspectPeers(func(_ api.PeerIdentityType) bool {
+		return true
+	})
+
+	assertRevokedPeerNotInView := func() {
+		alive := g1.Peers()
+		if assert.Len(t, alive, 1) {
+			assert.Equal(t, common.PKIidType(fmt.Sprintf("127.0.0.1:%d", port2)), alive[0].PKIid)
+		}
+		assert.Empty(t, g1.DeadPeers())
+	}
+	assertRevokedPeerNotInView()
+	// The revoked peer is not learned about again from the alive messages relayed by the other peer
+	time.Sleep(time.Second * 3)
+	assertRevokedPeerNotInView()
+}
+
 func createDataMsg(seqnum uint64, data []byte, channel common.ChannelID) *proto.GossipMessage {
 	return &proto.GossipMessage{
 		Channel: []byte(channel),
@@ -44,8 +44,9 @@ type Mapper interface {
 	// GetPKIidOfCert returns the PKI-ID of a certificate
 	GetPKIidOfCert(api.PeerIdentityType) common.PKIidType
 
-	// SuspectPeers re-validates all peers that match the given predicate
-	SuspectPeers(isSuspected api.PeerSuspector)
+	// SuspectPeers re-validates all peers that match the given predicate,
+	// and returns the PKI-IDs of the identities that were found revoked
+	SuspectPeers(isSuspected api.PeerSuspector) []common.PKIidType
 
 	// IdentityInfo returns information known peer identities
 	IdentityInfo() api.PeerIdentitySet
@@ -62,6 +63,9 @@ type identityMapperImpl struct {
 	mcs        api.MessageCryptoService
 	sa         api.SecurityAdvisor
 	pkiID2Cert map[string]*storedIdentity
+	// revoked maps the PKI-IDs of identities found revoked
+	// to the time they were found revoked
+	revoked map[string]time.Time
 	sync.RWMutex
 	stopChan  chan struct{}
 	once      sync.Once
@@ -75,6 +79,7 @@ func NewIdentityMapper(mcs api.MessageCryptoService, selfIdentity api.PeerIdenti
 		onPurge:    onPurge,
 		mcs:        mcs,
 		pkiID2Cert: make(map[string]*storedIdentity),
+		revoked:    make(map[string]time.Time),
 		stopChan:   make(chan struct{}),
 		selfPKIID:  string(selfPKIID),
 		sa:         sa,
@@ -116,6 +121,9 @@ func (is *identityMapperImpl) Put(pkiID common.PKIidType, identity api.PeerIdent
 	}
 
 	if err := is.mcs.ValidateIdentity(identity); err != nil {
+		if is.isRevoked(pkiID) {
+			return errors.WithMessage(err, "identity was revoked")
+		}
 		return err
 	}
 
@@ -126,6 +134,8 @@ func (is *identityMapperImpl) Put(pkiID common.PKIidType, identity api.PeerIdent
 
 	is.Lock()
 	defer is.Unlock()
+	// The identity is valid, so if it was revoked, its revocation was lifted
+	delete(is.revoked, string(pkiID))
 	// Check if identity already exists.
 	// If so, no need to overwrite it.
 	if _, exists := is.pkiID2Cert[string(pkiID)]; exists {
@@ -186,35 +196,75 @@ func (is *identityMapperImpl) GetPKIidOfCert(identity api.PeerIdentityType) comm
 	return is.mcs.GetPKIidOfCert(identity)
 }
 
-// SuspectPeers re-validates all peers that match the given predicate
-func (is *identityMapperImpl) SuspectPeers(isSuspected api.PeerSuspector) {
-	for _, identity := range is.validateIdentities(isSuspected) {
+// SuspectPeers re-validates all peers that match the given predicate,
+// and returns the PKI-IDs of the identities that were found revoked
+func (is *identityMapperImpl) SuspectPeers(isSuspected api.PeerSuspector) []common.PKIidType {
+	unusedIdentities, revokedIdentities := is.validateIdentities(isSuspected)
+	for _, identity := range unusedIdentities {
 		identity.cancelExpirationTimer()
 		is.delete(identity.pkiID, identity.peerIdentity)
 	}
+	var revoked []common.PKIidType
+	for _, identity := range revokedIdentities {
+		identity.cancelExpirationTimer()
+		is.revoke(identity.pkiID, identity.peerIdentity)
+		revoked = append(revoked, identity.pkiID)
+	}
+	is.purgeRevocations()
+	return revoked
 }
 
-// validateIdentities returns a list of identities that have been revoked, expired or haven't been
-// used for a long time
-func (is *identityMapperImpl) validateIdentities(isSuspected api.PeerSuspector) []*storedIdentity {
+// validateIdentities returns a list of identities that have expired or haven't been
+// used for a long time, and a list of identities that have been revoked
+func (is *identityMapperImpl) validateIdentities(isSuspected api.PeerSuspector) (unused []*storedIdentity, revoked []*storedIdentity) {
 	now := time.Now()
 	usageTh := GetIdentityUsageThreshold()
 	is.RLock()
 	defer is.RUnlock()
-	var revokedIdentities []*storedIdentity
 	for pkiID, storedIdentity := range is.pkiID2Cert {
 		if pkiID != is.selfPKIID && storedIdentity.fetchLastAccessTime().Add(usageTh).Before(now) {
-			revokedIdentities = append(revokedIdentities, storedIdentity)
+			unused = append(unused, storedIdentity)
 			continue
 		}
 		if !isSuspected(storedIdentity.peerIdentity) {
 			continue
 		}
 		if err := is.mcs.ValidateIdentity(storedIdentity.fetchIdentity()); err != nil {
-			revokedIdentities = append(revokedIdentities, storedIdentity)
+			revoked = append(revoked, storedIdentity)
+		}
+	}
+	return unused, revoked
+}
+
+// isRevoked returns whether the identity of the given PKI-ID was found revoked
+func (is *identityMapperImpl) isRevoked(pkiID common.PKIidType) bool {
+	is.RLock()
+	defer is.RUnlock()
+	_, revoked := is.revoked[string(pkiID)]
+	return revoked
+}
+
+// revoke deletes an identity that was found revoked, and remembers its PKI-ID
+// so that the identity is reported as revoked if it is presented again
+func (is *identityMapperImpl) revoke(pkiID common.PKIidType, identity api.PeerIdentityType) {
+	is.Lock()
+	defer is.Unlock()
+	is.revoked[string(pkiID)] = time.Now()
+	is.onPurge(pkiID, identity)
+	delete(is.pkiID2Cert, string(pkiID))
+}
+
+// purgeRevocations forgets the PKI-IDs of identities that were found revoked
+// longer than the identity usage threshold ago
+func (is *identityMapperImpl) purgeRevocations() {
+	usageTh := GetIdentityUsageThreshold()
+	is.Lock()
+	defer is.Unlock()
+	for pkiID, revocationTime := range is.revoked {
+		if time.Since(revocationTime) > usageTh {
+			delete(is.revoked, pkiID)
 		}
 	}
-	return revokedIdentities
 }
 
 // IdentityInfo returns information known peer identities
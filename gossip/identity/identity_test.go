@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -238,6 +239,48 @@ func TestListInvalidIdentities(t *testing.T) {
 	assert.NotNil(t, cert)
 }
 
+func TestRevocation(t *testing.T) {
+	var purged sync.Map
+	idStore := NewIdentityMapper(msgCryptoService, dummyID, func(pkiID common.PKIidType, _ api.PeerIdentityType) {
+		purged.Store(string(pkiID), struct{}{})
+	}, msgCryptoService)
+	defer idStore.Stop()
+	identity := api.PeerIdentityType("yacovm")
+	identity2 := api.PeerIdentityType("not-yacovm")
+	pkiID := msgCryptoService.GetPKIidOfCert(identity)
+	pkiID2 := msgCryptoService.GetPKIidOfCert(identity2)
+	assert.NoError(t, idStore.Put(pkiID, identity))
+	assert.NoError(t, idStore.Put(pkiID2, identity2))
+
+	// Only the identities found revoked are returned
+	msgCryptoService.revokedIdentities[string(pkiID)] = struct{}{}
+	defer func() {
+		msgCryptoService.revokedIdentities = map[string]struct{}{}
+	}()
+	revoked := idStore.SuspectPeers(func(_ api.PeerIdentityType) bool {
+		return true
+	})
+	assert.Equal(t, []common.PKIidType{pkiID}, revoked)
+	_, isPurged := purged.Load(string(pkiID))
+	assert.True(t, isPurged)
+	_, err := idStore.Get(pkiID)
+	assert.Error(t, err)
+	_, err = idStore.Get(pkiID2)
+	assert.NoError(t, err)
+
+	// The revoked identity is rejected as such
+	err = idStore.Put(pkiID, identity)
+	assert.EqualError(t, err, "identity was revoked: revoked")
+
+	// Once the revocation is lifted, the identity is accepted again
+	delete(msgCryptoService.revokedIdentities, string(pkiID))
+	assert.NoError(t, idStore.Put(pkiID, identity))
+	revoked = idStore.SuspectPeers(func(_ api.PeerIdentityType) bool {
+		return true
+	})
+	assert.Empty(t, revoked)
+}
+
 func TestExpiration(t *testing.T) {
 	deletedIdentities := make(chan string, 1)
 	SetIdentityUsageThreshold(time.Second * 500)
@@ -98,6 +98,8 @@ type CommMetrics struct {
 	SentMessages     metrics.Counter
 	BufferOverflow   metrics.Counter
 	ReceivedMessages metrics.Counter
+	SentBytes        metrics.Counter
+	ReceivedBytes    metrics.Counter
 }
 
 func newCommMetrics(p metrics.Provider) *CommMetrics {
@@ -105,6 +107,8 @@ func newCommMetrics(p metrics.Provider) *CommMetrics {
 		SentMessages:     p.NewCounter(SentMessagesOpts),
 		BufferOverflow:   p.NewCounter(BufferOverflowOpts),
 		ReceivedMessages: p.NewCounter(ReceivedMessagesOpts),
+		SentBytes:        p.NewCounter(SentBytesOpts),
+		ReceivedBytes:    p.NewCounter(ReceivedBytesOpts),
 	}
 }
 
@@ -132,6 +136,24 @@ var (
 		Help:         "Number of messages received",
 		StatsdFormat: "%{#fqname}",
 	}
+
+	SentBytesOpts = metrics.CounterOpts{
+		Namespace:    "gossip",
+		Subsystem:    "comm",
+		Name:         "bytes_sent",
+		Help:         "Number of bytes sent, after compression",
+		LabelNames:   []string{"type", "channel"},
+		StatsdFormat: "%{#fqname}.%{type}.%{channel}",
+	}
+
+	ReceivedBytesOpts = metrics.CounterOpts{
+		Namespace:    "gossip",
+		Subsystem:    "comm",
+		Name:         "bytes_received",
+		Help:         "Number of bytes received, before decompression",
+		LabelNames:   []string{"type", "channel"},
+		StatsdFormat: "%{#fqname}.%{type}.%{channel}",
+	}
 )
 
 // MembershipMetrics encapsulates gossip channel membership related metrics
@@ -167,6 +189,10 @@ type PrivdataMetrics struct {
 	ReconciliationDuration         metrics.Histogram
 	PullDuration                   metrics.Histogram
 	RetrieveDuration               metrics.Histogram
+	ReconciledElements             metrics.Counter
+	ReconciliationLastAttempt      metrics.Gauge
+	MissingEligible                metrics.Gauge
+	MissingIneligible              metrics.Gauge
 }
 
 func newPrivdataMetrics(p metrics.Provider) *PrivdataMetrics {
@@ -180,6 +206,10 @@ func newPrivdataMetrics(p metrics.Provider) *PrivdataMetrics {
 		ReconciliationDuration:         p.NewHistogram(ReconciliationDurationOpts),
 		PullDuration:                   p.NewHistogram(PullDurationOpts),
 		RetrieveDuration:               p.NewHistogram(RetrieveDurationOpts),
+		ReconciledElements:             p.NewCounter(ReconciledElementsOpts),
+		ReconciliationLastAttempt:      p.NewGauge(ReconciliationLastAttemptOpts),
+		MissingEligible:                p.NewGauge(MissingEligibleOpts),
+		MissingIneligible:              p.NewGauge(MissingIneligibleOpts),
 	}
 }
 
@@ -264,4 +294,40 @@ var (
 		LabelNames:   []string{"channel"},
 		StatsdFormat: "%{#fqname}.%{channel}",
 	}
+
+	ReconciledElementsOpts = metrics.CounterOpts{
+		Namespace:    "gossip",
+		Subsystem:    "privdata",
+		Name:         "reconciled_elements",
+		Help:         "Number of missing private data elements reconciled from other peers",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	ReconciliationLastAttemptOpts = metrics.GaugeOpts{
+		Namespace:    "gossip",
+		Subsystem:    "privdata",
+		Name:         "reconciliation_last_attempt",
+		Help:         "Time of the last reconciliation attempt (in seconds since the epoch)",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	MissingEligibleOpts = metrics.GaugeOpts{
+		Namespace:    "gossip",
+		Subsystem:    "privdata",
+		Name:         "missing_eligible",
+		Help:         "Number of private data elements missing for the collections the peer is eligible for",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	MissingIneligibleOpts = metrics.GaugeOpts{
+		Namespace:    "gossip",
+		Subsystem:    "privdata",
+		Name:         "missing_ineligible",
+		Help:         "Number of private data elements missing for the collections the peer is not eligible for",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
 )
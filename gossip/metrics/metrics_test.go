@@ -42,6 +42,8 @@ func TestMetrics(t *testing.T) {
 	assert.NotNil(t, gossipMetrics.CommMetrics.SentMessages)
 	assert.NotNil(t, gossipMetrics.CommMetrics.ReceivedMessages)
 	assert.NotNil(t, gossipMetrics.CommMetrics.BufferOverflow)
+	assert.NotNil(t, gossipMetrics.CommMetrics.SentBytes)
+	assert.NotNil(t, gossipMetrics.CommMetrics.ReceivedBytes)
 
 	assert.NotNil(t, gossipMetrics.MembershipMetrics)
 	assert.NotNil(t, gossipMetrics.MembershipMetrics.Total)
@@ -56,4 +58,8 @@ func TestMetrics(t *testing.T) {
 	assert.NotNil(t, gossipMetrics.PrivdataMetrics.ReconciliationDuration)
 	assert.NotNil(t, gossipMetrics.PrivdataMetrics.PullDuration)
 	assert.NotNil(t, gossipMetrics.PrivdataMetrics.RetrieveDuration)
+	assert.NotNil(t, gossipMetrics.PrivdataMetrics.ReconciledElements)
+	assert.NotNil(t, gossipMetrics.PrivdataMetrics.ReconciliationLastAttempt)
+	assert.NotNil(t, gossipMetrics.PrivdataMetrics.MissingEligible)
+	assert.NotNil(t, gossipMetrics.PrivdataMetrics.MissingIneligible)
 }
@@ -24,6 +24,8 @@ type TestMetricProvider struct {
 	FakeSentMessages     *metricsfakes.Counter
 	FakeBufferOverflow   *metricsfakes.Counter
 	FakeReceivedMessages *metricsfakes.Counter
+	FakeSentBytes        *metricsfakes.Counter
+	FakeReceivedBytes    *metricsfakes.Counter
 
 	FakeTotalGauge *metricsfakes.Gauge
 
@@ -36,6 +38,10 @@ type TestMetricProvider struct {
 	FakeReconciliationDuration         *metricsfakes.Histogram
 	FakePullDuration                   *metricsfakes.Histogram
 	FakeRetrieveDuration               *metricsfakes.Histogram
+	FakeReconciledElements             *metricsfakes.Counter
+	FakeReconciliationLastAttempt      *metricsfakes.Gauge
+	FakeMissingEligible                *metricsfakes.Gauge
+	FakeMissingIneligible              *metricsfakes.Gauge
 }
 
 func TestUtilConstructMetricProvider() *TestMetricProvider {
@@ -50,6 +56,8 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 	fakeSentMessages := testUtilConstructCounter()
 	fakeBufferOverflow := testUtilConstructCounter()
 	fakeReceivedMessages := testUtilConstructCounter()
+	fakeSentBytes := testUtilConstructCounter()
+	fakeReceivedBytes := testUtilConstructCounter()
 
 	fakeTotalGauge := testUtilConstructGauge()
 
@@ -62,6 +70,10 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 	fakeReconciliationDuration := testUtilConstructHist()
 	fakePullDuration := testUtilConstructHist()
 	fakeRetrieveDuration := testUtilConstructHist()
+	fakeReconciledElements := testUtilConstructCounter()
+	fakeReconciliationLastAttempt := testUtilConstructGauge()
+	fakeMissingEligible := testUtilConstructGauge()
+	fakeMissingIneligible := testUtilConstructGauge()
 
 	fakeProvider.NewCounterStub = func(opts metrics.CounterOpts) metrics.Counter {
 		switch opts.Name {
@@ -71,6 +83,12 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 			return fakeSentMessages
 		case gmetrics.ReceivedMessagesOpts.Name:
 			return fakeReceivedMessages
+		case gmetrics.SentBytesOpts.Name:
+			return fakeSentBytes
+		case gmetrics.ReceivedBytesOpts.Name:
+			return fakeReceivedBytes
+		case gmetrics.ReconciledElementsOpts.Name:
+			return fakeReconciledElements
 		}
 		return nil
 	}
@@ -109,6 +127,12 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 			return fakeDeclarationGauge
 		case gmetrics.TotalOpts.Name:
 			return fakeTotalGauge
+		case gmetrics.ReconciliationLastAttemptOpts.Name:
+			return fakeReconciliationLastAttempt
+		case gmetrics.MissingEligibleOpts.Name:
+			return fakeMissingEligible
+		case gmetrics.MissingIneligibleOpts.Name:
+			return fakeMissingIneligible
 		}
 		return nil
 	}
@@ -122,6 +146,8 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 		fakeSentMessages,
 		fakeBufferOverflow,
 		fakeReceivedMessages,
+		fakeSentBytes,
+		fakeReceivedBytes,
 		fakeTotalGauge,
 		fakeValidationDuration,
 		fakeListMissingPrivateDataDuration,
@@ -132,6 +158,10 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 		fakeReconciliationDuration,
 		fakePullDuration,
 		fakeRetrieveDuration,
+		fakeReconciledElements,
+		fakeReconciliationLastAttempt,
+		fakeMissingEligible,
+		fakeMissingIneligible,
 	}
 }
 
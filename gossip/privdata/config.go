@@ -8,6 +8,7 @@ package privdata
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -28,6 +29,12 @@ type PrivdataConfig struct {
 	ReconcileBatchSize int
 	// ReconciliationEnabled is a flag that indicates whether private data reconciliation is enabled or not.
 	ReconciliationEnabled bool
+	// ReconcileRecentBlocks determines the number of most recent blocks whose missing private data is reconciled
+	// first in each iteration, including the private data deprioritized after failed attempts. Default is 0.
+	ReconcileRecentBlocks int
+	// ReconcilePriorityCollections lists the collections, as '<chaincode>/<collection>', whose missing private data
+	// is reconciled first in each iteration, regardless of the blocks they belong to.
+	ReconcilePriorityCollections []string
 	// ImplicitCollectionDisseminationPolicy specifies the dissemination  policy for the peer's own implicit collection.
 	ImplicitCollDisseminationPolicy ImplicitCollectionDisseminationPolicy
 }
@@ -65,6 +72,18 @@ func (c *PrivdataConfig) loadPrivDataConfig() {
 
 	c.ReconciliationEnabled = viper.GetBool("peer.gossip.pvtData.reconciliationEnabled")
 
+	c.ReconcileRecentBlocks = viper.GetInt("peer.gossip.pvtData.reconcileRecentBlocks")
+	if c.ReconcileRecentBlocks < 0 {
+		panic(fmt.Sprintf("peer.gossip.pvtData.reconcileRecentBlocks (%d) cannot be less than zero", c.ReconcileRecentBlocks))
+	}
+
+	for _, coll := range viper.GetStringSlice("peer.gossip.pvtData.reconcilePriorityCollections") {
+		if parts := strings.Split(coll, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			panic(fmt.Sprintf("peer.gossip.pvtData.reconcilePriorityCollections entry (%s) is not in the form '<chaincode>/<collection>'", coll))
+		}
+		c.ReconcilePriorityCollections = append(c.ReconcilePriorityCollections, coll)
+	}
+
 	requiredPeerCount := viper.GetInt("peer.gossip.pvtData.implicitCollectionDisseminationPolicy.requiredPeerCount")
 
 	maxPeerCount := implicitCollectionMaxPeerCountDefault
@@ -21,15 +21,19 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("peer.gossip.pvtData.reconcileSleepInterval", "10s")
 	viper.Set("peer.gossip.pvtData.reconcileBatchSize", 10)
 	viper.Set("peer.gossip.pvtData.reconciliationEnabled", true)
+	viper.Set("peer.gossip.pvtData.reconcileRecentBlocks", 5)
+	viper.Set("peer.gossip.pvtData.reconcilePriorityCollections", []string{"cc1/coll1", "cc2/coll2"})
 	viper.Set("peer.gossip.pvtData.implicitCollectionDisseminationPolicy.requiredPeerCount", 2)
 	viper.Set("peer.gossip.pvtData.implicitCollectionDisseminationPolicy.maxPeerCount", 3)
 
 	coreConfig := privdata.GlobalConfig()
 
 	expectedConfig := &privdata.PrivdataConfig{
-		ReconcileSleepInterval: 10 * time.Second,
-		ReconcileBatchSize:     10,
-		ReconciliationEnabled:  true,
+		ReconcileSleepInterval:       10 * time.Second,
+		ReconcileBatchSize:           10,
+		ReconciliationEnabled:        true,
+		ReconcileRecentBlocks:        5,
+		ReconcilePriorityCollections: []string{"cc1/coll1", "cc2/coll2"},
 		ImplicitCollDisseminationPolicy: privdata.ImplicitCollectionDisseminationPolicy{
 			RequiredPeerCount: 2,
 			MaxPeerCount:      3,
@@ -79,4 +83,21 @@ func TestGlobalConfigPanic(t *testing.T) {
 		func() { privdata.GlobalConfig() },
 		"A panic should occur because requiredPeerCount is less than zero",
 	)
+	viper.Set("peer.gossip.pvtData.implicitCollectionDisseminationPolicy.requiredPeerCount", 0)
+	viper.Set("peer.gossip.pvtData.reconcileRecentBlocks", -1)
+	assert.PanicsWithValue(
+		t,
+		"peer.gossip.pvtData.reconcileRecentBlocks (-1) cannot be less than zero",
+		func() { privdata.GlobalConfig() },
+		"A panic should occur because reconcileRecentBlocks is less than zero",
+	)
+
+	viper.Set("peer.gossip.pvtData.reconcileRecentBlocks", 0)
+	viper.Set("peer.gossip.pvtData.reconcilePriorityCollections", []string{"cc1/coll1", "coll2"})
+	assert.PanicsWithValue(
+		t,
+		"peer.gossip.pvtData.reconcilePriorityCollections entry (coll2) is not in the form '<chaincode>/<collection>'",
+		func() { privdata.GlobalConfig() },
+		"A panic should occur because a priority collection is malformed",
+	)
 }
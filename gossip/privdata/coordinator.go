@@ -174,12 +174,14 @@ func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDa
 		MissingPvtData: make(ledger.TxMissingPvtDataMap),
 	}
 
+	purgePvtData := c.Support.CapabilityProvider.Capabilities().PurgePvtData()
+
 	exist, err := c.DoesPvtDataInfoExistInLedger(block.Header.Number)
 	if err != nil {
 		return err
 	}
 	if exist {
-		commitOpts := &ledger.CommitOptions{FetchPvtDataFromLedger: true}
+		commitOpts := &ledger.CommitOptions{FetchPvtDataFromLedger: true, PurgePvtData: purgePvtData}
 		return c.CommitLegacy(blockAndPvtData, commitOpts)
 	}
 
@@ -223,7 +225,7 @@ func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDa
 
 	// commit block and private data
 	commitStart := time.Now()
-	err = c.CommitLegacy(blockAndPvtData, &ledger.CommitOptions{})
+	err = c.CommitLegacy(blockAndPvtData, &ledger.CommitOptions{PurgePvtData: purgePvtData})
 	c.reportCommitDuration(time.Since(commitStart))
 	if err != nil {
 		return errors.Wrap(err, "commit failed")
@@ -232,9 +234,32 @@ func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDa
 	// Purge transactions
 	go retrievedPvtdata.Purge()
 
+	// Purge the private data of the keys purged by the block
+	if purgePvtData {
+		c.purgePvtDataOfKeys(block)
+	}
+
 	return nil
 }
 
+// purgePvtDataOfKeys removes from the transient store the private data of the
+// transactions already in the ledger which write the keys purged by the valid
+// transactions of a committed block. It is the only store of private data held
+// by gossip beyond the commit of a block: the private data prefetched along with
+// the blocks in the state buffer is consumed by the commit of its block, and the
+// private data already disseminated to other peers is purged by each of them
+// when committing the block. The private data of pending transactions is kept,
+// as they are ordered after the purge.
+func (c *coordinator) purgePvtDataOfKeys(block *common.Block) {
+	var purgedKeys []*rwsetutil.PurgedKey
+	for _, keys := range rwsetutil.PurgedKeysOfBlock(block) {
+		purgedKeys = append(purgedKeys, keys...)
+	}
+	if err := c.store.PurgeByKeys(purgedKeys, block.Header.Number, c.TxIDExists); err != nil {
+		c.logger.Errorf("Failed purging private data of purged keys from transient store for block [%d]: %s", block.Header.Number, err)
+	}
+}
+
 // StorePvtData used to persist private date into transient store
 func (c *coordinator) StorePvtData(txID string, privData *protostransientstore.TxPvtReadWriteSetWithConfigInfo, blkHeight uint64) error {
 	return c.store.Persist(txID, blkHeight, privData)
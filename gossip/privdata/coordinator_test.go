@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/peer"
 	tspb "github.com/hyperledger/fabric-protos-go/transientstore"
 	"github.com/osdi23p228/fabric/bccsp/factory"
+	ledgertestutil "github.com/osdi23p228/fabric/common/ledger/testutil"
 	"github.com/osdi23p228/fabric/common/metrics/disabled"
 	util2 "github.com/osdi23p228/fabric/common/util"
 	"github.com/osdi23p228/fabric/core/common/privdata"
@@ -640,6 +641,7 @@ func TestCoordinatorStoreInvalidBlock(t *testing.T) {
 	appCapability := &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(true)
+	appCapability.On("PurgePvtData").Return(false)
 	coordinator := NewCoordinator(mspID, Support{
 		ChainID:            "testchannelid",
 		CollectionStore:    cs,
@@ -725,6 +727,7 @@ func TestCoordinatorStoreInvalidBlock(t *testing.T) {
 	appCapability = &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(false)
+	appCapability.On("PurgePvtData").Return(false)
 	coordinator = NewCoordinator(mspID, Support{
 		ChainID:            "testchannelid",
 		CollectionStore:    cs,
@@ -782,6 +785,7 @@ func TestCoordinatorStoreInvalidBlock(t *testing.T) {
 	appCapability = &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(true)
+	appCapability.On("PurgePvtData").Return(false)
 	digKeys = []privdatacommon.DigKey{}
 	fetcher = &fetcherMock{t: t}
 	fetcher.On("fetch", mock.Anything).expectingDigests(digKeys).Return(&privdatacommon.FetchedPvtDataContainer{
@@ -977,6 +981,7 @@ func TestCoordinatorToFilterOutPvtRWSetsWithWrongHash(t *testing.T) {
 	appCapability := &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(true)
+	appCapability.On("PurgePvtData").Return(false)
 	coordinator := NewCoordinator(mspID, Support{
 		ChainID:            "testchannelid",
 		CollectionStore:    cs,
@@ -1098,6 +1103,7 @@ func TestCoordinatorStoreBlock(t *testing.T) {
 	appCapability := &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(true)
+	appCapability.On("PurgePvtData").Return(false)
 	coordinator := NewCoordinator(mspID, Support{
 		ChainID:            "testchannelid",
 		CollectionStore:    cs,
@@ -1338,7 +1344,7 @@ func TestCoordinatorStoreBlockWhenPvtDataExistInLedger(t *testing.T) {
 		privateDataPassed2Ledger := args.Get(0).(*ledger.BlockAndPvtData).PvtData
 		assert.Equal(t, ledger.TxPvtDataMap{}, privateDataPassed2Ledger)
 		commitOpts := args.Get(1).(*ledger.CommitOptions)
-		expectedCommitOpts := &ledger.CommitOptions{FetchPvtDataFromLedger: true}
+		expectedCommitOpts := &ledger.CommitOptions{FetchPvtDataFromLedger: true, PurgePvtData: true}
 		assert.Equal(t, expectedCommitOpts, commitOpts)
 		commitHappened = true
 	}).Return(nil)
@@ -1371,6 +1377,7 @@ func TestCoordinatorStoreBlockWhenPvtDataExistInLedger(t *testing.T) {
 	appCapability := &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(true)
+	appCapability.On("PurgePvtData").Return(true)
 	coordinator := NewCoordinator(mspID, Support{
 		ChainID:            "testchannelid",
 		CollectionStore:    nil,
@@ -1486,6 +1493,7 @@ func TestProceedWithoutPrivateData(t *testing.T) {
 	appCapability := &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(true)
+	appCapability.On("PurgePvtData").Return(false)
 	coordinator := NewCoordinator(mspID, Support{
 		ChainID:            "testchannelid",
 		CollectionStore:    cs,
@@ -1560,6 +1568,7 @@ func TestProceedWithInEligiblePrivateData(t *testing.T) {
 	appCapability := &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(true)
+	appCapability.On("PurgePvtData").Return(false)
 	coordinator := NewCoordinator(mspID, Support{
 		ChainID:            "testchannelid",
 		CollectionStore:    cs,
@@ -1606,6 +1615,7 @@ func TestCoordinatorGetBlocks(t *testing.T) {
 	appCapability := &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(true)
+	appCapability.On("PurgePvtData").Return(false)
 
 	hash := util2.ComputeSHA256([]byte("rws-pre-image"))
 	bf := &blockFactory{
@@ -1732,6 +1742,7 @@ func TestPurgeBelowHeight(t *testing.T) {
 	appCapability := &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(true)
+	appCapability.On("PurgePvtData").Return(false)
 	coordinator := NewCoordinator(mspID, Support{
 		ChainID:            "testchannelid",
 		CollectionStore:    cs,
@@ -1779,6 +1790,7 @@ func TestCoordinatorStorePvtData(t *testing.T) {
 	appCapability := &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(true)
+	appCapability.On("PurgePvtData").Return(false)
 	coordinator := NewCoordinator(mspID, Support{
 		ChainID:            "testchannelid",
 		CollectionStore:    cs,
@@ -1877,6 +1889,7 @@ func TestIgnoreReadOnlyColRWSets(t *testing.T) {
 	appCapability := &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(true)
+	appCapability.On("PurgePvtData").Return(false)
 	coordinator := NewCoordinator(mspID, Support{
 		ChainID:            "testchannelid",
 		CollectionStore:    cs,
@@ -1961,6 +1974,7 @@ func TestCoordinatorMetrics(t *testing.T) {
 	appCapability := &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(true)
+	appCapability.On("PurgePvtData").Return(false)
 	coordinator := NewCoordinator(mspID, Support{
 		ChainID:            "testchannelid",
 		CollectionStore:    cs,
@@ -2005,3 +2019,50 @@ func TestCoordinatorMetrics(t *testing.T) {
 	}
 	assert.Eventually(t, purgeDuration, 2*time.Second, 100*time.Millisecond)
 }
+
+func TestCoordinatorPurgePvtDataOfKeys(t *testing.T) {
+	store := newTransientStore(t)
+	defer store.tearDown()
+
+	builder := rwsetutil.NewRWSetBuilder()
+	builder.AddToPvtAndHashedWriteSet("ns1", "c1", "key1", []byte("value1"))
+	builder.AddToPvtAndHashedWriteSet("ns1", "c1", "key2", []byte("value2"))
+	simRes, err := builder.GetTxSimulationResults()
+	require.NoError(t, err)
+	require.NoError(t, store.Persist("tx1", 1, &tspb.TxPvtReadWriteSetWithConfigInfo{PvtRwset: simRes.PvtSimulationResults}))
+	require.NoError(t, store.Persist("tx2", 1, &tspb.TxPvtReadWriteSetWithConfigInfo{PvtRwset: simRes.PvtSimulationResults}))
+
+	builder = rwsetutil.NewRWSetBuilder()
+	builder.AddToPvtAndHashedPurgeSet("ns1", "c1", "key1")
+	simRes, err = builder.GetTxSimulationResults()
+	require.NoError(t, err)
+	pubSimRes, err := simRes.GetPubSimulationBytes()
+	require.NoError(t, err)
+	bg, _ := ledgertestutil.NewBlockGenerator(t, "testchannelid", false)
+	block := bg.NextBlock([][]byte{pubSimRes})
+
+	// tx1 is already in the ledger while tx2 is still pending
+	committer := &mocks.Committer{}
+	committer.On("TxIDExists", "tx1").Return(true, nil)
+	committer.On("TxIDExists", "tx2").Return(false, nil)
+
+	c := NewCoordinator("Org1MSP", Support{ChainID: "testchannelid", Committer: committer}, store.store, protoutil.SignedData{},
+		metrics.NewGossipMetrics(&disabled.Provider{}).PrivdataMetrics, testConfig, nil)
+	c.(*coordinator).purgePvtDataOfKeys(block)
+
+	iterator, err := store.GetTxPvtRWSetByTxid("tx1", nil)
+	require.NoError(t, err)
+	res, err := iterator.Next()
+	require.NoError(t, err)
+	iterator.Close()
+	require.Nil(t, res)
+
+	iterator, err = store.GetTxPvtRWSetByTxid("tx2", nil)
+	require.NoError(t, err)
+	defer iterator.Close()
+	res, err = iterator.Next()
+	require.NoError(t, err)
+	kvRWSet := &kvrwset.KVRWSet{}
+	require.NoError(t, pb.Unmarshal(res.PvtSimulationResultsWithConfig.PvtRwset.NsPvtRwset[0].CollectionPvtRwset[0].Rwset, kvRWSet))
+	require.Len(t, kvRWSet.Writes, 2)
+}
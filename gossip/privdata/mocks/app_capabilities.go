@@ -23,6 +23,20 @@ func (_m *AppCapabilities) ACLs() bool {
 	return r0
 }
 
+// BatchStateAccess provides a mock function with given fields:
+func (_m *AppCapabilities) BatchStateAccess() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // CollectionUpgrade provides a mock function with given fields:
 func (_m *AppCapabilities) CollectionUpgrade() bool {
 	ret := _m.Called()
@@ -107,6 +121,20 @@ func (_m *AppCapabilities) PrivateChannelData() bool {
 	return r0
 }
 
+// PurgePvtData provides a mock function with given fields:
+func (_m *AppCapabilities) PurgePvtData() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // StorePvtDataOfInvalidTx provides a mock function with given fields:
 func (_m *AppCapabilities) StorePvtDataOfInvalidTx() bool {
 	ret := _m.Called()
@@ -202,3 +202,24 @@ func (_m *Committer) LedgerHeight() (uint64, error) {
 
 	return r0, r1
 }
+
+// TxIDExists provides a mock function with given fields: txID
+func (_m *Committer) TxIDExists(txID string) (bool, error) {
+	ret := _m.Called(txID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(txID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(txID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -10,6 +10,52 @@ type MissingPvtDataTracker struct {
 	mock.Mock
 }
 
+// GetMissingPvtDataCounts provides a mock function with given fields:
+func (_m *MissingPvtDataTracker) GetMissingPvtDataCounts() (*ledger.MissingPvtDataCounts, error) {
+	ret := _m.Called()
+
+	var r0 *ledger.MissingPvtDataCounts
+	if rf, ok := ret.Get(0).(func() *ledger.MissingPvtDataCounts); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ledger.MissingPvtDataCounts)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMissingPvtDataInfoForBlockRange provides a mock function with given fields: startBlock, endBlock, maxBlocks, filter
+func (_m *MissingPvtDataTracker) GetMissingPvtDataInfoForBlockRange(startBlock uint64, endBlock uint64, maxBlocks int, filter ledger.PvtNsCollFilter) (ledger.MissingPvtDataInfo, error) {
+	ret := _m.Called(startBlock, endBlock, maxBlocks, filter)
+
+	var r0 ledger.MissingPvtDataInfo
+	if rf, ok := ret.Get(0).(func(uint64, uint64, int, ledger.PvtNsCollFilter) ledger.MissingPvtDataInfo); ok {
+		r0 = rf(startBlock, endBlock, maxBlocks, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ledger.MissingPvtDataInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, uint64, int, ledger.PvtNsCollFilter) error); ok {
+		r1 = rf(startBlock, endBlock, maxBlocks, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMissingPvtDataInfoForMostRecentBlocks provides a mock function with given fields: maxBlocks
 func (_m *MissingPvtDataTracker) GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error) {
 	ret := _m.Called(maxBlocks)
@@ -125,9 +125,10 @@ func (ec *eligibilityComputer) computeEligibility(mspID string, pvtdataToRetriev
 				collection: col,
 			}
 
-			// First check if mspID is found in the MemberOrgs before falling back to AccessFilter policy evaluation
+			// First check if mspID is found in the orgs which are members regardless of the OU or role
+			// of their peers, before falling back to AccessFilter policy evaluation
 			memberOrgs := policy.MemberOrgs()
-			if _, ok := memberOrgs[mspID]; !ok &&
+			if _, ok := policy.OrgWideMemberOrgs()[mspID]; !ok &&
 				!policy.AccessFilter()(ec.selfSignedData) {
 				ec.logger.Debugf("Peer is not eligible for collection: chaincode [%s], "+
 					"collection name [%s], txID [%s] the policy is [%#v]. Skipping.",
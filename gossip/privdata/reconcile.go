@@ -10,6 +10,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math"
+	"strings"
 	"sync"
 	"time"
 
@@ -53,6 +54,25 @@ type PvtDataReconciler interface {
 	Start()
 	// Stop function stops reconciler
 	Stop()
+	// Status returns the progress of the reconciliation
+	Status() ReconciliationStatus
+	// ReconcileBlockRange reconciles right away the missing private data of the blocks within the
+	// range [startBlock, endBlock], and returns the number of private data elements reconciled
+	ReconcileBlockRange(startBlock, endBlock uint64) (int, error)
+}
+
+// ReconciliationStatus reports the progress of the reconciliation of the missing private data of a channel
+type ReconciliationStatus struct {
+	Channel string `json:"channel"`
+	Enabled bool   `json:"enabled"`
+	// MissingEligible and MissingIneligible are the number of private data elements missing
+	// for the collections the peer is eligible and ineligible for, as of the last attempt
+	MissingEligible   uint64 `json:"missingEligible"`
+	MissingIneligible uint64 `json:"missingIneligible"`
+	// Reconciled is the number of private data elements reconciled since the peer started
+	Reconciled       uint64    `json:"reconciled"`
+	LastAttempt      time.Time `json:"lastAttempt"`
+	LastAttemptError string    `json:"lastAttemptError,omitempty"`
 }
 
 type Reconciler struct {
@@ -61,9 +81,20 @@ type Reconciler struct {
 	metrics                *metrics.PrivdataMetrics
 	ReconcileSleepInterval time.Duration
 	ReconcileBatchSize     int
-	stopChan               chan struct{}
-	startOnce              sync.Once
-	stopOnce               sync.Once
+	// ReconcileRecentBlocks is the number of most recent blocks whose missing private data
+	// is reconciled first in each iteration
+	ReconcileRecentBlocks int
+	// PriorityCollections are the collections whose missing private data is reconciled
+	// first in each iteration
+	PriorityCollections ledger.PvtNsCollFilter
+	stopChan            chan struct{}
+	startOnce           sync.Once
+	stopOnce            sync.Once
+	// reconcileLock serializes the reconciliation iterations and the reconciliations
+	// triggered through ReconcileBlockRange
+	reconcileLock sync.Mutex
+	statusLock    sync.RWMutex
+	status        ReconciliationStatus
 	ReconciliationFetcher
 	committer.Committer
 }
@@ -82,17 +113,36 @@ func (*NoOpReconciler) Stop() {
 	// do nothing
 }
 
+func (*NoOpReconciler) Status() ReconciliationStatus {
+	return ReconciliationStatus{}
+}
+
+func (*NoOpReconciler) ReconcileBlockRange(startBlock, endBlock uint64) (int, error) {
+	return 0, errors.New("private data reconciliation is disabled")
+}
+
 // NewReconciler creates a new instance of reconciler
 func NewReconciler(channel string, metrics *metrics.PrivdataMetrics, c committer.Committer,
 	fetcher ReconciliationFetcher, config *PrivdataConfig) *Reconciler {
 	reconcilerLogger := logger.With("channel", channel)
 	reconcilerLogger.Debug("Private data reconciliation is enabled")
+	var priorityCollections ledger.PvtNsCollFilter
+	for _, coll := range config.ReconcilePriorityCollections {
+		// the entries are validated to be in the form '<chaincode>/<collection>'
+		ccColl := strings.SplitN(coll, "/", 2)
+		if priorityCollections == nil {
+			priorityCollections = ledger.NewPvtNsCollFilter()
+		}
+		priorityCollections.Add(ccColl[0], ccColl[1])
+	}
 	return &Reconciler{
 		channel:                channel,
 		logger:                 reconcilerLogger,
 		metrics:                metrics,
 		ReconcileSleepInterval: config.ReconcileSleepInterval,
 		ReconcileBatchSize:     config.ReconcileBatchSize,
+		ReconcileRecentBlocks:  config.ReconcileRecentBlocks,
+		PriorityCollections:    priorityCollections,
 		Committer:              c,
 		ReconciliationFetcher:  fetcher,
 		stopChan:               make(chan struct{}),
@@ -118,34 +168,149 @@ func (r *Reconciler) run() {
 			return
 		case <-time.After(r.ReconcileSleepInterval):
 			r.logger.Debug("Start reconcile missing private info")
-			if err := r.reconcile(); err != nil {
+			err := r.reconcile()
+			if err != nil {
 				r.logger.Error("Failed to reconcile missing private info, error: ", err.Error())
-				break
 			}
+			r.updateMissingCounts()
 		}
 	}
 }
 
-// returns the number of items that were reconciled , minBlock, maxBlock (blocks range) and an error
+// Status returns the progress of the reconciliation
+func (r *Reconciler) Status() ReconciliationStatus {
+	r.statusLock.RLock()
+	defer r.statusLock.RUnlock()
+	status := r.status
+	status.Channel = r.channel
+	status.Enabled = true
+	return status
+}
+
+// ReconcileBlockRange reconciles right away the missing private data of the blocks within the
+// range [startBlock, endBlock], including the private data deprioritized after failed attempts
+func (r *Reconciler) ReconcileBlockRange(startBlock, endBlock uint64) (int, error) {
+	if startBlock > endBlock {
+		return 0, errors.Errorf("invalid block range [%d - %d]", startBlock, endBlock)
+	}
+
+	r.reconcileLock.Lock()
+	defer r.reconcileLock.Unlock()
+
+	r.logger.Infof("Reconciling missing private data of blocks range [%d - %d]", startBlock, endBlock)
+	startTime := r.startAttempt()
+	reconciled, err := r.reconcileBlockRange(startBlock, endBlock, nil)
+	r.endAttempt(startTime, reconciled, err)
+	r.updateMissingCounts()
+	return reconciled, err
+}
+
+// reconcile runs a reconciliation iteration, reconciling first the prioritized missing private
+// data and then the missing private data of the most recent blocks
 func (r *Reconciler) reconcile() error {
-	missingPvtDataTracker, err := r.GetMissingPvtDataTracker()
+	r.reconcileLock.Lock()
+	defer r.reconcileLock.Unlock()
+
+	defer r.reportReconciliationDuration(time.Now())
+
+	startTime := r.startAttempt()
+	totalReconciled, err := r.reconcilePrioritized()
+	if err == nil {
+		var reconciled int
+		reconciled, err = r.reconcileMostRecentBlocks()
+		totalReconciled += reconciled
+	}
+	r.endAttempt(startTime, totalReconciled, err)
+	return err
+}
+
+// reconcilePrioritized reconciles the missing private data of the most recent blocks and of the
+// priority collections, as configured, before the regular reconciliation takes place
+func (r *Reconciler) reconcilePrioritized() (int, error) {
+	totalReconciled := 0
+	if r.ReconcileRecentBlocks > 0 {
+		height, err := r.LedgerHeight()
+		if err != nil {
+			return 0, errors.WithMessage(err, "failed to get the ledger height")
+		}
+		if height > 0 {
+			startBlock, endBlock := uint64(0), height-1
+			if endBlock >= uint64(r.ReconcileRecentBlocks) {
+				startBlock = endBlock - uint64(r.ReconcileRecentBlocks) + 1
+			}
+			reconciled, err := r.reconcileBlockRange(startBlock, endBlock, nil)
+			totalReconciled += reconciled
+			if err != nil {
+				return totalReconciled, err
+			}
+		}
+	}
+
+	if len(r.PriorityCollections) > 0 {
+		reconciled, err := r.reconcileBlockRange(0, math.MaxUint64, r.PriorityCollections)
+		totalReconciled += reconciled
+		if err != nil {
+			return totalReconciled, err
+		}
+	}
+	return totalReconciled, nil
+}
+
+// reconcileBlockRange reconciles the missing private data within the block range, from the most
+// recent blocks down to the oldest ones. When a filter is provided, only the private data of the
+// collections in the filter is looked up and reconciled.
+func (r *Reconciler) reconcileBlockRange(startBlock, endBlock uint64, filter ledger.PvtNsCollFilter) (int, error) {
+	missingPvtDataTracker, err := r.missingPvtDataTracker()
 	if err != nil {
-		r.logger.Error("reconciliation error when trying to get missingPvtDataTracker:", err)
-		return err
+		return 0, err
 	}
-	if missingPvtDataTracker == nil {
-		r.logger.Error("got nil as MissingPvtDataTracker, exiting...")
-		return errors.New("got nil as MissingPvtDataTracker, exiting...")
+
+	totalReconciled := 0
+	for {
+		missingPvtDataInfo, err := missingPvtDataTracker.GetMissingPvtDataInfoForBlockRange(startBlock, endBlock, r.ReconcileBatchSize, filter)
+		if err != nil {
+			r.logger.Error("reconciliation error when trying to get missing pvt data info for blocks range:", err)
+			return totalReconciled, err
+		}
+		if len(missingPvtDataInfo) == 0 {
+			return totalReconciled, nil
+		}
+
+		minBlock := uint64(math.MaxUint64)
+		for blockNum := range missingPvtDataInfo {
+			if blockNum < minBlock {
+				minBlock = blockNum
+			}
+		}
+
+		reconciled, _, _, err := r.reconcileMissingPvtData(missingPvtDataInfo)
+		totalReconciled += reconciled
+		if err != nil {
+			return totalReconciled, err
+		}
+
+		// fewer blocks than requested means that there is no more missing private data in the range
+		if minBlock <= startBlock || len(missingPvtDataInfo) < r.ReconcileBatchSize {
+			return totalReconciled, nil
+		}
+		endBlock = minBlock - 1
 	}
-	totalReconciled, minBlock, maxBlock := 0, uint64(math.MaxUint64), uint64(0)
+}
 
-	defer r.reportReconciliationDuration(time.Now())
+// reconcileMostRecentBlocks reconciles the missing private data of the most recent blocks
+// in batches until there is no more missing private data to reconcile
+func (r *Reconciler) reconcileMostRecentBlocks() (int, error) {
+	missingPvtDataTracker, err := r.missingPvtDataTracker()
+	if err != nil {
+		return 0, err
+	}
+	totalReconciled, minBlock, maxBlock := 0, uint64(math.MaxUint64), uint64(0)
 
 	for {
 		missingPvtDataInfo, err := missingPvtDataTracker.GetMissingPvtDataInfoForMostRecentBlocks(r.ReconcileBatchSize)
 		if err != nil {
 			r.logger.Error("reconciliation error when trying to get missing pvt data info recent blocks:", err)
-			return err
+			return totalReconciled, err
 		}
 		// if missingPvtDataInfo is nil, len will return 0
 		if len(missingPvtDataInfo) == 0 {
@@ -154,35 +319,97 @@ func (r *Reconciler) reconcile() error {
 			} else {
 				r.logger.Debug("Reconciliation cycle finished successfully. no items to reconcile")
 			}
-			return nil
+			return totalReconciled, nil
 		}
 
 		r.logger.Debug("got from ledger", len(missingPvtDataInfo), "blocks with missing private data, trying to reconcile...")
 
-		dig2collectionCfg, minB, maxB := r.getDig2CollectionConfig(missingPvtDataInfo)
-		fetchedData, err := r.FetchReconciledItems(dig2collectionCfg)
+		reconciled, minB, maxB, err := r.reconcileMissingPvtData(missingPvtDataInfo)
+		totalReconciled += reconciled
 		if err != nil {
-			r.logger.Error("reconciliation error when trying to fetch missing items from different peers:", err)
-			return err
+			return totalReconciled, err
 		}
-
-		pvtDataToCommit := r.preparePvtDataToCommit(fetchedData.AvailableElements)
-		unreconciled := constructUnreconciledMissingData(dig2collectionCfg, fetchedData.AvailableElements)
-		pvtdataHashMismatch, err := r.CommitPvtDataOfOldBlocks(pvtDataToCommit, unreconciled)
-		if err != nil {
-			return errors.Wrap(err, "failed to commit private data")
-		}
-		r.logMismatched(pvtdataHashMismatch)
 		if minB < minBlock {
 			minBlock = minB
 		}
 		if maxB > maxBlock {
 			maxBlock = maxB
 		}
-		totalReconciled += len(fetchedData.AvailableElements)
 	}
 }
 
+// reconcileMissingPvtData fetches the missing private data from the other peers and commits it,
+// returning the number of the items reconciled along with the range of the blocks
+func (r *Reconciler) reconcileMissingPvtData(missingPvtDataInfo ledger.MissingPvtDataInfo) (int, uint64, uint64, error) {
+	dig2collectionCfg, minB, maxB := r.getDig2CollectionConfig(missingPvtDataInfo)
+	fetchedData, err := r.FetchReconciledItems(dig2collectionCfg)
+	if err != nil {
+		r.logger.Error("reconciliation error when trying to fetch missing items from different peers:", err)
+		return 0, minB, maxB, err
+	}
+
+	pvtDataToCommit := r.preparePvtDataToCommit(fetchedData.AvailableElements)
+	unreconciled := constructUnreconciledMissingData(dig2collectionCfg, fetchedData.AvailableElements)
+	pvtdataHashMismatch, err := r.CommitPvtDataOfOldBlocks(pvtDataToCommit, unreconciled)
+	if err != nil {
+		return 0, minB, maxB, errors.Wrap(err, "failed to commit private data")
+	}
+	r.logMismatched(pvtdataHashMismatch)
+	return len(fetchedData.AvailableElements), minB, maxB, nil
+}
+
+func (r *Reconciler) missingPvtDataTracker() (ledger.MissingPvtDataTracker, error) {
+	missingPvtDataTracker, err := r.GetMissingPvtDataTracker()
+	if err != nil {
+		r.logger.Error("reconciliation error when trying to get missingPvtDataTracker:", err)
+		return nil, err
+	}
+	if missingPvtDataTracker == nil {
+		r.logger.Error("got nil as MissingPvtDataTracker, exiting...")
+		return nil, errors.New("got nil as MissingPvtDataTracker, exiting...")
+	}
+	return missingPvtDataTracker, nil
+}
+
+func (r *Reconciler) startAttempt() time.Time {
+	startTime := time.Now()
+	r.metrics.ReconciliationLastAttempt.With("channel", r.channel).Set(float64(startTime.Unix()))
+	return startTime
+}
+
+func (r *Reconciler) endAttempt(startTime time.Time, reconciled int, err error) {
+	r.metrics.ReconciledElements.With("channel", r.channel).Add(float64(reconciled))
+
+	r.statusLock.Lock()
+	defer r.statusLock.Unlock()
+	r.status.LastAttempt = startTime
+	r.status.Reconciled += uint64(reconciled)
+	r.status.LastAttemptError = ""
+	if err != nil {
+		r.status.LastAttemptError = err.Error()
+	}
+}
+
+// updateMissingCounts refreshes the number of the private data elements missing on the peer
+func (r *Reconciler) updateMissingCounts() {
+	missingPvtDataTracker, err := r.missingPvtDataTracker()
+	if err != nil {
+		return
+	}
+	counts, err := missingPvtDataTracker.GetMissingPvtDataCounts()
+	if err != nil {
+		r.logger.Warningf("Failed to count the missing private data: %s", err)
+		return
+	}
+	r.metrics.MissingEligible.With("channel", r.channel).Set(float64(counts.Eligible))
+	r.metrics.MissingIneligible.With("channel", r.channel).Set(float64(counts.Ineligible))
+
+	r.statusLock.Lock()
+	defer r.statusLock.Unlock()
+	r.status.MissingEligible = counts.Eligible
+	r.status.MissingIneligible = counts.Ineligible
+}
+
 func (r *Reconciler) reportReconciliationDuration(startTime time.Time) {
 	r.metrics.ReconciliationDuration.With("channel", r.channel).Observe(time.Since(startTime).Seconds())
 }
@@ -8,6 +8,7 @@ package privdata
 
 import (
 	"errors"
+	"math"
 	"sync"
 	"testing"
 	"time"
@@ -235,6 +236,7 @@ func TestReconciliationHappyPathWithScheduler(t *testing.T) {
 
 	missingPvtDataTracker.On("GetMissingPvtDataInfoForMostRecentBlocks", mock.Anything).Return(missingInfo, nil).Run(func(_ mock.Arguments) {
 		missingPvtDataTracker.Mock = mock.Mock{}
+		missingPvtDataTracker.On("GetMissingPvtDataCounts").Return(&ledger.MissingPvtDataCounts{}, nil)
 		missingPvtDataTracker.On("GetMissingPvtDataInfoForMostRecentBlocks", mock.Anything).Return(nil, nil)
 	})
 	configHistoryRetriever.On("MostRecentCollectionConfigBelow", mock.Anything, mock.Anything).Return(&collectionConfigInfo, nil)
@@ -349,6 +351,7 @@ func TestReconciliationPullingMissingPrivateDataAtOnePass(t *testing.T) {
 			// will go into same round
 			<-nextC
 			missingPvtDataTracker.Mock = mock.Mock{}
+			missingPvtDataTracker.On("GetMissingPvtDataCounts").Return(&ledger.MissingPvtDataCounts{}, nil)
 			missingPvtDataTracker.On("GetMissingPvtDataInfoForMostRecentBlocks", mock.Anything).
 				Return(nil, nil)
 		})
@@ -542,6 +545,186 @@ func TestFailuresWhileReconcilingMissingPvtData(t *testing.T) {
 	assert.Contains(t, "failed get missing pvt data for recent blocks", err.Error())
 }
 
+func TestReconciliationPrioritized(t *testing.T) {
+	// Scenario: the missing private data of the most recent blocks and of the priority
+	// collections is reconciled before the missing private data of the other collections.
+	committer := &mocks.Committer{}
+	fetcher := &mocks.ReconciliationFetcher{}
+	configHistoryRetriever := &mocks.ConfigHistoryRetriever{}
+	missingPvtDataTracker := &mocks.MissingPvtDataTracker{}
+
+	collectionConfigInfo := &ledger.CollectionConfigInfo{
+		CollectionConfig: &peer.CollectionConfigPackage{
+			Config: []*peer.CollectionConfig{
+				{Payload: &peer.CollectionConfig_StaticCollectionConfig{
+					StaticCollectionConfig: &peer.StaticCollectionConfig{Name: "col1"},
+				}},
+				{Payload: &peer.CollectionConfig_StaticCollectionConfig{
+					StaticCollectionConfig: &peer.StaticCollectionConfig{Name: "col2"},
+				}},
+			},
+		},
+	}
+
+	committer.On("LedgerHeight").Return(uint64(11), nil)
+	committer.On("GetMissingPvtDataTracker").Return(missingPvtDataTracker, nil)
+	committer.On("GetConfigHistoryRetriever").Return(configHistoryRetriever, nil)
+	configHistoryRetriever.On("MostRecentCollectionConfigBelow", mock.Anything, mock.Anything).Return(collectionConfigInfo, nil)
+
+	priorityCollections := ledger.NewPvtNsCollFilter()
+	priorityCollections.Add("ns1", "col2")
+
+	// most recent blocks
+	missingPvtDataTracker.On("GetMissingPvtDataInfoForBlockRange", uint64(9), uint64(10), 5, ledger.PvtNsCollFilter(nil)).Return(ledger.MissingPvtDataInfo{
+		10: {1: {{Namespace: "ns1", Collection: "col1"}}},
+	}, nil).Once()
+	// priority collections, looked up by collection in the ledger
+	missingPvtDataTracker.On("GetMissingPvtDataInfoForBlockRange", uint64(0), uint64(math.MaxUint64), 5, priorityCollections).Return(ledger.MissingPvtDataInfo{
+		3: {2: {{Namespace: "ns1", Collection: "col2"}}},
+	}, nil).Once()
+	// regular reconciliation
+	missingPvtDataTracker.On("GetMissingPvtDataInfoForMostRecentBlocks", 5).Return(ledger.MissingPvtDataInfo{
+		3: {1: {{Namespace: "ns1", Collection: "col1"}}},
+	}, nil).Once()
+	missingPvtDataTracker.On("GetMissingPvtDataInfoForMostRecentBlocks", 5).Return(nil, nil).Once()
+
+	var fetched [][]privdatacommon.DigKey
+	fetcher.On("FetchReconciledItems", mock.Anything).Return(func(dig2CollectionConfig privdatacommon.Dig2CollectionConfig) *privdatacommon.FetchedPvtDataContainer {
+		result := &privdatacommon.FetchedPvtDataContainer{}
+		var digests []privdatacommon.DigKey
+		for digest := range dig2CollectionConfig {
+			digests = append(digests, digest)
+			result.AvailableElements = append(result.AvailableElements, &gossip2.PvtDataElement{
+				Digest: &gossip2.PvtDataDigest{
+					BlockSeq:   digest.BlockSeq,
+					Collection: digest.Collection,
+					Namespace:  digest.Namespace,
+					SeqInBlock: digest.SeqInBlock,
+				},
+				Payload: [][]byte{[]byte("rws-pre-image")},
+			})
+		}
+		fetched = append(fetched, digests)
+		return result
+	}, nil)
+	committer.On("CommitPvtDataOfOldBlocks", mock.Anything, mock.Anything).Return(nil, nil)
+
+	r := &Reconciler{
+		channel:                "mychannel",
+		logger:                 logger.With("channel", "mychannel"),
+		metrics:                metrics.NewGossipMetrics(&disabled.Provider{}).PrivdataMetrics,
+		ReconcileSleepInterval: time.Minute,
+		ReconcileBatchSize:     5,
+		ReconcileRecentBlocks:  2,
+		PriorityCollections:    priorityCollections,
+		ReconciliationFetcher:  fetcher,
+		Committer:              committer,
+	}
+	require.NoError(t, r.reconcile())
+	missingPvtDataTracker.AssertExpectations(t)
+
+	require.Equal(t, [][]privdatacommon.DigKey{
+		{{Namespace: "ns1", Collection: "col1", BlockSeq: 10, SeqInBlock: 1}},
+		{{Namespace: "ns1", Collection: "col2", BlockSeq: 3, SeqInBlock: 2}},
+		{{Namespace: "ns1", Collection: "col1", BlockSeq: 3, SeqInBlock: 1}},
+	}, fetched)
+
+	status := r.Status()
+	require.Equal(t, uint64(3), status.Reconciled)
+	require.Empty(t, status.LastAttemptError)
+}
+
+func TestReconcileBlockRange(t *testing.T) {
+	committer := &mocks.Committer{}
+	fetcher := &mocks.ReconciliationFetcher{}
+	configHistoryRetriever := &mocks.ConfigHistoryRetriever{}
+	missingPvtDataTracker := &mocks.MissingPvtDataTracker{}
+
+	collectionConfigInfo := &ledger.CollectionConfigInfo{
+		CollectionConfig: &peer.CollectionConfigPackage{
+			Config: []*peer.CollectionConfig{
+				{Payload: &peer.CollectionConfig_StaticCollectionConfig{
+					StaticCollectionConfig: &peer.StaticCollectionConfig{Name: "col1"},
+				}},
+			},
+		},
+	}
+
+	committer.On("GetMissingPvtDataTracker").Return(missingPvtDataTracker, nil)
+	committer.On("GetConfigHistoryRetriever").Return(configHistoryRetriever, nil)
+	configHistoryRetriever.On("MostRecentCollectionConfigBelow", mock.Anything, mock.Anything).Return(collectionConfigInfo, nil)
+
+	// the range is retrieved a block at a time, from the most recent block down to the start of the range
+	missingPvtDataTracker.On("GetMissingPvtDataInfoForBlockRange", uint64(2), uint64(8), 1, ledger.PvtNsCollFilter(nil)).Return(ledger.MissingPvtDataInfo{
+		7: {1: {{Namespace: "ns1", Collection: "col1"}}},
+	}, nil).Once()
+	missingPvtDataTracker.On("GetMissingPvtDataInfoForBlockRange", uint64(2), uint64(6), 1, ledger.PvtNsCollFilter(nil)).Return(ledger.MissingPvtDataInfo{
+		2: {
+			1: {{Namespace: "ns1", Collection: "col1"}},
+			3: {{Namespace: "ns1", Collection: "col1"}},
+		},
+	}, nil).Once()
+	missingPvtDataTracker.On("GetMissingPvtDataCounts").Return(&ledger.MissingPvtDataCounts{Eligible: 4, Ineligible: 2}, nil)
+
+	fetcher.On("FetchReconciledItems", mock.Anything).Return(func(dig2CollectionConfig privdatacommon.Dig2CollectionConfig) *privdatacommon.FetchedPvtDataContainer {
+		result := &privdatacommon.FetchedPvtDataContainer{}
+		for digest := range dig2CollectionConfig {
+			result.AvailableElements = append(result.AvailableElements, &gossip2.PvtDataElement{
+				Digest: &gossip2.PvtDataDigest{
+					BlockSeq:   digest.BlockSeq,
+					Collection: digest.Collection,
+					Namespace:  digest.Namespace,
+					SeqInBlock: digest.SeqInBlock,
+				},
+				Payload: [][]byte{[]byte("rws-pre-image")},
+			})
+		}
+		return result
+	}, nil)
+	committer.On("CommitPvtDataOfOldBlocks", mock.Anything, mock.Anything).Return(nil, nil)
+
+	testMetricProvider := gmetricsmocks.TestUtilConstructMetricProvider()
+	r := NewReconciler("mychannel", metrics.NewGossipMetrics(testMetricProvider.FakeProvider).PrivdataMetrics, committer, fetcher,
+		&PrivdataConfig{ReconcileSleepInterval: time.Minute, ReconcileBatchSize: 1, ReconciliationEnabled: true})
+
+	_, err := r.ReconcileBlockRange(8, 2)
+	require.EqualError(t, err, "invalid block range [8 - 2]")
+
+	reconciled, err := r.ReconcileBlockRange(2, 8)
+	require.NoError(t, err)
+	require.Equal(t, 3, reconciled)
+	missingPvtDataTracker.AssertExpectations(t)
+
+	status := r.Status()
+	require.Equal(t, "mychannel", status.Channel)
+	require.True(t, status.Enabled)
+	require.Equal(t, uint64(3), status.Reconciled)
+	require.Equal(t, uint64(4), status.MissingEligible)
+	require.Equal(t, uint64(2), status.MissingIneligible)
+	require.False(t, status.LastAttempt.IsZero())
+	require.Empty(t, status.LastAttemptError)
+
+	require.Equal(t, 1, testMetricProvider.FakeReconciledElements.AddCallCount())
+	require.Equal(t, float64(3), testMetricProvider.FakeReconciledElements.AddArgsForCall(0))
+	require.Equal(t, float64(4), testMetricProvider.FakeMissingEligible.SetArgsForCall(0))
+	require.Equal(t, float64(2), testMetricProvider.FakeMissingIneligible.SetArgsForCall(0))
+	require.Equal(t, []string{"channel", "mychannel"}, testMetricProvider.FakeReconciliationLastAttempt.WithArgsForCall(0))
+
+	// a failed attempt is reported in the status
+	committer.Mock = mock.Mock{}
+	committer.On("GetMissingPvtDataTracker").Return(nil, errors.New("failed to obtain missing pvt data tracker"))
+	_, err = r.ReconcileBlockRange(2, 8)
+	require.EqualError(t, err, "failed to obtain missing pvt data tracker")
+	require.Equal(t, "failed to obtain missing pvt data tracker", r.Status().LastAttemptError)
+}
+
+func TestNoOpReconciler(t *testing.T) {
+	r := &NoOpReconciler{}
+	require.False(t, r.Status().Enabled)
+	_, err := r.ReconcileBlockRange(1, 2)
+	require.EqualError(t, err, "private data reconciliation is disabled")
+}
+
 func TestConstructUnreconciledMissingData(t *testing.T) {
 	requestedMissingData := privdatacommon.Dig2CollectionConfig{
 		privdatacommon.DigKey{
@@ -0,0 +1,143 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// ReconciliationURL is the path of the operations endpoint of the private data reconciliation
+const ReconciliationURL = "/privdata/reconciliation"
+
+// ReconcilerProvider provides the private data reconcilers of the channels the peer joined
+type ReconcilerProvider interface {
+	// Reconcilers returns the reconcilers keyed by channel
+	Reconcilers() map[string]PvtDataReconciler
+}
+
+// ReconcileRequest is the body of a request to reconcile the missing private data
+// of a block range right away
+type ReconcileRequest struct {
+	Channel    string `json:"channel"`
+	StartBlock uint64 `json:"startBlock"`
+	EndBlock   uint64 `json:"endBlock"`
+}
+
+// ReconcileResponse reports the number of private data elements reconciled
+// in response to a ReconcileRequest
+type ReconcileResponse struct {
+	Channel    string `json:"channel"`
+	Reconciled int    `json:"reconciled"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// ReconciliationHandler serves the status of the private data reconciliation of the
+// channels on GET, optionally restricted to a channel by the 'channel' query parameter,
+// and reconciles the private data of a block range on POST.
+type ReconciliationHandler struct {
+	Provider ReconcilerProvider
+}
+
+// NewReconciliationHandler creates a new ReconciliationHandler
+func NewReconciliationHandler(provider ReconcilerProvider) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		Provider: provider,
+	}
+}
+
+func (h *ReconciliationHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		h.serveStatus(resp, req)
+
+	case http.MethodPost:
+		h.serveReconcile(resp, req)
+
+	default:
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		h.sendResponse(resp, http.StatusMethodNotAllowed, err)
+	}
+}
+
+func (h *ReconciliationHandler) serveStatus(resp http.ResponseWriter, req *http.Request) {
+	reconcilers := h.Provider.Reconcilers()
+
+	if channel := req.URL.Query().Get("channel"); channel != "" {
+		reconciler, ok := reconcilers[channel]
+		if !ok {
+			h.sendResponse(resp, http.StatusNotFound, fmt.Errorf("channel %s not found", channel))
+			return
+		}
+		h.sendResponse(resp, http.StatusOK, channelStatus(channel, reconciler))
+		return
+	}
+
+	statuses := []ReconciliationStatus{}
+	for channel, reconciler := range reconcilers {
+		statuses = append(statuses, channelStatus(channel, reconciler))
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Channel < statuses[j].Channel })
+	h.sendResponse(resp, http.StatusOK, statuses)
+}
+
+func (h *ReconciliationHandler) serveReconcile(resp http.ResponseWriter, req *http.Request) {
+	var reconcileReq ReconcileRequest
+	decoder := json.NewDecoder(req.Body)
+	if err := decoder.Decode(&reconcileReq); err != nil {
+		h.sendResponse(resp, http.StatusBadRequest, err)
+		return
+	}
+	req.Body.Close()
+
+	if reconcileReq.Channel == "" {
+		h.sendResponse(resp, http.StatusBadRequest, fmt.Errorf("channel is required"))
+		return
+	}
+	if reconcileReq.StartBlock > reconcileReq.EndBlock {
+		err := fmt.Errorf("invalid block range [%d - %d]", reconcileReq.StartBlock, reconcileReq.EndBlock)
+		h.sendResponse(resp, http.StatusBadRequest, err)
+		return
+	}
+	reconciler, ok := h.Provider.Reconcilers()[reconcileReq.Channel]
+	if !ok {
+		h.sendResponse(resp, http.StatusNotFound, fmt.Errorf("channel %s not found", reconcileReq.Channel))
+		return
+	}
+
+	reconciled, err := reconciler.ReconcileBlockRange(reconcileReq.StartBlock, reconcileReq.EndBlock)
+	if err != nil {
+		h.sendResponse(resp, http.StatusInternalServerError, err)
+		return
+	}
+	h.sendResponse(resp, http.StatusOK, &ReconcileResponse{Channel: reconcileReq.Channel, Reconciled: reconciled})
+}
+
+func channelStatus(channel string, reconciler PvtDataReconciler) ReconciliationStatus {
+	status := reconciler.Status()
+	status.Channel = channel
+	return status
+}
+
+func (h *ReconciliationHandler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	encoder := json.NewEncoder(resp)
+	if err, ok := payload.(error); ok {
+		payload = &ErrorResponse{Error: err.Error()}
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+
+	if err := encoder.Encode(payload); err != nil {
+		logger.Errorf("failed to encode payload: %s", err)
+	}
+}
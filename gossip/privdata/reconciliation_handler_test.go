@@ -0,0 +1,135 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type reconcilers map[string]PvtDataReconciler
+
+func (r reconcilers) Reconcilers() map[string]PvtDataReconciler {
+	return r
+}
+
+type fakeReconciler struct {
+	NoOpReconciler
+	status          ReconciliationStatus
+	reconciled      int
+	err             error
+	reconciledRange [2]uint64
+}
+
+func (f *fakeReconciler) Status() ReconciliationStatus {
+	return f.status
+}
+
+func (f *fakeReconciler) ReconcileBlockRange(startBlock, endBlock uint64) (int, error) {
+	f.reconciledRange = [2]uint64{startBlock, endBlock}
+	return f.reconciled, f.err
+}
+
+func TestReconciliationHandlerStatus(t *testing.T) {
+	lastAttempt := time.Unix(1600000000, 0).UTC()
+	handler := NewReconciliationHandler(reconcilers{
+		"mychannel": &fakeReconciler{
+			status: ReconciliationStatus{
+				Enabled:           true,
+				MissingEligible:   3,
+				MissingIneligible: 1,
+				Reconciled:        5,
+				LastAttempt:       lastAttempt,
+			},
+		},
+		"another-channel": &NoOpReconciler{},
+	})
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, ReconciliationURL, nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	var statuses []ReconciliationStatus
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &statuses))
+	require.Equal(t, []ReconciliationStatus{
+		{Channel: "another-channel"},
+		{
+			Channel:           "mychannel",
+			Enabled:           true,
+			MissingEligible:   3,
+			MissingIneligible: 1,
+			Reconciled:        5,
+			LastAttempt:       lastAttempt,
+		},
+	}, statuses)
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, ReconciliationURL+"?channel=mychannel", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `{"channel":"mychannel","enabled":true,"missingEligible":3,"missingIneligible":1,"reconciled":5,"lastAttempt":"2020-09-13T12:26:40Z"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, ReconciliationURL+"?channel=unknown", nil))
+	require.Equal(t, http.StatusNotFound, resp.Code)
+	require.JSONEq(t, `{"error":"channel unknown not found"}`, resp.Body.String())
+}
+
+func TestReconciliationHandlerReconcile(t *testing.T) {
+	reconciler := &fakeReconciler{reconciled: 4}
+	handler := NewReconciliationHandler(reconcilers{"mychannel": reconciler})
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, ReconciliationURL, strings.NewReader(`{"channel":"mychannel","startBlock":2,"endBlock":10}`))
+	handler.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `{"channel":"mychannel","reconciled":4}`, resp.Body.String())
+	require.Equal(t, [2]uint64{2, 10}, reconciler.reconciledRange)
+
+	reconciler.err = errors.New("fetch failed")
+	resp = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, ReconciliationURL, strings.NewReader(`{"channel":"mychannel","startBlock":2,"endBlock":10}`))
+	handler.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusInternalServerError, resp.Code)
+	require.JSONEq(t, `{"error":"fetch failed"}`, resp.Body.String())
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+		expectedErr  string
+	}{
+		{"bad body", `{"channel":`, http.StatusBadRequest, "unexpected EOF"},
+		{"missing channel", `{"startBlock":2,"endBlock":10}`, http.StatusBadRequest, "channel is required"},
+		{"invalid range", `{"channel":"mychannel","startBlock":10,"endBlock":2}`, http.StatusBadRequest, "invalid block range [10 - 2]"},
+		{"unknown channel", `{"channel":"unknown","startBlock":2,"endBlock":10}`, http.StatusNotFound, "channel unknown not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, ReconciliationURL, strings.NewReader(tt.body)))
+			require.Equal(t, tt.expectedCode, resp.Code)
+			var errResp ErrorResponse
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errResp))
+			require.Equal(t, tt.expectedErr, errResp.Error)
+		})
+	}
+}
+
+func TestReconciliationHandlerInvalidMethod(t *testing.T) {
+	handler := NewReconciliationHandler(reconcilers{})
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, ReconciliationURL, nil))
+	require.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	require.JSONEq(t, `{"error":"invalid request method: DELETE"}`, resp.Body.String())
+}
@@ -16,6 +16,7 @@ import (
 const (
 	btlPullMarginDefault           = 10
 	transientBlockRetentionDefault = 1000
+	electionMaxLeadersDefault      = 1
 )
 
 // ServiceConfig is the config struct for gossip services
@@ -40,6 +41,11 @@ type ServiceConfig struct {
 	// ElectionLeaderElectionDuration is the time passes since last declaration message before peer decides to perform
 	// leader election (unit: second).
 	ElectionLeaderElectionDuration time.Duration
+	// ElectionStrategy is the strategy by which leader election ranks the candidates for leadership:
+	// lowestID, leastLoaded or lowestLatency.
+	ElectionStrategy string
+	// ElectionMaxLeaders is the number of peers of the organization that lead a channel concurrently.
+	ElectionMaxLeaders int
 	// PvtDataPullRetryThreshold determines the maximum duration of time private data corresponding for
 	// a given block.
 	PvtDataPullRetryThreshold time.Duration
@@ -77,6 +83,20 @@ func (c *ServiceConfig) loadGossipConfig() {
 	c.ElectionLeaderAliveThreshold = util.GetDurationOrDefault("peer.gossip.election.leaderAliveThreshold", election.DefLeaderAliveThreshold)
 	c.ElectionLeaderElectionDuration = util.GetDurationOrDefault("peer.gossip.election.leaderElectionDuration", election.DefLeaderElectionDuration)
 
+	c.ElectionStrategy = viper.GetString("peer.gossip.election.strategy")
+	switch c.ElectionStrategy {
+	case LowestIDElectionStrategy, LeastLoadedElectionStrategy, LowestLatencyElectionStrategy:
+	case "":
+		c.ElectionStrategy = LowestIDElectionStrategy
+	default:
+		logger.Warningf("Unknown leader election strategy %s, defaulting to %s", c.ElectionStrategy, LowestIDElectionStrategy)
+		c.ElectionStrategy = LowestIDElectionStrategy
+	}
+	c.ElectionMaxLeaders = viper.GetInt("peer.gossip.election.maxLeaders")
+	if c.ElectionMaxLeaders < 1 {
+		c.ElectionMaxLeaders = electionMaxLeadersDefault
+	}
+
 	c.PvtDataPushAckTimeout = viper.GetDuration("peer.gossip.pvtData.pushAckTimeout")
 	c.PvtDataPullRetryThreshold = viper.GetDuration("peer.gossip.pvtData.pullRetryThreshold")
 	c.SkipPullingInvalidTransactionsDuringCommit = viper.GetBool("peer.gossip.pvtData.skipPullingInvalidTransactionsDuringCommit")
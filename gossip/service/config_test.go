@@ -28,6 +28,8 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("peer.gossip.orgLeader", true)
 	viper.Set("peer.gossip.election.leaderAliveThreshold", "10m")
 	viper.Set("peer.gossip.election.leaderElectionDuration", "5s")
+	viper.Set("peer.gossip.election.strategy", "leastLoaded")
+	viper.Set("peer.gossip.election.maxLeaders", 2)
 	viper.Set("peer.gossip.pvtData.btlPullMargin", 15)
 	viper.Set("peer.gossip.pvtData.transientstoreMaxBlockRetention", 1000)
 	viper.Set("peer.gossip.pvtData.skipPullingInvalidTransactionsDuringCommit", false)
@@ -46,6 +48,8 @@ func TestGlobalConfig(t *testing.T) {
 		ElectionLeaderElectionDuration:             5 * time.Second,
 		ElectionStartupGracePeriod:                 election.DefStartupGracePeriod,
 		ElectionMembershipSampleInterval:           election.DefMembershipSampleInterval,
+		ElectionStrategy:                           service.LeastLoadedElectionStrategy,
+		ElectionMaxLeaders:                         2,
 		BtlPullMargin:                              15,
 		TransientstoreMaxBlockRetention:            uint64(1000),
 		SkipPullingInvalidTransactionsDuringCommit: false,
@@ -53,3 +57,16 @@ func TestGlobalConfig(t *testing.T) {
 
 	assert.Equal(t, coreConfig, expectedConfig)
 }
+
+func TestGlobalConfigElectionStrategyDefaults(t *testing.T) {
+	viper.Reset()
+	coreConfig := service.GlobalConfig()
+	assert.Equal(t, service.LowestIDElectionStrategy, coreConfig.ElectionStrategy)
+	assert.Equal(t, 1, coreConfig.ElectionMaxLeaders)
+
+	viper.Set("peer.gossip.election.strategy", "fastest")
+	viper.Set("peer.gossip.election.maxLeaders", -1)
+	coreConfig = service.GlobalConfig()
+	assert.Equal(t, service.LowestIDElectionStrategy, coreConfig.ElectionStrategy)
+	assert.Equal(t, 1, coreConfig.ElectionMaxLeaders)
+}
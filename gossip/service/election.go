@@ -0,0 +1,176 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"time"
+
+	gossipcommon "github.com/osdi23p228/fabric/gossip/common"
+	"github.com/osdi23p228/fabric/gossip/election"
+	"github.com/osdi23p228/fabric/internal/pkg/peer/orderers"
+)
+
+// Leader election strategies
+const (
+	// LowestIDElectionStrategy elects the peers with the lowest PKI-IDs
+	LowestIDElectionStrategy = "lowestID"
+	// LeastLoadedElectionStrategy elects the peers leading the fewest other channels
+	LeastLoadedElectionStrategy = "leastLoaded"
+	// LowestLatencyElectionStrategy elects the peers with the lowest latency to the ordering service
+	LowestLatencyElectionStrategy = "lowestLatency"
+)
+
+// electionScorer periodically computes the scores of the peer for leading
+// the channels it runs leader election for, and publishes them to the other
+// peers in the metadata of its alive messages
+type electionScorer struct {
+	strategy string
+	interval time.Duration
+	publish  func(metadata []byte)
+	// leading returns whether the peer leads each of the channels it runs leader election for
+	leading func() map[string]bool
+
+	lock     sync.Mutex
+	sources  map[string]*orderers.ConnectionSource
+	scores   map[string]uint64
+	stopOnce sync.Once
+	stopChan chan struct{}
+}
+
+func newElectionScorer(strategy string, interval time.Duration, publish func([]byte), leading func() map[string]bool) *electionScorer {
+	return &electionScorer{
+		strategy: strategy,
+		interval: interval,
+		publish:  publish,
+		leading:  leading,
+		sources:  make(map[string]*orderers.ConnectionSource),
+		scores:   make(map[string]uint64),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// addChannel starts scoring the peer for leading the given channel
+func (s *electionScorer) addChannel(channelID string, source *orderers.ConnectionSource) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.sources[channelID] = source
+}
+
+// score returns the last score computed for leading the given channel
+func (s *electionScorer) score(channelID string) (uint64, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	score, exists := s.scores[channelID]
+	return score, exists
+}
+
+func (s *electionScorer) run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.update()
+		select {
+		case <-ticker.C:
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+func (s *electionScorer) update() {
+	s.lock.Lock()
+	sources := make(map[string]*orderers.ConnectionSource, len(s.sources))
+	for channelID, source := range s.sources {
+		sources[channelID] = source
+	}
+	s.lock.Unlock()
+
+	scores := make(map[string]uint64, len(sources))
+	switch s.strategy {
+	case LeastLoadedElectionStrategy:
+		leading := s.leading()
+		for channelID := range sources {
+			var load uint64
+			for otherChannel, isLeader := range leading {
+				if isLeader && otherChannel != channelID {
+					load++
+				}
+			}
+			scores[channelID] = load
+		}
+	case LowestLatencyElectionStrategy:
+		for channelID, source := range sources {
+			latency, err := ordererLatency(source, s.interval)
+			if err != nil {
+				logger.Debugf("Failed measuring the latency to the ordering service of channel %s: %v", channelID, err)
+				continue
+			}
+			scores[channelID] = uint64(latency / time.Microsecond)
+		}
+	}
+
+	s.lock.Lock()
+	s.scores = scores
+	s.lock.Unlock()
+	s.publish(election.EncodeScores(scores))
+}
+
+func (s *electionScorer) stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+	})
+}
+
+// ordererLatency returns the time it takes to open a connection
+// to a random orderer of the given source
+func ordererLatency(source *orderers.ConnectionSource, timeout time.Duration) (time.Duration, error) {
+	endpoint, err := source.RandomEndpoint()
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", endpoint.Address, timeout)
+	if err != nil {
+		return 0, err
+	}
+	latency := time.Since(start)
+	conn.Close()
+	return latency, nil
+}
+
+// electionStrategy returns the strategy the leader election of the given channel ranks peers by
+func (g *GossipService) electionStrategy(channelID string, self gossipcommon.PKIidType) election.Strategy {
+	maxLeaders := g.serviceConfig.ElectionMaxLeaders
+	if g.electionScorer == nil {
+		return election.NewLowestIDStrategy(maxLeaders)
+	}
+	return election.NewLowestScoreStrategy(maxLeaders, func(id []byte) (uint64, bool) {
+		if bytes.Equal(id, self) {
+			return g.electionScorer.score(channelID)
+		}
+		for _, member := range g.PeersOfChannel(gossipcommon.ChannelID(channelID)) {
+			if bytes.Equal(member.PKIid, id) {
+				return election.ScoreFromMetadata(member.Metadata, channelID)
+			}
+		}
+		return 0, false
+	})
+}
+
+// leadingChannels returns whether the peer leads each of the channels it runs leader election for
+func (g *GossipService) leadingChannels() map[string]bool {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	leading := make(map[string]bool, len(g.leaderElection))
+	for channelID, le := range g.leaderElection {
+		leading[channelID] = le.IsLeader()
+	}
+	return leading
+}
@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/osdi23p228/fabric/common/flogging"
+	"github.com/osdi23p228/fabric/gossip/common"
+	"github.com/osdi23p228/fabric/gossip/discovery"
+	"github.com/osdi23p228/fabric/gossip/election"
+	"github.com/osdi23p228/fabric/internal/pkg/peer/orderers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElectionScorerLeastLoaded(t *testing.T) {
+	var published []byte
+	leading := map[string]bool{"A": true, "B": true, "C": false}
+	scorer := newElectionScorer(LeastLoadedElectionStrategy, time.Second, func(md []byte) {
+		published = md
+	}, func() map[string]bool {
+		return leading
+	})
+	for _, channelID := range []string{"A", "B", "C"} {
+		scorer.addChannel(channelID, nil)
+	}
+
+	scorer.update()
+
+	for channelID, expected := range map[string]uint64{"A": 1, "B": 1, "C": 2} {
+		score, exists := scorer.score(channelID)
+		assert.True(t, exists)
+		assert.Equal(t, expected, score)
+		score, exists = election.ScoreFromMetadata(published, channelID)
+		assert.True(t, exists)
+		assert.Equal(t, expected, score)
+	}
+}
+
+func TestElectionScorerLowestLatency(t *testing.T) {
+	lsnr, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lsnr.Close()
+
+	reachable := orderers.NewConnectionSource(flogging.MustGetLogger("test"), nil)
+	reachable.Update([]string{lsnr.Addr().String()}, nil)
+	unreachable := orderers.NewConnectionSource(flogging.MustGetLogger("test"), nil)
+
+	var published []byte
+	scorer := newElectionScorer(LowestLatencyElectionStrategy, time.Second, func(md []byte) {
+		published = md
+	}, nil)
+	scorer.addChannel("A", reachable)
+	scorer.addChannel("B", unreachable)
+
+	scorer.update()
+
+	_, exists := scorer.score("A")
+	assert.True(t, exists)
+	_, exists = election.ScoreFromMetadata(published, "A")
+	assert.True(t, exists)
+	_, exists = scorer.score("B")
+	assert.False(t, exists)
+}
+
+type electionGossipMock struct {
+	*gossipMock
+	peersOfChannel map[string][]discovery.NetworkMember
+}
+
+func (g *electionGossipMock) PeersOfChannel(channel common.ChannelID) []discovery.NetworkMember {
+	return g.peersOfChannel[string(channel)]
+}
+
+func TestElectionStrategy(t *testing.T) {
+	g := &GossipService{
+		gossipSvc: &electionGossipMock{
+			peersOfChannel: map[string][]discovery.NetworkMember{
+				"A": {
+					{PKIid: common.PKIidType("p1"), Metadata: election.EncodeScores(map[string]uint64{"A": 3})},
+					{PKIid: common.PKIidType("p2"), Metadata: election.EncodeScores(map[string]uint64{"A": 1})},
+					{PKIid: common.PKIidType("p3")},
+				},
+			},
+		},
+		serviceConfig: &ServiceConfig{ElectionMaxLeaders: 2},
+	}
+
+	// Without scores, peers are ranked by their IDs
+	strategy := g.electionStrategy("A", common.PKIidType("self"))
+	assert.Equal(t, 2, strategy.MaxLeaders())
+	assert.True(t, strategy.Less([]byte("p2"), []byte("p3")))
+
+	g.electionScorer = newElectionScorer(LeastLoadedElectionStrategy, time.Second, func([]byte) {}, func() map[string]bool {
+		return map[string]bool{"A": false, "B": true}
+	})
+	g.electionScorer.addChannel("A", nil)
+	g.electionScorer.update()
+
+	// Peers are ranked by the scores they published, and the local score of the peer itself
+	strategy = g.electionStrategy("A", common.PKIidType("self"))
+	assert.True(t, strategy.Less([]byte("p2"), []byte("self")))
+	assert.True(t, strategy.Less([]byte("self"), []byte("p1")))
+	assert.True(t, strategy.Less([]byte("p1"), []byte("p3")))
+}
@@ -57,6 +57,9 @@ type gossipSvc interface {
 	// GetPeers returns the NetworkMembers considered alive
 	Peers() []discovery.NetworkMember
 
+	// DeadPeers returns the NetworkMembers presumed dead
+	DeadPeers() []discovery.NetworkMember
+
 	// PeersOfChannel returns the NetworkMembers considered alive
 	// and also subscribed to the channel given
 	PeersOfChannel(common.ChannelID) []discovery.NetworkMember
@@ -176,6 +179,7 @@ type GossipService struct {
 	serviceConfig     *ServiceConfig
 	privdataConfig    *gossipprivdata.PrivdataConfig
 	anchorPeerTracker *anchorPeerTracker
+	electionScorer    *electionScorer
 }
 
 // This is an implementation of api.JoinChannelMessage.
@@ -266,7 +270,7 @@ func New(
 		anchorPeerTracker,
 	)
 
-	return &GossipService{
+	gossipService := &GossipService{
 		gossipSvc:       gossipComponent,
 		mcs:             mcs,
 		privateHandlers: make(map[string]privateHandler),
@@ -285,7 +289,20 @@ func New(
 		serviceConfig:     serviceConfig,
 		privdataConfig:    privdataConfig,
 		anchorPeerTracker: anchorPeerTracker,
-	}, nil
+	}
+
+	scored := serviceConfig.ElectionStrategy == LeastLoadedElectionStrategy || serviceConfig.ElectionStrategy == LowestLatencyElectionStrategy
+	if serviceConfig.UseLeaderElection && scored {
+		gossipService.electionScorer = newElectionScorer(
+			serviceConfig.ElectionStrategy,
+			serviceConfig.ElectionLeaderAliveThreshold/2,
+			gossipComponent.UpdateMetadata,
+			gossipService.leadingChannels,
+		)
+		go gossipService.electionScorer.run()
+	}
+
+	return gossipService, nil
 }
 
 // DistributePrivateData distribute private read write set inside the channel based on the collections policies
@@ -407,6 +424,9 @@ func (g *GossipService) InitializeChannel(channelID string, ordererSource *order
 
 		if leaderElection {
 			logger.Debug("Delivery uses dynamic leader election mechanism, channel", channelID)
+			if g.electionScorer != nil {
+				g.electionScorer.addChannel(channelID, ordererSource)
+			}
 			g.leaderElection[channelID] = g.newLeaderElectionComponent(channelID, g.onStatusChangeFactory(channelID,
 				support.Committer), g.metrics.ElectionMetrics)
 		} else if isStaticOrgLeader {
@@ -470,6 +490,18 @@ func (g *GossipService) AddPayload(channelID string, payload *gproto.Payload) er
 	return g.chains[channelID].AddPayload(payload)
 }
 
+// Reconcilers returns the private data reconcilers of the channels, keyed by channel
+func (g *GossipService) Reconcilers() map[string]gossipprivdata.PvtDataReconciler {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	reconcilers := make(map[string]gossipprivdata.PvtDataReconciler, len(g.privateHandlers))
+	for channelID, handler := range g.privateHandlers {
+		reconcilers[channelID] = handler.reconciler
+	}
+	return reconcilers
+}
+
 // Stop stops the gossip component
 func (g *GossipService) Stop() {
 	g.lock.Lock()
@@ -488,6 +520,9 @@ func (g *GossipService) Stop() {
 			g.deliveryService[chainID].Stop()
 		}
 	}
+	if g.electionScorer != nil {
+		g.electionScorer.stop()
+	}
 	g.gossipSvc.Stop()
 }
 
@@ -500,6 +535,7 @@ func (g *GossipService) newLeaderElectionComponent(channelID string, callback fu
 		MembershipSampleInterval: g.serviceConfig.ElectionMembershipSampleInterval,
 		LeaderAliveThreshold:     g.serviceConfig.ElectionLeaderAliveThreshold,
 		LeaderElectionDuration:   g.serviceConfig.ElectionLeaderElectionDuration,
+		Strategy:                 g.electionStrategy(channelID, PKIid),
 	}
 	return election.NewLeaderElectionService(adapter, string(PKIid), callback, config)
 }
@@ -280,6 +280,13 @@ func TestWithStaticDeliverClientLeader(t *testing.T) {
 		assert.True(t, gossips[i].deliveryService[channelName].(*mockDeliverService).running[channelName], "Block deliverer not started for peer %d", i)
 	}
 
+	for i := 0; i < n; i++ {
+		reconcilers := gossips[i].Reconcilers()
+		assert.Len(t, reconcilers, 2)
+		assert.Contains(t, reconcilers, "chanA")
+		assert.Contains(t, reconcilers, "chanB")
+	}
+
 	stopPeers(gossips)
 }
 
@@ -437,6 +444,10 @@ func (li *mockLedgerInfo) DoesPvtDataInfoExistInLedger(blkNum uint64) (bool, err
 	return false, nil
 }
 
+func (li *mockLedgerInfo) TxIDExists(txID string) (bool, error) {
+	return false, nil
+}
+
 // Commit block to the ledger
 func (li *mockLedgerInfo) Commit(block *common.Block) error {
 	return nil
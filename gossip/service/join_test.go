@@ -66,6 +66,10 @@ func (*gossipMock) Peers() []discovery.NetworkMember {
 	panic("implement me")
 }
 
+func (*gossipMock) DeadPeers() []discovery.NetworkMember {
+	panic("implement me")
+}
+
 func (*gossipMock) PeersOfChannel(common.ChannelID) []discovery.NetworkMember {
 	panic("implement me")
 }
@@ -0,0 +1,222 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	gproto "github.com/hyperledger/fabric-protos-go/gossip"
+	gossipcommon "github.com/osdi23p228/fabric/gossip/common"
+	"github.com/osdi23p228/fabric/gossip/discovery"
+)
+
+// MembershipURL is the path of the operations endpoint of the gossip membership view
+const MembershipURL = "/gossip/membership"
+
+// Leader election modes reported in a ChannelView
+const (
+	DynamicLeaderElection = "dynamic"
+	StaticLeaderElection  = "static"
+	NoLeaderElection      = "none"
+)
+
+// MembershipView is the view the gossip layer of the peer has of the network
+type MembershipView struct {
+	Self       PeerInfo       `json:"self"`
+	Alive      []PeerInfo     `json:"alive"`
+	Dead       []PeerInfo     `json:"dead"`
+	Channels   []ChannelView  `json:"channels"`
+	Identities []IdentityInfo `json:"identities"`
+}
+
+// PeerInfo describes a peer known to the gossip layer
+type PeerInfo struct {
+	PKIID            string `json:"pkiID"`
+	MSPID            string `json:"mspID,omitempty"`
+	Endpoint         string `json:"endpoint"`
+	InternalEndpoint string `json:"internalEndpoint,omitempty"`
+}
+
+// ChannelView is the view the gossip layer of the peer has of a channel
+type ChannelView struct {
+	Channel        string        `json:"channel"`
+	LeaderElection string        `json:"leaderElection"`
+	Leaders        []string      `json:"leaders,omitempty"`
+	Self           ChannelPeer   `json:"self"`
+	Peers          []ChannelPeer `json:"peers"`
+}
+
+// ChannelPeer describes a peer of a channel, as published by the peer
+type ChannelPeer struct {
+	PeerInfo
+	LedgerHeight uint64          `json:"ledgerHeight"`
+	LeftChannel  bool            `json:"leftChannel,omitempty"`
+	Chaincodes   []ChaincodeInfo `json:"chaincodes,omitempty"`
+}
+
+// ChaincodeInfo describes a chaincode installed on a peer
+type ChaincodeInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// IdentityInfo describes a peer identity known to the gossip layer
+type IdentityInfo struct {
+	PKIID      string     `json:"pkiID"`
+	MSPID      string     `json:"mspID"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+}
+
+// MembershipView returns the view the gossip layer of the peer has of the network
+func (g *GossipService) MembershipView() *MembershipView {
+	identities := g.IdentityInfo()
+	orgs := make(map[string]string, len(identities))
+	view := &MembershipView{
+		Alive:      []PeerInfo{},
+		Dead:       []PeerInfo{},
+		Channels:   []ChannelView{},
+		Identities: []IdentityInfo{},
+	}
+	for _, identity := range identities {
+		orgs[string(identity.PKIId)] = string(identity.Organization)
+		info := IdentityInfo{
+			PKIID: identity.PKIId.String(),
+			MSPID: string(identity.Organization),
+		}
+		if expiration, err := g.mcs.Expiration(identity.Identity); err == nil && !expiration.IsZero() {
+			info.Expiration = &expiration
+		}
+		view.Identities = append(view.Identities, info)
+	}
+	sort.Slice(view.Identities, func(i, j int) bool { return view.Identities[i].PKIID < view.Identities[j].PKIID })
+
+	peerInfo := func(member discovery.NetworkMember) PeerInfo {
+		return PeerInfo{
+			PKIID:            member.PKIid.String(),
+			MSPID:            orgs[string(member.PKIid)],
+			Endpoint:         member.Endpoint,
+			InternalEndpoint: member.InternalEndpoint,
+		}
+	}
+
+	view.Self = peerInfo(g.SelfMembershipInfo())
+	view.Self.MSPID = string(g.secAdv.OrgByPeerIdentity(g.peerIdentity))
+	for _, member := range sortedMembers(g.Peers()) {
+		view.Alive = append(view.Alive, peerInfo(member))
+	}
+	for _, member := range sortedMembers(g.DeadPeers()) {
+		view.Dead = append(view.Dead, peerInfo(member))
+	}
+
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	for channelID := range g.chains {
+		channel := ChannelView{
+			Channel:        channelID,
+			LeaderElection: NoLeaderElection,
+			Self:           ChannelPeer{PeerInfo: view.Self},
+			Peers:          []ChannelPeer{},
+		}
+		if le, exists := g.leaderElection[channelID]; exists {
+			channel.LeaderElection = DynamicLeaderElection
+			for _, leader := range le.Leaders() {
+				channel.Leaders = append(channel.Leaders, gossipcommon.PKIidType(leader).String())
+			}
+		} else if g.serviceConfig.OrgLeader {
+			channel.LeaderElection = StaticLeaderElection
+			channel.Leaders = []string{view.Self.PKIID}
+		}
+		if stateInfo := g.SelfChannelInfo(gossipcommon.ChannelID(channelID)); stateInfo != nil {
+			channel.Self = channelPeer(view.Self, stateInfo.GetStateInfo().GetProperties())
+		}
+		for _, member := range sortedMembers(g.PeersOfChannel(gossipcommon.ChannelID(channelID))) {
+			channel.Peers = append(channel.Peers, channelPeer(peerInfo(member), member.Properties))
+		}
+		view.Channels = append(view.Channels, channel)
+	}
+	sort.Slice(view.Channels, func(i, j int) bool { return view.Channels[i].Channel < view.Channels[j].Channel })
+
+	return view
+}
+
+func channelPeer(info PeerInfo, properties *gproto.Properties) ChannelPeer {
+	peer := ChannelPeer{
+		PeerInfo:     info,
+		LedgerHeight: properties.GetLedgerHeight(),
+		LeftChannel:  properties.GetLeftChannel(),
+	}
+	for _, cc := range properties.GetChaincodes() {
+		peer.Chaincodes = append(peer.Chaincodes, ChaincodeInfo{Name: cc.Name, Version: cc.Version})
+	}
+	return peer
+}
+
+func sortedMembers(members []discovery.NetworkMember) []discovery.NetworkMember {
+	sort.Slice(members, func(i, j int) bool { return members[i].PKIid.String() < members[j].PKIid.String() })
+	return members
+}
+
+// MembershipViewProvider provides the view the gossip layer of the peer has of the network
+type MembershipViewProvider interface {
+	MembershipView() *MembershipView
+}
+
+// MembershipHandler serves the membership view of the gossip layer on GET, optionally
+// restricted to a channel by the 'channel' query parameter.
+type MembershipHandler struct {
+	Provider MembershipViewProvider
+}
+
+// NewMembershipHandler creates a new MembershipHandler
+func NewMembershipHandler(provider MembershipViewProvider) *MembershipHandler {
+	return &MembershipHandler{
+		Provider: provider,
+	}
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (h *MembershipHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		h.sendResponse(resp, http.StatusMethodNotAllowed, err)
+		return
+	}
+
+	view := h.Provider.MembershipView()
+	if channelID := req.URL.Query().Get("channel"); channelID != "" {
+		for _, channel := range view.Channels {
+			if channel.Channel == channelID {
+				h.sendResponse(resp, http.StatusOK, channel)
+				return
+			}
+		}
+		h.sendResponse(resp, http.StatusNotFound, fmt.Errorf("channel %s not found", channelID))
+		return
+	}
+	h.sendResponse(resp, http.StatusOK, view)
+}
+
+func (h *MembershipHandler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	encoder := json.NewEncoder(resp)
+	if err, ok := payload.(error); ok {
+		payload = &errorResponse{Error: err.Error()}
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+
+	if err := encoder.Encode(payload); err != nil {
+		logger.Errorf("failed to encode payload: %s", err)
+	}
+}
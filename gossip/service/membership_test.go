@@ -0,0 +1,250 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	proto "github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/osdi23p228/fabric/gossip/api"
+	"github.com/osdi23p228/fabric/gossip/common"
+	"github.com/osdi23p228/fabric/gossip/discovery"
+	"github.com/osdi23p228/fabric/gossip/election"
+	"github.com/osdi23p228/fabric/gossip/protoext"
+	"github.com/osdi23p228/fabric/gossip/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type membershipGossipMock struct {
+	*gossipMock
+	alive          []discovery.NetworkMember
+	dead           []discovery.NetworkMember
+	peersOfChannel map[string][]discovery.NetworkMember
+	ledgerHeights  map[string]uint64
+	identities     api.PeerIdentitySet
+}
+
+func (g *membershipGossipMock) SelfMembershipInfo() discovery.NetworkMember {
+	return discovery.NetworkMember{PKIid: common.PKIidType("self"), Endpoint: "self:7051"}
+}
+
+func (g *membershipGossipMock) SelfChannelInfo(channel common.ChannelID) *protoext.SignedGossipMessage {
+	height, exists := g.ledgerHeights[string(channel)]
+	if !exists {
+		return nil
+	}
+	return &protoext.SignedGossipMessage{
+		GossipMessage: &proto.GossipMessage{
+			Content: &proto.GossipMessage_StateInfo{
+				StateInfo: &proto.StateInfo{
+					Properties: &proto.Properties{LedgerHeight: height},
+				},
+			},
+		},
+	}
+}
+
+func (g *membershipGossipMock) Peers() []discovery.NetworkMember {
+	return g.alive
+}
+
+func (g *membershipGossipMock) DeadPeers() []discovery.NetworkMember {
+	return g.dead
+}
+
+func (g *membershipGossipMock) PeersOfChannel(channel common.ChannelID) []discovery.NetworkMember {
+	return g.peersOfChannel[string(channel)]
+}
+
+func (g *membershipGossipMock) IdentityInfo() api.PeerIdentitySet {
+	return g.identities
+}
+
+type expirationCryptoService struct {
+	naiveCryptoService
+	expirations map[string]time.Time
+}
+
+func (cs *expirationCryptoService) Expiration(peerIdentity api.PeerIdentityType) (time.Time, error) {
+	return cs.expirations[string(peerIdentity)], nil
+}
+
+type leaderElectionMock struct {
+	election.LeaderElectionService
+	leaders [][]byte
+}
+
+func (le *leaderElectionMock) Leaders() [][]byte {
+	return le.leaders
+}
+
+func TestMembershipView(t *testing.T) {
+	expiration := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := &GossipService{
+		gossipSvc: &membershipGossipMock{
+			alive: []discovery.NetworkMember{
+				{PKIid: common.PKIidType("p2"), Endpoint: "p2:7051", InternalEndpoint: "p2.org2:7051"},
+				{PKIid: common.PKIidType("p1"), Endpoint: "p1:7051"},
+			},
+			dead: []discovery.NetworkMember{
+				{PKIid: common.PKIidType("p3"), Endpoint: "p3:7051"},
+			},
+			peersOfChannel: map[string][]discovery.NetworkMember{
+				"A": {
+					{
+						PKIid:    common.PKIidType("p1"),
+						Endpoint: "p1:7051",
+						Properties: &proto.Properties{
+							LedgerHeight: 10,
+							Chaincodes:   []*proto.Chaincode{{Name: "mycc", Version: "1.0"}},
+						},
+					},
+				},
+			},
+			ledgerHeights: map[string]uint64{"A": 12},
+			identities: api.PeerIdentitySet{
+				{PKIId: common.PKIidType("p2"), Identity: api.PeerIdentityType("p2-cert"), Organization: api.OrgIdentityType("Org2MSP")},
+				{PKIId: common.PKIidType("p1"), Identity: api.PeerIdentityType("p1-cert"), Organization: api.OrgIdentityType("Org1MSP")},
+			},
+		},
+		mcs: &expirationCryptoService{
+			expirations: map[string]time.Time{"p1-cert": expiration},
+		},
+		secAdv:       &secAdvMock{},
+		peerIdentity: api.PeerIdentityType("Org1MSP"),
+		chains: map[string]state.GossipStateProvider{
+			"A": nil,
+			"B": nil,
+		},
+		leaderElection: map[string]election.LeaderElectionService{
+			"A": &leaderElectionMock{leaders: [][]byte{[]byte("p1"), []byte("p2")}},
+		},
+		serviceConfig: &ServiceConfig{},
+	}
+
+	view := g.MembershipView()
+
+	assert.Equal(t, &MembershipView{
+		Self: PeerInfo{PKIID: "73656c66", MSPID: "Org1MSP", Endpoint: "self:7051"},
+		Alive: []PeerInfo{
+			{PKIID: "7031", MSPID: "Org1MSP", Endpoint: "p1:7051"},
+			{PKIID: "7032", MSPID: "Org2MSP", Endpoint: "p2:7051", InternalEndpoint: "p2.org2:7051"},
+		},
+		Dead: []PeerInfo{
+			{PKIID: "7033", Endpoint: "p3:7051"},
+		},
+		Channels: []ChannelView{
+			{
+				Channel:        "A",
+				LeaderElection: DynamicLeaderElection,
+				Leaders:        []string{"7031", "7032"},
+				Self: ChannelPeer{
+					PeerInfo:     PeerInfo{PKIID: "73656c66", MSPID: "Org1MSP", Endpoint: "self:7051"},
+					LedgerHeight: 12,
+				},
+				Peers: []ChannelPeer{
+					{
+						PeerInfo:     PeerInfo{PKIID: "7031", MSPID: "Org1MSP", Endpoint: "p1:7051"},
+						LedgerHeight: 10,
+						Chaincodes:   []ChaincodeInfo{{Name: "mycc", Version: "1.0"}},
+					},
+				},
+			},
+			{
+				Channel:        "B",
+				LeaderElection: NoLeaderElection,
+				Self: ChannelPeer{
+					PeerInfo: PeerInfo{PKIID: "73656c66", MSPID: "Org1MSP", Endpoint: "self:7051"},
+				},
+				Peers: []ChannelPeer{},
+			},
+		},
+		Identities: []IdentityInfo{
+			{PKIID: "7031", MSPID: "Org1MSP", Expiration: &expiration},
+			{PKIID: "7032", MSPID: "Org2MSP"},
+		},
+	}, view)
+
+	// A static leader considers itself as the leader of the channels without leader election
+	g.serviceConfig.OrgLeader = true
+	view = g.MembershipView()
+	assert.Equal(t, StaticLeaderElection, view.Channels[1].LeaderElection)
+	assert.Equal(t, []string{"73656c66"}, view.Channels[1].Leaders)
+}
+
+type membershipViewProvider func() *MembershipView
+
+func (p membershipViewProvider) MembershipView() *MembershipView {
+	return p()
+}
+
+func TestMembershipHandler(t *testing.T) {
+	view := &MembershipView{
+		Self:       PeerInfo{PKIID: "73656c66", Endpoint: "self:7051"},
+		Alive:      []PeerInfo{},
+		Dead:       []PeerInfo{},
+		Identities: []IdentityInfo{},
+		Channels: []ChannelView{
+			{Channel: "A", LeaderElection: DynamicLeaderElection, Leaders: []string{"7031"}, Peers: []ChannelPeer{}},
+		},
+	}
+	handler := NewMembershipHandler(membershipViewProvider(func() *MembershipView { return view }))
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		expectedCode int
+		expectedBody interface{}
+	}{
+		{
+			name:         "membership view",
+			method:       http.MethodGet,
+			url:          MembershipURL,
+			expectedCode: http.StatusOK,
+			expectedBody: view,
+		},
+		{
+			name:         "channel view",
+			method:       http.MethodGet,
+			url:          MembershipURL + "?channel=A",
+			expectedCode: http.StatusOK,
+			expectedBody: &view.Channels[0],
+		},
+		{
+			name:         "unknown channel",
+			method:       http.MethodGet,
+			url:          MembershipURL + "?channel=B",
+			expectedCode: http.StatusNotFound,
+			expectedBody: &errorResponse{Error: "channel B not found"},
+		},
+		{
+			name:         "invalid method",
+			method:       http.MethodPost,
+			url:          MembershipURL,
+			expectedCode: http.StatusMethodNotAllowed,
+			expectedBody: &errorResponse{Error: "invalid request method: POST"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, httptest.NewRequest(tt.method, tt.url, nil))
+
+			assert.Equal(t, tt.expectedCode, resp.Code)
+			assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+			expected, err := json.Marshal(tt.expectedBody)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), resp.Body.String())
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	pcomm "github.com/hyperledger/fabric-protos-go/common"
 	proto "github.com/hyperledger/fabric-protos-go/gossip"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
 	tspb "github.com/hyperledger/fabric-protos-go/transientstore"
 	"github.com/osdi23p228/fabric/bccsp/factory"
 	"github.com/osdi23p228/fabric/common/configtx/test"
@@ -253,6 +254,10 @@ func (*mockCommitter) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker,
 	panic("implement me")
 }
 
+func (*mockCommitter) TxIDExists(txID string) (bool, error) {
+	panic("implement me")
+}
+
 func (*mockCommitter) CommitPvtDataOfOldBlocks(
 	reconciledPvtdata []*ledger.ReconciledPvtdata,
 	unreconciled ledger.MissingPvtDataInfo,
@@ -336,6 +341,10 @@ func (mock *ramLedger) GetBlockByNumber(blockNumber uint64) (*pcomm.Block, error
 	}
 }
 
+func (mock *ramLedger) GetTransactionByID(txID string) (*peer.ProcessedTransaction, error) {
+	panic("implement me")
+}
+
 func (mock *ramLedger) Close() {
 
 }
@@ -423,6 +432,7 @@ func newPeerNodeWithGossipWithValidatorWithMetrics(logger gutil.Logger, id int,
 	appCapability := &capabilitymock.AppCapabilities{}
 	capabilityProvider.On("Capabilities").Return(appCapability)
 	appCapability.On("StorePvtDataOfInvalidTx").Return(true)
+	appCapability.On("PurgePvtData").Return(false)
 	coord := privdata.NewCoordinator(mspID, privdata.Support{
 		Validator:          v,
 		Committer:          committer,
@@ -87,6 +87,7 @@ type Gossip struct {
 	ConnTimeout                time.Duration   `yaml:"connTimeout,omitempty"`
 	RecvBuffSize               int             `yaml:"recvBuffSize,omitempty"`
 	SendBuffSize               int             `yaml:"sendBuffSize,omitempty"`
+	Compression                bool            `yaml:"compression,omitempty"`
 	DigestWaitTime             time.Duration   `yaml:"digestWaitTime,omitempty"`
 	RequestWaitTime            time.Duration   `yaml:"requestWaitTime,omitempty"`
 	ResponseWaitTime           time.Duration   `yaml:"responseWaitTime,omitempty"`
@@ -96,16 +97,25 @@ type Gossip struct {
 	MsgExpirationFactor        int             `yaml:"msgExpirationFactor,omitempty"`
 	MaxConnectionAttempts      int             `yaml:"maxConnectionAttempts,omitempty"`
 	ExternalEndpoint           string          `yaml:"externalEndpoint,omitempty"`
+	ErasureCoding              *ErasureCoding  `yaml:"erasureCoding,omitempty"`
 	Election                   *GossipElection `yaml:"election,omitempty"`
 	PvtData                    *GossipPvtData  `yaml:"pvtData,omitempty"`
 	State                      *GossipState    `yaml:"state,omitempty"`
 }
 
+type ErasureCoding struct {
+	Enabled      bool `yaml:"enabled"`
+	MinBlockSize int  `yaml:"minBlockSize,omitempty"`
+	ParityShards int  `yaml:"parityShards,omitempty"`
+}
+
 type GossipElection struct {
 	StartupGracePeriod       time.Duration `yaml:"startupGracePeriod,omitempty"`
 	MembershipSampleInterval time.Duration `yaml:"membershipSampleInterval,omitempty"`
 	LeaderAliveThreshold     time.Duration `yaml:"leaderAliveThreshold,omitempty"`
 	LeaderElectionDuration   time.Duration `yaml:"leaderElectionDuration,omitempty"`
+	Strategy                 string        `yaml:"strategy,omitempty"`
+	MaxLeaders               int           `yaml:"maxLeaders,omitempty"`
 }
 
 type GossipPvtData struct {
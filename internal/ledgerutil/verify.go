@@ -0,0 +1,177 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledgerutil
+
+import (
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/osdi23p228/fabric/bccsp"
+	"github.com/osdi23p228/fabric/common/channelconfig"
+	"github.com/osdi23p228/fabric/common/flogging"
+	"github.com/osdi23p228/fabric/common/ledger/blkstorage"
+	"github.com/osdi23p228/fabric/common/policies"
+	"github.com/osdi23p228/fabric/common/util"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("ledgerutil")
+
+// LedgerType is the kind of node owning a block store, which determines the block index layout.
+type LedgerType string
+
+const (
+	PeerLedger    LedgerType = "peer"
+	OrdererLedger LedgerType = "orderer"
+)
+
+// IndexConfig returns the block index config used by the given kind of node.
+func (lt LedgerType) IndexConfig() (*blkstorage.IndexConfig, error) {
+	switch lt {
+	case PeerLedger:
+		return &blkstorage.IndexConfig{
+			AttrsToIndex: []blkstorage.IndexableAttr{
+				blkstorage.IndexableAttrBlockHash,
+				blkstorage.IndexableAttrBlockNum,
+				blkstorage.IndexableAttrTxID,
+				blkstorage.IndexableAttrBlockNumTranNum,
+			},
+		}, nil
+	case OrdererLedger:
+		return &blkstorage.IndexConfig{
+			AttrsToIndex: []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNum},
+		}, nil
+	default:
+		return nil, errors.Errorf("unknown ledger type %s", lt)
+	}
+}
+
+// ConfigSignatureVerifier verifies the metadata signatures of the blocks of a channel
+// against the BlockValidation policy of the channel config in effect when each block
+// was written. It tracks the config by applying the config blocks it encounters, and
+// must therefore be given the blocks of a channel in order.
+type ConfigSignatureVerifier struct {
+	cryptoProvider bccsp.BCCSP
+	bundle         *channelconfig.Bundle
+
+	// Unverified counts the blocks whose signatures could not be verified because
+	// no config block preceded them, e.g. in a ledger bootstrapped from a snapshot.
+	Unverified uint64
+}
+
+// NewConfigSignatureVerifier creates a ConfigSignatureVerifier.
+func NewConfigSignatureVerifier(cryptoProvider bccsp.BCCSP) *ConfigSignatureVerifier {
+	return &ConfigSignatureVerifier{cryptoProvider: cryptoProvider}
+}
+
+// VerifyBlockSignatures verifies the signatures of the block, and applies the config
+// carried by the block if it is a config block.
+func (v *ConfigSignatureVerifier) VerifyBlockSignatures(block *common.Block) error {
+	var err error
+	switch {
+	case block.Header.Number == 0:
+		// The genesis block is not signed
+	case v.bundle == nil:
+		v.Unverified++
+	default:
+		err = v.verify(block)
+	}
+
+	if protoutil.IsConfigBlock(block) {
+		if updateErr := v.updateConfig(block); updateErr != nil {
+			return updateErr
+		}
+	}
+
+	return err
+}
+
+func (v *ConfigSignatureVerifier) verify(block *common.Block) error {
+	metadata, err := protoutil.GetMetadataFromBlock(block, common.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return errors.WithMessage(err, "failed unmarshaling signatures metadata")
+	}
+	if len(metadata.Signatures) == 0 {
+		return errors.New("block is not signed")
+	}
+
+	var signatureSet []*protoutil.SignedData
+	for _, metadataSignature := range metadata.Signatures {
+		shdr, err := protoutil.UnmarshalSignatureHeader(metadataSignature.SignatureHeader)
+		if err != nil {
+			return errors.WithMessage(err, "failed unmarshaling signature header")
+		}
+		signatureSet = append(signatureSet, &protoutil.SignedData{
+			Identity:  shdr.Creator,
+			Data:      util.ConcatenateBytes(metadata.Value, metadataSignature.SignatureHeader, protoutil.BlockHeaderBytes(block.Header)),
+			Signature: metadataSignature.Signature,
+		})
+	}
+
+	policy, ok := v.bundle.PolicyManager().GetPolicy(policies.BlockValidation)
+	if !ok {
+		return errors.Errorf("no %s policy in the channel config", policies.BlockValidation)
+	}
+	if err := policy.EvaluateSignedData(signatureSet); err != nil {
+		return errors.WithMessagef(err, "%s policy not satisfied", policies.BlockValidation)
+	}
+	return nil
+}
+
+func (v *ConfigSignatureVerifier) updateConfig(block *common.Block) error {
+	envelope, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return errors.WithMessage(err, "failed extracting config envelope")
+	}
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		return errors.WithMessage(err, "failed unmarshaling config envelope payload")
+	}
+	if payload.Header == nil {
+		return errors.New("config envelope payload is missing header")
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return errors.WithMessage(err, "failed unmarshaling config envelope channel header")
+	}
+	if chdr.Type != int32(common.HeaderType_CONFIG) {
+		// Blocks of a system channel creating another channel do not change the config of the channel
+		return nil
+	}
+
+	bundle, err := channelconfig.NewBundleFromEnvelope(envelope, v.cryptoProvider)
+	if err != nil {
+		return errors.WithMessage(err, "failed applying config")
+	}
+	logger.Debugf("Applied config of block [%d]", block.Header.Number)
+	v.bundle = bundle
+	return nil
+}
+
+// VerifyLedger verifies the hash chain, the index and, unless skipSignatures is set,
+// the block signatures of the ledger of the given channel in a block store directory.
+func VerifyLedger(blockStorePath, channelID string, ledgerType LedgerType, skipSignatures bool, cryptoProvider bccsp.BCCSP) (*blkstorage.VerificationReport, error) {
+	indexConfig, err := ledgerType.IndexConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var sigVerifier blkstorage.BlockSignatureVerifier
+	var configVerifier *ConfigSignatureVerifier
+	if !skipSignatures {
+		configVerifier = NewConfigSignatureVerifier(cryptoProvider)
+		sigVerifier = configVerifier
+	}
+
+	report, err := blkstorage.VerifyBlockStore(blockStorePath, channelID, indexConfig, sigVerifier)
+	if err != nil {
+		return nil, err
+	}
+	if configVerifier != nil && configVerifier.Unverified > 0 {
+		logger.Warningf("Signatures of %d blocks of channel %s were not verified, as no config block precedes them", configVerifier.Unverified, channelID)
+	}
+	return report, nil
+}
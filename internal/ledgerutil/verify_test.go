@@ -0,0 +1,139 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledgerutil
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/osdi23p228/fabric/bccsp/sw"
+	"github.com/osdi23p228/fabric/common/genesis"
+	"github.com/osdi23p228/fabric/common/ledger/blockledger"
+	"github.com/osdi23p228/fabric/common/ledger/blockledger/fileledger"
+	"github.com/osdi23p228/fabric/common/metrics/disabled"
+	"github.com/osdi23p228/fabric/common/util"
+	"github.com/osdi23p228/fabric/core/config/configtest"
+	"github.com/osdi23p228/fabric/internal/configtxgen/encoder"
+	"github.com/osdi23p228/fabric/internal/configtxgen/genesisconfig"
+	"github.com/osdi23p228/fabric/msp"
+	"github.com/osdi23p228/fabric/msp/mgmt"
+	msptesttools "github.com/osdi23p228/fabric/msp/mgmt/testtools"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func signBlock(t *testing.T, block *cb.Block, signer msp.SigningIdentity) {
+	value := protoutil.MarshalOrPanic(&cb.OrdererBlockMetadata{LastConfig: &cb.LastConfig{Index: 0}})
+	shdr := protoutil.MarshalOrPanic(protoutil.NewSignatureHeaderOrPanic(signer))
+	signature, err := signer.Sign(util.ConcatenateBytes(value, shdr, protoutil.BlockHeaderBytes(block.Header)))
+	require.NoError(t, err)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&cb.Metadata{
+		Value:      value,
+		Signatures: []*cb.MetadataSignature{{SignatureHeader: shdr, Signature: signature}},
+	})
+}
+
+func TestVerifyLedger(t *testing.T) {
+	require.NoError(t, msptesttools.LoadMSPSetupForTesting())
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	signer := mgmt.GetLocalSigningIdentityOrPanic(cryptoProvider)
+
+	conf := genesisconfig.Load(genesisconfig.SampleSingleMSPSoloProfile, configtest.GetDevConfigDir())
+	channelGroup, err := encoder.NewChannelGroup(conf)
+	require.NoError(t, err)
+	genesisBlock := genesis.NewFactoryImpl(channelGroup).Block("testchannel")
+
+	dir, err := ioutil.TempDir("", "ledgerutil-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	lf, err := fileledger.New(dir, &disabled.Provider{})
+	require.NoError(t, err)
+	rw, err := lf.GetOrCreate("testchannel")
+	require.NoError(t, err)
+	require.NoError(t, rw.Append(genesisBlock))
+
+	for i := 0; i < 3; i++ {
+		block := blockledger.CreateNextBlock(rw, []*cb.Envelope{{Payload: []byte{byte(i)}}})
+		signBlock(t, block, signer)
+		if i == 1 {
+			sigMD, err := protoutil.GetMetadataFromBlock(block, cb.BlockMetadataIndex_SIGNATURES)
+			require.NoError(t, err)
+			sigMD.Signatures[0].Signature = []byte("bad signature")
+			block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(sigMD)
+		}
+		require.NoError(t, rw.Append(block))
+	}
+	lf.Close()
+
+	t.Run("with signatures", func(t *testing.T) {
+		report, err := VerifyLedger(dir, "testchannel", OrdererLedger, false, cryptoProvider)
+		require.NoError(t, err)
+		require.Equal(t, uint64(4), report.BlocksVerified)
+		require.Nil(t, report.FirstBrokenBlock)
+		require.Empty(t, report.IndexMismatches)
+		require.Len(t, report.InvalidSignatures, 1)
+		require.Equal(t, uint64(2), report.InvalidSignatures[0].BlockNum)
+		require.Contains(t, report.InvalidSignatures[0].Reason, "/Channel/Orderer/BlockValidation policy not satisfied")
+	})
+
+	t.Run("without signatures", func(t *testing.T) {
+		report, err := VerifyLedger(dir, "testchannel", OrdererLedger, true, cryptoProvider)
+		require.NoError(t, err)
+		require.True(t, report.OK(), report.String())
+	})
+
+	t.Run("unknown ledger type", func(t *testing.T) {
+		_, err := VerifyLedger(dir, "testchannel", LedgerType("kafka"), true, cryptoProvider)
+		require.EqualError(t, err, "unknown ledger type kafka")
+	})
+}
+
+func TestVerifyLedgerSystemChannel(t *testing.T) {
+	require.NoError(t, msptesttools.LoadMSPSetupForTesting())
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	signer := mgmt.GetLocalSigningIdentityOrPanic(cryptoProvider)
+
+	conf := genesisconfig.Load(genesisconfig.SampleSingleMSPSoloProfile, configtest.GetDevConfigDir())
+	channelGroup, err := encoder.NewChannelGroup(conf)
+	require.NoError(t, err)
+	genesisBlock := genesis.NewFactoryImpl(channelGroup).Block("systemchannel")
+
+	dir, err := ioutil.TempDir("", "ledgerutil-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	lf, err := fileledger.New(dir, &disabled.Provider{})
+	require.NoError(t, err)
+	rw, err := lf.GetOrCreate("systemchannel")
+	require.NoError(t, err)
+	require.NoError(t, rw.Append(genesisBlock))
+
+	// the system channel records the creation of an application channel
+	channelConfig, err := protoutil.ExtractEnvelope(genesis.NewFactoryImpl(channelGroup).Block("testchannel"), 0)
+	require.NoError(t, err)
+	ordererTx, err := protoutil.CreateSignedEnvelope(cb.HeaderType_ORDERER_TRANSACTION, "systemchannel", signer, channelConfig, 0, 0)
+	require.NoError(t, err)
+	block := blockledger.CreateNextBlock(rw, []*cb.Envelope{ordererTx})
+	signBlock(t, block, signer)
+	require.NoError(t, rw.Append(block))
+	require.True(t, protoutil.IsConfigBlock(block))
+
+	block = blockledger.CreateNextBlock(rw, []*cb.Envelope{{Payload: []byte{1}}})
+	signBlock(t, block, signer)
+	require.NoError(t, rw.Append(block))
+	lf.Close()
+
+	report, err := VerifyLedger(dir, "systemchannel", OrdererLedger, false, cryptoProvider)
+	require.NoError(t, err)
+	require.True(t, report.OK(), report.String())
+	require.Equal(t, uint64(3), report.BlocksVerified)
+}
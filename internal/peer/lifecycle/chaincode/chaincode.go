@@ -37,8 +37,10 @@ func Cmd(cryptoProvider bccsp.BCCSP) *cobra.Command {
 	chaincodeCmd.AddCommand(InstallCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(QueryInstalledCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(GetInstalledPackageCmd(nil, cryptoProvider))
+	chaincodeCmd.AddCommand(UninstallCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(ApproveForMyOrgCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(QueryApprovedCmd(nil, cryptoProvider))
+	chaincodeCmd.AddCommand(QueryPendingApprovalsCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(CheckCommitReadinessCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(CommitCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(QueryCommittedCmd(nil, cryptoProvider))
@@ -64,6 +66,7 @@ var (
 	connectionProfilePath string
 	waitForEvent          bool
 	waitForEventTimeout   time.Duration
+	waitForCommittable    bool
 	packageID             string
 	sequence              int
 	initRequired          bool
@@ -73,8 +76,8 @@ var (
 
 var chaincodeCmd = &cobra.Command{
 	Use:   "chaincode",
-	Short: "Perform chaincode operations: package|install|queryinstalled|getinstalledpackage|approveformyorg|queryapproved|checkcommitreadiness|commit|querycommitted",
-	Long:  "Perform chaincode operations: package|install|queryinstalled|getinstalledpackage|approveformyorg|queryapproved|checkcommitreadiness|commit|querycommitted",
+	Short: "Perform chaincode operations: package|install|queryinstalled|getinstalledpackage|uninstall|approveformyorg|queryapproved|querypendingapprovals|checkcommitreadiness|commit|querycommitted",
+	Long:  "Perform chaincode operations: package|install|queryinstalled|getinstalledpackage|uninstall|approveformyorg|queryapproved|querypendingapprovals|checkcommitreadiness|commit|querycommitted",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		common.InitCmd(cmd, args)
 		common.SetOrdererEnv(cmd, args)
@@ -111,6 +114,8 @@ func ResetFlags() {
 		"Whether to wait for the event from each peer's deliver filtered service signifying that the transaction has been committed successfully")
 	flags.DurationVar(&waitForEventTimeout, "waitForEventTimeout", 30*time.Second,
 		"Time to wait for the event from each peer's deliver filtered service signifying that the 'invoke' transaction has been committed successfully")
+	flags.BoolVar(&waitForCommittable, "waitForCommittable", false,
+		"Whether to wait, using the peer's deliver filtered service, until the chaincode definition approved for the chaincode given by --name is committable")
 	flags.StringVarP(&packageID, "package-id", "", "", "The identifier of the chaincode install package")
 	flags.IntVarP(&sequence, "sequence", "", 0, "The sequence number of the chaincode definition for the channel")
 	flags.BoolVarP(&initRequired, "init-required", "", false, "Whether the chaincode requires invoking 'init'")
@@ -0,0 +1,334 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/bccsp"
+	"github.com/osdi23p228/fabric/core/chaincode/lifecycle/msgs"
+	"github.com/osdi23p228/fabric/internal/peer/chaincode"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// PendingApprovalsQuerier holds the dependencies needed to query the
+// chaincode definitions approved by the organizations which have not been
+// committed yet
+type PendingApprovalsQuerier struct {
+	Certificate    tls.Certificate
+	Command        *cobra.Command
+	DeliverClient  pb.DeliverClient
+	EndorserClient EndorserClient
+	Input          *PendingApprovalsQueryInput
+	Signer         Signer
+	Writer         io.Writer
+}
+
+type PendingApprovalsQueryInput struct {
+	ChannelID           string
+	Name                string
+	PeerAddress         string
+	WaitForCommittable  bool
+	WaitForEventTimeout time.Duration
+	OutputFormat        string
+}
+
+// QueryPendingApprovalsCmd returns the cobra command for querying the
+// chaincode definitions approved by the organizations which have not been
+// committed yet
+func QueryPendingApprovalsCmd(p *PendingApprovalsQuerier, cryptoProvider bccsp.BCCSP) *cobra.Command {
+	chaincodeQueryPendingApprovalsCmd := &cobra.Command{
+		Use:   "querypendingapprovals",
+		Short: "Query the approved chaincode definitions which are pending commit.",
+		Long:  "Query the chaincode definitions approved by the organizations of a channel which have not been committed yet, along with the approval status by org.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if p == nil {
+				ccInput := &ClientConnectionsInput{
+					CommandName:           cmd.Name(),
+					EndorserRequired:      true,
+					ChannelID:             channelID,
+					PeerAddresses:         peerAddresses,
+					TLSRootCertFiles:      tlsRootCertFiles,
+					ConnectionProfilePath: connectionProfilePath,
+					TLSEnabled:            viper.GetBool("peer.tls.enabled"),
+				}
+
+				cc, err := NewClientConnections(ccInput, cryptoProvider)
+				if err != nil {
+					return err
+				}
+
+				pqInput := &PendingApprovalsQueryInput{
+					ChannelID:           channelID,
+					Name:                chaincodeName,
+					PeerAddress:         peerAddresses[0],
+					WaitForCommittable:  waitForCommittable,
+					WaitForEventTimeout: waitForEventTimeout,
+					OutputFormat:        output,
+				}
+
+				p = &PendingApprovalsQuerier{
+					Certificate:    cc.Certificate,
+					Command:        cmd,
+					DeliverClient:  cc.DeliverClients[0],
+					EndorserClient: cc.EndorserClients[0],
+					Input:          pqInput,
+					Signer:         cc.Signer,
+					Writer:         os.Stdout,
+				}
+			}
+			return p.Query()
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+		"name",
+		"waitForCommittable",
+		"waitForEventTimeout",
+		"output",
+	}
+	attachFlags(chaincodeQueryPendingApprovalsCmd, flagList)
+
+	return chaincodeQueryPendingApprovalsCmd
+}
+
+// Query returns the chaincode definitions approved by the organizations
+// which have not been committed on a given channel. When waiting for a
+// committable definition, the query is repeated whenever the peer delivers
+// a block until the definition of the named chaincode is committable.
+func (p *PendingApprovalsQuerier) Query() error {
+	if p.Command != nil {
+		// Parsing of the command line is done so silence cmd usage
+		p.Command.SilenceUsage = true
+	}
+
+	if p.Input.ChannelID == "" {
+		return errors.New("The required parameter 'channelID' is empty. Rerun the command with -C flag")
+	}
+
+	if p.Input.WaitForCommittable {
+		if p.Input.Name == "" {
+			return errors.New("The required parameter 'name' is empty. Rerun the command with -n flag")
+		}
+		return p.waitForCommittable()
+	}
+
+	proposalResponse, err := p.query()
+	if err != nil {
+		return err
+	}
+	return p.writeResponse(proposalResponse)
+}
+
+// waitForCommittable queries the pending approvals each time the peer's
+// deliver filtered service delivers a block, until the definition of the
+// named chaincode is committable or the timeout expires
+func (p *PendingApprovalsQuerier) waitForCommittable() error {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), p.Input.WaitForEventTimeout)
+	defer cancelFunc()
+
+	dg := chaincode.NewDeliverGroup(
+		[]pb.DeliverClient{p.DeliverClient},
+		[]string{p.Input.PeerAddress},
+		p.Signer,
+		p.Certificate,
+		p.Input.ChannelID,
+		"",
+	)
+	// connect to the deliver service before the first query so that
+	// no block committed after the query is missed
+	err := dg.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	dc := dg.Clients[0]
+
+	blocks := make(chan error)
+	go func() {
+		for {
+			resp, err := dc.Connection.Recv()
+			if err != nil {
+				err = errors.WithMessagef(err, "error receiving from deliver filtered at %s", dc.Address)
+			} else {
+				switch r := resp.Type.(type) {
+				case *pb.DeliverResponse_FilteredBlock:
+				case *pb.DeliverResponse_Status:
+					err = errors.Errorf("deliver completed with status (%s) before the definition became committable", r.Status)
+				default:
+					err = errors.Errorf("received unexpected response type (%T) from %s", r, dc.Address)
+				}
+			}
+			select {
+			case blocks <- err:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		proposalResponse, err := p.query()
+		if err != nil {
+			return err
+		}
+		committable, err := isCommittable(proposalResponse, p.Input.Name)
+		if err != nil {
+			return err
+		}
+		if committable {
+			return p.writeResponse(proposalResponse)
+		}
+
+		select {
+		case err := <-blocks:
+			if err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return errors.Errorf("timed out waiting for the definition of chaincode '%s' to become committable", p.Input.Name)
+		}
+	}
+}
+
+// isCommittable returns whether the definition of the named chaincode
+// approved by the organization is committable
+func isCommittable(proposalResponse *pb.ProposalResponse, name string) (bool, error) {
+	result := &msgs.QueryPendingApprovalsResult{}
+	err := proto.Unmarshal(proposalResponse.Response.Payload, result)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal proposal response's response payload")
+	}
+	for _, approval := range result.PendingApprovals {
+		// the definitions only approved by other organizations are
+		// returned without their parameters
+		if approval.Name == name && approval.Version != "" && approval.Committable {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (p *PendingApprovalsQuerier) query() (*pb.ProposalResponse, error) {
+	proposal, err := p.createProposal()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create proposal")
+	}
+
+	signedProposal, err := signProposal(proposal, p.Signer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create signed proposal")
+	}
+
+	proposalResponse, err := p.EndorserClient.ProcessProposal(context.Background(), signedProposal)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to endorse proposal")
+	}
+
+	if proposalResponse == nil {
+		return nil, errors.New("received nil proposal response")
+	}
+
+	if proposalResponse.Response == nil {
+		return nil, errors.New("received proposal response with nil response")
+	}
+
+	if proposalResponse.Response.Status != int32(cb.Status_SUCCESS) {
+		return nil, errors.Errorf("query failed with status: %d - %s", proposalResponse.Response.Status, proposalResponse.Response.Message)
+	}
+
+	return proposalResponse, nil
+}
+
+func (p *PendingApprovalsQuerier) writeResponse(proposalResponse *pb.ProposalResponse) error {
+	if strings.ToLower(p.Input.OutputFormat) == "json" {
+		return printResponseAsJSON(proposalResponse, &msgs.QueryPendingApprovalsResult{}, p.Writer)
+	}
+	return p.printResponse(proposalResponse)
+}
+
+// printResponse prints the information included in the response
+// from the server as human readable plain-text.
+func (p *PendingApprovalsQuerier) printResponse(proposalResponse *pb.ProposalResponse) error {
+	result := &msgs.QueryPendingApprovalsResult{}
+	err := proto.Unmarshal(proposalResponse.Response.Payload, result)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal proposal response's response payload")
+	}
+
+	fmt.Fprintf(p.Writer, "Pending approvals on channel '%s':\n", p.Input.ChannelID)
+	for _, approval := range result.PendingApprovals {
+		orgs := []string{}
+		for org := range approval.Approvals {
+			orgs = append(orgs, org)
+		}
+		sort.Strings(orgs)
+
+		approvals := make([]string, len(orgs))
+		for i, org := range orgs {
+			approvals[i] = fmt.Sprintf("%s: %t", org, approval.Approvals[org])
+		}
+
+		if approval.Version == "" {
+			// the parameters of the definitions only approved by other
+			// organizations are unknown to the peer
+			fmt.Fprintf(p.Writer, "Name: %s, Version: <approved by other orgs only>, Sequence: %d, Committable: %t, Approvals: [%s]\n",
+				approval.Name, approval.Sequence, approval.Committable, strings.Join(approvals, ", "))
+			continue
+		}
+		fmt.Fprintf(p.Writer, "Name: %s, Version: %s, Sequence: %d, Committable: %t, Approvals: [%s]\n",
+			approval.Name, approval.Version, approval.Sequence, approval.Committable, strings.Join(approvals, ", "))
+	}
+	return nil
+}
+
+func (p *PendingApprovalsQuerier) createProposal() (*pb.Proposal, error) {
+	args := &msgs.QueryPendingApprovalsArgs{}
+
+	argsBytes, err := proto.Marshal(args)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal args")
+	}
+	ccInput := &pb.ChaincodeInput{Args: [][]byte{[]byte("QueryPendingApprovals"), argsBytes}}
+
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: lifecycleName},
+			Input:       ccInput,
+		},
+	}
+
+	signerSerialized, err := p.Signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to serialize identity")
+	}
+
+	proposal, _, err := protoutil.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, p.Input.ChannelID, cis, signerSerialized)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create ChaincodeInvocationSpec proposal")
+	}
+
+	return proposal, nil
+}
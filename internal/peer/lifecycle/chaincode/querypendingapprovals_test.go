@@ -0,0 +1,382 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/bccsp/sw"
+	"github.com/osdi23p228/fabric/core/chaincode/lifecycle/msgs"
+	"github.com/osdi23p228/fabric/internal/peer/lifecycle/chaincode"
+	"github.com/osdi23p228/fabric/internal/peer/lifecycle/chaincode/mock"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("QueryPendingApprovals", func() {
+	Describe("PendingApprovalsQuerier", func() {
+		var (
+			mockProposalResponse    *pb.ProposalResponse
+			mockEndorserClient      *mock.EndorserClient
+			mockDeliverClient       *mock.PeerDeliverClient
+			mockSigner              *mock.Signer
+			input                   *chaincode.PendingApprovalsQueryInput
+			pendingApprovalsQuerier *chaincode.PendingApprovalsQuerier
+		)
+
+		BeforeEach(func() {
+			mockResult := &msgs.QueryPendingApprovalsResult{
+				PendingApprovals: []*msgs.QueryPendingApprovalsResult_PendingApproval{
+					{
+						Name:     "cc_name",
+						Sequence: 7,
+						Version:  "version_1.0",
+						Approvals: map[string]bool{
+							"org2": false,
+							"org1": true,
+						},
+						Committable: false,
+					},
+					{
+						Name:     "other_cc",
+						Sequence: 2,
+						Version:  "version_2.0",
+						Approvals: map[string]bool{
+							"org1": true,
+							"org2": true,
+						},
+						Committable: true,
+					},
+					{
+						Name:     "third_cc",
+						Sequence: 3,
+						Approvals: map[string]bool{
+							"org1": false,
+							"org2": true,
+						},
+					},
+				},
+			}
+
+			mockResultBytes, err := proto.Marshal(mockResult)
+			Expect(err).NotTo(HaveOccurred())
+			mockProposalResponse = &pb.ProposalResponse{
+				Response: &pb.Response{
+					Status:  200,
+					Payload: mockResultBytes,
+				},
+			}
+
+			mockEndorserClient = &mock.EndorserClient{}
+			mockEndorserClient.ProcessProposalReturns(mockProposalResponse, nil)
+
+			mockDeliverClient = &mock.PeerDeliverClient{}
+
+			mockSigner = &mock.Signer{}
+			buffer := gbytes.NewBuffer()
+
+			input = &chaincode.PendingApprovalsQueryInput{
+				ChannelID: "test-channel",
+			}
+
+			pendingApprovalsQuerier = &chaincode.PendingApprovalsQuerier{
+				Input:          input,
+				DeliverClient:  mockDeliverClient,
+				EndorserClient: mockEndorserClient,
+				Signer:         mockSigner,
+				Writer:         buffer,
+			}
+		})
+
+		It("queries the pending approvals and writes the output as human readable plain-text", func() {
+			err := pendingApprovalsQuerier.Query()
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(pendingApprovalsQuerier.Writer).Should(gbytes.Say("Pending approvals on channel 'test-channel':\n"))
+			Eventually(pendingApprovalsQuerier.Writer).Should(gbytes.Say(`\QName: cc_name, Version: version_1.0, Sequence: 7, Committable: false, Approvals: [org1: true, org2: false]\E`))
+			Eventually(pendingApprovalsQuerier.Writer).Should(gbytes.Say(`\QName: other_cc, Version: version_2.0, Sequence: 2, Committable: true, Approvals: [org1: true, org2: true]\E`))
+			Eventually(pendingApprovalsQuerier.Writer).Should(gbytes.Say(`\QName: third_cc, Version: <approved by other orgs only>, Sequence: 3, Committable: false, Approvals: [org1: false, org2: true]\E`))
+
+			Expect(mockEndorserClient.ProcessProposalCallCount()).To(Equal(1))
+		})
+
+		Context("when JSON-formatted output is requested", func() {
+			BeforeEach(func() {
+				pendingApprovalsQuerier.Input.OutputFormat = "json"
+			})
+
+			It("queries the pending approvals and writes the output as JSON", func() {
+				err := pendingApprovalsQuerier.Query()
+				Expect(err).NotTo(HaveOccurred())
+				expectedOutput := &msgs.QueryPendingApprovalsResult{}
+				err = proto.Unmarshal(mockProposalResponse.Response.Payload, expectedOutput)
+				Expect(err).NotTo(HaveOccurred())
+				json, err := json.MarshalIndent(expectedOutput, "", "\t")
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(pendingApprovalsQuerier.Writer).Should(gbytes.Say(fmt.Sprintf(`\Q%s\E`, string(json))))
+			})
+		})
+
+		Context("when the payload contains bytes that aren't a QueryPendingApprovalsResult", func() {
+			BeforeEach(func() {
+				mockProposalResponse.Response = &pb.Response{
+					Payload: []byte("badpayloadbadpayload"),
+					Status:  200,
+				}
+			})
+
+			It("returns an error", func() {
+				err := pendingApprovalsQuerier.Query()
+				Expect(err).To(MatchError(ContainSubstring("failed to unmarshal proposal response's response payload")))
+			})
+		})
+
+		Context("when the channel is not provided", func() {
+			BeforeEach(func() {
+				pendingApprovalsQuerier.Input.ChannelID = ""
+			})
+
+			It("returns an error", func() {
+				err := pendingApprovalsQuerier.Query()
+				Expect(err).To(MatchError("The required parameter 'channelID' is empty. Rerun the command with -C flag"))
+			})
+		})
+
+		Context("when the signer cannot be serialized", func() {
+			BeforeEach(func() {
+				mockSigner.SerializeReturns(nil, errors.New("bad serialization"))
+			})
+
+			It("returns an error", func() {
+				err := pendingApprovalsQuerier.Query()
+				Expect(err).To(MatchError("failed to create proposal: failed to serialize identity: bad serialization"))
+			})
+		})
+
+		Context("when the signer fails to sign the proposal", func() {
+			BeforeEach(func() {
+				mockSigner.SignReturns(nil, errors.New("bad sign"))
+			})
+
+			It("returns an error", func() {
+				err := pendingApprovalsQuerier.Query()
+				Expect(err).To(MatchError("failed to create signed proposal: bad sign"))
+			})
+		})
+
+		Context("when the endorser fails to endorse the proposal", func() {
+			BeforeEach(func() {
+				mockEndorserClient.ProcessProposalReturns(nil, errors.New("bad endorsement"))
+			})
+
+			It("returns an error", func() {
+				err := pendingApprovalsQuerier.Query()
+				Expect(err).To(MatchError("failed to endorse proposal: bad endorsement"))
+			})
+		})
+
+		Context("when the endorser returns a nil proposal response", func() {
+			BeforeEach(func() {
+				mockEndorserClient.ProcessProposalReturns(nil, nil)
+			})
+
+			It("returns an error", func() {
+				err := pendingApprovalsQuerier.Query()
+				Expect(err).To(MatchError("received nil proposal response"))
+			})
+		})
+
+		Context("when the endorser returns a proposal response with a nil response", func() {
+			BeforeEach(func() {
+				mockProposalResponse.Response = nil
+			})
+
+			It("returns an error", func() {
+				err := pendingApprovalsQuerier.Query()
+				Expect(err).To(MatchError("received proposal response with nil response"))
+			})
+		})
+
+		Context("when the endorser returns a non-success status", func() {
+			BeforeEach(func() {
+				mockProposalResponse.Response = &pb.Response{
+					Status:  500,
+					Message: "message",
+				}
+			})
+
+			It("returns an error", func() {
+				err := pendingApprovalsQuerier.Query()
+				Expect(err).To(MatchError("query failed with status: 500 - message"))
+			})
+		})
+		Context("when waiting for a committable definition", func() {
+			var pendingResponse *pb.ProposalResponse
+
+			BeforeEach(func() {
+				input.Name = "cc_name"
+				input.PeerAddress = "querypendingapprovalspeer0"
+				input.WaitForCommittable = true
+				input.WaitForEventTimeout = 3 * time.Second
+
+				pendingResult := &msgs.QueryPendingApprovalsResult{
+					PendingApprovals: []*msgs.QueryPendingApprovalsResult_PendingApproval{
+						{
+							Name:      "cc_name",
+							Sequence:  7,
+							Version:   "version_1.0",
+							Approvals: map[string]bool{"org1": true, "org2": false},
+						},
+						{
+							Name:        "cc_name",
+							Sequence:    7,
+							Approvals:   map[string]bool{"org1": false, "org2": true},
+							Committable: true,
+						},
+					},
+				}
+				pendingResultBytes, err := proto.Marshal(pendingResult)
+				Expect(err).NotTo(HaveOccurred())
+				pendingResponse = &pb.ProposalResponse{
+					Response: &pb.Response{
+						Status:  200,
+						Payload: pendingResultBytes,
+					},
+				}
+				mockProposalResponse.Response.Payload, err = proto.Marshal(&msgs.QueryPendingApprovalsResult{
+					PendingApprovals: []*msgs.QueryPendingApprovalsResult_PendingApproval{
+						{
+							Name:        "cc_name",
+							Sequence:    7,
+							Version:     "version_1.0",
+							Approvals:   map[string]bool{"org1": true, "org2": true},
+							Committable: true,
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				mockEndorserClient.ProcessProposalReturnsOnCall(0, pendingResponse, nil)
+
+				mockDeliverClient.DeliverFilteredStub = func(ctx context.Context, opts ...grpc.CallOption) (pb.Deliver_DeliverFilteredClient, error) {
+					mockDF := &mock.Deliver{}
+					resp := &pb.DeliverResponse{
+						Type: &pb.DeliverResponse_FilteredBlock{
+							FilteredBlock: createFilteredBlock(input.ChannelID, "approvetx"),
+						},
+					}
+					mockDF.RecvReturns(resp, nil)
+					return mockDF, nil
+				}
+			})
+
+			It("queries the pending approvals again after a block is delivered until the definition is committable", func() {
+				err := pendingApprovalsQuerier.Query()
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(pendingApprovalsQuerier.Writer).Should(gbytes.Say(`\QName: cc_name, Version: version_1.0, Sequence: 7, Committable: true, Approvals: [org1: true, org2: true]\E`))
+				Expect(mockEndorserClient.ProcessProposalCallCount()).To(Equal(2))
+				Expect(mockDeliverClient.DeliverFilteredCallCount()).To(Equal(1))
+			})
+
+			Context("when the definition is already committable", func() {
+				BeforeEach(func() {
+					mockEndorserClient.ProcessProposalReturnsOnCall(0, mockProposalResponse, nil)
+				})
+
+				It("returns after the first query", func() {
+					err := pendingApprovalsQuerier.Query()
+					Expect(err).NotTo(HaveOccurred())
+					Eventually(pendingApprovalsQuerier.Writer).Should(gbytes.Say(`\QName: cc_name, Version: version_1.0, Sequence: 7, Committable: true\E`))
+					Expect(mockEndorserClient.ProcessProposalCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the definition does not become committable before the timeout", func() {
+				BeforeEach(func() {
+					input.WaitForEventTimeout = 10 * time.Millisecond
+					mockEndorserClient.ProcessProposalReturns(pendingResponse, nil)
+				})
+
+				It("returns an error", func() {
+					err := pendingApprovalsQuerier.Query()
+					Expect(err).To(MatchError("timed out waiting for the definition of chaincode 'cc_name' to become committable"))
+				})
+			})
+
+			Context("when the name is not provided", func() {
+				BeforeEach(func() {
+					input.Name = ""
+				})
+
+				It("returns an error", func() {
+					err := pendingApprovalsQuerier.Query()
+					Expect(err).To(MatchError("The required parameter 'name' is empty. Rerun the command with -n flag"))
+				})
+			})
+
+			Context("when the client can't connect to the deliver service", func() {
+				BeforeEach(func() {
+					mockDeliverClient.DeliverFilteredReturns(nil, errors.New("robusta"))
+				})
+
+				It("returns an error", func() {
+					err := pendingApprovalsQuerier.Query()
+					Expect(err).To(MatchError("failed to connect to deliver on all peers: error connecting to deliver filtered at querypendingapprovalspeer0: robusta"))
+				})
+			})
+
+			Context("when the deliver service returns an error", func() {
+				BeforeEach(func() {
+					mockDeliverClient.DeliverFilteredStub = func(ctx context.Context, opts ...grpc.CallOption) (pb.Deliver_DeliverFilteredClient, error) {
+						mockDF := &mock.Deliver{}
+						mockDF.RecvReturns(nil, errors.New("arabica"))
+						return mockDF, nil
+					}
+				})
+
+				It("returns an error", func() {
+					err := pendingApprovalsQuerier.Query()
+					Expect(err).To(MatchError("error receiving from deliver filtered at querypendingapprovalspeer0: arabica"))
+				})
+			})
+		})
+	})
+
+	Describe("QueryPendingApprovalsCmd", func() {
+		var queryPendingApprovalsCmd *cobra.Command
+
+		BeforeEach(func() {
+			cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+			Expect(err).To(BeNil())
+			queryPendingApprovalsCmd = chaincode.QueryPendingApprovalsCmd(nil, cryptoProvider)
+			queryPendingApprovalsCmd.SilenceErrors = true
+			queryPendingApprovalsCmd.SilenceUsage = true
+			queryPendingApprovalsCmd.SetArgs([]string{
+				"--channelID=testchannel",
+				"--peerAddresses=querypendingapprovalspeer1",
+				"--tlsRootCertFiles=tls1",
+			})
+		})
+
+		AfterEach(func() {
+			chaincode.ResetFlags()
+		})
+
+		It("attempts to connect to the endorser", func() {
+			err := queryPendingApprovalsCmd.Execute()
+			Expect(err).To(MatchError(ContainSubstring("failed to retrieve endorser client")))
+		})
+	})
+})
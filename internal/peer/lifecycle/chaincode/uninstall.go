@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/bccsp"
+	"github.com/osdi23p228/fabric/core/chaincode/lifecycle/msgs"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Uninstaller holds the dependencies needed to uninstall
+// a chaincode package from a peer.
+type Uninstaller struct {
+	Command        *cobra.Command
+	EndorserClient EndorserClient
+	Input          *UninstallInput
+	Signer         Signer
+}
+
+// UninstallInput holds the input parameters for uninstalling
+// a chaincode package.
+type UninstallInput struct {
+	PackageID string
+}
+
+// Validate checks that the required uninstall parameters
+// are provided.
+func (u *UninstallInput) Validate() error {
+	if u.PackageID == "" {
+		return errors.New("The required parameter 'package-id' is empty. Rerun the command with --package-id flag")
+	}
+
+	return nil
+}
+
+// UninstallCmd returns the cobra command for chaincode uninstall.
+func UninstallCmd(u *Uninstaller, cryptoProvider bccsp.BCCSP) *cobra.Command {
+	chaincodeUninstallCmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Uninstall a chaincode.",
+		Long:  "Uninstall a chaincode package from a peer and stop any running instance of it.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if u == nil {
+				ccInput := &ClientConnectionsInput{
+					CommandName:           cmd.Name(),
+					EndorserRequired:      true,
+					ChannelID:             channelID,
+					PeerAddresses:         peerAddresses,
+					TLSRootCertFiles:      tlsRootCertFiles,
+					ConnectionProfilePath: connectionProfilePath,
+					TLSEnabled:            viper.GetBool("peer.tls.enabled"),
+				}
+				c, err := NewClientConnections(ccInput, cryptoProvider)
+				if err != nil {
+					return err
+				}
+
+				// uninstall is currently only supported for one peer so just use
+				// the first endorser client
+				u = &Uninstaller{
+					Command:        cmd,
+					EndorserClient: c.EndorserClients[0],
+					Input: &UninstallInput{
+						PackageID: packageID,
+					},
+					Signer: c.Signer,
+				}
+			}
+			return u.Uninstall()
+		},
+	}
+	flagList := []string{
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+		"package-id",
+	}
+	attachFlags(chaincodeUninstallCmd, flagList)
+
+	return chaincodeUninstallCmd
+}
+
+// Uninstall uninstalls a chaincode package from a peer.
+func (u *Uninstaller) Uninstall() error {
+	if u.Command != nil {
+		// Parsing of the command line is done so silence cmd usage
+		u.Command.SilenceUsage = true
+	}
+
+	if err := u.Input.Validate(); err != nil {
+		return err
+	}
+
+	proposal, err := u.createProposal()
+	if err != nil {
+		return errors.WithMessage(err, "failed to create proposal")
+	}
+
+	signedProposal, err := signProposal(proposal, u.Signer)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create signed proposal")
+	}
+
+	proposalResponse, err := u.EndorserClient.ProcessProposal(context.Background(), signedProposal)
+	if err != nil {
+		return errors.WithMessage(err, "failed to endorse chaincode uninstall")
+	}
+
+	if proposalResponse == nil {
+		return errors.New("chaincode uninstall failed: received nil proposal response")
+	}
+
+	if proposalResponse.Response == nil {
+		return errors.New("chaincode uninstall failed: received proposal response with nil response")
+	}
+
+	if proposalResponse.Response.Status != int32(cb.Status_SUCCESS) {
+		return errors.Errorf("chaincode uninstall failed with status: %d - %s", proposalResponse.Response.Status, proposalResponse.Response.Message)
+	}
+
+	logger.Infof("Uninstalled chaincode package with package ID '%s'", u.Input.PackageID)
+
+	return nil
+}
+
+func (u *Uninstaller) createProposal() (*pb.Proposal, error) {
+	args := &msgs.UninstallChaincodeArgs{
+		PackageId: u.Input.PackageID,
+	}
+
+	argsBytes, err := proto.Marshal(args)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal args")
+	}
+
+	ccInput := &pb.ChaincodeInput{
+		Args: [][]byte{[]byte("UninstallChaincode"), argsBytes},
+	}
+
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: lifecycleName},
+			Input:       ccInput,
+		},
+	}
+
+	signerSerialized, err := u.Signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to serialize identity")
+	}
+
+	proposal, _, err := protoutil.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, "", cis, signerSerialized)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create ChaincodeInvocationSpec proposal")
+	}
+
+	return proposal, nil
+}
@@ -0,0 +1,197 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/bccsp/sw"
+	"github.com/osdi23p228/fabric/core/chaincode/lifecycle/msgs"
+	"github.com/osdi23p228/fabric/internal/peer/lifecycle/chaincode"
+	"github.com/osdi23p228/fabric/internal/peer/lifecycle/chaincode/mock"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Uninstall", func() {
+	Describe("Uninstaller", func() {
+		var (
+			mockProposalResponse *pb.ProposalResponse
+			mockEndorserClient   *mock.EndorserClient
+			mockSigner           *mock.Signer
+			input                *chaincode.UninstallInput
+			uninstaller          *chaincode.Uninstaller
+		)
+
+		BeforeEach(func() {
+			mockEndorserClient = &mock.EndorserClient{}
+			mockProposalResponse = &pb.ProposalResponse{
+				Response: &pb.Response{
+					Status: 200,
+				},
+			}
+			mockEndorserClient.ProcessProposalReturns(mockProposalResponse, nil)
+
+			input = &chaincode.UninstallInput{
+				PackageID: "pkgid",
+			}
+
+			mockSigner = &mock.Signer{}
+
+			uninstaller = &chaincode.Uninstaller{
+				Input:          input,
+				EndorserClient: mockEndorserClient,
+				Signer:         mockSigner,
+			}
+		})
+
+		It("uninstalls the chaincode package", func() {
+			err := uninstaller.Uninstall()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(mockEndorserClient.ProcessProposalCallCount()).To(Equal(1))
+			_, signedProposal, _ := mockEndorserClient.ProcessProposalArgsForCall(0)
+			proposal, err := protoutil.UnmarshalProposal(signedProposal.ProposalBytes)
+			Expect(err).NotTo(HaveOccurred())
+			payload, err := protoutil.UnmarshalChaincodeProposalPayload(proposal.Payload)
+			Expect(err).NotTo(HaveOccurred())
+			cis, err := protoutil.UnmarshalChaincodeInvocationSpec(payload.Input)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cis.ChaincodeSpec.ChaincodeId.Name).To(Equal("_lifecycle"))
+			args := cis.ChaincodeSpec.Input.Args
+			Expect(args).To(HaveLen(2))
+			Expect(string(args[0])).To(Equal("UninstallChaincode"))
+			uninstallArgs := &msgs.UninstallChaincodeArgs{}
+			Expect(proto.Unmarshal(args[1], uninstallArgs)).To(Succeed())
+			Expect(uninstallArgs.PackageId).To(Equal("pkgid"))
+		})
+
+		Context("when the package id is not specified", func() {
+			BeforeEach(func() {
+				input.PackageID = ""
+			})
+
+			It("returns an error", func() {
+				err := uninstaller.Uninstall()
+				Expect(err).To(MatchError("The required parameter 'package-id' is empty. Rerun the command with --package-id flag"))
+			})
+		})
+
+		Context("when the signer cannot be serialized", func() {
+			BeforeEach(func() {
+				mockSigner.SerializeReturns(nil, errors.New("cafe"))
+			})
+
+			It("returns an error", func() {
+				err := uninstaller.Uninstall()
+				Expect(err).To(MatchError("failed to create proposal: failed to serialize identity: cafe"))
+			})
+		})
+
+		Context("when the signer fails to sign the proposal", func() {
+			BeforeEach(func() {
+				mockSigner.SignReturns(nil, errors.New("tea"))
+			})
+
+			It("returns an error", func() {
+				err := uninstaller.Uninstall()
+				Expect(err).To(MatchError("failed to create signed proposal: tea"))
+			})
+		})
+
+		Context("when the endorser fails to endorse the proposal", func() {
+			BeforeEach(func() {
+				mockEndorserClient.ProcessProposalReturns(nil, errors.New("latte"))
+			})
+
+			It("returns an error", func() {
+				err := uninstaller.Uninstall()
+				Expect(err).To(MatchError("failed to endorse chaincode uninstall: latte"))
+			})
+		})
+
+		Context("when the endorser returns a nil proposal response", func() {
+			BeforeEach(func() {
+				mockEndorserClient.ProcessProposalReturns(nil, nil)
+			})
+
+			It("returns an error", func() {
+				err := uninstaller.Uninstall()
+				Expect(err).To(MatchError("chaincode uninstall failed: received nil proposal response"))
+			})
+		})
+
+		Context("when the endorser returns a proposal response with a nil response", func() {
+			BeforeEach(func() {
+				mockProposalResponse.Response = nil
+			})
+
+			It("returns an error", func() {
+				err := uninstaller.Uninstall()
+				Expect(err).To(MatchError("chaincode uninstall failed: received proposal response with nil response"))
+			})
+		})
+
+		Context("when the endorser returns a non-success status", func() {
+			BeforeEach(func() {
+				mockProposalResponse.Response = &pb.Response{
+					Status:  404,
+					Message: "capuccino",
+				}
+			})
+
+			It("returns an error", func() {
+				err := uninstaller.Uninstall()
+				Expect(err).To(MatchError("chaincode uninstall failed with status: 404 - capuccino"))
+			})
+		})
+	})
+
+	Describe("UninstallCmd", func() {
+		var uninstallCmd *cobra.Command
+
+		BeforeEach(func() {
+			cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+			Expect(err).To(BeNil())
+			uninstallCmd = chaincode.UninstallCmd(nil, cryptoProvider)
+			uninstallCmd.SilenceErrors = true
+			uninstallCmd.SilenceUsage = true
+			uninstallCmd.SetArgs([]string{
+				"--package-id=test-package",
+				"--peerAddresses=test1",
+				"--tlsRootCertFiles=tls1",
+			})
+		})
+
+		AfterEach(func() {
+			chaincode.ResetFlags()
+		})
+
+		It("sets up the uninstaller and attempts to uninstall the chaincode package", func() {
+			err := uninstallCmd.Execute()
+			Expect(err).To(MatchError(ContainSubstring("failed to retrieve endorser client for uninstall")))
+		})
+
+		Context("when more than one peer address is provided", func() {
+			BeforeEach(func() {
+				uninstallCmd.SetArgs([]string{
+					"--peerAddresses=test3",
+					"--peerAddresses=test4",
+				})
+			})
+
+			It("returns an error", func() {
+				err := uninstallCmd.Execute()
+				Expect(err).To(MatchError(ContainSubstring("failed to validate peer connection parameters")))
+			})
+		})
+	})
+})
@@ -48,6 +48,7 @@ import (
 	"github.com/osdi23p228/fabric/core/chaincode/lifecycle"
 	"github.com/osdi23p228/fabric/core/chaincode/persistence"
 	"github.com/osdi23p228/fabric/core/chaincode/platforms"
+	"github.com/osdi23p228/fabric/core/committer"
 	"github.com/osdi23p228/fabric/core/committer/txvalidator/plugin"
 	"github.com/osdi23p228/fabric/core/common/ccprovider"
 	"github.com/osdi23p228/fabric/core/common/privdata"
@@ -55,6 +56,7 @@ import (
 	"github.com/osdi23p228/fabric/core/container"
 	"github.com/osdi23p228/fabric/core/container/dockercontroller"
 	"github.com/osdi23p228/fabric/core/container/externalbuilder"
+	"github.com/osdi23p228/fabric/core/container/kubernetescontroller"
 	"github.com/osdi23p228/fabric/core/deliverservice"
 	"github.com/osdi23p228/fabric/core/dispatcher"
 	"github.com/osdi23p228/fabric/core/endorser"
@@ -91,6 +93,7 @@ import (
 	peergossip "github.com/osdi23p228/fabric/internal/peer/gossip"
 	"github.com/osdi23p228/fabric/internal/peer/version"
 	"github.com/osdi23p228/fabric/internal/pkg/comm"
+	"github.com/osdi23p228/fabric/internal/pkg/gateway"
 	"github.com/osdi23p228/fabric/msp"
 	"github.com/osdi23p228/fabric/msp/mgmt"
 	"github.com/osdi23p228/fabric/protoutil"
@@ -171,6 +174,17 @@ func (e endorserChannelAdapter) Channel(channelID string) *endorser.Channel {
 	return nil
 }
 
+type gatewayLedgerAdapter struct {
+	peer *peer.Peer
+}
+
+func (g gatewayLedgerAdapter) Ledger(channelID string) (gateway.Ledger, error) {
+	if l := g.peer.GetLedger(channelID); l != nil {
+		return l, nil
+	}
+	return nil, errors.Errorf("channel %s not found", channelID)
+}
+
 type custodianLauncherAdapter struct {
 	launcher      chaincode.Launcher
 	streamHandler extcc.StreamHandler
@@ -279,21 +293,28 @@ func serve(args []string) error {
 		cs.SetClientCertificate(clientCert)
 	}
 
-	transientStoreProvider, err := transientstore.NewStoreProvider(
-		filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "transientstore"),
-	)
+	transientStoreProvider, err := transientstore.NewStoreProviderWithConfig(transientstore.Config{
+		Path:              filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "transientstore"),
+		EncryptionEnabled: coreConfig.TransientStoreEncryptionEnabled,
+		CryptoProvider:    factory.GetDefault(),
+		MaxSizePerChannel: coreConfig.TransientStoreMaxSizePerChannel,
+		MetricsProvider:   metricsProvider,
+	})
 	if err != nil {
 		return errors.WithMessage(err, "failed to open transient store")
 	}
 
 	deliverServiceConfig := deliverservice.GlobalConfig()
 
+	commitNotifier := committer.NewCommitNotifier()
+
 	peerInstance := &peer.Peer{
 		ServerConfig:             serverConfig,
 		CredentialSupport:        cs,
 		StoreProvider:            transientStoreProvider,
 		CryptoProvider:           factory.GetDefault(),
 		OrdererEndpointOverrides: deliverServiceConfig.OrdererEndpointOverrides,
+		CommitNotifier:           commitNotifier,
 	}
 
 	localMSP := mgmt.GetLocalMSP(factory.GetDefault())
@@ -468,6 +489,9 @@ func serve(args []string) error {
 
 	peerInstance.GossipService = gossipService
 
+	opsSystem.RegisterHandler(gossipprivdata.ReconciliationURL, gossipprivdata.NewReconciliationHandler(gossipService))
+	opsSystem.RegisterHandler(gossipservice.MembershipURL, gossipservice.NewMembershipHandler(gossipService))
+
 	if err := lifecycleCache.InitializeLocalChaincodes(); err != nil {
 		return errors.WithMessage(err, "could not initialize local chaincodes")
 	}
@@ -524,14 +548,44 @@ func serve(args []string) error {
 		HandlerRegistry: chaincodeHandlerRegistry,
 	}
 
-	if coreConfig.VMEndpoint == "" && len(coreConfig.ExternalBuilders) == 0 {
-		logger.Panic("VMEndpoint not set and no ExternalBuilders defined")
+	if coreConfig.VMEndpoint == "" && !coreConfig.VMKubernetesEnabled && len(coreConfig.ExternalBuilders) == 0 {
+		logger.Panic("VMEndpoint not set, Kubernetes VM disabled and no ExternalBuilders defined")
 	}
 
 	chaincodeConfig := chaincode.GlobalConfig()
 
+	chaincodeLoggingEnv := []string{
+		"CORE_CHAINCODE_LOGGING_LEVEL=" + chaincodeConfig.LogLevel,
+		"CORE_CHAINCODE_LOGGING_SHIM=" + chaincodeConfig.ShimLogLevel,
+		"CORE_CHAINCODE_LOGGING_FORMAT=" + chaincodeConfig.LogFormat,
+	}
+
 	var dockerBuilder container.DockerBuilder
-	if coreConfig.VMEndpoint != "" {
+	switch {
+	case coreConfig.VMKubernetesEnabled:
+		client, err := kubernetescontroller.NewInClusterClient(coreConfig.VMKubernetesNamespace)
+		if err != nil {
+			logger.Panicf("cannot create kubernetes client: %s", err)
+		}
+
+		kubernetesVM := &kubernetescontroller.KubernetesVM{
+			PeerID:          coreConfig.PeerID,
+			NetworkID:       coreConfig.NetworkID,
+			BuildMetrics:    kubernetescontroller.NewBuildMetrics(opsSystem.Provider),
+			Client:          client,
+			AttachStdOut:    coreConfig.VMKubernetesAttachStdout,
+			PlatformBuilder: platformRegistry,
+			LoggingEnv:      chaincodeLoggingEnv,
+			MSPID:           mspID,
+			ClaimName:       coreConfig.VMKubernetesClaimName,
+			SharedPath:      coreConfig.VMKubernetesSharedPath,
+			BuildTimeout:    coreConfig.VMKubernetesBuildTimeout,
+		}
+		if err := opsSystem.RegisterChecker("kubernetes", kubernetesVM); err != nil {
+			logger.Panicf("failed to register kubernetes health check: %s", err)
+		}
+		dockerBuilder = kubernetesVM
+	case coreConfig.VMEndpoint != "":
 		client, err := createDockerClient(coreConfig)
 		if err != nil {
 			logger.Panicf("cannot create docker client: %s", err)
@@ -553,12 +607,8 @@ func serve(args []string) error {
 			// This field is superfluous for chaincodes built with v2.0+ binaries
 			// however, to prevent users from being forced to rebuild leaving for now
 			// but it should be removed in the future.
-			LoggingEnv: []string{
-				"CORE_CHAINCODE_LOGGING_LEVEL=" + chaincodeConfig.LogLevel,
-				"CORE_CHAINCODE_LOGGING_SHIM=" + chaincodeConfig.ShimLogLevel,
-				"CORE_CHAINCODE_LOGGING_FORMAT=" + chaincodeConfig.LogFormat,
-			},
-			MSPID: mspID,
+			LoggingEnv: chaincodeLoggingEnv,
+			MSPID:      mspID,
 		}
 		if err := opsSystem.RegisterChecker("docker", dockerVM); err != nil {
 			logger.Panicf("failed to register docker health check: %s", err)
@@ -566,7 +616,7 @@ func serve(args []string) error {
 		dockerBuilder = dockerVM
 	}
 
-	// docker is disabled when we're missing the docker config
+	// docker is disabled when we're missing the docker and kubernetes config
 	if dockerBuilder == nil {
 		dockerBuilder = &disabledDockerBuilder{}
 	}
@@ -627,10 +677,17 @@ func serve(args []string) error {
 	lifecycleFunctions := &lifecycle.ExternalFunctions{
 		Resources:                 lifecycleResources,
 		InstallListener:           lifecycleCache,
+		UninstallListener:         lifecycleCache,
 		InstalledChaincodesLister: lifecycleCache,
 		ChaincodeBuilder:          containerRouter,
 		BuildRegistry:             buildRegistry,
 	}
+	lifecycleCache.PendingApprovalsQuerier = &lifecycle.LedgerPendingApprovals{
+		Functions:           lifecycleFunctions,
+		ChannelConfigSource: peerInstance,
+		LedgerGetter:        peerInstance,
+		OrgMSPID:            mspID,
+	}
 
 	lifecycleSCC := &lifecycle.SCC{
 		Dispatcher: &dispatcher.Dispatcher{
@@ -744,6 +801,10 @@ func serve(args []string) error {
 		Support:                endorserSupport,
 		Metrics:                endorser.NewMetrics(metricsProvider),
 	}
+	if coreConfig.QueryResultCacheEnabled {
+		serverEndorser.QueryCache = endorser.NewQueryCache(coreConfig.QueryResultCacheMaxEntries)
+		commitNotifier.AddBlockListener(serverEndorser.QueryCache)
+	}
 
 	// deploy system chaincodes
 	for _, cc := range []scc.SelfDescribingSysCC{lsccInst, csccInst, qsccInst, lifecycleSCC} {
@@ -775,6 +836,13 @@ func serve(args []string) error {
 			// channel but it won't fire any updates to its listeners
 			lifecycleCache.InitializeMetadata(cid)
 
+			// log the definitions approved by this org as they become
+			// committable, so that an admin following the peer log knows
+			// when the definition can be committed
+			lifecycleCache.RegisterApprovalListener(cid, lifecycle.HandleChaincodeDefinitionCommittableFunc(func(channelID string, approval *lifecycle.PendingApproval) {
+				logger.Infof("Chaincode definition for chaincode '%s' with sequence %d approved by org '%s' is committable on channel '%s'", approval.Name, approval.Definition.Sequence, mspID, channelID)
+			}))
+
 			// initialize the legacyMetadataManager for this channel.
 			// This call will pre-populate chaincode information from
 			// the legacy lifecycle for this channel; it will also fire
@@ -800,19 +868,19 @@ func serve(args []string) error {
 		coreConfig.ValidatorPoolSize,
 	)
 
-	if coreConfig.DiscoveryEnabled {
-		registerDiscoveryService(
-			coreConfig,
+	discoverySupport := newDiscoverySupport(
+		coreConfig,
+		peerInstance,
+		policyMgr,
+		lifecycle.NewMetadataProvider(
+			lifecycleCache,
+			legacyMetadataManager,
 			peerInstance,
-			peerServer,
-			policyMgr,
-			lifecycle.NewMetadataProvider(
-				lifecycleCache,
-				legacyMetadataManager,
-				peerInstance,
-			),
-			gossipService,
-		)
+		),
+		gossipService,
+	)
+	if coreConfig.DiscoveryEnabled {
+		registerDiscoveryService(coreConfig, peerServer, discoverySupport)
 	}
 
 	logger.Infof("Starting peer with ID=[%s], network ID=[%s], address=[%s]", coreConfig.PeerID, coreConfig.NetworkID, coreConfig.PeerAddress)
@@ -874,6 +942,30 @@ func serve(args []string) error {
 	// Register the Endorser server
 	pb.RegisterEndorserServer(peerServer.Server(), auth)
 
+	if coreConfig.GatewayEnabled {
+		gatewayClient, err := comm.NewGRPCClient(comm.ClientConfig{
+			Timeout: coreConfig.GatewayDialTimeout,
+			KaOpts:  deliverServiceConfig.KeepaliveOptions,
+			SecOpts: deliverServiceConfig.SecOpts,
+		})
+		if err != nil {
+			return errors.WithMessage(err, "failed to create the gateway grpc client")
+		}
+		gatewayServer := gateway.CreateServer(
+			auth,
+			signingIdentityBytes,
+			coreConfig.LocalMSPID,
+			discoverySupport,
+			gatewayLedgerAdapter{peer: peerInstance},
+			commitNotifier,
+			aclProvider,
+			gatewayClient,
+			gateway.Options{EndorsementTimeout: coreConfig.GatewayEndorsementTimeout},
+		)
+		gateway.RegisterGatewayServer(peerServer.Server(), gatewayServer)
+		logger.Info("Gateway service activated")
+	}
+
 	go func() {
 		var grpcErr error
 		if grpcErr = peerServer.Start(); grpcErr != nil {
@@ -931,14 +1023,13 @@ func createSelfSignedData() protoutil.SignedData {
 	}
 }
 
-func registerDiscoveryService(
+func newDiscoverySupport(
 	coreConfig *peer.Config,
 	peerInstance *peer.Peer,
-	peerServer *comm.GRPCServer,
 	polMgr policies.ChannelPolicyManagerGetter,
 	metadataProvider *lifecycle.MetadataProvider,
 	gossipService *gossipservice.GossipService,
-) {
+) *discsupport.DiscoverySupport {
 	mspID := coreConfig.LocalMSPID
 	localAccessPolicy := localPolicy(policydsl.SignedByAnyAdmin([]string{mspID}))
 	if coreConfig.DiscoveryOrgMembersAllowed {
@@ -961,7 +1052,10 @@ func registerDiscoveryService(
 		}
 		return block
 	}))
-	support := discsupport.NewDiscoverySupport(acl, gSup, ea, confSup, acl)
+	return discsupport.NewDiscoverySupport(acl, gSup, ea, confSup, acl)
+}
+
+func registerDiscoveryService(coreConfig *peer.Config, peerServer *comm.GRPCServer, support *discsupport.DiscoverySupport) {
 	svc := discovery.NewService(discovery.Config{
 		TLS:                          peerServer.TLSEnabled(),
 		AuthCacheEnabled:             coreConfig.DiscoveryAuthCacheEnabled,
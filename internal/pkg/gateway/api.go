@@ -0,0 +1,268 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/core/aclmgmt/resources"
+	"github.com/osdi23p228/fabric/core/ledger"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Evaluate will invoke the transaction function as specified in the SignedProposal
+func (gs *Server) Evaluate(ctx context.Context, request *EvaluateRequest) (*EvaluateResponse, error) {
+	if request == nil {
+		return nil, status.Error(codes.InvalidArgument, "an evaluate request is required")
+	}
+	channel, chaincode, err := getChannelAndChaincodeFromSignedProposal(request.ProposedTransaction)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to unpack transaction proposal: %s", err)
+	}
+
+	endorser, err := gs.registry.evaluator(channel, chaincode, request.TargetOrganizations)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, gs.options.EndorsementTimeout)
+	defer cancel()
+	response, err := endorser.client.ProcessProposal(ctx, request.ProposedTransaction)
+	if err != nil {
+		return nil, rpcError(codes.Aborted, "failed to evaluate transaction", endpointError(endorser.address, endorser.mspid, err))
+	}
+	if status := response.GetResponse().GetStatus(); status < 200 || status >= 400 {
+		return nil, rpcError(
+			codes.Aborted,
+			"evaluate call to endorser returned an error response",
+			endpointError(endorser.address, endorser.mspid, errors.Errorf("error %d, %s", status, response.GetResponse().GetMessage())),
+		)
+	}
+
+	return &EvaluateResponse{Result: response.Response}, nil
+}
+
+// Endorse will collect endorsements by invoking the transaction function specified in the SignedProposal against
+// sufficient Peers to satisfy the endorsement policy.
+func (gs *Server) Endorse(ctx context.Context, request *EndorseRequest) (*EndorseResponse, error) {
+	if request == nil {
+		return nil, status.Error(codes.InvalidArgument, "an endorse request is required")
+	}
+	signedProposal := request.ProposedTransaction
+	channel, chaincode, err := getChannelAndChaincodeFromSignedProposal(signedProposal)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to unpack transaction proposal: %s", err)
+	}
+	proposal, err := protoutil.UnmarshalProposal(signedProposal.ProposalBytes)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to unpack transaction proposal: %s", err)
+	}
+
+	endorsers, err := gs.registry.endorsers(channel, chaincode, request.EndorsingOrganizations)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, gs.options.EndorsementTimeout)
+	defer cancel()
+
+	responses := make([]*pb.ProposalResponse, len(endorsers))
+	errorDetails := make([]*ErrorDetail, len(endorsers))
+	var wg sync.WaitGroup
+	for i, e := range endorsers {
+		wg.Add(1)
+		go func(i int, e *endorser) {
+			defer wg.Done()
+			response, err := e.client.ProcessProposal(ctx, signedProposal)
+			if err == nil {
+				if status := response.GetResponse().GetStatus(); status < 200 || status >= 400 {
+					err = errors.Errorf("error %d, %s", status, response.GetResponse().GetMessage())
+				}
+			}
+			if err != nil {
+				logger.Warnw("Endorse call to endorser failed", "channel", channel, "txID", request.TransactionId, "endorserAddress", e.address, "endorserMspid", e.mspid, "error", err)
+				errorDetails[i] = endpointError(e.address, e.mspid, err)
+				return
+			}
+			responses[i] = response
+		}(i, e)
+	}
+	wg.Wait()
+
+	var failures []proto.Message
+	for _, detail := range errorDetails {
+		if detail != nil {
+			failures = append(failures, detail)
+		}
+	}
+	if len(failures) > 0 {
+		return nil, rpcError(codes.Aborted, "failed to endorse transaction", failures...)
+	}
+
+	env, err := protoutil.CreateTx(proposal, responses...)
+	if err != nil {
+		return nil, status.Errorf(codes.Aborted, "failed to assemble transaction: %s", err)
+	}
+
+	return &EndorseResponse{PreparedTransaction: env}, nil
+}
+
+// Submit will send the signed transaction to the ordering service. The response indicates whether the transaction was
+// successfully received by the orderer. This does not imply successful commit of the transaction, only that is has
+// been delivered to the orderer.
+func (gs *Server) Submit(ctx context.Context, request *SubmitRequest) (*SubmitResponse, error) {
+	if request == nil {
+		return nil, status.Error(codes.InvalidArgument, "a submit request is required")
+	}
+	txn := request.PreparedTransaction
+	if txn == nil {
+		return nil, status.Error(codes.InvalidArgument, "a prepared transaction is required")
+	}
+	if len(txn.Signature) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "prepared transaction must be signed")
+	}
+
+	orderers, err := gs.registry.orderers(request.ChannelId)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%s", err)
+	}
+
+	var failures []proto.Message
+	for _, o := range orderers {
+		err := broadcast(ctx, o.client, txn)
+		if err == nil {
+			return &SubmitResponse{}, nil
+		}
+		logger.Warnw("Error sending transaction to orderer", "channel", request.ChannelId, "txID", request.TransactionId, "endpoint", o.address, "err", err)
+		failures = append(failures, endpointError(o.address, o.mspid, err))
+	}
+
+	return nil, rpcError(codes.Unavailable, "no orderers could successfully process transaction", failures...)
+}
+
+// CommitStatus returns the validation code for a specific transaction on a specific channel. If the transaction is
+// not yet committed, it waits until a block containing the transaction is committed, or the request is cancelled.
+func (gs *Server) CommitStatus(ctx context.Context, signedRequest *SignedCommitStatusRequest) (*CommitStatusResponse, error) {
+	if signedRequest == nil {
+		return nil, status.Error(codes.InvalidArgument, "a commit status request is required")
+	}
+	request := &CommitStatusRequest{}
+	if err := proto.Unmarshal(signedRequest.Request, request); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid status request: %s", err)
+	}
+
+	signedData := &protoutil.SignedData{
+		Data:      signedRequest.Request,
+		Identity:  request.Identity,
+		Signature: signedRequest.Signature,
+	}
+	if err := gs.aclChecker.CheckACL(resources.Gateway_CommitStatus, request.ChannelId, []*protoutil.SignedData{signedData}); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "%s", err)
+	}
+
+	l, err := gs.ledgers.Ledger(request.ChannelId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s", err)
+	}
+
+	// Register before looking up the ledger, so that a commit happening in between is not missed
+	statusC, release := gs.notifier.RegisterTx(request.ChannelId, request.TransactionId)
+	defer release()
+
+	code, err := l.GetTxValidationCodeByTxID(request.TransactionId)
+	if _, ok := err.(ledger.NotFoundInIndexErr); ok {
+		select {
+		case txStatus := <-statusC:
+			return &CommitStatusResponse{Result: txStatus.ValidationCode, BlockNumber: txStatus.BlockNumber}, nil
+		case <-ctx.Done():
+			return nil, contextError(ctx.Err())
+		}
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get the validation code of transaction %s: %s", request.TransactionId, err)
+	}
+	block, err := l.GetBlockByTxID(request.TransactionId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get the block of transaction %s: %s", request.TransactionId, err)
+	}
+
+	return &CommitStatusResponse{Result: code, BlockNumber: block.Header.Number}, nil
+}
+
+func broadcast(ctx context.Context, client ab.AtomicBroadcastClient, txn *common.Envelope) error {
+	stream, err := client.Broadcast(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.CloseSend()
+
+	if err := stream.Send(txn); err != nil {
+		return errors.WithMessage(err, "failed to send transaction to orderer")
+	}
+	response, err := stream.Recv()
+	if err != nil {
+		return errors.WithMessage(err, "failed to receive response from orderer")
+	}
+	if response.Status != common.Status_SUCCESS {
+		return errors.Errorf("received unsuccessful response from orderer: %s %s", response.Status, response.Info)
+	}
+	return nil
+}
+
+func getChannelAndChaincodeFromSignedProposal(signedProposal *pb.SignedProposal) (string, string, error) {
+	if signedProposal == nil {
+		return "", "", errors.New("a signed proposal is required")
+	}
+	proposal, err := protoutil.UnmarshalProposal(signedProposal.ProposalBytes)
+	if err != nil {
+		return "", "", err
+	}
+	header, err := protoutil.UnmarshalHeader(proposal.Header)
+	if err != nil {
+		return "", "", err
+	}
+	channelHeader, err := protoutil.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return "", "", err
+	}
+	chaincode, err := protoutil.InvokedChaincodeName(signedProposal.ProposalBytes)
+	if err != nil {
+		return "", "", err
+	}
+	return channelHeader.ChannelId, chaincode, nil
+}
+
+func contextError(err error) error {
+	if err == context.DeadlineExceeded {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Canceled, err.Error())
+}
+
+func endpointError(address, mspid string, err error) *ErrorDetail {
+	return &ErrorDetail{Address: address, MspId: mspid, Message: err.Error()}
+}
+
+func rpcError(code codes.Code, message string, details ...proto.Message) error {
+	st := status.New(code, message)
+	if len(details) > 0 {
+		dst, err := st.WithDetails(details...)
+		if err == nil {
+			return dst.Err()
+		}
+		logger.Warningf("Failed to attach error details: %s", err)
+	}
+	return st.Err()
+}
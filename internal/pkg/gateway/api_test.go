@@ -0,0 +1,424 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cp "github.com/hyperledger/fabric-protos-go/common"
+	dp "github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/core/aclmgmt/resources"
+	"github.com/osdi23p228/fabric/core/committer"
+	"github.com/osdi23p228/fabric/core/ledger"
+	gdiscovery "github.com/osdi23p228/fabric/gossip/discovery"
+	"github.com/osdi23p228/fabric/internal/pkg/comm"
+	"github.com/osdi23p228/fabric/internal/pkg/gateway"
+	"github.com/osdi23p228/fabric/internal/pkg/gateway/mocks"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type orderer struct {
+	status cp.Status
+	txs    chan *cp.Envelope
+}
+
+func (o *orderer) Broadcast(stream ab.AtomicBroadcast_BroadcastServer) error {
+	env, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	o.txs <- env
+	return stream.Send(&ab.BroadcastResponse{Status: o.status, Info: "info"})
+}
+
+func (o *orderer) Deliver(ab.AtomicBroadcast_DeliverServer) error {
+	return errors.New("not implemented")
+}
+
+type testContext struct {
+	server         *gateway.Server
+	localEndorser  *mocks.EndorserServer
+	remoteEndorser *mocks.EndorserServer
+	remoteAddress  string
+	orderer        *orderer
+	ordererAddress string
+	discovery      *mocks.Discovery
+	ledger         *mocks.Ledger
+	notifier       *mocks.CommitNotifier
+	statusC        chan *committer.TxStatus
+	aclChecker     *mocks.ACLChecker
+}
+
+func identity(mspid, name string) []byte {
+	return protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: mspid, IdBytes: []byte(name)})
+}
+
+func discoveryPeer(mspid, name, endpoint string) *dp.Peer {
+	aliveMsg := &gossip.GossipMessage{
+		Content: &gossip.GossipMessage_AliveMsg{
+			AliveMsg: &gossip.AliveMessage{Membership: &gossip.Member{Endpoint: endpoint}},
+		},
+	}
+	return &dp.Peer{
+		Identity:       identity(mspid, name),
+		MembershipInfo: &gossip.Envelope{Payload: protoutil.MarshalOrPanic(aliveMsg)},
+	}
+}
+
+func startServer(t *testing.T, register func(*comm.GRPCServer)) string {
+	srv, err := comm.NewGRPCServer("127.0.0.1:0", comm.ServerConfig{})
+	require.NoError(t, err)
+	register(srv)
+	go srv.Start()
+	t.Cleanup(srv.Stop)
+	return srv.Address()
+}
+
+func proposalResponse(payload string) *pb.ProposalResponse {
+	return &pb.ProposalResponse{
+		Payload:     []byte(payload),
+		Endorsement: &pb.Endorsement{Endorser: []byte("endorser")},
+		Response:    &pb.Response{Status: 200, Payload: []byte("result")},
+	}
+}
+
+func setup(t *testing.T) *testContext {
+	tc := &testContext{
+		localEndorser:  &mocks.EndorserServer{},
+		remoteEndorser: &mocks.EndorserServer{},
+		orderer:        &orderer{status: cp.Status_SUCCESS, txs: make(chan *cp.Envelope, 1)},
+		discovery:      &mocks.Discovery{},
+		ledger:         &mocks.Ledger{},
+		notifier:       &mocks.CommitNotifier{},
+		statusC:        make(chan *committer.TxStatus, 1),
+		aclChecker:     &mocks.ACLChecker{},
+	}
+	tc.notifier.RegisterTxReturns(tc.statusC, func() {})
+	tc.localEndorser.ProcessProposalReturns(proposalResponse("payload"), nil)
+	tc.remoteEndorser.ProcessProposalReturns(proposalResponse("payload"), nil)
+
+	tc.remoteAddress = startServer(t, func(srv *comm.GRPCServer) { pb.RegisterEndorserServer(srv.Server(), tc.remoteEndorser) })
+	tc.ordererAddress = startServer(t, func(srv *comm.GRPCServer) { ab.RegisterAtomicBroadcastServer(srv.Server(), tc.orderer) })
+
+	host, portString, err := net.SplitHostPort(tc.ordererAddress)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portString)
+	require.NoError(t, err)
+	tc.discovery.ConfigReturns(&dp.ConfigResult{
+		Orderers: map[string]*dp.Endpoints{
+			"OrdererMSP": {Endpoint: []*dp.Endpoint{{Host: host, Port: uint32(port)}}},
+		},
+	}, nil)
+	tc.discovery.PeersForEndorsementReturns(&dp.EndorsementDescriptor{
+		Chaincode: "mycc",
+		EndorsersByGroups: map[string]*dp.Peers{
+			"G1": {Peers: []*dp.Peer{discoveryPeer("Org1MSP", "local", "localhost:7051")}},
+			"G2": {Peers: []*dp.Peer{discoveryPeer("Org2MSP", "remote", tc.remoteAddress)}},
+		},
+		Layouts: []*dp.Layout{{QuantitiesByGroup: map[string]uint32{"G1": 1, "G2": 1}}},
+	}, nil)
+
+	tc.discovery.PeersReturns(gdiscovery.Members{
+		{Endpoint: "localhost:7051"},
+		{Endpoint: tc.remoteAddress},
+	})
+
+	ledgers := &mocks.LedgerProvider{}
+	ledgers.LedgerReturns(tc.ledger, nil)
+
+	client, err := comm.NewGRPCClient(comm.ClientConfig{Timeout: time.Second})
+	require.NoError(t, err)
+
+	tc.server = gateway.CreateServer(
+		tc.localEndorser,
+		identity("Org1MSP", "local"),
+		"Org1MSP",
+		tc.discovery,
+		ledgers,
+		tc.notifier,
+		tc.aclChecker,
+		client,
+		gateway.Options{EndorsementTimeout: 5 * time.Second},
+	)
+	return tc
+}
+
+func signedProposal(t *testing.T) *pb.SignedProposal {
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: "mycc"},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("invoke")}},
+		},
+	}
+	proposal, _, err := protoutil.CreateChaincodeProposal(cp.HeaderType_ENDORSER_TRANSACTION, "mychannel", cis, identity("Org1MSP", "client"))
+	require.NoError(t, err)
+	return &pb.SignedProposal{ProposalBytes: protoutil.MarshalOrPanic(proposal), Signature: []byte("signature")}
+}
+
+func errorDetails(t *testing.T, err error) []*gateway.ErrorDetail {
+	var details []*gateway.ErrorDetail
+	for _, detail := range status.Convert(err).Details() {
+		errorDetail, ok := detail.(*gateway.ErrorDetail)
+		require.True(t, ok)
+		details = append(details, errorDetail)
+	}
+	return details
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Run("on the local peer", func(t *testing.T) {
+		tc := setup(t)
+		response, err := tc.server.Evaluate(context.Background(), &gateway.EvaluateRequest{ProposedTransaction: signedProposal(t)})
+		require.NoError(t, err)
+		require.Equal(t, []byte("result"), response.Result.Payload)
+		require.Equal(t, 1, tc.localEndorser.ProcessProposalCallCount())
+		require.Equal(t, 0, tc.remoteEndorser.ProcessProposalCallCount())
+
+		channel, interest := tc.discovery.PeersForEndorsementArgsForCall(0)
+		require.Equal(t, "mychannel", string(channel))
+		require.Equal(t, "mycc", interest.Chaincodes[0].Name)
+	})
+
+	t.Run("on a peer of a target organization", func(t *testing.T) {
+		tc := setup(t)
+		response, err := tc.server.Evaluate(context.Background(), &gateway.EvaluateRequest{
+			ProposedTransaction: signedProposal(t),
+			TargetOrganizations: []string{"Org2MSP"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []byte("result"), response.Result.Payload)
+		require.Equal(t, 0, tc.localEndorser.ProcessProposalCallCount())
+		require.Equal(t, 1, tc.remoteEndorser.ProcessProposalCallCount())
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		tc := setup(t)
+		tc.localEndorser.ProcessProposalReturns(&pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "chaincode failed"}}, nil)
+		_, err := tc.server.Evaluate(context.Background(), &gateway.EvaluateRequest{ProposedTransaction: signedProposal(t)})
+		require.Equal(t, codes.Aborted, status.Code(err))
+		details := errorDetails(t, err)
+		require.Len(t, details, 1)
+		require.Equal(t, "Org1MSP", details[0].MspId)
+		require.Equal(t, "error 500, chaincode failed", details[0].Message)
+	})
+
+	t.Run("no peers of target organization", func(t *testing.T) {
+		tc := setup(t)
+		_, err := tc.server.Evaluate(context.Background(), &gateway.EvaluateRequest{
+			ProposedTransaction: signedProposal(t),
+			TargetOrganizations: []string{"Org3MSP"},
+		})
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.Contains(t, err.Error(), "no peers available to evaluate chaincode mycc on channel mychannel")
+	})
+
+	t.Run("malformed proposal", func(t *testing.T) {
+		tc := setup(t)
+		_, err := tc.server.Evaluate(context.Background(), &gateway.EvaluateRequest{ProposedTransaction: &pb.SignedProposal{ProposalBytes: []byte("garbage")}})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestEndorse(t *testing.T) {
+	t.Run("satisfies a layout", func(t *testing.T) {
+		tc := setup(t)
+		response, err := tc.server.Endorse(context.Background(), &gateway.EndorseRequest{ProposedTransaction: signedProposal(t)})
+		require.NoError(t, err)
+		require.Equal(t, 1, tc.localEndorser.ProcessProposalCallCount())
+		require.Equal(t, 1, tc.remoteEndorser.ProcessProposalCallCount())
+
+		env := response.PreparedTransaction
+		require.Nil(t, env.Signature)
+		payload, err := protoutil.UnmarshalPayload(env.Payload)
+		require.NoError(t, err)
+		tx, err := protoutil.UnmarshalTransaction(payload.Data)
+		require.NoError(t, err)
+		cap, err := protoutil.UnmarshalChaincodeActionPayload(tx.Actions[0].Payload)
+		require.NoError(t, err)
+		require.Len(t, cap.Action.Endorsements, 2)
+		require.Equal(t, []byte("payload"), cap.Action.ProposalResponsePayload)
+	})
+
+	t.Run("endorsing organizations", func(t *testing.T) {
+		tc := setup(t)
+		_, err := tc.server.Endorse(context.Background(), &gateway.EndorseRequest{
+			ProposedTransaction:    signedProposal(t),
+			EndorsingOrganizations: []string{"Org2MSP"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0, tc.localEndorser.ProcessProposalCallCount())
+		require.Equal(t, 1, tc.remoteEndorser.ProcessProposalCallCount())
+	})
+
+	t.Run("remote endorser fails", func(t *testing.T) {
+		tc := setup(t)
+		tc.remoteEndorser.ProcessProposalReturns(nil, errors.New("endorsement failure"))
+		_, err := tc.server.Endorse(context.Background(), &gateway.EndorseRequest{ProposedTransaction: signedProposal(t)})
+		require.Equal(t, codes.Aborted, status.Code(err))
+		details := errorDetails(t, err)
+		require.Len(t, details, 1)
+		require.Equal(t, tc.remoteAddress, details[0].Address)
+		require.Equal(t, "Org2MSP", details[0].MspId)
+		require.Contains(t, details[0].Message, "endorsement failure")
+	})
+
+	t.Run("mismatched responses", func(t *testing.T) {
+		tc := setup(t)
+		tc.remoteEndorser.ProcessProposalReturns(proposalResponse("other payload"), nil)
+		_, err := tc.server.Endorse(context.Background(), &gateway.EndorseRequest{ProposedTransaction: signedProposal(t)})
+		require.Equal(t, codes.Aborted, status.Code(err))
+		require.Contains(t, err.Error(), "ProposalResponsePayloads do not match")
+	})
+
+	t.Run("no satisfiable layout", func(t *testing.T) {
+		tc := setup(t)
+		tc.discovery.PeersForEndorsementReturns(&dp.EndorsementDescriptor{
+			EndorsersByGroups: map[string]*dp.Peers{
+				"G1": {Peers: []*dp.Peer{discoveryPeer("Org1MSP", "local", "localhost:7051")}},
+			},
+			Layouts: []*dp.Layout{{QuantitiesByGroup: map[string]uint32{"G1": 2}}},
+		}, nil)
+		_, err := tc.server.Endorse(context.Background(), &gateway.EndorseRequest{ProposedTransaction: signedProposal(t)})
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.Contains(t, err.Error(), "no combination of peers can be found to satisfy the endorsement policy of chaincode mycc on channel mychannel")
+	})
+
+	t.Run("discovery fails", func(t *testing.T) {
+		tc := setup(t)
+		tc.discovery.PeersForEndorsementReturns(nil, errors.New("no such chaincode"))
+		_, err := tc.server.Endorse(context.Background(), &gateway.EndorseRequest{ProposedTransaction: signedProposal(t)})
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.Contains(t, err.Error(), "failed to find the endorsers of chaincode mycc on channel mychannel: no such chaincode")
+	})
+}
+
+func TestSubmit(t *testing.T) {
+	txn := &cp.Envelope{Payload: []byte("payload"), Signature: []byte("signature")}
+
+	t.Run("success", func(t *testing.T) {
+		tc := setup(t)
+		_, err := tc.server.Submit(context.Background(), &gateway.SubmitRequest{ChannelId: "mychannel", PreparedTransaction: txn})
+		require.NoError(t, err)
+		require.True(t, proto.Equal(txn, <-tc.orderer.txs))
+		require.Equal(t, "mychannel", tc.discovery.ConfigArgsForCall(0))
+	})
+
+	t.Run("unsigned transaction", func(t *testing.T) {
+		tc := setup(t)
+		_, err := tc.server.Submit(context.Background(), &gateway.SubmitRequest{ChannelId: "mychannel", PreparedTransaction: &cp.Envelope{Payload: []byte("payload")}})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		require.Contains(t, err.Error(), "prepared transaction must be signed")
+	})
+
+	t.Run("orderer rejects transaction", func(t *testing.T) {
+		tc := setup(t)
+		tc.orderer.status = cp.Status_BAD_REQUEST
+		_, err := tc.server.Submit(context.Background(), &gateway.SubmitRequest{ChannelId: "mychannel", PreparedTransaction: txn})
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		details := errorDetails(t, err)
+		require.Len(t, details, 1)
+		require.Equal(t, tc.ordererAddress, details[0].Address)
+		require.Equal(t, "OrdererMSP", details[0].MspId)
+		require.Equal(t, "received unsuccessful response from orderer: BAD_REQUEST info", details[0].Message)
+	})
+
+	t.Run("no orderers", func(t *testing.T) {
+		tc := setup(t)
+		tc.discovery.ConfigReturns(&dp.ConfigResult{}, nil)
+		_, err := tc.server.Submit(context.Background(), &gateway.SubmitRequest{ChannelId: "mychannel", PreparedTransaction: txn})
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.Contains(t, err.Error(), "no orderers available for channel mychannel")
+	})
+}
+
+func TestCommitStatus(t *testing.T) {
+	request := &gateway.CommitStatusRequest{TransactionId: "txid", ChannelId: "mychannel", Identity: identity("Org1MSP", "client")}
+	signedRequest := &gateway.SignedCommitStatusRequest{Request: protoutil.MarshalOrPanic(request), Signature: []byte("signature")}
+
+	t.Run("committed", func(t *testing.T) {
+		tc := setup(t)
+		tc.ledger.GetTxValidationCodeByTxIDReturns(pb.TxValidationCode_MVCC_READ_CONFLICT, nil)
+		tc.ledger.GetBlockByTxIDReturns(&cp.Block{Header: &cp.BlockHeader{Number: 42}}, nil)
+		response, err := tc.server.CommitStatus(context.Background(), signedRequest)
+		require.NoError(t, err)
+		require.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, response.Result)
+		require.Equal(t, uint64(42), response.BlockNumber)
+
+		resource, channel, idinfo := tc.aclChecker.CheckACLArgsForCall(0)
+		require.Equal(t, resources.Gateway_CommitStatus, resource)
+		require.Equal(t, "mychannel", channel)
+		require.Equal(t, []*protoutil.SignedData{{
+			Data:      signedRequest.Request,
+			Identity:  request.Identity,
+			Signature: signedRequest.Signature,
+		}}, idinfo)
+	})
+
+	t.Run("waits for commit", func(t *testing.T) {
+		tc := setup(t)
+		released := false
+		tc.notifier.RegisterTxReturns(tc.statusC, func() { released = true })
+		tc.ledger.GetTxValidationCodeByTxIDReturns(pb.TxValidationCode(-1), ledger.NotFoundInIndexErr("not found"))
+		tc.statusC <- &committer.TxStatus{TxID: "txid", ValidationCode: pb.TxValidationCode_VALID, BlockNumber: 43}
+		response, err := tc.server.CommitStatus(context.Background(), signedRequest)
+		require.NoError(t, err)
+		require.Equal(t, pb.TxValidationCode_VALID, response.Result)
+		require.Equal(t, uint64(43), response.BlockNumber)
+		require.True(t, released)
+
+		channel, txID := tc.notifier.RegisterTxArgsForCall(0)
+		require.Equal(t, "mychannel", channel)
+		require.Equal(t, "txid", txID)
+		require.Equal(t, 0, tc.ledger.GetBlockByTxIDCallCount())
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		tc := setup(t)
+		tc.ledger.GetTxValidationCodeByTxIDReturns(pb.TxValidationCode(-1), ledger.NotFoundInIndexErr("not found"))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := tc.server.CommitStatus(ctx, signedRequest)
+		require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+
+	t.Run("unknown channel", func(t *testing.T) {
+		tc := setup(t)
+		ledgers := &mocks.LedgerProvider{}
+		ledgers.LedgerReturns(nil, errors.New("channel mychannel not found"))
+		server := gateway.CreateServer(tc.localEndorser, nil, "Org1MSP", tc.discovery, ledgers, tc.notifier, tc.aclChecker, nil, gateway.Options{})
+		_, err := server.CommitStatus(context.Background(), signedRequest)
+		require.Equal(t, codes.NotFound, status.Code(err))
+		require.Equal(t, 0, tc.notifier.RegisterTxCallCount())
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		tc := setup(t)
+		tc.aclChecker.CheckACLReturns(errors.New("policy not satisfied"))
+		_, err := tc.server.CommitStatus(context.Background(), signedRequest)
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		require.Equal(t, 0, tc.ledger.GetTxValidationCodeByTxIDCallCount())
+	})
+
+	t.Run("malformed request", func(t *testing.T) {
+		tc := setup(t)
+		_, err := tc.server.CommitStatus(context.Background(), &gateway.SignedCommitStatusRequest{Request: []byte("garbage")})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
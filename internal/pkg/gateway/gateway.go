@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	dp "github.com/hyperledger/fabric-protos-go/discovery"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/common/flogging"
+	"github.com/osdi23p228/fabric/core/committer"
+	gossipcommon "github.com/osdi23p228/fabric/gossip/common"
+	gdiscovery "github.com/osdi23p228/fabric/gossip/discovery"
+	"github.com/osdi23p228/fabric/internal/pkg/comm"
+)
+
+var logger = flogging.MustGetLogger("gateway")
+
+//go:generate counterfeiter -o mocks/discovery.go --fake-name Discovery . Discovery
+
+// Discovery provides the gateway with the view of the network that the discovery
+// service offers to clients: the configuration of a channel, which carries the
+// orderer endpoints and TLS root certificates of its organizations, and the sets of
+// peers that can satisfy the endorsement policy of a chaincode. The alive members of
+// the network are used to close the connections to the peers that left it.
+type Discovery interface {
+	Config(channel string) (*dp.ConfigResult, error)
+	PeersForEndorsement(channel gossipcommon.ChannelID, interest *dp.ChaincodeInterest) (*dp.EndorsementDescriptor, error)
+	Peers() gdiscovery.Members
+}
+
+//go:generate counterfeiter -o mocks/ledgerprovider.go --fake-name LedgerProvider . LedgerProvider
+
+// LedgerProvider provides access to the ledgers of the channels joined by the peer.
+type LedgerProvider interface {
+	Ledger(channelID string) (Ledger, error)
+}
+
+//go:generate counterfeiter -o mocks/ledger.go --fake-name Ledger . Ledger
+
+// Ledger is the subset of the ledger of a channel used to find committed transactions.
+type Ledger interface {
+	GetTxValidationCodeByTxID(txID string) (pb.TxValidationCode, error)
+	GetBlockByTxID(txID string) (*common.Block, error)
+}
+
+//go:generate counterfeiter -o mocks/commitnotifier.go --fake-name CommitNotifier . CommitNotifier
+
+// CommitNotifier notifies the gateway of the commit of the transactions it waits for.
+type CommitNotifier interface {
+	RegisterTx(channelID, txID string) (<-chan *committer.TxStatus, func())
+}
+
+//go:generate counterfeiter -o mocks/aclchecker.go --fake-name ACLChecker . ACLChecker
+
+// ACLChecker checks the access of a client to a resource of a channel.
+type ACLChecker interface {
+	CheckACL(resName string, channelID string, idinfo interface{}) error
+}
+
+// Options are the configurable options of the gateway.
+type Options struct {
+	// EndorsementTimeout is the duration the gateway waits for a response from
+	// other endorsing peers.
+	EndorsementTimeout time.Duration
+}
+
+// Server represents the GRPC server for the Gateway.
+type Server struct {
+	registry   *registry
+	ledgers    LedgerProvider
+	notifier   CommitNotifier
+	aclChecker ACLChecker
+	options    Options
+}
+
+// CreateServer creates an embedded instance of the Gateway. The local endorser is the
+// endorser service of the peer hosting the gateway, whose serialized identity is
+// localIdentity. The client, whose timeout bounds the establishment of connections,
+// is used to connect to remote endorsing peers and orderers.
+func CreateServer(
+	localEndorser pb.EndorserServer,
+	localIdentity []byte,
+	localMSPID string,
+	discovery Discovery,
+	ledgers LedgerProvider,
+	notifier CommitNotifier,
+	aclChecker ACLChecker,
+	client *comm.GRPCClient,
+	options Options,
+) *Server {
+	return &Server{
+		registry:   newRegistry(localEndorser, localIdentity, localMSPID, discovery, client),
+		ledgers:    ledgers,
+		notifier:   notifier,
+		aclChecker: aclChecker,
+		options:    options,
+	}
+}
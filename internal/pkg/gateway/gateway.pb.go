@@ -0,0 +1,840 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: gateway.proto
+
+package gateway
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	common "github.com/hyperledger/fabric-protos-go/common"
+	peer "github.com/hyperledger/fabric-protos-go/peer"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// EndorseRequest contains the details required to obtain sufficient endorsements for a
+// transaction to be committed to the ledger.
+type EndorseRequest struct {
+	// The unique identifier for the transaction.
+	TransactionId string `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	// Identifier of the channel this request is bound for.
+	ChannelId string `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	// The signed proposal ready for endorsement.
+	ProposedTransaction *peer.SignedProposal `protobuf:"bytes,3,opt,name=proposed_transaction,json=proposedTransaction,proto3" json:"proposed_transaction,omitempty"`
+	// If targeting the peers of specific organizations (e.g. for private data scenarios),
+	// the list of organizations' MSP IDs should be supplied here.
+	EndorsingOrganizations []string `protobuf:"bytes,4,rep,name=endorsing_organizations,json=endorsingOrganizations,proto3" json:"endorsing_organizations,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
+	XXX_unrecognized       []byte   `json:"-"`
+	XXX_sizecache          int32    `json:"-"`
+}
+
+func (m *EndorseRequest) Reset()         { *m = EndorseRequest{} }
+func (m *EndorseRequest) String() string { return proto.CompactTextString(m) }
+func (*EndorseRequest) ProtoMessage()    {}
+func (*EndorseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{0}
+}
+
+func (m *EndorseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EndorseRequest.Unmarshal(m, b)
+}
+func (m *EndorseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EndorseRequest.Marshal(b, m, deterministic)
+}
+func (m *EndorseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EndorseRequest.Merge(m, src)
+}
+func (m *EndorseRequest) XXX_Size() int {
+	return xxx_messageInfo_EndorseRequest.Size(m)
+}
+func (m *EndorseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EndorseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EndorseRequest proto.InternalMessageInfo
+
+func (m *EndorseRequest) GetTransactionId() string {
+	if m != nil {
+		return m.TransactionId
+	}
+	return ""
+}
+
+func (m *EndorseRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *EndorseRequest) GetProposedTransaction() *peer.SignedProposal {
+	if m != nil {
+		return m.ProposedTransaction
+	}
+	return nil
+}
+
+func (m *EndorseRequest) GetEndorsingOrganizations() []string {
+	if m != nil {
+		return m.EndorsingOrganizations
+	}
+	return nil
+}
+
+// EndorseResponse returns the result of endorsing a transaction.
+type EndorseResponse struct {
+	// The unsigned set of transaction responses from the endorsing peers for signing by
+	// the client before submitting to the ordering service (via the gateway).
+	PreparedTransaction  *common.Envelope `protobuf:"bytes,1,opt,name=prepared_transaction,json=preparedTransaction,proto3" json:"prepared_transaction,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *EndorseResponse) Reset()         { *m = EndorseResponse{} }
+func (m *EndorseResponse) String() string { return proto.CompactTextString(m) }
+func (*EndorseResponse) ProtoMessage()    {}
+func (*EndorseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{1}
+}
+
+func (m *EndorseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EndorseResponse.Unmarshal(m, b)
+}
+func (m *EndorseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EndorseResponse.Marshal(b, m, deterministic)
+}
+func (m *EndorseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EndorseResponse.Merge(m, src)
+}
+func (m *EndorseResponse) XXX_Size() int {
+	return xxx_messageInfo_EndorseResponse.Size(m)
+}
+func (m *EndorseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EndorseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EndorseResponse proto.InternalMessageInfo
+
+func (m *EndorseResponse) GetPreparedTransaction() *common.Envelope {
+	if m != nil {
+		return m.PreparedTransaction
+	}
+	return nil
+}
+
+// SubmitRequest contains the details required to submit a transaction (update the ledger).
+type SubmitRequest struct {
+	// Identifier of the transaction to submit.
+	TransactionId string `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	// Identifier of the channel this request is bound for.
+	ChannelId string `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	// The signed set of endorsed transaction responses to submit.
+	PreparedTransaction  *common.Envelope `protobuf:"bytes,3,opt,name=prepared_transaction,json=preparedTransaction,proto3" json:"prepared_transaction,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *SubmitRequest) Reset()         { *m = SubmitRequest{} }
+func (m *SubmitRequest) String() string { return proto.CompactTextString(m) }
+func (*SubmitRequest) ProtoMessage()    {}
+func (*SubmitRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{2}
+}
+
+func (m *SubmitRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubmitRequest.Unmarshal(m, b)
+}
+func (m *SubmitRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubmitRequest.Marshal(b, m, deterministic)
+}
+func (m *SubmitRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubmitRequest.Merge(m, src)
+}
+func (m *SubmitRequest) XXX_Size() int {
+	return xxx_messageInfo_SubmitRequest.Size(m)
+}
+func (m *SubmitRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubmitRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubmitRequest proto.InternalMessageInfo
+
+func (m *SubmitRequest) GetTransactionId() string {
+	if m != nil {
+		return m.TransactionId
+	}
+	return ""
+}
+
+func (m *SubmitRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *SubmitRequest) GetPreparedTransaction() *common.Envelope {
+	if m != nil {
+		return m.PreparedTransaction
+	}
+	return nil
+}
+
+// SubmitResponse returns the result of submitting a transaction.
+type SubmitResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubmitResponse) Reset()         { *m = SubmitResponse{} }
+func (m *SubmitResponse) String() string { return proto.CompactTextString(m) }
+func (*SubmitResponse) ProtoMessage()    {}
+func (*SubmitResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{3}
+}
+
+func (m *SubmitResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubmitResponse.Unmarshal(m, b)
+}
+func (m *SubmitResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubmitResponse.Marshal(b, m, deterministic)
+}
+func (m *SubmitResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubmitResponse.Merge(m, src)
+}
+func (m *SubmitResponse) XXX_Size() int {
+	return xxx_messageInfo_SubmitResponse.Size(m)
+}
+func (m *SubmitResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubmitResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubmitResponse proto.InternalMessageInfo
+
+// SignedCommitStatusRequest contains a serialized CommitStatusRequest message, and a
+// digital signature for the serialized request message.
+type SignedCommitStatusRequest struct {
+	// Serialized CommitStatusRequest message.
+	Request []byte `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	// Signature for request message.
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignedCommitStatusRequest) Reset()         { *m = SignedCommitStatusRequest{} }
+func (m *SignedCommitStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*SignedCommitStatusRequest) ProtoMessage()    {}
+func (*SignedCommitStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{4}
+}
+
+func (m *SignedCommitStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedCommitStatusRequest.Unmarshal(m, b)
+}
+func (m *SignedCommitStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedCommitStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *SignedCommitStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedCommitStatusRequest.Merge(m, src)
+}
+func (m *SignedCommitStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_SignedCommitStatusRequest.Size(m)
+}
+func (m *SignedCommitStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedCommitStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedCommitStatusRequest proto.InternalMessageInfo
+
+func (m *SignedCommitStatusRequest) GetRequest() []byte {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (m *SignedCommitStatusRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// CommitStatusRequest contains the details required to check whether a transaction
+// committed.
+type CommitStatusRequest struct {
+	// Identifier of the transaction to check.
+	TransactionId string `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	// Identifier of the channel this request is bound for.
+	ChannelId string `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	// Client requestor identity.
+	Identity             []byte   `protobuf:"bytes,3,opt,name=identity,proto3" json:"identity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CommitStatusRequest) Reset()         { *m = CommitStatusRequest{} }
+func (m *CommitStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*CommitStatusRequest) ProtoMessage()    {}
+func (*CommitStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{5}
+}
+
+func (m *CommitStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitStatusRequest.Unmarshal(m, b)
+}
+func (m *CommitStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *CommitStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitStatusRequest.Merge(m, src)
+}
+func (m *CommitStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_CommitStatusRequest.Size(m)
+}
+func (m *CommitStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitStatusRequest proto.InternalMessageInfo
+
+func (m *CommitStatusRequest) GetTransactionId() string {
+	if m != nil {
+		return m.TransactionId
+	}
+	return ""
+}
+
+func (m *CommitStatusRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *CommitStatusRequest) GetIdentity() []byte {
+	if m != nil {
+		return m.Identity
+	}
+	return nil
+}
+
+// CommitStatusResponse returns the result of committing a transaction.
+type CommitStatusResponse struct {
+	// The result of the transaction commit, as determined by the validation of the
+	// transaction by the peer.
+	Result peer.TxValidationCode `protobuf:"varint,1,opt,name=result,proto3,enum=protos.TxValidationCode" json:"result,omitempty"`
+	// Block number that contains the transaction.
+	BlockNumber          uint64   `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CommitStatusResponse) Reset()         { *m = CommitStatusResponse{} }
+func (m *CommitStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*CommitStatusResponse) ProtoMessage()    {}
+func (*CommitStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{6}
+}
+
+func (m *CommitStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitStatusResponse.Unmarshal(m, b)
+}
+func (m *CommitStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *CommitStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitStatusResponse.Merge(m, src)
+}
+func (m *CommitStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_CommitStatusResponse.Size(m)
+}
+func (m *CommitStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitStatusResponse proto.InternalMessageInfo
+
+func (m *CommitStatusResponse) GetResult() peer.TxValidationCode {
+	if m != nil {
+		return m.Result
+	}
+	return peer.TxValidationCode_VALID
+}
+
+func (m *CommitStatusResponse) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+// EvaluateRequest contains the details required to evaluate a transaction (query the
+// ledger).
+type EvaluateRequest struct {
+	// Identifier of the transaction to evaluate.
+	TransactionId string `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	// Identifier of the channel this request is bound for.
+	ChannelId string `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	// The signed proposal ready for evaluation.
+	ProposedTransaction *peer.SignedProposal `protobuf:"bytes,3,opt,name=proposed_transaction,json=proposedTransaction,proto3" json:"proposed_transaction,omitempty"`
+	// If targeting the peers of specific organizations (e.g. for private data scenarios),
+	// the list of organizations' MSP IDs should be supplied here.
+	TargetOrganizations  []string `protobuf:"bytes,4,rep,name=target_organizations,json=targetOrganizations,proto3" json:"target_organizations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EvaluateRequest) Reset()         { *m = EvaluateRequest{} }
+func (m *EvaluateRequest) String() string { return proto.CompactTextString(m) }
+func (*EvaluateRequest) ProtoMessage()    {}
+func (*EvaluateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{7}
+}
+
+func (m *EvaluateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EvaluateRequest.Unmarshal(m, b)
+}
+func (m *EvaluateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EvaluateRequest.Marshal(b, m, deterministic)
+}
+func (m *EvaluateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EvaluateRequest.Merge(m, src)
+}
+func (m *EvaluateRequest) XXX_Size() int {
+	return xxx_messageInfo_EvaluateRequest.Size(m)
+}
+func (m *EvaluateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EvaluateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EvaluateRequest proto.InternalMessageInfo
+
+func (m *EvaluateRequest) GetTransactionId() string {
+	if m != nil {
+		return m.TransactionId
+	}
+	return ""
+}
+
+func (m *EvaluateRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *EvaluateRequest) GetProposedTransaction() *peer.SignedProposal {
+	if m != nil {
+		return m.ProposedTransaction
+	}
+	return nil
+}
+
+func (m *EvaluateRequest) GetTargetOrganizations() []string {
+	if m != nil {
+		return m.TargetOrganizations
+	}
+	return nil
+}
+
+// EvaluateResponse returns the result of evaluating a transaction.
+type EvaluateResponse struct {
+	// The response that is returned by the transaction function, as defined in
+	// peer/proposal_response.proto.
+	Result               *peer.Response `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *EvaluateResponse) Reset()         { *m = EvaluateResponse{} }
+func (m *EvaluateResponse) String() string { return proto.CompactTextString(m) }
+func (*EvaluateResponse) ProtoMessage()    {}
+func (*EvaluateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{8}
+}
+
+func (m *EvaluateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EvaluateResponse.Unmarshal(m, b)
+}
+func (m *EvaluateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EvaluateResponse.Marshal(b, m, deterministic)
+}
+func (m *EvaluateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EvaluateResponse.Merge(m, src)
+}
+func (m *EvaluateResponse) XXX_Size() int {
+	return xxx_messageInfo_EvaluateResponse.Size(m)
+}
+func (m *EvaluateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EvaluateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EvaluateResponse proto.InternalMessageInfo
+
+func (m *EvaluateResponse) GetResult() *peer.Response {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+// If any of the functions in the Gateway service returns an error, then it will be in
+// the format of a google.rpc.Status message. The 'details' field of this message will
+// be populated with extra information if the error is a result of one or more failed
+// requests to remote peers or orderer nodes. ErrorDetail contains details of errors
+// that are received by any of the endorsing peers or orderer nodes.
+type ErrorDetail struct {
+	// The address of the endorsing peer or orderer that returned an error.
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// The MSP Identifier of this node.
+	MspId string `protobuf:"bytes,2,opt,name=msp_id,json=mspId,proto3" json:"msp_id,omitempty"`
+	// The error message returned by this node.
+	Message              string   `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ErrorDetail) Reset()         { *m = ErrorDetail{} }
+func (m *ErrorDetail) String() string { return proto.CompactTextString(m) }
+func (*ErrorDetail) ProtoMessage()    {}
+func (*ErrorDetail) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{9}
+}
+
+func (m *ErrorDetail) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ErrorDetail.Unmarshal(m, b)
+}
+func (m *ErrorDetail) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ErrorDetail.Marshal(b, m, deterministic)
+}
+func (m *ErrorDetail) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ErrorDetail.Merge(m, src)
+}
+func (m *ErrorDetail) XXX_Size() int {
+	return xxx_messageInfo_ErrorDetail.Size(m)
+}
+func (m *ErrorDetail) XXX_DiscardUnknown() {
+	xxx_messageInfo_ErrorDetail.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ErrorDetail proto.InternalMessageInfo
+
+func (m *ErrorDetail) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *ErrorDetail) GetMspId() string {
+	if m != nil {
+		return m.MspId
+	}
+	return ""
+}
+
+func (m *ErrorDetail) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*EndorseRequest)(nil), "gateway.EndorseRequest")
+	proto.RegisterType((*EndorseResponse)(nil), "gateway.EndorseResponse")
+	proto.RegisterType((*SubmitRequest)(nil), "gateway.SubmitRequest")
+	proto.RegisterType((*SubmitResponse)(nil), "gateway.SubmitResponse")
+	proto.RegisterType((*SignedCommitStatusRequest)(nil), "gateway.SignedCommitStatusRequest")
+	proto.RegisterType((*CommitStatusRequest)(nil), "gateway.CommitStatusRequest")
+	proto.RegisterType((*CommitStatusResponse)(nil), "gateway.CommitStatusResponse")
+	proto.RegisterType((*EvaluateRequest)(nil), "gateway.EvaluateRequest")
+	proto.RegisterType((*EvaluateResponse)(nil), "gateway.EvaluateResponse")
+	proto.RegisterType((*ErrorDetail)(nil), "gateway.ErrorDetail")
+}
+
+func init() { proto.RegisterFile("gateway.proto", fileDescriptor_f1a937782ebbded5) }
+
+var fileDescriptor_f1a937782ebbded5 = []byte{
+	// 635 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x95, 0xdb, 0x92, 0x34, 0xd3, 0x34, 0xad, 0x36, 0x25, 0x4d, 0xad, 0x56, 0x2a, 0x96, 0x90,
+	0x72, 0x8a, 0x69, 0x7a, 0x00, 0xa4, 0x4a, 0x48, 0x94, 0x08, 0xe5, 0xc2, 0x87, 0x53, 0x55, 0x88,
+	0x4b, 0xb4, 0x89, 0x07, 0x77, 0x15, 0x7b, 0xd7, 0xec, 0xae, 0x5b, 0xca, 0x0f, 0xe1, 0xc0, 0xff,
+	0xe2, 0xc2, 0xaf, 0x41, 0xb6, 0xd7, 0x89, 0x4d, 0xd3, 0x03, 0x52, 0x0f, 0x9c, 0x92, 0x99, 0xf7,
+	0x66, 0xfd, 0xe6, 0xed, 0xcc, 0xc2, 0x76, 0x40, 0x35, 0xde, 0xd0, 0xdb, 0x7e, 0x2c, 0x85, 0x16,
+	0xa4, 0x6e, 0x42, 0xbb, 0x3d, 0x13, 0x51, 0x24, 0xb8, 0x9b, 0xff, 0xe4, 0xa8, 0xdd, 0x8e, 0x11,
+	0xa5, 0x1b, 0x4b, 0x11, 0x0b, 0x45, 0x43, 0x93, 0x3c, 0xac, 0x24, 0x27, 0x12, 0x55, 0x2c, 0xb8,
+	0x42, 0x83, 0x76, 0x32, 0x54, 0x4b, 0xca, 0x15, 0x9d, 0x69, 0x56, 0x1c, 0xe5, 0xfc, 0xb6, 0xa0,
+	0x35, 0xe4, 0xbe, 0x90, 0x0a, 0x3d, 0xfc, 0x9a, 0xa0, 0xd2, 0xe4, 0x29, 0xb4, 0x4a, 0xbc, 0x09,
+	0xf3, 0xbb, 0xd6, 0xb1, 0xd5, 0x6b, 0x78, 0xdb, 0xa5, 0xec, 0xc8, 0x27, 0x47, 0x00, 0xb3, 0x2b,
+	0xca, 0x39, 0x86, 0x29, 0x65, 0x2d, 0xa3, 0x34, 0x4c, 0x66, 0xe4, 0x93, 0x11, 0xec, 0xe5, 0x5a,
+	0xd0, 0x9f, 0x94, 0x0a, 0xbb, 0xeb, 0xc7, 0x56, 0x6f, 0x6b, 0xd0, 0xc9, 0x3f, 0xaf, 0xfa, 0x63,
+	0x16, 0x70, 0xf4, 0x3f, 0x18, 0xd5, 0x5e, 0xbb, 0xa8, 0xb9, 0x58, 0x96, 0x90, 0xe7, 0xb0, 0x8f,
+	0x99, 0x44, 0xc6, 0x83, 0x89, 0x90, 0x01, 0xe5, 0xec, 0x3b, 0x4d, 0x11, 0xd5, 0xdd, 0x38, 0x5e,
+	0xef, 0x35, 0xbc, 0xce, 0x02, 0x7e, 0x5f, 0x46, 0x9d, 0x4b, 0xd8, 0x59, 0xf4, 0x96, 0xbb, 0x41,
+	0xce, 0x53, 0x59, 0x18, 0x53, 0xf9, 0x97, 0x2c, 0x2b, 0x93, 0xb5, 0xdb, 0x37, 0x3e, 0x0f, 0xf9,
+	0x35, 0x86, 0x22, 0x46, 0xaf, 0x5d, 0xb0, 0x4b, 0x82, 0x9c, 0x9f, 0x16, 0x6c, 0x8f, 0x93, 0x69,
+	0xc4, 0xf4, 0xc3, 0x7a, 0x76, 0x9f, 0xb8, 0xf5, 0x7f, 0x11, 0xb7, 0x0b, 0xad, 0x42, 0x5b, 0xde,
+	0xb3, 0x33, 0x86, 0x83, 0xdc, 0xe6, 0x73, 0x11, 0x45, 0x4c, 0x8f, 0x35, 0xd5, 0x89, 0x2a, 0x94,
+	0x77, 0xa1, 0x2e, 0xf3, 0xbf, 0x99, 0xe4, 0xa6, 0x57, 0x84, 0xe4, 0x10, 0x1a, 0x8a, 0x05, 0x9c,
+	0xea, 0x44, 0x62, 0xa6, 0xb5, 0xe9, 0x2d, 0x13, 0xce, 0x0d, 0xb4, 0x57, 0x1d, 0xf7, 0x30, 0x46,
+	0xd8, 0xb0, 0xc9, 0x7c, 0xe4, 0x9a, 0xe9, 0xdb, 0xac, 0xf9, 0xa6, 0xb7, 0x88, 0x9d, 0x39, 0xec,
+	0x55, 0x3f, 0x6c, 0x6e, 0xf6, 0x19, 0xd4, 0x24, 0xaa, 0x24, 0xcc, 0xfb, 0x68, 0x0d, 0xba, 0xc5,
+	0x88, 0x5d, 0x7c, 0xbb, 0xa4, 0x21, 0xf3, 0xb3, 0x99, 0x38, 0x17, 0x3e, 0x7a, 0x86, 0x47, 0x9e,
+	0x40, 0x73, 0x1a, 0x8a, 0xd9, 0x7c, 0xc2, 0x93, 0x68, 0x8a, 0x32, 0x93, 0xb1, 0xe1, 0x6d, 0x65,
+	0xb9, 0x77, 0x59, 0xca, 0xf9, 0x65, 0xc1, 0xce, 0xf0, 0x9a, 0x86, 0x09, 0xd5, 0xff, 0xef, 0x7e,
+	0x9c, 0xc0, 0x9e, 0xa6, 0x32, 0x40, 0xbd, 0x72, 0x39, 0xda, 0x39, 0x56, 0xdd, 0x8c, 0x33, 0xd8,
+	0x5d, 0xb6, 0x65, 0x0c, 0xec, 0x55, 0x0c, 0x4c, 0xe7, 0xcd, 0x68, 0x28, 0x18, 0x85, 0x71, 0xce,
+	0x27, 0xd8, 0x1a, 0x4a, 0x29, 0xe4, 0x1b, 0xd4, 0x94, 0x85, 0xe9, 0x08, 0x51, 0xdf, 0x97, 0xa8,
+	0x94, 0x71, 0xa2, 0x08, 0xc9, 0x63, 0xa8, 0x45, 0x2a, 0x5e, 0xf6, 0xff, 0x28, 0x52, 0xf1, 0xc8,
+	0x4f, 0x0b, 0x22, 0x54, 0x8a, 0x06, 0x98, 0xb5, 0xdb, 0xf0, 0x8a, 0x70, 0xf0, 0x63, 0x0d, 0xea,
+	0x6f, 0xf3, 0xa7, 0x8f, 0x9c, 0x41, 0xdd, 0x6c, 0x2f, 0xd9, 0xef, 0x17, 0xcf, 0x63, 0xf5, 0xad,
+	0xb2, 0xbb, 0x77, 0x01, 0xd3, 0xcd, 0x4b, 0xa8, 0xe5, 0x6b, 0x40, 0x3a, 0x0b, 0x4e, 0x65, 0x67,
+	0xed, 0xfd, 0x3b, 0x79, 0x53, 0xfa, 0x11, 0x9a, 0xe5, 0x09, 0x23, 0xce, 0x92, 0x78, 0xdf, 0x1a,
+	0xd9, 0x47, 0x0b, 0xce, 0xca, 0xe1, 0x7c, 0x05, 0x9b, 0x85, 0xdf, 0xa4, 0xa4, 0xb9, 0x3a, 0x59,
+	0xf6, 0xc1, 0x0a, 0x24, 0x3f, 0xe0, 0xf5, 0xe9, 0xe7, 0x93, 0x80, 0xe9, 0xab, 0x64, 0x9a, 0x3e,
+	0x02, 0xae, 0x50, 0x3e, 0x1b, 0x9c, 0xc6, 0x83, 0xc1, 0x0b, 0xf7, 0x0b, 0x9d, 0x4a, 0x36, 0x73,
+	0x19, 0xd7, 0x28, 0x39, 0x0d, 0xdd, 0x78, 0x1e, 0xb8, 0xe6, 0x94, 0x69, 0x2d, 0xbb, 0xc0, 0xd3,
+	0x3f, 0x03, 0x00, 0x4b, 0x63, 0x4d, 0x72, 0x5d, 0x06, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// GatewayClient is the client API for Gateway service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GatewayClient interface {
+	// The Endorse service passes a proposed transaction to the gateway in order to
+	// obtain sufficient endorsement. The gateway will determine the endorsement plan
+	// for the requested chaincode and forward to the appropriate peers for endorsement.
+	// It will return to the client a prepared transaction in the form of an Envelope
+	// message, which the client must sign before invoking Submit.
+	Endorse(ctx context.Context, in *EndorseRequest, opts ...grpc.CallOption) (*EndorseResponse, error)
+	// The Submit service will process the prepared transaction returned from Endorse,
+	// once it has been signed by the client. It will send it to the ordering service.
+	Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error)
+	// The CommitStatus service returns the validation code of a committed transaction,
+	// along with the number of the block it was committed in. If the transaction has
+	// not yet committed, the call waits until it does, or until the client cancels it.
+	CommitStatus(ctx context.Context, in *SignedCommitStatusRequest, opts ...grpc.CallOption) (*CommitStatusResponse, error)
+	// The Evaluate service passes a proposed transaction to the gateway in order to
+	// invoke the transaction function and return the result to the client. No ledger
+	// updates are made.
+	Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error)
+}
+
+type gatewayClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGatewayClient(cc grpc.ClientConnInterface) GatewayClient {
+	return &gatewayClient{cc}
+}
+
+func (c *gatewayClient) Endorse(ctx context.Context, in *EndorseRequest, opts ...grpc.CallOption) (*EndorseResponse, error) {
+	out := new(EndorseResponse)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/Endorse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error) {
+	out := new(SubmitResponse)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/Submit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) CommitStatus(ctx context.Context, in *SignedCommitStatusRequest, opts ...grpc.CallOption) (*CommitStatusResponse, error) {
+	out := new(CommitStatusResponse)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/CommitStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error) {
+	out := new(EvaluateResponse)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/Evaluate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GatewayServer is the server API for Gateway service.
+type GatewayServer interface {
+	// The Endorse service passes a proposed transaction to the gateway in order to
+	// obtain sufficient endorsement. The gateway will determine the endorsement plan
+	// for the requested chaincode and forward to the appropriate peers for endorsement.
+	// It will return to the client a prepared transaction in the form of an Envelope
+	// message, which the client must sign before invoking Submit.
+	Endorse(context.Context, *EndorseRequest) (*EndorseResponse, error)
+	// The Submit service will process the prepared transaction returned from Endorse,
+	// once it has been signed by the client. It will send it to the ordering service.
+	Submit(context.Context, *SubmitRequest) (*SubmitResponse, error)
+	// The CommitStatus service returns the validation code of a committed transaction,
+	// along with the number of the block it was committed in. If the transaction has
+	// not yet committed, the call waits until it does, or until the client cancels it.
+	CommitStatus(context.Context, *SignedCommitStatusRequest) (*CommitStatusResponse, error)
+	// The Evaluate service passes a proposed transaction to the gateway in order to
+	// invoke the transaction function and return the result to the client. No ledger
+	// updates are made.
+	Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error)
+}
+
+// UnimplementedGatewayServer can be embedded to have forward compatible implementations.
+type UnimplementedGatewayServer struct {
+}
+
+func (*UnimplementedGatewayServer) Endorse(ctx context.Context, req *EndorseRequest) (*EndorseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Endorse not implemented")
+}
+func (*UnimplementedGatewayServer) Submit(ctx context.Context, req *SubmitRequest) (*SubmitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Submit not implemented")
+}
+func (*UnimplementedGatewayServer) CommitStatus(ctx context.Context, req *SignedCommitStatusRequest) (*CommitStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitStatus not implemented")
+}
+func (*UnimplementedGatewayServer) Evaluate(ctx context.Context, req *EvaluateRequest) (*EvaluateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Evaluate not implemented")
+}
+
+func RegisterGatewayServer(s *grpc.Server, srv GatewayServer) {
+	s.RegisterService(&_Gateway_serviceDesc, srv)
+}
+
+func _Gateway_Endorse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EndorseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).Endorse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/Endorse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).Endorse(ctx, req.(*EndorseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_Submit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).Submit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/Submit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).Submit(ctx, req.(*SubmitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_CommitStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignedCommitStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).CommitStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/CommitStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).CommitStatus(ctx, req.(*SignedCommitStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_Evaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).Evaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/Evaluate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).Evaluate(ctx, req.(*EvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Gateway_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "gateway.Gateway",
+	HandlerType: (*GatewayServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Endorse",
+			Handler:    _Gateway_Endorse_Handler,
+		},
+		{
+			MethodName: "Submit",
+			Handler:    _Gateway_Submit_Handler,
+		},
+		{
+			MethodName: "CommitStatus",
+			Handler:    _Gateway_CommitStatus_Handler,
+		},
+		{
+			MethodName: "Evaluate",
+			Handler:    _Gateway_Evaluate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gateway.proto",
+}
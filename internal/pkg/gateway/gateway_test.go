@@ -0,0 +1,16 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway_test
+
+import (
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+)
+
+//go:generate counterfeiter -o mocks/endorserserver.go --fake-name EndorserServer . endorserServer
+type endorserServer interface {
+	pb.EndorserServer
+}
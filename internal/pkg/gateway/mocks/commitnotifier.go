@@ -0,0 +1,119 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"sync"
+
+	"github.com/osdi23p228/fabric/core/committer"
+	"github.com/osdi23p228/fabric/internal/pkg/gateway"
+)
+
+type CommitNotifier struct {
+	RegisterTxStub        func(string, string) (<-chan *committer.TxStatus, func())
+	registerTxMutex       sync.RWMutex
+	registerTxArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	registerTxReturns struct {
+		result1 <-chan *committer.TxStatus
+		result2 func()
+	}
+	registerTxReturnsOnCall map[int]struct {
+		result1 <-chan *committer.TxStatus
+		result2 func()
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CommitNotifier) RegisterTx(arg1 string, arg2 string) (<-chan *committer.TxStatus, func()) {
+	fake.registerTxMutex.Lock()
+	ret, specificReturn := fake.registerTxReturnsOnCall[len(fake.registerTxArgsForCall)]
+	fake.registerTxArgsForCall = append(fake.registerTxArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RegisterTxStub
+	fakeReturns := fake.registerTxReturns
+	fake.recordInvocation("RegisterTx", []interface{}{arg1, arg2})
+	fake.registerTxMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CommitNotifier) RegisterTxCallCount() int {
+	fake.registerTxMutex.RLock()
+	defer fake.registerTxMutex.RUnlock()
+	return len(fake.registerTxArgsForCall)
+}
+
+func (fake *CommitNotifier) RegisterTxCalls(stub func(string, string) (<-chan *committer.TxStatus, func())) {
+	fake.registerTxMutex.Lock()
+	defer fake.registerTxMutex.Unlock()
+	fake.RegisterTxStub = stub
+}
+
+func (fake *CommitNotifier) RegisterTxArgsForCall(i int) (string, string) {
+	fake.registerTxMutex.RLock()
+	defer fake.registerTxMutex.RUnlock()
+	argsForCall := fake.registerTxArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *CommitNotifier) RegisterTxReturns(result1 <-chan *committer.TxStatus, result2 func()) {
+	fake.registerTxMutex.Lock()
+	defer fake.registerTxMutex.Unlock()
+	fake.RegisterTxStub = nil
+	fake.registerTxReturns = struct {
+		result1 <-chan *committer.TxStatus
+		result2 func()
+	}{result1, result2}
+}
+
+func (fake *CommitNotifier) RegisterTxReturnsOnCall(i int, result1 <-chan *committer.TxStatus, result2 func()) {
+	fake.registerTxMutex.Lock()
+	defer fake.registerTxMutex.Unlock()
+	fake.RegisterTxStub = nil
+	if fake.registerTxReturnsOnCall == nil {
+		fake.registerTxReturnsOnCall = make(map[int]struct {
+			result1 <-chan *committer.TxStatus
+			result2 func()
+		})
+	}
+	fake.registerTxReturnsOnCall[i] = struct {
+		result1 <-chan *committer.TxStatus
+		result2 func()
+	}{result1, result2}
+}
+
+func (fake *CommitNotifier) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.registerTxMutex.RLock()
+	defer fake.registerTxMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *CommitNotifier) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ gateway.CommitNotifier = new(CommitNotifier)
@@ -0,0 +1,264 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/osdi23p228/fabric/gossip/common"
+	discoverya "github.com/osdi23p228/fabric/gossip/discovery"
+	"github.com/osdi23p228/fabric/internal/pkg/gateway"
+)
+
+type Discovery struct {
+	ConfigStub        func(string) (*discovery.ConfigResult, error)
+	configMutex       sync.RWMutex
+	configArgsForCall []struct {
+		arg1 string
+	}
+	configReturns struct {
+		result1 *discovery.ConfigResult
+		result2 error
+	}
+	configReturnsOnCall map[int]struct {
+		result1 *discovery.ConfigResult
+		result2 error
+	}
+	PeersForEndorsementStub        func(common.ChannelID, *discovery.ChaincodeInterest) (*discovery.EndorsementDescriptor, error)
+	peersForEndorsementMutex       sync.RWMutex
+	peersForEndorsementArgsForCall []struct {
+		arg1 common.ChannelID
+		arg2 *discovery.ChaincodeInterest
+	}
+	peersForEndorsementReturns struct {
+		result1 *discovery.EndorsementDescriptor
+		result2 error
+	}
+	peersForEndorsementReturnsOnCall map[int]struct {
+		result1 *discovery.EndorsementDescriptor
+		result2 error
+	}
+	PeersStub        func() discoverya.Members
+	peersMutex       sync.RWMutex
+	peersArgsForCall []struct {
+	}
+	peersReturns struct {
+		result1 discoverya.Members
+	}
+	peersReturnsOnCall map[int]struct {
+		result1 discoverya.Members
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Discovery) Config(arg1 string) (*discovery.ConfigResult, error) {
+	fake.configMutex.Lock()
+	ret, specificReturn := fake.configReturnsOnCall[len(fake.configArgsForCall)]
+	fake.configArgsForCall = append(fake.configArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ConfigStub
+	fakeReturns := fake.configReturns
+	fake.recordInvocation("Config", []interface{}{arg1})
+	fake.configMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Discovery) ConfigCallCount() int {
+	fake.configMutex.RLock()
+	defer fake.configMutex.RUnlock()
+	return len(fake.configArgsForCall)
+}
+
+func (fake *Discovery) ConfigCalls(stub func(string) (*discovery.ConfigResult, error)) {
+	fake.configMutex.Lock()
+	defer fake.configMutex.Unlock()
+	fake.ConfigStub = stub
+}
+
+func (fake *Discovery) ConfigArgsForCall(i int) string {
+	fake.configMutex.RLock()
+	defer fake.configMutex.RUnlock()
+	argsForCall := fake.configArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Discovery) ConfigReturns(result1 *discovery.ConfigResult, result2 error) {
+	fake.configMutex.Lock()
+	defer fake.configMutex.Unlock()
+	fake.ConfigStub = nil
+	fake.configReturns = struct {
+		result1 *discovery.ConfigResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Discovery) ConfigReturnsOnCall(i int, result1 *discovery.ConfigResult, result2 error) {
+	fake.configMutex.Lock()
+	defer fake.configMutex.Unlock()
+	fake.ConfigStub = nil
+	if fake.configReturnsOnCall == nil {
+		fake.configReturnsOnCall = make(map[int]struct {
+			result1 *discovery.ConfigResult
+			result2 error
+		})
+	}
+	fake.configReturnsOnCall[i] = struct {
+		result1 *discovery.ConfigResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Discovery) PeersForEndorsement(arg1 common.ChannelID, arg2 *discovery.ChaincodeInterest) (*discovery.EndorsementDescriptor, error) {
+	fake.peersForEndorsementMutex.Lock()
+	ret, specificReturn := fake.peersForEndorsementReturnsOnCall[len(fake.peersForEndorsementArgsForCall)]
+	fake.peersForEndorsementArgsForCall = append(fake.peersForEndorsementArgsForCall, struct {
+		arg1 common.ChannelID
+		arg2 *discovery.ChaincodeInterest
+	}{arg1, arg2})
+	stub := fake.PeersForEndorsementStub
+	fakeReturns := fake.peersForEndorsementReturns
+	fake.recordInvocation("PeersForEndorsement", []interface{}{arg1, arg2})
+	fake.peersForEndorsementMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Discovery) PeersForEndorsementCallCount() int {
+	fake.peersForEndorsementMutex.RLock()
+	defer fake.peersForEndorsementMutex.RUnlock()
+	return len(fake.peersForEndorsementArgsForCall)
+}
+
+func (fake *Discovery) PeersForEndorsementCalls(stub func(common.ChannelID, *discovery.ChaincodeInterest) (*discovery.EndorsementDescriptor, error)) {
+	fake.peersForEndorsementMutex.Lock()
+	defer fake.peersForEndorsementMutex.Unlock()
+	fake.PeersForEndorsementStub = stub
+}
+
+func (fake *Discovery) PeersForEndorsementArgsForCall(i int) (common.ChannelID, *discovery.ChaincodeInterest) {
+	fake.peersForEndorsementMutex.RLock()
+	defer fake.peersForEndorsementMutex.RUnlock()
+	argsForCall := fake.peersForEndorsementArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Discovery) PeersForEndorsementReturns(result1 *discovery.EndorsementDescriptor, result2 error) {
+	fake.peersForEndorsementMutex.Lock()
+	defer fake.peersForEndorsementMutex.Unlock()
+	fake.PeersForEndorsementStub = nil
+	fake.peersForEndorsementReturns = struct {
+		result1 *discovery.EndorsementDescriptor
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Discovery) PeersForEndorsementReturnsOnCall(i int, result1 *discovery.EndorsementDescriptor, result2 error) {
+	fake.peersForEndorsementMutex.Lock()
+	defer fake.peersForEndorsementMutex.Unlock()
+	fake.PeersForEndorsementStub = nil
+	if fake.peersForEndorsementReturnsOnCall == nil {
+		fake.peersForEndorsementReturnsOnCall = make(map[int]struct {
+			result1 *discovery.EndorsementDescriptor
+			result2 error
+		})
+	}
+	fake.peersForEndorsementReturnsOnCall[i] = struct {
+		result1 *discovery.EndorsementDescriptor
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Discovery) Peers() discoverya.Members {
+	fake.peersMutex.Lock()
+	ret, specificReturn := fake.peersReturnsOnCall[len(fake.peersArgsForCall)]
+	fake.peersArgsForCall = append(fake.peersArgsForCall, struct {
+	}{})
+	fake.recordInvocation("Peers", []interface{}{})
+	fake.peersMutex.Unlock()
+	if fake.PeersStub != nil {
+		return fake.PeersStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.peersReturns
+	return fakeReturns.result1
+}
+
+func (fake *Discovery) PeersCallCount() int {
+	fake.peersMutex.RLock()
+	defer fake.peersMutex.RUnlock()
+	return len(fake.peersArgsForCall)
+}
+
+func (fake *Discovery) PeersCalls(stub func() discoverya.Members) {
+	fake.peersMutex.Lock()
+	defer fake.peersMutex.Unlock()
+	fake.PeersStub = stub
+}
+
+func (fake *Discovery) PeersReturns(result1 discoverya.Members) {
+	fake.peersMutex.Lock()
+	defer fake.peersMutex.Unlock()
+	fake.PeersStub = nil
+	fake.peersReturns = struct {
+		result1 discoverya.Members
+	}{result1}
+}
+
+func (fake *Discovery) PeersReturnsOnCall(i int, result1 discoverya.Members) {
+	fake.peersMutex.Lock()
+	defer fake.peersMutex.Unlock()
+	fake.PeersStub = nil
+	if fake.peersReturnsOnCall == nil {
+		fake.peersReturnsOnCall = make(map[int]struct {
+			result1 discoverya.Members
+		})
+	}
+	fake.peersReturnsOnCall[i] = struct {
+		result1 discoverya.Members
+	}{result1}
+}
+
+func (fake *Discovery) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.configMutex.RLock()
+	defer fake.configMutex.RUnlock()
+	fake.peersForEndorsementMutex.RLock()
+	defer fake.peersForEndorsementMutex.RUnlock()
+	fake.peersMutex.RLock()
+	defer fake.peersMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *Discovery) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ gateway.Discovery = new(Discovery)
@@ -0,0 +1,197 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/internal/pkg/gateway"
+)
+
+type Ledger struct {
+	GetBlockByTxIDStub        func(string) (*common.Block, error)
+	getBlockByTxIDMutex       sync.RWMutex
+	getBlockByTxIDArgsForCall []struct {
+		arg1 string
+	}
+	getBlockByTxIDReturns struct {
+		result1 *common.Block
+		result2 error
+	}
+	getBlockByTxIDReturnsOnCall map[int]struct {
+		result1 *common.Block
+		result2 error
+	}
+	GetTxValidationCodeByTxIDStub        func(string) (peer.TxValidationCode, error)
+	getTxValidationCodeByTxIDMutex       sync.RWMutex
+	getTxValidationCodeByTxIDArgsForCall []struct {
+		arg1 string
+	}
+	getTxValidationCodeByTxIDReturns struct {
+		result1 peer.TxValidationCode
+		result2 error
+	}
+	getTxValidationCodeByTxIDReturnsOnCall map[int]struct {
+		result1 peer.TxValidationCode
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Ledger) GetBlockByTxID(arg1 string) (*common.Block, error) {
+	fake.getBlockByTxIDMutex.Lock()
+	ret, specificReturn := fake.getBlockByTxIDReturnsOnCall[len(fake.getBlockByTxIDArgsForCall)]
+	fake.getBlockByTxIDArgsForCall = append(fake.getBlockByTxIDArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetBlockByTxIDStub
+	fakeReturns := fake.getBlockByTxIDReturns
+	fake.recordInvocation("GetBlockByTxID", []interface{}{arg1})
+	fake.getBlockByTxIDMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Ledger) GetBlockByTxIDCallCount() int {
+	fake.getBlockByTxIDMutex.RLock()
+	defer fake.getBlockByTxIDMutex.RUnlock()
+	return len(fake.getBlockByTxIDArgsForCall)
+}
+
+func (fake *Ledger) GetBlockByTxIDCalls(stub func(string) (*common.Block, error)) {
+	fake.getBlockByTxIDMutex.Lock()
+	defer fake.getBlockByTxIDMutex.Unlock()
+	fake.GetBlockByTxIDStub = stub
+}
+
+func (fake *Ledger) GetBlockByTxIDArgsForCall(i int) string {
+	fake.getBlockByTxIDMutex.RLock()
+	defer fake.getBlockByTxIDMutex.RUnlock()
+	argsForCall := fake.getBlockByTxIDArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Ledger) GetBlockByTxIDReturns(result1 *common.Block, result2 error) {
+	fake.getBlockByTxIDMutex.Lock()
+	defer fake.getBlockByTxIDMutex.Unlock()
+	fake.GetBlockByTxIDStub = nil
+	fake.getBlockByTxIDReturns = struct {
+		result1 *common.Block
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Ledger) GetBlockByTxIDReturnsOnCall(i int, result1 *common.Block, result2 error) {
+	fake.getBlockByTxIDMutex.Lock()
+	defer fake.getBlockByTxIDMutex.Unlock()
+	fake.GetBlockByTxIDStub = nil
+	if fake.getBlockByTxIDReturnsOnCall == nil {
+		fake.getBlockByTxIDReturnsOnCall = make(map[int]struct {
+			result1 *common.Block
+			result2 error
+		})
+	}
+	fake.getBlockByTxIDReturnsOnCall[i] = struct {
+		result1 *common.Block
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Ledger) GetTxValidationCodeByTxID(arg1 string) (peer.TxValidationCode, error) {
+	fake.getTxValidationCodeByTxIDMutex.Lock()
+	ret, specificReturn := fake.getTxValidationCodeByTxIDReturnsOnCall[len(fake.getTxValidationCodeByTxIDArgsForCall)]
+	fake.getTxValidationCodeByTxIDArgsForCall = append(fake.getTxValidationCodeByTxIDArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetTxValidationCodeByTxIDStub
+	fakeReturns := fake.getTxValidationCodeByTxIDReturns
+	fake.recordInvocation("GetTxValidationCodeByTxID", []interface{}{arg1})
+	fake.getTxValidationCodeByTxIDMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Ledger) GetTxValidationCodeByTxIDCallCount() int {
+	fake.getTxValidationCodeByTxIDMutex.RLock()
+	defer fake.getTxValidationCodeByTxIDMutex.RUnlock()
+	return len(fake.getTxValidationCodeByTxIDArgsForCall)
+}
+
+func (fake *Ledger) GetTxValidationCodeByTxIDCalls(stub func(string) (peer.TxValidationCode, error)) {
+	fake.getTxValidationCodeByTxIDMutex.Lock()
+	defer fake.getTxValidationCodeByTxIDMutex.Unlock()
+	fake.GetTxValidationCodeByTxIDStub = stub
+}
+
+func (fake *Ledger) GetTxValidationCodeByTxIDArgsForCall(i int) string {
+	fake.getTxValidationCodeByTxIDMutex.RLock()
+	defer fake.getTxValidationCodeByTxIDMutex.RUnlock()
+	argsForCall := fake.getTxValidationCodeByTxIDArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Ledger) GetTxValidationCodeByTxIDReturns(result1 peer.TxValidationCode, result2 error) {
+	fake.getTxValidationCodeByTxIDMutex.Lock()
+	defer fake.getTxValidationCodeByTxIDMutex.Unlock()
+	fake.GetTxValidationCodeByTxIDStub = nil
+	fake.getTxValidationCodeByTxIDReturns = struct {
+		result1 peer.TxValidationCode
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Ledger) GetTxValidationCodeByTxIDReturnsOnCall(i int, result1 peer.TxValidationCode, result2 error) {
+	fake.getTxValidationCodeByTxIDMutex.Lock()
+	defer fake.getTxValidationCodeByTxIDMutex.Unlock()
+	fake.GetTxValidationCodeByTxIDStub = nil
+	if fake.getTxValidationCodeByTxIDReturnsOnCall == nil {
+		fake.getTxValidationCodeByTxIDReturnsOnCall = make(map[int]struct {
+			result1 peer.TxValidationCode
+			result2 error
+		})
+	}
+	fake.getTxValidationCodeByTxIDReturnsOnCall[i] = struct {
+		result1 peer.TxValidationCode
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Ledger) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getBlockByTxIDMutex.RLock()
+	defer fake.getBlockByTxIDMutex.RUnlock()
+	fake.getTxValidationCodeByTxIDMutex.RLock()
+	defer fake.getTxValidationCodeByTxIDMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *Ledger) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ gateway.Ledger = new(Ledger)
@@ -0,0 +1,432 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sync"
+
+	dp "github.com/hyperledger/fabric-protos-go/discovery"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	gossipcommon "github.com/osdi23p228/fabric/gossip/common"
+	"github.com/osdi23p228/fabric/gossip/protoext"
+	"github.com/osdi23p228/fabric/internal/pkg/comm"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+type endorser struct {
+	client  pb.EndorserClient
+	conn    *grpc.ClientConn
+	address string
+	mspid   string
+}
+
+type orderer struct {
+	client  ab.AtomicBroadcastClient
+	conn    *grpc.ClientConn
+	address string
+	mspid   string
+}
+
+type dialer func(address string, tlsRootCerts [][]byte) (*grpc.ClientConn, error)
+
+// registry resolves the endorsers and orderers that requests are forwarded to, and
+// maintains the connections to them. Connections are established without holding
+// the mutex, and are closed once the peer leaves the membership known to discovery,
+// or once the orderer is no longer part of the configuration of any channel.
+type registry struct {
+	localEndorser *endorser
+	localIdentity []byte
+	discovery     Discovery
+	dial          dialer
+
+	mutex           sync.Mutex
+	remoteEndorsers map[string]*endorser
+	remoteOrderers  map[string]*orderer
+	channelOrderers map[string][]string
+}
+
+func newRegistry(localEndorser pb.EndorserServer, localIdentity []byte, localMSPID string, discovery Discovery, client *comm.GRPCClient) *registry {
+	dial := func(address string, tlsRootCerts [][]byte) (*grpc.ClientConn, error) {
+		certPool := x509.NewCertPool()
+		for _, root := range tlsRootCerts {
+			if err := comm.AddPemToCertPool(root, certPool); err != nil {
+				return nil, err
+			}
+		}
+		return client.NewConnection(address, comm.CertPoolOverride(certPool))
+	}
+
+	return &registry{
+		localEndorser:   &endorser{client: &localEndorserClient{server: localEndorser}, address: "localhost", mspid: localMSPID},
+		localIdentity:   localIdentity,
+		discovery:       discovery,
+		dial:            dial,
+		remoteEndorsers: map[string]*endorser{},
+		remoteOrderers:  map[string]*orderer{},
+		channelOrderers: map[string][]string{},
+	}
+}
+
+// evaluator returns an endorser of one of the given organizations, or of any
+// organization if none is given, that can run the given chaincode. The local peer
+// is preferred when it qualifies.
+func (r *registry) evaluator(channel, chaincode string, targetOrgs []string) (*endorser, error) {
+	descriptor, tlsRoots, err := r.endorsementDescriptor(channel, chaincode)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*dp.Peer
+	for _, peer := range allPeers(descriptor) {
+		mspid, err := mspIDOf(peer)
+		if err != nil {
+			logger.Warningf("Ignoring peer: %s", err)
+			continue
+		}
+		if len(targetOrgs) > 0 && !contains(targetOrgs, mspid) {
+			continue
+		}
+		if r.isLocal(peer) {
+			return r.localEndorser, nil
+		}
+		candidates = append(candidates, peer)
+	}
+
+	for _, peer := range candidates {
+		e, err := r.endorser(peer, tlsRoots)
+		if err != nil {
+			logger.Warningf("Failed to connect to peer: %s", err)
+			continue
+		}
+		return e, nil
+	}
+
+	return nil, errors.Errorf("no peers available to evaluate chaincode %s on channel %s", chaincode, channel)
+}
+
+// endorsers returns a set of endorsers whose endorsements satisfy the endorsement
+// policy of the given chaincode. If organizations are given, one endorser of each of
+// them is returned instead. The local peer is preferred when it qualifies.
+func (r *registry) endorsers(channel, chaincode string, endorsingOrgs []string) ([]*endorser, error) {
+	descriptor, tlsRoots, err := r.endorsementDescriptor(channel, chaincode)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(endorsingOrgs) > 0 {
+		return r.endorsersOfOrgs(channel, chaincode, endorsingOrgs, allPeers(descriptor), tlsRoots)
+	}
+
+	for _, layout := range descriptor.Layouts {
+		endorsers, ok := r.endorsersForLayout(layout, descriptor.EndorsersByGroups, tlsRoots)
+		if ok {
+			return endorsers, nil
+		}
+	}
+
+	return nil, errors.Errorf("no combination of peers can be found to satisfy the endorsement policy of chaincode %s on channel %s", chaincode, channel)
+}
+
+func (r *registry) endorsersOfOrgs(channel, chaincode string, orgs []string, peers []*dp.Peer, tlsRoots map[string][][]byte) ([]*endorser, error) {
+	var endorsers []*endorser
+	for _, org := range orgs {
+		var orgEndorser *endorser
+		for _, peer := range r.localFirst(peers) {
+			mspid, err := mspIDOf(peer)
+			if err != nil || mspid != org {
+				continue
+			}
+			e, err := r.endorser(peer, tlsRoots)
+			if err != nil {
+				logger.Warningf("Failed to connect to peer: %s", err)
+				continue
+			}
+			orgEndorser = e
+			break
+		}
+		if orgEndorser == nil {
+			return nil, errors.Errorf("no peers of organization %s available to endorse chaincode %s on channel %s", org, chaincode, channel)
+		}
+		endorsers = append(endorsers, orgEndorser)
+	}
+	return endorsers, nil
+}
+
+func (r *registry) endorsersForLayout(layout *dp.Layout, endorsersByGroups map[string]*dp.Peers, tlsRoots map[string][][]byte) ([]*endorser, bool) {
+	selected := map[string]bool{}
+	var endorsers []*endorser
+	for group, quantity := range layout.QuantitiesByGroup {
+		var count uint32
+		for _, peer := range r.localFirst(endorsersByGroups[group].GetPeers()) {
+			if count == quantity {
+				break
+			}
+			key := string(peer.Identity)
+			if selected[key] {
+				continue
+			}
+			e, err := r.endorser(peer, tlsRoots)
+			if err != nil {
+				logger.Warningf("Failed to connect to peer: %s", err)
+				continue
+			}
+			selected[key] = true
+			endorsers = append(endorsers, e)
+			count++
+		}
+		if count < quantity {
+			return nil, false
+		}
+	}
+	return endorsers, true
+}
+
+// orderers returns the orderers of the given channel that could be connected to.
+func (r *registry) orderers(channel string) ([]*orderer, error) {
+	config, err := r.discovery.Config(channel)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get the configuration of channel %s", channel)
+	}
+	tlsRoots := tlsRootCerts(config)
+
+	var addresses []string
+	for _, endpoints := range config.Orderers {
+		for _, endpoint := range endpoints.Endpoint {
+			addresses = append(addresses, fmt.Sprintf("%s:%d", endpoint.Host, endpoint.Port))
+		}
+	}
+	r.evictOrderers(channel, addresses)
+
+	var orderers []*orderer
+	for mspid, endpoints := range config.Orderers {
+		for _, endpoint := range endpoints.Endpoint {
+			address := fmt.Sprintf("%s:%d", endpoint.Host, endpoint.Port)
+			o, err := r.orderer(address, mspid, tlsRoots[mspid])
+			if err != nil {
+				logger.Warningf("Failed to connect to orderer %s: %s", address, err)
+				continue
+			}
+			orderers = append(orderers, o)
+		}
+	}
+	if len(orderers) == 0 {
+		return nil, errors.Errorf("no orderers available for channel %s", channel)
+	}
+	return orderers, nil
+}
+
+func (r *registry) endorsementDescriptor(channel, chaincode string) (*dp.EndorsementDescriptor, map[string][][]byte, error) {
+	r.evictEndorsers()
+
+	interest := &dp.ChaincodeInterest{Chaincodes: []*dp.ChaincodeCall{{Name: chaincode}}}
+	descriptor, err := r.discovery.PeersForEndorsement(gossipcommon.ChannelID(channel), interest)
+	if err != nil {
+		return nil, nil, errors.WithMessagef(err, "failed to find the endorsers of chaincode %s on channel %s", chaincode, channel)
+	}
+	config, err := r.discovery.Config(channel)
+	if err != nil {
+		return nil, nil, errors.WithMessagef(err, "failed to get the configuration of channel %s", channel)
+	}
+	return descriptor, tlsRootCerts(config), nil
+}
+
+func (r *registry) endorser(peer *dp.Peer, tlsRoots map[string][][]byte) (*endorser, error) {
+	if r.isLocal(peer) {
+		return r.localEndorser, nil
+	}
+
+	mspid, err := mspIDOf(peer)
+	if err != nil {
+		return nil, err
+	}
+	address, err := endpointOf(peer)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	e, ok := r.remoteEndorsers[address]
+	r.mutex.Unlock()
+	if ok {
+		return e, nil
+	}
+
+	conn, err := r.dial(address, tlsRoots[mspid])
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to connect to peer %s", address)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if e, ok := r.remoteEndorsers[address]; ok {
+		// another request connected to the peer in the meantime
+		conn.Close()
+		return e, nil
+	}
+	e = &endorser{client: pb.NewEndorserClient(conn), conn: conn, address: address, mspid: mspid}
+	r.remoteEndorsers[address] = e
+	return e, nil
+}
+
+func (r *registry) orderer(address, mspid string, tlsRootCerts [][]byte) (*orderer, error) {
+	r.mutex.Lock()
+	o, ok := r.remoteOrderers[address]
+	r.mutex.Unlock()
+	if ok {
+		return o, nil
+	}
+
+	conn, err := r.dial(address, tlsRootCerts)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if o, ok := r.remoteOrderers[address]; ok {
+		// another request connected to the orderer in the meantime
+		conn.Close()
+		return o, nil
+	}
+	o = &orderer{client: ab.NewAtomicBroadcastClient(conn), conn: conn, address: address, mspid: mspid}
+	r.remoteOrderers[address] = o
+	return o, nil
+}
+
+// evictEndorsers closes the connections to the peers which are no longer alive
+// members of the network known to discovery.
+func (r *registry) evictEndorsers() {
+	alive := map[string]bool{}
+	for _, member := range r.discovery.Peers() {
+		alive[member.Endpoint] = true
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for address, e := range r.remoteEndorsers {
+		if alive[address] {
+			continue
+		}
+		logger.Infof("Closing connection to peer %s which left the network", address)
+		if err := e.conn.Close(); err != nil {
+			logger.Warningf("Failed to close connection to peer %s: %s", address, err)
+		}
+		delete(r.remoteEndorsers, address)
+	}
+}
+
+// evictOrderers records the orderer addresses of the given channel, and closes the
+// connections to the orderers which are not in the configuration of any channel.
+func (r *registry) evictOrderers(channel string, addresses []string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.channelOrderers[channel] = addresses
+	configured := map[string]bool{}
+	for _, addresses := range r.channelOrderers {
+		for _, address := range addresses {
+			configured[address] = true
+		}
+	}
+	for address, o := range r.remoteOrderers {
+		if configured[address] {
+			continue
+		}
+		logger.Infof("Closing connection to orderer %s which left the channel configurations", address)
+		if err := o.conn.Close(); err != nil {
+			logger.Warningf("Failed to close connection to orderer %s: %s", address, err)
+		}
+		delete(r.remoteOrderers, address)
+	}
+}
+
+func (r *registry) isLocal(peer *dp.Peer) bool {
+	return bytes.Equal(peer.Identity, r.localIdentity)
+}
+
+func (r *registry) localFirst(peers []*dp.Peer) []*dp.Peer {
+	ordered := make([]*dp.Peer, 0, len(peers))
+	for _, peer := range peers {
+		if r.isLocal(peer) {
+			ordered = append([]*dp.Peer{peer}, ordered...)
+		} else {
+			ordered = append(ordered, peer)
+		}
+	}
+	return ordered
+}
+
+func allPeers(descriptor *dp.EndorsementDescriptor) []*dp.Peer {
+	seen := map[string]bool{}
+	var peers []*dp.Peer
+	for _, group := range descriptor.EndorsersByGroups {
+		for _, peer := range group.GetPeers() {
+			if seen[string(peer.Identity)] {
+				continue
+			}
+			seen[string(peer.Identity)] = true
+			peers = append(peers, peer)
+		}
+	}
+	return peers
+}
+
+func tlsRootCerts(config *dp.ConfigResult) map[string][][]byte {
+	roots := map[string][][]byte{}
+	for mspid, msp := range config.GetMsps() {
+		roots[mspid] = append(append([][]byte{}, msp.TlsRootCerts...), msp.TlsIntermediateCerts...)
+	}
+	return roots
+}
+
+func mspIDOf(peer *dp.Peer) (string, error) {
+	sID, err := protoutil.UnmarshalSerializedIdentity(peer.Identity)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to unmarshal peer identity")
+	}
+	return sID.Mspid, nil
+}
+
+func endpointOf(peer *dp.Peer) (string, error) {
+	if peer.MembershipInfo == nil {
+		return "", errors.New("peer has no membership information")
+	}
+	msg, err := protoext.EnvelopeToGossipMessage(peer.MembershipInfo)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to unmarshal peer membership information")
+	}
+	endpoint := msg.GetAliveMsg().GetMembership().GetEndpoint()
+	if endpoint == "" {
+		return "", errors.New("peer has no endpoint")
+	}
+	return endpoint, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// localEndorserClient adapts the endorser service of the local peer to an EndorserClient.
+type localEndorserClient struct {
+	server pb.EndorserServer
+}
+
+func (l *localEndorserClient) ProcessProposal(ctx context.Context, signedProposal *pb.SignedProposal, _ ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	return l.server.ProcessProposal(ctx, signedProposal)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"sync"
+	"testing"
+
+	dp "github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	gdiscovery "github.com/osdi23p228/fabric/gossip/discovery"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// membership is a Discovery whose alive members can be changed by a test.
+type membership struct {
+	Discovery
+	members gdiscovery.Members
+}
+
+func (m *membership) Peers() gdiscovery.Members {
+	return m.members
+}
+
+func testRegistry(discovery Discovery) *registry {
+	return &registry{
+		localIdentity: []byte("local"),
+		discovery:     discovery,
+		dial: func(address string, _ [][]byte) (*grpc.ClientConn, error) {
+			return grpc.Dial(address, grpc.WithInsecure())
+		},
+		remoteEndorsers: map[string]*endorser{},
+		remoteOrderers:  map[string]*orderer{},
+		channelOrderers: map[string][]string{},
+	}
+}
+
+func testPeer(endpoint string) *dp.Peer {
+	aliveMsg := &gossip.GossipMessage{
+		Content: &gossip.GossipMessage_AliveMsg{
+			AliveMsg: &gossip.AliveMessage{Membership: &gossip.Member{Endpoint: endpoint}},
+		},
+	}
+	return &dp.Peer{
+		Identity:       protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "Org2MSP", IdBytes: []byte(endpoint)}),
+		MembershipInfo: &gossip.Envelope{Payload: protoutil.MarshalOrPanic(aliveMsg)},
+	}
+}
+
+func TestRegistryConcurrentConnections(t *testing.T) {
+	r := testRegistry(&membership{})
+
+	var wg sync.WaitGroup
+	endorsers := make([]*endorser, 10)
+	for i := range endorsers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			e, err := r.endorser(testPeer("127.0.0.1:1"), nil)
+			require.NoError(t, err)
+			endorsers[i] = e
+		}(i)
+	}
+	wg.Wait()
+
+	require.Len(t, r.remoteEndorsers, 1)
+	for _, e := range endorsers {
+		require.Same(t, r.remoteEndorsers["127.0.0.1:1"], e)
+	}
+}
+
+func TestRegistryEvictsDepartedPeers(t *testing.T) {
+	discovery := &membership{members: gdiscovery.Members{{Endpoint: "127.0.0.1:1"}, {Endpoint: "127.0.0.1:2"}}}
+	r := testRegistry(discovery)
+
+	e1, err := r.endorser(testPeer("127.0.0.1:1"), nil)
+	require.NoError(t, err)
+	e2, err := r.endorser(testPeer("127.0.0.1:2"), nil)
+	require.NoError(t, err)
+
+	r.evictEndorsers()
+	require.Len(t, r.remoteEndorsers, 2)
+
+	discovery.members = gdiscovery.Members{{Endpoint: "127.0.0.1:2"}}
+	r.evictEndorsers()
+	require.Len(t, r.remoteEndorsers, 1)
+	require.Equal(t, connectivity.Shutdown, e1.conn.GetState())
+	require.NotEqual(t, connectivity.Shutdown, e2.conn.GetState())
+}
+
+func TestRegistryEvictsRemovedOrderers(t *testing.T) {
+	r := testRegistry(&membership{})
+
+	r.evictOrderers("channel-1", []string{"127.0.0.1:1"})
+	r.evictOrderers("channel-2", []string{"127.0.0.1:1", "127.0.0.1:2"})
+	o1, err := r.orderer("127.0.0.1:1", "OrdererMSP", nil)
+	require.NoError(t, err)
+	o2, err := r.orderer("127.0.0.1:2", "OrdererMSP", nil)
+	require.NoError(t, err)
+
+	// the orderer is still used by channel-1 when channel-2 drops it
+	r.evictOrderers("channel-2", []string{"127.0.0.1:2"})
+	require.Len(t, r.remoteOrderers, 2)
+
+	r.evictOrderers("channel-1", nil)
+	require.Len(t, r.remoteOrderers, 1)
+	require.Equal(t, connectivity.Shutdown, o1.conn.GetState())
+	require.NotEqual(t, connectivity.Shutdown, o2.conn.GetState())
+}
@@ -107,8 +107,9 @@ type SASLPlain struct {
 // Authentication contains configuration parameters related to authenticating
 // client messages.
 type Authentication struct {
-	TimeWindow         time.Duration
-	NoExpirationChecks bool
+	TimeWindow              time.Duration
+	NoExpirationChecks      bool
+	PrevalidateEndorsements bool
 }
 
 // Profile contains configuration for Go pprof profiling.
@@ -0,0 +1,192 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/osdi23p228/fabric/bccsp"
+	"github.com/osdi23p228/fabric/common/channelconfig"
+	"github.com/osdi23p228/fabric/common/ledger/blockledger"
+	"github.com/osdi23p228/fabric/orderer/consensus/etcdraft"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// LedgerStatus describes the ledger of a single orderer.
+type LedgerStatus struct {
+	Height         uint64
+	ConsensusType  string
+	ConsensusState orderer.ConsensusType_State
+}
+
+// ConsistencyReport is the outcome of a consistency check across the ledgers of several orderers.
+type ConsistencyReport struct {
+	// Ledgers maps the name of each orderer to the status of its ledger.
+	Ledgers map[string]*LedgerStatus
+	// CommonHeight is the height up to which all ledgers have been compared.
+	CommonHeight uint64
+	// Divergence describes the first block on which the ledgers disagree, if any.
+	Divergence *Divergence
+}
+
+// Divergence describes a block which is not identical across ledgers.
+type Divergence struct {
+	BlockNumber uint64
+	// Hashes maps the name of each orderer to the header hash of its block.
+	Hashes map[string][]byte
+}
+
+// Consistent returns whether the ledgers agree on all blocks up to the common height,
+// and on the consensus type and state of the last config.
+func (cr *ConsistencyReport) Consistent() bool {
+	if cr.Divergence != nil {
+		return false
+	}
+	var reference *LedgerStatus
+	for _, status := range cr.Ledgers {
+		if reference == nil {
+			reference = status
+			continue
+		}
+		if status.ConsensusType != reference.ConsensusType || status.ConsensusState != reference.ConsensusState {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns a human readable summary of the report.
+func (cr *ConsistencyReport) String() string {
+	buff := &bytes.Buffer{}
+	for _, name := range sortedNames(cr.Ledgers) {
+		status := cr.Ledgers[name]
+		fmt.Fprintf(buff, "%s: height %d, consensus %s (%s)\n", name, status.Height, status.ConsensusType, status.ConsensusState)
+	}
+	fmt.Fprintf(buff, "Compared blocks: [0, %d)\n", cr.CommonHeight)
+	if cr.Divergence != nil {
+		fmt.Fprintf(buff, "Ledgers diverge at block %d:\n", cr.Divergence.BlockNumber)
+		for _, name := range sortedNames(cr.Ledgers) {
+			fmt.Fprintf(buff, "  %s: %x\n", name, cr.Divergence.Hashes[name])
+		}
+	}
+	if cr.Consistent() {
+		fmt.Fprintf(buff, "Result: ledgers are consistent\n")
+	} else {
+		fmt.Fprintf(buff, "Result: ledgers are NOT consistent\n")
+	}
+	return buff.String()
+}
+
+func sortedNames(ledgers map[string]*LedgerStatus) []string {
+	names := make([]string, 0, len(ledgers))
+	for name := range ledgers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckLedgerConsistency compares the ledgers of a channel, as read from several orderers, block by
+// block up to the lowest height among them, and reports the first block which differs. It additionally
+// reports the consensus type and state in effect according to the last config block of each ledger, which
+// after a completed migration must be identical across all orderers.
+func CheckLedgerConsistency(ledgers map[string]blockledger.Reader, bccsp bccsp.BCCSP) (*ConsistencyReport, error) {
+	if len(ledgers) == 0 {
+		return nil, errors.New("no ledgers to compare")
+	}
+
+	report := &ConsistencyReport{
+		Ledgers: make(map[string]*LedgerStatus, len(ledgers)),
+	}
+
+	first := true
+	for name, reader := range ledgers {
+		height := reader.Height()
+		if height == 0 {
+			return nil, errors.Errorf("ledger of %s is empty", name)
+		}
+		if first || height < report.CommonHeight {
+			report.CommonHeight = height
+			first = false
+		}
+
+		ordererConfig, err := lastOrdererConfig(reader, bccsp)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to read last config of %s", name)
+		}
+		report.Ledgers[name] = &LedgerStatus{
+			Height:         height,
+			ConsensusType:  ordererConfig.ConsensusType(),
+			ConsensusState: ordererConfig.ConsensusState(),
+		}
+	}
+
+	names := sortedNames(report.Ledgers)
+	for seq := uint64(0); seq < report.CommonHeight; seq++ {
+		hashes := make(map[string][]byte, len(names))
+		var reference []byte
+		diverged := false
+		for i, name := range names {
+			block := blockledger.GetBlock(ledgers[name], seq)
+			if block == nil || block.Header == nil {
+				return nil, errors.Errorf("failed to read block %d of %s", seq, name)
+			}
+			hashes[name] = protoutil.BlockHeaderHash(block.Header)
+			if i == 0 {
+				reference = hashes[name]
+				continue
+			}
+			if !bytes.Equal(reference, hashes[name]) {
+				diverged = true
+			}
+		}
+		if diverged {
+			report.Divergence = &Divergence{BlockNumber: seq, Hashes: hashes}
+			break
+		}
+	}
+
+	return report, nil
+}
+
+func lastOrdererConfig(reader blockledger.Reader, bccsp bccsp.BCCSP) (channelconfig.Orderer, error) {
+	lastBlock := blockledger.GetBlock(reader, reader.Height()-1)
+	if lastBlock == nil {
+		return nil, errors.New("failed to read last block")
+	}
+
+	index, err := protoutil.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	configBlock := blockledger.GetBlock(reader, index)
+	if configBlock == nil {
+		return nil, errors.Errorf("failed to read config block %d", index)
+	}
+
+	env, err := etcdraft.ConfigEnvelopeFromBlock(configBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := channelconfig.NewBundleFromEnvelope(env, bccsp)
+	if err != nil {
+		return nil, err
+	}
+
+	ordererConfig, ok := bundle.OrdererConfig()
+	if !ok {
+		return nil, errors.New("config is missing orderer group")
+	}
+
+	return ordererConfig, nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package migration provides offline tooling in support of consensus-type migration,
+// i.e. previewing whether a migration config update is going to be accepted by the
+// ordering service, and verifying the consistency of the ledgers of the orderers once
+// a migration has completed.
+package migration
+
+import (
+	"bytes"
+	"fmt"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/osdi23p228/fabric/bccsp"
+	"github.com/osdi23p228/fabric/common/channelconfig"
+	"github.com/osdi23p228/fabric/common/flogging"
+	"github.com/osdi23p228/fabric/orderer/common/msgprocessor"
+	"github.com/osdi23p228/fabric/orderer/consensus/etcdraft"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("orderer.common.migration")
+
+// Report is the outcome of a dry run of a config update.
+type Report struct {
+	ChannelID    string
+	CurrentType  string
+	CurrentState orderer.ConsensusType_State
+	NextType     string
+	NextState    orderer.ConsensusType_State
+	// Violations lists every rule the config update violates, in the order in which they were checked.
+	Violations []error
+}
+
+// Passed returns whether the config update satisfies all the checked rules.
+func (r *Report) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// String returns a human readable summary of the report.
+func (r *Report) String() string {
+	buff := &bytes.Buffer{}
+	fmt.Fprintf(buff, "Channel: %s\n", r.ChannelID)
+	fmt.Fprintf(buff, "Current consensus: %s (%s)\n", r.CurrentType, r.CurrentState)
+	if r.NextType != "" {
+		fmt.Fprintf(buff, "Next consensus: %s (%s)\n", r.NextType, r.NextState)
+	}
+	if r.Passed() {
+		fmt.Fprintf(buff, "Result: config update would be accepted\n")
+		return buff.String()
+	}
+	fmt.Fprintf(buff, "Result: config update would be rejected, %d failing rule(s):\n", len(r.Violations))
+	for i, v := range r.Violations {
+		fmt.Fprintf(buff, "  %d. %s\n", i+1, v)
+	}
+	return buff.String()
+}
+
+// maintenanceFilterSupport adapts a channel config bundle to msgprocessor.MaintenanceFilterSupport.
+type maintenanceFilterSupport struct {
+	*channelconfig.Bundle
+}
+
+func (mfs *maintenanceFilterSupport) ChannelID() string {
+	return mfs.ConfigtxValidator().ChannelID()
+}
+
+// DryRun evaluates the given CONFIG_UPDATE envelope against the channel config contained in the
+// given config block, the same way the ordering service would, without a running orderer.
+// The config update is authorized against the current channel config, then the resulting config is
+// inspected by the maintenance filter, and, if the next consensus type is etcdraft, the etcdraft
+// consensus metadata is verified. Every failing rule is reported.
+//
+// An error is returned only if the inputs cannot be parsed.
+func DryRun(configBlock *cb.Block, configUpdate *cb.Envelope, bccsp bccsp.BCCSP) (*Report, error) {
+	configEnv, err := etcdraft.ConfigEnvelopeFromBlock(configBlock)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to extract config envelope from config block")
+	}
+
+	bundle, err := channelconfig.NewBundleFromEnvelope(configEnv, bccsp)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to parse current channel config")
+	}
+
+	ordererConfig, ok := bundle.OrdererConfig()
+	if !ok {
+		return nil, errors.New("current channel config is missing orderer group")
+	}
+
+	report := &Report{
+		ChannelID:    bundle.ConfigtxValidator().ChannelID(),
+		CurrentType:  ordererConfig.ConsensusType(),
+		CurrentState: ordererConfig.ConsensusState(),
+	}
+
+	nextConfigEnv, err := bundle.ConfigtxValidator().ProposeConfigUpdate(configUpdate)
+	if err != nil {
+		report.Violations = append(report.Violations, errors.WithMessage(err, "config update could not be applied to the current config"))
+		return report, nil
+	}
+
+	nextBundle, err := channelconfig.NewBundle(report.ChannelID, nextConfigEnv.Config, bccsp)
+	if err != nil {
+		report.Violations = append(report.Violations, errors.WithMessage(err, "failed to parse next channel config"))
+		return report, nil
+	}
+
+	nextOrdererConfig, ok := nextBundle.OrdererConfig()
+	if !ok {
+		report.Violations = append(report.Violations, errors.New("next channel config is missing orderer group"))
+		return report, nil
+	}
+	report.NextType = nextOrdererConfig.ConsensusType()
+	report.NextState = nextOrdererConfig.ConsensusState()
+
+	if err := bundle.ValidateNew(nextBundle); err != nil {
+		report.Violations = append(report.Violations, err)
+	}
+
+	config, err := protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG, report.ChannelID, nil, nextConfigEnv, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	mf := msgprocessor.NewMaintenanceFilter(&maintenanceFilterSupport{Bundle: bundle}, bccsp)
+	report.Violations = append(report.Violations, mf.Violations(config)...)
+
+	metadataChanged := ordererConfig.ConsensusType() != nextOrdererConfig.ConsensusType() ||
+		!bytes.Equal(ordererConfig.ConsensusMetadata(), nextOrdererConfig.ConsensusMetadata())
+	if nextOrdererConfig.ConsensusType() == "etcdraft" && metadataChanged {
+		if err := etcdraft.VerifyOrdererConfigMetadata(nextOrdererConfig); err != nil {
+			report.Violations = append(report.Violations, errors.WithMessage(err, "etcdraft consensus metadata is invalid"))
+		}
+	}
+
+	logger.Debugf("[channel: %s] Dry run completed with %d violation(s)", report.ChannelID, len(report.Violations))
+
+	return report, nil
+}
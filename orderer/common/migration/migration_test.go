@@ -0,0 +1,203 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/osdi23p228/fabric/bccsp"
+	"github.com/osdi23p228/fabric/bccsp/sw"
+	"github.com/osdi23p228/fabric/common/capabilities"
+	"github.com/osdi23p228/fabric/common/channelconfig"
+	"github.com/osdi23p228/fabric/common/genesis"
+	"github.com/osdi23p228/fabric/common/ledger/blockledger"
+	"github.com/osdi23p228/fabric/common/ledger/blockledger/fileledger"
+	"github.com/osdi23p228/fabric/common/metrics/disabled"
+	"github.com/osdi23p228/fabric/core/config/configtest"
+	"github.com/osdi23p228/fabric/internal/configtxgen/encoder"
+	"github.com/osdi23p228/fabric/internal/configtxgen/genesisconfig"
+	"github.com/osdi23p228/fabric/internal/configtxlator/update"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChannelID = "test-channel"
+
+type consensusTypeInfo struct {
+	ordererType string
+	metadata    []byte
+	state       orderer.ConsensusType_State
+}
+
+func newCryptoProvider(t *testing.T) bccsp.BCCSP {
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	return cryptoProvider
+}
+
+func makeConfig(t *testing.T, info consensusTypeInfo) *cb.Config {
+	gConf := genesisconfig.Load(genesisconfig.SampleInsecureSoloProfile, configtest.GetDevConfigDir())
+	gConf.Orderer.Capabilities = map[string]bool{
+		capabilities.OrdererV1_4_2: true,
+	}
+	channelGroup, err := encoder.NewChannelGroup(gConf)
+	require.NoError(t, err)
+
+	channelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey] = &cb.ConfigValue{
+		Value: protoutil.MarshalOrPanic(&orderer.ConsensusType{
+			Type:     info.ordererType,
+			Metadata: info.metadata,
+			State:    info.state,
+		}),
+		ModPolicy: channelconfig.AdminsPolicyKey,
+	}
+	return &cb.Config{ChannelGroup: channelGroup}
+}
+
+func makeConfigBlock(t *testing.T, info consensusTypeInfo) *cb.Block {
+	return genesis.NewFactoryImpl(makeConfig(t, info).ChannelGroup).Block(testChannelID)
+}
+
+func makeConfigUpdate(t *testing.T, current, next consensusTypeInfo) *cb.Envelope {
+	configUpdate, err := update.Compute(makeConfig(t, current), makeConfig(t, next))
+	require.NoError(t, err)
+	configUpdate.ChannelId = testChannelID
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{
+		ConfigUpdate: protoutil.MarshalOrPanic(configUpdate),
+	}
+	env, err := protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, testChannelID, nil, configUpdateEnv, 0, 0)
+	require.NoError(t, err)
+	return env
+}
+
+func TestDryRun(t *testing.T) {
+	cryptoProvider := newCryptoProvider(t)
+	current := consensusTypeInfo{ordererType: "kafka", state: orderer.ConsensusType_STATE_NORMAL}
+	configBlock := makeConfigBlock(t, current)
+
+	t.Run("Good entry to maintenance", func(t *testing.T) {
+		next := consensusTypeInfo{ordererType: "kafka", state: orderer.ConsensusType_STATE_MAINTENANCE}
+		report, err := DryRun(configBlock, makeConfigUpdate(t, current, next), cryptoProvider)
+		require.NoError(t, err)
+		assert.True(t, report.Passed(), report.String())
+		assert.Equal(t, testChannelID, report.ChannelID)
+		assert.Equal(t, "kafka", report.CurrentType)
+		assert.Equal(t, orderer.ConsensusType_STATE_NORMAL, report.CurrentState)
+		assert.Equal(t, orderer.ConsensusType_STATE_MAINTENANCE, report.NextState)
+		assert.Contains(t, report.String(), "Result: config update would be accepted")
+	})
+
+	t.Run("All failing rules reported", func(t *testing.T) {
+		next := consensusTypeInfo{ordererType: "etcdraft", metadata: []byte{1, 2, 3, 4}, state: orderer.ConsensusType_STATE_MAINTENANCE}
+		report, err := DryRun(configBlock, makeConfigUpdate(t, current, next), cryptoProvider)
+		require.NoError(t, err)
+		assert.False(t, report.Passed())
+		require.Len(t, report.Violations, 5)
+		assert.EqualError(t, report.Violations[0], "attempted to change ConsensusType.Type from kafka to etcdraft, but ConsensusType.State is changing from STATE_NORMAL to STATE_MAINTENANCE")
+		assert.Contains(t, report.Violations[4].Error(), "etcdraft consensus metadata is invalid")
+		assert.Contains(t, report.String(), "Result: config update would be rejected, 5 failing rule(s)")
+	})
+
+	t.Run("Config update not applicable", func(t *testing.T) {
+		next := consensusTypeInfo{ordererType: "kafka", state: orderer.ConsensusType_STATE_MAINTENANCE}
+		configUpdate, err := update.Compute(makeConfig(t, current), makeConfig(t, next))
+		require.NoError(t, err)
+		configUpdate.ChannelId = "other-channel"
+		env, err := protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, testChannelID, nil, &cb.ConfigUpdateEnvelope{
+			ConfigUpdate: protoutil.MarshalOrPanic(configUpdate),
+		}, 0, 0)
+		require.NoError(t, err)
+		report, err := DryRun(configBlock, env, cryptoProvider)
+		require.NoError(t, err)
+		require.Len(t, report.Violations, 1)
+		assert.Contains(t, report.Violations[0].Error(), "config update could not be applied to the current config")
+	})
+
+	t.Run("Bad config block", func(t *testing.T) {
+		_, err := DryRun(&cb.Block{}, &cb.Envelope{}, cryptoProvider)
+		assert.Error(t, err)
+	})
+}
+
+func appendBlock(t *testing.T, rw blockledger.ReadWriter, data ...[]byte) {
+	var envs []*cb.Envelope
+	for _, d := range data {
+		envs = append(envs, &cb.Envelope{Payload: d})
+	}
+	block := blockledger.CreateNextBlock(rw, envs)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&cb.Metadata{
+		Value: protoutil.MarshalOrPanic(&cb.OrdererBlockMetadata{
+			LastConfig: &cb.LastConfig{Index: 0},
+		}),
+	})
+	require.NoError(t, rw.Append(block))
+}
+
+func newLedger(t *testing.T, configBlock *cb.Block) (blockledger.ReadWriter, func()) {
+	dir, err := ioutil.TempDir("", "migration-")
+	require.NoError(t, err)
+	lf, err := fileledger.New(dir, &disabled.Provider{})
+	require.NoError(t, err)
+	rw, err := lf.GetOrCreate(testChannelID)
+	require.NoError(t, err)
+	require.NoError(t, rw.Append(configBlock))
+	return rw, func() {
+		lf.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCheckLedgerConsistency(t *testing.T) {
+	cryptoProvider := newCryptoProvider(t)
+	configBlock := makeConfigBlock(t, consensusTypeInfo{ordererType: "etcdraft", state: orderer.ConsensusType_STATE_NORMAL})
+
+	o1, cleanup1 := newLedger(t, configBlock)
+	defer cleanup1()
+	o2, cleanup2 := newLedger(t, configBlock)
+	defer cleanup2()
+	o3, cleanup3 := newLedger(t, configBlock)
+	defer cleanup3()
+
+	appendBlock(t, o1, []byte("tx1"))
+	appendBlock(t, o1, []byte("tx2"))
+	appendBlock(t, o2, []byte("tx1"))
+	appendBlock(t, o3, []byte("tx1"))
+
+	t.Run("Consistent", func(t *testing.T) {
+		report, err := CheckLedgerConsistency(map[string]blockledger.Reader{"o1": o1, "o2": o2}, cryptoProvider)
+		require.NoError(t, err)
+		assert.True(t, report.Consistent(), report.String())
+		assert.Equal(t, uint64(2), report.CommonHeight)
+		assert.Equal(t, uint64(3), report.Ledgers["o1"].Height)
+		assert.Equal(t, "etcdraft", report.Ledgers["o2"].ConsensusType)
+		assert.Nil(t, report.Divergence)
+	})
+
+	t.Run("Diverging", func(t *testing.T) {
+		appendBlock(t, o3, []byte("tx3"))
+		report, err := CheckLedgerConsistency(map[string]blockledger.Reader{"o1": o1, "o2": o2, "o3": o3}, cryptoProvider)
+		require.NoError(t, err)
+		assert.True(t, report.Consistent(), "blocks beyond the common height are not compared")
+		assert.Equal(t, uint64(2), report.CommonHeight)
+
+		report, err = CheckLedgerConsistency(map[string]blockledger.Reader{"o1": o1, "o3": o3}, cryptoProvider)
+		require.NoError(t, err)
+		require.NotNil(t, report.Divergence)
+		assert.Equal(t, uint64(2), report.Divergence.BlockNumber)
+		assert.Contains(t, report.String(), "Ledgers diverge at block 2")
+	})
+
+	t.Run("No ledgers", func(t *testing.T) {
+		_, err := CheckLedgerConsistency(nil, cryptoProvider)
+		assert.EqualError(t, err, "no ledgers to compare")
+	})
+}
@@ -0,0 +1,266 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/osdi23p228/fabric/common/policies"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	// lifecycleNamespace is the namespace of the _lifecycle system chaincode
+	lifecycleNamespace = "_lifecycle"
+	// lifecycleEndorsementPolicy is the policy endorsing the transactions of _lifecycle
+	lifecycleEndorsementPolicy = "/Channel/Application/LifecycleEndorsement"
+	// definitionFieldKeyPrefix prefixes the public keys holding the fields of
+	// the committed chaincode definitions, as 'namespaces/fields/<name>/<field>'
+	definitionFieldKeyPrefix = "namespaces/fields/"
+	// builtinValidationPlugin is the validation plugin whose validation parameter
+	// is the endorsement policy of the chaincode
+	builtinValidationPlugin = "vscc"
+)
+
+// chaincodeDefinition is the part of a committed chaincode definition
+// relevant to the endorsement filter.
+type chaincodeDefinition struct {
+	sequence int64
+	// validationInfoWritten is whether the definition rewrote the validation info,
+	// as the fields which did not change are not written
+	validationInfoWritten bool
+	// policy is nil when the chaincode is not validated by the builtin
+	// validation plugin, as its endorsement policy is then unknown
+	policy *pb.ApplicationPolicy
+}
+
+// ChaincodeDefinitions is an EndorsementPolicySource which holds the endorsement
+// policies of the chaincode definitions committed through _lifecycle, as recorded
+// from the blocks written by the orderer.
+//
+// The orderer does not know which transactions the peers invalidate, so the commit
+// of a definition is recorded when its endorsements satisfy the LifecycleEndorsement
+// policy, and the definitions which might still be the committed one are all kept:
+// as the endorsers of a definition with sequence N have committed the one with
+// sequence N-1, only the definitions older than that are dropped.
+type ChaincodeDefinitions struct {
+	mutex       sync.RWMutex
+	definitions map[string]map[string][]*chaincodeDefinition
+}
+
+// NewChaincodeDefinitions creates an empty set of chaincode definitions.
+func NewChaincodeDefinitions() *ChaincodeDefinitions {
+	return &ChaincodeDefinitions{
+		definitions: map[string]map[string][]*chaincodeDefinition{},
+	}
+}
+
+// EndorsementPolicies returns the endorsement policies of the definitions of the given
+// chaincode which might be the committed one, and whether they are all known.
+func (cd *ChaincodeDefinitions) EndorsementPolicies(channelID, chaincodeName string) ([]*pb.ApplicationPolicy, bool) {
+	cd.mutex.RLock()
+	defer cd.mutex.RUnlock()
+
+	definitions := cd.definitions[channelID][chaincodeName]
+	if len(definitions) == 0 {
+		return nil, false
+	}
+	policies := make([]*pb.ApplicationPolicy, 0, len(definitions))
+	for _, definition := range definitions {
+		if definition.policy == nil {
+			return nil, false
+		}
+		policies = append(policies, definition.policy)
+	}
+	return policies, true
+}
+
+// Reset forgets the definitions of the chaincodes of the given channel, before
+// the blocks of the channel are processed again.
+func (cd *ChaincodeDefinitions) Reset(channelID string) {
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+
+	delete(cd.definitions, channelID)
+}
+
+// ProcessBlock records the chaincode definitions committed by the transactions of the
+// block. The policy manager is the one of the channel configuration the block was
+// created with. Blocks must be processed in order.
+func (cd *ChaincodeDefinitions) ProcessBlock(channelID string, block *cb.Block, policyManager policies.Manager) {
+	for i, envBytes := range block.GetData().GetData() {
+		env, err := protoutil.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			logger.Debugf("[channel: %s] Skipping transaction %d of block [%d]: %s", channelID, i, block.Header.Number, err)
+			continue
+		}
+		definitions, err := committedDefinitions(env, policyManager)
+		if err != nil {
+			logger.Debugf("[channel: %s] Not recording the chaincode definitions of transaction %d of block [%d]: %s", channelID, i, block.Header.Number, err)
+			continue
+		}
+		for name, definition := range definitions {
+			logger.Debugf("[channel: %s] Recording definition of chaincode %s with sequence %d from block [%d]", channelID, name, definition.sequence, block.Header.Number)
+			cd.add(channelID, name, definition)
+		}
+	}
+}
+
+// add records a committed definition. When the definition did not rewrite the validation
+// info, it inherits the policies of the definitions it might have been committed on top of.
+func (cd *ChaincodeDefinitions) add(channelID, chaincodeName string, definition *chaincodeDefinition) {
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+
+	if _, ok := cd.definitions[channelID]; !ok {
+		cd.definitions[channelID] = map[string][]*chaincodeDefinition{}
+	}
+	existing := cd.definitions[channelID][chaincodeName]
+
+	var added []*chaincodeDefinition
+	if definition.validationInfoWritten || definition.sequence == 1 {
+		added = append(added, definition)
+	} else {
+		for _, previous := range existing {
+			if previous.sequence == definition.sequence-1 {
+				added = append(added, &chaincodeDefinition{sequence: definition.sequence, policy: previous.policy})
+			}
+		}
+		if len(added) == 0 {
+			// the definition the chaincode was committed on top of is unknown
+			added = append(added, definition)
+		}
+	}
+
+	var kept []*chaincodeDefinition
+	for _, previous := range existing {
+		if previous.sequence >= definition.sequence-1 {
+			kept = append(kept, previous)
+		}
+	}
+	cd.definitions[channelID][chaincodeName] = append(kept, added...)
+}
+
+// committedDefinitions returns the chaincode definitions committed by the transaction,
+// along with whether their validation info was written
+func committedDefinitions(env *cb.Envelope, policyManager policies.Manager) (map[string]*chaincodeDefinition, error) {
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("missing header in payload")
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if chdr.Type != int32(cb.HeaderType_ENDORSER_TRANSACTION) {
+		return nil, nil
+	}
+	tx, err := protoutil.UnmarshalTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := map[string]*chaincodeDefinition{}
+	for _, action := range tx.Actions {
+		ccActionPayload, ccAction, err := protoutil.GetPayloads(action)
+		if err != nil {
+			return nil, err
+		}
+		if ccAction.GetChaincodeId().GetName() != lifecycleNamespace {
+			continue
+		}
+
+		policy, ok := policyManager.GetPolicy(lifecycleEndorsementPolicy)
+		if !ok {
+			return nil, errors.Errorf("could not find policy %s", lifecycleEndorsementPolicy)
+		}
+		if err := policy.EvaluateSignedData(endorsementSignatureSet(ccActionPayload.Action)); err != nil {
+			return nil, errors.WithMessage(err, "lifecycle endorsement policy not satisfied")
+		}
+
+		txRWSet := &rwset.TxReadWriteSet{}
+		if err := proto.Unmarshal(ccAction.Results, txRWSet); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal read-write set")
+		}
+		for _, nsRWSet := range txRWSet.NsRwset {
+			if nsRWSet.Namespace != lifecycleNamespace {
+				continue
+			}
+			kvRWSet := &kvrwset.KVRWSet{}
+			if err := proto.Unmarshal(nsRWSet.Rwset, kvRWSet); err != nil {
+				return nil, errors.Wrap(err, "could not unmarshal lifecycle read-write set")
+			}
+			if err := addDefinitionFields(definitions, kvRWSet.Writes); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for name, definition := range definitions {
+		// the sequence is always written when a definition is committed
+		if definition.sequence == 0 {
+			delete(definitions, name)
+		}
+	}
+	return definitions, nil
+}
+
+func addDefinitionFields(definitions map[string]*chaincodeDefinition, writes []*kvrwset.KVWrite) error {
+	for _, write := range writes {
+		if write.IsDelete || !strings.HasPrefix(write.Key, definitionFieldKeyPrefix) {
+			continue
+		}
+		nameAndField := strings.Split(strings.TrimPrefix(write.Key, definitionFieldKeyPrefix), "/")
+		if len(nameAndField) != 2 {
+			continue
+		}
+		name, field := nameAndField[0], nameAndField[1]
+		if field != "Sequence" && field != "ValidationInfo" {
+			continue
+		}
+
+		stateData := &lb.StateData{}
+		if err := proto.Unmarshal(write.Value, stateData); err != nil {
+			return errors.Wrapf(err, "could not unmarshal field %s of chaincode %s", field, name)
+		}
+		definition, ok := definitions[name]
+		if !ok {
+			definition = &chaincodeDefinition{}
+			definitions[name] = definition
+		}
+
+		if field == "Sequence" {
+			definition.sequence = stateData.GetInt64()
+			continue
+		}
+		definition.validationInfoWritten = true
+		validationInfo := &lb.ChaincodeValidationInfo{}
+		if err := proto.Unmarshal(stateData.GetBytes(), validationInfo); err != nil {
+			return errors.Wrapf(err, "could not unmarshal validation info of chaincode %s", name)
+		}
+		if validationInfo.ValidationPlugin != builtinValidationPlugin {
+			continue
+		}
+		policy := &pb.ApplicationPolicy{}
+		if err := proto.Unmarshal(validationInfo.ValidationParameter, policy); err != nil {
+			return errors.Wrapf(err, "could not unmarshal endorsement policy of chaincode %s", name)
+		}
+		definition.policy = policy
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/osdi23p228/fabric/orderer/common/msgprocessor/mocks"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// makeLifecycleTx creates a _lifecycle transaction committing the definition of the
+// chaincode with the given sequence, along with its validation info when the policy is set
+func makeLifecycleTx(ccName string, sequence int64, validationPlugin string, policy *pb.ApplicationPolicy) *cb.Envelope {
+	writes := []*kvrwset.KVWrite{{
+		Key:   fmt.Sprintf("namespaces/fields/%s/Sequence", ccName),
+		Value: protoutil.MarshalOrPanic(&lb.StateData{Type: &lb.StateData_Int64{Int64: sequence}}),
+	}}
+	if policy != nil {
+		validationInfo := protoutil.MarshalOrPanic(&lb.ChaincodeValidationInfo{
+			ValidationPlugin:    validationPlugin,
+			ValidationParameter: protoutil.MarshalOrPanic(policy),
+		})
+		writes = append(writes, &kvrwset.KVWrite{
+			Key:   fmt.Sprintf("namespaces/fields/%s/ValidationInfo", ccName),
+			Value: protoutil.MarshalOrPanic(&lb.StateData{Type: &lb.StateData_Bytes{Bytes: validationInfo}}),
+		})
+	}
+
+	prp := protoutil.MarshalOrPanic(&pb.ProposalResponsePayload{
+		Extension: protoutil.MarshalOrPanic(&pb.ChaincodeAction{
+			ChaincodeId: &pb.ChaincodeID{Name: "_lifecycle"},
+			Results: protoutil.MarshalOrPanic(&rwset.TxReadWriteSet{
+				NsRwset: []*rwset.NsReadWriteSet{{
+					Namespace: "_lifecycle",
+					Rwset:     protoutil.MarshalOrPanic(&kvrwset.KVRWSet{Writes: writes}),
+				}},
+			}),
+		}),
+	})
+
+	return &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+					ChannelId: "mychannel",
+				}),
+			},
+			Data: protoutil.MarshalOrPanic(&pb.Transaction{
+				Actions: []*pb.TransactionAction{{
+					Payload: protoutil.MarshalOrPanic(&pb.ChaincodeActionPayload{
+						Action: &pb.ChaincodeEndorsedAction{
+							ProposalResponsePayload: prp,
+							Endorsements:            []*pb.Endorsement{{Endorser: []byte("org1"), Signature: []byte("sig")}},
+						},
+					}),
+				}},
+			}),
+		}),
+	}
+}
+
+func makeBlock(envs ...*cb.Envelope) *cb.Block {
+	block := protoutil.NewBlock(1, nil)
+	for _, env := range envs {
+		block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(env))
+	}
+	return block
+}
+
+// commitDefinition records the commit of a chaincode definition endorsed as per the
+// LifecycleEndorsement policy
+func commitDefinition(definitions *ChaincodeDefinitions, ccName string, sequence int64, policy *pb.ApplicationPolicy) {
+	policyManager := &mocks.PolicyManager{}
+	policyManager.GetPolicyReturns(&mocks.Policy{}, true)
+	definitions.ProcessBlock("mychannel", makeBlock(makeLifecycleTx(ccName, sequence, "vscc", policy)), policyManager)
+}
+
+func assertPolicies(t *testing.T, actual []*pb.ApplicationPolicy, expected ...*pb.ApplicationPolicy) {
+	assert.Len(t, actual, len(expected))
+	for i := range expected {
+		assert.True(t, proto.Equal(expected[i], actual[i]), "expected policy %v, got %v", expected[i], actual[i])
+	}
+}
+
+func TestChaincodeDefinitionsProcessBlock(t *testing.T) {
+	policyA := referencePolicy("/Channel/Application/A")
+	policyB := referencePolicy("/Channel/Application/B")
+	policyC := referencePolicy("/Channel/Application/C")
+
+	definitions := NewChaincodeDefinitions()
+	_, ok := definitions.EndorsementPolicies("mychannel", "mycc")
+	assert.False(t, ok)
+
+	commitDefinition(definitions, "mycc", 1, policyA)
+	policies, ok := definitions.EndorsementPolicies("mychannel", "mycc")
+	assert.True(t, ok)
+	assertPolicies(t, policies, policyA)
+
+	// the validation info is not written when it did not change
+	commitDefinition(definitions, "mycc", 2, nil)
+	policies, ok = definitions.EndorsementPolicies("mychannel", "mycc")
+	assert.True(t, ok)
+	assertPolicies(t, policies, policyA, policyA)
+
+	// only one of the definitions with the same sequence is valid on the peers
+	commitDefinition(definitions, "mycc", 2, policyB)
+	policies, ok = definitions.EndorsementPolicies("mychannel", "mycc")
+	assert.True(t, ok)
+	assertPolicies(t, policies, policyA, policyA, policyB)
+
+	// the definition with sequence 1 is dropped once one with sequence 3 is committed
+	commitDefinition(definitions, "mycc", 3, policyC)
+	policies, ok = definitions.EndorsementPolicies("mychannel", "mycc")
+	assert.True(t, ok)
+	assertPolicies(t, policies, policyA, policyB, policyC)
+
+	_, ok = definitions.EndorsementPolicies("mychannel", "othercc")
+	assert.False(t, ok)
+	_, ok = definitions.EndorsementPolicies("otherchannel", "mycc")
+	assert.False(t, ok)
+
+	definitions.Reset("mychannel")
+	_, ok = definitions.EndorsementPolicies("mychannel", "mycc")
+	assert.False(t, ok)
+}
+
+func TestChaincodeDefinitionsNotEndorsed(t *testing.T) {
+	definitions := NewChaincodeDefinitions()
+
+	policy := &mocks.Policy{}
+	policy.EvaluateSignedDataReturns(fmt.Errorf("not enough signatures"))
+	policyManager := &mocks.PolicyManager{}
+	policyManager.GetPolicyReturns(policy, true)
+	definitions.ProcessBlock("mychannel", makeBlock(makeLifecycleTx("mycc", 1, "vscc", referencePolicy("/Channel/Application/A"))), policyManager)
+
+	assert.Equal(t, "/Channel/Application/LifecycleEndorsement", policyManager.GetPolicyArgsForCall(0))
+	signatureSet := policy.EvaluateSignedDataArgsForCall(0)
+	assert.Len(t, signatureSet, 1)
+	assert.Equal(t, []byte("org1"), signatureSet[0].Identity)
+	_, ok := definitions.EndorsementPolicies("mychannel", "mycc")
+	assert.False(t, ok)
+
+	policyManager = &mocks.PolicyManager{}
+	policyManager.GetPolicyReturns(nil, false)
+	definitions.ProcessBlock("mychannel", makeBlock(makeLifecycleTx("mycc", 1, "vscc", referencePolicy("/Channel/Application/A"))), policyManager)
+	_, ok = definitions.EndorsementPolicies("mychannel", "mycc")
+	assert.False(t, ok)
+}
+
+func TestChaincodeDefinitionsUnknownPolicy(t *testing.T) {
+	definitions := NewChaincodeDefinitions()
+	policyManager := &mocks.PolicyManager{}
+	policyManager.GetPolicyReturns(&mocks.Policy{}, true)
+
+	// the policy of a custom validation plugin is unknown to the orderer
+	definitions.ProcessBlock("mychannel", makeBlock(makeLifecycleTx("mycc", 1, "custom", referencePolicy("/Channel/Application/A"))), policyManager)
+	_, ok := definitions.EndorsementPolicies("mychannel", "mycc")
+	assert.False(t, ok)
+
+	// as is the definition a chaincode is committed on top of, when its commit was not recorded
+	definitions.ProcessBlock("mychannel", makeBlock(makeLifecycleTx("othercc", 2, "vscc", nil)), policyManager)
+	_, ok = definitions.EndorsementPolicies("mychannel", "othercc")
+	assert.False(t, ok)
+
+	definitions.ProcessBlock("mychannel", makeBlock(
+		makeEndorserTx("mycc", "org1"),
+		&cb.Envelope{Payload: []byte("garbage")},
+		makeLifecycleTx("mycc", 2, "vscc", referencePolicy("/Channel/Application/B")),
+	), policyManager)
+	policies, ok := definitions.EndorsementPolicies("mychannel", "mycc")
+	assert.False(t, ok, "the definition with sequence 1 might still be the committed one")
+	assert.Nil(t, policies)
+}
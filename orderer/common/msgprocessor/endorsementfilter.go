@@ -0,0 +1,153 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/common/cauthdsl"
+	"github.com/osdi23p228/fabric/common/policies"
+	"github.com/osdi23p228/fabric/msp"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// EndorsementPolicySource provides the endorsement policies of the chaincodes
+// committed on a channel through _lifecycle.
+type EndorsementPolicySource interface {
+	// EndorsementPolicies returns the endorsement policies of the definitions of the
+	// given chaincode which might be the committed one, and whether they are known.
+	EndorsementPolicies(channelID, chaincodeName string) ([]*pb.ApplicationPolicy, bool)
+}
+
+// EndorsementFilterSupport provides the resources required for the endorsement filter
+type EndorsementFilterSupport interface {
+	// PolicyManager returns a reference to the current policy manager
+	PolicyManager() policies.Manager
+	// MSPManager returns the msp.MSPManager for the channel
+	MSPManager() msp.MSPManager
+}
+
+// EndorsementFilter rejects endorser transactions whose endorsements cannot
+// satisfy any of the endorsement policies the invoked chaincode might have. Transactions for
+// chaincodes that are unknown to the EndorsementPolicySource are forwarded,
+// as the peers remain responsible for the complete validation.
+//
+// Note that key-level (state-based) endorsement policies are not visible to the
+// orderer, and therefore this filter must not be enabled on channels that
+// rely on them.
+type EndorsementFilter struct {
+	support EndorsementFilterSupport
+	source  EndorsementPolicySource
+}
+
+// NewEndorsementFilter creates a new endorsement filter, at every evaluation the
+// source is queried for the latest committed chaincode definitions.
+func NewEndorsementFilter(support EndorsementFilterSupport, source EndorsementPolicySource) *EndorsementFilter {
+	return &EndorsementFilter{
+		support: support,
+		source:  source,
+	}
+}
+
+// Apply checks the endorsements of every action of an endorser transaction
+// against the chaincode endorsement policy, resulting in Reject or Forward, never Accept
+func (ef *EndorsementFilter) Apply(message *cb.Envelope) error {
+	payload, err := protoutil.UnmarshalPayload(message.Payload)
+	if err != nil {
+		return err
+	}
+	if payload.Header == nil {
+		return errors.New("missing header in payload")
+	}
+
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return err
+	}
+	if chdr.Type != int32(cb.HeaderType_ENDORSER_TRANSACTION) {
+		return nil
+	}
+
+	tx, err := protoutil.UnmarshalTransaction(payload.Data)
+	if err != nil {
+		return err
+	}
+	if len(tx.Actions) == 0 {
+		return errors.New("transaction has no actions")
+	}
+
+	for i, action := range tx.Actions {
+		ccActionPayload, ccAction, err := protoutil.GetPayloads(action)
+		if err != nil {
+			return errors.WithMessagef(err, "could not extract payload of action %d", i)
+		}
+		if ccAction.ChaincodeId == nil {
+			return errors.Errorf("action %d has no chaincode ID", i)
+		}
+
+		ccName := ccAction.ChaincodeId.Name
+		appPolicies, ok := ef.source.EndorsementPolicies(chdr.ChannelId, ccName)
+		if !ok {
+			logger.Debugf("[channel: %s] No definition known for chaincode %s, forwarding transaction %s", chdr.ChannelId, ccName, chdr.TxId)
+			continue
+		}
+
+		signatureSet := endorsementSignatureSet(ccActionPayload.Action)
+		for j, appPolicy := range appPolicies {
+			policy, err := ef.policy(appPolicy)
+			if err != nil {
+				return errors.WithMessagef(err, "could not resolve endorsement policy for chaincode %s", ccName)
+			}
+
+			err = policy.EvaluateSignedData(signatureSet)
+			if err == nil {
+				break
+			}
+			logger.Debugf("[channel: %s] Endorsement policy evaluation failed for chaincode %s, transaction %s: %s", chdr.ChannelId, ccName, chdr.TxId, err)
+			if j == len(appPolicies)-1 {
+				return errors.Wrapf(errors.WithStack(ErrPermissionDenied), "endorsement policy for chaincode %s not satisfied: %s", ccName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ef *EndorsementFilter) policy(appPolicy *pb.ApplicationPolicy) (policies.Policy, error) {
+	switch p := appPolicy.Type.(type) {
+	case *pb.ApplicationPolicy_SignaturePolicy:
+		pp := &cauthdsl.EnvelopeBasedPolicyProvider{Deserializer: ef.support.MSPManager()}
+		return pp.NewPolicy(p.SignaturePolicy)
+	case *pb.ApplicationPolicy_ChannelConfigPolicyReference:
+		policy, ok := ef.support.PolicyManager().GetPolicy(p.ChannelConfigPolicyReference)
+		if !ok {
+			return nil, errors.Errorf("could not find policy %s", p.ChannelConfigPolicyReference)
+		}
+		return policy, nil
+	default:
+		return nil, errors.Errorf("unsupported policy type %T", p)
+	}
+}
+
+// endorsementSignatureSet returns the signed data of the endorsements of the action
+func endorsementSignatureSet(action *pb.ChaincodeEndorsedAction) []*protoutil.SignedData {
+	prp := action.GetProposalResponsePayload()
+	signatureSet := make([]*protoutil.SignedData, 0, len(action.GetEndorsements()))
+	for _, endorsement := range action.GetEndorsements() {
+		data := make([]byte, len(prp)+len(endorsement.Endorser))
+		copy(data, prp)
+		copy(data[len(prp):], endorsement.Endorser)
+
+		signatureSet = append(signatureSet, &protoutil.SignedData{
+			Data:      data,
+			Identity:  endorsement.Endorser,
+			Signature: endorsement.Signature,
+		})
+	}
+	return signatureSet
+}
@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"fmt"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/common/policydsl"
+	"github.com/osdi23p228/fabric/orderer/common/msgprocessor/mocks"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeEndorserTx(ccName string, endorsers ...string) *cb.Envelope {
+	prp := protoutil.MarshalOrPanic(&pb.ProposalResponsePayload{
+		Extension: protoutil.MarshalOrPanic(&pb.ChaincodeAction{
+			ChaincodeId: &pb.ChaincodeID{Name: ccName},
+		}),
+	})
+	endorsements := make([]*pb.Endorsement, 0, len(endorsers))
+	for _, e := range endorsers {
+		endorsements = append(endorsements, &pb.Endorsement{Endorser: []byte(e), Signature: []byte("sig")})
+	}
+
+	return &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+					ChannelId: "mychannel",
+					TxId:      "tx1",
+				}),
+			},
+			Data: protoutil.MarshalOrPanic(&pb.Transaction{
+				Actions: []*pb.TransactionAction{{
+					Payload: protoutil.MarshalOrPanic(&pb.ChaincodeActionPayload{
+						Action: &pb.ChaincodeEndorsedAction{
+							ProposalResponsePayload: prp,
+							Endorsements:            endorsements,
+						},
+					}),
+				}},
+			}),
+		}),
+	}
+}
+
+func referencePolicy(name string) *pb.ApplicationPolicy {
+	return &pb.ApplicationPolicy{
+		Type: &pb.ApplicationPolicy_ChannelConfigPolicyReference{
+			ChannelConfigPolicyReference: name,
+		},
+	}
+}
+
+func TestEndorsementFilterUnknownChaincode(t *testing.T) {
+	mockResources := newMockResources(true, fmt.Errorf("Error"))
+	definitions := NewChaincodeDefinitions()
+
+	err := NewEndorsementFilter(mockResources, definitions).Apply(makeEndorserTx("mycc", "org1"))
+	assert.NoError(t, err)
+}
+
+func TestEndorsementFilterNonEndorserTx(t *testing.T) {
+	mockResources := newMockResources(true, fmt.Errorf("Error"))
+	definitions := NewChaincodeDefinitions()
+	commitDefinition(definitions, "mycc", 1, referencePolicy("/Channel/Application/Endorsement"))
+
+	env := &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+					Type: int32(cb.HeaderType_MESSAGE),
+				}),
+			},
+		}),
+	}
+	err := NewEndorsementFilter(mockResources, definitions).Apply(env)
+	assert.NoError(t, err)
+}
+
+func TestEndorsementFilterPolicyReference(t *testing.T) {
+	definitions := NewChaincodeDefinitions()
+	commitDefinition(definitions, "mycc", 1, referencePolicy("/Channel/Application/Endorsement"))
+
+	t.Run("Satisfied", func(t *testing.T) {
+		policy := &mocks.Policy{}
+		policyManager := &mocks.PolicyManager{}
+		policyManager.GetPolicyReturns(policy, true)
+		mockResources := &mocks.Resources{}
+		mockResources.PolicyManagerReturns(policyManager)
+
+		err := NewEndorsementFilter(mockResources, definitions).Apply(makeEndorserTx("mycc", "org1", "org2"))
+		assert.NoError(t, err)
+		assert.Equal(t, "/Channel/Application/Endorsement", policyManager.GetPolicyArgsForCall(0))
+		assert.Equal(t, 1, policy.EvaluateSignedDataCallCount())
+		signatureSet := policy.EvaluateSignedDataArgsForCall(0)
+		assert.Len(t, signatureSet, 2)
+		assert.Equal(t, []byte("org1"), signatureSet[0].Identity)
+		assert.Equal(t, []byte("org2"), signatureSet[1].Identity)
+	})
+
+	t.Run("NotSatisfied", func(t *testing.T) {
+		mockResources := newMockResources(true, fmt.Errorf("Error"))
+		err := NewEndorsementFilter(mockResources, definitions).Apply(makeEndorserTx("mycc", "org1"))
+		assert.Error(t, err)
+		assert.Equal(t, ErrPermissionDenied, errors.Cause(err))
+	})
+
+	t.Run("MissingPolicy", func(t *testing.T) {
+		mockResources := newMockResources(false, nil)
+		err := NewEndorsementFilter(mockResources, definitions).Apply(makeEndorserTx("mycc", "org1"))
+		assert.EqualError(t, err, "could not resolve endorsement policy for chaincode mycc: could not find policy /Channel/Application/Endorsement")
+	})
+}
+
+func TestEndorsementFilterSignaturePolicy(t *testing.T) {
+	mockResources := &mocks.Resources{}
+	definitions := NewChaincodeDefinitions()
+
+	commitDefinition(definitions, "mycc", 1, &pb.ApplicationPolicy{
+		Type: &pb.ApplicationPolicy_SignaturePolicy{
+			SignaturePolicy: policydsl.AcceptAllPolicy,
+		},
+	})
+	err := NewEndorsementFilter(mockResources, definitions).Apply(makeEndorserTx("mycc"))
+	assert.NoError(t, err)
+
+	commitDefinition(definitions, "mycc", 2, &pb.ApplicationPolicy{
+		Type: &pb.ApplicationPolicy_SignaturePolicy{
+			SignaturePolicy: policydsl.RejectAllPolicy,
+		},
+	})
+	err = NewEndorsementFilter(mockResources, definitions).Apply(makeEndorserTx("mycc"))
+	assert.NoError(t, err, "the definition with sequence 1 might still be the committed one")
+
+	commitDefinition(definitions, "mycc", 3, nil)
+	err = NewEndorsementFilter(mockResources, definitions).Apply(makeEndorserTx("mycc"))
+	assert.Error(t, err)
+	assert.Equal(t, ErrPermissionDenied, errors.Cause(err))
+}
+
+func TestEndorsementFilterMalformed(t *testing.T) {
+	mockResources := newMockResources(true, nil)
+	definitions := NewChaincodeDefinitions()
+
+	err := NewEndorsementFilter(mockResources, definitions).Apply(&cb.Envelope{Payload: []byte("garbage")})
+	assert.Error(t, err)
+
+	env := &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+					Type: int32(cb.HeaderType_ENDORSER_TRANSACTION),
+				}),
+			},
+			Data: protoutil.MarshalOrPanic(&pb.Transaction{}),
+		}),
+	}
+	err = NewEndorsementFilter(mockResources, definitions).Apply(env)
+	assert.EqualError(t, err, "transaction has no actions")
+}
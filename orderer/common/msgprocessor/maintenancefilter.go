@@ -74,46 +74,75 @@ func (mf *MaintenanceFilter) Apply(message *cb.Envelope) error {
 	return nil
 }
 
+// Violations applies the maintenance filter on a CONFIG tx, and returns every transition rule
+// the tx violates, rather than only the first one. It is intended for previewing the outcome
+// of a consensus-type migration config update.
+func (mf *MaintenanceFilter) Violations(message *cb.Envelope) []error {
+	ordererConf, ok := mf.support.OrdererConfig()
+	if !ok {
+		logger.Panic("Programming error: orderer config not found")
+	}
+
+	configEnvelope := &cb.ConfigEnvelope{}
+	if _, err := protoutil.UnmarshalEnvelopeOfType(message, cb.HeaderType_CONFIG, configEnvelope); err != nil {
+		return []error{errors.Wrap(err, "envelope unmarshalling failed")}
+	}
+
+	return mf.violations(configEnvelope, ordererConf)
+}
+
 // inspect checks whether the next orderer config, extracted from the incoming configEnvelope, respects the
 // transition rules of consensus-type migration using maintenance-mode.
 func (mf *MaintenanceFilter) inspect(configEnvelope *cb.ConfigEnvelope, ordererConfig channelconfig.Orderer) error {
+	if violations := mf.violations(configEnvelope, ordererConfig); len(violations) > 0 {
+		return violations[0]
+	}
+	return nil
+}
+
+// violations returns all the transition rules of consensus-type migration that the next orderer config,
+// extracted from the incoming configEnvelope, does not respect. The rules are returned in the order in
+// which they are checked.
+func (mf *MaintenanceFilter) violations(configEnvelope *cb.ConfigEnvelope, ordererConfig channelconfig.Orderer) []error {
 	if configEnvelope.LastUpdate == nil {
-		return errors.Errorf("updated config does not include a config update")
+		return []error{errors.Errorf("updated config does not include a config update")}
 	}
 
 	bundle, err := channelconfig.NewBundle(mf.support.ChannelID(), configEnvelope.Config, mf.bccsp)
 	if err != nil {
-		return errors.Wrap(err, "failed to parse config")
+		return []error{errors.Wrap(err, "failed to parse config")}
 	}
 
 	nextOrdererConfig, ok := bundle.OrdererConfig()
 	if !ok {
-		return errors.New("next config is missing orderer group")
+		return []error{errors.New("next config is missing orderer group")}
 	}
 
+	var violations []error
+
 	if !ordererConfig.Capabilities().ConsensusTypeMigration() {
 		if nextState := nextOrdererConfig.ConsensusState(); nextState != orderer.ConsensusType_STATE_NORMAL {
-			return errors.Errorf("next config attempted to change ConsensusType.State to %s, but capability is disabled", nextState)
+			violations = append(violations, errors.Errorf("next config attempted to change ConsensusType.State to %s, but capability is disabled", nextState))
 		}
 		if ordererConfig.ConsensusType() != nextOrdererConfig.ConsensusType() {
-			return errors.Errorf("next config attempted to change ConsensusType.Type from %s to %s, but capability is disabled",
-				ordererConfig.ConsensusType(), nextOrdererConfig.ConsensusType())
+			violations = append(violations, errors.Errorf("next config attempted to change ConsensusType.Type from %s to %s, but capability is disabled",
+				ordererConfig.ConsensusType(), nextOrdererConfig.ConsensusType()))
 		}
-		return nil
+		return violations
 	}
 
 	// Entry to- and exit from- maintenance-mode should not be accompanied by any other change.
 	if ordererConfig.ConsensusState() != nextOrdererConfig.ConsensusState() {
 		if err1Change := mf.ensureConsensusTypeChangeOnly(configEnvelope); err1Change != nil {
-			return err1Change
+			violations = append(violations, err1Change)
 		}
 		if ordererConfig.ConsensusType() != nextOrdererConfig.ConsensusType() {
-			return errors.Errorf("attempted to change ConsensusType.Type from %s to %s, but ConsensusType.State is changing from %s to %s",
-				ordererConfig.ConsensusType(), nextOrdererConfig.ConsensusType(), ordererConfig.ConsensusState(), nextOrdererConfig.ConsensusState())
+			violations = append(violations, errors.Errorf("attempted to change ConsensusType.Type from %s to %s, but ConsensusType.State is changing from %s to %s",
+				ordererConfig.ConsensusType(), nextOrdererConfig.ConsensusType(), ordererConfig.ConsensusState(), nextOrdererConfig.ConsensusState()))
 		}
 		if !bytes.Equal(nextOrdererConfig.ConsensusMetadata(), ordererConfig.ConsensusMetadata()) {
-			return errors.Errorf("attempted to change ConsensusType.Metadata, but ConsensusType.State is changing from %s to %s",
-				ordererConfig.ConsensusState(), nextOrdererConfig.ConsensusState())
+			violations = append(violations, errors.Errorf("attempted to change ConsensusType.Metadata, but ConsensusType.State is changing from %s to %s",
+				ordererConfig.ConsensusState(), nextOrdererConfig.ConsensusState()))
 		}
 	}
 
@@ -121,36 +150,38 @@ func (mf *MaintenanceFilter) inspect(configEnvelope *cb.ConfigEnvelope, ordererC
 	// Note: only kafka to etcdraft or solo to etcdraft transitions are actually supported.
 	if ordererConfig.ConsensusType() != nextOrdererConfig.ConsensusType() {
 		if ordererConfig.ConsensusState() == orderer.ConsensusType_STATE_NORMAL {
-			return errors.Errorf("attempted to change consensus type from %s to %s, but current config ConsensusType.State is not in maintenance mode",
-				ordererConfig.ConsensusType(), nextOrdererConfig.ConsensusType())
+			violations = append(violations, errors.Errorf("attempted to change consensus type from %s to %s, but current config ConsensusType.State is not in maintenance mode",
+				ordererConfig.ConsensusType(), nextOrdererConfig.ConsensusType()))
 		}
 		if nextOrdererConfig.ConsensusState() == orderer.ConsensusType_STATE_NORMAL {
-			return errors.Errorf("attempted to change consensus type from %s to %s, but next config ConsensusType.State is not in maintenance mode",
-				ordererConfig.ConsensusType(), nextOrdererConfig.ConsensusType())
+			violations = append(violations, errors.Errorf("attempted to change consensus type from %s to %s, but next config ConsensusType.State is not in maintenance mode",
+				ordererConfig.ConsensusType(), nextOrdererConfig.ConsensusType()))
 		}
 
 		if !mf.permittedTargetConsensusTypes[nextOrdererConfig.ConsensusType()] {
-			return errors.Errorf("attempted to change consensus type from %s to %s, transition not supported",
-				ordererConfig.ConsensusType(), nextOrdererConfig.ConsensusType())
+			violations = append(violations, errors.Errorf("attempted to change consensus type from %s to %s, transition not supported",
+				ordererConfig.ConsensusType(), nextOrdererConfig.ConsensusType()))
 		}
 
 		if nextOrdererConfig.ConsensusType() == "etcdraft" {
 			updatedMetadata := &protoetcdraft.ConfigMetadata{}
 			if err := proto.Unmarshal(nextOrdererConfig.ConsensusMetadata(), updatedMetadata); err != nil {
-				return errors.Wrap(err, "failed to unmarshal etcdraft metadata configuration")
+				violations = append(violations, errors.Wrap(err, "failed to unmarshal etcdraft metadata configuration"))
 			}
 		}
 
-		logger.Infof("[channel: %s] consensus-type migration: about to change from %s to %s",
-			mf.support.ChannelID(), ordererConfig.ConsensusType(), nextOrdererConfig.ConsensusType())
+		if len(violations) == 0 {
+			logger.Infof("[channel: %s] consensus-type migration: about to change from %s to %s",
+				mf.support.ChannelID(), ordererConfig.ConsensusType(), nextOrdererConfig.ConsensusType())
+		}
 	}
 
-	if nextOrdererConfig.ConsensusState() != ordererConfig.ConsensusState() {
+	if len(violations) == 0 && nextOrdererConfig.ConsensusState() != ordererConfig.ConsensusState() {
 		logger.Infof("[channel: %s] maintenance mode: ConsensusType.State about to change from %s to %s",
 			mf.support.ChannelID(), ordererConfig.ConsensusState(), nextOrdererConfig.ConsensusState())
 	}
 
-	return nil
+	return violations
 }
 
 // ensureConsensusTypeChangeOnly checks that the only change is the the Channel/Orderer group, and within that,
@@ -346,6 +346,43 @@ func TestMaintenanceMissingConsensusType(t *testing.T) {
 	}
 }
 
+func TestMaintenanceViolations(t *testing.T) {
+	msActive := &mockSystemChannelFilterSupport{
+		OrdererConfigVal: newMockOrdererConfig(true, orderer.ConsensusType_STATE_NORMAL),
+	}
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	assert.NoError(t, err)
+	mf := NewMaintenanceFilter(msActive, cryptoProvider)
+	require.NotNil(t, mf)
+	current := consensusTypeInfo{ordererType: "kafka", metadata: []byte{}, state: orderer.ConsensusType_STATE_NORMAL}
+
+	t.Run("Good", func(t *testing.T) {
+		next := consensusTypeInfo{ordererType: "kafka", metadata: []byte{}, state: orderer.ConsensusType_STATE_MAINTENANCE}
+		configTx := makeConfigEnvelope(t, current, next)
+		assert.Empty(t, mf.Violations(configTx))
+	})
+
+	t.Run("Bad envelope", func(t *testing.T) {
+		violations := mf.Violations(&common.Envelope{})
+		require.Len(t, violations, 1)
+		assert.EqualError(t, violations[0], "envelope unmarshalling failed: envelope must have a Header")
+	})
+
+	t.Run("All violations reported", func(t *testing.T) {
+		next := consensusTypeInfo{ordererType: "etcdraft", metadata: []byte{1, 2, 3, 4}, state: orderer.ConsensusType_STATE_MAINTENANCE}
+		configTx := makeConfigEnvelope(t, current, next)
+		violations := mf.Violations(configTx)
+		require.Len(t, violations, 4)
+		assert.EqualError(t, violations[0], "attempted to change ConsensusType.Type from kafka to etcdraft, but ConsensusType.State is changing from STATE_NORMAL to STATE_MAINTENANCE")
+		assert.EqualError(t, violations[1], "attempted to change ConsensusType.Metadata, but ConsensusType.State is changing from STATE_NORMAL to STATE_MAINTENANCE")
+		assert.EqualError(t, violations[2], "attempted to change consensus type from kafka to etcdraft, but current config ConsensusType.State is not in maintenance mode")
+		assert.Contains(t, violations[3].Error(), "failed to unmarshal etcdraft metadata configuration")
+
+		err := mf.Apply(configTx)
+		assert.EqualError(t, err, "config transaction inspection failed: "+violations[0].Error())
+	})
+}
+
 type consensusTypeInfo struct {
 	ordererType string
 	metadata    []byte
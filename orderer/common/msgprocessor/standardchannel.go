@@ -63,7 +63,10 @@ func NewStandardChannel(support StandardChannelSupport, filters *RuleSet, bccsp
 //
 // In maintenance mode, require the signature of /Channel/Orderer/Writer. This will filter out configuration
 // changes that are not related to consensus-type migration (e.g on /Channel/Application).
-func CreateStandardChannelFilters(filterSupport channelconfig.Resources, config localconfig.TopLevel) *RuleSet {
+//
+// When endorsement pre-validation is enabled and an EndorsementPolicySource is supplied, endorser transactions
+// whose endorsements cannot satisfy the chaincode endorsement policy are rejected as well.
+func CreateStandardChannelFilters(filterSupport channelconfig.Resources, config localconfig.TopLevel, endorsementPolicies EndorsementPolicySource) *RuleSet {
 	rules := []Rule{
 		EmptyRejectRule,
 		NewSizeFilter(filterSupport),
@@ -76,6 +79,11 @@ func CreateStandardChannelFilters(filterSupport channelconfig.Resources, config
 		rules = append(rules[:2], append([]Rule{expirationRule}, rules[2:]...)...)
 	}
 
+	if config.General.Authentication.PrevalidateEndorsements && endorsementPolicies != nil {
+		// Evaluated last, as it is the most expensive check
+		rules = append(rules, NewEndorsementFilter(filterSupport, endorsementPolicies))
+	}
+
 	return NewRuleSet(rules)
 }
 
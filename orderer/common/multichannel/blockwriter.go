@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package multichannel
 
 import (
+	"bytes"
 	"sync"
 
 	"github.com/golang/protobuf/proto"
@@ -188,6 +189,9 @@ func (bw *BlockWriter) commitBlock(encodedMetadataValue []byte) {
 	logger.Debugf("[channel: %s] Wrote block [%d]", bw.support.ChannelID(), bw.lastBlock.GetHeader().Number)
 }
 
+// addBlockSignature signs the block and sets the signature metadata. Signatures already present in the
+// block metadata, e.g. co-signatures collected by the consenter from other orderers, are preserved as long
+// as they were produced over the same metadata value.
 func (bw *BlockWriter) addBlockSignature(block *cb.Block, consenterMetadata []byte) {
 	blockSignature := &cb.MetadataSignature{
 		SignatureHeader: protoutil.MarshalOrPanic(protoutil.NewSignatureHeaderOrPanic(bw.support)),
@@ -203,11 +207,19 @@ func (bw *BlockWriter) addBlockSignature(block *cb.Block, consenterMetadata []by
 		util.ConcatenateBytes(blockSignatureValue, blockSignature.SignatureHeader, protoutil.BlockHeaderBytes(block.Header)),
 	)
 
+	signatures := []*cb.MetadataSignature{blockSignature}
+	if existing := block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES]; len(existing) > 0 {
+		md := &cb.Metadata{}
+		if err := proto.Unmarshal(existing, md); err != nil || !bytes.Equal(md.Value, blockSignatureValue) {
+			logger.Warningf("[channel: %s] Discarding co-signatures of block [%d] which were produced over different metadata", bw.support.ChannelID(), block.Header.Number)
+		} else {
+			signatures = append(signatures, md.Signatures...)
+		}
+	}
+
 	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&cb.Metadata{
-		Value: blockSignatureValue,
-		Signatures: []*cb.MetadataSignature{
-			blockSignature,
-		},
+		Value:      blockSignatureValue,
+		Signatures: signatures,
 	})
 }
 
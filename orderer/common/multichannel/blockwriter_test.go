@@ -113,6 +113,64 @@ func TestBlockSignature(t *testing.T) {
 	assert.NotNil(t, md.Signatures, "Should have signature")
 }
 
+func TestBlockCoSignatures(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file-ledger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rlf, err := fileledger.New(dir, &disabled.Provider{})
+	require.NoError(t, err)
+
+	l, err := rlf.GetOrCreate("mychannel")
+	assert.NoError(t, err)
+	lastBlock := protoutil.NewBlock(0, nil)
+	l.Append(lastBlock)
+
+	consensusMetadata := []byte("bar")
+	metadataValue := protoutil.MarshalOrPanic(&cb.OrdererBlockMetadata{
+		LastConfig:        &cb.LastConfig{Index: 42},
+		ConsenterMetadata: protoutil.MarshalOrPanic(&cb.Metadata{Value: consensusMetadata}),
+	})
+	coSignature := &cb.MetadataSignature{SignatureHeader: []byte("header"), Signature: []byte("signature")}
+
+	newBlockWriter := func(value []byte) *BlockWriter {
+		prevBlock := blockledger.GetBlock(l, l.Height()-1)
+		block := protoutil.NewBlock(l.Height(), protoutil.BlockHeaderHash(prevBlock.Header))
+		block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&cb.Metadata{
+			Value:      value,
+			Signatures: []*cb.MetadataSignature{coSignature},
+		})
+		return &BlockWriter{
+			lastConfigBlockNum: 42,
+			support: &mockBlockWriterSupport{
+				SignerSerializer:  mockCrypto(),
+				ConfigTXValidator: &mocks.ConfigTXValidator{},
+				ReadWriter:        l,
+			},
+			lastBlock: block,
+		}
+	}
+
+	t.Run("same value", func(t *testing.T) {
+		bw := newBlockWriter(metadataValue)
+		bw.commitBlock(consensusMetadata)
+
+		md := protoutil.GetMetadataFromBlockOrPanic(blockledger.GetBlock(l, l.Height()-1), cb.BlockMetadataIndex_SIGNATURES)
+		assert.Equal(t, metadataValue, md.Value)
+		require.Len(t, md.Signatures, 2)
+		assert.True(t, proto.Equal(coSignature, md.Signatures[1]))
+	})
+
+	t.Run("different value", func(t *testing.T) {
+		bw := newBlockWriter([]byte("other value"))
+		bw.commitBlock(consensusMetadata)
+
+		md := protoutil.GetMetadataFromBlockOrPanic(blockledger.GetBlock(l, l.Height()-1), cb.BlockMetadataIndex_SIGNATURES)
+		assert.Equal(t, metadataValue, md.Value)
+		assert.Len(t, md.Signatures, 1)
+	})
+}
+
 func TestBlockLastConfig(t *testing.T) {
 	lastConfigSeq := uint64(6)
 	newConfigSeq := lastConfigSeq + 1
@@ -72,7 +72,7 @@ func newChainSupport(
 	}
 
 	// Set up the msgprocessor
-	cs.Processor = msgprocessor.NewStandardChannel(cs, msgprocessor.CreateStandardChannelFilters(cs, registrar.config), bccsp)
+	cs.Processor = msgprocessor.NewStandardChannel(cs, msgprocessor.CreateStandardChannelFilters(cs, registrar.config, registrar.chaincodeDefinitions), bccsp)
 
 	// Set up the block writer
 	cs.BlockWriter = newBlockWriter(lastBlock, registrar, cs)
@@ -135,7 +135,7 @@ func newChainSupportForJoin(
 	}
 
 	// Set up the msgprocessor
-	cs.Processor = msgprocessor.NewStandardChannel(cs, msgprocessor.CreateStandardChannelFilters(cs, registrar.config), bccsp)
+	cs.Processor = msgprocessor.NewStandardChannel(cs, msgprocessor.CreateStandardChannelFilters(cs, registrar.config, registrar.chaincodeDefinitions), bccsp)
 	// No BlockWriter, this will be created when the chain gets converted from follower.Chain to etcdraft.Chain
 	cs.BlockWriter = nil //TODO change embedding of BlockWriter struct to interface, and put here a NoOp implementation or one that panics if used
 
@@ -255,7 +255,7 @@ func (cs *ChainSupport) Sequence() uint64 {
 // Append appends a new block to the ledger in its raw form,
 // unlike WriteBlock that also mutates its metadata.
 func (cs *ChainSupport) Append(block *cb.Block) error {
-	return cs.ledgerResources.ReadWriter.Append(block)
+	return cs.ledgerResources.Append(block)
 }
 
 // VerifyBlockSignature verifies a signature of a block.
@@ -21,6 +21,7 @@ import (
 	"github.com/osdi23p228/fabric/common/flogging"
 	"github.com/osdi23p228/fabric/common/ledger/blockledger"
 	"github.com/osdi23p228/fabric/common/metrics"
+	"github.com/osdi23p228/fabric/common/policies"
 	"github.com/osdi23p228/fabric/internal/pkg/identity"
 	"github.com/osdi23p228/fabric/orderer/common/blockcutter"
 	"github.com/osdi23p228/fabric/orderer/common/localconfig"
@@ -91,6 +92,20 @@ func (cr *configResources) SharedConfig() channelconfig.Orderer {
 type ledgerResources struct {
 	*configResources
 	blockledger.ReadWriter
+
+	// chaincodeDefinitions is only set when endorsements are pre-validated
+	chaincodeDefinitions *msgprocessor.ChaincodeDefinitions
+}
+
+// Append appends the block to the ledger and records the chaincode definitions it commits.
+func (lr *ledgerResources) Append(block *cb.Block) error {
+	if err := lr.ReadWriter.Append(block); err != nil {
+		return err
+	}
+	if lr.chaincodeDefinitions != nil {
+		lr.chaincodeDefinitions.ProcessBlock(lr.ConfigtxValidator().ChannelID(), block, lr.PolicyManager())
+	}
+	return nil
 }
 
 // Registrar serves as a point of access and control for the individual channel resources.
@@ -108,6 +123,8 @@ type Registrar struct {
 	templator          msgprocessor.ChannelConfigTemplator
 	callbacks          []channelconfig.BundleActor
 	bccsp              bccsp.BCCSP
+
+	chaincodeDefinitions *msgprocessor.ChaincodeDefinitions
 }
 
 // ConfigBlock retrieves the last configuration block from the given ledger.
@@ -147,6 +164,8 @@ func NewRegistrar(
 		blockcutterMetrics: blockcutter.NewMetrics(metricsProvider),
 		callbacks:          callbacks,
 		bccsp:              bccsp,
+
+		chaincodeDefinitions: msgprocessor.NewChaincodeDefinitions(),
 	}
 
 	return r
@@ -327,13 +346,75 @@ func (r *Registrar) newLedgerResources(configTx *cb.Envelope) (*ledgerResources,
 		return nil, errors.Wrapf(err, "error getting ledger for channel: %s", chdr.ChannelId)
 	}
 
-	return &ledgerResources{
+	lr := &ledgerResources{
 		configResources: &configResources{
 			mutableResources: channelconfig.NewBundleSource(bundle, r.callbacks...),
 			bccsp:            r.bccsp,
 		},
 		ReadWriter: ledger,
-	}, nil
+	}
+
+	if r.config.General.Authentication.PrevalidateEndorsements {
+		if err := r.replayChaincodeDefinitions(chdr.ChannelId, ledger); err != nil {
+			return nil, errors.WithMessagef(err, "error recording chaincode definitions for channel: %s", chdr.ChannelId)
+		}
+		lr.chaincodeDefinitions = r.chaincodeDefinitions
+	}
+
+	return lr, nil
+}
+
+// replayChaincodeDefinitions records the chaincode definitions committed by the blocks
+// of the ledger, evaluating each block against the channel configuration it was created with.
+func (r *Registrar) replayChaincodeDefinitions(channelID string, ledger blockledger.Reader) error {
+	r.chaincodeDefinitions.Reset(channelID)
+
+	height := ledger.Height()
+	if height == 0 {
+		return nil
+	}
+
+	itr, _ := ledger.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 0}}})
+	defer itr.Close()
+
+	var policyManager policies.Manager
+	for number := uint64(0); number < height; number++ {
+		block, status := itr.Next()
+		if status != cb.Status_SUCCESS {
+			return errors.Errorf("error reading block [%d]: %s", number, status)
+		}
+
+		if protoutil.IsConfigBlock(block) {
+			configEnv, err := protoutil.ExtractEnvelope(block, 0)
+			if err != nil {
+				return errors.WithMessagef(err, "error extracting config envelope from block [%d]", number)
+			}
+			bundle, err := r.bundleFromConfigEnvelope(channelID, configEnv)
+			if err != nil {
+				return errors.WithMessagef(err, "error creating channelconfig bundle from block [%d]", number)
+			}
+			policyManager = bundle.PolicyManager()
+			continue
+		}
+		if policyManager == nil {
+			return errors.Errorf("block [%d] precedes the first config block", number)
+		}
+		r.chaincodeDefinitions.ProcessBlock(channelID, block, policyManager)
+	}
+
+	return nil
+}
+
+func (r *Registrar) bundleFromConfigEnvelope(channelID string, configEnv *cb.Envelope) (*channelconfig.Bundle, error) {
+	payload, err := protoutil.UnmarshalPayload(configEnv.Payload)
+	if err != nil {
+		return nil, err
+	}
+	configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	return channelconfig.NewBundle(channelID, configEnvelope.Config, r.bccsp)
 }
 
 // CreateChain makes the Registrar create a chain with the given name.
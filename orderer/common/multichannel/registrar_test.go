@@ -15,7 +15,12 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/osdi23p228/fabric/bccsp/factory"
 	"github.com/osdi23p228/fabric/bccsp/sw"
 	"github.com/osdi23p228/fabric/common/channelconfig"
 	"github.com/osdi23p228/fabric/common/crypto/tlsgen"
@@ -27,6 +32,7 @@ import (
 	"github.com/osdi23p228/fabric/internal/configtxgen/encoder"
 	"github.com/osdi23p228/fabric/internal/configtxgen/genesisconfig"
 	"github.com/osdi23p228/fabric/internal/pkg/identity"
+	mspmgmt "github.com/osdi23p228/fabric/msp/mgmt"
 	"github.com/osdi23p228/fabric/orderer/common/blockcutter"
 	"github.com/osdi23p228/fabric/orderer/common/localconfig"
 	"github.com/osdi23p228/fabric/orderer/common/multichannel/mocks"
@@ -665,3 +671,117 @@ func generateCertificates(t *testing.T, confAppRaft *genesisconfig.Profile, tlsC
 		c.ClientTlsCert = []byte(clnP)
 	}
 }
+
+// makeLifecycleTx creates a _lifecycle transaction endorsed by the signer, which
+// commits the definition of the chaincode with the given endorsement policy
+func makeLifecycleTx(t *testing.T, channelID, ccName string, sequence int64, policy string, signer identity.SignerSerializer) *cb.Envelope {
+	validationInfo := protoutil.MarshalOrPanic(&lb.ChaincodeValidationInfo{
+		ValidationPlugin: "vscc",
+		ValidationParameter: protoutil.MarshalOrPanic(&pb.ApplicationPolicy{
+			Type: &pb.ApplicationPolicy_ChannelConfigPolicyReference{ChannelConfigPolicyReference: policy},
+		}),
+	})
+	writes := []*kvrwset.KVWrite{
+		{
+			Key:   fmt.Sprintf("namespaces/fields/%s/Sequence", ccName),
+			Value: protoutil.MarshalOrPanic(&lb.StateData{Type: &lb.StateData_Int64{Int64: sequence}}),
+		},
+		{
+			Key:   fmt.Sprintf("namespaces/fields/%s/ValidationInfo", ccName),
+			Value: protoutil.MarshalOrPanic(&lb.StateData{Type: &lb.StateData_Bytes{Bytes: validationInfo}}),
+		},
+	}
+	prp := protoutil.MarshalOrPanic(&pb.ProposalResponsePayload{
+		Extension: protoutil.MarshalOrPanic(&pb.ChaincodeAction{
+			ChaincodeId: &pb.ChaincodeID{Name: "_lifecycle"},
+			Results: protoutil.MarshalOrPanic(&rwset.TxReadWriteSet{
+				NsRwset: []*rwset.NsReadWriteSet{{
+					Namespace: "_lifecycle",
+					Rwset:     protoutil.MarshalOrPanic(&kvrwset.KVRWSet{Writes: writes}),
+				}},
+			}),
+		}),
+	})
+
+	endorser, err := signer.Serialize()
+	require.NoError(t, err)
+	signature, err := signer.Sign(append(append([]byte{}, prp...), endorser...))
+	require.NoError(t, err)
+
+	return &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+					ChannelId: channelID,
+				}),
+			},
+			Data: protoutil.MarshalOrPanic(&pb.Transaction{
+				Actions: []*pb.TransactionAction{{
+					Payload: protoutil.MarshalOrPanic(&pb.ChaincodeActionPayload{
+						Action: &pb.ChaincodeEndorsedAction{
+							ProposalResponsePayload: prp,
+							Endorsements:            []*pb.Endorsement{{Endorser: endorser, Signature: signature}},
+						},
+					}),
+				}},
+			}),
+		}),
+	}
+}
+
+func TestChaincodeDefinitionsFromLedger(t *testing.T) {
+	err := mspmgmt.LoadLocalMsp(configtest.GetDevMspDir(), nil, "SampleOrg")
+	require.NoError(t, err)
+	signer := mspmgmt.GetLocalSigningIdentityOrPanic(factory.GetDefault())
+
+	confApp := genesisconfig.Load(genesisconfig.SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+	genesisBlockApp := encoder.New(confApp).GenesisBlockForChannel("mychannel")
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	tmpdir, err := ioutil.TempDir("", "registrar_test-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	lf, rl := newLedgerAndFactory(tmpdir, "mychannel", genesisBlockApp)
+	err = rl.Append(blockledger.CreateNextBlock(rl, []*cb.Envelope{
+		makeNormalTx("mychannel", 1),
+		makeLifecycleTx(t, "mychannel", "mycc", 1, "/Channel/Application/Writers", signer),
+	}))
+	require.NoError(t, err)
+
+	config := localconfig.TopLevel{}
+	config.General.Authentication.PrevalidateEndorsements = true
+	registrar := NewRegistrar(config, lf, mockCrypto(), &disabled.Provider{}, cryptoProvider)
+
+	// the definitions committed in the ledger are recorded when the channel resources are created
+	lr, err := registrar.newLedgerResources(configTx(rl))
+	require.NoError(t, err)
+	policies, ok := registrar.chaincodeDefinitions.EndorsementPolicies("mychannel", "mycc")
+	require.True(t, ok)
+	require.Len(t, policies, 1)
+	assert.Equal(t, "/Channel/Application/Writers", policies[0].GetChannelConfigPolicyReference())
+
+	// as are the ones committed in the blocks appended afterwards
+	err = lr.Append(blockledger.CreateNextBlock(lr, []*cb.Envelope{
+		makeLifecycleTx(t, "mychannel", "mycc", 2, "/Channel/Application/Readers", signer),
+		makeLifecycleTx(t, "mychannel", "othercc", 1, "/Channel/Application/Readers", mockCrypto()),
+	}))
+	require.NoError(t, err)
+	policies, ok = registrar.chaincodeDefinitions.EndorsementPolicies("mychannel", "mycc")
+	require.True(t, ok)
+	require.Len(t, policies, 2)
+	assert.Equal(t, "/Channel/Application/Readers", policies[1].GetChannelConfigPolicyReference())
+	_, ok = registrar.chaincodeDefinitions.EndorsementPolicies("mychannel", "othercc")
+	assert.False(t, ok, "the definition is not endorsed as per the LifecycleEndorsement policy")
+
+	// the definitions are not recorded when endorsements are not pre-validated
+	registrar = NewRegistrar(localconfig.TopLevel{}, lf, mockCrypto(), &disabled.Provider{}, cryptoProvider)
+	lr, err = registrar.newLedgerResources(configTx(rl))
+	require.NoError(t, err)
+	assert.Nil(t, lr.chaincodeDefinitions)
+	_, ok = registrar.chaincodeDefinitions.EndorsementPolicies("mychannel", "mycc")
+	assert.False(t, ok)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"runtime/debug"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/common/deliver"
+	"github.com/osdi23p228/fabric/protoutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type headerResponseSender struct {
+	pb.Deliver_DeliverServer
+}
+
+func (hrs *headerResponseSender) SendStatusResponse(status cb.Status) error {
+	reply := &pb.DeliverResponse{
+		Type: &pb.DeliverResponse_Status{Status: status},
+	}
+	return hrs.Send(reply)
+}
+
+// SendBlockResponse sends the header and the metadata of the block.
+func (hrs *headerResponseSender) SendBlockResponse(
+	block *cb.Block,
+	channelID string,
+	chain deliver.Chain,
+	signedData *protoutil.SignedData,
+) error {
+	response := &pb.DeliverResponse{
+		Type: &pb.DeliverResponse_Block{Block: deliver.HeaderOnlyBlock(block)},
+	}
+	return hrs.Send(response)
+}
+
+func (hrs *headerResponseSender) DataType() string {
+	return "block_header"
+}
+
+type filteredResponseSender struct {
+	pb.Deliver_DeliverFilteredServer
+}
+
+func (frs *filteredResponseSender) SendStatusResponse(status cb.Status) error {
+	reply := &pb.DeliverResponse{
+		Type: &pb.DeliverResponse_Status{Status: status},
+	}
+	return frs.Send(reply)
+}
+
+// IsFiltered is a marker method which indicates that this response sender
+// sends filtered blocks.
+func (frs *filteredResponseSender) IsFiltered() bool {
+	return true
+}
+
+// SendBlockResponse sends the filtered form of the block.
+func (frs *filteredResponseSender) SendBlockResponse(
+	block *cb.Block,
+	channelID string,
+	chain deliver.Chain,
+	signedData *protoutil.SignedData,
+) error {
+	filteredBlock, err := deliver.FilterBlock(block)
+	if err != nil {
+		logger.Warningf("Failed to generate filtered block due to: %s", err)
+		return frs.SendStatusResponse(cb.Status_BAD_REQUEST)
+	}
+	response := &pb.DeliverResponse{
+		Type: &pb.DeliverResponse_FilteredBlock{FilteredBlock: filteredBlock},
+	}
+	return frs.Send(response)
+}
+
+func (frs *filteredResponseSender) DataType() string {
+	return "filtered_block"
+}
+
+// lightDeliverServer implements the peer Deliver service on the orderer, for
+// clients which follow a channel without needing its transactions. Since full
+// blocks are served by the AtomicBroadcast service, Deliver streams header-only
+// blocks, and DeliverFiltered streams filtered blocks whose transactions are
+// reported as NOT_VALIDATED. Access is controlled as for AtomicBroadcast.Deliver.
+type lightDeliverServer struct {
+	*server
+}
+
+func newLightDeliverServer(abServer ab.AtomicBroadcastServer) pb.DeliverServer {
+	return &lightDeliverServer{server: abServer.(*server)}
+}
+
+// Deliver sends a stream of header-only blocks to a client after ordering
+func (s *lightDeliverServer) Deliver(srv pb.Deliver_DeliverServer) error {
+	logger.Debugf("Starting new header-only Deliver handler")
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Criticalf("Header-only Deliver client triggered panic: %s\n%s", r, debug.Stack())
+		}
+		logger.Debugf("Closing header-only Deliver stream")
+	}()
+
+	return s.handleDeliver(srv.Context(), srv, "DeliverHeaders", &headerResponseSender{
+		Deliver_DeliverServer: srv,
+	})
+}
+
+// DeliverFiltered sends a stream of filtered blocks to a client after ordering
+func (s *lightDeliverServer) DeliverFiltered(srv pb.Deliver_DeliverFilteredServer) error {
+	logger.Debugf("Starting new DeliverFiltered handler")
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Criticalf("DeliverFiltered client triggered panic: %s\n%s", r, debug.Stack())
+		}
+		logger.Debugf("Closing DeliverFiltered stream")
+	}()
+
+	return s.handleDeliver(srv.Context(), srv, "DeliverFiltered", &filteredResponseSender{
+		Deliver_DeliverFilteredServer: srv,
+	})
+}
+
+// DeliverWithPrivateData is not supported by the orderer, which holds no private data.
+func (s *lightDeliverServer) DeliverWithPrivateData(srv pb.Deliver_DeliverWithPrivateDataServer) error {
+	return status.Error(codes.Unimplemented, "the ordering service does not hold private data")
+}
@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockLightDeliverSrv struct {
+	grpc.ServerStream
+	sent []*pb.DeliverResponse
+}
+
+func (mlds *mockLightDeliverSrv) Recv() (*cb.Envelope, error) {
+	panic("Unimplemented")
+}
+
+func (mlds *mockLightDeliverSrv) Send(resp *pb.DeliverResponse) error {
+	mlds.sent = append(mlds.sent, resp)
+	return nil
+}
+
+func TestLightDeliverNoPanic(t *testing.T) {
+	// Defer recovers from the panic
+	_ = (&lightDeliverServer{server: &server{}}).Deliver(nil)
+	_ = (&lightDeliverServer{server: &server{}}).DeliverFiltered(nil)
+}
+
+func TestLightDeliverWithPrivateData(t *testing.T) {
+	err := (&lightDeliverServer{server: &server{}}).DeliverWithPrivateData(nil)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func lightDeliverTestBlock() *cb.Block {
+	block := protoutil.NewBlock(3, []byte("previous-hash"))
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(&cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+					ChannelId: "mychannel",
+					TxId:      "tx1",
+					Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+				}),
+			},
+		}),
+	})}
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+	return block
+}
+
+func TestHeaderResponseSender(t *testing.T) {
+	srv := &mockLightDeliverSrv{}
+	hrs := &headerResponseSender{Deliver_DeliverServer: srv}
+	assert.Equal(t, "block_header", hrs.DataType())
+
+	block := lightDeliverTestBlock()
+	require.NoError(t, hrs.SendBlockResponse(block, "mychannel", nil, nil))
+	require.NoError(t, hrs.SendStatusResponse(cb.Status_SUCCESS))
+
+	require.Len(t, srv.sent, 2)
+	sentBlock := srv.sent[0].GetBlock()
+	require.NotNil(t, sentBlock)
+	assert.Nil(t, sentBlock.Data)
+	assert.Equal(t, protoutil.BlockHeaderHash(block.Header), protoutil.BlockHeaderHash(sentBlock.Header))
+	assert.Equal(t, cb.Status_SUCCESS, srv.sent[1].GetStatus())
+}
+
+func TestFilteredResponseSender(t *testing.T) {
+	srv := &mockLightDeliverSrv{}
+	frs := &filteredResponseSender{Deliver_DeliverFilteredServer: srv}
+	assert.Equal(t, "filtered_block", frs.DataType())
+	assert.True(t, frs.IsFiltered())
+
+	require.NoError(t, frs.SendBlockResponse(lightDeliverTestBlock(), "mychannel", nil, nil))
+	require.Len(t, srv.sent, 1)
+	filteredBlock := srv.sent[0].GetFilteredBlock()
+	require.NotNil(t, filteredBlock)
+	assert.Equal(t, uint64(3), filteredBlock.Number)
+	assert.Equal(t, "mychannel", filteredBlock.ChannelId)
+	require.Len(t, filteredBlock.FilteredTransactions, 1)
+	assert.Equal(t, "tx1", filteredBlock.FilteredTransactions[0].Txid)
+	assert.Equal(t, pb.TxValidationCode_NOT_VALIDATED, filteredBlock.FilteredTransactions[0].TxValidationCode)
+
+	badBlock := lightDeliverTestBlock()
+	badBlock.Data.Data[0] = []byte("garbage")
+	require.NoError(t, frs.SendBlockResponse(badBlock, "mychannel", nil, nil))
+	require.Len(t, srv.sent, 2)
+	assert.Equal(t, cb.Status_BAD_REQUEST, srv.sent[1].GetStatus())
+}
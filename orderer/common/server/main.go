@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/fabric-lib-go/healthz"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/osdi23p228/fabric/bccsp"
 	"github.com/osdi23p228/fabric/bccsp/factory"
 	"github.com/osdi23p228/fabric/common/channelconfig"
@@ -64,6 +65,14 @@ var (
 	_       = app.Command("start", "Start the orderer node").Default() // preserved for cli compatibility
 	version = app.Command("version", "Show version information")
 
+	migrationCmd       = app.Command("migration", "Consensus-type migration tooling, to be used offline")
+	migrationDryRun    = migrationCmd.Command("dryrun", "Report every rule a consensus-type migration config update violates")
+	dryRunConfigBlock  = migrationDryRun.Flag("configBlock", "The latest config block of the channel").Required().ExistingFile()
+	dryRunConfigUpdate = migrationDryRun.Flag("configUpdate", "The signed config update envelope to evaluate").Required().ExistingFile()
+	migrationVerify    = migrationCmd.Command("verify", "Check the consistency of the ledgers of a channel across stopped orderers")
+	verifyChannelID    = migrationVerify.Flag("channelID", "The channel to verify").Required().String()
+	verifyLedgers      = migrationVerify.Flag("ledger", "An orderer name and its file ledger directory, as name=dir (may be repeated)").Required().StringMap()
+
 	clusterTypes = map[string]struct{}{"etcdraft": {}}
 )
 
@@ -77,6 +86,20 @@ func Main() {
 		return
 	}
 
+	// "migration" commands
+	switch fullCmd {
+	case migrationDryRun.FullCommand():
+		if err := migrationDryRunCmd(*dryRunConfigBlock, *dryRunConfigUpdate, os.Stdout); err != nil {
+			app.Fatalf("%s", err)
+		}
+		return
+	case migrationVerify.FullCommand():
+		if err := migrationVerifyCmd(*verifyChannelID, *verifyLedgers, os.Stdout); err != nil {
+			app.Fatalf("%s", err)
+		}
+		return
+	}
+
 	conf, err := localconfig.Load()
 	if err != nil {
 		logger.Error("failed to parse config: ", err)
@@ -271,6 +294,7 @@ func Main() {
 		go initializeProfilingService(conf)
 	}
 	ab.RegisterAtomicBroadcastServer(grpcServer.Server(), server)
+	pb.RegisterDeliverServer(grpcServer.Server(), newLightDeliverServer(server))
 	logger.Info("Beginning to serve requests")
 	if err := grpcServer.Start(); err != nil {
 		logger.Fatalf("Atomic Broadcast gRPC server has terminated while serving requests due to: %v", err)
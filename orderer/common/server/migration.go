@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/osdi23p228/fabric/bccsp/factory"
+	"github.com/osdi23p228/fabric/common/ledger/blockledger"
+	"github.com/osdi23p228/fabric/common/ledger/blockledger/fileledger"
+	"github.com/osdi23p228/fabric/common/metrics/disabled"
+	"github.com/osdi23p228/fabric/orderer/common/migration"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// migrationDryRunCmd evaluates a consensus-type migration config update against the
+// given config block, and writes the report to out. An error is returned if the update
+// violates any rule.
+func migrationDryRunCmd(configBlockPath, configUpdatePath string, out io.Writer) error {
+	blockBytes, err := ioutil.ReadFile(configBlockPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read config block")
+	}
+	block, err := protoutil.UnmarshalBlock(blockBytes)
+	if err != nil {
+		return errors.WithMessage(err, "failed to unmarshal config block")
+	}
+
+	updateBytes, err := ioutil.ReadFile(configUpdatePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read config update")
+	}
+	update, err := protoutil.UnmarshalEnvelope(updateBytes)
+	if err != nil {
+		return errors.WithMessage(err, "failed to unmarshal config update")
+	}
+
+	report, err := migration.DryRun(block, update, factory.GetDefault())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(out, report)
+	if !report.Passed() {
+		return errors.Errorf("config update violates %d rule(s)", len(report.Violations))
+	}
+	return nil
+}
+
+// migrationVerifyCmd compares the ledgers of a channel found in the given file ledger
+// directories, and writes the report to out. An error is returned if they are not consistent.
+func migrationVerifyCmd(channelID string, ledgerDirs map[string]string, out io.Writer) error {
+	if len(ledgerDirs) < 2 {
+		return errors.New("at least two ledgers are required")
+	}
+
+	ledgers := map[string]blockledger.Reader{}
+	for name, dir := range ledgerDirs {
+		lf, err := fileledger.New(dir, &disabled.Provider{})
+		if err != nil {
+			return errors.WithMessagef(err, "failed to open ledger of %s", name)
+		}
+		defer lf.Close()
+
+		if !contains(lf.ChannelIDs(), channelID) {
+			return errors.Errorf("ledger of %s does not contain channel %s", name, channelID)
+		}
+		rl, err := lf.GetOrCreate(channelID)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to open ledger of %s", name)
+		}
+		ledgers[name] = rl
+	}
+
+	report, err := migration.CheckLedgerConsistency(ledgers, factory.GetDefault())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(out, report)
+	if !report.Consistent() {
+		return errors.Errorf("ledgers of channel %s are not consistent", channelID)
+	}
+	return nil
+}
+
+func contains(channelIDs []string, channelID string) bool {
+	for _, id := range channelIDs {
+		if id == channelID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/osdi23p228/fabric/common/ledger/blockledger/fileledger"
+	"github.com/osdi23p228/fabric/common/metrics/disabled"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationDryRunCmdBadInput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migration-cmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	err = migrationDryRunCmd(filepath.Join(dir, "missing"), filepath.Join(dir, "missing"), &bytes.Buffer{})
+	assert.Contains(t, err.Error(), "failed to read config block")
+
+	garbage := filepath.Join(dir, "garbage")
+	require.NoError(t, ioutil.WriteFile(garbage, []byte{1, 2, 3}, 0600))
+	err = migrationDryRunCmd(garbage, garbage, &bytes.Buffer{})
+	assert.Contains(t, err.Error(), "failed to unmarshal config block")
+}
+
+func TestMigrationVerifyCmdBadInput(t *testing.T) {
+	err := migrationVerifyCmd("mychannel", map[string]string{"o1": "dir"}, &bytes.Buffer{})
+	assert.EqualError(t, err, "at least two ledgers are required")
+
+	dir1, err := ioutil.TempDir("", "migration-cmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir1)
+	dir2, err := ioutil.TempDir("", "migration-cmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir2)
+
+	lf, err := fileledger.New(dir1, &disabled.Provider{})
+	require.NoError(t, err)
+	lf.Close()
+
+	err = migrationVerifyCmd("mychannel", map[string]string{"o1": dir1, "o2": dir2}, &bytes.Buffer{})
+	assert.Contains(t, err.Error(), "does not contain channel mychannel")
+}
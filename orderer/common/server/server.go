@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package server
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -183,6 +184,14 @@ func (s *server) Deliver(srv ab.AtomicBroadcast_DeliverServer) error {
 		logger.Debugf("Closing Deliver stream")
 	}()
 
+	return s.handleDeliver(srv.Context(), srv, "Deliver", &responseSender{
+		AtomicBroadcast_DeliverServer: srv,
+	})
+}
+
+// handleDeliver serves the deliver requests received over a stream, sending the
+// blocks in the form chosen by the response sender.
+func (s *server) handleDeliver(ctx context.Context, receiver deliver.Receiver, function string, sender deliver.ResponseSender) error {
 	policyChecker := func(env *cb.Envelope, channelID string) error {
 		chain := s.GetChain(channelID)
 		if chain == nil {
@@ -196,15 +205,13 @@ func (s *server) Deliver(srv ab.AtomicBroadcast_DeliverServer) error {
 	deliverServer := &deliver.Server{
 		PolicyChecker: deliver.PolicyCheckerFunc(policyChecker),
 		Receiver: &deliverMsgTracer{
-			Receiver: srv,
+			Receiver: receiver,
 			msgTracer: msgTracer{
 				debug:    s.debug,
-				function: "Deliver",
+				function: function,
 			},
 		},
-		ResponseSender: &responseSender{
-			AtomicBroadcast_DeliverServer: srv,
-		},
+		ResponseSender: sender,
 	}
-	return s.dh.Handle(srv.Context(), deliverServer)
+	return s.dh.Handle(ctx, deliverServer)
 }
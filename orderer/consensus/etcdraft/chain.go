@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
 	"github.com/osdi23p228/fabric/bccsp"
 	"github.com/osdi23p228/fabric/common/flogging"
+	"github.com/osdi23p228/fabric/common/util"
 	"github.com/osdi23p228/fabric/orderer/common/cluster"
 	"github.com/osdi23p228/fabric/orderer/consensus"
 	"github.com/osdi23p228/fabric/protoutil"
@@ -127,6 +128,13 @@ type Options struct {
 
 	EvictionSuspicion   time.Duration
 	LeaderCheckInterval time.Duration
+
+	// BlockSignatureThreshold is the number of consenter signatures, including the
+	// signature of this node, a block is written with. Values below 2 disable co-signing.
+	BlockSignatureThreshold int
+	// BlockSignatureTimeout is the duration to wait for the signatures of other
+	// consenters before writing a block with fewer signatures than the threshold.
+	BlockSignatureTimeout time.Duration
 }
 
 type submit struct {
@@ -177,6 +185,12 @@ type Chain struct {
 	lastBlock    *common.Block
 	appliedIndex uint64
 
+	// needed by block co-signing
+	lastConfigBlockNum uint64
+	replayIndex        uint64 // last raft index in storage on start, entries up to it are replayed
+	blockSignatures    *BlockSignatureCollector
+	cosignC            chan *cosignedBlock
+
 	// needed by snapshotting
 	sizeLimit        uint32 // SnapshotIntervalSize in bytes
 	accDataSize      uint32 // accumulative data size since last snapshot
@@ -246,30 +260,47 @@ func NewChain(
 		return nil, errors.Errorf("failed to get last block")
 	}
 
+	replayIndex, err := storage.ram.LastIndex()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get last index from raft storage")
+	}
+
+	var lastConfigBlockNum uint64
+	if b.Header.Number > 0 {
+		lastConfigBlockNum, err = protoutil.GetLastConfigIndexFromBlock(b)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get last config index from last block")
+		}
+	}
+
 	c := &Chain{
-		configurator:     conf,
-		rpc:              rpc,
-		channelID:        support.ChannelID(),
-		raftID:           opts.RaftID,
-		submitC:          make(chan *submit),
-		applyC:           make(chan apply),
-		haltC:            make(chan struct{}),
-		doneC:            make(chan struct{}),
-		startC:           make(chan struct{}),
-		snapC:            make(chan *raftpb.Snapshot),
-		errorC:           make(chan struct{}),
-		gcC:              make(chan *gc),
-		observeC:         observeC,
-		support:          support,
-		fresh:            fresh,
-		appliedIndex:     opts.BlockMetadata.RaftIndex,
-		lastBlock:        b,
-		sizeLimit:        sizeLimit,
-		lastSnapBlockNum: snapBlkNum,
-		confState:        cc,
-		createPuller:     f,
-		clock:            opts.Clock,
-		haltCallback:     haltCallback,
+		configurator:       conf,
+		rpc:                rpc,
+		channelID:          support.ChannelID(),
+		raftID:             opts.RaftID,
+		submitC:            make(chan *submit),
+		applyC:             make(chan apply),
+		haltC:              make(chan struct{}),
+		doneC:              make(chan struct{}),
+		startC:             make(chan struct{}),
+		snapC:              make(chan *raftpb.Snapshot),
+		errorC:             make(chan struct{}),
+		gcC:                make(chan *gc),
+		observeC:           observeC,
+		support:            support,
+		fresh:              fresh,
+		appliedIndex:       opts.BlockMetadata.RaftIndex,
+		lastBlock:          b,
+		lastConfigBlockNum: lastConfigBlockNum,
+		replayIndex:        replayIndex,
+		blockSignatures:    NewBlockSignatureCollector(b.Header.Number+1, NewBlockSignatureVerifier(support), lg),
+		cosignC:            make(chan *cosignedBlock, blockSignatureWindow),
+		sizeLimit:          sizeLimit,
+		lastSnapBlockNum:   snapBlkNum,
+		confState:          cc,
+		createPuller:       f,
+		clock:              opts.Clock,
+		haltCallback:       haltCallback,
 		Metrics: &Metrics{
 			ClusterSize:             opts.Metrics.ClusterSize.With("channel", support.ChannelID()),
 			IsLeader:                opts.Metrics.IsLeader.With("channel", support.ChannelID()),
@@ -278,6 +309,7 @@ func NewChain(
 			SnapshotBlockNumber:     opts.Metrics.SnapshotBlockNumber.With("channel", support.ChannelID()),
 			LeaderChanges:           opts.Metrics.LeaderChanges.With("channel", support.ChannelID()),
 			ProposalFailures:        opts.Metrics.ProposalFailures.With("channel", support.ChannelID()),
+			ShortSignedBlocks:       opts.Metrics.ShortSignedBlocks.With("channel", support.ChannelID()),
 			DataPersistDuration:     opts.Metrics.DataPersistDuration.With("channel", support.ChannelID()),
 			NormalProposalsReceived: opts.Metrics.NormalProposalsReceived.With("channel", support.ChannelID()),
 			ConfigProposalsReceived: opts.Metrics.ConfigProposalsReceived.With("channel", support.ChannelID()),
@@ -361,6 +393,10 @@ func (c *Chain) Start() {
 	go c.gc()
 	go c.run()
 
+	if c.opts.BlockSignatureThreshold > 1 {
+		go c.cosign()
+	}
+
 	es := c.newEvictionSuspector()
 
 	interval := DefaultLeaderlessCheckInterval
@@ -458,6 +494,12 @@ func (c *Chain) Consensus(req *orderer.ConsensusRequest, sender uint64) error {
 		return err
 	}
 
+	// Raft messages are never empty, a request without payload carries
+	// the signature of the sender over a block it committed.
+	if len(req.Payload) == 0 {
+		return c.blockSignatures.Add(sender, req.Metadata)
+	}
+
 	stepMsg := &raftpb.Message{}
 	if err := proto.Unmarshal(req.Payload, stepMsg); err != nil {
 		return fmt.Errorf("failed to unmarshal StepRequest payload to Raft Message: %s", err)
@@ -814,7 +856,7 @@ func (c *Chain) writeBlock(block *common.Block, index uint64) {
 	m := protoutil.MarshalOrPanic(c.opts.BlockMetadata)
 	c.raftMetadataLock.Unlock()
 
-	c.support.WriteBlock(block, m)
+	c.writeToLedger(block, m, index)
 }
 
 // Orders the envelope in the `msg` content. SubmitRequest.
@@ -889,11 +931,15 @@ func (c *Chain) catchUp(snap *raftpb.Snapshot) error {
 		return nil
 	} else if b.Header.Number == c.lastBlock.Header.Number+1 {
 		c.logger.Infof("The only missing block [%d] is encapsulated in snapshot, committing it to shortcut catchup process", b.Header.Number)
+		c.flushCosignedBlocks()
 		c.commitBlock(b)
 		c.lastBlock = b
 		return nil
 	}
 
+	// blocks pulled from the cluster already carry the signatures of the orderer which wrote them
+	c.flushCosignedBlocks()
+
 	puller, err := c.createPuller()
 	if err != nil {
 		return errors.Errorf("failed to create block puller: %s", err)
@@ -926,6 +972,12 @@ func (c *Chain) commitBlock(block *common.Block) {
 
 	c.support.WriteConfigBlock(block, nil)
 
+	// keep the last config index co-signed with the following blocks in line with the one
+	// recorded by the block writer, which only config transactions of the channel update
+	if hdr, err := ConfigChannelHeader(block); err == nil && hdr.Type == int32(common.HeaderType_CONFIG) {
+		c.lastConfigBlockNum = block.Header.Number
+	}
+
 	configMembership := c.detectConfChange(block)
 
 	if configMembership != nil && configMembership.Changed() {
@@ -1180,7 +1232,8 @@ func (c *Chain) writeConfigBlock(block *common.Block, index uint64) {
 		blockMetadataBytes := protoutil.MarshalOrPanic(c.opts.BlockMetadata)
 
 		// write block with metadata
-		c.support.WriteConfigBlock(block, blockMetadataBytes)
+		c.lastConfigBlockNum = block.Header.Number
+		c.writeToLedger(block, blockMetadataBytes, index)
 
 		if configMembership == nil {
 			return
@@ -1235,13 +1288,213 @@ func (c *Chain) writeConfigBlock(block *common.Block, index uint64) {
 		m := protoutil.MarshalOrPanic(c.opts.BlockMetadata)
 		c.raftMetadataLock.Unlock()
 
-		c.support.WriteConfigBlock(block, m)
+		c.writeToLedger(block, m, index)
 
 	default:
 		c.logger.Panicf("Programming error: unexpected config type: %s", common.HeaderType(hdr.Type))
 	}
 }
 
+// writeToLedger writes the block with the given consenter metadata. When co-signing is enabled,
+// the block is queued to be written by the cosign goroutine, and config blocks are waited for
+// so that the new configuration is in effect when this returns.
+func (c *Chain) writeToLedger(block *common.Block, consenterMetadata []byte, index uint64) {
+	config := protoutil.IsConfigBlock(block)
+
+	if c.opts.BlockSignatureThreshold < 2 {
+		if config {
+			c.support.WriteConfigBlock(block, consenterMetadata)
+		} else {
+			c.support.WriteBlock(block, consenterMetadata)
+		}
+		return
+	}
+
+	timeout := c.opts.BlockSignatureTimeout
+	if timeout == 0 {
+		timeout = DefaultBlockSignatureTimeout
+	}
+
+	cb := &cosignedBlock{
+		block:    block,
+		metadata: consenterMetadata,
+		value: protoutil.MarshalOrPanic(&common.OrdererBlockMetadata{
+			LastConfig:        &common.LastConfig{Index: c.lastConfigBlockNum},
+			ConsenterMetadata: consenterMetadata,
+		}),
+		config: config,
+		// the other consenters have already written the blocks replayed from the raft log
+		catchUp:  index <= c.replayIndex,
+		deadline: c.clock.Now().Add(timeout),
+	}
+
+	c.raftMetadataLock.RLock()
+	for id := range c.opts.Consenters {
+		if id != c.raftID {
+			cb.consenters = append(cb.consenters, id)
+		}
+	}
+	c.raftMetadataLock.RUnlock()
+
+	if config {
+		cb.done = make(chan struct{})
+	}
+
+	select {
+	case c.cosignC <- cb:
+	case <-c.doneC:
+		return
+	}
+
+	if cb.done != nil {
+		select {
+		case <-cb.done:
+		case <-c.doneC:
+		}
+	}
+}
+
+// flushCosignedBlocks waits until the blocks queued to be co-signed are written.
+func (c *Chain) flushCosignedBlocks() {
+	if c.opts.BlockSignatureThreshold < 2 {
+		return
+	}
+
+	done := make(chan struct{})
+	select {
+	case c.cosignC <- &cosignedBlock{done: done}:
+	case <-c.doneC:
+		return
+	}
+
+	select {
+	case <-done:
+	case <-c.doneC:
+	}
+}
+
+// cosign writes the blocks queued by the apply loop in order. It sends the signature of this node over
+// each queued block to the other consenters, and writes the block once the signatures of
+// BlockSignatureThreshold-1 other consenters are collected, once the consenters which did not sign it yet
+// cannot make up the threshold anymore, or once BlockSignatureTimeout expired since it was queued.
+// Blocks replayed from the raft log are written without waiting. The blocks still queued when the chain
+// halts are not written, and as the raft index of the last written block is recorded in its metadata, they
+// are applied again once the chain restarts.
+func (c *Chain) cosign() {
+	identity, err := c.support.Serialize()
+	if err != nil {
+		c.logger.Panicf("Failed serializing signing identity: %s", err)
+	}
+
+	var queue []*cosignedBlock
+	for {
+		var cosignC <-chan *cosignedBlock
+		if len(queue) < blockSignatureWindow {
+			cosignC = c.cosignC
+		}
+
+		if len(queue) == 0 {
+			select {
+			case cb := <-cosignC:
+				queue = append(queue, c.sendBlockSignature(cb))
+			case <-c.doneC:
+				return
+			}
+			continue
+		}
+
+		updated := c.blockSignatures.Updated()
+		if c.writeCosignedBlock(queue[0], identity) {
+			queue = queue[1:]
+			continue
+		}
+
+		timer := c.clock.NewTimer(queue[0].deadline.Sub(c.clock.Now()))
+		select {
+		case cb := <-cosignC:
+			queue = append(queue, c.sendBlockSignature(cb))
+		case <-updated:
+		case <-timer.C():
+		case <-c.doneC:
+			timer.Stop()
+			return
+		}
+		timer.Stop()
+	}
+}
+
+// sendBlockSignature sends the signature of this node over the block to the other consenters.
+func (c *Chain) sendBlockSignature(cb *cosignedBlock) *cosignedBlock {
+	if cb.block == nil || cb.catchUp {
+		return cb
+	}
+
+	signatureHeader, err := protoutil.NewSignatureHeader(c.support)
+	if err != nil {
+		c.logger.Panicf("Failed creating signature header for block [%d]: %s", cb.block.Header.Number, err)
+	}
+	signature := &common.MetadataSignature{
+		SignatureHeader: protoutil.MarshalOrPanic(signatureHeader),
+	}
+	signature.Signature, err = c.support.Sign(util.ConcatenateBytes(cb.value, signature.SignatureHeader, protoutil.BlockHeaderBytes(cb.block.Header)))
+	if err != nil {
+		c.logger.Panicf("Failed signing block [%d]: %s", cb.block.Header.Number, err)
+	}
+
+	req := &orderer.ConsensusRequest{
+		Channel:  c.channelID,
+		Metadata: EncodeBlockSignature(cb.block.Header, cb.value, signature),
+	}
+	for _, dest := range cb.consenters {
+		if err := c.rpc.SendConsensus(dest, req); err != nil {
+			c.logger.Debugf("Failed sending signature over block [%d] to %d: %s", cb.block.Header.Number, dest, err)
+		}
+	}
+
+	return cb
+}
+
+// writeCosignedBlock writes the block with the signatures of the other consenters collected so far,
+// unless more signatures are still expected. It returns whether the block was written.
+//
+// A block written with fewer signatures than the threshold is counted by the short_signed_blocks metric.
+// Peers whose BlockValidation policy requires the threshold reject it from this orderer, and pull it
+// from another consenter which collected enough signatures.
+func (c *Chain) writeCosignedBlock(cb *cosignedBlock, identity []byte) bool {
+	if cb.block == nil {
+		close(cb.done)
+		return true
+	}
+
+	threshold := c.opts.BlockSignatureThreshold - 1
+	signatures, candidates := c.blockSignatures.Signatures(cb.block.Header, cb.value, identity, cb.consenters)
+	if len(signatures) < threshold && len(signatures)+candidates >= threshold && !cb.catchUp && c.clock.Now().Before(cb.deadline) {
+		return false
+	}
+
+	if len(signatures) < threshold {
+		c.logger.Warningf("Writing block [%d] with %d out of %d required signatures", cb.block.Header.Number, len(signatures)+1, c.opts.BlockSignatureThreshold)
+		c.Metrics.ShortSignedBlocks.Add(1)
+	}
+
+	cb.block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&common.Metadata{
+		Value:      cb.value,
+		Signatures: signatures,
+	})
+	c.blockSignatures.Discard(cb.block.Header.Number)
+
+	if cb.config {
+		c.support.WriteConfigBlock(cb.block, cb.metadata)
+	} else {
+		c.support.WriteBlock(cb.block, cb.metadata)
+	}
+
+	if cb.done != nil {
+		close(cb.done)
+	}
+	return true
+}
+
 // getInFlightConfChange returns ConfChange in-flight if any.
 // It returns confChangeInProgress if it is not nil. Otherwise
 // it returns ConfChange from the last committed block (might be nil).
@@ -363,6 +363,29 @@ var _ = Describe("Chain", func() {
 				Expect(fakeFields.fakeDataPersistDuration.ObserveArgsForCall(3)).Should(Equal(float64(0)))
 			})
 
+			Context("when blocks are co-signed", func() {
+				BeforeEach(func() {
+					opts.BlockSignatureThreshold = 2
+					opts.BlockSignatureTimeout = time.Hour
+				})
+
+				It("does not wait for signatures no other consenter can provide", func() {
+					close(cutter.Block)
+					cutter.CutNext = true
+
+					err := chain.Order(env, 0)
+					Expect(err).NotTo(HaveOccurred())
+					Eventually(support.WriteBlockCallCount, LongEventualTimeout).Should(Equal(1))
+					Expect(fakeFields.fakeShortSignedBlocks.AddCallCount()).To(Equal(1))
+
+					block, _ := support.WriteBlockArgsForCall(0)
+					md := &common.Metadata{}
+					Expect(proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], md)).To(Succeed())
+					Expect(md.Signatures).To(BeEmpty())
+					Expect(md.Value).NotTo(BeEmpty())
+				})
+			})
+
 			It("does not reset timer for every envelope", func() {
 				close(cutter.Block)
 
@@ -959,6 +982,59 @@ var _ = Describe("Chain", func() {
 							Eventually(c.support.WriteBlockCallCount, LongEventualTimeout).Should(Equal(2))
 						})
 
+						It("co-signs blocks with the last config pulled during catchup", func() {
+							Expect(chain.Order(env, uint64(0))).To(Succeed())
+							Eventually(support.WriteBlockCallCount, LongEventualTimeout).Should(Equal(1))
+							Expect(chain.Order(env, uint64(0))).To(Succeed())
+							Eventually(support.WriteBlockCallCount, LongEventualTimeout).Should(Equal(2))
+							Eventually(countFiles, LongEventualTimeout).Should(Equal(2))
+
+							chain.Halt()
+
+							// the cluster wrote a config transaction in block [1]
+							configBlock := protoutil.NewBlock(1, nil)
+							configBlock.Data.Data = [][]byte{marshalOrPanic(newConfigEnv(channelID,
+								common.HeaderType_CONFIG,
+								newConfigUpdateEnv(channelID, nil, map[string]*common.ConfigValue{
+									"BatchTimeout": {
+										Version: 1,
+										Value:   marshalOrPanic(&orderer.BatchTimeout{Timeout: "3ms"}),
+									},
+								}),
+							))}
+
+							c := newChain(10*time.Second, channelID, dataDir, 1, raftMetadata, consenters, cryptoProvider, nil)
+							c.opts.BlockSignatureThreshold = 2
+							c.opts.BlockSignatureTimeout = time.Hour
+							c.init()
+							c.puller.PullBlockStub = func(i uint64) *common.Block {
+								if i == 1 {
+									return configBlock
+								}
+								ledgerLock.Lock()
+								defer ledgerLock.Unlock()
+								return ledger[i]
+							}
+
+							c.Start()
+							defer c.Halt()
+
+							Eventually(c.support.WriteConfigBlockCallCount, LongEventualTimeout).Should(Equal(1))
+							Eventually(c.support.WriteBlockCallCount, LongEventualTimeout).Should(Equal(1))
+
+							campaign(c.Chain, c.observe)
+							c.cutter.CutNext = true
+							Expect(c.Order(env, uint64(0))).To(Succeed())
+							Eventually(c.support.WriteBlockCallCount, LongEventualTimeout).Should(Equal(2))
+
+							block, _ := c.support.WriteBlockArgsForCall(1)
+							md := &common.Metadata{}
+							Expect(proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], md)).To(Succeed())
+							obm := &common.OrdererBlockMetadata{}
+							Expect(proto.Unmarshal(md.Value, obm)).To(Succeed())
+							Expect(obm.LastConfig.Index).To(Equal(uint64(1)))
+						})
+
 						It("commits block from snapshot if it's missing from ledger", func() {
 							// Scenario:
 							// Single node exists right after a snapshot is taken, while the block
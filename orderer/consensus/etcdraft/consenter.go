@@ -57,6 +57,9 @@ type Config struct {
 	SnapDir              string // Snapshots of <my-channel> are stored in SnapDir/<my-channel>
 	EvictionSuspicion    string // Duration threshold that the node samples in order to suspect its eviction from the channel.
 	TickIntervalOverride string // Duration to use for tick interval instead of what is specified in the channel config.
+
+	BlockSignatureThreshold int    // Number of consenter signatures each block is written with, co-signing is disabled below 2.
+	BlockSignatureTimeout   string // Duration to wait for the signatures of other consenters over a block.
 }
 
 // Consenter implements etcdraft consenter
@@ -196,6 +199,14 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 		c.Logger.Infof("TickIntervalOverride is set, overriding channel configuration tick interval to %v", tickInterval)
 	}
 
+	blockSignatureTimeout := DefaultBlockSignatureTimeout
+	if c.EtcdRaftConfig.BlockSignatureTimeout != "" {
+		blockSignatureTimeout, err = time.ParseDuration(c.EtcdRaftConfig.BlockSignatureTimeout)
+		if err != nil {
+			return nil, errors.Errorf("failed parsing Consensus.BlockSignatureTimeout: %s: %v", c.EtcdRaftConfig.BlockSignatureTimeout, err)
+		}
+	}
+
 	opts := Options{
 		RaftID:        id,
 		Clock:         clock.NewClock(),
@@ -219,6 +230,9 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 		EvictionSuspicion: evictionSuspicion,
 		Cert:              c.Cert,
 		Metrics:           c.Metrics,
+
+		BlockSignatureThreshold: c.EtcdRaftConfig.BlockSignatureThreshold,
+		BlockSignatureTimeout:   blockSignatureTimeout,
 	}
 
 	rpc := &cluster.RPC{
@@ -400,6 +400,39 @@ var _ = Describe("Consenter", func() {
 		})
 	})
 
+	When("the BlockSignatureTimeout is invalid", func() {
+		It("returns an error", func() {
+			m := &etcdraftproto.ConfigMetadata{
+				Consenters: []*etcdraftproto.Consenter{
+					{ServerTlsCert: certAsPEM},
+				},
+				Options: &etcdraftproto.Options{
+					TickInterval:      "500ms",
+					ElectionTick:      10,
+					HeartbeatTick:     1,
+					MaxInflightBlocks: 5,
+				},
+			}
+			metadata := protoutil.MarshalOrPanic(m)
+			mockOrderer := &mocks.OrdererConfig{}
+			mockOrderer.ConsensusMetadataReturns(metadata)
+			mockOrderer.BatchSizeReturns(
+				&orderer.BatchSize{
+					PreferredMaxBytes: 2 * 1024 * 1024,
+				},
+			)
+			mockOrderer.CapabilitiesReturns(&mocks.OrdererCapabilities{})
+			support.SharedConfigReturns(mockOrderer)
+
+			consenter := newConsenter(chainGetter, tlsCA.CertBytes(), certAsPEM)
+			consenter.EtcdRaftConfig.BlockSignatureThreshold = 2
+			consenter.EtcdRaftConfig.BlockSignatureTimeout = "seven"
+
+			_, err := consenter.HandleChain(support, nil)
+			Expect(err).To(MatchError(ContainSubstring("failed parsing Consensus.BlockSignatureTimeout: seven")))
+		})
+	})
+
 	It("constructs a follower chain if no matching cert found", func() {
 		m := &etcdraftproto.ConfigMetadata{
 			Consenters: []*etcdraftproto.Consenter{
@@ -0,0 +1,252 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/osdi23p228/fabric/common/flogging"
+	"github.com/osdi23p228/fabric/common/util"
+	"github.com/osdi23p228/fabric/orderer/consensus"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// DefaultBlockSignatureTimeout is the default duration a consenter waits for the
+// signatures of other consenters over a committed block, before writing it anyway.
+const DefaultBlockSignatureTimeout = time.Second
+
+// blockSignatureWindow bounds the number of blocks ahead of the last written block
+// for which signatures of other consenters are buffered, as well as the number of
+// blocks waiting for the signatures of other consenters before being written.
+const blockSignatureWindow = 100
+
+// BlockSignatureVerifier verifies the signature of a consenter over a block header and
+// metadata value, and returns the serialized identity which produced it.
+type BlockSignatureVerifier func(header *common.BlockHeader, value []byte, signature *common.MetadataSignature) ([]byte, error)
+
+// blockSignature is a signature a consenter produced over a committed block.
+type blockSignature struct {
+	headerHash []byte
+	value      []byte
+	identity   []byte
+	signature  *common.MetadataSignature
+}
+
+// BlockSignatureCollector buffers the signatures the other consenters of a channel send over
+// committed blocks, so that each block can be written with a threshold of orderer signatures.
+type BlockSignatureCollector struct {
+	Logger *flogging.FabricLogger
+	Verify BlockSignatureVerifier
+
+	lock       sync.Mutex
+	signatures map[uint64]map[uint64]*blockSignature // block number -> sender -> signature
+	latest     map[uint64]uint64                     // sender -> highest block number signed
+	lowest     uint64                                // signatures of blocks below are discarded
+	updateC    chan struct{}
+}
+
+// NewBlockSignatureCollector creates a collector which accepts signatures of blocks starting from the given number.
+func NewBlockSignatureCollector(lowest uint64, verify BlockSignatureVerifier, logger *flogging.FabricLogger) *BlockSignatureCollector {
+	return &BlockSignatureCollector{
+		Logger:     logger,
+		Verify:     verify,
+		signatures: map[uint64]map[uint64]*blockSignature{},
+		latest:     map[uint64]uint64{},
+		lowest:     lowest,
+		updateC:    make(chan struct{}),
+	}
+}
+
+// EncodeBlockSignature encodes a signature over a block header and the given metadata value, as
+// sent to the other consenters. The encoding is a block with the header of the signed block, and the
+// signature in the SIGNATURES metadata.
+func EncodeBlockSignature(header *common.BlockHeader, value []byte, signature *common.MetadataSignature) []byte {
+	block := &common.Block{Header: header}
+	protoutil.InitBlockMetadata(block)
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&common.Metadata{
+		Value:      value,
+		Signatures: []*common.MetadataSignature{signature},
+	})
+	return protoutil.MarshalOrPanic(block)
+}
+
+// Add verifies and records the signature over a block sent by the given consenter.
+func (bsc *BlockSignatureCollector) Add(sender uint64, encoded []byte) error {
+	block := &common.Block{}
+	if err := proto.Unmarshal(encoded, block); err != nil {
+		return errors.Wrap(err, "failed to unmarshal block signature")
+	}
+	if block.Header == nil {
+		return errors.New("block signature is missing block header")
+	}
+	md, err := protoutil.GetMetadataFromBlock(block, common.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return errors.WithMessage(err, "failed to extract block signature")
+	}
+	if len(md.Signatures) != 1 {
+		return errors.Errorf("expected exactly one signature, got %d", len(md.Signatures))
+	}
+
+	number := block.Header.Number
+	identity, err := bsc.Verify(block.Header, md.Value, md.Signatures[0])
+	if err != nil {
+		return errors.WithMessagef(err, "invalid signature of consenter %d over block [%d]", sender, number)
+	}
+
+	bsc.lock.Lock()
+	defer bsc.lock.Unlock()
+
+	if number > bsc.latest[sender] {
+		bsc.latest[sender] = number
+	}
+	defer bsc.notify()
+
+	if number < bsc.lowest || number >= bsc.lowest+blockSignatureWindow {
+		bsc.Logger.Debugf("Discarding signature of consenter %d over block [%d], outside of window [%d, %d)",
+			sender, number, bsc.lowest, bsc.lowest+blockSignatureWindow)
+		return nil
+	}
+
+	if _, exists := bsc.signatures[number]; !exists {
+		bsc.signatures[number] = map[uint64]*blockSignature{}
+	}
+	bsc.signatures[number][sender] = &blockSignature{
+		headerHash: protoutil.BlockHeaderHash(block.Header),
+		value:      md.Value,
+		identity:   identity,
+		signature:  md.Signatures[0],
+	}
+
+	return nil
+}
+
+// Updated returns a channel which is closed once a signature is added.
+func (bsc *BlockSignatureCollector) Updated() <-chan struct{} {
+	bsc.lock.Lock()
+	defer bsc.lock.Unlock()
+
+	return bsc.updateC
+}
+
+// Signatures returns the signatures collected so far over the given block and metadata value,
+// excluding the ones produced by the given identity and counting each identity once. It also
+// returns the number of the given consenters which can still sign the block, i.e. which did not
+// sign it nor any later block.
+func (bsc *BlockSignatureCollector) Signatures(header *common.BlockHeader, value []byte, self []byte, consenters []uint64) ([]*common.MetadataSignature, int) {
+	headerHash := protoutil.BlockHeaderHash(header)
+
+	bsc.lock.Lock()
+	defer bsc.lock.Unlock()
+
+	var matching []*common.MetadataSignature
+	identities := [][]byte{self}
+	for sender, bs := range bsc.signatures[header.Number] {
+		if !bytes.Equal(bs.headerHash, headerHash) || !bytes.Equal(bs.value, value) {
+			bsc.Logger.Debugf("Consenter %d signed block [%d] with a different header or metadata, ignoring its signature", sender, header.Number)
+			continue
+		}
+		if containsIdentity(identities, bs.identity) {
+			bsc.Logger.Debugf("Consenter %d signed block [%d] with an identity which already signed it, ignoring its signature", sender, header.Number)
+			continue
+		}
+		identities = append(identities, bs.identity)
+		matching = append(matching, bs.signature)
+	}
+
+	var candidates int
+	for _, consenter := range consenters {
+		if _, signed := bsc.signatures[header.Number][consenter]; signed || bsc.latest[consenter] > header.Number {
+			continue
+		}
+		candidates++
+	}
+
+	return matching, candidates
+}
+
+// Discard discards the signatures of all blocks up to and including the given one.
+func (bsc *BlockSignatureCollector) Discard(number uint64) {
+	bsc.lock.Lock()
+	defer bsc.lock.Unlock()
+
+	for n := range bsc.signatures {
+		if n <= number {
+			delete(bsc.signatures, n)
+		}
+	}
+	if number+1 > bsc.lowest {
+		bsc.lowest = number + 1
+	}
+}
+
+// notify wakes up the goroutines waiting for signatures. Must be called with the lock held.
+func (bsc *BlockSignatureCollector) notify() {
+	close(bsc.updateC)
+	bsc.updateC = make(chan struct{})
+}
+
+func containsIdentity(identities [][]byte, identity []byte) bool {
+	for _, id := range identities {
+		if bytes.Equal(id, identity) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewBlockSignatureVerifier returns a BlockSignatureVerifier which accepts signatures produced by a valid
+// identity of one of the orderer organizations of the channel.
+func NewBlockSignatureVerifier(support consensus.ConsenterSupport) BlockSignatureVerifier {
+	return func(header *common.BlockHeader, value []byte, signature *common.MetadataSignature) ([]byte, error) {
+		signatureHeader, err := protoutil.UnmarshalSignatureHeader(signature.GetSignatureHeader())
+		if err != nil {
+			return nil, err
+		}
+		serializedIdentity, err := protoutil.UnmarshalSerializedIdentity(signatureHeader.Creator)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, org := range support.SharedConfig().Organizations() {
+			if org.MSPID() != serializedIdentity.Mspid {
+				continue
+			}
+			identity, err := org.MSP().DeserializeIdentity(signatureHeader.Creator)
+			if err != nil {
+				return nil, errors.WithMessage(err, "failed deserializing signer identity")
+			}
+			if err := identity.Validate(); err != nil {
+				return nil, errors.WithMessage(err, "signer identity is not valid")
+			}
+			if err := identity.Verify(util.ConcatenateBytes(value, signature.SignatureHeader, protoutil.BlockHeaderBytes(header)), signature.Signature); err != nil {
+				return nil, errors.WithMessage(err, "signature verification failed")
+			}
+			return signatureHeader.Creator, nil
+		}
+
+		return nil, errors.Errorf("signer is not a member of an orderer organization, MSP ID: %s", serializedIdentity.Mspid)
+	}
+}
+
+// cosignedBlock is a block waiting for the signatures of other consenters before being written.
+type cosignedBlock struct {
+	block      *common.Block
+	metadata   []byte
+	value      []byte
+	config     bool
+	catchUp    bool
+	consenters []uint64
+	deadline   time.Time
+	// done is closed once the block is written, and is only set for config blocks and
+	// for flushes, in which case the block is nil
+	done chan struct{}
+}
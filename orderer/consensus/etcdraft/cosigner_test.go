@@ -0,0 +1,191 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/osdi23p228/fabric/common/channelconfig"
+	"github.com/osdi23p228/fabric/common/flogging"
+	policymocks "github.com/osdi23p228/fabric/common/policies/mocks"
+	"github.com/osdi23p228/fabric/orderer/consensus/etcdraft"
+	"github.com/osdi23p228/fabric/orderer/consensus/etcdraft/mocks"
+	consensusmocks "github.com/osdi23p228/fabric/orderer/consensus/mocks"
+	"github.com/osdi23p228/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockSignatureCollector(t *testing.T) {
+	logger := flogging.MustGetLogger("test")
+	header := &common.BlockHeader{Number: 5, DataHash: []byte{1, 2, 3}}
+	value := []byte("value")
+
+	signature := func(sig string) *common.MetadataSignature {
+		return &common.MetadataSignature{SignatureHeader: []byte("header"), Signature: []byte(sig)}
+	}
+	// the identity of a signature is its content, and signatures from 'bad' are invalid
+	verify := func(header *common.BlockHeader, value []byte, signature *common.MetadataSignature) ([]byte, error) {
+		if string(signature.Signature) == "bad" {
+			return nil, errors.New("signature verification failed")
+		}
+		return signature.Signature, nil
+	}
+
+	t.Run("Threshold reached", func(t *testing.T) {
+		bsc := etcdraft.NewBlockSignatureCollector(5, verify, logger)
+		updated := bsc.Updated()
+		require.NoError(t, bsc.Add(2, etcdraft.EncodeBlockSignature(header, value, signature("2"))))
+		assert.True(t, isClosed(updated))
+		require.NoError(t, bsc.Add(3, etcdraft.EncodeBlockSignature(header, value, signature("3"))))
+
+		signatures, candidates := bsc.Signatures(header, value, []byte("1"), []uint64{2, 3, 4})
+		assert.ElementsMatch(t, []*common.MetadataSignature{signature("2"), signature("3")}, signatures)
+		assert.Equal(t, 1, candidates)
+
+		bsc.Discard(5)
+		signatures, _ = bsc.Signatures(header, value, []byte("1"), []uint64{2, 3, 4})
+		assert.Empty(t, signatures)
+		require.NoError(t, bsc.Add(2, etcdraft.EncodeBlockSignature(header, value, signature("2"))))
+		signatures, _ = bsc.Signatures(header, value, []byte("1"), []uint64{2, 3, 4})
+		assert.Empty(t, signatures, "signatures of written blocks are discarded")
+	})
+
+	t.Run("Consenters past the block", func(t *testing.T) {
+		bsc := etcdraft.NewBlockSignatureCollector(5, verify, logger)
+		require.NoError(t, bsc.Add(2, etcdraft.EncodeBlockSignature(&common.BlockHeader{Number: 6}, value, signature("2"))))
+		require.NoError(t, bsc.Add(3, etcdraft.EncodeBlockSignature(&common.BlockHeader{Number: 500}, value, signature("3"))))
+
+		signatures, candidates := bsc.Signatures(header, value, []byte("1"), []uint64{2, 3, 4})
+		assert.Empty(t, signatures)
+		assert.Equal(t, 1, candidates, "only consenter 4 can still sign the block")
+	})
+
+	t.Run("Invalid signatures rejected", func(t *testing.T) {
+		bsc := etcdraft.NewBlockSignatureCollector(5, verify, logger)
+		err := bsc.Add(2, etcdraft.EncodeBlockSignature(header, value, signature("bad")))
+		assert.EqualError(t, err, "invalid signature of consenter 2 over block [5]: signature verification failed")
+
+		signatures, candidates := bsc.Signatures(header, value, []byte("1"), []uint64{2})
+		assert.Empty(t, signatures)
+		assert.Equal(t, 1, candidates)
+	})
+
+	t.Run("Identities counted once", func(t *testing.T) {
+		bsc := etcdraft.NewBlockSignatureCollector(5, verify, logger)
+		require.NoError(t, bsc.Add(2, etcdraft.EncodeBlockSignature(header, value, signature("1"))))
+		require.NoError(t, bsc.Add(3, etcdraft.EncodeBlockSignature(header, value, signature("3"))))
+		require.NoError(t, bsc.Add(4, etcdraft.EncodeBlockSignature(header, value, signature("3"))))
+
+		signatures, candidates := bsc.Signatures(header, value, []byte("1"), []uint64{2, 3, 4})
+		assert.Equal(t, []*common.MetadataSignature{signature("3")}, signatures)
+		assert.Equal(t, 0, candidates)
+	})
+
+	t.Run("Mismatching signatures ignored", func(t *testing.T) {
+		bsc := etcdraft.NewBlockSignatureCollector(5, verify, logger)
+		otherHeader := &common.BlockHeader{Number: 5, DataHash: []byte{4, 5, 6}}
+		require.NoError(t, bsc.Add(2, etcdraft.EncodeBlockSignature(otherHeader, value, signature("2"))))
+		require.NoError(t, bsc.Add(3, etcdraft.EncodeBlockSignature(header, []byte("other value"), signature("3"))))
+		require.NoError(t, bsc.Add(4, etcdraft.EncodeBlockSignature(header, value, signature("4"))))
+
+		signatures, _ := bsc.Signatures(header, value, []byte("1"), []uint64{2, 3, 4})
+		assert.Equal(t, []*common.MetadataSignature{signature("4")}, signatures)
+	})
+
+	t.Run("Signatures outside of window discarded", func(t *testing.T) {
+		bsc := etcdraft.NewBlockSignatureCollector(5, verify, logger)
+		require.NoError(t, bsc.Add(2, etcdraft.EncodeBlockSignature(&common.BlockHeader{Number: 4}, value, signature("2"))))
+		require.NoError(t, bsc.Add(3, etcdraft.EncodeBlockSignature(&common.BlockHeader{Number: 105}, value, signature("3"))))
+		require.NoError(t, bsc.Add(4, etcdraft.EncodeBlockSignature(&common.BlockHeader{Number: 104}, value, signature("4"))))
+
+		for number, expected := range map[uint64]int{4: 0, 104: 1, 105: 0} {
+			signatures, _ := bsc.Signatures(&common.BlockHeader{Number: number}, value, []byte("1"), []uint64{2, 3, 4})
+			assert.Len(t, signatures, expected, "block [%d]", number)
+		}
+	})
+
+	t.Run("Malformed signature", func(t *testing.T) {
+		bsc := etcdraft.NewBlockSignatureCollector(5, verify, logger)
+		assert.Contains(t, bsc.Add(2, []byte{1, 2, 3}).Error(), "failed to unmarshal block signature")
+		assert.EqualError(t, bsc.Add(2, etcdraft.EncodeBlockSignature(nil, value, signature("2"))), "block signature is missing block header")
+	})
+}
+
+func TestBlockSignatureVerifier(t *testing.T) {
+	header := &common.BlockHeader{Number: 5, DataHash: []byte{1, 2, 3}}
+	value := []byte("value")
+	creator := protoutil.MarshalOrPanic(&mspproto.SerializedIdentity{Mspid: "OrdererOrg", IdBytes: []byte("cert")})
+	signature := &common.MetadataSignature{
+		SignatureHeader: protoutil.MarshalOrPanic(&common.SignatureHeader{Creator: creator}),
+		Signature:       []byte("signature"),
+	}
+
+	setup := func() (*consensusmocks.FakeConsenterSupport, *policymocks.Identity) {
+		identity := &policymocks.Identity{}
+		msp := &mocks.MSP{}
+		msp.DeserializeIdentityReturns(identity, nil)
+		org := &mocks.OrdererOrg{}
+		org.MSPIDReturns("OrdererOrg")
+		org.MSPReturns(msp)
+		ordererConfig := &mocks.OrdererConfig{}
+		ordererConfig.OrganizationsReturns(map[string]channelconfig.OrdererOrg{"OrdererOrg": org})
+		support := &consensusmocks.FakeConsenterSupport{}
+		support.SharedConfigReturns(ordererConfig)
+		return support, identity
+	}
+
+	t.Run("Valid signature", func(t *testing.T) {
+		support, identity := setup()
+		signer, err := etcdraft.NewBlockSignatureVerifier(support)(header, value, signature)
+		require.NoError(t, err)
+		assert.Equal(t, creator, signer)
+
+		msg, sig := identity.VerifyArgsForCall(0)
+		assert.Equal(t, append(append(append([]byte{}, value...), signature.SignatureHeader...), protoutil.BlockHeaderBytes(header)...), msg)
+		assert.Equal(t, []byte("signature"), sig)
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		support, identity := setup()
+		identity.VerifyReturns(fmt.Errorf("bad signature"))
+		_, err := etcdraft.NewBlockSignatureVerifier(support)(header, value, signature)
+		assert.EqualError(t, err, "signature verification failed: bad signature")
+	})
+
+	t.Run("Invalid identity", func(t *testing.T) {
+		support, identity := setup()
+		identity.ValidateReturns(fmt.Errorf("expired"))
+		_, err := etcdraft.NewBlockSignatureVerifier(support)(header, value, signature)
+		assert.EqualError(t, err, "signer identity is not valid: expired")
+	})
+
+	t.Run("Not an orderer organization", func(t *testing.T) {
+		support, _ := setup()
+		other := &common.MetadataSignature{
+			SignatureHeader: protoutil.MarshalOrPanic(&common.SignatureHeader{
+				Creator: protoutil.MarshalOrPanic(&mspproto.SerializedIdentity{Mspid: "PeerOrg", IdBytes: []byte("cert")}),
+			}),
+			Signature: []byte("signature"),
+		}
+		_, err := etcdraft.NewBlockSignatureVerifier(support)(header, value, other)
+		assert.EqualError(t, err, "signer is not a member of an orderer organization, MSP ID: PeerOrg")
+	})
+}
+
+func isClosed(c <-chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
@@ -65,6 +65,14 @@ var (
 		LabelNames:   []string{"channel"},
 		StatsdFormat: "%{#fqname}.%{channel}",
 	}
+	shortSignedBlocksOpts = metrics.CounterOpts{
+		Namespace:    "consensus",
+		Subsystem:    "etcdraft",
+		Name:         "short_signed_blocks",
+		Help:         "The number of blocks written with fewer signatures than the block signature threshold.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
 	dataPersistDurationOpts = metrics.HistogramOpts{
 		Namespace:    "consensus",
 		Subsystem:    "etcdraft",
@@ -99,6 +107,7 @@ type Metrics struct {
 	SnapshotBlockNumber     metrics.Gauge
 	LeaderChanges           metrics.Counter
 	ProposalFailures        metrics.Counter
+	ShortSignedBlocks       metrics.Counter
 	DataPersistDuration     metrics.Histogram
 	NormalProposalsReceived metrics.Counter
 	ConfigProposalsReceived metrics.Counter
@@ -113,6 +122,7 @@ func NewMetrics(p metrics.Provider) *Metrics {
 		SnapshotBlockNumber:     p.NewGauge(snapshotBlockNumberOpts),
 		LeaderChanges:           p.NewCounter(leaderChangesOpts),
 		ProposalFailures:        p.NewCounter(proposalFailuresOpts),
+		ShortSignedBlocks:       p.NewCounter(shortSignedBlocksOpts),
 		DataPersistDuration:     p.NewHistogram(dataPersistDurationOpts),
 		NormalProposalsReceived: p.NewCounter(normalProposalsReceivedOpts),
 		ConfigProposalsReceived: p.NewCounter(configProposalsReceivedOpts),
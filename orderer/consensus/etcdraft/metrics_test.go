@@ -38,7 +38,7 @@ var _ = Describe("Metrics", func() {
 
 			Expect(metrics).NotTo(BeNil())
 			Expect(fakeProvider.NewGaugeCallCount()).To(Equal(5))
-			Expect(fakeProvider.NewCounterCallCount()).To(Equal(4))
+			Expect(fakeProvider.NewCounterCallCount()).To(Equal(5))
 			Expect(fakeProvider.NewHistogramCallCount()).To(Equal(1))
 
 			Expect(metrics.ClusterSize).To(Equal(fakeGauge))
@@ -47,6 +47,7 @@ var _ = Describe("Metrics", func() {
 			Expect(metrics.SnapshotBlockNumber).To(Equal(fakeGauge))
 			Expect(metrics.LeaderChanges).To(Equal(fakeCounter))
 			Expect(metrics.ProposalFailures).To(Equal(fakeCounter))
+			Expect(metrics.ShortSignedBlocks).To(Equal(fakeCounter))
 			Expect(metrics.DataPersistDuration).To(Equal(fakeHistogram))
 			Expect(metrics.NormalProposalsReceived).To(Equal(fakeCounter))
 			Expect(metrics.ConfigProposalsReceived).To(Equal(fakeCounter))
@@ -63,6 +64,7 @@ func newFakeMetrics(fakeFields *fakeMetricsFields) *etcdraft.Metrics {
 		SnapshotBlockNumber:     fakeFields.fakeSnapshotBlockNumber,
 		LeaderChanges:           fakeFields.fakeLeaderChanges,
 		ProposalFailures:        fakeFields.fakeProposalFailures,
+		ShortSignedBlocks:       fakeFields.fakeShortSignedBlocks,
 		DataPersistDuration:     fakeFields.fakeDataPersistDuration,
 		NormalProposalsReceived: fakeFields.fakeNormalProposalsReceived,
 		ConfigProposalsReceived: fakeFields.fakeConfigProposalsReceived,
@@ -77,6 +79,7 @@ type fakeMetricsFields struct {
 	fakeSnapshotBlockNumber     *metricsfakes.Gauge
 	fakeLeaderChanges           *metricsfakes.Counter
 	fakeProposalFailures        *metricsfakes.Counter
+	fakeShortSignedBlocks       *metricsfakes.Counter
 	fakeDataPersistDuration     *metricsfakes.Histogram
 	fakeNormalProposalsReceived *metricsfakes.Counter
 	fakeConfigProposalsReceived *metricsfakes.Counter
@@ -91,6 +94,7 @@ func newFakeMetricsFields() *fakeMetricsFields {
 		fakeSnapshotBlockNumber:     newFakeGauge(),
 		fakeLeaderChanges:           newFakeCounter(),
 		fakeProposalFailures:        newFakeCounter(),
+		fakeShortSignedBlocks:       newFakeCounter(),
 		fakeDataPersistDuration:     newFakeHistogram(),
 		fakeNormalProposalsReceived: newFakeCounter(),
 		fakeConfigProposalsReceived: newFakeCounter(),
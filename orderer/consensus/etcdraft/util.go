@@ -258,6 +258,34 @@ func VerifyConfigMetadata(metadata *etcdraft.ConfigMetadata, verifyOpts x509.Ver
 	return nil
 }
 
+// VerifyOrdererConfigMetadata validates the Raft config metadata of an orderer config without
+// a running chain, e.g. in preparation of a consensus-type migration to etcdraft.
+// In contrast to VerifyConfigMetadata, the certificates of all consenters are also checked
+// for expiration, as all of them are going to be added to the cluster.
+func VerifyOrdererConfigMetadata(ordererConfig channelconfig.Orderer) error {
+	metadata := &etcdraft.ConfigMetadata{}
+	if err := proto.Unmarshal(ordererConfig.ConsensusMetadata(), metadata); err != nil {
+		return errors.Wrap(err, "failed to unmarshal etcdraft metadata configuration")
+	}
+
+	verifyOpts, err := createX509VerifyOptions(ordererConfig)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create x509 verify options from orderer config")
+	}
+
+	if err := VerifyConfigMetadata(metadata, verifyOpts); err != nil {
+		return errors.Wrap(err, "invalid config metadata")
+	}
+
+	for _, c := range metadata.Consenters {
+		if err := validateConsenterTLSCerts(c, verifyOpts, false); err != nil {
+			return errors.Wrapf(err, "consenter %s:%d has invalid certificates", c.Host, c.Port)
+		}
+	}
+
+	return nil
+}
+
 func parseCertificateFromBytes(cert []byte) (*x509.Certificate, error) {
 	pemBlock, _ := pem.Decode(cert)
 	if pemBlock == nil {
@@ -396,3 +396,37 @@ var _ = Describe("Metadata Validation", func() {
 		})
 	})
 })
+
+var _ = Describe("Offline Metadata Validation", func() {
+	var tlsCA tlsgen.CA
+
+	BeforeEach(func() {
+		var err error
+		tlsCA, err = tlsgen.NewCA()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("succeeds on valid metadata", func() {
+		ordererConfig := mockOrdererWithTLSRootCert(time.Hour, marshalOrPanic(createMetadata(3, tlsCA)), tlsCA)
+		Expect(etcdraft.VerifyOrdererConfigMetadata(ordererConfig)).To(Succeed())
+	})
+
+	It("fails when metadata is not well-formed", func() {
+		ordererConfig := mockOrdererWithTLSRootCert(time.Hour, []byte("test"), tlsCA)
+		Expect(etcdraft.VerifyOrdererConfigMetadata(ordererConfig)).To(MatchError(ContainSubstring("failed to unmarshal etcdraft metadata configuration")))
+	})
+
+	It("fails when metadata has no consenters", func() {
+		md := createMetadata(3, tlsCA)
+		md.Consenters = nil
+		ordererConfig := mockOrdererWithTLSRootCert(time.Hour, marshalOrPanic(md), tlsCA)
+		Expect(etcdraft.VerifyOrdererConfigMetadata(ordererConfig)).To(MatchError("invalid config metadata: empty consenter set"))
+	})
+
+	It("fails when consenter certificates are not issued by an orderer org", func() {
+		unknownCA, err := tlsgen.NewCA()
+		Expect(err).NotTo(HaveOccurred())
+		ordererConfig := mockOrdererWithTLSRootCert(time.Hour, marshalOrPanic(createMetadata(3, unknownCA)), tlsCA)
+		Expect(etcdraft.VerifyOrdererConfigMetadata(ordererConfig)).To(MatchError(ContainSubstring("invalid config metadata")))
+	})
+})
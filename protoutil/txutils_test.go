@@ -295,6 +295,40 @@ func TestCreateSignedTxStatus(t *testing.T) {
 	}
 }
 
+func TestCreateTx(t *testing.T) {
+	signerBytes := []byte("signer")
+	header := protoutil.MarshalOrPanic(&cb.Header{
+		ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+			Extension: protoutil.MarshalOrPanic(&pb.ChaincodeHeaderExtension{}),
+		}),
+		SignatureHeader: protoutil.MarshalOrPanic(&cb.SignatureHeader{
+			Creator: signerBytes,
+		}),
+	})
+	proposal := &pb.Proposal{Header: header}
+	response := &pb.ProposalResponse{
+		Payload:     []byte("payload"),
+		Endorsement: &pb.Endorsement{Endorser: []byte("endorser")},
+		Response:    &pb.Response{Status: int32(200)},
+	}
+
+	env, err := protoutil.CreateTx(proposal, response)
+	assert.NoError(t, err)
+	assert.Nil(t, env.Signature)
+
+	signingID := &fakes.SignerSerializer{}
+	signingID.SerializeReturns(signerBytes, nil)
+	signingID.SignReturns([]byte("signature"), nil)
+	signedEnv, err := protoutil.CreateSignedTx(proposal, signingID, response)
+	assert.NoError(t, err)
+	assert.Equal(t, signedEnv.Payload, env.Payload)
+	assert.Equal(t, []byte("signature"), signedEnv.Signature)
+	assert.Equal(t, env.Payload, signingID.SignArgsForCall(0))
+
+	_, err = protoutil.CreateTx(proposal)
+	assert.EqualError(t, err, "at least one proposal response is required")
+}
+
 func TestCreateSignedEnvelope(t *testing.T) {
 	var env *cb.Envelope
 	channelID := "mychannelID"